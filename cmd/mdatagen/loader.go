@@ -100,6 +100,8 @@ type attribute struct {
 type metadata struct {
 	// Name of the component.
 	Name string `validate:"notblank"`
+	// ResourceAttributes that can be set on the resource of the emitted metrics.
+	ResourceAttributes map[attributeName]attribute `mapstructure:"resource_attributes" validate:"dive"`
 	// Attributes emitted by one or more metrics.
 	Attributes map[attributeName]attribute `validate:"dive"`
 	// Metrics that can be emitted by the component.
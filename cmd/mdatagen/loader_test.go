@@ -34,6 +34,9 @@ func Test_loadMetadata(t *testing.T) {
 			yml:  "all_options.yaml",
 			want: metadata{
 				Name: "metricreceiver",
+				ResourceAttributes: map[attributeName]attribute{
+					"host.name": {
+						Description: "The name of the host."}},
 				Attributes: map[attributeName]attribute{
 					"enumAttribute": {
 						Description: "Attribute with a known set of values.",
@@ -207,95 +207,6 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
-func TestCreateSigningRoundTripperWithCredentials(t *testing.T) {
-	defaultRoundTripper := (http.RoundTripper)(http.DefaultTransport.(*http.Transport).Clone())
-
-	// Some form of AWS credentials must be set up for tests to succeed
-	awsCreds := fetchMockCredentials()
-
-	tests := []struct {
-		name         string
-		creds        *credentials.Credentials
-		roundTripper http.RoundTripper
-		authConfig   AuthConfig
-		authApplied  bool
-		returnError  bool
-	}{
-		{
-			"success_case",
-			awsCreds,
-			defaultRoundTripper,
-			AuthConfig{Region: "region", Service: "service"},
-			true,
-			false,
-		},
-		{
-			"success_case_no_auth_applied",
-			awsCreds,
-			defaultRoundTripper,
-			AuthConfig{Region: "", Service: ""},
-			false,
-			false,
-		},
-		{
-			"no_credentials_provided_error",
-			nil,
-			defaultRoundTripper,
-			AuthConfig{Region: "region", Service: "service"},
-			true,
-			true,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rtp, err := newSigningRoundTripperWithCredentials(tt.authConfig, tt.creds, tt.roundTripper, sdkInformation)
-			if tt.returnError {
-				assert.Error(t, err)
-				return
-			}
-			assert.NoError(t, err)
-			if tt.authApplied {
-				sRtp := rtp.(*signingRoundTripper)
-				assert.Equal(t, sRtp.transport, tt.roundTripper)
-				assert.Equal(t, tt.authConfig.Service, sRtp.service)
-			}
-		})
-	}
-}
-
-func TestCloneRequest(t *testing.T) {
-	req1, err := http.NewRequest("GET", "https://example.com", nil)
-	assert.NoError(t, err)
-
-	req2, err := http.NewRequest("GET", "https://example.com", nil)
-	assert.NoError(t, err)
-	req2.Header.Add("Header1", "val1")
-
-	tests := []struct {
-		name    string
-		request *http.Request
-		headers http.Header
-	}{
-		{
-			"no_headers",
-			req1,
-			http.Header{},
-		},
-		{
-			"headers",
-			req2,
-			http.Header{"Header1": []string{"val1"}},
-		},
-	}
-	// run tests
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r2 := cloneRequest(tt.request)
-			assert.EqualValues(t, tt.request.Header, r2.Header)
-		})
-	}
-}
-
 func TestParseEndpointRegion(t *testing.T) {
 	tests := []struct {
 		name       string
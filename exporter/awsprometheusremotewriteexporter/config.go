@@ -16,6 +16,7 @@ package awsprometheusremotewriteexporter // import "github.com/open-telemetry/op
 
 import (
 	prw "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/awsutil"
 )
 
 // Config defines configuration for Remote Write exporter.
@@ -38,3 +39,11 @@ type AuthConfig struct {
 	// Amazon Resource Name (ARN) of a role to assume. Optional.
 	RoleArn string `mapstructure:"role_arn"`
 }
+
+func (a AuthConfig) sigV4Settings() awsutil.SigV4Settings {
+	return awsutil.SigV4Settings{
+		Region:  a.Region,
+		Service: a.Service,
+		RoleARN: a.RoleArn,
+	}
+}
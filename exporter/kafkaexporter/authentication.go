@@ -18,6 +18,8 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -45,10 +47,27 @@ type SASLConfig struct {
 	Username string `mapstructure:"username"`
 	// Password to be used on authentication
 	Password string `mapstructure:"password"`
-	// SASL Mechanism to be used, possible values are: (PLAIN, AWS_MSK_IAM, SCRAM-SHA-256 or SCRAM-SHA-512).
+	// UsernameFile is the path of a file containing the username. Can not be specified with Username.
+	UsernameFile string `mapstructure:"username_file"`
+	// PasswordFile is the path of a file containing the password, e.g. written by an agent rotating
+	// it. Can not be specified with Password.
+	PasswordFile string `mapstructure:"password_file"`
+	// SASL Mechanism to be used, possible values are: (PLAIN, AWS_MSK_IAM, OAUTHBEARER, SCRAM-SHA-256 or SCRAM-SHA-512).
 	Mechanism string `mapstructure:"mechanism"`
 
 	AWSMSK AWSMSKConfig `mapstructure:"aws_msk"`
+
+	OAuth OAuthConfig `mapstructure:"oauth"`
+}
+
+// OAuthConfig defines the additional SASL authentication
+// measures needed to use OAUTHBEARER mechanism. Username and
+// Password are used as the OAuth2 client ID and client secret.
+type OAuthConfig struct {
+	// TokenURL is the endpoint used to retrieve access tokens using the client credentials flow
+	TokenURL string `mapstructure:"token_url"`
+	// Scopes is the optional list of scopes requested for the access token
+	Scopes []string `mapstructure:"scopes"`
 }
 
 // AWSMSKConfig defines the additional SASL authentication
@@ -100,6 +119,20 @@ func configurePlaintext(config PlainTextConfig, saramaConfig *sarama.Config) {
 }
 
 func configureSASL(config SASLConfig, saramaConfig *sarama.Config) error {
+	if config.Username != "" && config.UsernameFile != "" {
+		return fmt.Errorf("username and username_file can not both be provided")
+	}
+	if config.Password != "" && config.PasswordFile != "" {
+		return fmt.Errorf("password and password_file can not both be provided")
+	}
+
+	var err error
+	if config.Username, err = readCredential(config.Username, config.UsernameFile); err != nil {
+		return err
+	}
+	if config.Password, err = readCredential(config.Password, config.PasswordFile); err != nil {
+		return err
+	}
 
 	if config.Username == "" {
 		return fmt.Errorf("username have to be provided")
@@ -127,13 +160,31 @@ func configureSASL(config SASLConfig, saramaConfig *sarama.Config) error {
 			return awsmsk.NewIAMSASLClient(config.AWSMSK.BrokerAddr, config.AWSMSK.Region, saramaConfig.ClientID)
 		}
 		saramaConfig.Net.SASL.Mechanism = awsmsk.Mechanism
+	case "OAUTHBEARER":
+		if config.OAuth.TokenURL == "" {
+			return fmt.Errorf("token_url have to be provided")
+		}
+		saramaConfig.Net.SASL.TokenProvider = newOAuthTokenProvider(config.Username, config.Password, config.OAuth)
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
 	default:
-		return fmt.Errorf(`invalid SASL Mechanism %q: can be either "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256" or "SCRAM-SHA-512"`, config.Mechanism)
+		return fmt.Errorf(`invalid SASL Mechanism %q: can be either "PLAIN", "AWS_MSK_IAM", "OAUTHBEARER", "SCRAM-SHA-256" or "SCRAM-SHA-512"`, config.Mechanism)
 	}
 
 	return nil
 }
 
+// readCredential returns value, or the content of file without surrounding whitespace if set.
+func readCredential(value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
 func configureTLS(config configtls.TLSClientSetting, saramaConfig *sarama.Config) error {
 	tlsConfig, err := config.LoadTLSConfig()
 	if err != nil {
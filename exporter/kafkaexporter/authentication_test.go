@@ -15,6 +15,8 @@
 package kafkaexporter
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Shopify/sarama"
@@ -23,6 +25,34 @@ import (
 	"go.opentelemetry.io/collector/config/configtls"
 )
 
+func TestAuthenticationCredentialsFiles(t *testing.T) {
+	dir := t.TempDir()
+	usernameFile := filepath.Join(dir, "username")
+	require.NoError(t, os.WriteFile(usernameFile, []byte("jdoe\n"), 0600))
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass\n"), 0600))
+
+	config := &sarama.Config{}
+	require.NoError(t, ConfigureAuthentication(Authentication{SASL: &SASLConfig{UsernameFile: usernameFile, PasswordFile: passwordFile, Mechanism: "PLAIN"}}, config))
+	assert.Equal(t, "jdoe", config.Net.SASL.User)
+	assert.Equal(t, "pass", config.Net.SASL.Password)
+
+	// The files are read again every time the authentication is configured.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("rotated"), 0600))
+	config = &sarama.Config{}
+	require.NoError(t, ConfigureAuthentication(Authentication{SASL: &SASLConfig{Username: "jdoe", PasswordFile: passwordFile, Mechanism: "SCRAM-SHA-512"}}, config))
+	assert.Equal(t, "rotated", config.Net.SASL.Password)
+
+	err := ConfigureAuthentication(Authentication{SASL: &SASLConfig{Username: "jdoe", PasswordFile: filepath.Join(dir, "missing"), Mechanism: "PLAIN"}}, &sarama.Config{})
+	assert.Contains(t, err.Error(), "failed to read credentials file")
+
+	err = ConfigureAuthentication(Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", PasswordFile: passwordFile, Mechanism: "PLAIN"}}, &sarama.Config{})
+	assert.EqualError(t, err, "password and password_file can not both be provided")
+
+	err = ConfigureAuthentication(Authentication{SASL: &SASLConfig{Username: "jdoe", UsernameFile: usernameFile, Password: "pass", Mechanism: "PLAIN"}}, &sarama.Config{})
+	assert.EqualError(t, err, "username and username_file can not both be provided")
+}
+
 func TestAuthentication(t *testing.T) {
 	saramaPlaintext := &sarama.Config{}
 	saramaPlaintext.Net.SASL.Enable = true
@@ -48,6 +78,12 @@ func TestAuthentication(t *testing.T) {
 
 	saramaSASLPLAINConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
 
+	saramaSASLOAuthConfig := &sarama.Config{}
+	saramaSASLOAuthConfig.Net.SASL.Enable = true
+	saramaSASLOAuthConfig.Net.SASL.User = "jdoe"
+	saramaSASLOAuthConfig.Net.SASL.Password = "pass"
+	saramaSASLOAuthConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+
 	saramaTLSCfg := &sarama.Config{}
 	saramaTLSCfg.Net.TLS.Enable = true
 	tlsClient := configtls.TLSClientSetting{}
@@ -108,6 +144,16 @@ func TestAuthentication(t *testing.T) {
 			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "PLAIN"}},
 			saramaConfig: saramaSASLPLAINConfig,
 		},
+		{
+			auth: Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "OAUTHBEARER",
+				OAuth: OAuthConfig{TokenURL: "https://localhost/token"}}},
+			saramaConfig: saramaSASLOAuthConfig,
+		},
+		{
+			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "OAUTHBEARER"}},
+			saramaConfig: saramaSASLOAuthConfig,
+			err:          "token_url have to be provided",
+		},
 		{
 			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "SCRAM-SHA-222"}},
 			saramaConfig: saramaSASLSCRAM512Config,
@@ -134,6 +180,10 @@ func TestAuthentication(t *testing.T) {
 			} else {
 				// equalizes SCRAMClientGeneratorFunc to do assertion with the same reference.
 				config.Net.SASL.SCRAMClientGeneratorFunc = test.saramaConfig.Net.SASL.SCRAMClientGeneratorFunc
+				if config.Net.SASL.TokenProvider != nil {
+					assert.IsType(t, &oauthTokenProvider{}, config.Net.SASL.TokenProvider)
+					config.Net.SASL.TokenProvider = nil
+				}
 				assert.Equal(t, test.saramaConfig, config)
 			}
 		})
@@ -16,10 +16,12 @@ package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
@@ -34,6 +36,9 @@ type Config struct {
 	Brokers []string `mapstructure:"brokers"`
 	// Kafka protocol version
 	ProtocolVersion string `mapstructure:"protocol_version"`
+	// ProtocolVersionNegotiation lowers the protocol version to the version of the oldest broker,
+	// queried at startup, so that older brokers can be reached without knowing their version.
+	ProtocolVersionNegotiation bool `mapstructure:"protocol_version_negotiation"`
 	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
 	Topic string `mapstructure:"topic"`
 
@@ -49,6 +54,20 @@ type Config struct {
 
 	// Authentication defines used authentication mechanism.
 	Authentication Authentication `mapstructure:"auth"`
+
+	// SchemaRegistry defines the schema registry used to frame otlp_proto messages.
+	SchemaRegistry SchemaRegistry `mapstructure:"schema_registry"`
+
+	// TopicRouting defines how the topic is selected from the request context.
+	TopicRouting TopicRouting `mapstructure:"topic_routing"`
+
+	// SignalHeader adds the otlp_signal header to the messages, so that the traces, metrics and
+	// logs exported to a single topic can be consumed by a receiver with multi_signal enabled.
+	SignalHeader bool `mapstructure:"signal_header"`
+
+	// DedupHeader adds the otlp_dedup_key header to the messages, holding a key derived from the
+	// identity of the exported telemetry so that consumers can discard the duplicates of retries.
+	DedupHeader bool `mapstructure:"dedup_header"`
 }
 
 // Metadata defines configuration for retrieving metadata from the broker.
@@ -77,6 +96,72 @@ type Producer struct {
 	//   1 -> WaitForLocal. waits for only the local commit to succeed before responding ( default )
 	//   -1 -> WaitForAll. waits for all in-sync replicas to commit before responding.
 	RequiredAcks sarama.RequiredAcks `mapstructure:"required_acks"`
+
+	// TimestampSource sets the timestamp of the records.
+	// The options are:
+	//   produce -> the time the records are produced ( default )
+	//   telemetry -> the latest span end time, metric data point timestamp or log timestamp of the exported batch.
+	// Record timestamps require a protocol_version of at least 0.10.0.
+	TimestampSource string `mapstructure:"timestamp_source"`
+
+	// FlushTimeout is how long the exporter waits on shutdown for the messages in flight to be
+	// acknowledged by the brokers before closing the producer. The messages which are not flushed
+	// within the timeout are reported. If 0, the exporter waits until the shutdown is cancelled.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"`
+
+	// BatchSplit splits the batches into several messages before they are marshaled.
+	BatchSplit BatchSplit `mapstructure:"batch_split"`
+}
+
+// BatchSplit defines configuration for splitting the exported batches into several messages, so that
+// large batches are not rejected as a whole for exceeding MaxMessageBytes.
+type BatchSplit struct {
+	// MaxItems is the maximum number of spans, metric data points or log records per message. 0 means no limit.
+	MaxItems int `mapstructure:"max_items"`
+	// MaxBytes is the maximum size of the batch of a message, estimated with the OTLP protobuf encoding.
+	// It has to leave room for the overhead of the encoding and the record. 0 means no limit.
+	MaxBytes int `mapstructure:"max_bytes"`
+}
+
+// SchemaRegistry defines configuration for framing otlp_proto messages with the
+// Confluent schema registry wire format.
+type SchemaRegistry struct {
+	confighttp.HTTPClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// Enabled prepends the magic byte and the schema ID to every message.
+	Enabled bool `mapstructure:"enabled"`
+	// Subject the OTLP schema is registered under (default <topic>-value, for every topic the messages are produced to).
+	Subject string `mapstructure:"subject"`
+	// SchemaID of an already registered schema. When set, the schema is not registered.
+	SchemaID int `mapstructure:"schema_id"`
+	// Username to authenticate against the schema registry with basic auth.
+	Username string `mapstructure:"username"`
+	// Password to authenticate against the schema registry with basic auth.
+	Password string `mapstructure:"password"`
+}
+
+// TopicRouting defines configuration for routing requests to topics based on a
+// value of the request context, e.g. the tenant of a multi-tenant pipeline.
+// Requests that do not match any route are exported to Topic.
+type TopicRouting struct {
+	// FromContext is the name of the gRPC metadata key holding the routing value, requests received over HTTP carry no metadata.
+	FromContext string `mapstructure:"from_context"`
+	// Traces are the routes evaluated for traces, in order.
+	Traces []TopicRoute `mapstructure:"traces"`
+	// Metrics are the routes evaluated for metrics, in order.
+	Metrics []TopicRoute `mapstructure:"metrics"`
+	// Logs are the routes evaluated for logs, in order.
+	Logs []TopicRoute `mapstructure:"logs"`
+}
+
+// TopicRoute routes requests whose context value matches Value or Regex to Topic.
+type TopicRoute struct {
+	// Value the context value has to be equal to.
+	Value string `mapstructure:"value"`
+	// Regex the context value has to match.
+	Regex string `mapstructure:"regex"`
+	// Topic to export matching requests to.
+	Topic string `mapstructure:"topic"`
 }
 
 // MetadataRetry defines retry configuration for Metadata.
@@ -93,8 +178,91 @@ var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.ProtocolVersion != "" {
+		if _, err := parseProtocolVersion(cfg.ProtocolVersion); err != nil {
+			return fmt.Errorf("protocol_version has to be a Kafka version, e.g. 2.0.0 or 0.11.0.0. configured value %v", cfg.ProtocolVersion)
+		}
+	}
 	if cfg.Producer.RequiredAcks < -1 || cfg.Producer.RequiredAcks > 1 {
 		return fmt.Errorf("producer.required_acks has to be between -1 and 1. configured value %v", cfg.Producer.RequiredAcks)
 	}
+	switch cfg.Producer.TimestampSource {
+	case "", timestampSourceProduce:
+	case timestampSourceTelemetry:
+		if cfg.ProtocolVersion != "" {
+			version, err := parseProtocolVersion(cfg.ProtocolVersion)
+			if err == nil && !version.IsAtLeast(sarama.V0_10_0_0) {
+				return fmt.Errorf("producer.timestamp_source %v requires protocol_version 0.10.0 or higher. configured value %v", timestampSourceTelemetry, cfg.ProtocolVersion)
+			}
+		}
+	default:
+		return fmt.Errorf("producer.timestamp_source has to be either %v or %v. configured value %v", timestampSourceProduce, timestampSourceTelemetry, cfg.Producer.TimestampSource)
+	}
+	if cfg.Producer.FlushTimeout < 0 {
+		return fmt.Errorf("producer.flush_timeout has to be positive. configured value %v", cfg.Producer.FlushTimeout)
+	}
+	if cfg.Producer.BatchSplit.MaxItems < 0 {
+		return fmt.Errorf("producer.batch_split.max_items has to be positive. configured value %v", cfg.Producer.BatchSplit.MaxItems)
+	}
+	if cfg.Producer.BatchSplit.MaxBytes < 0 || cfg.Producer.BatchSplit.MaxBytes > cfg.Producer.MaxMessageBytes {
+		return fmt.Errorf("producer.batch_split.max_bytes has to be between 0 and producer.max_message_bytes %v. configured value %v", cfg.Producer.MaxMessageBytes, cfg.Producer.BatchSplit.MaxBytes)
+	}
+	if cfg.Encoding == zstdEncoding && cfg.ProtocolVersion != "" {
+		// The content-encoding header requires record headers.
+		version, err := parseProtocolVersion(cfg.ProtocolVersion)
+		if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+			return fmt.Errorf("encoding %v requires protocol_version 0.11.0 or higher. configured value %v", zstdEncoding, cfg.ProtocolVersion)
+		}
+	}
+	if cfg.SignalHeader && cfg.ProtocolVersion != "" {
+		version, err := parseProtocolVersion(cfg.ProtocolVersion)
+		if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+			return fmt.Errorf("signal_header requires protocol_version 0.11.0 or higher. configured value %v", cfg.ProtocolVersion)
+		}
+	}
+	if cfg.DedupHeader && cfg.ProtocolVersion != "" {
+		version, err := parseProtocolVersion(cfg.ProtocolVersion)
+		if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+			return fmt.Errorf("dedup_header requires protocol_version 0.11.0 or higher. configured value %v", cfg.ProtocolVersion)
+		}
+	}
+	if cfg.SchemaRegistry.Enabled {
+		if cfg.Encoding != defaultEncoding {
+			return fmt.Errorf("schema_registry is only supported with %s encoding. configured encoding %v", defaultEncoding, cfg.Encoding)
+		}
+		if cfg.SchemaRegistry.SchemaID == 0 && cfg.SchemaRegistry.Endpoint == "" {
+			return fmt.Errorf("schema_registry.endpoint has to be set when schema_registry.schema_id is not configured")
+		}
+	}
+	return validateTopicRouting(cfg.TopicRouting)
+}
+
+func validateTopicRouting(cfg TopicRouting) error {
+	signals := []struct {
+		name   string
+		routes []TopicRoute
+	}{
+		{name: "traces", routes: cfg.Traces},
+		{name: "metrics", routes: cfg.Metrics},
+		{name: "logs", routes: cfg.Logs},
+	}
+	for _, signal := range signals {
+		if len(signal.routes) > 0 && cfg.FromContext == "" {
+			return fmt.Errorf("topic_routing.from_context has to be set when topic_routing.%s is configured", signal.name)
+		}
+		for i, route := range signal.routes {
+			if route.Topic == "" {
+				return fmt.Errorf("topic_routing.%s[%d].topic has to be set", signal.name, i)
+			}
+			if (route.Value == "") == (route.Regex == "") {
+				return fmt.Errorf("topic_routing.%s[%d] has to set exactly one of value or regex", signal.name, i)
+			}
+			if route.Regex != "" {
+				if _, err := regexp.Compile(route.Regex); err != nil {
+					return fmt.Errorf("topic_routing.%s[%d].regex is invalid: %w", signal.name, i, err)
+				}
+			}
+		}
+	}
 	return nil
 }
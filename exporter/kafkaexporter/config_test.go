@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/service/servicetest"
 )
@@ -74,6 +75,239 @@ func TestLoadConfig(t *testing.T) {
 		Producer: Producer{
 			MaxMessageBytes: 10000000,
 			RequiredAcks:    sarama.WaitForAll,
+			TimestampSource: timestampSourceTelemetry,
+			FlushTimeout:    10 * time.Second,
+			BatchSplit: BatchSplit{
+				MaxItems: 1000,
+				MaxBytes: 9000000,
+			},
+		},
+		SchemaRegistry: SchemaRegistry{
+			HTTPClientSettings: confighttp.HTTPClientSettings{
+				Endpoint: "http://localhost:8081",
+				Timeout:  defaultSchemaRegistryTimeout,
+			},
+			Enabled:  true,
+			Subject:  "spans-otlp",
+			Username: "jdoe",
+			Password: "pass",
 		},
 	}, c)
 }
+
+func TestValidateSchemaRegistry(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		registry SchemaRegistry
+		err      string
+	}{
+		{
+			name:     "disabled",
+			encoding: "jaeger_proto",
+		},
+		{
+			name:     "endpoint",
+			encoding: defaultEncoding,
+			registry: SchemaRegistry{Enabled: true, HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://localhost:8081"}},
+		},
+		{
+			name:     "schema id",
+			encoding: defaultEncoding,
+			registry: SchemaRegistry{Enabled: true, SchemaID: 1},
+		},
+		{
+			name:     "unsupported encoding",
+			encoding: "otlp_json",
+			registry: SchemaRegistry{Enabled: true, SchemaID: 1},
+			err:      "schema_registry is only supported with otlp_proto encoding. configured encoding otlp_json",
+		},
+		{
+			name:     "missing endpoint",
+			encoding: defaultEncoding,
+			registry: SchemaRegistry{Enabled: true},
+			err:      "schema_registry.endpoint has to be set when schema_registry.schema_id is not configured",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Encoding = test.encoding
+			cfg.SchemaRegistry = test.registry
+			err := cfg.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateTimestampSource(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		protocolVersion string
+		err             string
+	}{
+		{
+			name:   "produce",
+			source: timestampSourceProduce,
+		},
+		{
+			name:   "telemetry",
+			source: timestampSourceTelemetry,
+		},
+		{
+			name:            "telemetry with protocol version",
+			source:          timestampSourceTelemetry,
+			protocolVersion: "2.0.0",
+		},
+		{
+			name:            "unsupported protocol version",
+			source:          timestampSourceTelemetry,
+			protocolVersion: "0.9.0.0",
+			err:             "producer.timestamp_source telemetry requires protocol_version 0.10.0 or higher. configured value 0.9.0.0",
+		},
+		{
+			name:   "invalid",
+			source: "broker",
+			err:    "producer.timestamp_source has to be either produce or telemetry. configured value broker",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Producer.TimestampSource = test.source
+			cfg.ProtocolVersion = test.protocolVersion
+			err := cfg.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateProtocolVersion(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "0.11.0"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "latest"
+	assert.EqualError(t, cfg.Validate(), "protocol_version has to be a Kafka version, e.g. 2.0.0 or 0.11.0.0. configured value latest")
+}
+
+func TestValidateFlushTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Producer.FlushTimeout = 0
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Producer.FlushTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "producer.flush_timeout has to be positive. configured value -1s")
+}
+
+func TestValidateBatchSplit(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Producer.BatchSplit = BatchSplit{MaxItems: 100, MaxBytes: cfg.Producer.MaxMessageBytes}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Producer.BatchSplit = BatchSplit{MaxItems: -1}
+	assert.EqualError(t, cfg.Validate(), "producer.batch_split.max_items has to be positive. configured value -1")
+
+	cfg.Producer.BatchSplit = BatchSplit{MaxBytes: -1}
+	assert.EqualError(t, cfg.Validate(), "producer.batch_split.max_bytes has to be between 0 and producer.max_message_bytes 1000000. configured value -1")
+
+	cfg.Producer.BatchSplit = BatchSplit{MaxBytes: 2000000}
+	assert.EqualError(t, cfg.Validate(), "producer.batch_split.max_bytes has to be between 0 and producer.max_message_bytes 1000000. configured value 2000000")
+}
+
+func TestValidateZstdEncoding(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = zstdEncoding
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.11.0"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.10.2.0"
+	assert.EqualError(t, cfg.Validate(), "encoding otlp_proto_zstd requires protocol_version 0.11.0 or higher. configured value 0.10.2.0")
+}
+
+func TestValidateSignalHeader(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SignalHeader = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.11.0"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.10.2.0"
+	assert.EqualError(t, cfg.Validate(), "signal_header requires protocol_version 0.11.0 or higher. configured value 0.10.2.0")
+}
+
+func TestValidateDedupHeader(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DedupHeader = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.11.0"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ProtocolVersion = "0.10.2.0"
+	assert.EqualError(t, cfg.Validate(), "dedup_header requires protocol_version 0.11.0 or higher. configured value 0.10.2.0")
+}
+
+func TestValidateTopicRouting(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing TopicRouting
+		err     string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "valid",
+			routing: TopicRouting{
+				FromContext: "x-tenant",
+				Traces:      []TopicRoute{{Value: "acme", Topic: "acme_spans"}},
+				Logs:        []TopicRoute{{Regex: "^team-.*", Topic: "team_logs"}},
+			},
+		},
+		{
+			name:    "missing from_context",
+			routing: TopicRouting{Metrics: []TopicRoute{{Value: "acme", Topic: "acme_metrics"}}},
+			err:     "topic_routing.from_context has to be set when topic_routing.metrics is configured",
+		},
+		{
+			name:    "missing topic",
+			routing: TopicRouting{FromContext: "x-tenant", Traces: []TopicRoute{{Value: "acme"}}},
+			err:     "topic_routing.traces[0].topic has to be set",
+		},
+		{
+			name:    "value and regex",
+			routing: TopicRouting{FromContext: "x-tenant", Logs: []TopicRoute{{Value: "acme", Regex: "acme", Topic: "acme_logs"}}},
+			err:     "topic_routing.logs[0] has to set exactly one of value or regex",
+		},
+		{
+			name:    "invalid regex",
+			routing: TopicRouting{FromContext: "x-tenant", Traces: []TopicRoute{{Regex: "(", Topic: "spans"}}},
+			err:     "topic_routing.traces[0].regex is invalid: error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.TopicRouting = test.routing
+			err := cfg.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// dedupHeader is the header holding a key derived from the identity of the exported telemetry,
+// so that consumers can discard the duplicates produced when a batch is retried.
+const dedupHeader = "otlp_dedup_key"
+
+// setDedupHeader sets the dedup header of every message. The keys are derived from the identity
+// of the batch and the position of the message in the batch, so that the messages of a batch
+// split by the marshaler get different keys while a retry of the batch gets the same keys.
+func setDedupHeader(messages []*sarama.ProducerMessage, batch []byte) {
+	var index [4]byte
+	for i, message := range messages {
+		h := sha256.New()
+		h.Write(batch)
+		binary.BigEndian.PutUint32(index[:], uint32(i))
+		h.Write(index[:])
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(dedupHeader),
+			Value: []byte(hex.EncodeToString(h.Sum(nil))),
+		})
+	}
+}
+
+// tracesIdentity returns the hash of the trace and span IDs of the spans.
+func tracesIdentity(td pdata.Traces) []byte {
+	h := sha256.New()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				traceID := spans.At(k).TraceID().Bytes()
+				spanID := spans.At(k).SpanID().Bytes()
+				h.Write(traceID[:])
+				h.Write(spanID[:])
+			}
+		}
+	}
+	return h.Sum(nil)
+}
+
+// metricsIdentity returns the hash of the resource, the name, the attributes and the timestamp
+// of the metric data points.
+func metricsIdentity(md pdata.Metrics) []byte {
+	h := sha256.New()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		writeAttributes(h, rms.At(i).Resource().Attributes())
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				writeString(h, metrics.At(k).Name())
+				writeMetricPoints(h, metrics.At(k))
+			}
+		}
+	}
+	return h.Sum(nil)
+}
+
+func writeMetricPoints(h hash.Hash, m pdata.Metric) {
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			writePoint(h, dps.At(i).Attributes(), dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			writePoint(h, dps.At(i).Attributes(), dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			writePoint(h, dps.At(i).Attributes(), dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			writePoint(h, dps.At(i).Attributes(), dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			writePoint(h, dps.At(i).Attributes(), dps.At(i).Timestamp())
+		}
+	}
+}
+
+// logsIdentity returns the hash of the resource, the timestamp, the trace and span IDs and the
+// body of the log records, which have no unique ID.
+func logsIdentity(ld pdata.Logs) []byte {
+	h := sha256.New()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		writeAttributes(h, rls.At(i).Resource().Attributes())
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				traceID := lr.TraceID().Bytes()
+				spanID := lr.SpanID().Bytes()
+				writePoint(h, lr.Attributes(), lr.Timestamp())
+				h.Write(traceID[:])
+				h.Write(spanID[:])
+				writeString(h, lr.Body().AsString())
+			}
+		}
+	}
+	return h.Sum(nil)
+}
+
+func writePoint(h hash.Hash, attrs pdata.AttributeMap, ts pdata.Timestamp) {
+	writeAttributes(h, attrs)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(ts))
+	h.Write(b[:])
+}
+
+// writeAttributes writes the attributes sorted by key, so that the identity doesn't depend on
+// their insertion order.
+func writeAttributes(h hash.Hash, attrs pdata.AttributeMap) {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pdata.AttributeValue) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		writeString(h, k)
+		writeString(h, v.AsString())
+	}
+	// Separates the attributes from the following fields.
+	h.Write([]byte{0})
+}
+
+// writeString writes the length of s before s, so that consecutive strings can't be confused.
+func writeString(h hash.Hash, s string) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(len(s)))
+	h.Write(b[:])
+	h.Write([]byte(s))
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func dedupKeys(messages []*sarama.ProducerMessage) []string {
+	var keys []string
+	for _, message := range messages {
+		for _, header := range message.Headers {
+			if string(header.Key) == dedupHeader {
+				keys = append(keys, string(header.Value))
+			}
+		}
+	}
+	return keys
+}
+
+func dedupTraces(spanIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for _, id := range spanIDs {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{id}))
+	}
+	return td
+}
+
+func TestSetDedupHeader(t *testing.T) {
+	keys := func(batch []byte, n int) []string {
+		messages := make([]*sarama.ProducerMessage, n)
+		for i := range messages {
+			messages[i] = &sarama.ProducerMessage{}
+		}
+		setDedupHeader(messages, batch)
+		return dedupKeys(messages)
+	}
+
+	first := keys(tracesIdentity(dedupTraces(1, 2)), 2)
+	require.Len(t, first, 2)
+	assert.NotEqual(t, first[0], first[1], "the messages of a batch must have different keys")
+	assert.Equal(t, first, keys(tracesIdentity(dedupTraces(1, 2)), 2), "a retry must have the same keys")
+	assert.NotEqual(t, first, keys(tracesIdentity(dedupTraces(1, 3)), 2))
+}
+
+func TestMetricsIdentity(t *testing.T) {
+	metrics := func(ts pdata.Timestamp, attrs ...string) pdata.Metrics {
+		md := pdata.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().InsertString("service.name", "app")
+		m := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("requests")
+		m.SetDataType(pdata.MetricDataTypeSum)
+		dp := m.Sum().DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		for i := 0; i < len(attrs); i += 2 {
+			dp.Attributes().InsertString(attrs[i], attrs[i+1])
+		}
+		return md
+	}
+
+	assert.Equal(t, metricsIdentity(metrics(1, "a", "1", "b", "2")), metricsIdentity(metrics(1, "b", "2", "a", "1")), "the attribute order must not matter")
+	assert.NotEqual(t, metricsIdentity(metrics(1, "a", "1")), metricsIdentity(metrics(2, "a", "1")))
+	assert.NotEqual(t, metricsIdentity(metrics(1, "a", "1")), metricsIdentity(metrics(1, "a", "2")))
+	assert.NotEqual(t, metricsIdentity(metrics(1, "a", "1b")), metricsIdentity(metrics(1, "a1", "b")))
+}
+
+func TestLogsIdentity(t *testing.T) {
+	logs := func(ts pdata.Timestamp, body string) pdata.Logs {
+		ld := pdata.NewLogs()
+		lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+		lr.SetTimestamp(ts)
+		lr.Body().SetStringVal(body)
+		return ld
+	}
+
+	assert.Equal(t, logsIdentity(logs(1, "started")), logsIdentity(logs(1, "started")))
+	assert.NotEqual(t, logsIdentity(logs(1, "started")), logsIdentity(logs(2, "started")))
+	assert.NotEqual(t, logsIdentity(logs(1, "started")), logsIdentity(logs(1, "stopped")))
+}
+
+// dedupChecker fails the messages sent to a mock producer without a dedup header, or with a
+// header if enabled is false.
+func dedupChecker(enabled bool) mocks.MessageChecker {
+	return func(message *sarama.ProducerMessage) error {
+		keys := dedupKeys([]*sarama.ProducerMessage{message})
+		if enabled && len(keys) != 1 {
+			return fmt.Errorf("expected a dedup header, got %v", message.Headers)
+		}
+		if !enabled && len(keys) != 0 {
+			return fmt.Errorf("unexpected dedup header %v", message.Headers)
+		}
+		return nil
+	}
+}
+
+func TestPushers_dedupHeader(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		tracesProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		tracesProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(dedupChecker(enabled))
+		traces := kafkaTracesProducer{
+			producer:    tracesProducer,
+			marshaler:   newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+			dedupHeader: enabled,
+		}
+		require.NoError(t, traces.tracesPusher(context.Background(), dedupTraces(1)))
+		require.NoError(t, traces.Close(context.Background()))
+
+		metricsProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		metricsProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(dedupChecker(enabled))
+		metrics := kafkaMetricsProducer{
+			producer:    metricsProducer,
+			marshaler:   newPdataMetricsMarshaler(otlp.NewProtobufMetricsMarshaler(), defaultEncoding),
+			dedupHeader: enabled,
+		}
+		require.NoError(t, metrics.metricsDataPusher(context.Background(), pdata.NewMetrics()))
+		require.NoError(t, metrics.Close(context.Background()))
+
+		logsProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		logsProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(dedupChecker(enabled))
+		logs := kafkaLogsProducer{
+			producer:    logsProducer,
+			marshaler:   newPdataLogsMarshaler(otlp.NewProtobufLogsMarshaler(), defaultEncoding),
+			dedupHeader: enabled,
+		}
+		require.NoError(t, logs.logsDataPusher(context.Background(), pdata.NewLogs()))
+		require.NoError(t, logs.Close(context.Background()))
+	}
+}
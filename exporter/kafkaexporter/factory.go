@@ -19,8 +19,10 @@ import (
 	"time"
 
 	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -42,6 +44,10 @@ const (
 	defaultProducerMaxMessageBytes = 1000000
 	// default required_acks for the producer
 	defaultProducerRequiredAcks = sarama.WaitForLocal
+	// default timeout of schema registry requests
+	defaultSchemaRegistryTimeout = 5 * time.Second
+	// default time to flush the messages in flight on shutdown
+	defaultProducerFlushTimeout = 5 * time.Second
 )
 
 // FactoryOption applies changes to kafkaExporterFactory.
@@ -56,6 +62,15 @@ func WithTracesMarshalers(tracesMarshalers ...TracesMarshaler) FactoryOption {
 	}
 }
 
+// WithProducerInterceptors adds interceptors called by the producers on every message before
+// it is sent, e.g. to add headers or encrypt the value. The interceptors are called in order,
+// after the headers of the exporter are set, and a panicking interceptor is skipped.
+func WithProducerInterceptors(interceptors ...sarama.ProducerInterceptor) FactoryOption {
+	return func(factory *kafkaExporterFactory) {
+		factory.producerInterceptors = append(factory.producerInterceptors, interceptors...)
+	}
+}
+
 // NewFactory creates Kafka exporter factory.
 func NewFactory(options ...FactoryOption) component.ExporterFactory {
 	f := &kafkaExporterFactory{
@@ -66,6 +81,7 @@ func NewFactory(options ...FactoryOption) component.ExporterFactory {
 	for _, o := range options {
 		o(f)
 	}
+	_ = view.Register(MetricViews()...)
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -95,6 +111,13 @@ func createDefaultConfig() config.Exporter {
 		Producer: Producer{
 			MaxMessageBytes: defaultProducerMaxMessageBytes,
 			RequiredAcks:    defaultProducerRequiredAcks,
+			TimestampSource: timestampSourceProduce,
+			FlushTimeout:    defaultProducerFlushTimeout,
+		},
+		SchemaRegistry: SchemaRegistry{
+			HTTPClientSettings: confighttp.HTTPClientSettings{
+				Timeout: defaultSchemaRegistryTimeout,
+			},
 		},
 	}
 }
@@ -103,6 +126,8 @@ type kafkaExporterFactory struct {
 	tracesMarshalers  map[string]TracesMarshaler
 	metricsMarshalers map[string]MetricsMarshaler
 	logsMarshalers    map[string]LogsMarshaler
+	// producerInterceptors are set on the sarama config of every producer.
+	producerInterceptors []sarama.ProducerInterceptor
 }
 
 func (f *kafkaExporterFactory) createTracesExporter(
@@ -117,7 +142,7 @@ func (f *kafkaExporterFactory) createTracesExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
-	exp, err := newTracesExporter(*oCfg, set, f.tracesMarshalers)
+	exp, err := newTracesExporter(*oCfg, set, f.tracesMarshalers, f.producerInterceptors)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +156,7 @@ func (f *kafkaExporterFactory) createTracesExporter(
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetrySettings),
 		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithStart(exp.Start),
 		exporterhelper.WithShutdown(exp.Close))
 }
 
@@ -146,7 +172,7 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
-	exp, err := newMetricsExporter(*oCfg, set, f.metricsMarshalers)
+	exp, err := newMetricsExporter(*oCfg, set, f.metricsMarshalers, f.producerInterceptors)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +186,7 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetrySettings),
 		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithStart(exp.Start),
 		exporterhelper.WithShutdown(exp.Close))
 }
 
@@ -175,7 +202,7 @@ func (f *kafkaExporterFactory) createLogsExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
-	exp, err := newLogsExporter(*oCfg, set, f.logsMarshalers)
+	exp, err := newLogsExporter(*oCfg, set, f.logsMarshalers, f.producerInterceptors)
 	if err != nil {
 		return nil, err
 	}
@@ -189,5 +216,6 @@ func (f *kafkaExporterFactory) createLogsExporter(
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetrySettings),
 		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithStart(exp.Start),
 		exporterhelper.WithShutdown(exp.Close))
 }
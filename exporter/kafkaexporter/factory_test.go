@@ -16,6 +16,7 @@ package kafkaexporter
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/Shopify/sarama"
@@ -122,6 +123,55 @@ func TestWithMarshalers(t *testing.T) {
 	})
 }
 
+// headerInterceptor adds a header to the messages and records them.
+type headerInterceptor struct {
+	mu       sync.Mutex
+	messages []*sarama.ProducerMessage
+}
+
+func (i *headerInterceptor) OnSend(msg *sarama.ProducerMessage) {
+	msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte("org"), Value: []byte("acme")})
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.messages = append(i.messages, msg)
+}
+
+func TestWithProducerInterceptors(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(defaultTracesTopic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).SetVersion(3),
+	})
+
+	first, second := &headerInterceptor{}, &headerInterceptor{}
+	f := NewFactory(WithProducerInterceptors(first), WithProducerInterceptors(second))
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	exporter, err := f.CreateTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(context.Background(), componenttest.NewNopHost()))
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	require.NoError(t, exporter.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	require.Len(t, first.messages, 1)
+	require.Len(t, second.messages, 1)
+	assert.Same(t, first.messages[0], second.messages[0])
+	assert.Equal(t, defaultTracesTopic, first.messages[0].Topic)
+	// Both interceptors added their header, the second one seeing the header of the first one.
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("org"), Value: []byte("acme")},
+		{Key: []byte("org"), Value: []byte("acme")},
+	}, first.messages[0].Headers)
+}
+
 type customMarshaler struct {
 }
 
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+// flushingProducer counts the messages handed to the producer and not yet acknowledged by the
+// brokers, so that they can be flushed within a deadline when the exporter is shut down.
+type flushingProducer struct {
+	sarama.SyncProducer
+	name         string
+	flushTimeout time.Duration
+	logger       *zap.Logger
+
+	mu       sync.Mutex
+	inFlight int
+	// idle is closed while no message is in flight.
+	idle chan struct{}
+}
+
+var _ sarama.SyncProducer = (*flushingProducer)(nil)
+
+func newFlushingProducer(producer sarama.SyncProducer, name string, flushTimeout time.Duration, logger *zap.Logger) *flushingProducer {
+	idle := make(chan struct{})
+	close(idle)
+	return &flushingProducer{
+		SyncProducer: producer,
+		name:         name,
+		flushTimeout: flushTimeout,
+		logger:       logger,
+		idle:         idle,
+	}
+}
+
+func (p *flushingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.started(1)
+	defer p.done(1)
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func (p *flushingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	p.started(len(msgs))
+	defer p.done(len(msgs))
+	return p.SyncProducer.SendMessages(msgs)
+}
+
+func (p *flushingProducer) started(n int) {
+	if n == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inFlight == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.inFlight += n
+}
+
+func (p *flushingProducer) done(n int) {
+	if n == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight -= n
+	if p.inFlight == 0 {
+		close(p.idle)
+	}
+}
+
+// flush waits until no message is in flight or ctx is done, and returns the number of messages
+// still in flight.
+func (p *flushingProducer) flush(ctx context.Context) int {
+	p.mu.Lock()
+	idle := p.idle
+	p.mu.Unlock()
+	select {
+	case <-idle:
+		return 0
+	case <-ctx.Done():
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.inFlight
+	}
+}
+
+// shutdown closes the producer once the messages in flight are flushed. If they are not flushed
+// within the flush timeout or before ctx is done, the unflushed messages are reported and the
+// producer is closed in the background, as closing it blocks until they are acknowledged.
+func (p *flushingProducer) shutdown(ctx context.Context) error {
+	if p.flushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.flushTimeout)
+		defer cancel()
+	}
+	unflushed := p.flush(ctx)
+	if unflushed == 0 {
+		return p.SyncProducer.Close()
+	}
+
+	p.logger.Warn("Messages were not flushed before the flush deadline, closing the producer", zap.Int("unflushed", unflushed))
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagInstanceName, p.name)}, statUnflushedMessages.M(int64(unflushed)))
+	go func() {
+		if err := p.SyncProducer.Close(); err != nil {
+			p.logger.Debug("Failed to close the producer", zap.Error(err))
+		}
+	}()
+	return fmt.Errorf("failed to flush %d messages before closing the producer", unflushed)
+}
+
+// closeProducer shuts down producer, flushing its messages in flight if it's a flushingProducer.
+func closeProducer(ctx context.Context, producer sarama.SyncProducer) error {
+	if p, ok := producer.(*flushingProducer); ok {
+		return p.shutdown(ctx)
+	}
+	return producer.Close()
+}
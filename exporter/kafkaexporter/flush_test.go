@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+// blockingProducer blocks the messages it's sent until unblock is closed. Like sarama's producers,
+// closing it waits for the messages in flight.
+type blockingProducer struct {
+	sarama.SyncProducer
+	sending chan struct{}
+	unblock chan struct{}
+	sent    chan struct{}
+	closed  chan struct{}
+}
+
+func newBlockingProducer(t *testing.T) *blockingProducer {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageAndSucceed()
+	return &blockingProducer{
+		SyncProducer: producer,
+		sending:      make(chan struct{}),
+		unblock:      make(chan struct{}),
+		sent:         make(chan struct{}),
+		closed:       make(chan struct{}),
+	}
+}
+
+func (p *blockingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	close(p.sending)
+	<-p.unblock
+	defer close(p.sent)
+	return p.SyncProducer.SendMessages(msgs)
+}
+
+func (p *blockingProducer) Close() error {
+	<-p.sent
+	defer close(p.closed)
+	return p.SyncProducer.Close()
+}
+
+func TestFlushingProducerFlushes(t *testing.T) {
+	blocking := newBlockingProducer(t)
+	p := kafkaTracesProducer{
+		producer:  newFlushingProducer(blocking, "kafka", time.Minute, zap.NewNop()),
+		marshaler: newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+	}
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	}()
+	<-blocking.sending
+
+	closed := make(chan error)
+	go func() {
+		closed <- p.Close(context.Background())
+	}()
+	select {
+	case <-closed:
+		t.Fatal("the producer was closed before the messages in flight were flushed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(blocking.unblock)
+	require.NoError(t, <-pushed)
+	require.NoError(t, <-closed)
+	<-blocking.closed
+}
+
+func TestFlushingProducerFlushTimeout(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	blocking := newBlockingProducer(t)
+	p := kafkaTracesProducer{
+		producer:  newFlushingProducer(blocking, "kafka/test", 10*time.Millisecond, zap.NewNop()),
+		marshaler: newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+	}
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	}()
+	<-blocking.sending
+
+	assert.EqualError(t, p.Close(context.Background()), "failed to flush 1 messages before closing the producer")
+
+	rows, err := view.RetrieveData(statUnflushedMessages.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+	assert.Equal(t, "kafka/test", rows[0].Tags[0].Value)
+
+	// The producer is closed in the background once the messages are acknowledged.
+	close(blocking.unblock)
+	require.NoError(t, <-pushed)
+	<-blocking.closed
+}
+
+func TestFlushingProducerIdle(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	p := newFlushingProducer(producer, "kafka", time.Minute, zap.NewNop())
+	require.NoError(t, p.SendMessages(nil))
+	assert.NoError(t, closeProducer(context.Background(), p))
+}
@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package kafkaexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/gogo/protobuf/jsonpb"
+	jaegerproto "github.com/jaegertracing/jaeger/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/zipkin/zipkinv2"
+)
+
+// redpandaEnv enables the tests against Redpanda in addition to Kafka.
+const redpandaEnv = "KAFKA_EXPORTER_INTEGRATION_REDPANDA"
+
+var (
+	// The brokers advertise the host port they are mapped to, which is therefore fixed.
+	containerRequestKafka = testcontainers.ContainerRequest{
+		Image:        "johnnypark/kafka-zookeeper:2.6.0",
+		ExposedPorts: []string{"19092:9092"},
+		Env: map[string]string{
+			"ADVERTISED_HOST": "localhost",
+			"ADVERTISED_PORT": "19092",
+		},
+		WaitingFor: wait.ForListeningPort("9092").WithStartupTimeout(2 * time.Minute),
+	}
+	containerRequestRedpanda = testcontainers.ContainerRequest{
+		Image:        "vectorized/redpanda:v21.11.3",
+		ExposedPorts: []string{"29092:9092"},
+		Cmd: []string{
+			"redpanda", "start", "--overprovisioned", "--smp", "1", "--memory", "512M", "--reserve-memory", "0M",
+			"--node-id", "0", "--check=false",
+			"--kafka-addr", "PLAINTEXT://0.0.0.0:9092", "--advertise-kafka-addr", "PLAINTEXT://localhost:29092",
+		},
+		WaitingFor: wait.ForListeningPort("9092").WithStartupTimeout(2 * time.Minute),
+	}
+)
+
+func TestKafkaExporterIntegration(t *testing.T) {
+	t.Run("Running kafka", func(t *testing.T) {
+		t.Parallel()
+		testExporterIntegration(t, containerRequestKafka)
+	})
+
+	t.Run("Running redpanda", func(t *testing.T) {
+		if os.Getenv(redpandaEnv) == "" {
+			t.Skipf("set %s to run the tests against Redpanda", redpandaEnv)
+		}
+		t.Parallel()
+		testExporterIntegration(t, containerRequestRedpanda)
+	})
+}
+
+func testExporterIntegration(t *testing.T, req testcontainers.ContainerRequest) {
+	container := getContainer(t, req)
+	defer func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	}()
+	hostname, err := container.Host(context.Background())
+	require.NoError(t, err)
+	port, err := container.MappedPort(context.Background(), "9092")
+	require.NoError(t, err)
+	brokers := []string{net.JoinHostPort(hostname, port.Port())}
+	waitForBroker(t, brokers)
+
+	for _, encoding := range []string{"otlp_proto", "otlp_json", "jaeger_proto", "jaeger_json", "zipkin_proto", "zipkin_json"} {
+		t.Run("traces "+encoding, func(t *testing.T) {
+			td := generateTracesTwoTraceIDs()
+			cfg := newIntegrationConfig(brokers, "traces_"+encoding, encoding)
+			createTopic(t, brokers, cfg.Topic)
+
+			exp, err := NewFactory().CreateTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+			require.NoError(t, err)
+			require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+			require.NoError(t, exp.Shutdown(context.Background()))
+
+			wantMessages := 1
+			switch encoding {
+			case "jaeger_proto", "jaeger_json":
+				wantMessages = td.SpanCount()
+			case "zipkin_proto", "zipkin_json":
+				wantMessages = 2
+			}
+			spans := 0
+			for _, message := range consumeMessages(t, brokers, cfg.Topic, wantMessages) {
+				spans += decodeSpans(t, encoding, message.Value)
+			}
+			assert.Equal(t, td.SpanCount(), spans)
+		})
+	}
+
+	for _, encoding := range []string{"otlp_proto", "otlp_json"} {
+		t.Run("metrics "+encoding, func(t *testing.T) {
+			md := testdata.GenerateMetricsTwoMetrics()
+			cfg := newIntegrationConfig(brokers, "metrics_"+encoding, encoding)
+			createTopic(t, brokers, cfg.Topic)
+
+			exp, err := NewFactory().CreateMetricsExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+			require.NoError(t, err)
+			require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
+			require.NoError(t, exp.Shutdown(context.Background()))
+
+			unmarshaler := otlp.NewProtobufMetricsUnmarshaler()
+			if encoding == "otlp_json" {
+				unmarshaler = otlp.NewJSONMetricsUnmarshaler()
+			}
+			messages := consumeMessages(t, brokers, cfg.Topic, 1)
+			got, err := unmarshaler.UnmarshalMetrics(messages[0].Value)
+			require.NoError(t, err)
+			assert.Equal(t, md, got)
+		})
+	}
+
+	for _, encoding := range []string{"otlp_proto", "otlp_json"} {
+		t.Run("logs "+encoding, func(t *testing.T) {
+			ld := testdata.GenerateLogsOneLogRecord()
+			cfg := newIntegrationConfig(brokers, "logs_"+encoding, encoding)
+			createTopic(t, brokers, cfg.Topic)
+
+			exp, err := NewFactory().CreateLogsExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+			require.NoError(t, err)
+			require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+			require.NoError(t, exp.Shutdown(context.Background()))
+
+			unmarshaler := otlp.NewProtobufLogsUnmarshaler()
+			if encoding == "otlp_json" {
+				unmarshaler = otlp.NewJSONLogsUnmarshaler()
+			}
+			messages := consumeMessages(t, brokers, cfg.Topic, 1)
+			got, err := unmarshaler.UnmarshalLogs(messages[0].Value)
+			require.NoError(t, err)
+			assert.Equal(t, ld, got)
+		})
+	}
+}
+
+func getContainer(t *testing.T, req testcontainers.ContainerRequest) testcontainers.Container {
+	require.NoError(t, req.Validate())
+	container, err := testcontainers.GenericContainer(
+		context.Background(),
+		testcontainers.GenericContainerRequest{
+			ContainerRequest: req,
+			Started:          true,
+		})
+	require.NoError(t, err)
+	return container
+}
+
+// newIntegrationConfig returns the config of an exporter sending synchronously to topic.
+func newIntegrationConfig(brokers []string, topic, encoding string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = brokers
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.Topic = topic
+	cfg.Encoding = encoding
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	return cfg
+}
+
+func newIntegrationSaramaConfig() *sarama.Config {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_0_0_0
+	return saramaConfig
+}
+
+// waitForBroker waits until the broker accepts connections and knows its controller,
+// which may take a while after the port is listened to.
+func waitForBroker(t *testing.T, brokers []string) {
+	require.Eventuallyf(t, func() bool {
+		admin, err := sarama.NewClusterAdmin(brokers, newIntegrationSaramaConfig())
+		if err != nil {
+			return false
+		}
+		defer admin.Close()
+		_, _, err = admin.DescribeCluster()
+		return err == nil
+	}, time.Minute, time.Second, "broker %v is not ready", brokers)
+}
+
+// createTopic creates a topic with a single partition, so that messages are consumed in order.
+func createTopic(t *testing.T, brokers []string, topic string) {
+	admin, err := sarama.NewClusterAdmin(brokers, newIntegrationSaramaConfig())
+	require.NoError(t, err)
+	defer admin.Close()
+	require.NoError(t, admin.CreateTopic(topic, &sarama.TopicDetail{NumPartitions: 1, ReplicationFactor: 1}, false))
+}
+
+// consumeMessages reads the first count messages of topic.
+func consumeMessages(t *testing.T, brokers []string, topic string, count int) []*sarama.ConsumerMessage {
+	consumer, err := sarama.NewConsumer(brokers, newIntegrationSaramaConfig())
+	require.NoError(t, err)
+	defer consumer.Close()
+	partitionConsumer, err := consumer.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	require.NoError(t, err)
+	defer partitionConsumer.Close()
+
+	var messages []*sarama.ConsumerMessage
+	timeout := time.After(30 * time.Second)
+	for len(messages) < count {
+		select {
+		case message := <-partitionConsumer.Messages():
+			messages = append(messages, message)
+		case <-timeout:
+			require.Failf(t, "timeout", "received %d messages of %d from topic %s", len(messages), count, topic)
+		}
+	}
+	return messages
+}
+
+// decodeSpans returns the number of spans of a message encoded with encoding.
+func decodeSpans(t *testing.T, encoding string, value []byte) int {
+	var unmarshaler pdata.TracesUnmarshaler
+	switch encoding {
+	case "otlp_proto":
+		unmarshaler = otlp.NewProtobufTracesUnmarshaler()
+	case "otlp_json":
+		unmarshaler = otlp.NewJSONTracesUnmarshaler()
+	case "zipkin_proto":
+		unmarshaler = zipkinv2.NewProtobufTracesUnmarshaler(false, false)
+	case "zipkin_json":
+		unmarshaler = zipkinv2.NewJSONTracesUnmarshaler(false)
+	case "jaeger_proto":
+		span := &jaegerproto.Span{}
+		require.NoError(t, span.Unmarshal(value))
+		return 1
+	case "jaeger_json":
+		span := &jaegerproto.Span{}
+		require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(value), span))
+		return 1
+	default:
+		require.FailNow(t, fmt.Sprintf("unknown encoding %s", encoding))
+	}
+	td, err := unmarshaler.UnmarshalTraces(value)
+	require.NoError(t, err)
+	return td.SpanCount()
+}
+
+// generateTracesTwoTraceIDs returns three spans of two traces, with the trace and span IDs
+// required by the jaeger and zipkin encodings.
+func generateTracesTwoTraceIDs() pdata.Traces {
+	traceIDs := []pdata.TraceID{
+		pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}),
+		pdata.NewTraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}),
+		pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}),
+	}
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "integration")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i, traceID := range traceIDs {
+		span := spans.AppendEmpty()
+		span.SetName(fmt.Sprintf("span-%d", i))
+		span.SetStartTimestamp(pdata.NewTimestampFromTime(time.Unix(1640995200, 0)))
+		span.SetEndTimestamp(pdata.NewTimestampFromTime(time.Unix(1640995201, 0)))
+		span.SetTraceID(traceID)
+		span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+	return td
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// Client registers schemas with a schema registry.
+type Client struct {
+	httpClient *http.Client
+	endpoint   string
+	username   string
+	password   string
+}
+
+// NewClient creates a Client sending requests to the schema registry at endpoint.
+// Requests are authenticated with basic auth when username is not empty.
+func NewClient(httpClient *http.Client, endpoint, username, password string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		username:   username,
+		password:   password,
+	}
+}
+
+type registerRequest struct {
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+type registerResponse struct {
+	ID int `json:"id"`
+}
+
+type errorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// Register registers the protobuf schema under subject and returns its ID.
+// Registering a schema that is already registered under subject returns the
+// ID of the existing schema.
+func (c *Client) Register(ctx context.Context, subject string, schema string) (int, error) {
+	body, err := json.Marshal(registerRequest{SchemaType: "PROTOBUF", Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+	endpoint := fmt.Sprintf("%s/subjects/%s/versions", c.endpoint, url.PathEscape(subject))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", contentType)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register schema for subject %q: %w", subject, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err = json.Unmarshal(respBody, &errResp); err == nil && errResp.Message != "" {
+			return 0, fmt.Errorf("failed to register schema for subject %q: %s (error code %d)", subject, errResp.Message, errResp.ErrorCode)
+		}
+		return 0, fmt.Errorf("failed to register schema for subject %q: unexpected status code %d", subject, resp.StatusCode)
+	}
+
+	var regResp registerResponse
+	if err = json.Unmarshal(respBody, &regResp); err != nil {
+		return 0, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return regResp.ID, nil
+}
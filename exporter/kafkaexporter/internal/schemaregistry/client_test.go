@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRegister(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/subjects/otlp_spans-value/versions", r.URL.Path)
+		assert.Equal(t, contentType, r.Header.Get("Content-Type"))
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "jdoe", user)
+		assert.Equal(t, "pass", pass)
+
+		var req registerRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "PROTOBUF", req.SchemaType)
+		assert.Equal(t, TracesSchema, req.Schema)
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), server.URL+"/", "jdoe", "pass")
+	id, err := client.Register(context.Background(), "otlp_spans-value", TracesSchema)
+	require.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func TestClientRegisterError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    string
+	}{
+		{
+			name:   "registry error",
+			status: http.StatusConflict,
+			body:   `{"error_code":409,"message":"Schema being registered is incompatible with an earlier schema"}`,
+			err:    `failed to register schema for subject "otlp_spans-value": Schema being registered is incompatible with an earlier schema (error code 409)`,
+		},
+		{
+			name:   "unexpected status",
+			status: http.StatusInternalServerError,
+			body:   "oops",
+			err:    `failed to register schema for subject "otlp_spans-value": unexpected status code 500`,
+		},
+		{
+			name:   "invalid response",
+			status: http.StatusOK,
+			body:   "{",
+			err:    "failed to decode schema registry response: unexpected end of JSON input",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.Client(), server.URL, "", "")
+			_, err := client.Register(context.Background(), "otlp_spans-value", TracesSchema)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schemaregistry implements the parts of the Confluent schema registry
+// protocol needed to produce protobuf messages that are accepted by topics with
+// schema validation enabled: schema registration and wire format framing.
+//
+// Further details on the wire format can be viewed here:
+// 		https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
+
+import (
+	_ "embed"
+)
+
+var (
+	// TracesSchema is the OTLP TracesData protobuf schema.
+	//go:embed otlp_traces.proto
+	TracesSchema string
+
+	// MetricsSchema is the OTLP MetricsData protobuf schema.
+	//go:embed otlp_metrics.proto
+	MetricsSchema string
+
+	// LogsSchema is the OTLP LogsData protobuf schema.
+	//go:embed otlp_logs.proto
+	LogsSchema string
+)
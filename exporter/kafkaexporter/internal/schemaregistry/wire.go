@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
+
+import (
+	"encoding/binary"
+)
+
+const (
+	magicByte = 0x0
+	// headerSize is the size of the magic byte, the schema ID and the
+	// message index array of a framed message.
+	headerSize = 1 + 4 + 1
+)
+
+// Frame prepends the Confluent protobuf wire format header to payload:
+// the magic byte, the big-endian schema ID and the message indexes. The
+// payload is always the first message of the schema, message indexes
+// [0] are encoded as a single zero byte.
+func Frame(schemaID int, payload []byte) []byte {
+	bts := make([]byte, headerSize, headerSize+len(payload))
+	bts[0] = magicByte
+	binary.BigEndian.PutUint32(bts[1:5], uint32(schemaID))
+	bts[5] = 0
+	return append(bts, payload...)
+}
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrame(t *testing.T) {
+	framed := Frame(258, []byte{0xa, 0xb})
+	assert.Equal(t, []byte{0x0, 0x0, 0x0, 0x1, 0x2, 0x0, 0xa, 0xb}, framed)
+}
+
+func TestFrameEmptyPayload(t *testing.T) {
+	framed := Frame(1, nil)
+	assert.Equal(t, []byte{0x0, 0x0, 0x0, 0x0, 0x1, 0x0}, framed)
+}
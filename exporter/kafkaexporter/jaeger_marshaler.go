@@ -31,8 +31,18 @@ type jaegerMarshaler struct {
 }
 
 var _ TracesMarshaler = (*jaegerMarshaler)(nil)
+var _ spanTimestampsMarshaler = (*jaegerMarshaler)(nil)
 
 func (j jaegerMarshaler) Marshal(traces pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	return j.marshal(traces, topic, false)
+}
+
+// marshalWithTimestamps marshals the spans like Marshal, setting the timestamp of every message to the end time of its span.
+func (j jaegerMarshaler) marshalWithTimestamps(traces pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	return j.marshal(traces, topic, true)
+}
+
+func (j jaegerMarshaler) marshal(traces pdata.Traces, topic string, withTimestamps bool) ([]*sarama.ProducerMessage, error) {
 	batches, err := jaegertranslator.InternalTracesToJaegerProto(traces)
 	if err != nil {
 		return nil, err
@@ -50,11 +60,16 @@ func (j jaegerMarshaler) Marshal(traces pdata.Traces, topic string) ([]*sarama.P
 				continue
 			}
 			key := []byte(span.TraceID.String())
-			messages = append(messages, &sarama.ProducerMessage{
+			message := &sarama.ProducerMessage{
 				Topic: topic,
 				Value: sarama.ByteEncoder(bts),
 				Key:   sarama.ByteEncoder(key),
-			})
+			}
+			// Spans without an end time are left to the produce time.
+			if end := span.StartTime.Add(span.Duration); withTimestamps && end.UnixNano() > 0 {
+				message.Timestamp = end
+			}
+			messages = append(messages, message)
 		}
 	}
 	return messages, errs
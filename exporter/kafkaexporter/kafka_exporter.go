@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
 )
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
@@ -31,8 +33,15 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 type kafkaTracesProducer struct {
 	producer  sarama.SyncProducer
 	topic     string
+	router    topicRouter
 	marshaler TracesMarshaler
+	framer    *schemaRegistryFramer
+	splitter  batchSplitter
 	logger    *zap.Logger
+
+	timestampFromTelemetry bool
+	signalHeader           bool
+	dedupHeader            bool
 }
 
 type kafkaErrors struct {
@@ -44,86 +53,154 @@ func (ke kafkaErrors) Error() string {
 	return fmt.Sprintf("Failed to deliver %d messages due to %s", ke.count, ke.err)
 }
 
-func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td pdata.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+func (e *kafkaTracesProducer) tracesPusher(ctx context.Context, td pdata.Traces) error {
+	topic := e.router.topic(ctx, e.topic)
+	var messages []*sarama.ProducerMessage
+	for _, part := range e.splitter.splitTraces(ctx, td) {
+		var partMessages []*sarama.ProducerMessage
+		var err error
+		if e.timestampFromTelemetry {
+			partMessages, err = marshalTracesWithTimestamps(e.marshaler, part, topic)
+		} else {
+			partMessages, err = e.marshaler.Marshal(part, topic)
+		}
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		if e.signalHeader {
+			setSignalHeader(partMessages, signalTraces)
+		}
+		if e.dedupHeader {
+			setDedupHeader(partMessages, tracesIdentity(part))
+		}
+		messages = append(messages, partMessages...)
 	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		if value, ok := err.(sarama.ProducerErrors); ok {
-			if len(value) > 0 {
-				return kafkaErrors{len(value), value[0].Err.Error()}
-			}
+	if e.framer != nil {
+		if err := e.framer.frame(ctx, topic, messages); err != nil {
+			return err
 		}
-		return err
+	}
+	if err := e.producer.SendMessages(messages); err != nil {
+		return wrapSendError(err)
 	}
 	return nil
 }
 
-func (e *kafkaTracesProducer) Close(context.Context) error {
-	return e.producer.Close()
+func (e *kafkaTracesProducer) Start(_ context.Context, host component.Host) error {
+	return e.framer.start(host)
+}
+
+func (e *kafkaTracesProducer) Close(ctx context.Context) error {
+	return closeProducer(ctx, e.producer)
 }
 
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
 	producer  sarama.SyncProducer
 	topic     string
+	router    topicRouter
 	marshaler MetricsMarshaler
+	framer    *schemaRegistryFramer
+	splitter  batchSplitter
 	logger    *zap.Logger
+
+	timestampFromTelemetry bool
+	signalHeader           bool
+	dedupHeader            bool
 }
 
-func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pdata.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pdata.Metrics) error {
+	topic := e.router.topic(ctx, e.topic)
+	var messages []*sarama.ProducerMessage
+	for _, part := range e.splitter.splitMetrics(ctx, md) {
+		partMessages, err := e.marshaler.Marshal(part, topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		if e.timestampFromTelemetry {
+			setTimestamp(partMessages, metricsTimestamp(part))
+		}
+		if e.signalHeader {
+			setSignalHeader(partMessages, signalMetrics)
+		}
+		if e.dedupHeader {
+			setDedupHeader(partMessages, metricsIdentity(part))
+		}
+		messages = append(messages, partMessages...)
 	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		if value, ok := err.(sarama.ProducerErrors); ok {
-			if len(value) > 0 {
-				return kafkaErrors{len(value), value[0].Err.Error()}
-			}
+	if e.framer != nil {
+		if err := e.framer.frame(ctx, topic, messages); err != nil {
+			return err
 		}
-		return err
+	}
+	if err := e.producer.SendMessages(messages); err != nil {
+		return wrapSendError(err)
 	}
 	return nil
 }
 
-func (e *kafkaMetricsProducer) Close(context.Context) error {
-	return e.producer.Close()
+func (e *kafkaMetricsProducer) Start(_ context.Context, host component.Host) error {
+	return e.framer.start(host)
+}
+
+func (e *kafkaMetricsProducer) Close(ctx context.Context) error {
+	return closeProducer(ctx, e.producer)
 }
 
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
 	producer  sarama.SyncProducer
 	topic     string
+	router    topicRouter
 	marshaler LogsMarshaler
+	framer    *schemaRegistryFramer
+	splitter  batchSplitter
 	logger    *zap.Logger
+
+	timestampFromTelemetry bool
+	signalHeader           bool
+	dedupHeader            bool
 }
 
-func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld pdata.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld pdata.Logs) error {
+	topic := e.router.topic(ctx, e.topic)
+	var messages []*sarama.ProducerMessage
+	for _, part := range e.splitter.splitLogs(ctx, ld) {
+		partMessages, err := e.marshaler.Marshal(part, topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		if e.timestampFromTelemetry {
+			setTimestamp(partMessages, logsTimestamp(part))
+		}
+		if e.signalHeader {
+			setSignalHeader(partMessages, signalLogs)
+		}
+		if e.dedupHeader {
+			setDedupHeader(partMessages, logsIdentity(part))
+		}
+		messages = append(messages, partMessages...)
 	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		if value, ok := err.(sarama.ProducerErrors); ok {
-			if len(value) > 0 {
-				return kafkaErrors{len(value), value[0].Err.Error()}
-			}
+	if e.framer != nil {
+		if err := e.framer.frame(ctx, topic, messages); err != nil {
+			return err
 		}
-		return err
+	}
+	if err := e.producer.SendMessages(messages); err != nil {
+		return wrapSendError(err)
 	}
 	return nil
 }
 
-func (e *kafkaLogsProducer) Close(context.Context) error {
-	return e.producer.Close()
+func (e *kafkaLogsProducer) Start(_ context.Context, host component.Host) error {
+	return e.framer.start(host)
+}
+
+func (e *kafkaLogsProducer) Close(ctx context.Context) error {
+	return closeProducer(ctx, e.producer)
 }
 
-func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
+func newSaramaProducer(config Config, logger *zap.Logger, interceptors []sarama.ProducerInterceptor) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 	// These setting are required by the sarama.SyncProducer implementation.
 	c.Producer.Return.Successes = true
@@ -135,8 +212,9 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
 	c.Producer.MaxMessageBytes = config.Producer.MaxMessageBytes
+	c.Producer.Interceptors = interceptors
 	if config.ProtocolVersion != "" {
-		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
+		version, err := parseProtocolVersion(config.ProtocolVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -145,65 +223,106 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	if err := ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
+	if config.ProtocolVersionNegotiation {
+		c.Version = negotiateProtocolVersion(config.Brokers, c, logger)
+	}
 	producer, err := sarama.NewSyncProducer(config.Brokers, c)
 	if err != nil {
-		return nil, err
+		// Brokers close the connections using a newer protocol than theirs, which sarama
+		// reports as running out of brokers.
+		return nil, fmt.Errorf("failed to create the producer with protocol version %v, which must not be newer than the version of the brokers: %w", c.Version, err)
 	}
 	return producer, nil
 }
 
-func newMetricsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]MetricsMarshaler) (*kafkaMetricsProducer, error) {
+func newMetricsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]MetricsMarshaler, interceptors []sarama.ProducerInterceptor) (*kafkaMetricsProducer, error) {
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	framer := newSchemaRegistryFramer(config.SchemaRegistry, schemaregistry.MetricsSchema)
+	router, err := newTopicRouter(config.TopicRouting, config.TopicRouting.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger, interceptors)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaMetricsProducer{
-		producer:  producer,
+		producer:  newFlushingProducer(producer, config.ID().String(), config.Producer.FlushTimeout, set.Logger),
 		topic:     config.Topic,
+		router:    router,
 		marshaler: marshaler,
+		framer:    framer,
+		splitter:  newBatchSplitter(config.ID().String(), config.Producer.BatchSplit),
 		logger:    set.Logger,
+
+		timestampFromTelemetry: config.Producer.TimestampSource == timestampSourceTelemetry,
+		signalHeader:           config.SignalHeader,
+		dedupHeader:            config.DedupHeader,
 	}, nil
 
 }
 
 // newTracesExporter creates Kafka exporter.
-func newTracesExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]TracesMarshaler) (*kafkaTracesProducer, error) {
+func newTracesExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]TracesMarshaler, interceptors []sarama.ProducerInterceptor) (*kafkaTracesProducer, error) {
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	framer := newSchemaRegistryFramer(config.SchemaRegistry, schemaregistry.TracesSchema)
+	router, err := newTopicRouter(config.TopicRouting, config.TopicRouting.Traces)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger, interceptors)
 	if err != nil {
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:  producer,
+		producer:  newFlushingProducer(producer, config.ID().String(), config.Producer.FlushTimeout, set.Logger),
 		topic:     config.Topic,
+		router:    router,
 		marshaler: marshaler,
+		framer:    framer,
+		splitter:  newBatchSplitter(config.ID().String(), config.Producer.BatchSplit),
 		logger:    set.Logger,
+
+		timestampFromTelemetry: config.Producer.TimestampSource == timestampSourceTelemetry,
+		signalHeader:           config.SignalHeader,
+		dedupHeader:            config.DedupHeader,
 	}, nil
 }
 
-func newLogsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]LogsMarshaler) (*kafkaLogsProducer, error) {
+func newLogsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]LogsMarshaler, interceptors []sarama.ProducerInterceptor) (*kafkaLogsProducer, error) {
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	framer := newSchemaRegistryFramer(config.SchemaRegistry, schemaregistry.LogsSchema)
+	router, err := newTopicRouter(config.TopicRouting, config.TopicRouting.Logs)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger, interceptors)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaLogsProducer{
-		producer:  producer,
+		producer:  newFlushingProducer(producer, config.ID().String(), config.Producer.FlushTimeout, set.Logger),
 		topic:     config.Topic,
+		router:    router,
 		marshaler: marshaler,
+		framer:    framer,
+		splitter:  newBatchSplitter(config.ID().String(), config.Producer.BatchSplit),
 		logger:    set.Logger,
+
+		timestampFromTelemetry: config.Producer.TimestampSource == timestampSourceTelemetry,
+		signalHeader:           config.SignalHeader,
+		dedupHeader:            config.DedupHeader,
 	}, nil
 
 }
@@ -29,8 +29,11 @@ func TestDefaultTracesMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
 		"otlp_json",
+		"otlp_proto_zstd",
 		"jaeger_proto",
 		"jaeger_json",
+		"zipkin_proto",
+		"zipkin_json",
 	}
 	marshalers := tracesMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -47,6 +50,7 @@ func TestDefaultMetricsMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
 		"otlp_json",
+		"otlp_proto_zstd",
 	}
 	marshalers := metricsMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -63,6 +67,7 @@ func TestDefaultLogsMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
 		"otlp_json",
+		"otlp_proto_zstd",
 	}
 	marshalers := logsMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	tagInstanceName, _ = tag.NewKey("name")
+
+	statUnflushedMessages = stats.Int64("kafka_exporter_unflushed_messages", "Number of messages in flight which were not flushed before the producer was closed", stats.UnitDimensionless)
+	statSplitBatches      = stats.Int64("kafka_exporter_split_batches", "Number of batches split into several messages by producer.batch_split", stats.UnitDimensionless)
+	statSplitMessages     = stats.Int64("kafka_exporter_split_messages", "Number of parts the batches split by producer.batch_split were marshaled from", stats.UnitDimensionless)
+)
+
+// MetricViews return metric views for Kafka exporter.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        statUnflushedMessages.Name(),
+			Measure:     statUnflushedMessages,
+			Description: statUnflushedMessages.Description(),
+			TagKeys:     []tag.Key{tagInstanceName},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statSplitBatches.Name(),
+			Measure:     statSplitBatches,
+			Description: statSplitBatches.Description(),
+			TagKeys:     []tag.Key{tagInstanceName},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statSplitMessages.Name(),
+			Measure:     statSplitMessages,
+			Description: statSplitMessages.Description(),
+			TagKeys:     []tag.Key{tagInstanceName},
+			Aggregation: view.Sum(),
+		},
+	}
+}
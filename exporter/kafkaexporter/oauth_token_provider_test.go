@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthTokenProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "kafka", r.Form.Get("scope"))
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "jdoe", clientID)
+		assert.Equal(t, "pass", clientSecret)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"my-token","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	provider := newOAuthTokenProvider("jdoe", "pass", OAuthConfig{TokenURL: server.URL, Scopes: []string{"kafka"}})
+	token, err := provider.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token.Token)
+
+	// the token is cached until it expires
+	token, err = provider.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token.Token)
+	assert.Equal(t, 1, requests)
+}
+
+func TestOAuthTokenProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	provider := newOAuthTokenProvider("jdoe", "pass", OAuthConfig{TokenURL: server.URL})
+	_, err := provider.Token()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve OAuth token")
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+)
+
+// apiKeyProduce is the key of the Produce API in ApiVersions responses.
+const apiKeyProduce = 0
+
+// produceVersions maps the highest Produce API version supported by a broker to the protocol
+// version of the broker, from the newest.
+var produceVersions = []struct {
+	maxVersion int16
+	version    sarama.KafkaVersion
+}{
+	{maxVersion: 8, version: sarama.V2_4_0_0},
+	{maxVersion: 7, version: sarama.V2_1_0_0},
+	{maxVersion: 6, version: sarama.V2_0_0_0},
+	{maxVersion: 5, version: sarama.V1_1_0_0},
+	{maxVersion: 4, version: sarama.V1_0_0_0},
+	{maxVersion: 3, version: sarama.V0_11_0_0},
+	{maxVersion: 2, version: sarama.V0_10_0_0},
+}
+
+// parseProtocolVersion parses a Kafka version. The versions older than 1.0.0 have four
+// numbers, e.g. 0.10.2.0, a missing fourth number is accepted.
+func parseProtocolVersion(s string) (sarama.KafkaVersion, error) {
+	version, err := sarama.ParseKafkaVersion(s)
+	if err != nil && strings.HasPrefix(s, "0.") && strings.Count(s, ".") == 2 {
+		return sarama.ParseKafkaVersion(s + ".0")
+	}
+	return version, err
+}
+
+// negotiateProtocolVersion returns the protocol version of c lowered to the version of the
+// oldest broker, found by sending them an ApiVersions request. The request is sent with the
+// 0.10.0 protocol, the first version supporting it. Brokers which can't be reached, or are
+// older than 0.10.0, are skipped. The version is never lowered for brokers of 2.4.0 or newer.
+func negotiateProtocolVersion(brokers []string, c *sarama.Config, logger *zap.Logger) sarama.KafkaVersion {
+	probe := *c
+	probe.Version = sarama.V0_10_0_0
+	probe.ApiVersionsRequest = false
+
+	version := c.Version
+	for _, addr := range brokers {
+		brokerVersion, err := brokerProtocolVersion(addr, &probe)
+		if err != nil {
+			logger.Warn("Failed to get the protocol version of the broker", zap.String("broker", addr), zap.Error(err))
+			continue
+		}
+		if brokerVersion != produceVersions[0].version && !brokerVersion.IsAtLeast(version) {
+			version = brokerVersion
+		}
+	}
+	if version != c.Version {
+		logger.Info("Lowered the protocol version to the version of the brokers",
+			zap.String("configured", c.Version.String()), zap.String("negotiated", version.String()))
+	}
+	return version
+}
+
+// brokerProtocolVersion returns the protocol version of the broker at addr, capped at 2.4.0.
+func brokerProtocolVersion(addr string, c *sarama.Config) (sarama.KafkaVersion, error) {
+	broker := sarama.NewBroker(addr)
+	if err := broker.Open(c); err != nil {
+		return sarama.KafkaVersion{}, err
+	}
+	defer broker.Close()
+	resp, err := broker.ApiVersions(&sarama.ApiVersionsRequest{})
+	if err != nil {
+		return sarama.KafkaVersion{}, err
+	}
+	if resp.ErrorCode != int16(sarama.ErrNoError) {
+		return sarama.KafkaVersion{}, sarama.KError(resp.ErrorCode)
+	}
+
+	maxVersion := int16(-1)
+	for _, key := range resp.ApiKeys {
+		if key.ApiKey == apiKeyProduce {
+			maxVersion = key.MaxVersion
+		}
+	}
+	for _, v := range produceVersions {
+		if maxVersion >= v.maxVersion {
+			return v.version, nil
+		}
+	}
+	// Brokers answering ApiVersions requests are at least 0.10.0.
+	return sarama.V0_10_0_0, nil
+}
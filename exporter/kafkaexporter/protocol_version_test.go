@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseProtocolVersion(t *testing.T) {
+	for s, want := range map[string]sarama.KafkaVersion{
+		"2.0.0":    sarama.V2_0_0_0,
+		"0.11.0.0": sarama.V0_11_0_0,
+		"0.10.2":   sarama.V0_10_2_0,
+	} {
+		version, err := parseProtocolVersion(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, version, s)
+	}
+	_, err := parseProtocolVersion("0.10")
+	assert.Error(t, err)
+}
+
+// newVersionedBroker returns a mock broker whose highest supported Produce API version is maxProduceVersion.
+func newVersionedBroker(t *testing.T, maxProduceVersion int16) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockWrapper(&sarama.ApiVersionsResponse{
+			ApiKeys: []sarama.ApiVersionsResponseKey{
+				{ApiKey: apiKeyProduce, MinVersion: 0, MaxVersion: maxProduceVersion},
+				{ApiKey: 3, MinVersion: 0, MaxVersion: 5},
+			},
+		}),
+	})
+	return broker
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		configured        sarama.KafkaVersion
+		maxProduceVersion []int16
+		want              sarama.KafkaVersion
+	}{
+		{
+			name:              "older broker",
+			configured:        sarama.V2_0_0_0,
+			maxProduceVersion: []int16{3},
+			want:              sarama.V0_11_0_0,
+		},
+		{
+			name:              "oldest broker",
+			configured:        sarama.V2_0_0_0,
+			maxProduceVersion: []int16{6, 2, 5},
+			want:              sarama.V0_10_0_0,
+		},
+		{
+			name:              "newer broker",
+			configured:        sarama.V0_11_0_0,
+			maxProduceVersion: []int16{7},
+			want:              sarama.V0_11_0_0,
+		},
+		{
+			name:              "recent broker",
+			configured:        sarama.V2_8_0_0,
+			maxProduceVersion: []int16{9},
+			want:              sarama.V2_8_0_0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var addrs []string
+			for _, v := range test.maxProduceVersion {
+				addrs = append(addrs, newVersionedBroker(t, v).Addr())
+			}
+			c := sarama.NewConfig()
+			c.Version = test.configured
+			assert.Equal(t, test.want, negotiateProtocolVersion(addrs, c, zap.NewNop()))
+			// The config is left untouched.
+			assert.Equal(t, test.configured, c.Version)
+		})
+	}
+}
+
+func TestNegotiateProtocolVersion_unreachableBroker(t *testing.T) {
+	unreachable := sarama.NewMockBroker(t, 1)
+	addr := unreachable.Addr()
+	unreachable.Close()
+
+	c := sarama.NewConfig()
+	c.Version = sarama.V2_0_0_0
+	c.Net.DialTimeout = 100 * time.Millisecond
+	assert.Equal(t, sarama.V2_0_0_0, negotiateProtocolVersion([]string{addr}, c, zap.NewNop()))
+
+	broker := newVersionedBroker(t, 4)
+	assert.Equal(t, sarama.V1_0_0_0, negotiateProtocolVersion([]string{addr, broker.Addr()}, c, zap.NewNop()))
+}
+
+func TestNewSaramaProducer_protocolVersionNegotiation(t *testing.T) {
+	broker := newVersionedBroker(t, 3)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockWrapper(&sarama.ApiVersionsResponse{
+			ApiKeys: []sarama.ApiVersionsResponseKey{{ApiKey: apiKeyProduce, MaxVersion: 3}},
+		}),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()),
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.ProtocolVersionNegotiation = true
+	producer, err := newSaramaProducer(*cfg, zap.NewNop(), nil)
+	require.NoError(t, err)
+	assert.NoError(t, producer.Close())
+
+	var metadataVersions []int16
+	for _, rr := range broker.History() {
+		if req, ok := rr.Request.(*sarama.MetadataRequest); ok {
+			metadataVersions = append(metadataVersions, req.Version)
+		}
+	}
+	// The metadata requests use the negotiated 0.11.0 protocol.
+	require.NotEmpty(t, metadataVersions)
+	for _, v := range metadataVersions {
+		assert.LessOrEqual(t, v, int16(4))
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// timestampSourceProduce sets the record timestamp to the time records are produced.
+	timestampSourceProduce = "produce"
+	// timestampSourceTelemetry sets the record timestamp from the timestamps of the telemetry.
+	timestampSourceTelemetry = "telemetry"
+)
+
+// setTimestamp sets the timestamp of messages, leaving them to the produce time if ts is zero.
+func setTimestamp(messages []*sarama.ProducerMessage, ts pdata.Timestamp) {
+	if ts == 0 {
+		return
+	}
+	t := ts.AsTime()
+	for _, m := range messages {
+		m.Timestamp = t
+	}
+}
+
+// spanTimestampsMarshaler is implemented by the traces marshalers producing a message per span, which set
+// the timestamp of every message to the end time of its span rather than of the latest span of the batch.
+type spanTimestampsMarshaler interface {
+	marshalWithTimestamps(traces pdata.Traces, topic string) ([]*sarama.ProducerMessage, error)
+}
+
+// marshalTracesWithTimestamps marshals td and sets the timestamp of every message from the spans it holds.
+func marshalTracesWithTimestamps(marshaler TracesMarshaler, td pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	if m, ok := marshaler.(spanTimestampsMarshaler); ok {
+		return m.marshalWithTimestamps(td, topic)
+	}
+	// The other marshalers produce a single message holding the whole batch.
+	messages, err := marshaler.Marshal(td, topic)
+	if err != nil {
+		return nil, err
+	}
+	setTimestamp(messages, tracesTimestamp(td))
+	return messages, nil
+}
+
+// tracesTimestamp returns the latest end time of the spans.
+func tracesTimestamp(td pdata.Traces) pdata.Timestamp {
+	var latest pdata.Timestamp
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				latest = maxTimestamp(latest, spans.At(k).EndTimestamp())
+			}
+		}
+	}
+	return latest
+}
+
+// metricsTimestamp returns the latest timestamp of the metric data points.
+func metricsTimestamp(md pdata.Metrics) pdata.Timestamp {
+	var latest pdata.Timestamp
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				latest = maxTimestamp(latest, metricTimestamp(metrics.At(k)))
+			}
+		}
+	}
+	return latest
+}
+
+func metricTimestamp(m pdata.Metric) pdata.Timestamp {
+	var latest pdata.Timestamp
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			latest = maxTimestamp(latest, dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			latest = maxTimestamp(latest, dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			latest = maxTimestamp(latest, dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			latest = maxTimestamp(latest, dps.At(i).Timestamp())
+		}
+	case pdata.MetricDataTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			latest = maxTimestamp(latest, dps.At(i).Timestamp())
+		}
+	}
+	return latest
+}
+
+// logsTimestamp returns the latest timestamp of the log records.
+func logsTimestamp(ld pdata.Logs) pdata.Timestamp {
+	var latest pdata.Timestamp
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				latest = maxTimestamp(latest, logs.At(k).Timestamp())
+			}
+		}
+	}
+	return latest
+}
+
+func maxTimestamp(a, b pdata.Timestamp) pdata.Timestamp {
+	if b > a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// recordingTracesMarshaler keeps the messages it returns so that their timestamps can be checked.
+type recordingTracesMarshaler struct {
+	messages []*sarama.ProducerMessage
+}
+
+func (r *recordingTracesMarshaler) Marshal(_ pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	r.messages = []*sarama.ProducerMessage{{Topic: topic, Value: sarama.StringEncoder("")}}
+	return r.messages, nil
+}
+
+func (r *recordingTracesMarshaler) Encoding() string {
+	return "recording"
+}
+
+func TestTracesTimestamp(t *testing.T) {
+	td := pdata.NewTraces()
+	assert.Equal(t, pdata.Timestamp(0), tracesTimestamp(td))
+
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetEndTimestamp(20)
+	spans.AppendEmpty().SetEndTimestamp(30)
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetEndTimestamp(10)
+	assert.Equal(t, pdata.Timestamp(30), tracesTimestamp(td))
+}
+
+func TestMetricsTimestamp(t *testing.T) {
+	md := pdata.NewMetrics()
+	assert.Equal(t, pdata.Timestamp(0), metricsTimestamp(md))
+
+	metrics := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+	gauge := metrics.AppendEmpty()
+	gauge.SetDataType(pdata.MetricDataTypeGauge)
+	gauge.Gauge().DataPoints().AppendEmpty().SetTimestamp(10)
+	sum := metrics.AppendEmpty()
+	sum.SetDataType(pdata.MetricDataTypeSum)
+	sum.Sum().DataPoints().AppendEmpty().SetTimestamp(20)
+	histogram := metrics.AppendEmpty()
+	histogram.SetDataType(pdata.MetricDataTypeHistogram)
+	histogram.Histogram().DataPoints().AppendEmpty().SetTimestamp(30)
+	assert.Equal(t, pdata.Timestamp(30), metricsTimestamp(md))
+
+	expHistogram := metrics.AppendEmpty()
+	expHistogram.SetDataType(pdata.MetricDataTypeExponentialHistogram)
+	expHistogram.ExponentialHistogram().DataPoints().AppendEmpty().SetTimestamp(40)
+	assert.Equal(t, pdata.Timestamp(40), metricsTimestamp(md))
+
+	summary := metrics.AppendEmpty()
+	summary.SetDataType(pdata.MetricDataTypeSummary)
+	summary.Summary().DataPoints().AppendEmpty().SetTimestamp(50)
+	assert.Equal(t, pdata.Timestamp(50), metricsTimestamp(md))
+}
+
+func TestLogsTimestamp(t *testing.T) {
+	ld := pdata.NewLogs()
+	assert.Equal(t, pdata.Timestamp(0), logsTimestamp(ld))
+
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	logs.AppendEmpty().SetTimestamp(20)
+	logs.AppendEmpty().SetTimestamp(10)
+	assert.Equal(t, pdata.Timestamp(20), logsTimestamp(ld))
+}
+
+func TestTracesPusher_timestampFromTelemetry(t *testing.T) {
+	end := time.Date(2022, 1, 20, 10, 0, 0, 0, time.UTC)
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetEndTimestamp(pdata.NewTimestampFromTime(end))
+
+	for _, fromTelemetry := range []bool{false, true} {
+		producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		producer.ExpectSendMessageAndSucceed()
+		marshaler := &recordingTracesMarshaler{}
+		p := kafkaTracesProducer{
+			producer:               producer,
+			marshaler:              marshaler,
+			timestampFromTelemetry: fromTelemetry,
+		}
+		require.NoError(t, p.tracesPusher(context.Background(), td))
+		require.NoError(t, p.Close(context.Background()))
+
+		require.Len(t, marshaler.messages, 1)
+		if fromTelemetry {
+			assert.True(t, end.Equal(marshaler.messages[0].Timestamp))
+		} else {
+			assert.True(t, marshaler.messages[0].Timestamp.IsZero())
+		}
+	}
+}
+
+func TestTracesPusher_timestampFromTelemetryPerSpan(t *testing.T) {
+	start := time.Date(2022, 1, 20, 10, 0, 0, 0, time.UTC)
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := 1; i <= 2; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pdata.NewTraceID([16]byte{byte(i)}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{byte(i)}))
+		span.SetStartTimestamp(pdata.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pdata.NewTimestampFromTime(start.Add(time.Duration(i) * time.Second)))
+	}
+
+	messages, err := marshalTracesWithTimestamps(jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}}, td, "topic")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	// Every message has the end time of its own span.
+	assert.True(t, start.Add(time.Second).Equal(messages[0].Timestamp))
+	assert.True(t, start.Add(2*time.Second).Equal(messages[1].Timestamp))
+
+	messages, err = jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}}.Marshal(td, "topic")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.True(t, messages[0].Timestamp.IsZero())
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
+)
+
+// schemaRegistryFramer frames messages with the Confluent schema registry wire format.
+type schemaRegistryFramer struct {
+	cfg    SchemaRegistry
+	schema string
+	client *schemaregistry.Client
+
+	mu sync.Mutex
+	// schemaIDs caches the ID of the schema registered under every subject. The subjects are
+	// derived from topic and the topics of the topic_routing routes, so their number is bounded.
+	schemaIDs map[string]int
+}
+
+// newSchemaRegistryFramer returns a framer for messages of schema, or nil if the schema
+// registry is not enabled.
+func newSchemaRegistryFramer(cfg SchemaRegistry, schema string) *schemaRegistryFramer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &schemaRegistryFramer{
+		cfg:       cfg,
+		schema:    schema,
+		schemaIDs: make(map[string]int),
+	}
+}
+
+// start creates the client of the schema registry, with the auth extensions of host.
+func (f *schemaRegistryFramer) start(host component.Host) error {
+	if f == nil || f.cfg.SchemaID != 0 {
+		return nil
+	}
+	httpClient, err := f.cfg.ToClient(host.GetExtensions())
+	if err != nil {
+		return err
+	}
+	f.client = schemaregistry.NewClient(httpClient, f.cfg.Endpoint, f.cfg.Username, f.cfg.Password)
+	return nil
+}
+
+// subject returns the subject of the schema of the messages produced to topic.
+func (f *schemaRegistryFramer) subject(topic string) string {
+	if f.cfg.Subject != "" {
+		return f.cfg.Subject
+	}
+	// TopicNameStrategy, the default subject name strategy of Confluent serializers.
+	return topic + "-value"
+}
+
+// frame prepends the wire format header to the value of every message produced to topic.
+// The schema is registered under the subject of topic on first use and its ID is reused
+// afterwards, a failed registration is retried with the next batch.
+func (f *schemaRegistryFramer) frame(ctx context.Context, topic string, messages []*sarama.ProducerMessage) error {
+	schemaID, err := f.getSchemaID(ctx, f.subject(topic))
+	if err != nil {
+		return err
+	}
+	for _, message := range messages {
+		bts, err := message.Value.Encode()
+		if err != nil {
+			return err
+		}
+		message.Value = sarama.ByteEncoder(schemaregistry.Frame(schemaID, bts))
+	}
+	return nil
+}
+
+func (f *schemaRegistryFramer) getSchemaID(ctx context.Context, subject string) (int, error) {
+	if f.cfg.SchemaID != 0 {
+		return f.cfg.SchemaID, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if schemaID, ok := f.schemaIDs[subject]; ok {
+		return schemaID, nil
+	}
+	schemaID, err := f.client.Register(ctx, subject, f.schema)
+	if err != nil {
+		return 0, err
+	}
+	f.schemaIDs[subject] = schemaID
+	return schemaID, nil
+}
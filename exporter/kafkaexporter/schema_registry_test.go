@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func TestNewSchemaRegistryFramer_disabled(t *testing.T) {
+	framer := newSchemaRegistryFramer(SchemaRegistry{}, schemaregistry.TracesSchema)
+	assert.Nil(t, framer)
+	assert.NoError(t, framer.start(componenttest.NewNopHost()))
+}
+
+func TestSchemaRegistryFramer_register(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/subjects/otlp_spans-value/versions", r.URL.Path)
+		_, _ = w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	framer := newSchemaRegistryFramer(SchemaRegistry{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+		Enabled:            true,
+	}, schemaregistry.TracesSchema)
+	require.NoError(t, framer.start(componenttest.NewNopHost()))
+
+	for i := 0; i < 2; i++ {
+		messages := []*sarama.ProducerMessage{{Value: sarama.ByteEncoder{0xa}}}
+		require.NoError(t, framer.frame(context.Background(), "otlp_spans", messages))
+		assert.Equal(t, sarama.ByteEncoder{0x0, 0x0, 0x0, 0x0, 0x7, 0x0, 0xa}, messages[0].Value)
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestSchemaRegistryFramer_register_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	framer := newSchemaRegistryFramer(SchemaRegistry{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+		Enabled:            true,
+		Subject:            "spans",
+	}, schemaregistry.TracesSchema)
+	require.NoError(t, framer.start(componenttest.NewNopHost()))
+
+	messages := []*sarama.ProducerMessage{{Value: sarama.ByteEncoder{0xa}}}
+	err := framer.frame(context.Background(), "otlp_spans", messages)
+	assert.EqualError(t, err, `failed to register schema for subject "spans": unexpected status code 500`)
+	assert.Equal(t, sarama.ByteEncoder{0xa}, messages[0].Value)
+}
+
+func TestSchemaRegistryFramer_routed_topics(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path == "/subjects/tenant_a-value/versions" {
+			_, _ = w.Write([]byte(`{"id":8}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	framer := newSchemaRegistryFramer(SchemaRegistry{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: server.URL},
+		Enabled:            true,
+	}, schemaregistry.TracesSchema)
+	require.NoError(t, framer.start(componenttest.NewNopHost()))
+
+	for _, topic := range []string{"otlp_spans", "tenant_a", "otlp_spans", "tenant_a"} {
+		messages := []*sarama.ProducerMessage{{Topic: topic, Value: sarama.ByteEncoder{0xa}}}
+		require.NoError(t, framer.frame(context.Background(), topic, messages))
+		schemaID := byte(0x7)
+		if topic == "tenant_a" {
+			schemaID = 0x8
+		}
+		assert.Equal(t, sarama.ByteEncoder{0x0, 0x0, 0x0, 0x0, schemaID, 0x0, 0xa}, messages[0].Value)
+	}
+	assert.Equal(t, map[string]int{
+		"/subjects/otlp_spans-value/versions": 1,
+		"/subjects/tenant_a-value/versions":   1,
+	}, requests)
+}
+
+func TestSchemaRegistryFramer_auth_extension(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id":7}`))
+	}))
+	defer server.Close()
+
+	authID := config.NewComponentID("oauth2client")
+	framer := newSchemaRegistryFramer(SchemaRegistry{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: server.URL,
+			Auth:     &configauth.Authentication{AuthenticatorID: authID},
+		},
+		Enabled: true,
+	}, schemaregistry.TracesSchema)
+	assert.Error(t, framer.start(componenttest.NewNopHost()))
+
+	host := &extensionsHost{Host: componenttest.NewNopHost(), extensions: map[config.ComponentID]component.Extension{
+		authID: &configauth.MockClientAuthenticator{ResultRoundTripper: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("Authorization", "Bearer token")
+			return http.DefaultTransport.RoundTrip(r)
+		})},
+	}}
+	require.NoError(t, framer.start(host))
+	messages := []*sarama.ProducerMessage{{Value: sarama.ByteEncoder{0xa}}}
+	require.NoError(t, framer.frame(context.Background(), "otlp_spans", messages))
+	assert.Equal(t, sarama.ByteEncoder{0x0, 0x0, 0x0, 0x0, 0x7, 0x0, 0xa}, messages[0].Value)
+}
+
+type extensionsHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h *extensionsHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTracesPusher_schema_registry(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		assert.Equal(t, []byte{0x0, 0x0, 0x0, 0x0, 0x3, 0x0}, val[:6])
+		return nil
+	})
+
+	framer := newSchemaRegistryFramer(SchemaRegistry{Enabled: true, SchemaID: 3}, schemaregistry.TracesSchema)
+	require.NoError(t, framer.start(componenttest.NewNopHost()))
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+		framer:    framer,
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	require.NoError(t, err)
+}
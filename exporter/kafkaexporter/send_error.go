@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"errors"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// permanentErrors are the errors returned by the brokers, or by sarama before sending the
+// messages, which are bound to happen again when the same messages are sent again.
+var permanentErrors = map[sarama.KError]bool{
+	sarama.ErrInvalidMessage:                     true,
+	sarama.ErrInvalidMessageSize:                 true,
+	sarama.ErrMessageSizeTooLarge:                true,
+	sarama.ErrInvalidTopic:                       true,
+	sarama.ErrMessageSetSizeTooLarge:             true,
+	sarama.ErrInvalidRequiredAcks:                true,
+	sarama.ErrTopicAuthorizationFailed:           true,
+	sarama.ErrClusterAuthorizationFailed:         true,
+	sarama.ErrInvalidTimestamp:                   true,
+	sarama.ErrUnsupportedVersion:                 true,
+	sarama.ErrUnsupportedForMessageFormat:        true,
+	sarama.ErrTransactionalIDAuthorizationFailed: true,
+	sarama.ErrInvalidRecord:                      true,
+}
+
+// wrapSendError converts the error returned when sending messages, so that the failures which
+// can't be recovered by retrying, e.g. a message exceeding the maximum size, are marked as
+// permanent and the batch is dropped instead of being retried. A batch is dropped as soon as
+// one of its messages fails permanently, since all of its messages are sent again on retry.
+// Other failures, e.g. a partition leader election, are retryable.
+func wrapSendError(err error) error {
+	var producerErrs sarama.ProducerErrors
+	if errors.As(err, &producerErrs) && len(producerErrs) > 0 {
+		for _, producerErr := range producerErrs {
+			if isPermanentError(producerErr.Err) {
+				return consumererror.NewPermanent(kafkaErrors{len(producerErrs), producerErr.Err.Error()})
+			}
+		}
+		return kafkaErrors{len(producerErrs), producerErrs[0].Err.Error()}
+	}
+	if isPermanentError(err) {
+		return consumererror.NewPermanent(err)
+	}
+	return err
+}
+
+func isPermanentError(err error) bool {
+	var kerr sarama.KError
+	if errors.As(err, &kerr) {
+		return permanentErrors[kerr]
+	}
+	var encodingErr sarama.PacketEncodingError
+	var configErr sarama.ConfigurationError
+	return errors.As(err, &encodingErr) || errors.As(err, &configErr)
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestWrapSendError(t *testing.T) {
+	msg := &sarama.ProducerMessage{}
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		errMsg    string
+	}{
+		{
+			name:   "retryable producer errors",
+			err:    sarama.ProducerErrors{{Msg: msg, Err: sarama.ErrNotLeaderForPartition}, {Msg: msg, Err: sarama.ErrRequestTimedOut}},
+			errMsg: "Failed to deliver 2 messages due to " + sarama.ErrNotLeaderForPartition.Error(),
+		},
+		{
+			name:      "permanent producer error",
+			err:       sarama.ProducerErrors{{Msg: msg, Err: sarama.ErrNotLeaderForPartition}, {Msg: msg, Err: sarama.ErrMessageSizeTooLarge}},
+			permanent: true,
+			errMsg:    "Permanent error: Failed to deliver 2 messages due to " + sarama.ErrMessageSizeTooLarge.Error(),
+		},
+		{
+			name:      "encoding error",
+			err:       sarama.PacketEncodingError{Info: "invalid"},
+			permanent: true,
+			errMsg:    "Permanent error: kafka: error encoding packet: invalid",
+		},
+		{
+			name:   "out of brokers",
+			err:    sarama.ErrOutOfBrokers,
+			errMsg: sarama.ErrOutOfBrokers.Error(),
+		},
+		{
+			name:   "other error",
+			err:    errors.New("failed to send"),
+			errMsg: "failed to send",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := wrapSendError(test.err)
+			assert.EqualError(t, err, test.errMsg)
+			assert.Equal(t, test.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+const (
+	// signalHeader is the header recording the signal of the messages, so that traces, metrics
+	// and logs can be exported to the same topic and told apart by receivers.
+	signalHeader = "otlp_signal"
+
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+	signalLogs    = "logs"
+)
+
+// setSignalHeader sets the signal header of every message.
+func setSignalHeader(messages []*sarama.ProducerMessage, signal string) {
+	for _, message := range messages {
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(signalHeader),
+			Value: []byte(signal),
+		})
+	}
+}
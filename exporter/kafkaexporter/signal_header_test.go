@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// signalChecker fails the messages sent to a mock producer without the expected signal header,
+// or with a header if signal is empty.
+func signalChecker(signal string) mocks.MessageChecker {
+	return func(message *sarama.ProducerMessage) error {
+		if signal == "" {
+			if len(message.Headers) != 0 {
+				return fmt.Errorf("unexpected headers %v", message.Headers)
+			}
+			return nil
+		}
+		if len(message.Headers) != 1 || string(message.Headers[0].Key) != signalHeader || string(message.Headers[0].Value) != signal {
+			return fmt.Errorf("expected the %s signal header, got %v", signal, message.Headers)
+		}
+		return nil
+	}
+}
+
+func TestPushers_signalHeader(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		expected := func(signal string) mocks.MessageChecker {
+			if enabled {
+				return signalChecker(signal)
+			}
+			return signalChecker("")
+		}
+
+		tracesProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		tracesProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expected(signalTraces))
+		traces := kafkaTracesProducer{
+			producer:     tracesProducer,
+			marshaler:    newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+			signalHeader: enabled,
+		}
+		require.NoError(t, traces.tracesPusher(context.Background(), pdata.NewTraces()))
+		require.NoError(t, traces.Close(context.Background()))
+
+		metricsProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		metricsProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expected(signalMetrics))
+		metrics := kafkaMetricsProducer{
+			producer:     metricsProducer,
+			marshaler:    newPdataMetricsMarshaler(otlp.NewProtobufMetricsMarshaler(), defaultEncoding),
+			signalHeader: enabled,
+		}
+		require.NoError(t, metrics.metricsDataPusher(context.Background(), pdata.NewMetrics()))
+		require.NoError(t, metrics.Close(context.Background()))
+
+		logsProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+		logsProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expected(signalLogs))
+		logs := kafkaLogsProducer{
+			producer:     logsProducer,
+			marshaler:    newPdataLogsMarshaler(otlp.NewProtobufLogsMarshaler(), defaultEncoding),
+			signalHeader: enabled,
+		}
+		require.NoError(t, logs.logsDataPusher(context.Background(), pdata.NewLogs()))
+		require.NoError(t, logs.Close(context.Background()))
+	}
+}
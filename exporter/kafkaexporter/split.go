@@ -0,0 +1,319 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// The sizes are estimated with the OTLP protobuf encoding, whatever the encoding of the messages.
+var (
+	tracesSizer  = otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	metricsSizer = otlp.NewProtobufMetricsMarshaler().(pdata.MetricsSizer)
+	logsSizer    = otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer)
+)
+
+// batchSplitter splits the batches holding more spans, data points or log records than maxItems, or whose
+// estimated encoded size is larger than maxBytes, into parts marshaled into separate messages. The batches
+// are halved until every part fits, a single item larger than maxBytes being exported on its own. A zero
+// limit is not enforced.
+type batchSplitter struct {
+	name     string
+	maxItems int
+	maxBytes int
+}
+
+func newBatchSplitter(name string, cfg BatchSplit) batchSplitter {
+	return batchSplitter{
+		name:     name,
+		maxItems: cfg.MaxItems,
+		maxBytes: cfg.MaxBytes,
+	}
+}
+
+// exceeds reports whether a batch of count items has to be split, computing its size only if needed.
+func (s batchSplitter) exceeds(count int, size func() int) bool {
+	if s.maxItems > 0 && count > s.maxItems {
+		return true
+	}
+	return s.maxBytes > 0 && count > 1 && size() > s.maxBytes
+}
+
+// record reports the split of a batch into the given number of parts.
+func (s batchSplitter) record(ctx context.Context, parts int) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagInstanceName, s.name)},
+		statSplitBatches.M(1), statSplitMessages.M(int64(parts)))
+}
+
+// splitTraces returns the parts of the traces. The traces are not modified, they are copied before being split.
+func (s batchSplitter) splitTraces(ctx context.Context, td pdata.Traces) []pdata.Traces {
+	if !s.exceeds(td.SpanCount(), func() int { return tracesSizer.TracesSize(td) }) {
+		return []pdata.Traces{td}
+	}
+	td = td.Clone()
+	var parts []pdata.Traces
+	for s.maxItems > 0 && td.SpanCount() > s.maxItems {
+		parts = s.splitTracesBySize(moveSpans(td, s.maxItems), parts)
+	}
+	parts = s.splitTracesBySize(td, parts)
+	s.record(ctx, len(parts))
+	return parts
+}
+
+func (s batchSplitter) splitTracesBySize(td pdata.Traces, parts []pdata.Traces) []pdata.Traces {
+	count := td.SpanCount()
+	if s.maxBytes == 0 || count <= 1 || tracesSizer.TracesSize(td) <= s.maxBytes {
+		return append(parts, td)
+	}
+	parts = s.splitTracesBySize(moveSpans(td, count/2), parts)
+	return s.splitTracesBySize(td, parts)
+}
+
+// splitMetrics returns the parts of the metrics. The metrics are not modified, they are copied before being split.
+func (s batchSplitter) splitMetrics(ctx context.Context, md pdata.Metrics) []pdata.Metrics {
+	if !s.exceeds(md.DataPointCount(), func() int { return metricsSizer.MetricsSize(md) }) {
+		return []pdata.Metrics{md}
+	}
+	md = md.Clone()
+	var parts []pdata.Metrics
+	for s.maxItems > 0 && md.DataPointCount() > s.maxItems {
+		parts = s.splitMetricsBySize(moveDataPoints(md, s.maxItems), parts)
+	}
+	parts = s.splitMetricsBySize(md, parts)
+	s.record(ctx, len(parts))
+	return parts
+}
+
+func (s batchSplitter) splitMetricsBySize(md pdata.Metrics, parts []pdata.Metrics) []pdata.Metrics {
+	count := md.DataPointCount()
+	if s.maxBytes == 0 || count <= 1 || metricsSizer.MetricsSize(md) <= s.maxBytes {
+		return append(parts, md)
+	}
+	parts = s.splitMetricsBySize(moveDataPoints(md, count/2), parts)
+	return s.splitMetricsBySize(md, parts)
+}
+
+// splitLogs returns the parts of the logs. The logs are not modified, they are copied before being split.
+func (s batchSplitter) splitLogs(ctx context.Context, ld pdata.Logs) []pdata.Logs {
+	if !s.exceeds(ld.LogRecordCount(), func() int { return logsSizer.LogsSize(ld) }) {
+		return []pdata.Logs{ld}
+	}
+	ld = ld.Clone()
+	var parts []pdata.Logs
+	for s.maxItems > 0 && ld.LogRecordCount() > s.maxItems {
+		parts = s.splitLogsBySize(moveLogRecords(ld, s.maxItems), parts)
+	}
+	parts = s.splitLogsBySize(ld, parts)
+	s.record(ctx, len(parts))
+	return parts
+}
+
+func (s batchSplitter) splitLogsBySize(ld pdata.Logs, parts []pdata.Logs) []pdata.Logs {
+	count := ld.LogRecordCount()
+	if s.maxBytes == 0 || count <= 1 || logsSizer.LogsSize(ld) <= s.maxBytes {
+		return append(parts, ld)
+	}
+	parts = s.splitLogsBySize(moveLogRecords(ld, count/2), parts)
+	return s.splitLogsBySize(ld, parts)
+}
+
+// moveSpans moves the first n spans of the traces to new traces, along with their resource and
+// instrumentation library.
+func moveSpans(from pdata.Traces, n int) pdata.Traces {
+	to := pdata.NewTraces()
+	from.ResourceSpans().RemoveIf(func(rs pdata.ResourceSpans) bool {
+		if n == 0 {
+			return false
+		}
+		toRS := to.ResourceSpans().AppendEmpty()
+		rs.Resource().CopyTo(toRS.Resource())
+		toRS.SetSchemaUrl(rs.SchemaUrl())
+		rs.InstrumentationLibrarySpans().RemoveIf(func(ils pdata.InstrumentationLibrarySpans) bool {
+			if n == 0 {
+				return false
+			}
+			toILS := toRS.InstrumentationLibrarySpans().AppendEmpty()
+			ils.InstrumentationLibrary().CopyTo(toILS.InstrumentationLibrary())
+			toILS.SetSchemaUrl(ils.SchemaUrl())
+			if ils.Spans().Len() <= n {
+				n -= ils.Spans().Len()
+				ils.Spans().MoveAndAppendTo(toILS.Spans())
+				return true
+			}
+			ils.Spans().RemoveIf(func(span pdata.Span) bool {
+				if n == 0 {
+					return false
+				}
+				span.CopyTo(toILS.Spans().AppendEmpty())
+				n--
+				return true
+			})
+			return false
+		})
+		return rs.InstrumentationLibrarySpans().Len() == 0
+	})
+	return to
+}
+
+// moveLogRecords moves the first n log records of the logs to new logs, along with their resource and
+// instrumentation library.
+func moveLogRecords(from pdata.Logs, n int) pdata.Logs {
+	to := pdata.NewLogs()
+	from.ResourceLogs().RemoveIf(func(rl pdata.ResourceLogs) bool {
+		if n == 0 {
+			return false
+		}
+		toRL := to.ResourceLogs().AppendEmpty()
+		rl.Resource().CopyTo(toRL.Resource())
+		toRL.SetSchemaUrl(rl.SchemaUrl())
+		rl.InstrumentationLibraryLogs().RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			if n == 0 {
+				return false
+			}
+			toILL := toRL.InstrumentationLibraryLogs().AppendEmpty()
+			ill.InstrumentationLibrary().CopyTo(toILL.InstrumentationLibrary())
+			toILL.SetSchemaUrl(ill.SchemaUrl())
+			if ill.Logs().Len() <= n {
+				n -= ill.Logs().Len()
+				ill.Logs().MoveAndAppendTo(toILL.Logs())
+				return true
+			}
+			ill.Logs().RemoveIf(func(lr pdata.LogRecord) bool {
+				if n == 0 {
+					return false
+				}
+				lr.CopyTo(toILL.Logs().AppendEmpty())
+				n--
+				return true
+			})
+			return false
+		})
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+	return to
+}
+
+// moveDataPoints moves the first n data points of the metrics to new metrics, along with their metric,
+// resource and instrumentation library. The data points of a metric may end up in several parts.
+func moveDataPoints(from pdata.Metrics, n int) pdata.Metrics {
+	to := pdata.NewMetrics()
+	from.ResourceMetrics().RemoveIf(func(rm pdata.ResourceMetrics) bool {
+		if n == 0 {
+			return false
+		}
+		toRM := to.ResourceMetrics().AppendEmpty()
+		rm.Resource().CopyTo(toRM.Resource())
+		toRM.SetSchemaUrl(rm.SchemaUrl())
+		rm.InstrumentationLibraryMetrics().RemoveIf(func(ilm pdata.InstrumentationLibraryMetrics) bool {
+			if n == 0 {
+				return false
+			}
+			toILM := toRM.InstrumentationLibraryMetrics().AppendEmpty()
+			ilm.InstrumentationLibrary().CopyTo(toILM.InstrumentationLibrary())
+			toILM.SetSchemaUrl(ilm.SchemaUrl())
+			ilm.Metrics().RemoveIf(func(m pdata.Metric) bool {
+				if n == 0 {
+					return false
+				}
+				if count := dataPointCount(m); count <= n {
+					n -= count
+					m.CopyTo(toILM.Metrics().AppendEmpty())
+					return true
+				}
+				moveMetricDataPoints(m, toILM.Metrics().AppendEmpty(), n)
+				n = 0
+				return false
+			})
+			return ilm.Metrics().Len() == 0
+		})
+		return rm.InstrumentationLibraryMetrics().Len() == 0
+	})
+	return to
+}
+
+func dataPointCount(m pdata.Metric) int {
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pdata.MetricDataTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pdata.MetricDataTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pdata.MetricDataTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pdata.MetricDataTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+// moveMetricDataPoints moves the first n data points of the metric, which has more, to the empty metric.
+func moveMetricDataPoints(from, to pdata.Metric, n int) {
+	to.SetName(from.Name())
+	to.SetDescription(from.Description())
+	to.SetUnit(from.Unit())
+	to.SetDataType(from.DataType())
+	switch from.DataType() {
+	case pdata.MetricDataTypeGauge:
+		from.Gauge().DataPoints().RemoveIf(func(dp pdata.NumberDataPoint) bool {
+			n--
+			if n >= 0 {
+				dp.CopyTo(to.Gauge().DataPoints().AppendEmpty())
+			}
+			return n >= 0
+		})
+	case pdata.MetricDataTypeSum:
+		to.Sum().SetAggregationTemporality(from.Sum().AggregationTemporality())
+		to.Sum().SetIsMonotonic(from.Sum().IsMonotonic())
+		from.Sum().DataPoints().RemoveIf(func(dp pdata.NumberDataPoint) bool {
+			n--
+			if n >= 0 {
+				dp.CopyTo(to.Sum().DataPoints().AppendEmpty())
+			}
+			return n >= 0
+		})
+	case pdata.MetricDataTypeHistogram:
+		to.Histogram().SetAggregationTemporality(from.Histogram().AggregationTemporality())
+		from.Histogram().DataPoints().RemoveIf(func(dp pdata.HistogramDataPoint) bool {
+			n--
+			if n >= 0 {
+				dp.CopyTo(to.Histogram().DataPoints().AppendEmpty())
+			}
+			return n >= 0
+		})
+	case pdata.MetricDataTypeExponentialHistogram:
+		to.ExponentialHistogram().SetAggregationTemporality(from.ExponentialHistogram().AggregationTemporality())
+		from.ExponentialHistogram().DataPoints().RemoveIf(func(dp pdata.ExponentialHistogramDataPoint) bool {
+			n--
+			if n >= 0 {
+				dp.CopyTo(to.ExponentialHistogram().DataPoints().AppendEmpty())
+			}
+			return n >= 0
+		})
+	case pdata.MetricDataTypeSummary:
+		from.Summary().DataPoints().RemoveIf(func(dp pdata.SummaryDataPoint) bool {
+			n--
+			if n >= 0 {
+				dp.CopyTo(to.Summary().DataPoints().AppendEmpty())
+			}
+			return n >= 0
+		})
+	}
+}
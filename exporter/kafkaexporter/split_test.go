@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func TestSplitTraces(t *testing.T) {
+	td := testdata.GenerateTracesManySpansSameResource(10)
+	spanSize := tracesSizer.TracesSize(testdata.GenerateTracesManySpansSameResource(2)) - tracesSizer.TracesSize(testdata.GenerateTracesManySpansSameResource(1))
+	tests := []struct {
+		name     string
+		splitter batchSplitter
+		want     []int
+	}{
+		{name: "disabled", want: []int{10}},
+		{name: "fitting", splitter: batchSplitter{maxItems: 10, maxBytes: tracesSizer.TracesSize(td)}, want: []int{10}},
+		{name: "max items", splitter: batchSplitter{maxItems: 4}, want: []int{4, 4, 2}},
+		{name: "max bytes", splitter: batchSplitter{maxBytes: tracesSizer.TracesSize(td) - 1}, want: []int{5, 5}},
+		{name: "max items and bytes", splitter: batchSplitter{maxItems: 8, maxBytes: tracesSizer.TracesSize(td) / 2}, want: []int{4, 4, 2}},
+		{name: "max bytes below span size", splitter: batchSplitter{maxBytes: spanSize / 2}, want: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			parts := tt.splitter.splitTraces(context.Background(), td)
+			var got []int
+			for i, part := range parts {
+				got = append(got, part.SpanCount())
+				rs := part.ResourceSpans().At(0)
+				assert.Equal(t, td.ResourceSpans().At(0).Resource(), rs.Resource())
+				// The spans keep their order.
+				assert.Equal(t, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(sum(got[:i])),
+					rs.InstrumentationLibrarySpans().At(0).Spans().At(0))
+				if tt.splitter.maxBytes > 0 && part.SpanCount() > 1 {
+					assert.LessOrEqual(t, tracesSizer.TracesSize(part), tt.splitter.maxBytes)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, testdata.GenerateTracesManySpansSameResource(10), td)
+		})
+	}
+}
+
+func TestSplitTracesResources(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+	parts := batchSplitter{maxItems: 2}.splitTraces(context.Background(), td)
+	require.Len(t, parts, 2)
+	for i, part := range parts {
+		require.Equal(t, 1, part.ResourceSpans().Len())
+		assert.Equal(t, td.ResourceSpans().At(i), part.ResourceSpans().At(0))
+	}
+
+	parts = batchSplitter{maxItems: 1}.splitTraces(context.Background(), td)
+	require.Len(t, parts, 3)
+	for i, part := range parts {
+		require.Equal(t, 1, part.SpanCount())
+		assert.Equal(t, td.ResourceSpans().At(i/2).Resource(), part.ResourceSpans().At(0).Resource())
+	}
+}
+
+func TestSplitMetrics(t *testing.T) {
+	md := testdata.GenerateMetricsOneCounterOneSummaryMetrics()
+	parts := batchSplitter{maxItems: 3}.splitMetrics(context.Background(), md)
+	require.Len(t, parts, 2)
+	assert.Equal(t, 3, parts[0].DataPointCount())
+	assert.Equal(t, 1, parts[1].DataPointCount())
+
+	// The data points of the summary are split across the parts, along with the metadata of the metric.
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	first := parts[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	second := parts[1].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, first.Len())
+	require.Equal(t, 1, second.Len())
+	assert.Equal(t, metrics.At(0), first.At(0))
+	for _, part := range []pdata.Metric{first.At(1), second.At(0)} {
+		assert.Equal(t, metrics.At(1).Name(), part.Name())
+		assert.Equal(t, pdata.MetricDataTypeSummary, part.DataType())
+		assert.Equal(t, 1, part.Summary().DataPoints().Len())
+	}
+	assert.Equal(t, metrics.At(1).Summary().DataPoints().At(0), first.At(1).Summary().DataPoints().At(0))
+	assert.Equal(t, metrics.At(1).Summary().DataPoints().At(1), second.At(0).Summary().DataPoints().At(0))
+	assert.Equal(t, testdata.GenerateMetricsOneCounterOneSummaryMetrics(), md)
+}
+
+func TestSplitMetricsMaxBytes(t *testing.T) {
+	md := testdata.GenerateMetricsManyMetricsSameResource(20)
+	maxBytes := metricsSizer.MetricsSize(md) / 3
+	parts := batchSplitter{maxBytes: maxBytes}.splitMetrics(context.Background(), md)
+	require.Len(t, parts, 4)
+	count := 0
+	for _, part := range parts {
+		assert.LessOrEqual(t, metricsSizer.MetricsSize(part), maxBytes)
+		count += part.DataPointCount()
+	}
+	assert.Equal(t, md.DataPointCount(), count)
+}
+
+func TestSplitLogs(t *testing.T) {
+	ld := testdata.GenerateLogsManyLogRecordsSameResource(5)
+	parts := batchSplitter{maxItems: 2}.splitLogs(context.Background(), ld)
+	require.Len(t, parts, 3)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	for i, part := range parts {
+		require.Equal(t, 1, part.ResourceLogs().Len())
+		assert.Equal(t, ld.ResourceLogs().At(0).Resource(), part.ResourceLogs().At(0).Resource())
+		partLogs := part.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for j := 0; j < partLogs.Len(); j++ {
+			assert.Equal(t, logs.At(2*i+j), partLogs.At(j))
+		}
+	}
+	assert.Equal(t, 1, parts[2].LogRecordCount())
+
+	parts = batchSplitter{maxBytes: logsSizer.LogsSize(ld) - 1}.splitLogs(context.Background(), ld)
+	require.Len(t, parts, 2)
+	assert.Equal(t, 2, parts[0].LogRecordCount())
+	assert.Equal(t, 3, parts[1].LogRecordCount())
+	assert.Equal(t, testdata.GenerateLogsManyLogRecordsSameResource(5), ld)
+}
+
+func TestTracesPusherSplit(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	for i := 0; i < 3; i++ {
+		producer.ExpectSendMessageAndSucceed()
+	}
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+		splitter:  newBatchSplitter("kafka/test", BatchSplit{MaxItems: 2}),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.tracesPusher(context.Background(), testdata.GenerateTracesManySpansSameResource(5)))
+
+	rows, err := view.RetrieveData(statSplitBatches.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+	assert.Equal(t, "kafka/test", rows[0].Tags[0].Value)
+	rows, err = view.RetrieveData(statSplitMessages.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(3), rows[0].Data.(*view.SumData).Value)
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
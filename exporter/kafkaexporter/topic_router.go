@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// topicRouter selects the topic of a request from a value in its context.
+// The zero value routes every request to the default topic.
+type topicRouter struct {
+	fromContext string
+	routes      []topicRoute
+}
+
+type topicRoute struct {
+	value string
+	regex *regexp.Regexp
+	topic string
+}
+
+// newTopicRouter creates a router for the routes of a single signal.
+func newTopicRouter(cfg TopicRouting, routes []TopicRoute) (topicRouter, error) {
+	router := topicRouter{fromContext: strings.ToLower(cfg.FromContext)}
+	for _, route := range routes {
+		tr := topicRoute{value: route.Value, topic: route.Topic}
+		if route.Regex != "" {
+			regex, err := regexp.Compile(route.Regex)
+			if err != nil {
+				return topicRouter{}, err
+			}
+			tr.regex = regex
+		}
+		router.routes = append(router.routes, tr)
+	}
+	return router, nil
+}
+
+// topic returns the topic of the first route matching the context value, or
+// defaultTopic if no route matches.
+func (r topicRouter) topic(ctx context.Context, defaultTopic string) string {
+	if len(r.routes) == 0 {
+		return defaultTopic
+	}
+	value, ok := r.extractFromContext(ctx)
+	if !ok {
+		return defaultTopic
+	}
+	for _, route := range r.routes {
+		if route.regex != nil {
+			if route.regex.MatchString(value) {
+				return route.topic
+			}
+		} else if route.value == value {
+			return route.topic
+		}
+	}
+	return defaultTopic
+}
+
+func (r topicRouter) extractFromContext(ctx context.Context) (string, bool) {
+	// The value is looked up in the metadata of requests that have gone through
+	// a gRPC server, the context of HTTP requests doesn't carry their headers.
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md[r.fromContext]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
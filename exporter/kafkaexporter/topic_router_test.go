@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func TestTopicRouter(t *testing.T) {
+	router, err := newTopicRouter(TopicRouting{FromContext: "X-Tenant"}, []TopicRoute{
+		{Value: "acme", Topic: "acme_spans"},
+		{Regex: "^team-.*", Topic: "team_spans"},
+		{Regex: ".*", Topic: "catch_all_spans"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		topic string
+	}{
+		{
+			name:  "no metadata",
+			ctx:   context.Background(),
+			topic: "otlp_spans",
+		},
+		{
+			name:  "missing key",
+			ctx:   metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-other", "acme")),
+			topic: "otlp_spans",
+		},
+		{
+			name:  "static value",
+			ctx:   metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme")),
+			topic: "acme_spans",
+		},
+		{
+			name:  "regex",
+			ctx:   metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "team-a")),
+			topic: "team_spans",
+		},
+		{
+			name:  "first route wins",
+			ctx:   metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "other")),
+			topic: "catch_all_spans",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.topic, router.topic(test.ctx, "otlp_spans"))
+		})
+	}
+}
+
+func TestTopicRouterNoRoutes(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))
+	assert.Equal(t, "otlp_spans", topicRouter{}.topic(ctx, "otlp_spans"))
+}
+
+func TestTopicRouterInvalidRegex(t *testing.T) {
+	_, err := newTopicRouter(TopicRouting{FromContext: "x-tenant"}, []TopicRoute{{Regex: "(", Topic: "spans"}})
+	assert.Error(t, err)
+}
+
+func TestTracesPusherTopicRouting(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+
+	router, err := newTopicRouter(TopicRouting{FromContext: "x-tenant"}, []TopicRoute{{Value: "acme", Topic: "acme_spans"}})
+	require.NoError(t, err)
+	marshaler := &topicRecordingMarshaler{}
+	p := kafkaTracesProducer{
+		producer:  producer,
+		topic:     "otlp_spans",
+		router:    router,
+		marshaler: marshaler,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))
+	require.NoError(t, p.tracesPusher(ctx, testdata.GenerateTracesTwoSpansSameResource()))
+	assert.Equal(t, "acme_spans", marshaler.topic)
+}
+
+type topicRecordingMarshaler struct {
+	topic string
+}
+
+func (m *topicRecordingMarshaler) Marshal(_ pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	m.topic = topic
+	return []*sarama.ProducerMessage{{Topic: topic, Value: sarama.ByteEncoder{}}}, nil
+}
+
+func (m *topicRecordingMarshaler) Encoding() string {
+	return defaultEncoding
+}
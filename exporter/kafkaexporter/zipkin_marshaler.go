@@ -0,0 +1,87 @@
+// Copyright 2020 The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/zipkin/zipkinv2"
+)
+
+const (
+	zipkinProtobufEncoding = "zipkin_proto"
+	zipkinJSONEncoding     = "zipkin_json"
+)
+
+// zipkinMarshaler serializes the spans of every trace to a Zipkin v2 list of spans, keyed by trace ID,
+// as consumed by the Zipkin Kafka collector.
+type zipkinMarshaler struct {
+	marshaler pdata.TracesMarshaler
+	encoding  string
+}
+
+var _ TracesMarshaler = (*zipkinMarshaler)(nil)
+
+func newZipkinProtobufMarshaler() zipkinMarshaler {
+	return zipkinMarshaler{marshaler: zipkinv2.NewProtobufTracesMarshaler(), encoding: zipkinProtobufEncoding}
+}
+
+func newZipkinJSONMarshaler() zipkinMarshaler {
+	return zipkinMarshaler{marshaler: zipkinv2.NewJSONTracesMarshaler(), encoding: zipkinJSONEncoding}
+}
+
+func (z zipkinMarshaler) Marshal(traces pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	var errs error
+	for _, trace := range groupByTrace(traces) {
+		bts, err := z.marshaler.MarshalTraces(trace)
+		// continue to process traces that can be serialized
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		key := trace.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID().HexString()
+		messages = append(messages, &sarama.ProducerMessage{
+			Topic: topic,
+			Value: sarama.ByteEncoder(bts),
+			Key:   sarama.ByteEncoder(key),
+		})
+	}
+	return messages, errs
+}
+
+func (z zipkinMarshaler) Encoding() string {
+	return z.encoding
+}
+
+// groupByTrace returns one pdata.Traces for each trace of traces, in the order of their first span.
+func groupByTrace(traces pdata.Traces) []pdata.Traces {
+	var result []pdata.Traces
+	byTraceID := map[pdata.TraceID]pdata.Traces{}
+	for _, batch := range batchpersignal.SplitTraces(traces) {
+		traceID := batch.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+		trace, ok := byTraceID[traceID]
+		if !ok {
+			byTraceID[traceID] = batch
+			result = append(result, batch)
+			continue
+		}
+		batch.ResourceSpans().MoveAndAppendTo(trace.ResourceSpans())
+	}
+	return result
+}
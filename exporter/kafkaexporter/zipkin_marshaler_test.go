@@ -0,0 +1,81 @@
+// Copyright 2020 The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/zipkin/zipkinv2"
+)
+
+func TestZipkinMarshaler(t *testing.T) {
+	traceID1 := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	traceID2 := pdata.NewTraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+
+	td := pdata.NewTraces()
+	for i, traceID := range []pdata.TraceID{traceID1, traceID2, traceID1} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", "foo")
+		span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName("bar")
+		span.SetStartTimestamp(pdata.Timestamp(10))
+		span.SetEndTimestamp(pdata.Timestamp(20))
+		span.SetTraceID(traceID)
+		span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+
+	tests := []struct {
+		marshaler   TracesMarshaler
+		unmarshaler pdata.TracesUnmarshaler
+		encoding    string
+	}{
+		{
+			marshaler:   newZipkinProtobufMarshaler(),
+			unmarshaler: zipkinv2.NewProtobufTracesUnmarshaler(false, false),
+			encoding:    "zipkin_proto",
+		},
+		{
+			marshaler:   newZipkinJSONMarshaler(),
+			unmarshaler: zipkinv2.NewJSONTracesUnmarshaler(false),
+			encoding:    "zipkin_json",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			messages, err := test.marshaler.Marshal(td, "topic")
+			require.NoError(t, err)
+			assert.Equal(t, test.encoding, test.marshaler.Encoding())
+
+			require.Len(t, messages, 2)
+			assert.Equal(t, sarama.ByteEncoder(traceID1.HexString()), messages[0].Key)
+			assert.Equal(t, sarama.ByteEncoder(traceID2.HexString()), messages[1].Key)
+
+			wantSpans := []int{2, 1}
+			for i, message := range messages {
+				assert.Equal(t, "topic", message.Topic)
+				bts, err := message.Value.Encode()
+				require.NoError(t, err)
+				trace, err := test.unmarshaler.UnmarshalTraces(bts)
+				require.NoError(t, err)
+				assert.Equal(t, wantSpans[i], trace.SpanCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	zstdEncoding = "otlp_proto_zstd"
+
+	contentEncodingHeader = "content-encoding"
+	contentEncodingZstd   = "zstd"
+)
+
+// zstdEncoder is safe for concurrent use with EncodeAll, creating it without options can't fail.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// compress compresses the value of every message with zstd, and sets the content-encoding
+// header of the messages so that consumers know how to decompress them.
+func compress(messages []*sarama.ProducerMessage) error {
+	for _, message := range messages {
+		bts, err := message.Value.Encode()
+		if err != nil {
+			return err
+		}
+		message.Value = sarama.ByteEncoder(zstdEncoder.EncodeAll(bts, nil))
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(contentEncodingHeader),
+			Value: []byte(contentEncodingZstd),
+		})
+	}
+	return nil
+}
+
+// zstdTracesMarshaler compresses the messages of the otlp_proto encoding with zstd.
+type zstdTracesMarshaler struct {
+	otlpProto TracesMarshaler
+}
+
+var _ TracesMarshaler = (*zstdTracesMarshaler)(nil)
+
+func (z zstdTracesMarshaler) Marshal(td pdata.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	messages, err := z.otlpProto.Marshal(td, topic)
+	if err != nil {
+		return nil, err
+	}
+	return messages, compress(messages)
+}
+
+func (z zstdTracesMarshaler) Encoding() string {
+	return zstdEncoding
+}
+
+// zstdMetricsMarshaler compresses the messages of the otlp_proto encoding with zstd.
+type zstdMetricsMarshaler struct {
+	otlpProto MetricsMarshaler
+}
+
+var _ MetricsMarshaler = (*zstdMetricsMarshaler)(nil)
+
+func (z zstdMetricsMarshaler) Marshal(md pdata.Metrics, topic string) ([]*sarama.ProducerMessage, error) {
+	messages, err := z.otlpProto.Marshal(md, topic)
+	if err != nil {
+		return nil, err
+	}
+	return messages, compress(messages)
+}
+
+func (z zstdMetricsMarshaler) Encoding() string {
+	return zstdEncoding
+}
+
+// zstdLogsMarshaler compresses the messages of the otlp_proto encoding with zstd.
+type zstdLogsMarshaler struct {
+	otlpProto LogsMarshaler
+}
+
+var _ LogsMarshaler = (*zstdLogsMarshaler)(nil)
+
+func (z zstdLogsMarshaler) Marshal(ld pdata.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	messages, err := z.otlpProto.Marshal(ld, topic)
+	if err != nil {
+		return nil, err
+	}
+	return messages, compress(messages)
+}
+
+func (z zstdLogsMarshaler) Encoding() string {
+	return zstdEncoding
+}
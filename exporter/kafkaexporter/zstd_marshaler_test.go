@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func decompressMessage(t *testing.T, message *sarama.ProducerMessage) []byte {
+	require.Len(t, message.Headers, 1)
+	assert.Equal(t, "content-encoding", string(message.Headers[0].Key))
+	assert.Equal(t, "zstd", string(message.Headers[0].Value))
+
+	bts, err := message.Value.Encode()
+	require.NoError(t, err)
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	defer decoder.Close()
+	decompressed, err := decoder.DecodeAll(bts, nil)
+	require.NoError(t, err)
+	return decompressed
+}
+
+func TestZstdTracesMarshaler(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("foo")
+
+	marshaler := tracesMarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, marshaler.Encoding())
+	messages, err := marshaler.Marshal(td, "otlp_spans")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "otlp_spans", messages[0].Topic)
+
+	got, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(decompressMessage(t, messages[0]))
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestZstdMetricsMarshaler(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("foo")
+
+	marshaler := metricsMarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, marshaler.Encoding())
+	messages, err := marshaler.Marshal(md, "otlp_metrics")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	got, err := otlp.NewProtobufMetricsUnmarshaler().UnmarshalMetrics(decompressMessage(t, messages[0]))
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+}
+
+func TestZstdLogsMarshaler(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty().SetName("foo")
+
+	marshaler := logsMarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, marshaler.Encoding())
+	messages, err := marshaler.Marshal(ld, "otlp_logs")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	got, err := otlp.NewProtobufLogsUnmarshaler().UnmarshalLogs(decompressMessage(t, messages[0]))
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+}
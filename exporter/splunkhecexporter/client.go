@@ -70,6 +70,9 @@ func (c *client) pushMetricsData(
 	if len(splunkDataPoints) == 0 {
 		return nil
 	}
+	if c.config.UseMultiMetricFormat {
+		splunkDataPoints = mergeEventsToMultiMetricFormat(splunkDataPoints)
+	}
 
 	body, compressed, err := encodeBodyEvents(&c.zippers, splunkDataPoints, c.config.DisableCompression)
 	if err != nil {
@@ -17,12 +17,14 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"sync"
 	"testing"
@@ -446,6 +448,56 @@ func TestReceiveMetrics(t *testing.T) {
 	assert.Contains(t, actual, "\"time\":2.002")
 }
 
+func TestReceiveMetricsWithMultiMetricFormat(t *testing.T) {
+	var events []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		for decoder.More() {
+			var event json.RawMessage
+			require.NoError(t, decoder.Decode(&event))
+			events = append(events, string(event))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.DisableCompression = true
+	cfg.UseMultiMetricFormat = true
+	c := client{
+		url:    serverURL,
+		client: server.Client(),
+		zippers: sync.Pool{New: func() interface{} {
+			return gzip.NewWriter(nil)
+		}},
+		config: cfg,
+		logger: zaptest.NewLogger(t),
+	}
+
+	md := pdata.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+	ts := pdata.NewTimestampFromTime(time.Unix(1, 0))
+	for _, name := range []string{"cpu", "mem", "disk"} {
+		metric := metrics.AppendEmpty()
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeGauge)
+		dp := metric.Gauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetIntVal(1)
+		dp.Attributes().InsertString("k0", "v0")
+	}
+
+	require.NoError(t, c.pushMetricsData(context.Background(), md))
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], `"metric_name:cpu":1`)
+	assert.Contains(t, events[0], `"metric_name:mem":1`)
+	assert.Contains(t, events[0], `"metric_name:disk":1`)
+}
+
 func TestReceiveTracesWithCompression(t *testing.T) {
 	request, err := runTraceExport(false, 1000, t)
 	assert.NoError(t, err)
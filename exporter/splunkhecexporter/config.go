@@ -75,6 +75,10 @@ type Config struct {
 	// Maximum log data size in bytes per HTTP post. Defaults to the backend limit of 2097152 bytes (2MiB).
 	MaxContentLengthLogs uint `mapstructure:"max_content_length_logs"`
 
+	// UseMultiMetricFormat merges the metric data points sharing their timestamp, metadata and dimensions into single
+	// events in the multiple-metric format, supported by Splunk 8.0+. Defaults to false.
+	UseMultiMetricFormat bool `mapstructure:"use_multi_metric_format"`
+
 	// TLSSetting struct exposes TLS client configuration.
 	TLSSetting configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 
@@ -63,6 +63,7 @@ func TestLoadConfig(t *testing.T) {
 		SplunkAppVersion:     "v0.0.1",
 		MaxConnections:       100,
 		MaxContentLengthLogs: 2 * 1024 * 1024,
+		UseMultiMetricFormat: true,
 		TimeoutSettings: exporterhelper.TimeoutSettings{
 			Timeout: 10 * time.Second,
 		},
@@ -15,8 +15,11 @@
 package splunkhecexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter"
 
 import (
+	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
@@ -231,6 +234,69 @@ func metricDataToSplunk(logger *zap.Logger, data pdata.Metrics, config *Config)
 	return splunkMetrics, numDroppedTimeSeries
 }
 
+// mergeEventsToMultiMetricFormat merges the metric events sharing their time, host, source, source type, index and
+// dimensions into single events holding the values of all their metrics, in the multiple-metric format of HEC:
+// https://docs.splunk.com/Documentation/Splunk/latest/Metrics/GetMetricsInOther#The_multiple-metric_JSON_format
+// A merged event takes the place of the first event merged into it. An event holding the value of a metric
+// already present in the event it would be merged into is kept as is, and the next events are merged into it.
+func mergeEventsToMultiMetricFormat(events []*splunk.Event) []*splunk.Event {
+	merged := make([]*splunk.Event, 0, len(events))
+	byKey := make(map[string]*splunk.Event, len(events))
+	var dims []string
+	var key strings.Builder
+	for _, event := range events {
+		dims = dims[:0]
+		for k := range event.Fields {
+			if !isMetricValueField(k) {
+				dims = append(dims, k)
+			}
+		}
+		sort.Strings(dims)
+
+		key.Reset()
+		if event.Time != nil {
+			key.WriteString(strconv.FormatFloat(*event.Time, 'f', -1, 64))
+		}
+		for _, part := range []string{event.Host, event.Source, event.SourceType, event.Index} {
+			key.WriteByte('\xff')
+			key.WriteString(part)
+		}
+		for _, dim := range dims {
+			key.WriteByte('\xff')
+			key.WriteString(dim)
+			key.WriteByte('=')
+			fmt.Fprint(&key, event.Fields[dim])
+		}
+
+		target, ok := byKey[key.String()]
+		if ok && !hasMetricValueOf(target, event) {
+			for k, v := range event.Fields {
+				if isMetricValueField(k) {
+					target.Fields[k] = v
+				}
+			}
+			continue
+		}
+		byKey[key.String()] = event
+		merged = append(merged, event)
+	}
+	return merged
+}
+
+func isMetricValueField(k string) bool {
+	return strings.HasPrefix(k, splunkMetricValue+":")
+}
+
+// hasMetricValueOf returns true if target already holds the value of one of the metrics of event.
+func hasMetricValueOf(target *splunk.Event, event *splunk.Event) bool {
+	for k := range event.Fields {
+		if _, ok := target.Fields[k]; ok && isMetricValueField(k) {
+			return true
+		}
+	}
+	return false
+}
+
 func createEvent(timestamp pdata.Timestamp, host string, source string, sourceType string, index string, fields map[string]interface{}) *splunk.Event {
 	return &splunk.Event{
 		Time:       timestampToSecondsWithMillisecondPrecision(timestamp),
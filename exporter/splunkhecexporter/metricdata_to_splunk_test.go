@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 
@@ -619,6 +620,127 @@ func Test_metricDataToSplunk(t *testing.T) {
 	}
 }
 
+func Test_mergeEventsToMultiMetricFormat(t *testing.T) {
+	ts1 := timestampToSecondsWithMillisecondPrecision(pdata.NewTimestampFromTime(time.Unix(1574092046, 0)))
+	ts2 := timestampToSecondsWithMillisecondPrecision(pdata.NewTimestampFromTime(time.Unix(1574092047, 0)))
+	keys := []string{"k0", "metric_type"}
+
+	tests := []struct {
+		name   string
+		events []*splunk.Event
+		want   []*splunk.Event
+	}{
+		{
+			name:   "empty",
+			events: []*splunk.Event{},
+			want:   []*splunk.Event{},
+		},
+		{
+			name: "same_dimensions",
+			events: []*splunk.Event{
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 1.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, int64(2), "src", "st", "idx", "host"),
+				commonSplunkMetric("disk", ts1, keys, []interface{}{"v0", "Gauge"}, int64(3), "src", "st", "idx", "host"),
+			},
+			want: []*splunk.Event{
+				{
+					Time:       ts1,
+					Source:     "src",
+					SourceType: "st",
+					Index:      "idx",
+					Host:       "host",
+					Event:      "metric",
+					Fields: map[string]interface{}{
+						"k0":               "v0",
+						"metric_type":      "Gauge",
+						"metric_name:cpu":  1.5,
+						"metric_name:mem":  int64(2),
+						"metric_name:disk": int64(3),
+					},
+				},
+			},
+		},
+		{
+			name: "different_dimensions_time_and_metadata",
+			events: []*splunk.Event{
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 1.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v1", "Gauge"}, 2.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Sum"}, 3.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts2, keys, []interface{}{"v0", "Gauge"}, 4.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", nil, keys, []interface{}{"v0", "Gauge"}, 5.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, 6.5, "src", "st", "idx", "host2"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, 7.5, "src", "st", "idx2", "host"),
+			},
+			want: []*splunk.Event{
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 1.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v1", "Gauge"}, 2.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Sum"}, 3.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts2, keys, []interface{}{"v0", "Gauge"}, 4.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", nil, keys, []interface{}{"v0", "Gauge"}, 5.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, 6.5, "src", "st", "idx", "host2"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, 7.5, "src", "st", "idx2", "host"),
+			},
+		},
+		{
+			name: "duplicate_metric",
+			events: []*splunk.Event{
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 1.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 2.5, "src", "st", "idx", "host"),
+				commonSplunkMetric("mem", ts1, keys, []interface{}{"v0", "Gauge"}, 3.5, "src", "st", "idx", "host"),
+			},
+			want: []*splunk.Event{
+				commonSplunkMetric("cpu", ts1, keys, []interface{}{"v0", "Gauge"}, 1.5, "src", "st", "idx", "host"),
+				{
+					Time:       ts1,
+					Source:     "src",
+					SourceType: "st",
+					Index:      "idx",
+					Host:       "host",
+					Event:      "metric",
+					Fields: map[string]interface{}{
+						"k0":              "v0",
+						"metric_type":     "Gauge",
+						"metric_name:cpu": 2.5,
+						"metric_name:mem": 3.5,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mergeEventsToMultiMetricFormat(tt.events))
+		})
+	}
+}
+
+func Test_mergeEventsToMultiMetricFormat_histogram(t *testing.T) {
+	md := pdata.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("latency")
+	metric.SetDataType(pdata.MetricDataTypeHistogram)
+	dp := metric.Histogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pdata.NewTimestampFromTime(time.Unix(1574092046, 0)))
+	dp.SetCount(4)
+	dp.SetSum(10)
+	dp.SetExplicitBounds([]float64{1, 2})
+	dp.SetBucketCounts([]uint64{1, 2, 1})
+	dp.Attributes().InsertString("k0", "v0")
+
+	events, _ := metricDataToSplunk(zap.NewNop(), md, createDefaultConfig().(*Config))
+	require.Len(t, events, 5)
+
+	merged := mergeEventsToMultiMetricFormat(events)
+	// The sum and the count share their dimensions, the buckets differ by their bound.
+	require.Len(t, merged, 4)
+	assert.Equal(t, 10.0, merged[0].Fields["metric_name:latency_sum"])
+	assert.Equal(t, uint64(4), merged[0].Fields["metric_name:latency_count"])
+	for _, event := range merged[1:] {
+		assert.Contains(t, event.Fields, "le")
+		assert.Contains(t, event.Fields, "metric_name:latency_bucket")
+	}
+}
+
 func commonSplunkMetric(
 	metricName string,
 	ts *float64,
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/healthcheckextension"
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// componentCheck reports whether a component is healthy, with details about its health serialized to JSON.
+type componentCheck func() (healthy bool, details interface{})
+
+// componentHealth is the health of a component as served on the components path.
+type componentHealth struct {
+	Healthy bool        `json:"healthy"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// componentChecks holds the health checks registered by the components.
+type componentChecks struct {
+	mu     sync.Mutex
+	checks map[config.ComponentID]*componentCheck
+}
+
+// register adds the check of a component, replacing the previous one, and returns a function removing it.
+func (c *componentChecks) register(id config.ComponentID, check componentCheck) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checks == nil {
+		c.checks = map[config.ComponentID]*componentCheck{}
+	}
+	registered := &check
+	c.checks[id] = registered
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// The check may have been replaced by a new instance of the component.
+		if c.checks[id] == registered {
+			delete(c.checks, id)
+		}
+	}
+}
+
+// check runs the registered checks, the components are healthy if all the checks are.
+func (c *componentChecks) check() (bool, map[string]componentHealth) {
+	c.mu.Lock()
+	checks := make(map[config.ComponentID]componentCheck, len(c.checks))
+	for id, check := range c.checks {
+		checks[id] = *check
+	}
+	c.mu.Unlock()
+
+	healthy := true
+	statuses := make(map[string]componentHealth, len(checks))
+	for id, check := range checks {
+		ok, details := check()
+		healthy = healthy && ok
+		statuses[id.String()] = componentHealth{Healthy: ok, Details: details}
+	}
+	return healthy, statuses
+}
+
+// checkComponent runs the check registered by the component of the given ID, if any.
+func (c *componentChecks) checkComponent(id string) (componentHealth, bool) {
+	c.mu.Lock()
+	var check *componentCheck
+	for cid, registered := range c.checks {
+		if cid.String() == id {
+			check = registered
+			break
+		}
+	}
+	c.mu.Unlock()
+	if check == nil {
+		return componentHealth{}, false
+	}
+	ok, details := (*check)()
+	return componentHealth{Healthy: ok, Details: details}, true
+}
+
+// handler serves the health of every component registering a health check on path, and the health of
+// a single component on path/<component ID>.
+func (c *componentChecks) handler(path string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == path {
+			healthy, statuses := c.check()
+			writeHealth(w, healthy, statuses)
+			return
+		}
+		status, ok := c.checkComponent(strings.TrimPrefix(r.URL.Path, path+"/"))
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeHealth(w, status.Healthy, status)
+	})
+}
+
+// writeHealth responds with http.StatusOK if healthy, or with http.StatusServiceUnavailable, and the health as JSON.
+func writeHealth(w http.ResponseWriter, healthy bool, health interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(health)
+}
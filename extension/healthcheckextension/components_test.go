@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testutil"
+)
+
+func TestComponentChecks(t *testing.T) {
+	var checks componentChecks
+	healthy, statuses := checks.check()
+	assert.True(t, healthy)
+	assert.Empty(t, statuses)
+
+	aID := config.NewComponentID("a")
+	bID := config.NewComponentIDWithName("b", "1")
+	unregisterA := checks.register(aID, func() (bool, interface{}) { return true, nil })
+	bHealthy := false
+	unregisterB := checks.register(bID, func() (bool, interface{}) { return bHealthy, "details" })
+
+	healthy, statuses = checks.check()
+	assert.False(t, healthy)
+	assert.Equal(t, map[string]componentHealth{
+		"a":   {Healthy: true},
+		"b/1": {Healthy: false, Details: "details"},
+	}, statuses)
+
+	bHealthy = true
+	healthy, _ = checks.check()
+	assert.True(t, healthy)
+
+	// Unregistering a replaced check keeps the new one.
+	unregisterA2 := checks.register(aID, func() (bool, interface{}) { return false, nil })
+	unregisterA()
+	healthy, statuses = checks.check()
+	assert.False(t, healthy)
+	assert.Len(t, statuses, 2)
+
+	unregisterA2()
+	unregisterB()
+	unregisterB()
+	healthy, statuses = checks.check()
+	assert.True(t, healthy)
+	assert.Empty(t, statuses)
+}
+
+func TestHealthCheckExtensionUsageWithCheckComponents(t *testing.T) {
+	cfg := Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+		CheckComponents: checkComponentsSettings{
+			Enabled: true,
+			Path:    "/components",
+		},
+		Path: "/",
+	}
+
+	hcExt := newServer(cfg, zap.NewNop())
+	require.NotNil(t, hcExt)
+
+	require.NoError(t, hcExt.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, hcExt.Shutdown(context.Background())) })
+
+	// Give a chance for the server goroutine to run.
+	runtime.Gosched()
+	require.NoError(t, hcExt.Ready())
+
+	client := &http.Client{}
+	url := "http://" + cfg.TCPAddr.Endpoint
+	get := func(path string) (int, map[string]componentHealth) {
+		resp, err := client.Get(url + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var statuses map[string]componentHealth
+		if path == cfg.CheckComponents.Path {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&statuses))
+		}
+		return resp.StatusCode, statuses
+	}
+
+	status, _ := get("/")
+	require.Equal(t, http.StatusOK, status)
+	status, statuses := get("/components")
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, statuses)
+	status, _ = get("/components/prometheus")
+	require.Equal(t, http.StatusNotFound, status)
+
+	healthy := false
+	unregister := hcExt.RegisterHealthCheck(config.NewComponentID("prometheus"), func() (bool, interface{}) {
+		return healthy, map[string]int{"down": 1}
+	})
+	unregisterOther := hcExt.RegisterHealthCheck(config.NewComponentIDWithName("prometheus", "other"), func() (bool, interface{}) {
+		return true, nil
+	})
+	defer unregisterOther()
+
+	// An unhealthy component doesn't make the health check fail.
+	status, _ = get("/")
+	require.Equal(t, http.StatusOK, status)
+	status, statuses = get("/components")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, map[string]componentHealth{
+		"prometheus":       {Healthy: false, Details: map[string]interface{}{"down": float64(1)}},
+		"prometheus/other": {Healthy: true},
+	}, statuses)
+
+	status, health := getComponent(t, client, url+"/components/prometheus")
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, componentHealth{Healthy: false, Details: map[string]interface{}{"down": float64(1)}}, health)
+	status, health = getComponent(t, client, url+"/components/prometheus/other")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, componentHealth{Healthy: true}, health)
+
+	healthy = true
+	status, _ = get("/components")
+	require.Equal(t, http.StatusOK, status)
+
+	unregister()
+	status, _ = get("/components/prometheus")
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+// getComponent returns the status code and the health served on the path of a single component.
+func getComponent(t *testing.T, client *http.Client, url string) (int, componentHealth) {
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var health componentHealth
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	return resp.StatusCode, health
+}
+
+func TestHealthCheckExtensionUsageWithoutCheckComponents(t *testing.T) {
+	cfg := Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: testutil.GetAvailableLocalAddress(t),
+		},
+		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+		CheckComponents:        defaultCheckComponentsSettings(),
+		Path:                   "/",
+	}
+
+	hcExt := newServer(cfg, zap.NewNop())
+	require.NotNil(t, hcExt)
+
+	require.NoError(t, hcExt.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, hcExt.Shutdown(context.Background())) })
+
+	// Give a chance for the server goroutine to run.
+	runtime.Gosched()
+	require.NoError(t, hcExt.Ready())
+
+	unregister := hcExt.RegisterHealthCheck(config.NewComponentID("prometheus"), func() (bool, interface{}) { return false, nil })
+	defer unregister()
+
+	client := &http.Client{}
+	url := "http://" + cfg.TCPAddr.Endpoint
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = client.Get(url + cfg.CheckComponents.Path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
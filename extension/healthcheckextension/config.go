@@ -44,6 +44,9 @@ type Config struct {
 
 	// CheckCollectorPipeline contains the list of settings of collector pipeline health check
 	CheckCollectorPipeline checkCollectorPipelineSettings `mapstructure:"check_collector_pipeline"`
+
+	// CheckComponents contains the settings of the health check of the components registering one
+	CheckComponents checkComponentsSettings `mapstructure:"check_components"`
 }
 
 var _ config.Extension = (*Config)(nil)
@@ -51,6 +54,7 @@ var (
 	errNoEndpointProvided                      = errors.New("bad config: endpoint must be specified")
 	errInvalidExporterFailureThresholdProvided = errors.New("bad config: exporter_failure_threshold expects a positive number")
 	errInvalidPath                             = errors.New("bad config: path must start with /")
+	errInvalidComponentsPath                   = errors.New("bad config: check_components.path must start with /, not be / and differ from path")
 )
 
 // Validate checks if the extension configuration is valid
@@ -68,6 +72,10 @@ func (cfg *Config) Validate() error {
 	if !strings.HasPrefix(cfg.Path, "/") {
 		return errInvalidPath
 	}
+	componentsPath := strings.TrimSuffix(cfg.CheckComponents.Path, "/")
+	if cfg.CheckComponents.Enabled && (!strings.HasPrefix(componentsPath, "/") || componentsPath == strings.TrimSuffix(cfg.Path, "/")) {
+		return errInvalidComponentsPath
+	}
 	return nil
 }
 
@@ -79,3 +87,11 @@ type checkCollectorPipelineSettings struct {
 	// ExporterFailureThreshold is the threshold of exporter failure numbers during the Interval
 	ExporterFailureThreshold int `mapstructure:"exporter_failure_threshold"`
 }
+
+type checkComponentsSettings struct {
+	// Enabled indicates whether the health of the components registering a health check is served on Path
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the path serving the health of every component registering a health check, the health of a
+	// single component is served on Path/<component ID>
+	Path string `mapstructure:"path"`
+}
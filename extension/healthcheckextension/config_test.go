@@ -48,10 +48,27 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint: "localhost:13",
 			},
 			CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+			CheckComponents:        defaultCheckComponentsSettings(),
 			Path:                   "/",
 		},
 		ext1)
 
+	ext3 := cfg.Extensions[config.NewComponentIDWithName(typeStr, "3")]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: config.NewExtensionSettings(config.NewComponentIDWithName(typeStr, "3")),
+			TCPAddr: confignet.TCPAddr{
+				Endpoint: "localhost:13",
+			},
+			CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+			CheckComponents: checkComponentsSettings{
+				Enabled: true,
+				Path:    "/status",
+			},
+			Path: "/",
+		},
+		ext3)
+
 	assert.Equal(t, 1, len(cfg.Service.Extensions))
 	assert.Equal(t, config.NewComponentIDWithName(typeStr, "1"), cfg.Service.Extensions[0])
 }
@@ -76,6 +93,10 @@ func TestLoadConfigError(t *testing.T) {
 			"invalidpath",
 			errInvalidPath,
 		},
+		{
+			"invalidcomponentspath",
+			errInvalidComponentsPath,
+		},
 	}
 	for _, tt := range tests {
 		factory := NewFactory()
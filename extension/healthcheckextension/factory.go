@@ -47,6 +47,7 @@ func createDefaultConfig() config.Extension {
 			Endpoint: defaultEndpoint,
 		},
 		CheckCollectorPipeline: defaultCheckCollectorPipelineSettings(),
+		CheckComponents:        defaultCheckComponentsSettings(),
 		Path:                   "/",
 	}
 }
@@ -65,3 +66,11 @@ func defaultCheckCollectorPipelineSettings() checkCollectorPipelineSettings {
 		ExporterFailureThreshold: 5,
 	}
 }
+
+// defaultCheckComponentsSettings returns the default settings for CheckComponents.
+func defaultCheckComponentsSettings() checkComponentsSettings {
+	return checkComponentsSettings{
+		Enabled: false,
+		Path:    "/components",
+	}
+}
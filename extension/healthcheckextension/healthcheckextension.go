@@ -20,11 +20,13 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.uber.org/zap"
 )
 
@@ -35,6 +37,7 @@ type healthCheckExtension struct {
 	server   http.Server
 	stopCh   chan struct{}
 	exporter *healthCheckExporter
+	checks   componentChecks
 }
 
 var _ component.PipelineWatcher = (*healthCheckExtension)(nil)
@@ -63,6 +66,7 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 		// Mount HC handler
 		mux := http.NewServeMux()
 		mux.Handle(hc.config.Path, hc.state.Handler())
+		hc.mountComponents(mux)
 		hc.server.Handler = mux
 		hc.stopCh = make(chan struct{})
 		go func() {
@@ -88,6 +92,7 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 
 		mux := http.NewServeMux()
 		mux.Handle(hc.config.Path, hc.handler())
+		hc.mountComponents(mux)
 		hc.server.Handler = mux
 		hc.stopCh = make(chan struct{})
 		go func() {
@@ -126,6 +131,26 @@ func (hc *healthCheckExtension) handler() http.Handler {
 	})
 }
 
+// mountComponents mounts the handler serving the health of the components, if check_components is enabled.
+// The health of the components is served apart from the health check path, so that an unhealthy component,
+// e.g. a receiver whose targets are down, doesn't make a liveness probe restart the collector.
+func (hc *healthCheckExtension) mountComponents(mux *http.ServeMux) {
+	if hc.config.CheckComponents.Enabled {
+		path := strings.TrimSuffix(hc.config.CheckComponents.Path, "/")
+		handler := hc.checks.handler(path)
+		mux.Handle(path, handler)
+		mux.Handle(path+"/", handler)
+	}
+}
+
+// RegisterHealthCheck registers the health check of a component, e.g. a receiver reporting the health
+// of the endpoints it scrapes. The check returns whether the component is healthy and details about its
+// health, served as JSON on the components path. The returned function unregisters the check and is
+// expected to be called when the component shuts down.
+func (hc *healthCheckExtension) RegisterHealthCheck(id config.ComponentID, check func() (bool, interface{})) func() {
+	return hc.checks.register(id, check)
+}
+
 func (hc *healthCheckExtension) check() bool {
 	return hc.exporter.checkHealthStatus(hc.config.CheckCollectorPipeline.ExporterFailureThreshold)
 }
@@ -57,7 +57,7 @@ func createExtension(
 	var metadataProvider ecsutil.MetadataProvider
 	var err error
 	if obsCfg.Endpoint == "" {
-		metadataProvider, err = ecsutil.NewDetectedTaskMetadataProvider(logger)
+		metadataProvider, err = ecsutil.NewDetectedTaskMetadataProvider(logger, ecsutil.WithRateLimit(ecsutil.DefaultRateLimit))
 	} else {
 		metadataProvider, err = metadataProviderFromEndpoint(obsCfg, logger)
 	}
@@ -86,7 +86,7 @@ func metadataProviderFromEndpoint(config *Config, logger *zap.Logger) (ecsutil.M
 		return nil, fmt.Errorf("failed to parse task metadata endpoint: %w", err)
 	}
 
-	restClient, err := ecsutil.NewRestClient(*parsed, config.HTTPClientSettings, logger)
+	restClient, err := ecsutil.NewRestClient(*parsed, config.HTTPClientSettings, logger, ecsutil.WithRateLimit(ecsutil.DefaultRateLimit))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ECS Task Observer rest client: %w", err)
 	}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/awsutil"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// SigV4Settings defines how the requests sent to AWS services are signed with AWS SigV4.
+type SigV4Settings struct {
+	// Region is the AWS region of the service.
+	Region string `mapstructure:"region"`
+	// Service is the name of the AWS service the requests are signed for, e.g. aps or es.
+	Service string `mapstructure:"service"`
+	// RoleARN is the Amazon Resource Name of a role to assume. Optional, the credentials of
+	// the default chain, e.g. environment variables or ~/.aws, are used if empty.
+	RoleARN string `mapstructure:"role_arn"`
+}
+
+// NewSigV4Credentials returns the credentials of the default chain, or of the role RoleARN
+// assumed with them.
+func NewSigV4Credentials(settings SigV4Settings) (*credentials.Credentials, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{Region: aws.String(settings.Region)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if settings.RoleARN != "" {
+		// Get credentials from an assumeRole API call.
+		return stscreds.NewCredentials(sess, settings.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = "aws-otel-collector-" + strconv.FormatInt(time.Now().Unix(), 10)
+		}), nil
+	}
+	// Get Credentials, either from ./aws or from environmental variables.
+	return sess.Config.Credentials, nil
+}
+
+// NewSigV4RoundTripper returns a http.RoundTripper signing the requests with creds before sending
+// them with next. The userAgent, e.g. the name and version of the component, is appended to the
+// User-Agent header of the requests.
+func NewSigV4RoundTripper(settings SigV4Settings, creds *credentials.Credentials, next http.RoundTripper, userAgent string) (http.RoundTripper, error) {
+	if creds == nil {
+		return nil, errors.New("no AWS credentials exist")
+	}
+	return &sigV4RoundTripper{
+		transport: next,
+		signer:    v4.NewSigner(creds),
+		region:    settings.Region,
+		service:   settings.Service,
+		userAgent: userAgent,
+	}, nil
+}
+
+// sigV4RoundTripper is a http.RoundTripper that performs AWS SigV4.
+type sigV4RoundTripper struct {
+	transport http.RoundTripper
+	signer    *v4.Signer
+	region    string
+	service   string
+	userAgent string
+}
+
+func (si *sigV4RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body *bytes.Reader
+	if req.GetBody != nil {
+		reqBody, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(reqBody)
+		reqBody.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(content)
+	}
+
+	// Clone request to ensure thread safety.
+	req2 := cloneRequest(req)
+
+	// Add the user agent of the component to the User-Agent header of the request.
+	ua := req2.Header.Get("User-Agent")
+	if len(ua) > 0 {
+		ua = ua + " " + si.userAgent
+	} else {
+		ua = si.userAgent
+	}
+	req2.Header.Set("User-Agent", ua)
+
+	// Sign the request, a nil body is signed as an empty one.
+	var err error
+	if body != nil {
+		_, err = si.signer.Sign(req2, body, si.service, si.region, time.Now())
+	} else {
+		_, err = si.signer.Sign(req2, nil, si.service, si.region, time.Now())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error signing the request: %w", err)
+	}
+
+	return si.transport.RoundTrip(req2)
+}
+
+func cloneRequest(r *http.Request) *http.Request {
+	// shallow copy of the struct
+	r2 := new(http.Request)
+	*r2 = *r
+	// deep copy of the Header
+	r2.Header = make(http.Header, len(r.Header))
+	for k, s := range r.Header {
+		r2.Header[k] = append([]string(nil), s...)
+	}
+	return r2
+}
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigV4RoundTripperSignsRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []byte
+		userAgent string
+		wantUA    string
+	}{
+		{
+			name:   "with_body",
+			body:   []byte("payload"),
+			wantUA: "test/1.0",
+		},
+		{
+			name:   "without_body",
+			wantUA: "test/1.0",
+		},
+		{
+			name:      "appends_user_agent",
+			body:      []byte("payload"),
+			userAgent: "client/2.0",
+			wantUA:    "client/2.0 test/1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := v4.GetSignedRequestSignature(r)
+				assert.NoError(t, err)
+				assert.Contains(t, r.Header.Get("Authorization"), "/region/service/aws4_request")
+				assert.Equal(t, tt.wantUA, r.Header.Get("User-Agent"))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			rt, err := NewSigV4RoundTripper(SigV4Settings{Region: "region", Service: "service"}, fetchMockCredentials(), http.DefaultTransport, "test/1.0")
+			require.NoError(t, err)
+
+			var req *http.Request
+			if tt.body != nil {
+				req, err = http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(tt.body))
+			} else {
+				req, err = http.NewRequest(http.MethodGet, server.URL, nil)
+			}
+			require.NoError(t, err)
+			if tt.userAgent != "" {
+				req.Header.Set("User-Agent", tt.userAgent)
+			}
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			// The original request must not be modified.
+			assert.Empty(t, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestNewSigV4Credentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings SigV4Settings
+	}{
+		{
+			"default_chain",
+			SigV4Settings{Region: "region", Service: "service"},
+		},
+		{
+			"assume_role",
+			SigV4Settings{Region: "region", Service: "service", RoleARN: "arn:aws:iam::123456789012:role/IAMRole"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := NewSigV4Credentials(tt.settings)
+			require.NoError(t, err)
+			require.NotNil(t, creds)
+		})
+	}
+}
+
+func TestNewSigV4RoundTripper(t *testing.T) {
+	defaultRoundTripper := (http.RoundTripper)(http.DefaultTransport.(*http.Transport).Clone())
+
+	// Some form of AWS credentials must be set up for tests to succeed
+	awsCreds := fetchMockCredentials()
+
+	tests := []struct {
+		name         string
+		creds        *credentials.Credentials
+		roundTripper http.RoundTripper
+		settings     SigV4Settings
+		authApplied  bool
+		returnError  bool
+	}{
+		{
+			"success_case",
+			awsCreds,
+			defaultRoundTripper,
+			SigV4Settings{Region: "region", Service: "service"},
+			true,
+			false,
+		},
+		{
+			"success_case_no_auth_applied",
+			awsCreds,
+			defaultRoundTripper,
+			SigV4Settings{Region: "", Service: ""},
+			false,
+			false,
+		},
+		{
+			"no_credentials_provided_error",
+			nil,
+			defaultRoundTripper,
+			SigV4Settings{Region: "region", Service: "service"},
+			true,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rtp, err := NewSigV4RoundTripper(tt.settings, tt.creds, tt.roundTripper, "test/1.0")
+			if tt.returnError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.authApplied {
+				sRtp := rtp.(*sigV4RoundTripper)
+				assert.Equal(t, sRtp.transport, tt.roundTripper)
+				assert.Equal(t, tt.settings.Service, sRtp.service)
+			}
+		})
+	}
+}
+
+func TestCloneRequest(t *testing.T) {
+	req1, err := http.NewRequest("GET", "https://example.com", nil)
+	assert.NoError(t, err)
+
+	req2, err := http.NewRequest("GET", "https://example.com", nil)
+	assert.NoError(t, err)
+	req2.Header.Add("Header1", "val1")
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		headers http.Header
+	}{
+		{
+			"no_headers",
+			req1,
+			http.Header{},
+		},
+		{
+			"headers",
+			req2,
+			http.Header{"Header1": []string{"val1"}},
+		},
+	}
+	// run tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r2 := cloneRequest(tt.request)
+			assert.EqualValues(t, tt.request.Header, r2.Header)
+		})
+	}
+}
+
+func fetchMockCredentials() *credentials.Credentials {
+	return credentials.NewStaticCredentials(
+		"MOCK_AWS_ACCESS_KEY",
+		"MOCK_AWS_SECRET_ACCESS_KEY",
+		"MOCK_TOKEN",
+	)
+}
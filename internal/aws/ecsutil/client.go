@@ -34,11 +34,12 @@ type Client interface {
 }
 
 // NewClientProvider creates the default rest client provider
-func NewClientProvider(baseURL url.URL, clientSettings confighttp.HTTPClientSettings, logger *zap.Logger) ClientProvider {
+func NewClientProvider(baseURL url.URL, clientSettings confighttp.HTTPClientSettings, logger *zap.Logger, opts ...ClientOption) ClientProvider {
 	return &defaultClientProvider{
 		baseURL:        baseURL,
 		clientSettings: clientSettings,
 		logger:         logger,
+		opts:           opts,
 	}
 }
 
@@ -51,6 +52,7 @@ type defaultClientProvider struct {
 	baseURL        url.URL
 	clientSettings confighttp.HTTPClientSettings
 	logger         *zap.Logger
+	opts           []ClientOption
 }
 
 func (dcp *defaultClientProvider) BuildClient() (Client, error) {
@@ -58,6 +60,7 @@ func (dcp *defaultClientProvider) BuildClient() (Client, error) {
 		dcp.baseURL,
 		dcp.clientSettings,
 		dcp.logger,
+		dcp.opts...,
 	)
 }
 
@@ -65,6 +68,7 @@ func defaultClient(
 	baseURL url.URL,
 	clientSettings confighttp.HTTPClientSettings,
 	logger *zap.Logger,
+	opts ...ClientOption,
 ) (*clientImpl, error) {
 	client, err := clientSettings.ToClient(map[cconfig.ComponentID]component.Extension{})
 	if err != nil {
@@ -73,10 +77,20 @@ func defaultClient(
 	if client == nil {
 		return nil, fmt.Errorf("unexpected default client nil value")
 	}
+	options := newClientOptions(opts)
+	if options.rateLimit != nil {
+		// The middlewares see the requests before they are rate limited.
+		client.Transport = newRateLimitedTransport(baseURL, *options.rateLimit, client.Transport)
+	}
+	if len(options.middlewares) > 0 {
+		client.Transport = options.wrap(client.Transport)
+	}
 	return &clientImpl{
 		baseURL:    baseURL,
 		httpClient: *client,
 		logger:     logger,
+		mutators:   options.mutators,
+		observers:  options.observers,
 	}, nil
 }
 
@@ -86,6 +100,8 @@ type clientImpl struct {
 	baseURL    url.URL
 	httpClient http.Client
 	logger     *zap.Logger
+	mutators   []RequestMutator
+	observers  []ResponseObserver
 }
 
 func (c *clientImpl) Get(path string) ([]byte, error) {
@@ -93,7 +109,15 @@ func (c *clientImpl) Get(path string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	for _, mutate := range c.mutators {
+		if err = mutate(req); err != nil {
+			return nil, err
+		}
+	}
 	resp, err := c.httpClient.Do(req)
+	for _, observe := range c.observers {
+		observe(req, resp, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,184 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutiltest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil/ecsutiltest"
+
+import (
+	_ "embed"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil/endpoints"
+)
+
+//go:embed testdata/task_stats.json
+var TaskStatsTestResponse []byte
+
+//go:embed testdata/container_stats.json
+var ContainerStatsTestResponse []byte
+
+// The v2 and v3 endpoints don't report the launch type of the task, the ARN and log options of the
+// containers, nor the network rate stats.
+
+//go:embed testdata/v3/task_metadata.json
+var V3TaskMetadataTestResponse []byte
+
+//go:embed testdata/v3/container_metadata.json
+var V3ContainerMetadataTestResponse []byte
+
+//go:embed testdata/v3/task_stats.json
+var V3TaskStatsTestResponse []byte
+
+//go:embed testdata/v3/container_stats.json
+var V3ContainerStatsTestResponse []byte
+
+// Version is the version of the task metadata endpoint served by a Server.
+type Version int
+
+const (
+	// V2 serves the paths of the version 2 endpoint, at a fixed address in the tasks using the awsvpc network mode.
+	V2 Version = 2
+	// V3 serves the paths of the version 3 endpoint, whose address is set in the ECS_CONTAINER_METADATA_URI
+	// environment variable.
+	V3 Version = 3
+	// V4 serves the paths of the version 4 endpoint, whose address is set in the ECS_CONTAINER_METADATA_URI_V4
+	// environment variable.
+	V4 Version = 4
+)
+
+// The paths of the version 2 endpoint, relative to its address. The container paths are suffixed by the
+// Docker ID of the container.
+const (
+	V2TaskMetadataPath      = "/metadata"
+	V2ContainerMetadataPath = "/metadata/" + ContainerID
+	V2TaskStatsPath         = "/stats"
+	V2ContainerStatsPath    = "/stats/" + ContainerID
+)
+
+// ContainerID is the Docker ID of the container whose metadata and stats are served by the container paths,
+// the container of the collector in a task.
+const ContainerID = "325c979aea914acd93be2fdd2429e1d9-3811061257"
+
+// Server is a fake task metadata endpoint, serving the testdata responses of a version of the endpoint until
+// they are replaced by SetResponse. The requests of the paths without a response fail with a 404 status code.
+type Server struct {
+	*httptest.Server
+	version Version
+	// basePath is the path of the endpoint in the URL of the server.
+	basePath string
+
+	mu        sync.Mutex
+	responses map[string]response
+	requests  []string
+}
+
+type response struct {
+	status int
+	body   []byte
+}
+
+// NewServer starts a task metadata endpoint of the given version, which is closed at the end of the test.
+func NewServer(t testing.TB, version Version) *Server {
+	s := &Server{version: version, responses: map[string]response{}}
+	switch version {
+	case V2:
+		s.basePath = "/v2"
+		s.SetResponse(V2TaskMetadataPath, V3TaskMetadataTestResponse)
+		s.SetResponse(V2ContainerMetadataPath, V3ContainerMetadataTestResponse)
+		s.SetResponse(V2TaskStatsPath, V3TaskStatsTestResponse)
+		s.SetResponse(V2ContainerStatsPath, V3ContainerStatsTestResponse)
+	case V3:
+		s.basePath = "/v3/" + ContainerID
+		s.SetResponse(endpoints.TaskMetadataPath, V3TaskMetadataTestResponse)
+		s.SetResponse(endpoints.ContainerMetadataPath, V3ContainerMetadataTestResponse)
+		s.SetResponse(endpoints.TaskStatsPath, V3TaskStatsTestResponse)
+		s.SetResponse(endpoints.ContainerStatsPath, V3ContainerStatsTestResponse)
+	case V4:
+		s.basePath = "/v4/" + ContainerID
+		s.SetResponse(endpoints.TaskMetadataPath, TaskMetadataTestResponse)
+		s.SetResponse(endpoints.TaskMetadataWithTagsPath, TaskMetadataWithTagsTestResponse)
+		s.SetResponse(endpoints.ContainerMetadataPath, ContainerMetadataTestResponse)
+		s.SetResponse(endpoints.TaskStatsPath, TaskStatsTestResponse)
+		s.SetResponse(endpoints.ContainerStatsPath, ContainerStatsTestResponse)
+	default:
+		t.Fatalf("unsupported task metadata endpoint version %d", version)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, s.basePath) {
+		http.NotFound(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, s.basePath)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, path)
+	resp, ok := s.responses[path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+// Endpoint returns the address of the endpoint, to which the paths are relative.
+func (s *Server) Endpoint() *url.URL {
+	endpoint, _ := url.Parse(s.URL + s.basePath)
+	return endpoint
+}
+
+// SetEnv sets the environment variable of the address of the endpoint for the duration of the test,
+// for the endpoint to be detected like in a task. The version 2 endpoint has no environment variable.
+func (s *Server) SetEnv(t testing.TB) {
+	switch s.version {
+	case V3:
+		t.Setenv(endpoints.TaskMetadataEndpointV3EnvVar, s.Endpoint().String())
+	case V4:
+		t.Setenv(endpoints.TaskMetadataEndpointV4EnvVar, s.Endpoint().String())
+	default:
+		t.Fatalf("the task metadata endpoint version %d has no environment variable", s.version)
+	}
+}
+
+// SetResponse replaces the response of a path, relative to the endpoint.
+func (s *Server) SetResponse(path string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = response{status: http.StatusOK, body: body}
+}
+
+// SetStatus makes the requests of a path, relative to the endpoint, fail with the given status code.
+func (s *Server) SetStatus(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = response{status: status, body: []byte(http.StatusText(status))}
+}
+
+// Requests returns the paths requested so far, relative to the endpoint.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
@@ -0,0 +1,105 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutiltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil/ecsutiltest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil/endpoints"
+)
+
+func TestServerDetectedMetadataProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		version ecsutiltest.Version
+	}{
+		{name: "v3", version: ecsutiltest.V3},
+		{name: "v4", version: ecsutiltest.V4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := ecsutiltest.NewServer(t, tt.version)
+			server.SetEnv(t)
+
+			provider, err := ecsutil.NewDetectedTaskMetadataProvider(zap.NewNop())
+			require.NoError(t, err)
+			task, err := provider.FetchTaskMetadata()
+			require.NoError(t, err)
+			assert.Equal(t, "test200", task.Cluster)
+			assert.Len(t, task.Containers, 3)
+			container, err := provider.FetchContainerMetadata()
+			require.NoError(t, err)
+			assert.Equal(t, ecsutiltest.ContainerID, container.DockerID)
+
+			assert.Equal(t, []string{endpoints.TaskMetadataPath, endpoints.ContainerMetadataPath}, server.Requests())
+		})
+	}
+}
+
+func TestServerV2(t *testing.T) {
+	server := ecsutiltest.NewServer(t, ecsutiltest.V2)
+	client, err := ecsutil.NewRestClient(*server.Endpoint(), confighttp.HTTPClientSettings{}, zap.NewNop())
+	require.NoError(t, err)
+
+	var task ecsutil.TaskMetadata
+	body, err := client.GetResponse(ecsutiltest.V2TaskMetadataPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, "test200", task.Cluster)
+
+	var stats map[string]interface{}
+	body, err = client.GetResponse(ecsutiltest.V2TaskStatsPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(body, &stats))
+	assert.Len(t, stats, 3)
+
+	body, err = client.GetResponse(ecsutiltest.V2ContainerStatsPath)
+	require.NoError(t, err)
+	assert.Equal(t, ecsutiltest.V3ContainerStatsTestResponse, body)
+}
+
+func TestServerSetResponse(t *testing.T) {
+	server := ecsutiltest.NewServer(t, ecsutiltest.V4)
+	client, err := ecsutil.NewRestClient(*server.Endpoint(), confighttp.HTTPClientSettings{}, zap.NewNop())
+	require.NoError(t, err)
+
+	body, err := client.GetResponse(endpoints.TaskStatsPath)
+	require.NoError(t, err)
+	assert.Equal(t, ecsutiltest.TaskStatsTestResponse, body)
+
+	server.SetResponse(endpoints.TaskStatsPath, []byte("{}"))
+	body, err = client.GetResponse(endpoints.TaskStatsPath)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("{}"), body)
+
+	server.SetStatus(endpoints.TaskStatsPath, http.StatusInternalServerError)
+	_, err = client.GetResponse(endpoints.TaskStatsPath)
+	assert.Error(t, err)
+
+	_, err = client.GetResponse("/unknown")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{endpoints.TaskStatsPath, endpoints.TaskStatsPath, endpoints.TaskStatsPath, "/unknown"}, server.Requests())
+}
@@ -27,11 +27,16 @@ var ContainerMetadataTestResponse []byte
 //go:embed testdata/task_metadata.json
 var TaskMetadataTestResponse []byte
 
+//go:embed testdata/task_metadata_with_tags.json
+var TaskMetadataWithTagsTestResponse []byte
+
 // GetTestdataResponseByPath will return example metadata for a given path.
 func GetTestdataResponseByPath(_ *testing.T, path string) ([]byte, error) {
 	switch path {
 	case endpoints.TaskMetadataPath:
 		return TaskMetadataTestResponse, nil
+	case endpoints.TaskMetadataWithTagsPath:
+		return TaskMetadataWithTagsTestResponse, nil
 	case endpoints.ContainerMetadataPath:
 		return ContainerMetadataTestResponse, nil
 	}
@@ -25,8 +25,11 @@ const (
 	TaskMetadataEndpointV3EnvVar = "ECS_CONTAINER_METADATA_URI"
 	TaskMetadataEndpointV4EnvVar = "ECS_CONTAINER_METADATA_URI_V4"
 
-	TaskMetadataPath      = "/task"
-	ContainerMetadataPath = ""
+	TaskMetadataPath         = "/task"
+	TaskMetadataWithTagsPath = "/taskWithTags"
+	ContainerMetadataPath    = ""
+	TaskStatsPath            = "/task/stats"
+	ContainerStatsPath       = "/stats"
 )
 
 // ErrNoTaskMetadataEndpointDetected is a reserved error type to distinguish between incompatible environments
@@ -27,6 +27,9 @@ type TaskMetadata struct {
 	PullStoppedAt    string              `json:"PullStoppedAt,omitempty"`
 	Revision         string              `json:"Revision,omitempty"`
 	TaskARN          string              `json:"TaskARN,omitempty"`
+	// TaskTags and ContainerInstanceTags are only reported by the task metadata with tags endpoint.
+	TaskTags              map[string]string `json:"TaskTags,omitempty"`
+	ContainerInstanceTags map[string]string `json:"ContainerInstanceTags,omitempty"`
 }
 
 // ContainerMetadata defines container metadata for a container
@@ -48,6 +51,15 @@ type ContainerMetadata struct {
 	Networks      []Network         `json:"Networks,omitempty"`
 	StartedAt     string            `json:"StartedAt,omitempty"`
 	Type          string            `json:"Type,omitempty"`
+	Volumes       []Volume          `json:"Volumes,omitempty"`
+}
+
+// Volume defines a volume mounted in a container, e.g. a Docker volume, a bind mount,
+// or an attached EBS or EFS volume.
+type Volume struct {
+	DockerName  string `json:"DockerName,omitempty"`
+	Source      string `json:"Source,omitempty"`
+	Destination string `json:"Destination,omitempty"`
 }
 
 // Limits defines the Cpu and Memory limits
@@ -63,7 +75,81 @@ type LogOptions struct {
 	Stream   string `json:"awslogs-stream,omitempty"`
 }
 
+// Network mode values reported by the task metadata endpoint.
+const (
+	NetworkModeAWSVPC = "awsvpc"
+	NetworkModeBridge = "bridge"
+	NetworkModeHost   = "host"
+	NetworkModeNone   = "none"
+)
+
+// Network defines a network a container is attached to. The elastic network interface (ENI)
+// attachment details are only reported for containers using the awsvpc network mode.
 type Network struct {
-	IPv4Addresses []string `json:"IPv4Addresses,omitempty"`
-	NetworkMode   string   `json:"NetworkMode,omitempty"`
+	AttachmentIndex          *int     `json:"AttachmentIndex,omitempty"`
+	DomainNameServers        []string `json:"DomainNameServers,omitempty"`
+	DomainNameSearchList     []string `json:"DomainNameSearchList,omitempty"`
+	IPv4Addresses            []string `json:"IPv4Addresses,omitempty"`
+	IPv4SubnetCIDRBlock      string   `json:"IPv4SubnetCIDRBlock,omitempty"`
+	IPv6Addresses            []string `json:"IPv6Addresses,omitempty"`
+	IPv6SubnetCIDRBlock      string   `json:"IPv6SubnetCIDRBlock,omitempty"`
+	MACAddress               string   `json:"MACAddress,omitempty"`
+	NetworkMode              string   `json:"NetworkMode,omitempty"`
+	PrivateDNSName           string   `json:"PrivateDNSName,omitempty"`
+	SubnetGatewayIPv4Address string   `json:"SubnetGatewayIpv4Address,omitempty"`
+}
+
+// HasENIAttachment returns true if the network is backed by an elastic network interface.
+func (n Network) HasENIAttachment() bool {
+	return n.NetworkMode == NetworkModeAWSVPC && n.MACAddress != ""
+}
+
+// PrimaryIPv4Address returns the first IPv4 address of the network, or an empty string if there is none.
+func (n Network) PrimaryIPv4Address() string {
+	if len(n.IPv4Addresses) == 0 {
+		return ""
+	}
+	return n.IPv4Addresses[0]
+}
+
+// NetworkMode returns the network mode of the container's first network, or an empty string
+// if the container has no networks (e.g. when it runs with the "none" network mode).
+func (cm ContainerMetadata) NetworkMode() string {
+	if len(cm.Networks) == 0 {
+		return ""
+	}
+	return cm.Networks[0].NetworkMode
+}
+
+// PrimaryIPv4Address returns the first IPv4 address assigned to the container across its networks,
+// or an empty string if there is none. Containers using the host network mode don't report addresses.
+func (cm ContainerMetadata) PrimaryIPv4Address() string {
+	for _, n := range cm.Networks {
+		if ip := n.PrimaryIPv4Address(); ip != "" {
+			return ip
+		}
+	}
+	return ""
+}
+
+// ENIAttachment returns the first network of the container that is backed by an elastic network
+// interface, and whether one was found.
+func (cm ContainerMetadata) ENIAttachment() (Network, bool) {
+	for _, n := range cm.Networks {
+		if n.HasENIAttachment() {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// NetworkMode returns the network mode of the task. All containers of a task share the same
+// network mode, so the first container reporting one is used.
+func (tm TaskMetadata) NetworkMode() string {
+	for _, c := range tm.Containers {
+		if mode := c.NetworkMode(); mode != "" {
+			return mode
+		}
+	}
+	return ""
 }
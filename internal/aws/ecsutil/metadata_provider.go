@@ -27,6 +27,7 @@ import (
 
 type MetadataProvider interface {
 	FetchTaskMetadata() (*TaskMetadata, error)
+	FetchTaskMetadataWithTags() (*TaskMetadata, error)
 	FetchContainerMetadata() (*ContainerMetadata, error)
 }
 
@@ -44,7 +45,7 @@ func NewTaskMetadataProvider(client RestClient, logger *zap.Logger) MetadataProv
 	}
 }
 
-func NewDetectedTaskMetadataProvider(logger *zap.Logger) (MetadataProvider, error) {
+func NewDetectedTaskMetadataProvider(logger *zap.Logger, opts ...ClientOption) (MetadataProvider, error) {
 	endpoint, err := endpoints.GetTMEFromEnv()
 	if err != nil {
 		return nil, err
@@ -53,7 +54,7 @@ func NewDetectedTaskMetadataProvider(logger *zap.Logger) (MetadataProvider, erro
 	}
 
 	clientSettings := confighttp.HTTPClientSettings{}
-	client, err := NewRestClient(*endpoint, clientSettings, logger)
+	client, err := NewRestClient(*endpoint, clientSettings, logger, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +67,17 @@ func NewDetectedTaskMetadataProvider(logger *zap.Logger) (MetadataProvider, erro
 
 // FetchTaskMetadata retrieves the metadata for a task running on Amazon ECS
 func (md *ecsMetadataProviderImpl) FetchTaskMetadata() (*TaskMetadata, error) {
-	resp, err := md.client.GetResponse(endpoints.TaskMetadataPath)
+	return md.fetchTaskMetadata(endpoints.TaskMetadataPath)
+}
+
+// FetchTaskMetadataWithTags retrieves the metadata for a task running on Amazon ECS along with the tags
+// of the task and of its container instance, which requires the ecs:ListTagsForResource permission
+func (md *ecsMetadataProviderImpl) FetchTaskMetadataWithTags() (*TaskMetadata, error) {
+	return md.fetchTaskMetadata(endpoints.TaskMetadataWithTagsPath)
+}
+
+func (md *ecsMetadataProviderImpl) fetchTaskMetadata(path string) (*TaskMetadata, error) {
+	resp, err := md.client.GetResponse(path)
 	if err != nil {
 		return nil, err
 	}
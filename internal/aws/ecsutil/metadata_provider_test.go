@@ -55,6 +55,19 @@ func Test_ecsMetadata_fetchTask(t *testing.T) {
 	assert.Equal(t, 3, len(fetchResp.Containers))
 }
 
+func Test_ecsMetadata_fetchTaskWithTags(t *testing.T) {
+	mockRestClient := NewRestClientFromClient(&mockClient{response: string(ecsutiltest.TaskMetadataWithTagsTestResponse), retErr: false})
+	md := ecsMetadataProviderImpl{logger: zap.NewNop(), client: mockRestClient}
+	fetchResp, err := md.FetchTaskMetadataWithTags()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, fetchResp)
+	assert.Equal(t, "test200", fetchResp.Cluster)
+	assert.Equal(t, map[string]string{"team": "checkout", "cost-center": "1234"}, fetchResp.TaskTags)
+	assert.Equal(t, map[string]string{"env": "prod"}, fetchResp.ContainerInstanceTags)
+	assert.Equal(t, 3, len(fetchResp.Containers))
+}
+
 func Test_ecsMetadata_fetchContainer(t *testing.T) {
 	mockRestClient := NewRestClientFromClient(&mockClient{response: string(ecsutiltest.ContainerMetadataTestResponse), retErr: false})
 	md := ecsMetadataProviderImpl{logger: zap.NewNop(), client: mockRestClient}
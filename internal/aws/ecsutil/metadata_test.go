@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil/ecsutiltest"
+)
+
+const awsvpcContainerMetadata = `{
+    "DockerId": "cd189a933e5849daa93386466019ab50-2495160603",
+    "Name": "curl",
+    "KnownStatus": "RUNNING",
+    "Networks": [
+        {
+            "NetworkMode": "awsvpc",
+            "IPv4Addresses": [
+                "10.0.0.108"
+            ],
+            "AttachmentIndex": 0,
+            "MACAddress": "0e:9e:32:c7:48:85",
+            "IPv4SubnetCIDRBlock": "10.0.0.0/24",
+            "PrivateDNSName": "ip-10-0-0-108.us-west-2.compute.internal",
+            "SubnetGatewayIpv4Address": "10.0.0.1/24"
+        }
+    ],
+    "Volumes": [
+        {
+            "DockerName": "efs-html",
+            "Destination": "/usr/share/nginx/html"
+        },
+        {
+            "Source": "/var/lib/ecs/data",
+            "Destination": "/data"
+        }
+    ]
+}`
+
+func TestContainerMetadataAWSVPC(t *testing.T) {
+	cm := ContainerMetadata{}
+	require.NoError(t, json.Unmarshal([]byte(awsvpcContainerMetadata), &cm))
+
+	assert.Equal(t, NetworkModeAWSVPC, cm.NetworkMode())
+	assert.Equal(t, "10.0.0.108", cm.PrimaryIPv4Address())
+
+	eni, ok := cm.ENIAttachment()
+	require.True(t, ok)
+	require.NotNil(t, eni.AttachmentIndex)
+	assert.Equal(t, 0, *eni.AttachmentIndex)
+	assert.Equal(t, "0e:9e:32:c7:48:85", eni.MACAddress)
+	assert.Equal(t, "10.0.0.0/24", eni.IPv4SubnetCIDRBlock)
+	assert.Equal(t, "ip-10-0-0-108.us-west-2.compute.internal", eni.PrivateDNSName)
+	assert.Equal(t, "10.0.0.1/24", eni.SubnetGatewayIPv4Address)
+
+	assert.Equal(t, []Volume{
+		{DockerName: "efs-html", Destination: "/usr/share/nginx/html"},
+		{Source: "/var/lib/ecs/data", Destination: "/data"},
+	}, cm.Volumes)
+}
+
+func TestContainerMetadataBridge(t *testing.T) {
+	tm := TaskMetadata{}
+	require.NoError(t, json.Unmarshal(ecsutiltest.TaskMetadataTestResponse, &tm))
+
+	assert.Equal(t, NetworkModeBridge, tm.NetworkMode())
+	require.NotEmpty(t, tm.Containers)
+	cm := tm.Containers[0]
+	assert.Equal(t, NetworkModeBridge, cm.NetworkMode())
+	assert.Equal(t, "172.17.0.3", cm.PrimaryIPv4Address())
+	_, ok := cm.ENIAttachment()
+	assert.False(t, ok)
+}
+
+func TestContainerMetadataHost(t *testing.T) {
+	cm := ContainerMetadata{Networks: []Network{{NetworkMode: NetworkModeHost}}}
+
+	assert.Equal(t, NetworkModeHost, cm.NetworkMode())
+	assert.Equal(t, "", cm.PrimaryIPv4Address())
+	_, ok := cm.ENIAttachment()
+	assert.False(t, ok)
+}
+
+func TestContainerMetadataNoNetworks(t *testing.T) {
+	cm := ContainerMetadata{}
+	assert.Equal(t, "", cm.NetworkMode())
+	assert.Equal(t, "", cm.PrimaryIPv4Address())
+	assert.Equal(t, "", TaskMetadata{Containers: []ContainerMetadata{cm}}.NetworkMode())
+}
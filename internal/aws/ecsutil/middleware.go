@@ -0,0 +1,89 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil"
+
+import (
+	"net/http"
+)
+
+// RequestMutator modifies a request before it is sent, e.g. to authenticate it.
+// The request is not sent if an error is returned.
+type RequestMutator func(req *http.Request) error
+
+// ResponseObserver is notified of the outcome of a request, e.g. to log it or record metrics.
+// The response is nil if the request failed, its body must not be read by the observer.
+type ResponseObserver func(req *http.Request, resp *http.Response, err error)
+
+// Middleware wraps the transport of the client, e.g. to inject faults in tests.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// ClientOption customizes the requests of the clients built by a ClientProvider.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	mutators    []RequestMutator
+	observers   []ResponseObserver
+	middlewares []Middleware
+	rateLimit   *RateLimit
+}
+
+// WithRequestMutators adds mutators called in order on every request.
+func WithRequestMutators(mutators ...RequestMutator) ClientOption {
+	return func(o *clientOptions) {
+		o.mutators = append(o.mutators, mutators...)
+	}
+}
+
+// WithResponseObservers adds observers called in order after every request.
+func WithResponseObservers(observers ...ResponseObserver) ClientOption {
+	return func(o *clientOptions) {
+		o.observers = append(o.observers, observers...)
+	}
+}
+
+// WithMiddlewares adds middlewares wrapping the transport of the client, the first one
+// is the outermost and sees the requests first.
+func WithMiddlewares(middlewares ...Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+func newClientOptions(opts []ClientOption) clientOptions {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// wrap returns the transport wrapped by the middlewares.
+func (o clientOptions) wrap(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(o.middlewares) - 1; i >= 0; i-- {
+		transport = o.middlewares[i](transport)
+	}
+	return transport
+}
+
+// RoundTripperFunc is an adapter allowing the use of functions as http.RoundTripper, e.g. in middlewares.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+)
+
+func TestClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + " " + r.Header.Get("X-Middleware")))
+	}))
+	defer server.Close()
+	baseURL, _ := url.Parse(server.URL)
+
+	var calls []string
+	var statuses []int
+	middleware := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				req.Header.Add("X-Middleware", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	provider := NewClientProvider(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(),
+		WithRequestMutators(func(req *http.Request) error {
+			calls = append(calls, "mutator")
+			req.Header.Set("Authorization", "Bearer token")
+			return nil
+		}),
+		WithResponseObservers(func(req *http.Request, resp *http.Response, err error) {
+			require.NoError(t, err)
+			require.Equal(t, "/task", req.URL.Path)
+			calls = append(calls, "observer")
+			statuses = append(statuses, resp.StatusCode)
+		}),
+		WithMiddlewares(middleware("outer"), middleware("inner")),
+	)
+	client, err := provider.BuildClient()
+	require.NoError(t, err)
+
+	resp, err := client.Get("/task")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer token outer", string(resp))
+	require.Equal(t, []string{"mutator", "outer", "inner", "observer"}, calls)
+	require.Equal(t, []int{http.StatusOK}, statuses)
+}
+
+func TestClientOptionsErrors(t *testing.T) {
+	baseURL, _ := url.Parse("http://localhost:8080")
+	errMutator := errors.New("no credentials")
+	client, err := defaultClient(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(),
+		WithRequestMutators(func(*http.Request) error { return errMutator }),
+		WithMiddlewares(func(http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+				t.Fatal("request must not be sent")
+				return nil, nil
+			})
+		}),
+	)
+	require.NoError(t, err)
+	_, err = client.Get("/task")
+	require.ErrorIs(t, err, errMutator)
+
+	errFault := errors.New("injected fault")
+	var observed error
+	client, err = defaultClient(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(),
+		WithResponseObservers(func(_ *http.Request, resp *http.Response, err error) {
+			require.Nil(t, resp)
+			observed = err
+		}),
+		WithMiddlewares(func(http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errFault
+			})
+		}),
+	)
+	require.NoError(t, err)
+	_, err = client.Get("/task")
+	require.ErrorIs(t, err, errFault)
+	require.ErrorIs(t, observed, errFault)
+}
@@ -0,0 +1,143 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil"
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit configures the token bucket limiting the requests sent to an endpoint by all the
+// clients of the process, and the retries of the requests throttled by the endpoint.
+type RateLimit struct {
+	// RequestsPerSecond is the rate at which the bucket is refilled.
+	RequestsPerSecond float64
+	// Burst is the size of the bucket, i.e. the number of requests sent at once.
+	Burst int
+	// MaxRetries is the number of times a request is retried when the endpoint responds
+	// with 429 Too Many Requests.
+	MaxRetries int
+	// InitialBackoff and MaxBackoff bound the exponential backoff between the retries,
+	// the actual wait is drawn at random up to the backoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRateLimit stays below the default rate limits of the task metadata endpoint
+// served by the ECS agent, a steady rate of 40 requests per second with bursts of 60.
+var DefaultRateLimit = RateLimit{
+	RequestsPerSecond: 20,
+	Burst:             40,
+	MaxRetries:        3,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        2 * time.Second,
+}
+
+// WithRateLimit limits the requests of the client with a token bucket shared by all the clients
+// of the process with the same base URL. When clients are configured with different limits the
+// strictest rate and burst are used.
+func WithRateLimit(limit RateLimit) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimit = &limit
+	}
+}
+
+var sharedLimiters = struct {
+	sync.Mutex
+	byURL map[string]*rate.Limiter
+}{byURL: make(map[string]*rate.Limiter)}
+
+// sharedLimiter returns the limiter of the requests sent to baseURL.
+func sharedLimiter(baseURL url.URL, limit RateLimit) *rate.Limiter {
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+	key := baseURL.String()
+	limiter, ok := sharedLimiters.byURL[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)
+		sharedLimiters.byURL[key] = limiter
+		return limiter
+	}
+	if rate.Limit(limit.RequestsPerSecond) < limiter.Limit() {
+		limiter.SetLimit(rate.Limit(limit.RequestsPerSecond))
+	}
+	if limit.Burst < limiter.Burst() {
+		limiter.SetBurst(limit.Burst)
+	}
+	return limiter
+}
+
+type rateLimitedTransport struct {
+	limiter *rate.Limiter
+	limit   RateLimit
+	next    http.RoundTripper
+}
+
+func newRateLimitedTransport(baseURL url.URL, limit RateLimit, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &rateLimitedTransport{
+		limiter: sharedLimiter(baseURL, limit),
+		limit:   limit,
+		next:    next,
+	}
+}
+
+// RoundTrip waits for a token before sending every attempt of the request. Only requests without
+// a body, like the GET requests of the clients, are retried.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.limit.MaxRetries || req.Body != nil {
+			return resp, err
+		}
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(t.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// backoff returns a random wait up to the exponential backoff of the attempt, so that the
+// clients throttled at the same time don't retry at the same time.
+func (t *rateLimitedTransport) backoff(attempt int) time.Duration {
+	backoff := t.limit.MaxBackoff
+	if attempt < 32 {
+		if exp := t.limit.InitialBackoff << uint(attempt); exp > 0 && exp < backoff {
+			backoff = exp
+		}
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
@@ -0,0 +1,120 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecsutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitRetriesThrottledRequests(t *testing.T) {
+	var requests, throttled int32 = 0, 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= atomic.LoadInt32(&throttled) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	baseURL, _ := url.Parse(server.URL)
+
+	limit := RateLimit{RequestsPerSecond: 100, Burst: 10, MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	client, err := defaultClient(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(), WithRateLimit(limit))
+	require.NoError(t, err)
+
+	resp, err := client.Get("/task")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(resp))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+
+	// The retries are exhausted.
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&throttled, 3)
+	_, err = client.Get("/task")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func TestRateLimitSharedByBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	baseURL, _ := url.Parse(server.URL)
+
+	limit := RateLimit{RequestsPerSecond: 5, Burst: 2}
+	first, err := defaultClient(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(), WithRateLimit(limit))
+	require.NoError(t, err)
+	second, err := defaultClient(*baseURL, confighttp.HTTPClientSettings{}, zap.NewNop(), WithRateLimit(limit))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for _, client := range []Client{first, second, first, second} {
+		_, err = client.Get("/task")
+		require.NoError(t, err)
+	}
+	// The bucket of both clients holds 2 tokens, the 2 other requests wait for 200ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+}
+
+func TestSharedLimiterStrictest(t *testing.T) {
+	baseURL, _ := url.Parse("http://169.254.170.2/v4/strictest")
+	limiter := sharedLimiter(*baseURL, RateLimit{RequestsPerSecond: 10, Burst: 5})
+	assert.Same(t, limiter, sharedLimiter(*baseURL, RateLimit{RequestsPerSecond: 20, Burst: 2}))
+	assert.Equal(t, rate.Limit(10), limiter.Limit())
+	assert.Equal(t, 2, limiter.Burst())
+
+	otherURL, _ := url.Parse("http://169.254.170.2/v4/other")
+	assert.NotSame(t, limiter, sharedLimiter(*otherURL, RateLimit{RequestsPerSecond: 10, Burst: 5}))
+}
+
+func TestRateLimitContextDone(t *testing.T) {
+	transport := newRateLimitedTransport(url.URL{Scheme: "http", Host: "localhost", Path: "/context-done"},
+		RateLimit{RequestsPerSecond: 1, Burst: 1}, RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}))
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/context-done", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = transport.RoundTrip(req.WithContext(ctx))
+	assert.Error(t, err)
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	transport := &rateLimitedTransport{limit: RateLimit{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}}
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		backoff := transport.backoff(attempt)
+		assert.GreaterOrEqual(t, backoff, time.Duration(0))
+		assert.Less(t, backoff, max)
+	}
+	assert.Less(t, transport.backoff(100), time.Second)
+}
@@ -21,8 +21,8 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewRestClient(baseEndpoint url.URL, clientSettings confighttp.HTTPClientSettings, logger *zap.Logger) (RestClient, error) {
-	clientProvider := NewClientProvider(baseEndpoint, clientSettings, logger)
+func NewRestClient(baseEndpoint url.URL, clientSettings confighttp.HTTPClientSettings, logger *zap.Logger, opts ...ClientOption) (RestClient, error) {
+	clientProvider := NewClientProvider(baseEndpoint, clientSettings, logger, opts...)
 
 	client, err := clientProvider.BuildClient()
 	if err != nil {
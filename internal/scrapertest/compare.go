@@ -122,15 +122,21 @@ func CompareNumberDataPointSlices(expected, actual pdata.NumberDataPointSlice) e
 		}
 	}
 
-	matchingDPS := make(map[pdata.NumberDataPoint]pdata.NumberDataPoint, actual.Len())
+	// Data points with the same attributes are matched in order of appearance.
+	type dataPointPair struct {
+		expected, actual pdata.NumberDataPoint
+	}
+	matchingDPS := make([]dataPointPair, 0, actual.Len())
+	matched := make([]bool, expected.Len())
 	for j := 0; j < actual.Len(); j++ {
 		adp := actual.At(j)
 		var foundMatch bool
 		for k := 0; k < expected.Len(); k++ {
 			edp := expected.At(k)
-			if reflect.DeepEqual(edp.Attributes().Sort(), adp.Attributes().Sort()) {
+			if !matched[k] && reflect.DeepEqual(edp.Attributes().Sort(), adp.Attributes().Sort()) {
 				foundMatch = true
-				matchingDPS[adp] = edp
+				matched[k] = true
+				matchingDPS = append(matchingDPS, dataPointPair{expected: edp, actual: adp})
 				break
 			}
 		}
@@ -144,9 +150,9 @@ func CompareNumberDataPointSlices(expected, actual pdata.NumberDataPointSlice) e
 		return errs
 	}
 
-	for adp, edp := range matchingDPS {
-		if err := CompareNumberDataPoints(edp, adp); err != nil {
-			return multierr.Combine(fmt.Errorf("datapoint with attributes: %v, does not match expected", adp.Attributes().AsRaw()), err)
+	for _, pair := range matchingDPS {
+		if err := CompareNumberDataPoints(pair.expected, pair.actual); err != nil {
+			return multierr.Combine(fmt.Errorf("datapoint with attributes: %v, does not match expected", pair.actual.Attributes().AsRaw()), err)
 		}
 	}
 	return nil
@@ -251,6 +251,56 @@ func TestCompareMetricSlices(t *testing.T) {
 				reason: "An unpredictable data point value will cause failures if not ignored.",
 			},
 		},
+		{
+			name: "ignore-metric-attribute-value",
+			compareOptions: []CompareOption{
+				IgnoreMetricAttributeValue("connection_id"),
+			},
+			withoutOptions: expectation{
+				err: multierr.Combine(
+					errors.New("datapoints for metric: `sum.one`, do not match expected"),
+					errors.New("metric missing expected datapoint with attributes: map[connection_id:1 state:open]"),
+					errors.New("metric has extra datapoint with attributes: map[connection_id:42 state:open]"),
+				),
+				reason: "An unpredictable attribute value will cause failures if not ignored.",
+			},
+		},
+		{
+			name: "ignore-metric-attribute-value",
+			compareOptions: []CompareOption{
+				IgnoreMetricAttributeValue("connection_id", "sum.two"),
+			},
+			withoutOptions: expectation{
+				err: multierr.Combine(
+					errors.New("datapoints for metric: `sum.one`, do not match expected"),
+					errors.New("metric missing expected datapoint with attributes: map[connection_id:1 state:open]"),
+					errors.New("metric has extra datapoint with attributes: map[connection_id:42 state:open]"),
+				),
+				reason: "An unpredictable attribute value will cause failures if not ignored.",
+			},
+			withOptions: expectation{
+				err: multierr.Combine(
+					errors.New("datapoints for metric: `sum.one`, do not match expected"),
+					errors.New("metric missing expected datapoint with attributes: map[connection_id:1 state:open]"),
+					errors.New("metric has extra datapoint with attributes: map[connection_id:42 state:open]"),
+				),
+				reason: "Attribute values are only ignored for the given metrics.",
+			},
+		},
+		{
+			name: "ignore-data-point-order",
+			compareOptions: []CompareOption{
+				IgnoreDataPointOrder(),
+			},
+			withoutOptions: expectation{
+				err: multierr.Combine(
+					errors.New("datapoints for metric: `sum.one`, do not match expected"),
+					errors.New("datapoint with attributes: map[state:open], does not match expected"),
+					errors.New("metric datapoint IntVal doesn't match expected: 123, actual: 456"),
+				),
+				reason: "Data points with the same attributes are matched in order if the order is not ignored.",
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -32,6 +32,40 @@ func (opt ignoreValues) apply(expected, actual pdata.MetricSlice) {
 	maskMetricSliceValues(actual)
 }
 
+// IgnoreMetricAttributeValue is a CompareOption that clears the value of the
+// attributeName attribute of all data points of metricNames, or of all metrics
+// if no metricNames are given.
+func IgnoreMetricAttributeValue(attributeName string, metricNames ...string) CompareOption {
+	return ignoreMetricAttributeValue{
+		attributeName: attributeName,
+		metricNames:   metricNames,
+	}
+}
+
+type ignoreMetricAttributeValue struct {
+	attributeName string
+	metricNames   []string
+}
+
+func (opt ignoreMetricAttributeValue) apply(expected, actual pdata.MetricSlice) {
+	maskMetricSliceAttributeValues(expected, opt.attributeName, opt.metricNames...)
+	maskMetricSliceAttributeValues(actual, opt.attributeName, opt.metricNames...)
+}
+
+// IgnoreDataPointOrder is a CompareOption that sorts the data points of every
+// metric by attributes and value, so that data points with the same attributes
+// are matched regardless of the order in which they were recorded.
+func IgnoreDataPointOrder() CompareOption {
+	return ignoreDataPointOrder{}
+}
+
+type ignoreDataPointOrder struct{}
+
+func (opt ignoreDataPointOrder) apply(expected, actual pdata.MetricSlice) {
+	sortMetricSliceDataPoints(expected)
+	sortMetricSliceDataPoints(actual)
+}
+
 // maskMetricSliceValues sets all data point values to zero.
 func maskMetricSliceValues(metrics pdata.MetricSlice) {
 	for i := 0; i < metrics.Len(); i++ {
@@ -41,16 +75,7 @@ func maskMetricSliceValues(metrics pdata.MetricSlice) {
 
 // maskMetricValues sets all data point values to zero.
 func maskMetricValues(metric pdata.Metric) {
-	var dataPoints pdata.NumberDataPointSlice
-	switch metric.DataType() {
-	case pdata.MetricDataTypeGauge:
-		dataPoints = metric.Gauge().DataPoints()
-	case pdata.MetricDataTypeSum:
-		dataPoints = metric.Sum().DataPoints()
-	default:
-		panic(fmt.Sprintf("data type not supported: %s", metric.DataType()))
-	}
-	maskDataPointSliceValues(dataPoints)
+	maskDataPointSliceValues(dataPointsOf(metric))
 }
 
 // maskDataPointSliceValues sets all data point values to zero.
@@ -61,3 +86,57 @@ func maskDataPointSliceValues(dataPoints pdata.NumberDataPointSlice) {
 		dataPoint.SetDoubleVal(0)
 	}
 }
+
+// maskMetricSliceAttributeValues sets the value of the attributeName attribute of
+// the data points of metricNames, or of all metrics if no metricNames are given, to
+// an empty string.
+func maskMetricSliceAttributeValues(metrics pdata.MetricSlice, attributeName string, metricNames ...string) {
+	metricNameSet := make(map[string]bool, len(metricNames))
+	for _, metricName := range metricNames {
+		metricNameSet[metricName] = true
+	}
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		if len(metricNames) == 0 || metricNameSet[metric.Name()] {
+			maskDataPointSliceAttributeValues(dataPointsOf(metric), attributeName)
+		}
+	}
+}
+
+// maskDataPointSliceAttributeValues sets the value of the attributeName attribute
+// of all data points to an empty string.
+func maskDataPointSliceAttributeValues(dataPoints pdata.NumberDataPointSlice, attributeName string) {
+	for i := 0; i < dataPoints.Len(); i++ {
+		attributes := dataPoints.At(i).Attributes()
+		if _, ok := attributes.Get(attributeName); ok {
+			attributes.UpdateString(attributeName, "")
+		}
+	}
+}
+
+// sortMetricSliceDataPoints sorts the data points of all metrics by attributes and value.
+func sortMetricSliceDataPoints(metrics pdata.MetricSlice) {
+	for i := 0; i < metrics.Len(); i++ {
+		dataPointsOf(metrics.At(i)).Sort(func(a, b pdata.NumberDataPoint) bool {
+			return dataPointSortKey(a) < dataPointSortKey(b)
+		})
+	}
+}
+
+// dataPointSortKey returns a key ordering data points by attributes, then by value.
+func dataPointSortKey(dataPoint pdata.NumberDataPoint) string {
+	// Maps are printed sorted by key. Only the order being the same for expected and
+	// actual matters, values don't have to be ordered numerically.
+	return fmt.Sprintf("%v %d %v", dataPoint.Attributes().AsRaw(), dataPoint.IntVal(), dataPoint.DoubleVal())
+}
+
+func dataPointsOf(metric pdata.Metric) pdata.NumberDataPointSlice {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeGauge:
+		return metric.Gauge().DataPoints()
+	case pdata.MetricDataTypeSum:
+		return metric.Sum().DataPoints()
+	default:
+		panic(fmt.Sprintf("data type not supported: %s", metric.DataType()))
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downsampleprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/downsampleprocessor"
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// buffer holds the series received during an interval, the data points are written to the
+// metrics emitted at the end of the interval as they are received.
+type buffer struct {
+	gaugeAggregation string
+	md               pdata.Metrics
+	resources        map[string]*resourceBuffer
+}
+
+type resourceBuffer struct {
+	rm        pdata.ResourceMetrics
+	libraries map[string]*libraryBuffer
+}
+
+type libraryBuffer struct {
+	ilm     pdata.InstrumentationLibraryMetrics
+	metrics map[string]*metricBuffer
+}
+
+type metricBuffer struct {
+	metric pdata.Metric
+	series map[string]*series
+}
+
+// series is the data point of a series emitted at the end of the interval. The number of
+// samples and their sum are tracked to compute the mean of gauges.
+type series struct {
+	number    pdata.NumberDataPoint
+	histogram pdata.HistogramDataPoint
+	expHist   pdata.ExponentialHistogramDataPoint
+	summary   pdata.SummaryDataPoint
+	count     int
+	sum       float64
+}
+
+func newBuffer(gaugeAggregation string) *buffer {
+	return &buffer{
+		gaugeAggregation: gaugeAggregation,
+		md:               pdata.NewMetrics(),
+		resources:        map[string]*resourceBuffer{},
+	}
+}
+
+// supported returns whether the data points of the metric can be downsampled. Delta
+// exponential histograms can't be merged and metrics without a temporality can't be
+// aggregated, they are sent as they are received.
+func supported(metric pdata.Metric) bool {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeGauge, pdata.MetricDataTypeSummary:
+		return true
+	case pdata.MetricDataTypeSum:
+		return knownTemporality(metric.Sum().AggregationTemporality())
+	case pdata.MetricDataTypeHistogram:
+		return knownTemporality(metric.Histogram().AggregationTemporality())
+	case pdata.MetricDataTypeExponentialHistogram:
+		return metric.ExponentialHistogram().AggregationTemporality() == pdata.MetricAggregationTemporalityCumulative
+	}
+	return false
+}
+
+func knownTemporality(temporality pdata.MetricAggregationTemporality) bool {
+	return temporality == pdata.MetricAggregationTemporalityCumulative || temporality == pdata.MetricAggregationTemporalityDelta
+}
+
+// add merges the data points of a supported metric into the buffer.
+func (b *buffer) add(resource pdata.Resource, library pdata.InstrumentationLibrary, metric pdata.Metric) {
+	mb := b.metric(resource, library, metric)
+	switch metric.DataType() {
+	case pdata.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			b.addGauge(mb, dps.At(i))
+		}
+	case pdata.MetricDataTypeSum:
+		delta := metric.Sum().AggregationTemporality() == pdata.MetricAggregationTemporalityDelta
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			b.addSum(mb, dps.At(i), delta)
+		}
+	case pdata.MetricDataTypeHistogram:
+		delta := metric.Histogram().AggregationTemporality() == pdata.MetricAggregationTemporalityDelta
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			b.addHistogram(mb, dps.At(i), delta)
+		}
+	case pdata.MetricDataTypeExponentialHistogram:
+		// Only cumulative exponential histograms are supported, the latest value is kept.
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			key := attributesKey(dp.Attributes())
+			s, ok := mb.series[key]
+			if !ok {
+				s = &series{expHist: mb.metric.ExponentialHistogram().DataPoints().AppendEmpty()}
+				mb.series[key] = s
+				dp.CopyTo(s.expHist)
+			} else if dp.Timestamp() >= s.expHist.Timestamp() {
+				dp.CopyTo(s.expHist)
+			}
+		}
+	case pdata.MetricDataTypeSummary:
+		// Summaries are cumulative, the latest value is kept.
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			key := attributesKey(dp.Attributes())
+			s, ok := mb.series[key]
+			if !ok {
+				s = &series{summary: mb.metric.Summary().DataPoints().AppendEmpty()}
+				mb.series[key] = s
+				dp.CopyTo(s.summary)
+			} else if dp.Timestamp() >= s.summary.Timestamp() {
+				dp.CopyTo(s.summary)
+			}
+		}
+	}
+}
+
+// addGauge keeps the latest sample of the series, or the lowest, highest or mean value of
+// its samples with the timestamp of the latest one. A stale sample ends the series, it is kept
+// until a newer sample is received.
+func (b *buffer) addGauge(mb *metricBuffer, dp pdata.NumberDataPoint) {
+	key := attributesKey(dp.Attributes())
+	s, ok := mb.series[key]
+	if !ok {
+		s = &series{number: mb.metric.Gauge().DataPoints().AppendEmpty()}
+		mb.series[key] = s
+		s.reset(dp)
+		return
+	}
+	latest := dp.Timestamp() >= s.number.Timestamp()
+	if b.gaugeAggregation == GaugeAggregationLast || noRecordedValue(dp.Flags()) || noRecordedValue(s.number.Flags()) {
+		if latest {
+			s.reset(dp)
+		}
+		return
+	}
+
+	value := numberValue(dp)
+	switch b.gaugeAggregation {
+	case GaugeAggregationMean:
+		s.count++
+		s.sum += value
+		s.number.SetDoubleVal(s.sum / float64(s.count))
+	case GaugeAggregationMin:
+		if value < numberValue(s.number) {
+			setNumberValue(s.number, dp)
+		}
+	case GaugeAggregationMax:
+		if value > numberValue(s.number) {
+			setNumberValue(s.number, dp)
+		}
+	}
+	if latest {
+		s.number.SetTimestamp(dp.Timestamp())
+	}
+}
+
+// addSum keeps the latest value of cumulative series and adds up the values of delta series.
+func (b *buffer) addSum(mb *metricBuffer, dp pdata.NumberDataPoint, delta bool) {
+	key := attributesKey(dp.Attributes())
+	s, ok := mb.series[key]
+	if !ok {
+		s = &series{number: mb.metric.Sum().DataPoints().AppendEmpty()}
+		mb.series[key] = s
+		dp.CopyTo(s.number)
+		return
+	}
+	if !delta {
+		if dp.Timestamp() >= s.number.Timestamp() {
+			dp.CopyTo(s.number)
+		}
+		return
+	}
+
+	if dp.Type() == pdata.MetricValueTypeInt && s.number.Type() == pdata.MetricValueTypeInt {
+		s.number.SetIntVal(s.number.IntVal() + dp.IntVal())
+	} else {
+		s.number.SetDoubleVal(numberValue(s.number) + numberValue(dp))
+	}
+	extendTimestamps(s.number, dp.StartTimestamp(), dp.Timestamp())
+}
+
+// addHistogram keeps the latest value of cumulative series and merges the buckets of delta
+// series. A delta histogram whose bounds changed replaces the previous value of the series.
+func (b *buffer) addHistogram(mb *metricBuffer, dp pdata.HistogramDataPoint, delta bool) {
+	key := attributesKey(dp.Attributes())
+	s, ok := mb.series[key]
+	if !ok {
+		s = &series{histogram: mb.metric.Histogram().DataPoints().AppendEmpty()}
+		mb.series[key] = s
+		dp.CopyTo(s.histogram)
+		return
+	}
+	if !delta || !sameBuckets(s.histogram, dp) {
+		if dp.Timestamp() >= s.histogram.Timestamp() {
+			dp.CopyTo(s.histogram)
+		}
+		return
+	}
+
+	s.histogram.SetCount(s.histogram.Count() + dp.Count())
+	s.histogram.SetSum(s.histogram.Sum() + dp.Sum())
+	counts := make([]uint64, len(dp.BucketCounts()))
+	for i, c := range dp.BucketCounts() {
+		counts[i] = s.histogram.BucketCounts()[i] + c
+	}
+	s.histogram.SetBucketCounts(counts)
+	if dp.StartTimestamp() < s.histogram.StartTimestamp() {
+		s.histogram.SetStartTimestamp(dp.StartTimestamp())
+	}
+	if dp.Timestamp() > s.histogram.Timestamp() {
+		s.histogram.SetTimestamp(dp.Timestamp())
+	}
+}
+
+// metric returns the buffer of the metric, creating the resource, instrumentation library and
+// metric in the emitted metrics the first time they are received.
+func (b *buffer) metric(resource pdata.Resource, library pdata.InstrumentationLibrary, metric pdata.Metric) *metricBuffer {
+	resourceKey := attributesKey(resource.Attributes())
+	rb, ok := b.resources[resourceKey]
+	if !ok {
+		rb = &resourceBuffer{
+			rm:        b.md.ResourceMetrics().AppendEmpty(),
+			libraries: map[string]*libraryBuffer{},
+		}
+		resource.CopyTo(rb.rm.Resource())
+		b.resources[resourceKey] = rb
+	}
+
+	libraryKey := library.Name() + "\x00" + library.Version()
+	lb, ok := rb.libraries[libraryKey]
+	if !ok {
+		lb = &libraryBuffer{
+			ilm:     rb.rm.InstrumentationLibraryMetrics().AppendEmpty(),
+			metrics: map[string]*metricBuffer{},
+		}
+		library.CopyTo(lb.ilm.InstrumentationLibrary())
+		rb.libraries[libraryKey] = lb
+	}
+
+	metricKey := metricKey(metric)
+	mb, ok := lb.metrics[metricKey]
+	if !ok {
+		mb = &metricBuffer{
+			metric: lb.ilm.Metrics().AppendEmpty(),
+			series: map[string]*series{},
+		}
+		mb.metric.SetName(metric.Name())
+		mb.metric.SetDescription(metric.Description())
+		mb.metric.SetUnit(metric.Unit())
+		mb.metric.SetDataType(metric.DataType())
+		switch metric.DataType() {
+		case pdata.MetricDataTypeSum:
+			mb.metric.Sum().SetAggregationTemporality(metric.Sum().AggregationTemporality())
+			mb.metric.Sum().SetIsMonotonic(metric.Sum().IsMonotonic())
+		case pdata.MetricDataTypeHistogram:
+			mb.metric.Histogram().SetAggregationTemporality(metric.Histogram().AggregationTemporality())
+		case pdata.MetricDataTypeExponentialHistogram:
+			mb.metric.ExponentialHistogram().SetAggregationTemporality(metric.ExponentialHistogram().AggregationTemporality())
+		}
+		lb.metrics[metricKey] = mb
+	}
+	return mb
+}
+
+// reset sets the series to a single gauge sample.
+func (s *series) reset(dp pdata.NumberDataPoint) {
+	dp.CopyTo(s.number)
+	s.count = 1
+	s.sum = numberValue(dp)
+}
+
+// metricKey identifies the metrics whose data points can be merged.
+func metricKey(metric pdata.Metric) string {
+	key := []string{metric.Name(), metric.Unit(), metric.DataType().String()}
+	switch metric.DataType() {
+	case pdata.MetricDataTypeSum:
+		key = append(key, metric.Sum().AggregationTemporality().String())
+		if metric.Sum().IsMonotonic() {
+			key = append(key, "monotonic")
+		}
+	case pdata.MetricDataTypeHistogram:
+		key = append(key, metric.Histogram().AggregationTemporality().String())
+	}
+	return strings.Join(key, "\x00")
+}
+
+// attributesKey returns a key identifying the attributes, regardless of their order.
+func attributesKey(attrs pdata.AttributeMap) string {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pdata.AttributeValue) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(v.Type().String())
+		b.WriteByte(0)
+		b.WriteString(v.AsString())
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+func noRecordedValue(flags pdata.MetricDataPointFlags) bool {
+	return flags.HasFlag(pdata.MetricDataPointFlagNoRecordedValue)
+}
+
+func numberValue(dp pdata.NumberDataPoint) float64 {
+	if dp.Type() == pdata.MetricValueTypeInt {
+		return float64(dp.IntVal())
+	}
+	return dp.DoubleVal()
+}
+
+// setNumberValue sets the value of dst to the value of src, keeping its type.
+func setNumberValue(dst, src pdata.NumberDataPoint) {
+	if src.Type() == pdata.MetricValueTypeInt {
+		dst.SetIntVal(src.IntVal())
+	} else {
+		dst.SetDoubleVal(src.DoubleVal())
+	}
+}
+
+// extendTimestamps widens the time interval of dp to include [start, end].
+func extendTimestamps(dp pdata.NumberDataPoint, start, end pdata.Timestamp) {
+	if start < dp.StartTimestamp() {
+		dp.SetStartTimestamp(start)
+	}
+	if end > dp.Timestamp() {
+		dp.SetTimestamp(end)
+	}
+}
+
+func sameBuckets(a, b pdata.HistogramDataPoint) bool {
+	if len(a.ExplicitBounds()) != len(b.ExplicitBounds()) || len(a.BucketCounts()) != len(b.BucketCounts()) {
+		return false
+	}
+	for i, bound := range a.ExplicitBounds() {
+		if bound != b.ExplicitBounds()[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downsampleprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/downsampleprocessor"
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	// GaugeAggregationLast emits the latest sample of a gauge series.
+	GaugeAggregationLast = "last"
+	// GaugeAggregationMean emits the mean of the samples of a gauge series.
+	GaugeAggregationMean = "mean"
+	// GaugeAggregationMin emits the lowest sample of a gauge series.
+	GaugeAggregationMin = "min"
+	// GaugeAggregationMax emits the highest sample of a gauge series.
+	GaugeAggregationMax = "max"
+)
+
+// Config defines the configuration for the processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Interval is the interval at which the buffered series are emitted. Defaults to 60s.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// GaugeAggregation defines how the samples of a gauge series received during an interval
+	// are combined, possible values are: last (default), mean, min or max.
+	GaugeAggregation string `mapstructure:"gauge_aggregation"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks whether the input configuration has all of the required fields for the processor.
+// An error is returned if there are any invalid inputs.
+func (cfg *Config) Validate() error {
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval has to be positive, got %v", cfg.Interval)
+	}
+	switch cfg.GaugeAggregation {
+	case GaugeAggregationLast, GaugeAggregationMean, GaugeAggregationMin, GaugeAggregationMax:
+	default:
+		return fmt.Errorf("invalid gauge_aggregation %q: can be either %q, %q, %q or %q", cfg.GaugeAggregation,
+			GaugeAggregationLast, GaugeAggregationMean, GaugeAggregationMin, GaugeAggregationMax)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downsampleprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/downsampleprocessor"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+// downsampleProcessor buffers the series of the metrics it receives and sends a single data
+// point per series every interval.
+type downsampleProcessor struct {
+	logger           *zap.Logger
+	next             consumer.Metrics
+	interval         time.Duration
+	gaugeAggregation string
+
+	mu  sync.Mutex
+	buf *buffer
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newDownsampleProcessor(config *Config, logger *zap.Logger, next consumer.Metrics) *downsampleProcessor {
+	return &downsampleProcessor{
+		logger:           logger,
+		next:             next,
+		interval:         config.Interval,
+		gaugeAggregation: config.GaugeAggregation,
+		buf:              newBuffer(config.GaugeAggregation),
+		done:             make(chan struct{}),
+	}
+}
+
+// Start is invoked during service startup, the buffered series are sent every interval.
+func (p *downsampleProcessor) Start(context.Context, component.Host) error {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.flush(context.Background())
+			}
+		}
+	}()
+	return nil
+}
+
+// processMetrics implements the ProcessMetricsFunc type. The metrics that can be downsampled
+// are buffered, the other ones are sent right away.
+func (p *downsampleProcessor) processMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	p.mu.Lock()
+	md.ResourceMetrics().RemoveIf(func(rm pdata.ResourceMetrics) bool {
+		rm.InstrumentationLibraryMetrics().RemoveIf(func(ilm pdata.InstrumentationLibraryMetrics) bool {
+			ilm.Metrics().RemoveIf(func(metric pdata.Metric) bool {
+				if !supported(metric) {
+					return false
+				}
+				p.buf.add(rm.Resource(), ilm.InstrumentationLibrary(), metric)
+				return true
+			})
+			return ilm.Metrics().Len() == 0
+		})
+		return rm.InstrumentationLibraryMetrics().Len() == 0
+	})
+	p.mu.Unlock()
+
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+// flush sends the series buffered since the previous flush.
+func (p *downsampleProcessor) flush(ctx context.Context) {
+	p.mu.Lock()
+	md := p.buf.md
+	p.buf = newBuffer(p.gaugeAggregation)
+	p.mu.Unlock()
+
+	if md.ResourceMetrics().Len() == 0 {
+		return
+	}
+	if err := p.next.ConsumeMetrics(ctx, md); err != nil {
+		p.logger.Error("Failed to send downsampled metrics", zap.Int("data_points", md.DataPointCount()), zap.Error(err))
+	}
+}
+
+// Shutdown is invoked during service shutdown, the series buffered since the previous interval
+// are sent.
+func (p *downsampleProcessor) Shutdown(ctx context.Context) error {
+	close(p.done)
+	p.wg.Wait()
+	p.flush(ctx)
+	return nil
+}
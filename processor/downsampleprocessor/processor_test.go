@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package downsampleprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+func ts(seconds int) pdata.Timestamp {
+	return pdata.NewTimestampFromTime(time.Unix(1000+int64(seconds), 0))
+}
+
+// newMetrics returns metrics with a single resource and metric, to which the data points are added.
+func newMetrics(name string, dataType pdata.MetricDataType) (pdata.Metrics, pdata.Metric) {
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "test")
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName("scraper")
+	metric := ilm.Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	return md, metric
+}
+
+func newGauge(value float64, seconds int, flags ...pdata.MetricDataPointFlag) pdata.Metrics {
+	md, metric := newMetrics("gauge", pdata.MetricDataTypeGauge)
+	dp := metric.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts(seconds))
+	dp.SetDoubleVal(value)
+	dp.SetFlags(pdata.NewMetricDataPointFlags(flags...))
+	dp.Attributes().InsertString("host", "a")
+	return md
+}
+
+func newSum(temporality pdata.MetricAggregationTemporality, value int64, start, seconds int) pdata.Metrics {
+	md, metric := newMetrics("sum", pdata.MetricDataTypeSum)
+	metric.Sum().SetAggregationTemporality(temporality)
+	metric.Sum().SetIsMonotonic(true)
+	dp := metric.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(ts(start))
+	dp.SetTimestamp(ts(seconds))
+	dp.SetIntVal(value)
+	return md
+}
+
+func newDeltaHistogram(bounds []float64, counts []uint64, start, seconds int) pdata.Metrics {
+	md, metric := newMetrics("histogram", pdata.MetricDataTypeHistogram)
+	metric.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	dp := metric.Histogram().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(ts(start))
+	dp.SetTimestamp(ts(seconds))
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(counts)
+	var count uint64
+	for _, c := range counts {
+		count += c
+	}
+	dp.SetCount(count)
+	dp.SetSum(float64(count))
+	return md
+}
+
+func newTestProcessor(t *testing.T, gaugeAggregation string) (*downsampleProcessor, *consumertest.MetricsSink) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.GaugeAggregation = gaugeAggregation
+	p := newDownsampleProcessor(cfg, zap.NewNop(), sink)
+	t.Cleanup(func() {
+		assert.NoError(t, p.Shutdown(context.Background()))
+	})
+	return p, sink
+}
+
+// process sends each of the metrics to the processor, checking they are all buffered, and
+// returns the metrics flushed afterwards.
+func process(t *testing.T, p *downsampleProcessor, sink *consumertest.MetricsSink, mds ...pdata.Metrics) pdata.Metrics {
+	for _, md := range mds {
+		_, err := p.processMetrics(context.Background(), md)
+		require.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	}
+	sink.Reset()
+	p.flush(context.Background())
+	require.Len(t, sink.AllMetrics(), 1)
+	return sink.AllMetrics()[0]
+}
+
+func singleMetric(t *testing.T, md pdata.Metrics) pdata.Metric {
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]interface{}{"service.name": "test"}, rm.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rm.InstrumentationLibraryMetrics().Len())
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	assert.Equal(t, "scraper", ilm.InstrumentationLibrary().Name())
+	require.Equal(t, 1, ilm.Metrics().Len())
+	return ilm.Metrics().At(0)
+}
+
+func TestGaugeAggregations(t *testing.T) {
+	tests := []struct {
+		aggregation string
+		want        float64
+	}{
+		{aggregation: GaugeAggregationLast, want: 2},
+		{aggregation: GaugeAggregationMean, want: 3},
+		{aggregation: GaugeAggregationMin, want: 1},
+		{aggregation: GaugeAggregationMax, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			p, sink := newTestProcessor(t, tt.aggregation)
+			// The samples are not received in order.
+			md := process(t, p, sink, newGauge(1, 10), newGauge(2, 30), newGauge(6, 20))
+
+			metric := singleMetric(t, md)
+			assert.Equal(t, "gauge", metric.Name())
+			require.Equal(t, 1, metric.Gauge().DataPoints().Len())
+			dp := metric.Gauge().DataPoints().At(0)
+			assert.Equal(t, tt.want, dp.DoubleVal())
+			assert.Equal(t, ts(30), dp.Timestamp())
+			assert.Equal(t, map[string]interface{}{"host": "a"}, dp.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestGaugeStaleSample(t *testing.T) {
+	p, sink := newTestProcessor(t, GaugeAggregationMean)
+	md := process(t, p, sink, newGauge(1, 10), newGauge(0, 20, pdata.MetricDataPointFlagNoRecordedValue))
+	dp := singleMetric(t, md).Gauge().DataPoints().At(0)
+	assert.True(t, dp.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue))
+
+	// A sample after the stale marker starts the series again.
+	md = process(t, p, sink, newGauge(0, 20, pdata.MetricDataPointFlagNoRecordedValue), newGauge(4, 30), newGauge(2, 40))
+	dp = singleMetric(t, md).Gauge().DataPoints().At(0)
+	assert.False(t, dp.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue))
+	assert.Equal(t, 3.0, dp.DoubleVal())
+	assert.Equal(t, ts(40), dp.Timestamp())
+}
+
+func TestCumulativeSum(t *testing.T) {
+	p, sink := newTestProcessor(t, GaugeAggregationLast)
+	md := process(t, p, sink,
+		newSum(pdata.MetricAggregationTemporalityCumulative, 10, 0, 10),
+		newSum(pdata.MetricAggregationTemporalityCumulative, 30, 0, 30),
+		newSum(pdata.MetricAggregationTemporalityCumulative, 20, 0, 20))
+
+	metric := singleMetric(t, md)
+	assert.Equal(t, pdata.MetricAggregationTemporalityCumulative, metric.Sum().AggregationTemporality())
+	assert.True(t, metric.Sum().IsMonotonic())
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	dp := metric.Sum().DataPoints().At(0)
+	assert.Equal(t, int64(30), dp.IntVal())
+	assert.Equal(t, ts(0), dp.StartTimestamp())
+	assert.Equal(t, ts(30), dp.Timestamp())
+}
+
+func TestDeltaSum(t *testing.T) {
+	p, sink := newTestProcessor(t, GaugeAggregationLast)
+	md := process(t, p, sink,
+		newSum(pdata.MetricAggregationTemporalityDelta, 2, 10, 20),
+		newSum(pdata.MetricAggregationTemporalityDelta, 3, 0, 10),
+		newSum(pdata.MetricAggregationTemporalityDelta, 5, 20, 30))
+
+	metric := singleMetric(t, md)
+	assert.Equal(t, pdata.MetricAggregationTemporalityDelta, metric.Sum().AggregationTemporality())
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	dp := metric.Sum().DataPoints().At(0)
+	assert.Equal(t, pdata.MetricValueTypeInt, dp.Type())
+	assert.Equal(t, int64(10), dp.IntVal())
+	assert.Equal(t, ts(0), dp.StartTimestamp())
+	assert.Equal(t, ts(30), dp.Timestamp())
+}
+
+func TestDeltaHistogram(t *testing.T) {
+	p, sink := newTestProcessor(t, GaugeAggregationLast)
+	md := process(t, p, sink,
+		newDeltaHistogram([]float64{1, 5}, []uint64{1, 2, 3}, 0, 10),
+		newDeltaHistogram([]float64{1, 5}, []uint64{4, 5, 6}, 10, 20))
+
+	metric := singleMetric(t, md)
+	require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+	dp := metric.Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{5, 7, 9}, dp.BucketCounts())
+	assert.Equal(t, uint64(21), dp.Count())
+	assert.Equal(t, 21.0, dp.Sum())
+	assert.Equal(t, ts(0), dp.StartTimestamp())
+	assert.Equal(t, ts(20), dp.Timestamp())
+
+	// The latest histogram is kept when the bounds change.
+	md = process(t, p, sink,
+		newDeltaHistogram([]float64{1, 5}, []uint64{1, 2, 3}, 0, 10),
+		newDeltaHistogram([]float64{2}, []uint64{4, 5}, 10, 20))
+	dp = singleMetric(t, md).Histogram().DataPoints().At(0)
+	assert.Equal(t, []float64{2}, dp.ExplicitBounds())
+	assert.Equal(t, []uint64{4, 5}, dp.BucketCounts())
+}
+
+func TestSeriesIdentity(t *testing.T) {
+	p, sink := newTestProcessor(t, GaugeAggregationLast)
+
+	first, metric := newMetrics("gauge", pdata.MetricDataTypeGauge)
+	first.ResourceMetrics().At(0).Resource().Attributes().InsertString("host.name", "a")
+	dp := metric.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts(10))
+	dp.SetDoubleVal(1)
+	dp.Attributes().InsertString("a", "1")
+	dp.Attributes().InsertString("b", "2")
+
+	// The order of the attributes doesn't matter.
+	second, metric := newMetrics("gauge", pdata.MetricDataTypeGauge)
+	second.ResourceMetrics().At(0).Resource().Attributes().InsertString("host.name", "a")
+	dp = metric.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts(20))
+	dp.SetDoubleVal(2)
+	dp.Attributes().InsertString("b", "2")
+	dp.Attributes().InsertString("a", "1")
+
+	// The series of another resource is kept apart.
+	other, metric := newMetrics("gauge", pdata.MetricDataTypeGauge)
+	other.ResourceMetrics().At(0).Resource().Attributes().InsertString("host.name", "b")
+	dp = metric.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts(20))
+	dp.SetDoubleVal(3)
+	dp.Attributes().InsertString("a", "1")
+	dp.Attributes().InsertString("b", "2")
+
+	md := process(t, p, sink, first, second, other)
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+	assert.Equal(t, 2, md.DataPointCount())
+	for i, want := range []float64{2, 3} {
+		metrics := md.ResourceMetrics().At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		require.Equal(t, 1, metrics.Len())
+		assert.Equal(t, want, metrics.At(0).Gauge().DataPoints().At(0).DoubleVal())
+	}
+}
+
+func TestUnsupportedMetricsPassThrough(t *testing.T) {
+	p, _ := newTestProcessor(t, GaugeAggregationLast)
+
+	md, metric := newMetrics("delta_exponential_histogram", pdata.MetricDataTypeExponentialHistogram)
+	metric.ExponentialHistogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	metric.ExponentialHistogram().DataPoints().AppendEmpty().SetCount(1)
+	ilm := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0)
+	gauge := ilm.Metrics().AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetDataType(pdata.MetricDataTypeGauge)
+	gauge.Gauge().DataPoints().AppendEmpty().SetDoubleVal(1)
+
+	out, err := p.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, "delta_exponential_histogram", singleMetric(t, out).Name())
+	assert.Equal(t, 1, p.buf.md.DataPointCount())
+}
+
+func TestFlushEveryInterval(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Interval = 10 * time.Millisecond
+	p := newDownsampleProcessor(cfg, zap.NewNop(), sink)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	_, err := p.processMetrics(context.Background(), newGauge(1, 10))
+	require.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	assert.Eventually(t, func() bool { return sink.DataPointCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// The series buffered since the last interval are sent on shutdown.
+	_, err = p.processMetrics(context.Background(), newGauge(2, 20))
+	require.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, 2, sink.DataPointCount())
+}
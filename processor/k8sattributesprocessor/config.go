@@ -78,6 +78,12 @@ type ExtractConfig struct {
 	// It is a list of FieldExtractConfig type. See FieldExtractConfig
 	// documentation for more details.
 	Labels []FieldExtractConfig `mapstructure:"labels"`
+
+	// Owners allows extracting data from the objects owning a pod, such as
+	// custom resources managed by operators, and record it as resource attributes.
+	// It is a list of OwnerExtractConfig type. See OwnerExtractConfig
+	// documentation for more details.
+	Owners []OwnerExtractConfig `mapstructure:"owners"`
 }
 
 // FieldExtractConfig allows specifying an extraction rule to extract a value from exactly one field.
@@ -126,6 +132,37 @@ type FieldExtractConfig struct {
 	From string `mapstructure:"from"`
 }
 
+// OwnerExtractConfig allows specifying an extraction rule to extract a value from
+// an object owning a pod.
+//
+// The controller owner references of a pod are followed up, through the well-known
+// controllers (ReplicaSet, Deployment, StatefulSet, DaemonSet, Job and CronJob) and
+// the owners matched by other rules, until an owner matches the group and kind of the rule.
+// For example, the following rule adds the name of the Argo Rollout managing a pod:
+//
+//   owners:
+//     - tag_name: argo.rollout.name
+//       group: argoproj.io
+//       kind: Rollout
+//
+// The processor needs the permission to get the configured resources.
+type OwnerExtractConfig struct {
+	// TagName represents the name of the tag that will be added to the span.
+	// It defaults to k8s.<lowercase kind>.name when field_path is not set.
+	TagName string `mapstructure:"tag_name"`
+	// Group, Version and Kind identify the owner. The version of the owner
+	// reference is used when Version is not set.
+	Group   string `mapstructure:"group"`
+	Version string `mapstructure:"version"`
+	Kind    string `mapstructure:"kind"`
+	// Resource is the plural resource name of the owner.
+	// It defaults to the lowercase kind followed by "s".
+	Resource string `mapstructure:"resource"`
+	// FieldPath is the dot-separated path of the scalar field to extract from the owner.
+	// The default is metadata.name.
+	FieldPath string `mapstructure:"field_path"`
+}
+
 // FilterConfig section allows specifying filters to filter
 // pods by labels, fields, namespaces, nodes, etc.
 type FilterConfig struct {
@@ -68,6 +68,10 @@ func TestLoadConfig(t *testing.T) {
 					{TagName: "l1", Key: "label1", From: "pod"},
 					{TagName: "l2", Key: "label2", Regex: "field=(?P<value>.+)", From: kube.MetadataFromPod},
 				},
+				Owners: []OwnerExtractConfig{
+					{Group: "argoproj.io", Kind: "Rollout"},
+					{TagName: "knative.revision.generation", Group: "serving.knative.dev", Version: "v1", Kind: "Revision", Resource: "revisions", FieldPath: "metadata.generation"},
+				},
 			},
 			Filter: FilterConfig{
 				Namespace:      "ns2",
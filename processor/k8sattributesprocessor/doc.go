@@ -88,9 +88,27 @@
 //	  regex: field=(?P<value>.+)
 //	  from: pod
 
+//Attributes can also be extracted from the objects owning a pod, for example custom resources managed by operators
+//like Argo Rollouts or Knative, via the "owners" key. The controller owner references of a pod are followed up through
+//ReplicaSets, Deployments, StatefulSets, DaemonSets, Jobs, CronJobs and the kinds matched by other rules until an owner
+//matches the group and kind of a rule. Each item is specified as a config of tag_name, group, version, kind, resource
+//(the plural resource name, defaults to the lowercase kind followed by "s") and field_path (the dot-separated path of
+//the field to extract, defaults to metadata.name). The tag_name defaults to `k8s.<lowercase kind>.name` when no
+//field_path is set. Fetched owners are cached for 5 minutes, and owners that could not be fetched for 1 minute.
+//
+//owners:
+//  - group: argoproj.io # extracts the name of the Argo Rollout owning the pod and inserts it as a tag with key `k8s.rollout.name`
+//	  kind: Rollout
+//  - tag_name: knative.revision.generation # extracts the generation of the Knative Revision owning the pod
+//	  group: serving.knative.dev
+//	  kind: Revision
+//	  field_path: metadata.generation
+
 // RBAC
 //
 // TODO: mention the required RBAC rules.
+// When owner rules are configured, the processor also needs the "get" permission on the
+// configured resources and on the ReplicaSets, Deployments, StatefulSets, DaemonSets, Jobs and CronJobs in between.
 //
 // Config
 //
@@ -27,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -40,12 +42,17 @@ type WatchClient struct {
 	deleteMut         sync.Mutex
 	logger            *zap.Logger
 	kc                kubernetes.Interface
+	dc                dynamic.Interface
 	informer          cache.SharedInformer
 	namespaceInformer cache.SharedInformer
 	deploymentRegex   *regexp.Regexp
 	deleteQueue       []deleteRequest
 	stopCh            chan struct{}
 
+	// A cache of the objects owning pods, keyed by UID.
+	ownersMut sync.Mutex
+	owners    map[types.UID]*cachedOwner
+
 	// A map containing Pod related data, used to associate them with resources.
 	// Key can be either an IP address or Pod UID
 	Pods         map[PodIdentifier]*Pod
@@ -64,7 +71,7 @@ type WatchClient struct {
 var dRegex = regexp.MustCompile(`^(.*)-[0-9a-zA-Z]*-[0-9a-zA-Z]*$`)
 
 // New initializes a new k8s Client.
-func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules, filters Filters, associations []Association, exclude Excludes, newClientSet APIClientsetProvider, newInformer InformerProvider, newNamespaceInformer InformerProviderNamespace) (Client, error) {
+func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules, filters Filters, associations []Association, exclude Excludes, newClientSet APIClientsetProvider, newInformer InformerProvider, newNamespaceInformer InformerProviderNamespace, newDynamicClient DynamicClientProvider) (Client, error) {
 	c := &WatchClient{
 		logger:          logger,
 		Rules:           rules,
@@ -88,6 +95,17 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 	}
 	c.kc = kc
 
+	if len(c.Rules.Owners) > 0 {
+		if newDynamicClient == nil {
+			newDynamicClient = k8sconfig.MakeDynamicClient
+		}
+		c.dc, err = newDynamicClient(apiCfg)
+		if err != nil {
+			return nil, err
+		}
+		c.owners = map[types.UID]*cachedOwner{}
+	}
+
 	labelSelector, fieldSelector, err := selectorsFromFilters(c.Filters)
 	if err != nil {
 		return nil, err
@@ -237,6 +255,8 @@ func (c *WatchClient) deleteLoop(interval time.Duration, gracePeriod time.Durati
 			observability.RecordPodTableSize(int64(podTableSize))
 			c.m.Unlock()
 
+			c.pruneOwners(now)
+
 		case <-c.stopCh:
 			return
 		}
@@ -341,6 +361,10 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 			}
 		}
 	}
+
+	if len(c.Rules.Owners) > 0 {
+		c.extractOwnerAttributes(pod, tags)
+	}
 	return tags
 }
 
@@ -21,6 +21,7 @@ import (
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -44,6 +45,9 @@ var (
 	// TODO: move these to config with default values
 	defaultPodDeleteGracePeriod = time.Second * 120
 	watchSyncPeriod             = time.Minute * 5
+	ownerCacheTTL               = time.Minute * 5
+	ownerMissTTL                = time.Minute
+	ownerGetTimeout             = time.Second * 5
 )
 
 // Client defines the main interface that allows querying pods by metadata.
@@ -55,12 +59,16 @@ type Client interface {
 }
 
 // ClientProvider defines a func type that returns a new Client.
-type ClientProvider func(*zap.Logger, k8sconfig.APIConfig, ExtractionRules, Filters, []Association, Excludes, APIClientsetProvider, InformerProvider, InformerProviderNamespace) (Client, error)
+type ClientProvider func(*zap.Logger, k8sconfig.APIConfig, ExtractionRules, Filters, []Association, Excludes, APIClientsetProvider, InformerProvider, InformerProviderNamespace, DynamicClientProvider) (Client, error)
 
 // APIClientsetProvider defines a func type that initializes and return a new kubernetes
 // Clientset object.
 type APIClientsetProvider func(config k8sconfig.APIConfig) (kubernetes.Interface, error)
 
+// DynamicClientProvider defines a func type that initializes and return a new kubernetes
+// dynamic client, used to fetch the objects owning pods.
+type DynamicClientProvider func(config k8sconfig.APIConfig) (dynamic.Interface, error)
+
 // Pod represents a kubernetes pod.
 type Pod struct {
 	Name       string
@@ -148,6 +156,7 @@ type ExtractionRules struct {
 
 	Annotations []FieldExtractionRule
 	Labels      []FieldExtractionRule
+	Owners      []OwnerExtractionRule
 }
 
 // FieldExtractionRule is used to specify which fields to extract from pod fields
@@ -169,6 +178,22 @@ type FieldExtractionRule struct {
 	From string
 }
 
+// OwnerExtractionRule is used to specify which field to extract from an object
+// owning a pod, directly or through other owners, and inject into spans as attributes.
+type OwnerExtractionRule struct {
+	// Name is used to as the Span tag name.
+	Name string
+	// Group, Version and Kind identify the owner the field is extracted from.
+	// An empty Version matches the version set in the owner reference.
+	Group   string
+	Version string
+	Kind    string
+	// Resource is the plural resource name used to fetch the owner.
+	Resource string
+	// FieldPath is the path of the field to extract, e.g. [metadata name].
+	FieldPath []string
+}
+
 // Associations represent a list of rules for Pod metadata associations with resources
 type Associations struct {
 	Associations []Association
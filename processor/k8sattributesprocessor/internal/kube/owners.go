@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sattributesprocessor/internal/kube"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxOwnerDepth bounds the number of owner references followed from a pod.
+const maxOwnerDepth = 8
+
+// builtinOwners are the well-known controllers that are followed when looking for
+// the owners matching an extraction rule, e.g. the ReplicaSet between a pod and an Argo Rollout.
+var builtinOwners = map[schema.GroupKind]string{
+	{Group: "apps", Kind: "ReplicaSet"}:  "replicasets",
+	{Group: "apps", Kind: "Deployment"}:  "deployments",
+	{Group: "apps", Kind: "StatefulSet"}: "statefulsets",
+	{Group: "apps", Kind: "DaemonSet"}:   "daemonsets",
+	{Group: "batch", Kind: "Job"}:        "jobs",
+	{Group: "batch", Kind: "CronJob"}:    "cronjobs",
+}
+
+// cachedOwner is an owner fetched from the API server, or the error fetching it. Errors are
+// cached for less time, so that a missing owner or denied access doesn't cost a request per pod event.
+type cachedOwner struct {
+	obj       *unstructured.Unstructured
+	err       error
+	expiresAt time.Time
+}
+
+// extractOwnerAttributes walks up the controller owner references of the pod and
+// adds the fields selected by the owner extraction rules to tags. The walk stops at
+// the first owner that is neither a well-known controller nor matched by a rule.
+func (c *WatchClient) extractOwnerAttributes(pod *api_v1.Pod, tags map[string]string) {
+	ref := meta_v1.GetControllerOf(pod)
+	for depth := 0; ref != nil && depth < maxOwnerDepth; depth++ {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			c.logger.Debug("invalid owner reference", zap.String("apiVersion", ref.APIVersion), zap.Error(err))
+			return
+		}
+
+		gvr := gv.WithResource(builtinOwners[gv.WithKind(ref.Kind).GroupKind()])
+		var rules []OwnerExtractionRule
+		for _, r := range c.Rules.Owners {
+			if r.Group == gv.Group && r.Kind == ref.Kind {
+				rules = append(rules, r)
+				gvr.Resource = r.Resource
+				if r.Version != "" {
+					gvr.Version = r.Version
+				}
+			}
+		}
+		if gvr.Resource == "" {
+			return
+		}
+
+		owner, err := c.getOwner(gvr, pod.GetNamespace(), ref)
+		if err != nil {
+			c.logger.Debug("failed to get pod owner", zap.String("kind", ref.Kind), zap.String("name", ref.Name), zap.Error(err))
+			return
+		}
+		for _, r := range rules {
+			v, found, err := unstructured.NestedFieldNoCopy(owner.Object, r.FieldPath...)
+			if err != nil || !found {
+				continue
+			}
+			switch v.(type) {
+			case string, bool, int64, float64:
+				tags[r.Name] = fmt.Sprint(v)
+			}
+		}
+		ref = meta_v1.GetControllerOf(owner)
+	}
+}
+
+// getOwner returns the object referenced by ref, from the cache if it has been
+// fetched recently. It is called from the pod informer handlers, the request to the
+// API server is bounded by ownerGetTimeout.
+func (c *WatchClient) getOwner(gvr schema.GroupVersionResource, namespace string, ref *meta_v1.OwnerReference) (*unstructured.Unstructured, error) {
+	now := time.Now()
+	c.ownersMut.Lock()
+	cached, ok := c.owners[ref.UID]
+	c.ownersMut.Unlock()
+	if ok && cached.expiresAt.After(now) {
+		return cached.obj, cached.err
+	}
+
+	obj, err := c.fetchOwner(gvr, namespace, ref)
+	cached = &cachedOwner{obj: obj, err: err, expiresAt: now.Add(ownerCacheTTL)}
+	if err != nil {
+		cached.expiresAt = now.Add(ownerMissTTL)
+	}
+	c.ownersMut.Lock()
+	c.owners[ref.UID] = cached
+	c.ownersMut.Unlock()
+	return obj, err
+}
+
+func (c *WatchClient) fetchOwner(gvr schema.GroupVersionResource, namespace string, ref *meta_v1.OwnerReference) (*unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ownerGetTimeout)
+	defer cancel()
+	obj, err := c.dc.Resource(gvr).Namespace(namespace).Get(ctx, ref.Name, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetUID() != ref.UID {
+		return nil, fmt.Errorf("owner %s/%s has uid %s, expected %s", namespace, ref.Name, obj.GetUID(), ref.UID)
+	}
+	return obj, nil
+}
+
+// pruneOwners removes the expired owners from the cache.
+func (c *WatchClient) pruneOwners(now time.Time) {
+	c.ownersMut.Lock()
+	defer c.ownersMut.Unlock()
+	for uid, o := range c.owners {
+		if !o.expiresAt.After(now) {
+			delete(c.owners, uid)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+)
+
+func newOwner(apiVersion, kind, name, uid string, labels map[string]interface{}, controller *meta_v1.OwnerReference) *unstructured.Unstructured {
+	metadata := map[string]interface{}{
+		"name":       name,
+		"namespace":  "ns",
+		"uid":        uid,
+		"labels":     labels,
+		"generation": int64(2),
+	}
+	if controller != nil {
+		metadata["ownerReferences"] = []interface{}{
+			map[string]interface{}{
+				"apiVersion": controller.APIVersion,
+				"kind":       controller.Kind,
+				"name":       controller.Name,
+				"uid":        string(controller.UID),
+				"controller": true,
+			},
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   metadata,
+	}}
+}
+
+func controllerRef(apiVersion, kind, name, uid string) *meta_v1.OwnerReference {
+	controller := true
+	return &meta_v1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(uid), Controller: &controller}
+}
+
+func newTestClientWithOwners(t *testing.T, rules []OwnerExtractionRule, objs ...runtime.Object) *WatchClient {
+	dc := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	c, err := New(zap.NewNop(), k8sconfig.APIConfig{}, ExtractionRules{Owners: rules}, Filters{}, []Association{}, Excludes{}, newFakeAPIClientset, NewFakeInformer, NewFakeNamespaceInformer,
+		func(k8sconfig.APIConfig) (dynamic.Interface, error) { return dc, nil })
+	require.NoError(t, err)
+	return c.(*WatchClient)
+}
+
+func TestExtractOwnerAttributes(t *testing.T) {
+	rolloutRef := controllerRef("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid")
+	rsRef := controllerRef("apps/v1", "ReplicaSet", "checkout-6d4cf56db6", "rs-uid")
+	rollout := newOwner("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid", map[string]interface{}{"team": "payments"}, nil)
+	rs := newOwner("apps/v1", "ReplicaSet", "checkout-6d4cf56db6", "rs-uid", nil, rolloutRef)
+
+	rules := []OwnerExtractionRule{
+		{Name: "k8s.rollout.name", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "name"}},
+		{Name: "team", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "labels", "team"}},
+		{Name: "missing", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"spec", "missing"}},
+		{Name: "not-scalar", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "labels"}},
+		{Name: "generation", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "generation"}},
+	}
+
+	tests := []struct {
+		name  string
+		refs  []meta_v1.OwnerReference
+		objs  []runtime.Object
+		rules []OwnerExtractionRule
+		want  map[string]string
+	}{
+		{
+			name:  "through-replicaset",
+			refs:  []meta_v1.OwnerReference{*rsRef},
+			objs:  []runtime.Object{rs, rollout},
+			rules: rules,
+			want:  map[string]string{"k8s.rollout.name": "checkout", "team": "payments", "generation": "2"},
+		},
+		{
+			name:  "direct-owner",
+			refs:  []meta_v1.OwnerReference{*rolloutRef},
+			objs:  []runtime.Object{rollout},
+			rules: rules,
+			want:  map[string]string{"k8s.rollout.name": "checkout", "team": "payments", "generation": "2"},
+		},
+		{
+			name:  "unknown-owner",
+			refs:  []meta_v1.OwnerReference{*controllerRef("example.com/v1", "Unknown", "checkout", "unknown-uid")},
+			objs:  []runtime.Object{rollout},
+			rules: rules,
+			want:  map[string]string{},
+		},
+		{
+			name:  "owner-not-found",
+			refs:  []meta_v1.OwnerReference{*rsRef},
+			objs:  []runtime.Object{rs},
+			rules: rules,
+			want:  map[string]string{},
+		},
+		{
+			name: "uid-mismatch",
+			refs: []meta_v1.OwnerReference{*rsRef},
+			objs: []runtime.Object{
+				newOwner("apps/v1", "ReplicaSet", "checkout-6d4cf56db6", "other-uid", nil, rolloutRef),
+				rollout,
+			},
+			rules: rules,
+			want:  map[string]string{},
+		},
+		{
+			name: "not-controller",
+			refs: []meta_v1.OwnerReference{
+				{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Name: "checkout", UID: "rollout-uid"},
+			},
+			objs:  []runtime.Object{rollout},
+			rules: rules,
+			want:  map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClientWithOwners(t, tt.rules, tt.objs...)
+			pod := &api_v1.Pod{}
+			pod.Name = "checkout-6d4cf56db6-x2x7v"
+			pod.Namespace = "ns"
+			pod.OwnerReferences = tt.refs
+			assert.Equal(t, tt.want, c.extractPodAttributes(pod))
+		})
+	}
+}
+
+func TestOwnerCache(t *testing.T) {
+	rolloutRef := controllerRef("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid")
+	rollout := newOwner("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid", nil, nil)
+	rules := []OwnerExtractionRule{
+		{Name: "k8s.rollout.name", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "name"}},
+	}
+	c := newTestClientWithOwners(t, rules, rollout)
+
+	pod := &api_v1.Pod{}
+	pod.Namespace = "ns"
+	pod.OwnerReferences = []meta_v1.OwnerReference{*rolloutRef}
+	assert.Equal(t, map[string]string{"k8s.rollout.name": "checkout"}, c.extractPodAttributes(pod))
+	require.Len(t, c.owners, 1)
+
+	// The cached owner is used even if the object is gone.
+	require.NoError(t, c.dc.(*dynamicfake.FakeDynamicClient).Tracker().Delete(rollout.GroupVersionKind().GroupVersion().WithResource("rollouts"), "ns", "checkout"))
+	assert.Equal(t, map[string]string{"k8s.rollout.name": "checkout"}, c.extractPodAttributes(pod))
+
+	c.pruneOwners(time.Now())
+	assert.Len(t, c.owners, 1)
+	c.pruneOwners(time.Now().Add(ownerCacheTTL))
+	assert.Len(t, c.owners, 0)
+	assert.Equal(t, map[string]string{}, c.extractPodAttributes(pod))
+}
+
+func TestOwnerCacheMiss(t *testing.T) {
+	rolloutRef := controllerRef("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid")
+	rollout := newOwner("argoproj.io/v1alpha1", "Rollout", "checkout", "rollout-uid", nil, nil)
+	rules := []OwnerExtractionRule{
+		{Name: "k8s.rollout.name", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "name"}},
+	}
+	c := newTestClientWithOwners(t, rules)
+
+	pod := &api_v1.Pod{}
+	pod.Namespace = "ns"
+	pod.OwnerReferences = []meta_v1.OwnerReference{*rolloutRef}
+	assert.Equal(t, map[string]string{}, c.extractPodAttributes(pod))
+	require.Len(t, c.owners, 1)
+
+	// The missing owner isn't requested again until the error expires.
+	require.NoError(t, c.dc.(*dynamicfake.FakeDynamicClient).Tracker().Add(rollout))
+	assert.Equal(t, map[string]string{}, c.extractPodAttributes(pod))
+
+	c.pruneOwners(time.Now().Add(ownerMissTTL))
+	assert.Len(t, c.owners, 0)
+	assert.Equal(t, map[string]string{"k8s.rollout.name": "checkout"}, c.extractPodAttributes(pod))
+}
+
+func TestNewDynamicClientError(t *testing.T) {
+	rules := ExtractionRules{Owners: []OwnerExtractionRule{{Name: "k8s.rollout.name", Kind: "Rollout"}}}
+	c, err := New(zap.NewNop(), k8sconfig.APIConfig{}, rules, Filters{}, []Association{}, Excludes{}, newFakeAPIClientset, NewFakeInformer, NewFakeNamespaceInformer,
+		func(k8sconfig.APIConfig) (dynamic.Interface, error) { return nil, assert.AnError })
+	assert.Nil(t, c)
+	assert.ErrorIs(t, err, assert.AnError)
+}
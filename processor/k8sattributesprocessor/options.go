@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 
 	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 	"k8s.io/apimachinery/pkg/selection"
@@ -140,6 +141,49 @@ func WithExtractAnnotations(annotations ...FieldExtractConfig) Option {
 	}
 }
 
+// WithExtractOwners allows specifying options to control extraction of attributes from pod owners.
+func WithExtractOwners(owners ...OwnerExtractConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		owners, err := extractOwnerRules(owners...)
+		if err != nil {
+			return err
+		}
+		p.rules.Owners = owners
+		return nil
+	}
+}
+
+func extractOwnerRules(owners ...OwnerExtractConfig) ([]kube.OwnerExtractionRule, error) {
+	rules := []kube.OwnerExtractionRule{}
+	for _, o := range owners {
+		if o.Kind == "" {
+			return rules, fmt.Errorf("kind must be set for owners")
+		}
+
+		name := o.TagName
+		fieldPath := o.FieldPath
+		if fieldPath == "" {
+			fieldPath = "metadata.name"
+			if name == "" {
+				name = fmt.Sprintf("k8s.%s.name", strings.ToLower(o.Kind))
+			}
+		}
+		if name == "" {
+			return rules, fmt.Errorf("tag_name must be set for owner %s with field_path %s", o.Kind, o.FieldPath)
+		}
+
+		resource := o.Resource
+		if resource == "" {
+			resource = strings.ToLower(o.Kind) + "s"
+		}
+
+		rules = append(rules, kube.OwnerExtractionRule{
+			Name: name, Group: o.Group, Version: o.Version, Kind: o.Kind, Resource: resource, FieldPath: strings.Split(fieldPath, "."),
+		})
+	}
+	return rules, nil
+}
+
 func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.FieldExtractionRule, error) {
 	rules := []kube.FieldExtractionRule{}
 	for _, a := range fields {
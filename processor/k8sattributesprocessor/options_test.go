@@ -705,6 +705,71 @@ func Test_extractFieldRules(t *testing.T) {
 	}
 }
 
+func TestWithExtractOwners(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []OwnerExtractConfig
+		want    []kube.OwnerExtractionRule
+		wantErr bool
+	}{
+		{
+			"empty",
+			[]OwnerExtractConfig{},
+			[]kube.OwnerExtractionRule{},
+			false,
+		},
+		{
+			"defaults",
+			[]OwnerExtractConfig{
+				{Group: "argoproj.io", Kind: "Rollout"},
+			},
+			[]kube.OwnerExtractionRule{
+				{Name: "k8s.rollout.name", Group: "argoproj.io", Kind: "Rollout", Resource: "rollouts", FieldPath: []string{"metadata", "name"}},
+			},
+			false,
+		},
+		{
+			"field-path",
+			[]OwnerExtractConfig{
+				{TagName: "team", Group: "serving.knative.dev", Version: "v1", Kind: "Service", Resource: "services", FieldPath: "metadata.labels.team"},
+			},
+			[]kube.OwnerExtractionRule{
+				{Name: "team", Group: "serving.knative.dev", Version: "v1", Kind: "Service", Resource: "services", FieldPath: []string{"metadata", "labels", "team"}},
+			},
+			false,
+		},
+		{
+			"missing-kind",
+			[]OwnerExtractConfig{
+				{TagName: "rollout", Group: "argoproj.io"},
+			},
+			nil,
+			true,
+		},
+		{
+			"missing-tag-name",
+			[]OwnerExtractConfig{
+				{Group: "argoproj.io", Kind: "Rollout", FieldPath: "spec.strategy"},
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &kubernetesprocessor{}
+			option := WithExtractOwners(tt.args...)
+			err := option(p)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, p.rules.Owners)
+		})
+	}
+}
+
 func TestWithExtractPodAssociation(t *testing.T) {
 	tests := []struct {
 		name string
@@ -54,6 +54,12 @@ const (
 
 	// SubmatchCaseFieldName is the mapstructure field name for SubmatchCase field
 	SubmatchCaseFieldName = "submatch_case"
+
+	// BucketBoundsFieldName is the mapstructure field name for BucketBounds field
+	BucketBoundsFieldName = "bucket_bounds"
+
+	// MaxBucketsFieldName is the mapstructure field name for MaxBuckets field
+	MaxBucketsFieldName = "max_buckets"
 )
 
 // Config defines configuration for Resource processor.
@@ -151,6 +157,12 @@ type Operation struct {
 
 	// LabelValue identifies the exact label value to operate on
 	LabelValue string `mapstructure:"label_value"`
+
+	// BucketBounds is the list of the explicit bounds to merge the histogram buckets into, in increasing order.
+	BucketBounds []float64 `mapstructure:"bucket_bounds"`
+
+	// MaxBuckets is the maximum number of histogram buckets, consecutive buckets are merged above it.
+	MaxBuckets int `mapstructure:"max_buckets"`
 }
 
 // ValueAction renames label values.
@@ -217,9 +229,13 @@ const (
 	// AggregateLabelValues aggregates away the values in Operation.AggregatedValues
 	// by the method indicated by Operation.AggregationType.
 	AggregateLabelValues OperationAction = "aggregate_label_values"
+
+	// MergeHistogramBuckets merges the buckets of histograms into the buckets of Operation.BucketBounds,
+	// or into at most Operation.MaxBuckets buckets.
+	MergeHistogramBuckets OperationAction = "experimental_merge_histogram_buckets"
 )
 
-var operationActions = []OperationAction{AddLabel, UpdateLabel, DeleteLabelValue, ToggleScalarDataType, ScaleValue, AggregateLabels, AggregateLabelValues}
+var operationActions = []OperationAction{AddLabel, UpdateLabel, DeleteLabelValue, ToggleScalarDataType, ScaleValue, AggregateLabels, AggregateLabelValues, MergeHistogramBuckets}
 
 func (oa OperationAction) isValid() bool {
 	for _, operationAction := range operationActions {
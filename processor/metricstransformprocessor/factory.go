@@ -131,6 +131,11 @@ func validateConfiguration(config *Config) error {
 			if op.Action == ScaleValue && op.Scale == 0 {
 				return fmt.Errorf("operation %v: missing required field %q while %q is %v", i+1, ScaleFieldName, ActionFieldName, ScaleValue)
 			}
+			if op.Action == MergeHistogramBuckets {
+				if err := validateMergeHistogramBuckets(op); err != nil {
+					return fmt.Errorf("operation %v: %w", i+1, err)
+				}
+			}
 
 			if op.AggregationType != "" && !op.AggregationType.isValid() {
 				return fmt.Errorf("operation %v: %q must be in %q", i+1, AggregationTypeFieldName, aggregationTypes)
@@ -140,6 +145,23 @@ func validateConfiguration(config *Config) error {
 	return nil
 }
 
+// validateMergeHistogramBuckets validates that exactly one of the bucket bounds or the maximum number of
+// buckets is set, with increasing bounds or a positive number of buckets.
+func validateMergeHistogramBuckets(op Operation) error {
+	if (len(op.BucketBounds) == 0) == (op.MaxBuckets == 0) {
+		return fmt.Errorf("exactly one of %q or %q is required while %q is %v", BucketBoundsFieldName, MaxBucketsFieldName, ActionFieldName, MergeHistogramBuckets)
+	}
+	if op.MaxBuckets < 0 {
+		return fmt.Errorf("%q must be positive", MaxBucketsFieldName)
+	}
+	for i := 1; i < len(op.BucketBounds); i++ {
+		if op.BucketBounds[i] <= op.BucketBounds[i-1] {
+			return fmt.Errorf("%q must be in increasing order", BucketBoundsFieldName)
+		}
+	}
+	return nil
+}
+
 // buildHelperConfig constructs the maps that will be useful for the operations
 func buildHelperConfig(config *Config, version string) ([]internalTransform, error) {
 	helperDataTransforms := make([]internalTransform, len(config.Transforms))
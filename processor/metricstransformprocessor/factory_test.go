@@ -94,6 +94,16 @@ func TestCreateProcessors(t *testing.T) {
 			succeed:      false,
 			errorMessage: fmt.Sprintf("operation %v: missing required field %q while %q is %v", 1, ScaleFieldName, ActionFieldName, ScaleValue),
 		},
+		{
+			configName:   "config_invalid_merge_histogram_buckets.yaml",
+			succeed:      false,
+			errorMessage: fmt.Sprintf("operation %v: exactly one of %q or %q is required while %q is %v", 1, BucketBoundsFieldName, MaxBucketsFieldName, ActionFieldName, MergeHistogramBuckets),
+		},
+		{
+			configName:   "config_invalid_bucket_bounds.yaml",
+			succeed:      false,
+			errorMessage: fmt.Sprintf("operation %v: %q must be in increasing order", 1, BucketBoundsFieldName),
+		},
 		{
 			configName:   "config_invalid_regexp.yaml",
 			succeed:      false,
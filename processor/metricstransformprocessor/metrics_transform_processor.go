@@ -442,6 +442,8 @@ func (mtp *metricsTransformProcessor) update(match *match, transform internalTra
 			mtp.addLabelOp(match.metric, op)
 		case DeleteLabelValue:
 			mtp.deleteLabelValueOp(match.metric, op)
+		case MergeHistogramBuckets:
+			mtp.mergeHistogramBucketsOp(match.metric, op)
 		}
 	}
 }
@@ -1705,6 +1705,106 @@ var (
 					build(),
 			},
 		},
+		// Merge Histogram Buckets
+		{
+			name: "metric_experimental_merge_histogram_buckets_bounds",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action:       MergeHistogramBuckets,
+								BucketBounds: []float64{2, 4},
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 15, 40, []float64{1, 2, 3, 4, 5}, []int64{1, 2, 3, 4, 3, 2}).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 15, 40, []float64{2, 4}, []int64{3, 7, 5}).
+					build(),
+			},
+		},
+		{
+			name: "metric_experimental_merge_histogram_buckets_bounds_not_subset",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action:       MergeHistogramBuckets,
+								BucketBounds: []float64{2.5, 10},
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 6, 10, []float64{1, 2, 3}, []int64{1, 2, 2, 1}).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 6, 10, []float64{2.5, 10}, []int64{3, 2, 1}).
+					build(),
+			},
+		},
+		{
+			name: "metric_experimental_merge_histogram_buckets_max_buckets",
+			transforms: []internalTransform{
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric1"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action:     MergeHistogramBuckets,
+								MaxBuckets: 3,
+							},
+						},
+					},
+				},
+				{
+					MetricIncludeFilter: internalFilterStrict{include: "metric2"},
+					Action:              Update,
+					Operations: []internalOperation{
+						{
+							configOperation: Operation{
+								Action:     MergeHistogramBuckets,
+								MaxBuckets: 3,
+							},
+						},
+					},
+				},
+			},
+			in: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 15, 40, []float64{1, 2, 3, 4, 5}, []int64{1, 2, 3, 4, 3, 2}).
+					build(),
+				metricBuilder().setName("metric2").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 3, 6, []float64{1, 2}, []int64{1, 1, 1}).
+					build(),
+			},
+			out: []*metricspb.Metric{
+				metricBuilder().setName("metric1").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 15, 40, []float64{2, 4}, []int64{3, 7, 5}).
+					build(),
+				metricBuilder().setName("metric2").setDataType(metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION).
+					addTimeseries(1, nil).addDistributionPoints(0, 3, 6, []float64{1, 2}, []int64{1, 1, 1}).
+					build(),
+			},
+		},
 		// Add Label to a metric
 		{
 			name: "update existing metric by adding a new label when there are no labels",
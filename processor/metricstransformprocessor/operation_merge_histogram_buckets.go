@@ -0,0 +1,87 @@
+// Copyright 2021 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstransformprocessor"
+
+import metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+
+// mergeHistogramBucketsOp merges the buckets of the distribution points with explicit bucket bounds, either
+// into the buckets of the configured bounds, or into groups of consecutive buckets so that there are at
+// most the configured number of buckets. The count, sum and sum of squared deviation are unchanged.
+func (mtp *metricsTransformProcessor) mergeHistogramBucketsOp(metric *metricspb.Metric, op internalOperation) {
+	if metric.MetricDescriptor.Type != metricspb.MetricDescriptor_GAUGE_DISTRIBUTION &&
+		metric.MetricDescriptor.Type != metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION {
+		return
+	}
+	for _, ts := range metric.Timeseries {
+		for _, dp := range ts.Points {
+			dist := dp.GetDistributionValue()
+			if dist == nil {
+				continue
+			}
+			explicit := dist.GetBucketOptions().GetExplicit()
+			if explicit == nil || len(dist.Buckets) != len(explicit.Bounds)+1 {
+				continue
+			}
+			bounds := op.configOperation.BucketBounds
+			if op.configOperation.MaxBuckets > 0 {
+				if len(dist.Buckets) <= op.configOperation.MaxBuckets {
+					continue
+				}
+				bounds = groupedBucketBounds(explicit.Bounds, op.configOperation.MaxBuckets)
+			}
+			dist.Buckets = mergeBuckets(explicit.Bounds, dist.Buckets, bounds)
+			explicit.Bounds = append([]float64(nil), bounds...)
+		}
+	}
+}
+
+// groupedBucketBounds returns the bounds of the buckets grouping the consecutive buckets of bounds,
+// so that there are at most maxBuckets buckets.
+func groupedBucketBounds(bounds []float64, maxBuckets int) []float64 {
+	bucketCount := len(bounds) + 1
+	groupSize := (bucketCount + maxBuckets - 1) / maxBuckets
+	var grouped []float64
+	for i := groupSize - 1; i < len(bounds); i += groupSize {
+		grouped = append(grouped, bounds[i])
+	}
+	return grouped
+}
+
+// mergeBuckets adds the count of every bucket to the first new bucket whose upper bound is greater than
+// or equal to the upper bound of the bucket. The counts of the new buckets are exact if the new bounds
+// are a subset of the bounds, otherwise the counts of the buckets are moved to the higher new bucket.
+// The exemplar of the new buckets is the last exemplar of the buckets merged into them.
+func mergeBuckets(bounds []float64, buckets []*metricspb.DistributionValue_Bucket, newBounds []float64) []*metricspb.DistributionValue_Bucket {
+	merged := make([]*metricspb.DistributionValue_Bucket, len(newBounds)+1)
+	for i := range merged {
+		merged[i] = &metricspb.DistributionValue_Bucket{}
+	}
+	j := 0
+	for i, bucket := range buckets {
+		// The last bucket has no upper bound and is merged into the last new bucket.
+		if i == len(bounds) {
+			j = len(newBounds)
+		} else {
+			for j < len(newBounds) && newBounds[j] < bounds[i] {
+				j++
+			}
+		}
+		merged[j].Count += bucket.Count
+		if bucket.Exemplar != nil {
+			merged[j].Exemplar = bucket.Exemplar
+		}
+	}
+	return merged
+}
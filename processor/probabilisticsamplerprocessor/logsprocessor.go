@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"sort"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type logsamplerprocessor struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+}
+
+// newLogsProcessor returns a processor.LogsProcessor that will perform head sampling according to the given
+// configuration.
+func newLogsProcessor(nextConsumer consumer.Logs, cfg *Config) (component.LogsProcessor, error) {
+	lsp := &logsamplerprocessor{
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           cfg.HashSeed,
+	}
+
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		lsp.processLogs,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+}
+
+func (lsp *logsamplerprocessor) processLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	ld.ResourceLogs().RemoveIf(func(rl pdata.ResourceLogs) bool {
+		rl.InstrumentationLibraryLogs().RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			ill.Logs().RemoveIf(func(lr pdata.LogRecord) bool {
+				sp := parseSamplingPriority(lr.Attributes())
+				if sp == doNotSampleSpan {
+					return true
+				}
+				sampled := sp == mustSampleSpan ||
+					hash(logRecordKey(lr), lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+				return !sampled
+			})
+			// Filter out empty InstrumentationLibraryLogs
+			return ill.Logs().Len() == 0
+		})
+		// Filter out empty ResourceLogs
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+	if ld.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// logRecordKey returns the bytes hashed to sample a log record: its trace ID if it has one, so that
+// the logs of a sampled trace are sampled along with the spans, or the content of the record otherwise,
+// so that the replicas of a tier agree on the records they receive. The resource is left out, as it
+// may be enriched differently by every replica.
+func logRecordKey(lr pdata.LogRecord) []byte {
+	if tid := lr.TraceID(); !tid.IsEmpty() {
+		tidBytes := tid.Bytes()
+		return tidBytes[:]
+	}
+
+	key := make([]byte, 12, 64)
+	binary.BigEndian.PutUint64(key, uint64(lr.Timestamp()))
+	binary.BigEndian.PutUint32(key[8:], uint32(lr.SeverityNumber()))
+	key = appendString(key, lr.SeverityText())
+	key = appendString(key, lr.Body().AsString())
+
+	attrs := lr.Attributes()
+	names := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pdata.AttributeValue) bool {
+		names = append(names, k)
+		return true
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		v, _ := attrs.Get(name)
+		key = appendString(key, name)
+		key = appendString(key, v.AsString())
+	}
+	return key
+}
+
+// appendString appends the length prefixed s, so that the concatenated strings are not ambiguous.
+func appendString(key []byte, s string) []byte {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(s)))
+	return append(append(key, size[:]...), s...)
+}
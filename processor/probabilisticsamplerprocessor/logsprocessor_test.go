@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/idutils"
+)
+
+// genRandomLogs generates numLogs log records, with random trace IDs if withTraceIDs is set, or with
+// random bodies otherwise.
+func genRandomLogs(numLogs int, withTraceIDs bool) pdata.Logs {
+	r := rand.New(rand.NewSource(1))
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for i := 0; i < numLogs; i++ {
+		lr := logs.AppendEmpty()
+		lr.SetTimestamp(pdata.Timestamp(1643241600000000000))
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+		lr.Attributes().InsertString("http.method", "GET")
+		if withTraceIDs {
+			lr.SetTraceID(idutils.UInt64ToTraceID(r.Uint64(), r.Uint64()))
+			lr.Body().SetStringVal("request")
+		} else {
+			lr.Body().SetStringVal("request " + strconv.FormatUint(r.Uint64(), 10))
+		}
+	}
+	return ld
+}
+
+func TestNewLogsProcessor(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 15.5,
+	}
+	_, err := newLogsProcessor(nil, cfg)
+	assert.Error(t, err)
+
+	lp, err := newLogsProcessor(consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.True(t, lp.Capabilities().MutatesData)
+}
+
+// Test_logsamplerprocessor_SamplingPercentageRange checks the sampling rates of the logs with and without trace IDs
+// are within acceptable deltas.
+func Test_logsamplerprocessor_SamplingPercentageRange(t *testing.T) {
+	const numLogs = 1e5
+	for _, percentage := range []float32{0, 5, 50, 90, 100} {
+		for _, withTraceIDs := range []bool{true, false} {
+			t.Run(strconv.FormatFloat(float64(percentage), 'f', -1, 32)+"/traceIDs="+strconv.FormatBool(withTraceIDs), func(t *testing.T) {
+				sink := new(consumertest.LogsSink)
+				lp, err := newLogsProcessor(sink, &Config{
+					ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+					SamplingPercentage: percentage,
+				})
+				require.NoError(t, err)
+				require.NoError(t, lp.ConsumeLogs(context.Background(), genRandomLogs(numLogs, withTraceIDs)))
+
+				actual := float64(sink.LogRecordCount()) / numLogs * 100
+				assert.LessOrEqual(t, math.Abs(actual-float64(percentage)), 0.5)
+			})
+		}
+	}
+}
+
+// Test_logsamplerprocessor_TraceConsistency checks the logs of the spans sampled by the traces processor are sampled,
+// and only them.
+func Test_logsamplerprocessor_TraceConsistency(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 30,
+		HashSeed:           22,
+	}
+	tracesSink := new(consumertest.TracesSink)
+	tp, err := newTracesProcessor(tracesSink, cfg)
+	require.NoError(t, err)
+	logsSink := new(consumertest.LogsSink)
+	lp, err := newLogsProcessor(logsSink, cfg)
+	require.NoError(t, err)
+
+	traces := genRandomTestData(1, 1000, "test-svc", 1)[0]
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	spans := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		lr := logs.AppendEmpty()
+		lr.SetTraceID(spans.At(i).TraceID())
+		lr.SetSpanID(spans.At(i).SpanID())
+	}
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+	sampledTraces, _ := assertSampledData(t, tracesSink.AllTraces(), "test-svc")
+	require.NotEmpty(t, sampledTraces)
+	sampledLogs := map[[16]byte]bool{}
+	for _, sampled := range logsSink.AllLogs() {
+		logs := sampled.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logs.Len(); i++ {
+			sampledLogs[logs.At(i).TraceID().Bytes()] = true
+		}
+	}
+	assert.Equal(t, sampledTraces, sampledLogs)
+}
+
+// Test_logsamplerprocessor_ConsistentDecisions checks replicas with the same seed take the same decisions for log
+// records without trace IDs, independently of the order of their attributes and of their resource.
+func Test_logsamplerprocessor_ConsistentDecisions(t *testing.T) {
+	newRecord := func(attrs ...string) pdata.LogRecord {
+		lr := pdata.NewLogRecord()
+		lr.SetTimestamp(pdata.Timestamp(1643241600000000000))
+		lr.SetSeverityText("INFO")
+		lr.Body().SetStringVal("request")
+		for i := 0; i < len(attrs); i += 2 {
+			lr.Attributes().InsertString(attrs[i], attrs[i+1])
+		}
+		return lr
+	}
+	key := logRecordKey(newRecord("a", "1", "b", "2"))
+	assert.Equal(t, key, logRecordKey(newRecord("b", "2", "a", "1")))
+	assert.NotEqual(t, key, logRecordKey(newRecord("a", "1", "b", "3")))
+	assert.NotEqual(t, key, logRecordKey(newRecord("a", "1b", "", "2")))
+
+	withTraceID := newRecord()
+	withTraceID.SetTraceID(idutils.UInt64ToTraceID(1, 2))
+	traceID := withTraceID.TraceID().Bytes()
+	assert.Equal(t, traceID[:], logRecordKey(withTraceID))
+
+	sampled := func(resource string) int {
+		sink := new(consumertest.LogsSink)
+		lp, err := newLogsProcessor(sink, &Config{
+			ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+			SamplingPercentage: 50,
+			HashSeed:           22,
+		})
+		require.NoError(t, err)
+		ld := genRandomLogs(1000, false)
+		ld.ResourceLogs().At(0).Resource().Attributes().InsertString("host.name", resource)
+		require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+		return sink.LogRecordCount()
+	}
+	assert.Equal(t, sampled("replica-1"), sampled("replica-2"))
+}
+
+// Test_logsamplerprocessor_SamplingPriority checks the "sampling.priority" attribute of the log records takes
+// priority over the hashing.
+func Test_logsamplerprocessor_SamplingPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority pdata.AttributeValue
+		sampled  bool
+	}{
+		{name: "must_sample", priority: pdata.NewAttributeValueInt(2), sampled: true},
+		{name: "must_not_sample", priority: pdata.NewAttributeValueDouble(0), sampled: false},
+		{name: "defer_sample", priority: pdata.NewAttributeValueString("-1"), sampled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			lp, err := newLogsProcessor(sink, &Config{
+				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+			})
+			require.NoError(t, err)
+			ld := genRandomLogs(1, false)
+			ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes().Insert("sampling.priority", tt.priority)
+			require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+			if tt.sampled {
+				assert.Equal(t, 1, sink.LogRecordCount())
+			} else {
+				assert.Equal(t, 0, sink.LogRecordCount())
+			}
+		})
+	}
+}
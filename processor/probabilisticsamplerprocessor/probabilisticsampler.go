@@ -106,7 +106,11 @@ func (tsp *tracesamplerprocessor) processTraces(_ context.Context, td pdata.Trac
 // OpenTracing semantic tags:
 // https://github.com/opentracing/specification/blob/main/semantic_conventions.md#span-tags-table
 func parseSpanSamplingPriority(span pdata.Span) samplingPriority {
-	attribMap := span.Attributes()
+	return parseSamplingPriority(span.Attributes())
+}
+
+// parseSamplingPriority checks the "sampling.priority" attribute of a span or log record.
+func parseSamplingPriority(attribMap pdata.AttributeMap) samplingPriority {
 	if attribMap.Len() <= 0 {
 		return deferDecision
 	}
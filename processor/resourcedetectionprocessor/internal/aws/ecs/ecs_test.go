@@ -54,6 +54,10 @@ func (md *mockMetaDataProvider) FetchTaskMetadata() (*ecsutil.TaskMetadata, erro
 	return tmd, nil
 }
 
+func (md *mockMetaDataProvider) FetchTaskMetadataWithTags() (*ecsutil.TaskMetadata, error) {
+	return md.FetchTaskMetadata()
+}
+
 func (md *mockMetaDataProvider) FetchContainerMetadata() (*ecsutil.ContainerMetadata, error) {
 	c := createTestContainer(md.isV4)
 	return &c, nil
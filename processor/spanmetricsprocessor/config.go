@@ -58,6 +58,33 @@ type Config struct {
 	DimensionsCacheSize int `mapstructure:"dimensions_cache_size"`
 
 	AggregationTemporality string `mapstructure:"aggregation_temporality"`
+
+	// Histogram defines the type of the latency histogram.
+	// Optional. Latencies are recorded in an explicit bucket histogram by default.
+	Histogram HistogramConfig `mapstructure:"histogram"`
+
+	// DimensionsCardinalityLimit defines the maximum number of distinct dimension sets held by the processor.
+	// Spans that would exceed it are accounted to an overflow series per service carrying only the service.name
+	// dimension and the otel.metric.overflow attribute set to true. The dimension sets are only released with
+	// the delta aggregation temporality, once their metrics are exported; with the cumulative temporality they
+	// are held for the lifetime of the processor.
+	// Optional. No limit is applied by default.
+	DimensionsCardinalityLimit int `mapstructure:"dimensions_cardinality_limit"`
+}
+
+// HistogramConfig defines the type of the latency histogram.
+type HistogramConfig struct {
+	// Exponential records latencies in an exponential histogram instead of the buckets defined by
+	// LatencyHistogramBuckets.
+	Exponential *ExponentialHistogramConfig `mapstructure:"exponential"`
+}
+
+// ExponentialHistogramConfig defines the configuration of the exponential latency histogram.
+type ExponentialHistogramConfig struct {
+	// MaxSize is the maximum number of buckets of the histogram. The histogram scale is lowered
+	// as needed to keep the number of buckets under MaxSize.
+	// Optional. See defaultExponentialHistogramMaxSize in processor.go for the default value.
+	MaxSize int32 `mapstructure:"max_size"`
 }
 
 // GetAggregationTemporality converts the string value given in the config into a MetricAggregationTemporality.
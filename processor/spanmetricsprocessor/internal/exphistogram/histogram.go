@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exphistogram implements a base-2 exponential histogram of positive values,
+// as defined by the OpenTelemetry exponential histogram data model, which lowers its
+// scale as needed to keep the number of buckets within a maximum size.
+package exphistogram // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/exphistogram"
+
+import (
+	"math"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	// MaxScale is the highest scale supported by the data model, recording starts at this scale.
+	MaxScale int32 = 20
+	// MinScale is the lowest scale a histogram is downscaled to.
+	MinScale int32 = -10
+)
+
+// Histogram records values into exponentially sized buckets. Bucket i at scale s covers
+// the values in (base^i, base^(i+1)] with base = 2^(2^-s). Values <= 0 and NaN are counted
+// in the zero bucket, NaN is left out of the sum.
+type Histogram struct {
+	maxSize int32
+	scale   int32
+
+	count     uint64
+	sum       float64
+	zeroCount uint64
+
+	// counts holds the bucket counts for the indexes [offset, offset+len(counts)).
+	offset int32
+	counts []uint64
+}
+
+// New creates a Histogram holding at most maxSize buckets.
+func New(maxSize int32) *Histogram {
+	return &Histogram{
+		maxSize: maxSize,
+		scale:   MaxScale,
+	}
+}
+
+// Record adds value to the histogram.
+func (h *Histogram) Record(value float64) {
+	h.count++
+	if math.IsNaN(value) {
+		// Adding NaN would turn the sum into NaN for the rest of the life of the series.
+		h.zeroCount++
+		return
+	}
+	h.sum += value
+	if value <= 0 {
+		h.zeroCount++
+		return
+	}
+
+	index := h.index(value)
+	if len(h.counts) == 0 {
+		h.offset = index
+		h.counts = append(h.counts, 1)
+		return
+	}
+
+	low, high := h.offset, h.offset+int32(len(h.counts))-1
+	if index < low {
+		low = index
+	}
+	if index > high {
+		high = index
+	}
+	if change := h.scaleChange(low, high); change > 0 {
+		h.downscale(change)
+		index >>= change
+	}
+	h.grow(index)
+	h.counts[index-h.offset]++
+}
+
+// CopyTo writes the histogram to dp.
+func (h *Histogram) CopyTo(dp pdata.ExponentialHistogramDataPoint) {
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.SetScale(h.scale)
+	dp.SetZeroCount(h.zeroCount)
+	dp.Positive().SetOffset(h.offset)
+	counts := make([]uint64, len(h.counts))
+	copy(counts, h.counts)
+	dp.Positive().SetBucketCounts(counts)
+}
+
+// index returns the index of the bucket value falls into at the current scale.
+func (h *Histogram) index(value float64) int32 {
+	return int32(math.Ceil(math.Log2(value)*math.Ldexp(1, int(h.scale)))) - 1
+}
+
+// scaleChange returns by how much the scale has to be lowered for the indexes
+// [low, high] to fit in maxSize buckets.
+func (h *Histogram) scaleChange(low, high int32) int32 {
+	var change int32
+	for high-low+1 > h.maxSize && h.scale-change > MinScale {
+		low >>= 1
+		high >>= 1
+		change++
+	}
+	return change
+}
+
+// downscale lowers the scale by change, merging 2^change neighboring buckets into one.
+func (h *Histogram) downscale(change int32) {
+	h.scale -= change
+	if len(h.counts) == 0 {
+		return
+	}
+	offset := h.offset >> change
+	counts := make([]uint64, (h.offset+int32(len(h.counts))-1)>>change-offset+1)
+	for i, c := range h.counts {
+		counts[(h.offset+int32(i))>>change-offset] += c
+	}
+	h.offset = offset
+	h.counts = counts
+}
+
+// grow extends the bucket counts to include index.
+func (h *Histogram) grow(index int32) {
+	if index < h.offset {
+		counts := make([]uint64, h.offset-index+int32(len(h.counts)))
+		copy(counts[h.offset-index:], h.counts)
+		h.counts = counts
+		h.offset = index
+		return
+	}
+	if high := h.offset + int32(len(h.counts)) - 1; index > high {
+		h.counts = append(h.counts, make([]uint64, index-high)...)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exphistogram
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestRecordSingleValue(t *testing.T) {
+	h := New(160)
+	h.Record(4)
+
+	dp := pdata.NewExponentialHistogramDataPoint()
+	h.CopyTo(dp)
+	assert.Equal(t, uint64(1), dp.Count())
+	assert.Equal(t, 4.0, dp.Sum())
+	assert.Equal(t, MaxScale, dp.Scale())
+	assert.Equal(t, []uint64{1}, dp.Positive().BucketCounts())
+	// 4 is the upper boundary of its bucket.
+	assert.Equal(t, 4.0, upperBound(dp.Scale(), dp.Positive().Offset()))
+}
+
+func TestRecordZero(t *testing.T) {
+	h := New(160)
+	h.Record(0)
+	h.Record(-1)
+
+	dp := pdata.NewExponentialHistogramDataPoint()
+	h.CopyTo(dp)
+	assert.Equal(t, uint64(2), dp.Count())
+	assert.Equal(t, uint64(2), dp.ZeroCount())
+	assert.Empty(t, dp.Positive().BucketCounts())
+}
+
+func TestRecordNaN(t *testing.T) {
+	h := New(160)
+	h.Record(4)
+	h.Record(math.NaN())
+	h.Record(2)
+
+	dp := pdata.NewExponentialHistogramDataPoint()
+	h.CopyTo(dp)
+	assert.Equal(t, uint64(3), dp.Count())
+	assert.Equal(t, 6.0, dp.Sum())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+}
+
+func TestRecordDownscale(t *testing.T) {
+	h := New(4)
+	for _, v := range []float64{1, 2, 4, 8, 16, 32} {
+		h.Record(v)
+	}
+
+	dp := pdata.NewExponentialHistogramDataPoint()
+	h.CopyTo(dp)
+	assert.Equal(t, uint64(6), dp.Count())
+	assert.Equal(t, 63.0, dp.Sum())
+	assert.LessOrEqual(t, len(dp.Positive().BucketCounts()), 4)
+
+	var total uint64
+	for _, c := range dp.Positive().BucketCounts() {
+		total += c
+	}
+	assert.Equal(t, uint64(6), total)
+
+	// Every value must fall within the boundaries of the bucket it was counted in.
+	for _, v := range []float64{1, 2, 4, 8, 16, 32} {
+		i := h.index(v)
+		assert.GreaterOrEqual(t, i, dp.Positive().Offset())
+		assert.Less(t, v, upperBound(dp.Scale(), i)*(1+1e-9))
+		assert.Greater(t, v, upperBound(dp.Scale(), i-1))
+	}
+}
+
+func TestRecordBelowOffset(t *testing.T) {
+	h := New(160)
+	h.Record(100)
+	h.Record(10)
+
+	dp := pdata.NewExponentialHistogramDataPoint()
+	h.CopyTo(dp)
+	counts := dp.Positive().BucketCounts()
+	assert.Equal(t, uint64(1), counts[0])
+	assert.Equal(t, uint64(1), counts[len(counts)-1])
+	assert.LessOrEqual(t, len(counts), 160)
+}
+
+func upperBound(scale, index int32) float64 {
+	return math.Exp2(math.Ldexp(float64(index+1), -int(scale)))
+}
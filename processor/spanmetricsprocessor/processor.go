@@ -32,6 +32,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/exphistogram"
 )
 
 const (
@@ -41,8 +42,10 @@ const (
 	statusCodeKey      = "status.code" // OpenTelemetry non-standard constant.
 	metricKeySeparator = string(byte(0))
 	traceIDKey         = "trace_id"
+	overflowKey        = "otel.metric.overflow"
 
-	defaultDimensionsCacheSize = 1000
+	defaultDimensionsCacheSize         = 1000
+	defaultExponentialHistogramMaxSize = 160
 )
 
 var (
@@ -56,6 +59,7 @@ var (
 
 type exemplarData struct {
 	traceID pdata.TraceID
+	spanID  pdata.SpanID
 	value   float64
 }
 
@@ -85,6 +89,10 @@ type processorImp struct {
 	latencyBounds        []float64
 	latencyExemplarsData map[metricKey][]exemplarData
 
+	// Exponential latency histogram, used instead of the explicit bucket histogram when configured.
+	expHistogramMaxSize  int32
+	latencyExpHistograms map[metricKey]*exphistogram.Histogram
+
 	// An LRU cache of dimension key-value maps keyed by a unique identifier formed by a concatenation of its values:
 	// e.g. { "foo/barOK": { "serviceName": "foo", "operation": "/bar", "status_code": "OK" }}
 	metricKeyToDimensions *cache.Cache
@@ -104,6 +112,24 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 		}
 	}
 
+	var expHistogramMaxSize int32
+	if pConfig.Histogram.Exponential != nil {
+		if pConfig.LatencyHistogramBuckets != nil {
+			return nil, fmt.Errorf("latency_histogram_buckets can't be used with an exponential histogram")
+		}
+		expHistogramMaxSize = pConfig.Histogram.Exponential.MaxSize
+		if expHistogramMaxSize == 0 {
+			expHistogramMaxSize = defaultExponentialHistogramMaxSize
+		}
+		if expHistogramMaxSize < 0 {
+			return nil, fmt.Errorf("invalid exponential histogram max size: %v, it should be positive", expHistogramMaxSize)
+		}
+	}
+
+	if pConfig.DimensionsCardinalityLimit < 0 {
+		return nil, fmt.Errorf("invalid dimensions cardinality limit: %v, it should be positive", pConfig.DimensionsCardinalityLimit)
+	}
+
 	if err := validateDimensions(pConfig.Dimensions); err != nil {
 		return nil, err
 	}
@@ -129,6 +155,8 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 		latencyCount:          make(map[metricKey]uint64),
 		latencyBucketCounts:   make(map[metricKey][]uint64),
 		latencyExemplarsData:  make(map[metricKey][]exemplarData),
+		expHistogramMaxSize:   expHistogramMaxSize,
+		latencyExpHistograms:  make(map[metricKey]*exphistogram.Histogram),
 		nextConsumer:          nextConsumer,
 		dimensions:            pConfig.Dimensions,
 		metricKeyToDimensions: metricKeyToDimensionsCache,
@@ -281,6 +309,9 @@ func (p *processorImp) buildMetrics() (*pdata.Metrics, error) {
 // collectLatencyMetrics collects the raw latency metrics, writing the data
 // into the given instrumentation library metrics.
 func (p *processorImp) collectLatencyMetrics(ilm pdata.InstrumentationLibraryMetrics) error {
+	if p.expHistogramMaxSize > 0 {
+		return p.collectExpLatencyMetrics(ilm)
+	}
 	for key := range p.latencyCount {
 		mLatency := ilm.Metrics().AppendEmpty()
 		mLatency.SetDataType(pdata.MetricDataTypeHistogram)
@@ -310,6 +341,35 @@ func (p *processorImp) collectLatencyMetrics(ilm pdata.InstrumentationLibraryMet
 	return nil
 }
 
+// collectExpLatencyMetrics collects the raw latency metrics recorded in exponential
+// histograms, writing the data into the given instrumentation library metrics.
+func (p *processorImp) collectExpLatencyMetrics(ilm pdata.InstrumentationLibraryMetrics) error {
+	for key, histogram := range p.latencyExpHistograms {
+		mLatency := ilm.Metrics().AppendEmpty()
+		mLatency.SetDataType(pdata.MetricDataTypeExponentialHistogram)
+		mLatency.SetName("latency")
+		mLatency.ExponentialHistogram().SetAggregationTemporality(p.config.GetAggregationTemporality())
+
+		timestamp := pdata.NewTimestampFromTime(time.Now())
+
+		dpLatency := mLatency.ExponentialHistogram().DataPoints().AppendEmpty()
+		dpLatency.SetStartTimestamp(pdata.NewTimestampFromTime(p.startTime))
+		dpLatency.SetTimestamp(timestamp)
+		histogram.CopyTo(dpLatency)
+
+		setLatencyExemplars(p.latencyExemplarsData[key], timestamp, dpLatency.Exemplars())
+
+		dimensions, err := p.getDimensionsByMetricKey(key)
+		if err != nil {
+			p.logger.Error(err.Error())
+			return err
+		}
+
+		dimensions.CopyTo(dpLatency.Attributes())
+	}
+	return nil
+}
+
 // collectCallMetrics collects the raw call count metrics, writing the data
 // into the given instrumentation library metrics.
 func (p *processorImp) collectCallMetrics(ilm pdata.InstrumentationLibraryMetrics) error {
@@ -387,13 +447,31 @@ func (p *processorImp) aggregateMetricsForSpan(serviceName string, span pdata.Sp
 	key := buildKey(serviceName, span, p.dimensions, resourceAttr)
 
 	p.lock.Lock()
-	p.cache(serviceName, span, key, resourceAttr)
+	if p.exceedsCardinalityLimit(key) {
+		key = buildOverflowKey(serviceName)
+		p.metricKeyToDimensions.ContainsOrAdd(key, buildOverflowDimensionKVs(serviceName))
+	} else {
+		p.cache(serviceName, span, key, resourceAttr)
+	}
 	p.updateCallMetrics(key)
 	p.updateLatencyMetrics(key, latencyInMilliseconds, index)
-	p.updateLatencyExemplars(key, latencyInMilliseconds, span.TraceID())
+	p.updateLatencyExemplars(key, latencyInMilliseconds, span.TraceID(), span.SpanID())
 	p.lock.Unlock()
 }
 
+// exceedsCardinalityLimit returns whether accounting a span to the new metric key k would
+// exceed the configured dimensions cardinality limit. The limit applies to the keys held in
+// callSum, which are only reset after a flush with the delta aggregation temporality.
+func (p *processorImp) exceedsCardinalityLimit(k metricKey) bool {
+	if p.config.DimensionsCardinalityLimit <= 0 {
+		return false
+	}
+	if _, ok := p.callSum[k]; ok {
+		return false
+	}
+	return len(p.callSum) >= p.config.DimensionsCardinalityLimit
+}
+
 // updateCallMetrics increments the call count for the given metric key.
 func (p *processorImp) updateCallMetrics(key metricKey) {
 	p.callSum[key]++
@@ -406,17 +484,19 @@ func (p *processorImp) resetAccumulatedMetrics() {
 	p.latencyCount = make(map[metricKey]uint64)
 	p.latencySum = make(map[metricKey]float64)
 	p.latencyBucketCounts = make(map[metricKey][]uint64)
+	p.latencyExpHistograms = make(map[metricKey]*exphistogram.Histogram)
 	p.metricKeyToDimensions.Purge()
 }
 
 // updateLatencyExemplars sets the histogram exemplars for the given metric key and append the exemplar data.
-func (p *processorImp) updateLatencyExemplars(key metricKey, value float64, traceID pdata.TraceID) {
+func (p *processorImp) updateLatencyExemplars(key metricKey, value float64, traceID pdata.TraceID, spanID pdata.SpanID) {
 	if _, ok := p.latencyExemplarsData[key]; !ok {
 		p.latencyExemplarsData[key] = []exemplarData{}
 	}
 
 	e := exemplarData{
 		traceID: traceID,
+		spanID:  spanID,
 		value:   value,
 	}
 	p.latencyExemplarsData[key] = append(p.latencyExemplarsData[key], e)
@@ -431,6 +511,15 @@ func (p *processorImp) resetExemplarData() {
 
 // updateLatencyMetrics increments the histogram counts for the given metric key and bucket index.
 func (p *processorImp) updateLatencyMetrics(key metricKey, latency float64, index int) {
+	if p.expHistogramMaxSize > 0 {
+		histogram, ok := p.latencyExpHistograms[key]
+		if !ok {
+			histogram = exphistogram.New(p.expHistogramMaxSize)
+			p.latencyExpHistograms[key] = histogram
+		}
+		histogram.Record(latency)
+		return
+	}
 	if _, ok := p.latencyBucketCounts[key]; !ok {
 		p.latencyBucketCounts[key] = make([]uint64, len(p.latencyBounds))
 	}
@@ -453,6 +542,14 @@ func (p *processorImp) buildDimensionKVs(serviceName string, span pdata.Span, op
 	return dims
 }
 
+// buildOverflowDimensionKVs builds the dimensions of the overflow series of serviceName.
+func buildOverflowDimensionKVs(serviceName string) pdata.AttributeMap {
+	dims := pdata.NewAttributeMap()
+	dims.UpsertString(serviceNameKey, serviceName)
+	dims.UpsertBool(overflowKey, true)
+	return dims
+}
+
 func concatDimensionValue(metricKeyBuilder *strings.Builder, value string, prefixSep bool) {
 	// It's worth noting that from pprof benchmarks, WriteString is the most expensive operation of this processor.
 	// Specifically, the need to grow the underlying []byte slice to make room for the appended string.
@@ -484,6 +581,15 @@ func buildKey(serviceName string, span pdata.Span, optionalDims []Dimension, res
 	return k
 }
 
+// buildOverflowKey builds the metric key of the overflow series of serviceName. The key can't collide
+// with regular metric keys, which always contain at least the four default dimensions.
+func buildOverflowKey(serviceName string) metricKey {
+	var metricKeyBuilder strings.Builder
+	concatDimensionValue(&metricKeyBuilder, serviceName, false)
+	concatDimensionValue(&metricKeyBuilder, overflowKey, true)
+	return metricKey(metricKeyBuilder.String())
+}
+
 // getDimensionValue gets the dimension value for the given configured dimension.
 // It searches through the span's attributes first, being the more specific;
 // falling back to searching in resource attributes if it can't be found in the span.
@@ -560,6 +666,8 @@ func setLatencyExemplars(exemplarsData []exemplarData, timestamp pdata.Timestamp
 
 		exemplar.SetDoubleVal(value)
 		exemplar.SetTimestamp(timestamp)
+		exemplar.SetTraceID(traceID)
+		exemplar.SetSpanID(ed.spanID)
 		exemplar.FilteredAttributes().Insert(traceIDKey, pdata.NewAttributeValueString(traceID.HexString()))
 	}
 
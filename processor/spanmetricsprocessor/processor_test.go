@@ -35,6 +35,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/exphistogram"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/mocks"
 )
 
@@ -162,6 +163,169 @@ func TestConfigureLatencyBounds(t *testing.T) {
 	assert.Equal(t, []float64{0.000003, 0.003, 3, 3000, maxDurationMs}, p.latencyBounds)
 }
 
+func TestConfigureExponentialHistogram(t *testing.T) {
+	testcases := []struct {
+		name        string
+		buckets     []time.Duration
+		maxSize     int32
+		wantMaxSize int32
+		wantErr     string
+	}{
+		{
+			name:        "default max size",
+			wantMaxSize: defaultExponentialHistogramMaxSize,
+		},
+		{
+			name:        "max size",
+			maxSize:     20,
+			wantMaxSize: 20,
+		},
+		{
+			name:    "negative max size",
+			maxSize: -1,
+			wantErr: "invalid exponential histogram max size: -1, it should be positive",
+		},
+		{
+			name:    "explicit buckets",
+			buckets: []time.Duration{time.Millisecond},
+			wantErr: "latency_histogram_buckets can't be used with an exponential histogram",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.LatencyHistogramBuckets = tc.buckets
+			cfg.Histogram.Exponential = &ExponentialHistogramConfig{MaxSize: tc.maxSize}
+
+			p, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMaxSize, p.expHistogramMaxSize)
+		})
+	}
+}
+
+func TestConfigureDimensionsCardinalityLimit(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.DimensionsCardinalityLimit = -1
+
+	_, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+	assert.EqualError(t, err, "invalid dimensions cardinality limit: -1, it should be positive")
+}
+
+func TestProcessorConsumeTracesExponentialHistogram(t *testing.T) {
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+
+	mexp.On("ConsumeMetrics", mock.Anything, mock.MatchedBy(func(input pdata.Metrics) bool {
+		m := input.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		require.Equal(t, 6, m.Len())
+		for mi := 3; mi < m.Len(); mi++ {
+			assert.Equal(t, "latency", m.At(mi).Name())
+			require.Equal(t, pdata.MetricDataTypeExponentialHistogram, m.At(mi).DataType())
+
+			data := m.At(mi).ExponentialHistogram()
+			assert.Equal(t, pdata.MetricAggregationTemporalityDelta, data.AggregationTemporality())
+			require.Equal(t, 1, data.DataPoints().Len())
+
+			dp := data.DataPoints().At(0)
+			assert.Equal(t, uint64(1), dp.Count())
+			assert.Equal(t, sampleLatency, dp.Sum())
+			assert.Equal(t, []uint64{1}, dp.Positive().BucketCounts())
+			require.Equal(t, 1, dp.Exemplars().Len())
+			assert.False(t, dp.Exemplars().At(0).TraceID().IsEmpty())
+			verifyMetricLabels(dp, t, make(map[metricID]bool))
+		}
+		return true
+	})).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	defaultNullValue := "defaultNullValue"
+	p := newProcessorImp(mexp, tcon, &defaultNullValue, delta, t)
+	p.expHistogramMaxSize = defaultExponentialHistogramMaxSize
+
+	err := p.ConsumeTraces(context.Background(), buildSampleTrace())
+	assert.NoError(t, err)
+}
+
+func TestProcessorDimensionsCardinalityLimit(t *testing.T) {
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+
+	mexp.On("ConsumeMetrics", mock.Anything, mock.MatchedBy(func(input pdata.Metrics) bool {
+		m := input.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		// One regular and one overflow series for each of call count and latency.
+		require.Equal(t, 4, m.Len())
+
+		var overflowCalls int64
+		for mi := 0; mi < 2; mi++ {
+			dp := m.At(mi).Sum().DataPoints().At(0)
+			if _, ok := dp.Attributes().Get(overflowKey); ok {
+				overflowCalls += dp.IntVal()
+				assert.Equal(t, 2, dp.Attributes().Len())
+			}
+		}
+		// Only the first dimension set is tracked, service-a (client kind) exceeds the limit.
+		assert.Equal(t, int64(1), overflowCalls)
+		return true
+	})).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	defaultNullValue := "defaultNullValue"
+	p := newProcessorImp(mexp, tcon, &defaultNullValue, delta, t)
+	p.config.DimensionsCardinalityLimit = 1
+
+	traces := pdata.NewTraces()
+	initServiceSpans(
+		serviceSpans{
+			serviceName: "service-a",
+			spans: []span{
+				{operation: "/ping", kind: pdata.SpanKindServer, statusCode: pdata.StatusCodeOk},
+				{operation: "/ping", kind: pdata.SpanKindClient, statusCode: pdata.StatusCodeOk},
+			},
+		}, traces.ResourceSpans().AppendEmpty())
+
+	err := p.ConsumeTraces(context.Background(), traces)
+	assert.NoError(t, err)
+}
+
+func TestProcessorDimensionsCardinalityLimitAcrossFlushes(t *testing.T) {
+	for _, tc := range []struct {
+		temporality  string
+		wantOverflow bool
+	}{
+		// The dimension set of the first batch is still held by the processor.
+		{temporality: cumulative, wantOverflow: true},
+		{temporality: delta, wantOverflow: false},
+	} {
+		t.Run(tc.temporality, func(t *testing.T) {
+			defaultNullValue := "defaultNullValue"
+			p := newProcessorImp(&mocks.MetricsExporter{}, &mocks.TracesConsumer{}, &defaultNullValue, tc.temporality, t)
+			p.config.DimensionsCardinalityLimit = 1
+
+			for _, kind := range []pdata.SpanKind{pdata.SpanKindServer, pdata.SpanKindClient} {
+				traces := pdata.NewTraces()
+				initServiceSpans(
+					serviceSpans{
+						serviceName: "service-a",
+						spans:       []span{{operation: "/ping", kind: kind, statusCode: pdata.StatusCodeOk}},
+					}, traces.ResourceSpans().AppendEmpty())
+				p.aggregateMetrics(traces)
+				if kind == pdata.SpanKindServer {
+					_, err := p.buildMetrics()
+					require.NoError(t, err)
+				}
+			}
+
+			_, overflow := p.callSum[buildOverflowKey("service-a")]
+			assert.Equal(t, tc.wantOverflow, overflow)
+		})
+	}
+}
+
 func TestProcessorCapabilities(t *testing.T) {
 	// Prepare
 	factory := NewFactory()
@@ -354,6 +518,7 @@ func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, de
 		latencyBucketCounts:  make(map[metricKey][]uint64),
 		latencyBounds:        defaultLatencyHistogramBucketsMs,
 		latencyExemplarsData: make(map[metricKey][]exemplarData),
+		latencyExpHistograms: make(map[metricKey]*exphistogram.Histogram),
 		dimensions: []Dimension{
 			// Set nil defaults to force a lookup for the attribute in the span.
 			{stringAttrName, nil},
@@ -772,12 +937,14 @@ func TestSanitize(t *testing.T) {
 func TestSetLatencyExemplars(t *testing.T) {
 	// ----- conditions -------------------------------------------------------
 	traces := buildSampleTrace()
-	traceID := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+	span := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	traceID := span.TraceID()
+	spanID := span.SpanID()
 	exemplarSlice := pdata.NewExemplarSlice()
 	timestamp := pdata.NewTimestampFromTime(time.Now())
 	value := float64(42)
 
-	ed := []exemplarData{{traceID: traceID, value: value}}
+	ed := []exemplarData{{traceID: traceID, spanID: spanID, value: value}}
 
 	// ----- call -------------------------------------------------------------
 	setLatencyExemplars(ed, timestamp, exemplarSlice)
@@ -790,6 +957,8 @@ func TestSetLatencyExemplars(t *testing.T) {
 	assert.Equal(t, traceIDValue.AsString(), traceID.HexString())
 	assert.Equal(t, exemplarSlice.At(0).Timestamp(), timestamp)
 	assert.Equal(t, exemplarSlice.At(0).DoubleVal(), value)
+	assert.Equal(t, traceID, exemplarSlice.At(0).TraceID())
+	assert.Equal(t, spanID, exemplarSlice.At(0).SpanID())
 }
 
 func TestProcessorUpdateLatencyExemplars(t *testing.T) {
@@ -797,19 +966,21 @@ func TestProcessorUpdateLatencyExemplars(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
 	traces := buildSampleTrace()
-	traceID := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID()
+	span := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	traceID := span.TraceID()
+	spanID := span.SpanID()
 	key := metricKey("metricKey")
 	next := new(consumertest.TracesSink)
 	p, err := newProcessor(zaptest.NewLogger(t), cfg, next)
 	value := float64(42)
 
 	// ----- call -------------------------------------------------------------
-	p.updateLatencyExemplars(key, value, traceID)
+	p.updateLatencyExemplars(key, value, traceID, spanID)
 
 	// ----- verify -----------------------------------------------------------
 	assert.NoError(t, err)
 	assert.NotEmpty(t, p.latencyExemplarsData[key])
-	assert.Equal(t, p.latencyExemplarsData[key][0], exemplarData{traceID: traceID, value: value})
+	assert.Equal(t, p.latencyExemplarsData[key][0], exemplarData{traceID: traceID, spanID: spanID, value: value})
 }
 
 func TestProcessorResetExemplarData(t *testing.T) {
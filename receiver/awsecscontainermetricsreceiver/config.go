@@ -15,6 +15,7 @@
 package awsecscontainermetricsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver"
 
 import (
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -26,4 +27,43 @@ type Config struct {
 
 	// CollectionInterval is the interval at which metrics should be collected
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// TaskTags lists the tags of the task added as resource attributes of the metrics, e.g. to
+	// attribute costs to teams. The tags of the service are only available when they are
+	// propagated to its tasks.
+	TaskTags []TagAttribute `mapstructure:"task_tags"`
+
+	// ContainerInstanceTags lists the tags of the EC2 container instance running the task added as
+	// resource attributes of the metrics.
+	ContainerInstanceTags []TagAttribute `mapstructure:"container_instance_tags"`
+}
+
+// TagAttribute defines the resource attribute a tag is added as.
+type TagAttribute struct {
+	// Key is the key of the tag.
+	Key string `mapstructure:"key"`
+
+	// Attribute is the name of the resource attribute, which defaults to aws.ecs.task.tag.<key> for
+	// the task tags and aws.ecs.container_instance.tag.<key> for the container instance tags.
+	Attribute string `mapstructure:"attribute"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	for i, tag := range cfg.TaskTags {
+		if tag.Key == "" {
+			return fmt.Errorf("task_tags[%d].key has to be set", i)
+		}
+	}
+	for i, tag := range cfg.ContainerInstanceTags {
+		if tag.Key == "" {
+			return fmt.Errorf("container_instance_tags[%d].key has to be set", i)
+		}
+	}
+	return nil
+}
+
+// fetchTags returns whether tags are added as resource attributes.
+func (cfg *Config) fetchTags() bool {
+	return len(cfg.TaskTags) > 0 || len(cfg.ContainerInstanceTags) > 0
 }
@@ -38,7 +38,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 3)
 
 	r1 := cfg.Receivers[config.NewComponentID(typeStr)]
 	assert.Equal(t, r1, factory.CreateDefaultConfig())
@@ -49,4 +49,20 @@ func TestLoadConfig(t *testing.T) {
 			ReceiverSettings:   config.NewReceiverSettings(config.NewComponentIDWithName(typeStr, "collection_interval_settings")),
 			CollectionInterval: 10 * time.Second,
 		})
+
+	r3 := cfg.Receivers[config.NewComponentIDWithName(typeStr, "tags")].(*Config)
+	assert.Equal(t, []TagAttribute{{Key: "team"}, {Key: "cost-center", Attribute: "cost_center"}}, r3.TaskTags)
+	assert.Equal(t, []TagAttribute{{Key: "env"}}, r3.ContainerInstanceTags)
+}
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TaskTags = []TagAttribute{{Key: "team"}, {Attribute: "cost_center"}}
+	assert.EqualError(t, cfg.Validate(), "task_tags[1].key has to be set")
+
+	cfg.TaskTags = nil
+	cfg.ContainerInstanceTags = []TagAttribute{{}}
+	assert.EqualError(t, cfg.Validate(), "container_instance_tags[0].key has to be set")
 }
@@ -12,7 +12,6 @@ require (
 	go.opentelemetry.io/collector/model v0.42.0
 	go.uber.org/zap v1.20.0
 	google.golang.org/protobuf v1.27.1
-
 )
 
 require (
@@ -42,6 +41,7 @@ require (
 	golang.org/x/net v0.0.0-20210813160813-60bc85c4be6d // indirect
 	golang.org/x/sys v0.0.0-20211205182925-97ca703d548d // indirect
 	golang.org/x/text v0.3.7 // indirect
+	golang.org/x/time v0.0.0-20211116232009-f0f3c7e86c11 // indirect
 	google.golang.org/genproto v0.0.0-20211208223120-3a66f561d7aa // indirect
 	google.golang.org/grpc v1.43.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
@@ -33,6 +33,7 @@ const (
 	attributeContainerFinishedAt  = "aws.ecs.container.finished_at"
 	attributeContainerKnownStatus = "aws.ecs.container.know_status"
 	attributeContainerExitCode    = "aws.ecs.container.exit_code"
+	attributeVolumeDevice         = "aws.ecs.volume.device"
 
 	cpusInVCpu = 1024
 	bytesInMiB = 1024 * 1024
@@ -71,6 +72,9 @@ const (
 	attributeStorageRead  = "storage.read_bytes"
 	attributeStorageWrite = "storage.write_bytes"
 
+	attributeVolumeReadBytes  = "volume.read_bytes"
+	attributeVolumeWriteBytes = "volume.write_bytes"
+
 	attributeDuration = "duration"
 
 	unitBytes       = "Bytes"
@@ -46,4 +46,13 @@ type ECSMetrics struct {
 
 	StorageReadBytes  uint64
 	StorageWriteBytes uint64
+
+	Volumes []VolumeMetrics
+}
+
+// VolumeMetrics defines the IO metrics of a block device used by a container, e.g. an attached EBS volume
+type VolumeMetrics struct {
+	Device     string
+	ReadBytes  uint64
+	WriteBytes uint64
 }
@@ -15,6 +15,8 @@
 package awsecscontainermetrics // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/internal/awsecscontainermetrics"
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 )
 
@@ -85,11 +87,40 @@ func getContainerMetrics(stats *ContainerStats, logger *zap.Logger) ECSMetrics {
 
 		m.StorageReadBytes = storageReadBytes
 		m.StorageWriteBytes = storageWriteBytes
+		m.Volumes = getVolumeMetrics(stats.Disk)
 	}
 
 	return m
 }
 
+// getVolumeMetrics returns the read and written bytes of every block device in the blkio stats of
+// the container, identified by their major and minor numbers, in the order they are first reported.
+func getVolumeMetrics(stats *DiskStats) []VolumeMetrics {
+	var m []VolumeMetrics
+	index := map[string]int{}
+	for _, blockStat := range stats.IoServiceBytesRecursives {
+		if blockStat.Major == nil || blockStat.Minor == nil || blockStat.Value == nil {
+			continue
+		}
+		if blockStat.Op != "Read" && blockStat.Op != "Write" {
+			continue
+		}
+		device := fmt.Sprintf("%d:%d", *blockStat.Major, *blockStat.Minor)
+		i, ok := index[device]
+		if !ok {
+			i = len(m)
+			index[device] = i
+			m = append(m, VolumeMetrics{Device: device})
+		}
+		if blockStat.Op == "Read" {
+			m[i].ReadBytes = *blockStat.Value
+		} else {
+			m[i].WriteBytes = *blockStat.Value
+		}
+	}
+	return m
+}
+
 // Followed ECS Agent calculations
 // https://github.com/aws/amazon-ecs-agent/blob/1ebf0604c13013596cfd4eb239574a85890b13e8/agent/stats/utils.go#L30
 func getNetworkStats(stats map[string]NetworkStats) [8]uint64 {
@@ -288,6 +288,25 @@ func TestExtractStorageUsage(t *testing.T) {
 	require.EqualValues(t, v, write)
 }
 
+func TestGetVolumeMetrics(t *testing.T) {
+	major, rootMinor, ebsMinor := uint64(259), uint64(0), uint64(3)
+	read, write, total := uint64(10), uint64(20), uint64(30)
+	stats := &DiskStats{IoServiceBytesRecursives: []IoServiceBytesRecursive{
+		{Major: &major, Minor: &rootMinor, Op: "Read", Value: &v},
+		{Major: &major, Minor: &rootMinor, Op: "Write", Value: &v},
+		{Major: &major, Minor: &ebsMinor, Op: "Read", Value: &read},
+		{Major: &major, Minor: &ebsMinor, Op: "Write", Value: &write},
+		{Major: &major, Minor: &ebsMinor, Op: "Total", Value: &total},
+		{Op: "Read", Value: &read},
+	}}
+
+	require.Equal(t, []VolumeMetrics{
+		{Device: "259:0", ReadBytes: v, WriteBytes: v},
+		{Device: "259:3", ReadBytes: read, WriteBytes: write},
+	}, getVolumeMetrics(stats))
+	require.Nil(t, getVolumeMetrics(&DiskStats{}))
+}
+
 func TestGetNetworkStats(t *testing.T) {
 	v := uint64(100)
 	stats := make(map[string]NetworkStats)
@@ -27,11 +27,13 @@ import (
 type StatsProvider struct {
 	rc               ecsutil.RestClient
 	metadataProvider ecsutil.MetadataProvider
+	withTags         bool
 }
 
-// NewStatsProvider returns a new stats provider
-func NewStatsProvider(rc ecsutil.RestClient, logger *zap.Logger) *StatsProvider {
-	return &StatsProvider{rc: rc, metadataProvider: ecsutil.NewTaskMetadataProvider(rc, logger)}
+// NewStatsProvider returns a new stats provider, which fetches the task metadata along with the tags
+// of the task and of its container instance if withTags is true
+func NewStatsProvider(rc ecsutil.RestClient, logger *zap.Logger, withTags bool) *StatsProvider {
+	return &StatsProvider{rc: rc, metadataProvider: ecsutil.NewTaskMetadataProvider(rc, logger), withTags: withTags}
 }
 
 // GetStats calls the ecs task metadata endpoint and unmarshals the data
@@ -39,7 +41,11 @@ func (p *StatsProvider) GetStats() (map[string]*ContainerStats, ecsutil.TaskMeta
 	stats := make(map[string]*ContainerStats)
 	var metadata ecsutil.TaskMetadata
 
-	taskMetadata, err := p.metadataProvider.FetchTaskMetadata()
+	fetchTaskMetadata := p.metadataProvider.FetchTaskMetadata
+	if p.withTags {
+		fetchTaskMetadata = p.metadataProvider.FetchTaskMetadataWithTags
+	}
+	taskMetadata, err := fetchTaskMetadata()
 	if err != nil {
 		return stats, metadata, fmt.Errorf("cannot read data from task metadata endpoint: %w", err)
 	}
@@ -76,7 +76,7 @@ func TestGetStats(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			provider := NewStatsProvider(tt.client, zap.NewNop())
+			provider := NewStatsProvider(tt.client, zap.NewNop(), false)
 			stats, metadata, err := provider.GetStats()
 			if tt.wantError == "" {
 				require.NoError(t, err)
@@ -88,3 +88,11 @@ func TestGetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStatsWithTags(t *testing.T) {
+	provider := NewStatsProvider(&testRestClient{}, zap.NewNop(), true)
+	_, metadata, err := provider.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "checkout", "cost-center": "1234"}, metadata.TaskTags)
+	assert.Equal(t, map[string]string{"env": "prod"}, metadata.ContainerInstanceTags)
+}
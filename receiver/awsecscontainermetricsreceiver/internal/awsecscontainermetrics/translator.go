@@ -58,9 +58,29 @@ func convertToOTLPMetrics(prefix string, m ECSMetrics, r pdata.Resource, timesta
 	appendIntSum(prefix+attributeStorageRead, unitBytes, int64(m.StorageReadBytes), timestamp, ilms.AppendEmpty())
 	appendIntSum(prefix+attributeStorageWrite, unitBytes, int64(m.StorageWriteBytes), timestamp, ilms.AppendEmpty())
 
+	appendVolumeMetrics(prefix, m.Volumes, timestamp, ilms)
+
 	return md
 }
 
+// appendVolumeMetrics appends the read and written bytes metrics of the volumes, with a data point per
+// volume identified by its device.
+func appendVolumeMetrics(prefix string, volumes []VolumeMetrics, timestamp pdata.Timestamp, ilms pdata.InstrumentationLibraryMetricsSlice) {
+	if len(volumes) == 0 {
+		return
+	}
+	read := appendMetric(ilms.AppendEmpty(), prefix+attributeVolumeReadBytes, unitBytes)
+	read.SetDataType(pdata.MetricDataTypeSum)
+	read.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	write := appendMetric(ilms.AppendEmpty(), prefix+attributeVolumeWriteBytes, unitBytes)
+	write.SetDataType(pdata.MetricDataTypeSum)
+	write.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	for _, v := range volumes {
+		appendIntDataPoint(read.Sum().DataPoints(), int64(v.ReadBytes), timestamp).Attributes().InsertString(attributeVolumeDevice, v.Device)
+		appendIntDataPoint(write.Sum().DataPoints(), int64(v.WriteBytes), timestamp).Attributes().InsertString(attributeVolumeDevice, v.Device)
+	}
+}
+
 func convertStoppedContainerDataToOTMetrics(prefix string, containerResource pdata.Resource, timestamp pdata.Timestamp, duration float64) pdata.Metrics {
 	md := pdata.NewMetrics()
 	rm := md.ResourceMetrics().AppendEmpty()
@@ -100,10 +120,11 @@ func appendDoubleGauge(metricName string, unit string, value float64, ts pdata.T
 	dataPoint.SetTimestamp(ts)
 }
 
-func appendIntDataPoint(dataPoints pdata.NumberDataPointSlice, value int64, ts pdata.Timestamp) {
+func appendIntDataPoint(dataPoints pdata.NumberDataPointSlice, value int64, ts pdata.Timestamp) pdata.NumberDataPoint {
 	dataPoint := dataPoints.AppendEmpty()
 	dataPoint.SetIntVal(value)
 	dataPoint.SetTimestamp(ts)
+	return dataPoint
 }
 
 func appendMetric(ilm pdata.InstrumentationLibraryMetrics, name, unit string) pdata.Metric {
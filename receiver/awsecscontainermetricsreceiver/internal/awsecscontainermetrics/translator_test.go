@@ -39,6 +39,35 @@ func TestConvertToOTMetrics(t *testing.T) {
 	assert.EqualValues(t, conventions.SchemaURL, md.ResourceMetrics().At(0).SchemaUrl())
 }
 
+func TestConvertVolumeMetrics(t *testing.T) {
+	timestamp := pdata.NewTimestampFromTime(time.Now())
+	m := ECSMetrics{Volumes: []VolumeMetrics{
+		{Device: "259:0", ReadBytes: 1024, WriteBytes: 2048},
+		{Device: "259:3", ReadBytes: 10, WriteBytes: 20},
+	}}
+
+	md := convertToOTLPMetrics("container.", m, pdata.NewResource(), timestamp)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	require.EqualValues(t, 28, ilms.Len())
+
+	readMetric := ilms.At(26).Metrics().At(0)
+	assert.Equal(t, "container.volume.read_bytes", readMetric.Name())
+	require.Equal(t, 2, readMetric.Sum().DataPoints().Len())
+	assert.Equal(t, map[string]interface{}{"aws.ecs.volume.device": "259:0"}, readMetric.Sum().DataPoints().At(0).Attributes().AsRaw())
+	assert.EqualValues(t, 1024, readMetric.Sum().DataPoints().At(0).IntVal())
+	assert.Equal(t, map[string]interface{}{"aws.ecs.volume.device": "259:3"}, readMetric.Sum().DataPoints().At(1).Attributes().AsRaw())
+	assert.EqualValues(t, 10, readMetric.Sum().DataPoints().At(1).IntVal())
+
+	writeMetric := ilms.At(27).Metrics().At(0)
+	assert.Equal(t, "container.volume.write_bytes", writeMetric.Name())
+	require.Equal(t, 2, writeMetric.Sum().DataPoints().Len())
+	assert.EqualValues(t, 20, writeMetric.Sum().DataPoints().At(1).IntVal())
+
+	// No volume metrics are appended without volumes.
+	md = convertToOTLPMetrics("container.", ECSMetrics{}, pdata.NewResource(), timestamp)
+	require.EqualValues(t, 26, md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Len())
+}
+
 func TestIntGauge(t *testing.T) {
 	intValue := int64(100)
 	timestamp := pdata.NewTimestampFromTime(time.Now())
@@ -21,12 +21,18 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/aws/ecsutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/internal/awsecscontainermetrics"
 )
 
+const (
+	taskTagAttributePrefix              = "aws.ecs.task.tag."
+	containerInstanceTagAttributePrefix = "aws.ecs.container_instance.tag."
+)
+
 var _ component.MetricsReceiver = (*awsEcsContainerMetricsReceiver)(nil)
 
 // awsEcsContainerMetricsReceiver implements the component.MetricsReceiver for aws ecs container metrics.
@@ -85,7 +91,7 @@ func (aecmr *awsEcsContainerMetricsReceiver) Shutdown(context.Context) error {
 
 // collectDataFromEndpoint collects container stats from Amazon ECS Task Metadata Endpoint
 func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context.Context) error {
-	aecmr.provider = awsecscontainermetrics.NewStatsProvider(aecmr.restClient, aecmr.logger)
+	aecmr.provider = awsecscontainermetrics.NewStatsProvider(aecmr.restClient, aecmr.logger, aecmr.config.fetchTags())
 	stats, metadata, err := aecmr.provider.GetStats()
 
 	if err != nil {
@@ -95,7 +101,9 @@ func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context
 
 	// TODO: report self metrics using obsreport
 	mds := awsecscontainermetrics.MetricsData(stats, metadata, aecmr.logger)
+	tags := aecmr.tagAttributes(metadata)
 	for _, md := range mds {
+		addTagAttributes(md, tags)
 		err = aecmr.nextConsumer.ConsumeMetrics(ctx, md)
 		if err != nil {
 			return err
@@ -104,3 +112,42 @@ func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context
 
 	return nil
 }
+
+// tagAttributes returns the resource attributes set from the configured tags of the task and of its
+// container instance. Tags missing from the metadata are skipped.
+func (aecmr *awsEcsContainerMetricsReceiver) tagAttributes(metadata ecsutil.TaskMetadata) map[string]string {
+	attributes := make(map[string]string)
+	for _, tag := range aecmr.config.TaskTags {
+		if value, ok := metadata.TaskTags[tag.Key]; ok {
+			attributes[tagAttributeName(tag, taskTagAttributePrefix)] = value
+		}
+	}
+	for _, tag := range aecmr.config.ContainerInstanceTags {
+		if value, ok := metadata.ContainerInstanceTags[tag.Key]; ok {
+			attributes[tagAttributeName(tag, containerInstanceTagAttributePrefix)] = value
+		}
+	}
+	return attributes
+}
+
+func tagAttributeName(tag TagAttribute, prefix string) string {
+	if tag.Attribute != "" {
+		return tag.Attribute
+	}
+	return prefix + tag.Key
+}
+
+// addTagAttributes adds the tag attributes to the resources of md, without overriding the
+// attributes read from the task metadata.
+func addTagAttributes(md pdata.Metrics, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		attrs := rms.At(i).Resource().Attributes()
+		for k, v := range tags {
+			attrs.InsertString(k, v)
+		}
+	}
+}
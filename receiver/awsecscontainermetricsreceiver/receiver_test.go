@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
@@ -96,6 +97,39 @@ func TestCollectDataFromEndpoint(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCollectDataFromEndpointWithTags(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TaskTags = []TagAttribute{{Key: "team"}, {Key: "cost-center", Attribute: "cost_center"}, {Key: "missing"}}
+	cfg.ContainerInstanceTags = []TagAttribute{{Key: "env"}}
+	sink := new(consumertest.MetricsSink)
+	metricsReceiver, err := newAWSECSContainermetrics(
+		zap.NewNop(),
+		cfg,
+		sink,
+		&fakeRestClient{t},
+	)
+	require.NoError(t, err)
+
+	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	require.NoError(t, r.collectDataFromEndpoint(context.Background()))
+
+	require.NotEmpty(t, sink.AllMetrics())
+	for _, md := range sink.AllMetrics() {
+		attrs := md.ResourceMetrics().At(0).Resource().Attributes()
+		for k, v := range map[string]string{
+			"aws.ecs.task.tag.team":              "checkout",
+			"cost_center":                        "1234",
+			"aws.ecs.container_instance.tag.env": "prod",
+		} {
+			attr, ok := attrs.Get(k)
+			require.True(t, ok, k)
+			assert.Equal(t, v, attr.StringVal())
+		}
+		_, ok := attrs.Get("aws.ecs.task.tag.missing")
+		assert.False(t, ok)
+	}
+}
+
 func TestCollectDataFromEndpointWithConsumerError(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/cloudflarereceiver"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var (
+	errNoEndpoint     = errors.New("endpoint must be specified")
+	errEmptyAttribute = errors.New("attributes must not contain empty field or attribute names")
+	errMaxBodySize    = errors.New("max_request_body_size must be positive")
+)
+
+// Config defines configuration for the Cloudflare receiver.
+type Config struct {
+	config.ReceiverSettings       `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Secret is the value the X-CF-Secret header of the requests has to be equal to. It is set in the
+	// destination of the Logpush job with the header_X-CF-Secret parameter. Requests are not
+	// authenticated if empty.
+	Secret string `mapstructure:"secret"`
+	// MaxRequestBodySize is the maximum size in bytes of the payload of a request, both as received and once
+	// decompressed.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+	// TimestampField is the field of the logs holding their timestamp.
+	TimestampField string `mapstructure:"timestamp_field"`
+	// Attributes maps fields of the logs to attributes, in addition to the fields mapped to the
+	// HTTP semantic conventions.
+	Attributes map[string]string `mapstructure:"attributes"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errNoEndpoint
+	}
+	if cfg.MaxRequestBodySize <= 0 {
+		return errMaxBodySize
+	}
+	for field, attribute := range cfg.Attributes {
+		if field == "" || attribute == "" {
+			return errEmptyAttribute
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.Len(t, cfg.Receivers, 2)
+
+	require.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	expected := factory.CreateDefaultConfig().(*Config)
+	expected.SetIDName("custom")
+	expected.Endpoint = "0.0.0.0:9443"
+	expected.TLSSetting = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "/etc/otel/cert.pem",
+			KeyFile:  "/etc/otel/key.pem",
+		},
+	}
+	expected.Secret = "1234abcd"
+	expected.MaxRequestBodySize = 10485760
+	expected.TimestampField = "EdgeEndTimestamp"
+	expected.Attributes = map[string]string{
+		"ClientRequestReferer": "http.referer",
+		"ClientCountry":        "client.geo.country",
+	}
+	require.Equal(t, expected, cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")])
+}
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Endpoint = ""
+	require.ErrorIs(t, cfg.Validate(), errNoEndpoint)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MaxRequestBodySize = 0
+	require.ErrorIs(t, cfg.Validate(), errMaxBodySize)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.Attributes = map[string]string{"ClientCountry": ""}
+	require.ErrorIs(t, cfg.Validate(), errEmptyAttribute)
+}
@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/cloudflarereceiver"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+const (
+	// statusField is the field of the logs of the http_requests dataset holding the response status.
+	statusField = "EdgeResponseStatus"
+	// protocolField is the field of the logs of the http_requests dataset holding the protocol of the request.
+	protocolField = "ClientRequestProtocol"
+)
+
+// semanticAttributes maps the fields of the logs of the http_requests dataset to the attributes
+// of the HTTP semantic conventions and to Cloudflare specific attributes.
+var semanticAttributes = map[string]string{
+	"ClientIP":               conventions.AttributeHTTPClientIP,
+	"ClientRequestBytes":     conventions.AttributeHTTPRequestContentLength,
+	"ClientRequestHost":      conventions.AttributeHTTPHost,
+	"ClientRequestMethod":    conventions.AttributeHTTPMethod,
+	"ClientRequestScheme":    conventions.AttributeHTTPScheme,
+	"ClientRequestURI":       conventions.AttributeHTTPTarget,
+	"ClientRequestUserAgent": conventions.AttributeHTTPUserAgent,
+	"EdgeResponseBytes":      conventions.AttributeHTTPResponseContentLength,
+	statusField:              conventions.AttributeHTTPStatusCode,
+	protocolField:            conventions.AttributeHTTPFlavor,
+	"RayID":                  "cloudflare.ray_id",
+	"ZoneName":               "cloudflare.zone.name",
+}
+
+// logConverter converts the lines of the NDJSON payloads of Logpush jobs to log records.
+type logConverter struct {
+	timestampField string
+	attributes     map[string]string
+}
+
+func newLogConverter(cfg *Config) *logConverter {
+	attributes := make(map[string]string, len(semanticAttributes)+len(cfg.Attributes))
+	for field, attribute := range semanticAttributes {
+		attributes[field] = attribute
+	}
+	for field, attribute := range cfg.Attributes {
+		attributes[field] = attribute
+	}
+	return &logConverter{
+		timestampField: cfg.TimestampField,
+		attributes:     attributes,
+	}
+}
+
+// appendLog appends the log record of a line to logs. The body of the record is the line, its timestamp
+// is the time it was received at unless the line holds one.
+func (c *logConverter) appendLog(logs pdata.LogSlice, line []byte, received pdata.Timestamp) error {
+	fields := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	// Preserve the precision of the timestamps in nanoseconds.
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return fmt.Errorf("failed to decode log: %w", err)
+	}
+
+	lr := logs.AppendEmpty()
+	lr.Body().SetStringVal(string(line))
+	lr.SetTimestamp(received)
+	if value, ok := fields[c.timestampField]; ok {
+		if ts, ok := parseTimestamp(value); ok {
+			lr.SetTimestamp(ts)
+		}
+	}
+
+	attrs := lr.Attributes()
+	for field, value := range fields {
+		attribute, ok := c.attributes[field]
+		if !ok {
+			continue
+		}
+		if field == protocolField {
+			if protocol, ok := value.(string); ok {
+				// The flavor attribute doesn't include the protocol name, e.g. 1.1 for HTTP/1.1.
+				value = strings.TrimPrefix(protocol, "HTTP/")
+			}
+		}
+		insertAttribute(attrs, attribute, value)
+	}
+
+	if status, ok := fields[statusField].(json.Number); ok {
+		if code, err := status.Int64(); err == nil {
+			setSeverity(lr, code)
+		}
+	}
+	return nil
+}
+
+// setSeverity sets the severity of a log record from the status of the response, server errors are
+// errors and client errors warnings.
+func setSeverity(lr pdata.LogRecord, status int64) {
+	switch {
+	case status >= 500:
+		lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+		lr.SetSeverityText("ERROR")
+	case status >= 400:
+		lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+		lr.SetSeverityText("WARN")
+	default:
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+		lr.SetSeverityText("INFO")
+	}
+}
+
+// parseTimestamp parses a timestamp in one of the formats of Logpush jobs: RFC 3339, or seconds or
+// nanoseconds since the epoch. Milliseconds and microseconds are also accepted.
+func parseTimestamp(value interface{}) (pdata.Timestamp, bool) {
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return 0, false
+		}
+		return pdata.NewTimestampFromTime(t), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			switch {
+			case i >= 1e17:
+				return pdata.Timestamp(i), true
+			case i >= 1e14:
+				return pdata.NewTimestampFromTime(time.UnixMicro(i)), true
+			case i >= 1e11:
+				return pdata.NewTimestampFromTime(time.UnixMilli(i)), true
+			default:
+				return pdata.NewTimestampFromTime(time.Unix(i, 0)), true
+			}
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return pdata.Timestamp(f * float64(time.Second)), true
+	}
+	return 0, false
+}
+
+// insertAttribute inserts a decoded JSON value into attrs, objects and arrays are inserted as JSON.
+func insertAttribute(attrs pdata.AttributeMap, key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		attrs.InsertString(key, v)
+	case bool:
+		attrs.InsertBool(key, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			attrs.InsertInt(key, i)
+		} else if f, err := v.Float64(); err == nil {
+			attrs.InsertDouble(key, f)
+		} else {
+			attrs.InsertString(key, v.String())
+		}
+	case nil:
+	default:
+		if b, err := json.Marshal(v); err == nil {
+			attrs.InsertString(key, string(b))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestAppendLog(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Attributes = map[string]string{
+		"ClientRequestReferer": "http.referer",
+		"Cookies":              "http.cookies",
+		// Overrides the semantic attribute.
+		"ZoneName": "zone",
+	}
+	converter := newLogConverter(cfg)
+
+	logs := pdata.NewLogSlice()
+	line := `{"ClientIP":"89.163.242.206","ClientRequestMethod":"GET","ClientRequestProtocol":"HTTP/1.1",` +
+		`"ClientRequestReferer":"https://example.com/","ClientRequestUserAgent":"curl/7.79.1","Cookies":{"session":"abc"},` +
+		`"EdgeResponseStatus":404,"EdgeStartTimestamp":1642015901123456789,"RayID":"6cb4c6d8ff2a5e1a","ZoneName":"example.com","WAFScore":0.5}`
+	require.NoError(t, converter.appendLog(logs, []byte(line), pdata.Timestamp(1)))
+	require.Equal(t, 1, logs.Len())
+
+	lr := logs.At(0)
+	assert.Equal(t, line, lr.Body().StringVal())
+	assert.Equal(t, pdata.Timestamp(1642015901123456789), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "WARN", lr.SeverityText())
+	assert.Equal(t, map[string]interface{}{
+		"http.client_ip":    "89.163.242.206",
+		"http.method":       "GET",
+		"http.flavor":       "1.1",
+		"http.referer":      "https://example.com/",
+		"http.user_agent":   "curl/7.79.1",
+		"http.cookies":      `{"session":"abc"}`,
+		"http.status_code":  int64(404),
+		"cloudflare.ray_id": "6cb4c6d8ff2a5e1a",
+		"zone":              "example.com",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestAppendLogWithoutTimestamp(t *testing.T) {
+	converter := newLogConverter(createDefaultConfig().(*Config))
+	logs := pdata.NewLogSlice()
+	require.NoError(t, converter.appendLog(logs, []byte(`{"EdgeStartTimestamp":"yesterday"}`), pdata.Timestamp(1)))
+	require.NoError(t, converter.appendLog(logs, []byte(`{"ClientRequestHost":"example.com"}`), pdata.Timestamp(2)))
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, pdata.Timestamp(1), logs.At(0).Timestamp())
+	assert.Equal(t, pdata.Timestamp(2), logs.At(1).Timestamp())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logs.At(1).SeverityNumber())
+}
+
+func TestAppendLogInvalid(t *testing.T) {
+	converter := newLogConverter(createDefaultConfig().(*Config))
+	logs := pdata.NewLogSlice()
+	require.Error(t, converter.appendLog(logs, []byte(`{"RayID":`), 0))
+	require.Error(t, converter.appendLog(logs, []byte(`["RayID"]`), 0))
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2022, 1, 12, 19, 31, 41, 0, time.UTC)
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+		want  time.Time
+	}{
+		{name: "rfc3339", value: "2022-01-12T19:31:41Z", want: expected},
+		{name: "rfc3339 nano", value: "2022-01-12T19:31:41.123456789Z", want: expected.Add(123456789)},
+		{name: "unix", value: json.Number("1642015901"), want: expected},
+		{name: "unix float", value: json.Number("1642015901.5"), want: expected.Add(500 * time.Millisecond)},
+		{name: "unix milli", value: json.Number("1642015901123"), want: expected.Add(123 * time.Millisecond)},
+		{name: "unix micro", value: json.Number("1642015901123456"), want: expected.Add(123456 * time.Microsecond)},
+		{name: "unix nano", value: json.Number("1642015901123456789"), want: expected.Add(123456789)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, ok := parseTimestamp(tc.value)
+			require.True(t, ok)
+			assert.Equal(t, tc.want.UnixNano(), int64(ts))
+		})
+	}
+
+	_, ok := parseTimestamp("12/01/2022")
+	assert.False(t, ok)
+	_, ok = parseTimestamp(true)
+	assert.False(t, ok)
+}
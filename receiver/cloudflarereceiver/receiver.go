@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/cloudflarereceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	secretHeader = "X-CF-Secret"
+
+	gzipEncoding = "gzip"
+
+	defaultServerTimeout = 20 * time.Second
+)
+
+var (
+	errInvalidMethod   = errors.New("invalid http method")
+	errInvalidSecret   = errors.New("invalid secret")
+	errInvalidEncoding = errors.New("invalid encoding")
+	errBodyTooLarge    = errors.New("request body too large")
+	errInvalidLogs     = errors.New("invalid logs")
+)
+
+// logsReceiver receives the logs pushed by Cloudflare Logpush jobs.
+type logsReceiver struct {
+	settings   component.ReceiverCreateSettings
+	config     *Config
+	consumer   consumer.Logs
+	converter  *logConverter
+	server     *http.Server
+	shutdownWG sync.WaitGroup
+	obsrecv    *obsreport.Receiver
+}
+
+var _ component.LogsReceiver = (*logsReceiver)(nil)
+
+func newLogsReceiver(settings component.ReceiverCreateSettings, cfg *Config, next consumer.Logs) *logsReceiver {
+	transport := "http"
+	if cfg.TLSSetting != nil {
+		transport = "https"
+	}
+	return &logsReceiver{
+		settings:  settings,
+		config:    cfg,
+		consumer:  next,
+		converter: newLogConverter(cfg),
+		obsrecv: obsreport.NewReceiver(obsreport.ReceiverSettings{
+			ReceiverID:             cfg.ID(),
+			Transport:              transport,
+			ReceiverCreateSettings: settings,
+		}),
+	}
+}
+
+// Start starts the server receiving the logs.
+func (r *logsReceiver) Start(_ context.Context, host component.Host) error {
+	if r.config.Secret == "" {
+		r.settings.Logger.Warn("No secret is configured, the receiver accepts logs from any client of its endpoint",
+			zap.String("endpoint", r.config.Endpoint))
+	}
+
+	ln, err := r.config.HTTPServerSettings.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+	}
+
+	r.server, err = r.config.HTTPServerSettings.ToServer(host, r.settings.TelemetrySettings, http.HandlerFunc(r.handleReq))
+	if err != nil {
+		return err
+	}
+	r.server.ReadHeaderTimeout = defaultServerTimeout
+	r.server.WriteTimeout = defaultServerTimeout
+
+	r.shutdownWG.Add(1)
+	go func() {
+		defer r.shutdownWG.Done()
+		if errHTTP := r.server.Serve(ln); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the server.
+func (r *logsReceiver) Shutdown(context.Context) error {
+	if r.server == nil {
+		return nil
+	}
+	err := r.server.Close()
+	r.shutdownWG.Wait()
+	return err
+}
+
+// handleReq converts the lines of the payload of a request to logs. The response status tells Logpush
+// whether to retry the request.
+func (r *logsReceiver) handleReq(w http.ResponseWriter, req *http.Request) {
+	ctx := r.obsrecv.StartLogsOp(req.Context())
+
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, w, http.StatusMethodNotAllowed, 0, errInvalidMethod)
+		return
+	}
+	if r.config.Secret != "" && subtle.ConstantTimeCompare([]byte(req.Header.Get(secretHeader)), []byte(r.config.Secret)) != 1 {
+		r.failRequest(ctx, w, http.StatusUnauthorized, 0, errInvalidSecret)
+		return
+	}
+
+	if req.ContentLength > r.config.MaxRequestBodySize {
+		r.failRequest(ctx, w, http.StatusRequestEntityTooLarge, 0, errBodyTooLarge)
+		return
+	}
+	var body io.Reader = http.MaxBytesReader(w, req.Body, r.config.MaxRequestBodySize)
+	switch req.Header.Get("Content-Encoding") {
+	case "":
+	case gzipEncoding:
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			r.failRequest(ctx, w, http.StatusBadRequest, 0, err)
+			return
+		}
+		defer gz.Close()
+		body = &limitedReader{reader: gz, remaining: r.config.MaxRequestBodySize}
+	default:
+		r.failRequest(ctx, w, http.StatusUnsupportedMediaType, 0, errInvalidEncoding)
+		return
+	}
+
+	logs := pdata.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	received := pdata.NewTimestampFromTime(time.Now())
+	reader := bufio.NewReader(body)
+	invalid := 0
+	var invalidErr error
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			// An invalid line doesn't fail the request, Logpush would push the whole batch again.
+			if convErr := r.converter.appendLog(records, line, received); convErr != nil {
+				invalid++
+				invalidErr = convErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, errBodyTooLarge) {
+			r.failRequest(ctx, w, http.StatusRequestEntityTooLarge, records.Len(), err)
+			return
+		}
+		if err != nil {
+			r.failRequest(ctx, w, http.StatusBadRequest, records.Len(), err)
+			return
+		}
+	}
+	if invalid > 0 {
+		r.settings.Logger.Debug("Skipped invalid Cloudflare logs", zap.Int("count", invalid), zap.Error(invalidErr))
+		// The skipped lines are reported as refused log records.
+		r.obsrecv.EndLogsOp(r.obsrecv.StartLogsOp(req.Context()), typeStr, invalid, fmt.Errorf("%w: %v", errInvalidLogs, invalidErr))
+	}
+
+	if records.Len() > 0 {
+		if err := r.consumer.ConsumeLogs(ctx, logs); err != nil {
+			r.failRequest(ctx, w, http.StatusInternalServerError, records.Len(), err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	r.obsrecv.EndLogsOp(ctx, typeStr, records.Len(), nil)
+}
+
+func (r *logsReceiver) failRequest(ctx context.Context, w http.ResponseWriter, status int, numRecords int, err error) {
+	http.Error(w, http.StatusText(status), status)
+	r.settings.Logger.Debug("Cloudflare logs request failed", zap.Int("status", status), zap.Error(err))
+	r.obsrecv.EndLogsOp(ctx, typeStr, numRecords, err)
+}
+
+// limitedReader reads from reader until remaining bytes are read, and fails with errBodyTooLarge
+// if there are more.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errBodyTooLarge
+	}
+	// Reading a byte more than remaining tells whether the payload is too large.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), errBodyTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudflarereceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testutil"
+)
+
+func gzipPayload(t *testing.T, payload []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(payload)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.Secret = "1234abcd"
+	sink := new(consumertest.LogsSink)
+	r := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", "http_requests.ndjson"))
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "http://"+cfg.Endpoint+"/logs", bytes.NewReader(gzipPayload(t, payload)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set(secretHeader, "1234abcd")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, sink.AllLogs(), 1)
+	logs := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+	method, _ := logs.At(0).Attributes().Get("http.method")
+	assert.Equal(t, "GET", method.StringVal())
+	status, _ := logs.At(1).Attributes().Get("http.status_code")
+	assert.Equal(t, int64(503), status.IntVal())
+}
+
+func TestHandleReq(t *testing.T) {
+	line := `{"ClientRequestMethod":"GET","EdgeResponseStatus":200}`
+	for _, tc := range []struct {
+		name        string
+		method      string
+		encoding    string
+		secret      string
+		body        []byte
+		consumerErr error
+		wantStatus  int
+		wantLogs    int
+	}{
+		{name: "plain", method: http.MethodPost, secret: "secret", body: []byte(line + "\n" + line), wantStatus: http.StatusOK, wantLogs: 2},
+		{name: "gzip", method: http.MethodPost, encoding: "gzip", secret: "secret", body: gzipPayload(t, []byte(line+"\n\n")), wantStatus: http.StatusOK, wantLogs: 1},
+		{name: "empty", method: http.MethodPost, secret: "secret", wantStatus: http.StatusOK},
+		{name: "invalid method", method: http.MethodGet, secret: "secret", wantStatus: http.StatusMethodNotAllowed},
+		{name: "invalid secret", method: http.MethodPost, secret: "other", body: []byte(line), wantStatus: http.StatusUnauthorized},
+		{name: "missing secret", method: http.MethodPost, body: []byte(line), wantStatus: http.StatusUnauthorized},
+		{name: "invalid encoding", method: http.MethodPost, encoding: "br", secret: "secret", body: []byte(line), wantStatus: http.StatusUnsupportedMediaType},
+		{name: "invalid gzip", method: http.MethodPost, encoding: "gzip", secret: "secret", body: []byte(line), wantStatus: http.StatusBadRequest},
+		{name: "invalid log", method: http.MethodPost, secret: "secret", body: []byte(line + "\n{\n" + line), wantStatus: http.StatusOK, wantLogs: 2},
+		{name: "too large", method: http.MethodPost, secret: "secret", body: bytes.Repeat([]byte(line+"\n"), 20), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "too large gzip", method: http.MethodPost, encoding: "gzip", secret: "secret", body: gzipPayload(t, bytes.Repeat([]byte(line+"\n"), 20)), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "consumer error", method: http.MethodPost, secret: "secret", body: []byte(line), consumerErr: errors.New("queue is full"), wantStatus: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Secret = "secret"
+			cfg.MaxRequestBodySize = 1024
+			sink := new(consumertest.LogsSink)
+			var next consumer.Logs = sink
+			if tc.consumerErr != nil {
+				next = consumertest.NewErr(tc.consumerErr)
+			}
+			r := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, next)
+
+			req := httptest.NewRequest(tc.method, "/", bytes.NewReader(tc.body))
+			if tc.encoding != "" {
+				req.Header.Set("Content-Encoding", tc.encoding)
+			}
+			if tc.secret != "" {
+				req.Header.Set(secretHeader, tc.secret)
+			}
+			w := httptest.NewRecorder()
+			r.handleReq(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code, strings.TrimSpace(w.Body.String()))
+			assert.Equal(t, tc.wantLogs, sink.LogRecordCount())
+		})
+	}
+}
+
+func TestHandleReqWithoutSecret(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	r := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), createDefaultConfig().(*Config), sink)
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"RayID":"6cb4c6d8ff2a5e1a"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+func TestLimitedReader(t *testing.T) {
+	l := &limitedReader{reader: strings.NewReader("abcd"), remaining: 4}
+	b, err := ioutil.ReadAll(l)
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", string(b))
+
+	l = &limitedReader{reader: strings.NewReader("abcde"), remaining: 4}
+	b, err = ioutil.ReadAll(l)
+	require.ErrorIs(t, err, errBodyTooLarge)
+	assert.Equal(t, "abcd", string(b))
+}
@@ -24,7 +24,8 @@ func DefaultMetricsSettings() MetricsSettings {
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
-	startTime pdata.Timestamp
+	startTime                  pdata.Timestamp
+	instrumentationLibraryName string
 }
 
 // metricBuilderOption applies changes to default metrics builder.
@@ -37,9 +38,18 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/couchbasereceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		startTime: pdata.NewTimestampFromTime(time.Now()),
+		startTime:                  pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName: "otelcol/couchbasereceiver",
 	}
 	for _, op := range options {
 		op(mb)
@@ -53,6 +63,26 @@ func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption)
 func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
 // and metrics builder should update its startTime and reset it's internal state accordingly.
 func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
@@ -472,6 +472,7 @@ func newMetricCouchdbHttpdViews(settings MetricSettings) metricCouchdbHttpdViews
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
 	startTime                       pdata.Timestamp
+	instrumentationLibraryName      string
 	metricCouchdbAverageRequestTime metricCouchdbAverageRequestTime
 	metricCouchdbDatabaseOpen       metricCouchdbDatabaseOpen
 	metricCouchdbDatabaseOperations metricCouchdbDatabaseOperations
@@ -492,9 +493,18 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/couchdbreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
 		startTime:                       pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:      "otelcol/couchdbreceiver",
 		metricCouchdbAverageRequestTime: newMetricCouchdbAverageRequestTime(settings.CouchdbAverageRequestTime),
 		metricCouchdbDatabaseOpen:       newMetricCouchdbDatabaseOpen(settings.CouchdbDatabaseOpen),
 		metricCouchdbDatabaseOperations: newMetricCouchdbDatabaseOperations(settings.CouchdbDatabaseOperations),
@@ -524,6 +534,26 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricCouchdbHttpdViews.emit(metrics)
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // RecordCouchdbAverageRequestTimeDataPoint adds a data point to couchdb.average_request_time metric.
 func (mb *MetricsBuilder) RecordCouchdbAverageRequestTimeDataPoint(ts pdata.Timestamp, val float64) {
 	mb.metricCouchdbAverageRequestTime.recordDataPoint(mb.startTime, ts, val)
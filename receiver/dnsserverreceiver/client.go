@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnsserverreceiver"
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Paths of the statistics served by BIND and CoreDNS.
+const (
+	bindJSONPath       = "/json/v1/server"
+	bindXMLPath        = "/xml/v3/server"
+	corednsDefaultPath = "/metrics"
+)
+
+// serverStats are the statistics of a DNS server, counted since the server was started.
+type serverStats struct {
+	// queries are the number of queries received, by query type.
+	queries map[string]int64
+	// responses are the number of responses sent, by response code.
+	responses map[string]int64
+	// cache is nil if the server doesn't report its cache statistics.
+	cache *cacheStats
+	// zoneTransfers is nil if the server doesn't report its zone transfers.
+	zoneTransfers *zoneTransferStats
+}
+
+type cacheStats struct {
+	hits, misses int64
+}
+
+type zoneTransferStats struct {
+	successes, failures int64
+}
+
+// statsClient queries the statistics of a DNS server.
+type statsClient interface {
+	stats(ctx context.Context) (*serverStats, error)
+}
+
+// httpStatsClient gets the statistics of a DNS server from an HTTP endpoint.
+type httpStatsClient struct {
+	client *http.Client
+	url    string
+	parse  func(io.Reader) (*serverStats, error)
+}
+
+func newStatsClient(client *http.Client, cfg *Config) (*httpStatsClient, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+	c := &httpStatsClient{client: client}
+	switch {
+	case cfg.ServerType == serverTypeCoreDNS:
+		// The endpoint is the Prometheus endpoint, which is served on /metrics by default.
+		if u.Path == "" || u.Path == "/" {
+			u.Path = corednsDefaultPath
+		}
+		c.parse = parseCoreDNS
+	case cfg.BindFormat == bindFormatXML:
+		u.Path = strings.TrimSuffix(u.Path, "/") + bindXMLPath
+		c.parse = parseBindXML
+	default:
+		u.Path = strings.TrimSuffix(u.Path, "/") + bindJSONPath
+		c.parse = parseBindJSON
+	}
+	c.url = u.String()
+	return c, nil
+}
+
+func (c *httpStatsClient) stats(ctx context.Context) (*serverStats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("request to %s failed with status %d: %s", c.url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	stats, err := c.parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the statistics of %s: %w", c.url, err)
+	}
+	return stats, nil
+}
+
+// bindJSONStats are the statistics of the JSON statistics channel of BIND used by the receiver.
+type bindJSONStats struct {
+	QTypes    map[string]int64 `json:"qtypes"`
+	RCodes    map[string]int64 `json:"rcodes"`
+	ZoneStats map[string]int64 `json:"zonestats"`
+	Views     map[string]struct {
+		Resolver struct {
+			CacheStats map[string]int64 `json:"cachestats"`
+		} `json:"resolver"`
+	} `json:"views"`
+}
+
+func parseBindJSON(r io.Reader) (*serverStats, error) {
+	var s bindJSONStats
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	stats := &serverStats{queries: s.QTypes, responses: s.RCodes}
+	for _, view := range s.Views {
+		stats.addCache(view.Resolver.CacheStats)
+	}
+	stats.addZoneTransfers(s.ZoneStats)
+	return stats, nil
+}
+
+// bindXMLStats are the statistics of the XML v3 statistics channel of BIND used by the receiver.
+type bindXMLStats struct {
+	Server struct {
+		Counters []bindXMLCounters `xml:"counters"`
+	} `xml:"server"`
+	Views []struct {
+		Counters []bindXMLCounters `xml:"counters"`
+	} `xml:"views>view"`
+}
+
+type bindXMLCounters struct {
+	Type     string `xml:"type,attr"`
+	Counters []struct {
+		Name  string `xml:"name,attr"`
+		Value int64  `xml:",chardata"`
+	} `xml:"counter"`
+}
+
+func (c bindXMLCounters) values() map[string]int64 {
+	values := make(map[string]int64, len(c.Counters))
+	for _, counter := range c.Counters {
+		values[counter.Name] = counter.Value
+	}
+	return values
+}
+
+func parseBindXML(r io.Reader) (*serverStats, error) {
+	var s bindXMLStats
+	if err := xml.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	stats := &serverStats{}
+	for _, counters := range s.Server.Counters {
+		switch counters.Type {
+		case "qtype":
+			stats.queries = counters.values()
+		case "rcode":
+			stats.responses = counters.values()
+		case "zonestat":
+			stats.addZoneTransfers(counters.values())
+		}
+	}
+	for _, view := range s.Views {
+		for _, counters := range view.Counters {
+			if counters.Type == "cachestats" {
+				stats.addCache(counters.values())
+			}
+		}
+	}
+	return stats, nil
+}
+
+// addCache adds the queries answered from the cache of a BIND view, the caches of the views are
+// reported as a single cache.
+func (s *serverStats) addCache(cachestats map[string]int64) {
+	hits, hasHits := cachestats["QueryHits"]
+	misses, hasMisses := cachestats["QueryMisses"]
+	if !hasHits && !hasMisses {
+		return
+	}
+	if s.cache == nil {
+		s.cache = &cacheStats{}
+	}
+	s.cache.hits += hits
+	s.cache.misses += misses
+}
+
+// addZoneTransfers adds the incoming zone transfers of the BIND zone statistics.
+func (s *serverStats) addZoneTransfers(zonestats map[string]int64) {
+	if zonestats == nil {
+		return
+	}
+	s.zoneTransfers = &zoneTransferStats{
+		successes: zonestats["XfrSuccess"],
+		failures:  zonestats["XfrFail"],
+	}
+}
+
+// CoreDNS metrics used by the receiver, their other labels, e.g. server or zone, are aggregated.
+const (
+	corednsRequests    = "coredns_dns_requests_total"
+	corednsResponses   = "coredns_dns_responses_total"
+	corednsCacheHits   = "coredns_cache_hits_total"
+	corednsCacheMisses = "coredns_cache_misses_total"
+)
+
+func parseCoreDNS(r io.Reader) (*serverStats, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, err
+	}
+	stats := &serverStats{
+		queries:   sumByLabel(families[corednsRequests], "type"),
+		responses: sumByLabel(families[corednsResponses], "rcode"),
+	}
+	hits, hasHits := families[corednsCacheHits]
+	misses, hasMisses := families[corednsCacheMisses]
+	if hasHits || hasMisses {
+		// The cache plugin is enabled.
+		stats.cache = &cacheStats{hits: sum(hits), misses: sum(misses)}
+	}
+	return stats, nil
+}
+
+// sumByLabel returns the sum of the values of the counters of family, by value of the label.
+func sumByLabel(family *dto.MetricFamily, label string) map[string]int64 {
+	values := map[string]int64{}
+	for _, m := range family.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == label {
+				values[l.GetValue()] += int64(m.GetCounter().GetValue())
+			}
+		}
+	}
+	return values
+}
+
+// sum returns the sum of the values of the counters of family.
+func sum(family *dto.MetricFamily) int64 {
+	var total int64
+	for _, m := range family.GetMetric() {
+		total += int64(m.GetCounter().GetValue())
+	}
+	return total
+}
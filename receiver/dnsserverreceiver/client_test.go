@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBind(t *testing.T) {
+	expected := &serverStats{
+		queries:       map[string]int64{"A": 980, "AAAA": 410, "MX": 25, "PTR": 125},
+		responses:     map[string]int64{"NOERROR": 1320, "SERVFAIL": 12, "NXDOMAIN": 208},
+		cache:         &cacheStats{hits: 1106, misses: 312},
+		zoneTransfers: &zoneTransferStats{successes: 6, failures: 2},
+	}
+
+	f, err := os.Open(filepath.Join("testdata", "bind_server.json"))
+	require.NoError(t, err)
+	defer f.Close()
+	stats, err := parseBindJSON(f)
+	require.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	f, err = os.Open(filepath.Join("testdata", "bind_server.xml"))
+	require.NoError(t, err)
+	defer f.Close()
+	stats, err = parseBindXML(f)
+	require.NoError(t, err)
+	assert.Equal(t, expected, stats)
+}
+
+func TestParseBindWithoutResolver(t *testing.T) {
+	stats, err := parseBindJSON(strings.NewReader(`{"qtypes": {"A": 1}, "rcodes": {"NOERROR": 1}}`))
+	require.NoError(t, err)
+	assert.Nil(t, stats.cache)
+	assert.Nil(t, stats.zoneTransfers)
+}
+
+func TestParseCoreDNS(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "coredns_metrics.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	stats, err := parseCoreDNS(f)
+	require.NoError(t, err)
+	assert.Equal(t, &serverStats{
+		queries:   map[string]int64{"A": 640, "AAAA": 300},
+		responses: map[string]int64{"NOERROR": 830, "SERVFAIL": 10, "NXDOMAIN": 100},
+		cache:     &cacheStats{hits: 700, misses: 240},
+	}, stats)
+
+	stats, err = parseCoreDNS(strings.NewReader("coredns_dns_requests_total{type=\"A\"} 1\n"))
+	require.NoError(t, err)
+	assert.Nil(t, stats.cache, "the cache plugin is not enabled")
+}
+
+func TestStatsClientURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		serverType string
+		bindFormat string
+		want       string
+	}{
+		{name: "bind json", endpoint: "http://localhost:8053", serverType: serverTypeBind, bindFormat: bindFormatJSON, want: "http://localhost:8053/json/v1/server"},
+		{name: "bind xml", endpoint: "http://localhost:8053/", serverType: serverTypeBind, bindFormat: bindFormatXML, want: "http://localhost:8053/xml/v3/server"},
+		{name: "bind behind a proxy", endpoint: "https://proxy/bind", serverType: serverTypeBind, bindFormat: bindFormatJSON, want: "https://proxy/bind/json/v1/server"},
+		{name: "coredns default path", endpoint: "http://localhost:9153", serverType: serverTypeCoreDNS, bindFormat: bindFormatJSON, want: "http://localhost:9153/metrics"},
+		{name: "coredns path", endpoint: "http://localhost:9153/custom", serverType: serverTypeCoreDNS, bindFormat: bindFormatJSON, want: "http://localhost:9153/custom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = tt.endpoint
+			cfg.ServerType = tt.serverType
+			cfg.BindFormat = tt.bindFormat
+			c, err := newStatsClient(http.DefaultClient, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.url)
+		})
+	}
+}
+
+func TestStatsClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == bindJSONPath {
+			_, _ = w.Write([]byte("not json"))
+			return
+		}
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	c, err := newStatsClient(server.Client(), cfg)
+	require.NoError(t, err)
+	_, err = c.stats(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse the statistics of "+server.URL+bindJSONPath)
+
+	cfg.BindFormat = bindFormatXML
+	c, err = newStatsClient(server.Client(), cfg)
+	require.NoError(t, err)
+	_, err = c.stats(context.Background())
+	require.EqualError(t, err, "request to "+server.URL+bindXMLPath+" failed with status 403: forbidden")
+}
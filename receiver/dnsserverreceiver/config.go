@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnsserverreceiver"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnsserverreceiver/internal/metadata"
+)
+
+var errNoEndpoint = errors.New("endpoint must not be empty")
+
+// Types of the DNS servers the receiver scrapes.
+const (
+	serverTypeBind    = "bind"
+	serverTypeCoreDNS = "coredns"
+)
+
+// Formats of the BIND statistics channel.
+const (
+	bindFormatJSON = "json"
+	bindFormatXML  = "xml"
+)
+
+// Config is the configuration for the DNS server receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+	confighttp.HTTPClientSettings           `mapstructure:",squash"`
+	// Metrics defines which metrics to enable for the scraper.
+	Metrics metadata.MetricsSettings `mapstructure:"metrics"`
+	// ServerType is the type of the DNS server, either bind to scrape the statistics channel of
+	// BIND, or coredns to scrape the Prometheus endpoint of CoreDNS.
+	ServerType string `mapstructure:"server_type"`
+	// BindFormat is the format of the BIND statistics channel, either json or xml for the
+	// servers built without JSON support.
+	BindFormat string `mapstructure:"bind_format"`
+}
+
+// Validate validates the given config, returning an error specifying any issues with the config.
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errNoEndpoint
+	}
+	switch cfg.ServerType {
+	case serverTypeBind, serverTypeCoreDNS:
+	default:
+		return fmt.Errorf("invalid server_type %q: can be either %q or %q", cfg.ServerType, serverTypeBind, serverTypeCoreDNS)
+	}
+	switch cfg.BindFormat {
+	case bindFormatJSON, bindFormatXML:
+	default:
+		return fmt.Errorf("invalid bind_format %q: can be either %q or %q", cfg.BindFormat, bindFormatJSON, bindFormatXML)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.Len(t, cfg.Receivers, 3)
+
+	require.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	expected := factory.CreateDefaultConfig().(*Config)
+	expected.SetIDName("coredns")
+	expected.Endpoint = "http://localhost:9153/metrics"
+	expected.ServerType = serverTypeCoreDNS
+	expected.CollectionInterval = 30 * time.Second
+	expected.Metrics.DNSServerZoneTransfers.Enabled = false
+	require.Equal(t, expected, cfg.Receivers[config.NewComponentIDWithName(typeStr, "coredns")])
+
+	expected = factory.CreateDefaultConfig().(*Config)
+	expected.SetIDName("bindxml")
+	expected.BindFormat = bindFormatXML
+	require.Equal(t, expected, cfg.Receivers[config.NewComponentIDWithName(typeStr, "bindxml")])
+}
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Endpoint = ""
+	require.ErrorIs(t, cfg.Validate(), errNoEndpoint)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.ServerType = "unbound"
+	require.EqualError(t, cfg.Validate(), `invalid server_type "unbound": can be either "bind" or "coredns"`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.BindFormat = "yaml"
+	require.EqualError(t, cfg.Validate(), `invalid bind_format "yaml": can be either "json" or "xml"`)
+}
@@ -0,0 +1,392 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// MetricSettings provides common settings for a particular metric.
+type MetricSettings struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// MetricsSettings provides settings for dnsserverreceiver metrics.
+type MetricsSettings struct {
+	DNSServerCacheLookups  MetricSettings `mapstructure:"dns.server.cache.lookups"`
+	DNSServerQueries       MetricSettings `mapstructure:"dns.server.queries"`
+	DNSServerResponses     MetricSettings `mapstructure:"dns.server.responses"`
+	DNSServerZoneTransfers MetricSettings `mapstructure:"dns.server.zone_transfers"`
+}
+
+func DefaultMetricsSettings() MetricsSettings {
+	return MetricsSettings{
+		DNSServerCacheLookups: MetricSettings{
+			Enabled: true,
+		},
+		DNSServerQueries: MetricSettings{
+			Enabled: true,
+		},
+		DNSServerResponses: MetricSettings{
+			Enabled: true,
+		},
+		DNSServerZoneTransfers: MetricSettings{
+			Enabled: true,
+		},
+	}
+}
+
+type metricDNSServerCacheLookups struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dns.server.cache.lookups metric with initial data.
+func (m *metricDNSServerCacheLookups) init() {
+	m.data.SetName("dns.server.cache.lookups")
+	m.data.SetDescription("The number of queries looked up in the cache of the server, the cache hit rate is the ratio of hits to lookups.")
+	m.data.SetUnit("{lookups}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDNSServerCacheLookups) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, cacheResultAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.CacheResult, pdata.NewAttributeValueString(cacheResultAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDNSServerCacheLookups) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDNSServerCacheLookups) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDNSServerCacheLookups(settings MetricSettings) metricDNSServerCacheLookups {
+	m := metricDNSServerCacheLookups{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDNSServerQueries struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dns.server.queries metric with initial data.
+func (m *metricDNSServerQueries) init() {
+	m.data.SetName("dns.server.queries")
+	m.data.SetDescription("The number of queries received by the server.")
+	m.data.SetUnit("{queries}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDNSServerQueries) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, queryTypeAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.QueryType, pdata.NewAttributeValueString(queryTypeAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDNSServerQueries) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDNSServerQueries) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDNSServerQueries(settings MetricSettings) metricDNSServerQueries {
+	m := metricDNSServerQueries{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDNSServerResponses struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dns.server.responses metric with initial data.
+func (m *metricDNSServerResponses) init() {
+	m.data.SetName("dns.server.responses")
+	m.data.SetDescription("The number of responses sent by the server.")
+	m.data.SetUnit("{responses}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDNSServerResponses) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, rcodeAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.Rcode, pdata.NewAttributeValueString(rcodeAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDNSServerResponses) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDNSServerResponses) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDNSServerResponses(settings MetricSettings) metricDNSServerResponses {
+	m := metricDNSServerResponses{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricDNSServerZoneTransfers struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills dns.server.zone_transfers metric with initial data.
+func (m *metricDNSServerZoneTransfers) init() {
+	m.data.SetName("dns.server.zone_transfers")
+	m.data.SetDescription("The number of incoming zone transfers of the secondary zones of the server. Only reported by BIND.")
+	m.data.SetUnit("{transfers}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricDNSServerZoneTransfers) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, transferResultAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.TransferResult, pdata.NewAttributeValueString(transferResultAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricDNSServerZoneTransfers) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricDNSServerZoneTransfers) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricDNSServerZoneTransfers(settings MetricSettings) metricDNSServerZoneTransfers {
+	m := metricDNSServerZoneTransfers{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+// MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
+// required to produce metric representation defined in metadata and user settings.
+type MetricsBuilder struct {
+	startTime                    pdata.Timestamp
+	instrumentationLibraryName   string
+	metricDNSServerCacheLookups  metricDNSServerCacheLookups
+	metricDNSServerQueries       metricDNSServerQueries
+	metricDNSServerResponses     metricDNSServerResponses
+	metricDNSServerZoneTransfers metricDNSServerZoneTransfers
+}
+
+// metricBuilderOption applies changes to default metrics builder.
+type metricBuilderOption func(*MetricsBuilder)
+
+// WithStartTime sets startTime on the metrics builder.
+func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.startTime = startTime
+	}
+}
+
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/dnsserverreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
+func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
+	mb := &MetricsBuilder{
+		startTime:                    pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:   "otelcol/dnsserverreceiver",
+		metricDNSServerCacheLookups:  newMetricDNSServerCacheLookups(settings.DNSServerCacheLookups),
+		metricDNSServerQueries:       newMetricDNSServerQueries(settings.DNSServerQueries),
+		metricDNSServerResponses:     newMetricDNSServerResponses(settings.DNSServerResponses),
+		metricDNSServerZoneTransfers: newMetricDNSServerZoneTransfers(settings.DNSServerZoneTransfers),
+	}
+	for _, op := range options {
+		op(mb)
+	}
+	return mb
+}
+
+// Emit appends generated metrics to a pdata.MetricsSlice and updates the internal state to be ready for recording
+// another set of data points. This function will be doing all transformations required to produce metric representation
+// defined in metadata and user settings, e.g. delta/cumulative translation.
+func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
+	mb.metricDNSServerCacheLookups.emit(metrics)
+	mb.metricDNSServerQueries.emit(metrics)
+	mb.metricDNSServerResponses.emit(metrics)
+	mb.metricDNSServerZoneTransfers.emit(metrics)
+}
+
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
+// RecordDNSServerCacheLookupsDataPoint adds a data point to dns.server.cache.lookups metric.
+func (mb *MetricsBuilder) RecordDNSServerCacheLookupsDataPoint(ts pdata.Timestamp, val int64, cacheResultAttributeValue string) {
+	mb.metricDNSServerCacheLookups.recordDataPoint(mb.startTime, ts, val, cacheResultAttributeValue)
+}
+
+// RecordDNSServerQueriesDataPoint adds a data point to dns.server.queries metric.
+func (mb *MetricsBuilder) RecordDNSServerQueriesDataPoint(ts pdata.Timestamp, val int64, queryTypeAttributeValue string) {
+	mb.metricDNSServerQueries.recordDataPoint(mb.startTime, ts, val, queryTypeAttributeValue)
+}
+
+// RecordDNSServerResponsesDataPoint adds a data point to dns.server.responses metric.
+func (mb *MetricsBuilder) RecordDNSServerResponsesDataPoint(ts pdata.Timestamp, val int64, rcodeAttributeValue string) {
+	mb.metricDNSServerResponses.recordDataPoint(mb.startTime, ts, val, rcodeAttributeValue)
+}
+
+// RecordDNSServerZoneTransfersDataPoint adds a data point to dns.server.zone_transfers metric.
+func (mb *MetricsBuilder) RecordDNSServerZoneTransfersDataPoint(ts pdata.Timestamp, val int64, transferResultAttributeValue string) {
+	mb.metricDNSServerZoneTransfers.recordDataPoint(mb.startTime, ts, val, transferResultAttributeValue)
+}
+
+// Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
+// and metrics builder should update its startTime and reset it's internal state accordingly.
+func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
+	mb.startTime = pdata.NewTimestampFromTime(time.Now())
+	for _, op := range options {
+		op(mb)
+	}
+}
+
+// Attributes contains the possible metric attributes that can be used.
+var Attributes = struct {
+	// CacheResult (Whether the query was answered from the cache.)
+	CacheResult string
+	// QueryType (The type of the DNS queries, e.g. A or AAAA.)
+	QueryType string
+	// Rcode (The response code of the DNS responses, e.g. NOERROR or SERVFAIL.)
+	Rcode string
+	// TransferResult (Whether the zone transfer succeeded.)
+	TransferResult string
+}{
+	"result",
+	"type",
+	"rcode",
+	"result",
+}
+
+// A is an alias for Attributes.
+var A = Attributes
+
+// AttributeCacheResult are the possible values that the attribute "cache_result" can have.
+var AttributeCacheResult = struct {
+	Hit  string
+	Miss string
+}{
+	"hit",
+	"miss",
+}
+
+// AttributeTransferResult are the possible values that the attribute "transfer_result" can have.
+var AttributeTransferResult = struct {
+	Success string
+	Failure string
+}{
+	"success",
+	"failure",
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnsserverreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/dnsserverreceiver/internal/metadata"
+)
+
+type dnsServerScraper struct {
+	client         statsClient
+	logger         *zap.Logger
+	cfg            *Config
+	metricsBuilder *metadata.MetricsBuilder
+}
+
+func newDNSServerScraper(
+	logger *zap.Logger,
+	cfg *Config,
+) *dnsServerScraper {
+	return &dnsServerScraper{
+		logger:         logger,
+		cfg:            cfg,
+		metricsBuilder: metadata.NewMetricsBuilder(cfg.Metrics),
+	}
+}
+
+func (s *dnsServerScraper) start(_ context.Context, host component.Host) error {
+	httpClient, err := s.cfg.ToClient(host.GetExtensions())
+	if err != nil {
+		return err
+	}
+	client, err := newStatsClient(httpClient, s.cfg)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	return nil
+}
+
+func (s *dnsServerScraper) scrape(ctx context.Context) (pdata.Metrics, error) {
+	stats, err := s.client.stats(ctx)
+	if err != nil {
+		s.logger.Error("Failed to fetch the DNS server statistics", zap.Error(err))
+		return pdata.NewMetrics(), err
+	}
+
+	now := pdata.NewTimestampFromTime(time.Now())
+	for queryType, count := range stats.queries {
+		s.metricsBuilder.RecordDNSServerQueriesDataPoint(now, count, queryType)
+	}
+	for rcode, count := range stats.responses {
+		s.metricsBuilder.RecordDNSServerResponsesDataPoint(now, count, rcode)
+	}
+	if stats.cache != nil {
+		s.metricsBuilder.RecordDNSServerCacheLookupsDataPoint(now, stats.cache.hits, metadata.AttributeCacheResult.Hit)
+		s.metricsBuilder.RecordDNSServerCacheLookupsDataPoint(now, stats.cache.misses, metadata.AttributeCacheResult.Miss)
+	}
+	if stats.zoneTransfers != nil {
+		s.metricsBuilder.RecordDNSServerZoneTransfersDataPoint(now, stats.zoneTransfers.successes, metadata.AttributeTransferResult.Success)
+		s.metricsBuilder.RecordDNSServerZoneTransfersDataPoint(now, stats.zoneTransfers.failures, metadata.AttributeTransferResult.Failure)
+	}
+
+	metrics := pdata.NewMetrics()
+	s.metricsBuilder.EmitForResource(metrics.ResourceMetrics())
+	return metrics, nil
+}
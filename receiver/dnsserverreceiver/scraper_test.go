@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsserverreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+)
+
+type mockClient struct {
+	serverStats *serverStats
+	err         error
+}
+
+func (c *mockClient) stats(context.Context) (*serverStats, error) {
+	return c.serverStats, c.err
+}
+
+// newTestServer serves the statistics of BIND and CoreDNS from the testdata.
+func newTestServer(t *testing.T) *httptest.Server {
+	payloads := map[string]string{
+		bindJSONPath:       "bind_server.json",
+		bindXMLPath:        "bind_server.xml",
+		corednsDefaultPath: "coredns_metrics.txt",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := payloads[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", payload))
+		require.NoError(t, err)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScrape(t *testing.T) {
+	server := newTestServer(t)
+	tests := []struct {
+		name       string
+		serverType string
+		bindFormat string
+		expected   string
+	}{
+		{name: "bind json", serverType: serverTypeBind, bindFormat: bindFormatJSON, expected: "expected_metrics_bind.json"},
+		{name: "bind xml", serverType: serverTypeBind, bindFormat: bindFormatXML, expected: "expected_metrics_bind.json"},
+		{name: "coredns", serverType: serverTypeCoreDNS, bindFormat: bindFormatJSON, expected: "expected_metrics_coredns.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = server.URL
+			cfg.ServerType = tt.serverType
+			cfg.BindFormat = tt.bindFormat
+			s := newDNSServerScraper(zap.NewNop(), cfg)
+			require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+			actualMetrics, err := s.scrape(context.Background())
+			require.NoError(t, err)
+
+			expectedMetrics, err := golden.ReadMetrics(filepath.Join("testdata", tt.expected))
+			require.NoError(t, err)
+			requireMetricsEqual(t, expectedMetrics, actualMetrics)
+		})
+	}
+}
+
+func TestScrapeError(t *testing.T) {
+	s := newDNSServerScraper(zap.NewNop(), createDefaultConfig().(*Config))
+	s.client = &mockClient{err: errors.New("connection refused")}
+
+	metrics, err := s.scrape(context.Background())
+	require.EqualError(t, err, "connection refused")
+	require.Equal(t, 0, metrics.MetricCount())
+}
+
+func TestScrapeDisabledMetrics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.DNSServerQueries.Enabled = false
+	cfg.Metrics.DNSServerResponses.Enabled = false
+	s := newDNSServerScraper(zap.NewNop(), cfg)
+	s.client = &mockClient{serverStats: &serverStats{
+		queries:       map[string]int64{"A": 1},
+		responses:     map[string]int64{"NOERROR": 1},
+		cache:         &cacheStats{hits: 3, misses: 1},
+		zoneTransfers: &zoneTransferStats{successes: 1},
+	}}
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, metrics.MetricCount())
+	ms := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, "dns.server.cache.lookups", ms.At(0).Name())
+	require.Equal(t, "dns.server.zone_transfers", ms.At(1).Name())
+}
+
+func requireMetricsEqual(t *testing.T, expected, actual pdata.Metrics) {
+	require.Equal(t, expected.ResourceMetrics().Len(), actual.ResourceMetrics().Len())
+	for i := 0; i < expected.ResourceMetrics().Len(); i++ {
+		ilms1 := expected.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+		ilms2 := actual.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+		require.Equal(t, ilms1.Len(), ilms2.Len())
+		for j := 0; j < ilms1.Len(); j++ {
+			require.Equal(t, ilms1.At(j).InstrumentationLibrary().Name(), ilms2.At(j).InstrumentationLibrary().Name())
+			require.NoError(t, scrapertest.CompareMetricSlices(ilms1.At(j).Metrics(), ilms2.At(j).Metrics()))
+		}
+	}
+}
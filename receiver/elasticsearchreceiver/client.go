@@ -16,7 +16,6 @@ package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -34,27 +34,41 @@ import (
 var (
 	errUnauthenticated = errors.New("status 401, unauthenticated")
 	errUnauthorized    = errors.New("status 403, unauthorized")
+	errNotFound        = errors.New("status 404, not found")
 )
 
 // elasticsearchClient defines the interface to retrieve metrics from an Elasticsearch cluster.
 type elasticsearchClient interface {
 	NodeStats(ctx context.Context, nodes []string) (*model.NodeStats, error)
+	NodesInfo(ctx context.Context, nodes []string) (*model.NodesInfo, error)
 	ClusterHealth(ctx context.Context) (*model.ClusterHealth, error)
+	ILMStatus(ctx context.Context) (*model.ILMStatus, error)
+	ILMExplain(ctx context.Context) (*model.ILMExplain, error)
+	IndexStats(ctx context.Context) (*model.IndexStats, error)
+	IndexAliases(ctx context.Context) (model.IndexAliases, error)
+	DataStreams(ctx context.Context) (*model.DataStreams, error)
+	TransformStats(ctx context.Context) (*model.TransformStats, error)
+	MLJobStats(ctx context.Context) (*model.MLJobStats, error)
+	PendingTasks(ctx context.Context) (*model.PendingTasks, error)
+	CatHealth(ctx context.Context) (model.CatHealth, error)
+	CatNodes(ctx context.Context) (model.CatNodes, error)
+	CatShards(ctx context.Context) (model.CatShards, error)
 }
 
 // defaultElasticsearchClient is the main implementation of elasticsearchClient.
 // It retrieves the required metrics from Elasticsearch's REST api.
 type defaultElasticsearchClient struct {
-	client     *http.Client
-	endpoint   *url.URL
-	authHeader string
-	logger     *zap.Logger
+	client      *http.Client
+	endpoint    *url.URL
+	credentials *credentials
+	backoff     BackoffConfig
+	logger      *zap.Logger
 }
 
 var _ elasticsearchClient = (*defaultElasticsearchClient)(nil)
 
 func newElasticsearchClient(logger *zap.Logger, c Config, h component.Host) (*defaultElasticsearchClient, error) {
-	client, err := c.HTTPClientSettings.ToClient(h.GetExtensions())
+	client, err := newHTTPClient(c, h)
 	if err != nil {
 		return nil, err
 	}
@@ -64,18 +78,17 @@ func newElasticsearchClient(logger *zap.Logger, c Config, h component.Host) (*de
 		return nil, err
 	}
 
-	var authHeader string
-	if c.Username != "" && c.Password != "" {
-		userPass := fmt.Sprintf("%s:%s", c.Username, c.Password)
-		authb64 := base64.StdEncoding.EncodeToString([]byte(userPass))
-		authHeader = fmt.Sprintf("Basic %s", authb64)
+	creds, err := newCredentials(logger, c)
+	if err != nil {
+		return nil, err
 	}
 
 	return &defaultElasticsearchClient{
-		client:     client,
-		authHeader: authHeader,
-		endpoint:   endpoint,
-		logger:     logger,
+		client:      client,
+		credentials: creds,
+		endpoint:    endpoint,
+		backoff:     c.Backoff,
+		logger:      logger,
 	}, nil
 }
 
@@ -107,6 +120,27 @@ func (c defaultElasticsearchClient) NodeStats(ctx context.Context, nodes []strin
 	return &nodeStats, err
 }
 
+// nodesInfoFilter filters the response of the nodes info endpoint down to the fields used by the scraper.
+const nodesInfoFilter = "filter_path=nodes.*.name,nodes.*.roles"
+
+func (c defaultElasticsearchClient) NodesInfo(ctx context.Context, nodes []string) (*model.NodesInfo, error) {
+	var nodeSpec string
+	if len(nodes) > 0 {
+		nodeSpec = strings.Join(nodes, ",")
+	} else {
+		nodeSpec = "_all"
+	}
+
+	body, err := c.doRequest(ctx, fmt.Sprintf("_nodes/%s?%s", nodeSpec, nodesInfoFilter))
+	if err != nil {
+		return nil, err
+	}
+
+	nodesInfo := model.NodesInfo{}
+	err = json.Unmarshal(body, &nodesInfo)
+	return &nodesInfo, err
+}
+
 func (c defaultElasticsearchClient) ClusterHealth(ctx context.Context) (*model.ClusterHealth, error) {
 	body, err := c.doRequest(ctx, "_cluster/health")
 	if err != nil {
@@ -118,7 +152,187 @@ func (c defaultElasticsearchClient) ClusterHealth(ctx context.Context) (*model.C
 	return &clusterHealth, err
 }
 
+func (c defaultElasticsearchClient) ILMStatus(ctx context.Context) (*model.ILMStatus, error) {
+	body, err := c.doRequest(ctx, "_ilm/status")
+	if err != nil {
+		return nil, err
+	}
+
+	ilmStatus := model.ILMStatus{}
+	err = json.Unmarshal(body, &ilmStatus)
+	return &ilmStatus, err
+}
+
+// ilmExplainPath only requests the indices that are in the ERROR step of their lifecycle,
+// and filters the response down to the fields used by the scraper.
+const ilmExplainPath = "_all/_ilm/explain?only_errors=true&filter_path=indices.*.index,indices.*.policy,indices.*.step"
+
+func (c defaultElasticsearchClient) ILMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	body, err := c.doRequest(ctx, ilmExplainPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ilmExplain := model.ILMExplain{}
+	err = json.Unmarshal(body, &ilmExplain)
+	return &ilmExplain, err
+}
+
+// indexStatsPath requests the stats of every shard copy, and filters the response down to
+// the fields used by the scraper.
+const indexStatsPath = "_all/_stats/store,docs?level=shards&filter_path=indices.*.shards.*.routing.node,indices.*.shards.*.routing.primary,indices.*.shards.*.store.size_in_bytes,indices.*.shards.*.docs.count,indices.*.shards.*.docs.deleted"
+
+func (c defaultElasticsearchClient) IndexStats(ctx context.Context) (*model.IndexStats, error) {
+	body, err := c.doRequest(ctx, indexStatsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	indexStats := model.IndexStats{}
+	err = json.Unmarshal(body, &indexStats)
+	return &indexStats, err
+}
+
+func (c defaultElasticsearchClient) IndexAliases(ctx context.Context) (model.IndexAliases, error) {
+	body, err := c.doRequest(ctx, "_alias")
+	if err != nil {
+		return nil, err
+	}
+
+	indexAliases := model.IndexAliases{}
+	err = json.Unmarshal(body, &indexAliases)
+	return indexAliases, err
+}
+
+const dataStreamsPath = "_data_stream?filter_path=data_streams.name,data_streams.indices.index_name"
+
+// DataStreams returns no data streams if the data stream endpoint doesn't exist, as on Elasticsearch
+// versions prior to 7.9 which don't support data streams.
+func (c defaultElasticsearchClient) DataStreams(ctx context.Context) (*model.DataStreams, error) {
+	body, err := c.doRequest(ctx, dataStreamsPath)
+	if errors.Is(err, errNotFound) {
+		return &model.DataStreams{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataStreams := model.DataStreams{}
+	err = json.Unmarshal(body, &dataStreams)
+	return &dataStreams, err
+}
+
+// transformStatsPath requests the stats of up to 10000 transforms, the maximum page size, and filters
+// the response down to the fields used by the scraper.
+const transformStatsPath = "_transform/_all/_stats?size=10000&filter_path=transforms.id,transforms.state,transforms.stats.index_failures,transforms.stats.search_failures"
+
+func (c defaultElasticsearchClient) TransformStats(ctx context.Context) (*model.TransformStats, error) {
+	body, err := c.doRequest(ctx, transformStatsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	transformStats := model.TransformStats{}
+	err = json.Unmarshal(body, &transformStats)
+	return &transformStats, err
+}
+
+const mlJobStatsPath = "_ml/anomaly_detectors/_all/_stats?filter_path=jobs.job_id,jobs.state"
+
+func (c defaultElasticsearchClient) MLJobStats(ctx context.Context) (*model.MLJobStats, error) {
+	body, err := c.doRequest(ctx, mlJobStatsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	mlJobStats := model.MLJobStats{}
+	err = json.Unmarshal(body, &mlJobStats)
+	return &mlJobStats, err
+}
+
+// pendingTasksPath filters the response of the pending tasks endpoint down to the fields used by the scraper.
+const pendingTasksPath = "_cluster/pending_tasks?filter_path=tasks.priority,tasks.time_in_queue_millis"
+
+func (c defaultElasticsearchClient) PendingTasks(ctx context.Context) (*model.PendingTasks, error) {
+	body, err := c.doRequest(ctx, pendingTasksPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingTasks := model.PendingTasks{}
+	err = json.Unmarshal(body, &pendingTasks)
+	return &pendingTasks, err
+}
+
+// catHealthPath requests the columns of the cluster health used by the scraper.
+const catHealthPath = "_cat/health?format=json&h=cluster,status,node.total,node.data,shards,relo,init,unassign"
+
+func (c defaultElasticsearchClient) CatHealth(ctx context.Context) (model.CatHealth, error) {
+	body, err := c.doRequest(ctx, catHealthPath)
+	if err != nil {
+		return nil, err
+	}
+
+	catHealth := model.CatHealth{}
+	err = json.Unmarshal(body, &catHealth)
+	return catHealth, err
+}
+
+// catNodesPath requests the columns of the nodes used by the scraper, in bytes and milliseconds.
+const catNodesPath = "_cat/nodes?format=json&bytes=b&time=ms&h=name,heap.current,heap.max,file_desc.current,disk.avail," +
+	"query_cache.memory_size,query_cache.evictions,query_cache.hit_count,query_cache.miss_count,fielddata.memory_size,fielddata.evictions," +
+	"indexing.index_total,indexing.index_time,indexing.delete_total,indexing.delete_time,get.total,get.time," +
+	"search.query_total,search.query_time,search.fetch_total,search.fetch_time,search.scroll_total,search.scroll_time," +
+	"merges.total,merges.total_time,refresh.total,refresh.time,flush.total,flush.total_time"
+
+func (c defaultElasticsearchClient) CatNodes(ctx context.Context) (model.CatNodes, error) {
+	body, err := c.doRequest(ctx, catNodesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	catNodes := model.CatNodes{}
+	err = json.Unmarshal(body, &catNodes)
+	return catNodes, err
+}
+
+// catShardsPath requests the columns of the shard copies used by the scraper, in bytes.
+const catShardsPath = "_cat/shards?format=json&bytes=b&h=index,shard,prirep,node,docs,store"
+
+func (c defaultElasticsearchClient) CatShards(ctx context.Context) (model.CatShards, error) {
+	body, err := c.doRequest(ctx, catShardsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	catShards := model.CatShards{}
+	err = json.Unmarshal(body, &catShards)
+	return catShards, err
+}
+
+// doRequest makes a request to the given path, retrying it up to MaxRetries times after the backoff
+// interval if Elasticsearch rejects it with a 429 status code.
 func (c defaultElasticsearchClient) doRequest(ctx context.Context, path string) ([]byte, error) {
+	for rejections := 0; ; rejections++ {
+		body, err := c.doRequestOnce(ctx, path)
+		var tooManyRequests *tooManyRequestsError
+		if !errors.As(err, &tooManyRequests) || rejections >= c.backoff.MaxRetries {
+			return body, err
+		}
+
+		interval := backoffInterval(c.backoff, rejections, tooManyRequests.retryAfter)
+		c.logger.Debug("Elasticsearch rejected the request, retrying", zap.String("path", path), zap.Duration("interval", interval))
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c defaultElasticsearchClient) doRequestOnce(ctx context.Context, path string) ([]byte, error) {
 	endpoint, err := c.endpoint.Parse(path)
 	if err != nil {
 		return nil, err
@@ -129,8 +343,8 @@ func (c defaultElasticsearchClient) doRequest(ctx context.Context, path string)
 		return nil, err
 	}
 
-	if c.authHeader != "" {
-		req.Header.Add("Authorization", c.authHeader)
+	if authHeader := c.credentials.authHeader(); authHeader != "" {
+		req.Header.Add("Authorization", authHeader)
 	}
 
 	// See https://www.elastic.co/guide/en/elasticsearch/reference/8.0/api-conventions.html#api-compatibility
@@ -158,9 +372,15 @@ func (c defaultElasticsearchClient) doRequest(ctx context.Context, path string)
 
 	switch resp.StatusCode {
 	case 401:
+		// The credentials may have been rotated, read them again before the next request.
+		c.credentials.invalidate()
 		return nil, errUnauthenticated
 	case 403:
 		return nil, errUnauthorized
+	case 404:
+		return nil, errNotFound
+	case 429:
+		return nil, &tooManyRequestsError{retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
 	default:
 		return nil, fmt.Errorf("got non 200 status code %d", resp.StatusCode)
 	}
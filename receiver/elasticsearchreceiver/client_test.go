@@ -16,16 +16,29 @@ package elasticsearchreceiver
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
@@ -40,6 +53,84 @@ func TestCreateClientInvalidEndpoint(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestClusterHealthMutualTLS(t *testing.T) {
+	health, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
+	require.NoError(t, err)
+	actualClusterHealth := model.ClusterHealth{}
+	require.NoError(t, json.Unmarshal(health, &actualClusterHealth))
+
+	certs := newTestCertificates(t, "elasticsearch.internal")
+	elasticsearchMock := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+		_, err := rw.Write(health)
+		require.NoError(t, err)
+	}))
+	elasticsearchMock.TLS = &tls.Config{
+		Certificates: []tls.Certificate{certs.server},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    certs.pool,
+	}
+	elasticsearchMock.StartTLS()
+	defer elasticsearchMock.Close()
+
+	testCases := []struct {
+		desc        string
+		tlsSetting  configtls.TLSClientSetting
+		expectedErr string
+	}{
+		{
+			desc: "client certificate",
+			tlsSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CAFile: certs.caFile, CertFile: certs.clientCertFile, KeyFile: certs.clientKeyFile},
+				ServerName: "elasticsearch.internal",
+			},
+		},
+		{
+			desc: "no client certificate",
+			tlsSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CAFile: certs.caFile},
+				ServerName: "elasticsearch.internal",
+			},
+			expectedErr: "tls",
+		},
+		{
+			desc: "no server name override",
+			tlsSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CAFile: certs.caFile, CertFile: certs.clientCertFile, KeyFile: certs.clientKeyFile},
+			},
+			expectedErr: "certificate",
+		},
+		{
+			desc: "unknown certificate authority",
+			tlsSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CertFile: certs.clientCertFile, KeyFile: certs.clientKeyFile},
+				ServerName: "elasticsearch.internal",
+			},
+			expectedErr: "certificate",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			client, err := newElasticsearchClient(zap.NewNop(), Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint:   elasticsearchMock.URL,
+					TLSSetting: testCase.tlsSetting,
+				},
+			}, componenttest.NewNopHost())
+			require.NoError(t, err)
+
+			clusterHealth, err := client.ClusterHealth(context.Background())
+			if testCase.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &actualClusterHealth, clusterHealth)
+		})
+	}
+}
+
 func TestNodeStatsNoPassword(t *testing.T) {
 	nodeJSON, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_linux.json")
 	require.NoError(t, err)
@@ -116,6 +207,33 @@ func TestNodeStatsAuthentication(t *testing.T) {
 	require.Equal(t, &actualNodeStats, nodeStats)
 }
 
+func TestNodeStatsPasswordFileRotated(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("old"), 0600))
+
+	elasticsearchMock := mockServer(t, "user", "pass")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+		Username:     "user",
+		PasswordFile: passwordFile,
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = client.NodeStats(ctx, []string{"_all"})
+	require.ErrorIs(t, err, errUnauthorized)
+
+	// The password is read again once the reload interval elapsed.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass"), 0600))
+	client.credentials.loadedAt = time.Time{}
+	_, err = client.NodeStats(ctx, []string{"_all"})
+	require.NoError(t, err)
+}
+
 func TestNodeStatsNoAuthentication(t *testing.T) {
 	elasticsearchMock := mockServer(t, "user", "pass")
 	defer elasticsearchMock.Close()
@@ -150,6 +268,30 @@ func TestNodeStatsBadAuthentication(t *testing.T) {
 	require.ErrorIs(t, err, errUnauthorized)
 }
 
+func TestNodesInfoLocal(t *testing.T) {
+	nodesInfoJSON, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_info_local.json")
+	require.NoError(t, err)
+
+	actualNodesInfo := model.NodesInfo{}
+	require.NoError(t, json.Unmarshal(nodesInfoJSON, &actualNodesInfo))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	nodesInfo, err := client.NodesInfo(ctx, []string{"_local"})
+	require.NoError(t, err)
+
+	require.Equal(t, &actualNodesInfo, nodesInfo)
+}
+
 func TestClusterHealthNoPassword(t *testing.T) {
 	healthJSON, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
 	require.NoError(t, err)
@@ -237,6 +379,293 @@ func TestClusterHealthNoAuthorization(t *testing.T) {
 	require.ErrorIs(t, err, errUnauthorized)
 }
 
+func TestILMStatusNoPassword(t *testing.T) {
+	ilmStatusJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_status.json")
+	require.NoError(t, err)
+
+	actualILMStatus := model.ILMStatus{}
+	require.NoError(t, json.Unmarshal(ilmStatusJSON, &actualILMStatus))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ilmStatus, err := client.ILMStatus(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualILMStatus, ilmStatus)
+}
+
+func TestILMExplainNoPassword(t *testing.T) {
+	ilmExplainJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_explain.json")
+	require.NoError(t, err)
+
+	actualILMExplain := model.ILMExplain{}
+	require.NoError(t, json.Unmarshal(ilmExplainJSON, &actualILMExplain))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ilmExplain, err := client.ILMExplain(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualILMExplain, ilmExplain)
+}
+
+func TestTransformStatsNoPassword(t *testing.T) {
+	transformStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/transform_stats.json")
+	require.NoError(t, err)
+
+	actualTransformStats := model.TransformStats{}
+	require.NoError(t, json.Unmarshal(transformStatsJSON, &actualTransformStats))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	transformStats, err := client.TransformStats(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualTransformStats, transformStats)
+}
+
+func TestMLJobStatsNoPassword(t *testing.T) {
+	mlJobStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ml_job_stats.json")
+	require.NoError(t, err)
+
+	actualMLJobStats := model.MLJobStats{}
+	require.NoError(t, json.Unmarshal(mlJobStatsJSON, &actualMLJobStats))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	mlJobStats, err := client.MLJobStats(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualMLJobStats, mlJobStats)
+}
+
+func TestPendingTasksNoPassword(t *testing.T) {
+	pendingTasksJSON, err := ioutil.ReadFile("./testdata/sample_payloads/pending_tasks.json")
+	require.NoError(t, err)
+
+	actualPendingTasks := model.PendingTasks{}
+	require.NoError(t, json.Unmarshal(pendingTasksJSON, &actualPendingTasks))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	pendingTasks, err := client.PendingTasks(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualPendingTasks, pendingTasks)
+}
+
+func TestIndexStatsNoPassword(t *testing.T) {
+	indexStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/index_stats.json")
+	require.NoError(t, err)
+
+	actualIndexStats := model.IndexStats{}
+	require.NoError(t, json.Unmarshal(indexStatsJSON, &actualIndexStats))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	indexStats, err := client.IndexStats(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualIndexStats, indexStats)
+}
+
+func TestIndexAliasesNoPassword(t *testing.T) {
+	aliasesJSON, err := ioutil.ReadFile("./testdata/sample_payloads/aliases.json")
+	require.NoError(t, err)
+
+	actualAliases := model.IndexAliases{}
+	require.NoError(t, json.Unmarshal(aliasesJSON, &actualAliases))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	aliases, err := client.IndexAliases(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, actualAliases, aliases)
+	require.Contains(t, aliases["logs-2022.02.01"].Aliases, "logs-write")
+}
+
+func TestDataStreamsNoPassword(t *testing.T) {
+	dataStreamsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/data_streams.json")
+	require.NoError(t, err)
+
+	actualDataStreams := model.DataStreams{}
+	require.NoError(t, json.Unmarshal(dataStreamsJSON, &actualDataStreams))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dataStreams, err := client.DataStreams(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, &actualDataStreams, dataStreams)
+}
+
+func TestDataStreamsNotFound(t *testing.T) {
+	elasticsearchMock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(404)
+	}))
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	dataStreams, err := client.DataStreams(ctx)
+	require.NoError(t, err)
+	require.Empty(t, dataStreams.DataStreams)
+
+	_, err = client.IndexAliases(ctx)
+	require.ErrorIs(t, err, errNotFound)
+}
+
+func TestCatHealthNoPassword(t *testing.T) {
+	catHealthJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_health.json")
+	require.NoError(t, err)
+
+	actualCatHealth := model.CatHealth{}
+	require.NoError(t, json.Unmarshal(catHealthJSON, &actualCatHealth))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	catHealth, err := client.CatHealth(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, actualCatHealth, catHealth)
+}
+
+func TestCatNodesNoPassword(t *testing.T) {
+	catNodesJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_nodes.json")
+	require.NoError(t, err)
+
+	actualCatNodes := model.CatNodes{}
+	require.NoError(t, json.Unmarshal(catNodesJSON, &actualCatNodes))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	catNodes, err := client.CatNodes(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, actualCatNodes, catNodes)
+}
+
+func TestCatShardsNoPassword(t *testing.T) {
+	catShardsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_shards.json")
+	require.NoError(t, err)
+
+	actualCatShards := model.CatShards{}
+	require.NoError(t, json.Unmarshal(catShardsJSON, &actualCatShards))
+
+	elasticsearchMock := mockServer(t, "", "")
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	catShards, err := client.CatShards(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, actualCatShards, catShards)
+}
+
 func TestDoRequestBadPath(t *testing.T) {
 	client, err := newElasticsearchClient(zap.NewNop(), Config{
 		HTTPClientSettings: confighttp.HTTPClientSettings{
@@ -280,6 +709,77 @@ func TestDoRequest404(t *testing.T) {
 	require.Contains(t, err.Error(), "404")
 }
 
+func TestDoRequest429(t *testing.T) {
+	var requests int
+	elasticsearchMock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if requests <= 2 {
+			rw.Header().Set("Retry-After", "0")
+			rw.WriteHeader(429)
+			return
+		}
+		rw.WriteHeader(200)
+		_, _ = rw.Write([]byte("{}"))
+	}))
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+		Backoff: BackoffConfig{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     10 * time.Millisecond,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	_, err = client.doRequest(context.Background(), "_cluster/health")
+	require.ErrorIs(t, err, errTooManyRequests)
+	require.Equal(t, 1, requests)
+
+	requests = 0
+	client.backoff.MaxRetries = 1
+	_, err = client.doRequest(context.Background(), "_cluster/health")
+	require.ErrorIs(t, err, errTooManyRequests)
+	require.Equal(t, 2, requests)
+
+	requests = 0
+	client.backoff.MaxRetries = 2
+	_, err = client.doRequest(context.Background(), "_cluster/health")
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+}
+
+func TestDoRequest429RetryAfter(t *testing.T) {
+	elasticsearchMock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Retry-After", "120")
+		rw.WriteHeader(429)
+	}))
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+		Backoff: BackoffConfig{
+			MaxRetries:      1,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Hour,
+		},
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// The retry waits for the delay requested by Elasticsearch, until the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.doRequest(ctx, "_cluster/health")
+	var tooManyRequests *tooManyRequestsError
+	require.True(t, errors.As(err, &tooManyRequests))
+	require.Equal(t, 2*time.Minute, tooManyRequests.retryAfter)
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
 // mockServer gives a mock elasticsearch server for testing; if username or password is included, they will be required for the client.
 // otherwise, authorization is ignored.
 func mockServer(t *testing.T, username, password string) *httptest.Server {
@@ -287,6 +787,30 @@ func mockServer(t *testing.T, username, password string) *httptest.Server {
 	require.NoError(t, err)
 	health, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
 	require.NoError(t, err)
+	ilmStatus, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_status.json")
+	require.NoError(t, err)
+	ilmExplain, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_explain.json")
+	require.NoError(t, err)
+	indexStats, err := ioutil.ReadFile("./testdata/sample_payloads/index_stats.json")
+	require.NoError(t, err)
+	aliases, err := ioutil.ReadFile("./testdata/sample_payloads/aliases.json")
+	require.NoError(t, err)
+	dataStreams, err := ioutil.ReadFile("./testdata/sample_payloads/data_streams.json")
+	require.NoError(t, err)
+	nodesInfo, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_info_local.json")
+	require.NoError(t, err)
+	transformStats, err := ioutil.ReadFile("./testdata/sample_payloads/transform_stats.json")
+	require.NoError(t, err)
+	mlJobStats, err := ioutil.ReadFile("./testdata/sample_payloads/ml_job_stats.json")
+	require.NoError(t, err)
+	pendingTasks, err := ioutil.ReadFile("./testdata/sample_payloads/pending_tasks.json")
+	require.NoError(t, err)
+	catHealth, err := ioutil.ReadFile("./testdata/sample_payloads/cat_health.json")
+	require.NoError(t, err)
+	catNodes, err := ioutil.ReadFile("./testdata/sample_payloads/cat_nodes.json")
+	require.NoError(t, err)
+	catShards, err := ioutil.ReadFile("./testdata/sample_payloads/cat_shards.json")
+	require.NoError(t, err)
 
 	elasticsearchMock := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		if username != "" || password != "" {
@@ -307,14 +831,170 @@ func mockServer(t *testing.T, username, password string) *httptest.Server {
 			return
 		}
 
+		if req.URL.Path == "/_nodes/_local" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(nodesInfo)
+			require.NoError(t, err)
+			return
+		}
+
 		if strings.HasPrefix(req.URL.Path, "/_cluster/health") {
 			rw.WriteHeader(200)
 			_, err = rw.Write(health)
 			require.NoError(t, err)
 			return
 		}
+
+		if strings.HasPrefix(req.URL.Path, "/_ilm/status") {
+			rw.WriteHeader(200)
+			_, err = rw.Write(ilmStatus)
+			require.NoError(t, err)
+			return
+		}
+
+		if strings.HasPrefix(req.URL.Path, "/_all/_ilm/explain") {
+			rw.WriteHeader(200)
+			_, err = rw.Write(ilmExplain)
+			require.NoError(t, err)
+			return
+		}
+
+		if strings.HasPrefix(req.URL.Path, "/_all/_stats") {
+			rw.WriteHeader(200)
+			_, err = rw.Write(indexStats)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_alias" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(aliases)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_data_stream" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(dataStreams)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_transform/_all/_stats" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(transformStats)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_ml/anomaly_detectors/_all/_stats" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(mlJobStats)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_cluster/pending_tasks" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(pendingTasks)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_cat/health" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(catHealth)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_cat/nodes" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(catNodes)
+			require.NoError(t, err)
+			return
+		}
+
+		if req.URL.Path == "/_cat/shards" {
+			rw.WriteHeader(200)
+			_, err = rw.Write(catShards)
+			require.NoError(t, err)
+			return
+		}
 		rw.WriteHeader(404)
 	}))
 
 	return elasticsearchMock
 }
+
+// testCertificates are the certificates of a test certificate authority, a server certificate
+// valid for a DNS name only, and the PEM files of the CA and of a client certificate.
+type testCertificates struct {
+	pool           *x509.CertPool
+	server         tls.Certificate
+	caDER          []byte
+	caFile         string
+	clientCertFile string
+	clientKeyFile  string
+}
+
+func newTestCertificates(t *testing.T, serverName string) testCertificates {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	certs := testCertificates{
+		pool:   x509.NewCertPool(),
+		caDER:  caDER,
+		caFile: filepath.Join(dir, "ca.pem"),
+	}
+	certs.pool.AddCert(ca)
+	writePEM(t, certs.caFile, "CERTIFICATE", caDER)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage, dnsNames []string) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "test"},
+			DNSNames:     dnsNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return der, key
+	}
+
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth, []string{serverName})
+	certs.server = tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth, nil)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	certs.clientCertFile = filepath.Join(dir, "client.pem")
+	certs.clientKeyFile = filepath.Join(dir, "client-key.pem")
+	writePEM(t, certs.clientCertFile, "CERTIFICATE", clientDER)
+	writePEM(t, certs.clientKeyFile, "EC PRIVATE KEY", clientKeyDER)
+	return certs
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
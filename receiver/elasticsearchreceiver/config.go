@@ -18,6 +18,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
@@ -35,8 +38,58 @@ var (
 	errUsernameNotSpecified = errors.New("password was specified, but not username")
 	errPasswordNotSpecified = errors.New("username was specified, but not password")
 	errEmptyEndpoint        = errors.New("endpoint must be specified")
+	errUsernameConflict     = errors.New("username and username_file can not both be specified")
+	errPasswordConflict     = errors.New("password and password_file can not both be specified")
+	errAPIKeyConflict       = errors.New("api_key and api_key_file can not both be specified")
+	errAPIKeyAndBasicAuth   = errors.New("api_key can not be specified with username and password")
+	errEmptyIndexPattern    = errors.New("indices must not contain empty patterns")
+	errEmitClusterNotLocal  = fmt.Errorf("emit_cluster_health_from requires nodes to be [%q]", localNode)
+	errEmitClusterSkipped   = errors.New("emit_cluster_health_from can not be set when skip_cluster_metrics is enabled")
+	errEmitClusterFilters   = errors.New("emit_cluster_health_from can not be set with node_roles or node_attributes")
+	errUnknownNodeRole      = errors.New("node_roles must only contain known roles")
+	errEmptyNodeAttribute   = errors.New("node_attributes must not contain empty names or values")
+	errNegativeMaxRetries   = errors.New("backoff.max_retries must not be negative")
+	errInitialInterval      = errors.New("backoff.initial_interval must be positive")
+	errMaxInterval          = errors.New("backoff.max_interval must not be less than backoff.initial_interval")
+	errCollectionInterval   = errors.New("must not be less than collection_interval")
+	errUnknownRestricted    = fmt.Errorf("restricted_mode must be one of %q, %q or %q", restrictedModeNever, restrictedModeAlways, restrictedModeFallback)
+	errEmitClusterRestrict  = errors.New("emit_cluster_health_from can not be set with restricted_mode")
+	errTLSCertKeyPair       = errors.New("tls.cert_file and tls.key_file must be specified together")
+	errTLSWithHTTP          = errors.New("tls settings require an https endpoint")
+	errInvalidCAFingerprint = errors.New("ca_fingerprint must be a hex encoded SHA-256 fingerprint")
+	errCAFingerprintCAFile  = errors.New("ca_fingerprint can not be specified with tls.ca_file or tls.insecure_skip_verify")
+	errCAFingerprintWrapped = errors.New("ca_fingerprint can not be specified with headers, compression or auth")
+	errConcurrentRequests   = errors.New("concurrent_requests must be positive")
 )
 
+const (
+	// localNode is the node selector of the node the receiver connects to.
+	localNode = "_local"
+	// electedMasterRole selects the node the cluster elected as master in EmitClusterHealthFrom.
+	electedMasterRole = "elected_master"
+	// excludedRolePrefix negates a role of NodeRoles.
+	excludedRolePrefix = "-"
+)
+
+const (
+	// restrictedModeNever never scrapes the _cat APIs.
+	restrictedModeNever = "never"
+	// restrictedModeAlways always scrapes the _cat APIs instead of the node stats and cluster health APIs.
+	restrictedModeAlways = "always"
+	// restrictedModeFallback switches to the _cat APIs once a node stats or cluster health request is unauthorized.
+	restrictedModeFallback = "fallback"
+)
+
+// nodeFilterRoles are the roles which can be selected by the node filters of the node stats API.
+var nodeFilterRoles = map[string]bool{
+	"master":            true,
+	"data":              true,
+	"ingest":            true,
+	"voting_only":       true,
+	"ml":                true,
+	"coordinating_only": true,
+}
+
 // Config is the configuration for the elasticsearch receiver
 type Config struct {
 	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
@@ -47,21 +100,142 @@ type Config struct {
 	// See https://www.elastic.co/guide/en/elasticsearch/reference/7.9/cluster.html#cluster-nodes for which selectors may be used here.
 	// If Nodes is empty, no nodes will be scraped.
 	Nodes []string `mapstructure:"nodes"`
+	// NodeRoles adds the nodes having one of the roles, e.g. master, data or ingest, to the nodes selected by Nodes.
+	// A role prefixed by - removes the nodes having the role instead, e.g. [master, -data] selects the dedicated masters.
+	NodeRoles []string `mapstructure:"node_roles"`
+	// NodeAttributes adds the nodes whose custom attributes, set with node.attr, have the values to the nodes selected by
+	// Nodes and NodeRoles. The values may contain * wildcards.
+	NodeAttributes map[string]string `mapstructure:"node_attributes"`
 	// SkipClusterMetrics indicates whether cluster level metrics from /_cluster/health should be scraped or not.
 	SkipClusterMetrics bool `mapstructure:"skip_cluster_metrics"`
+	// EmitClusterHealthFrom restricts the scraping of the cluster level metrics to the receivers connected to a node
+	// having this role, e.g. master, or to the one connected to the elected master if set to elected_master. It lets
+	// a receiver run next to every node of the cluster, scraping only its local node, without having the cluster level
+	// metrics scraped by every receiver. Requires Nodes to be ["_local"]. If empty, the cluster level metrics are always scraped.
+	EmitClusterHealthFrom string `mapstructure:"emit_cluster_health_from"`
+	// ShardMetrics indicates whether shard level metrics from /_stats?level=shards should be scraped or not.
+	// A data point is emitted for every copy of every shard in the cluster, which may result in a high cardinality.
+	ShardMetrics bool `mapstructure:"shard_metrics"`
+	// TransformMetrics indicates whether the state and failures of the transforms from /_transform/_all/_stats
+	// should be scraped or not. Requires the transform feature of Elasticsearch.
+	TransformMetrics bool `mapstructure:"transform_metrics"`
+	// MLJobMetrics indicates whether the state of the machine learning anomaly detection jobs from
+	// /_ml/anomaly_detectors/_all/_stats should be scraped or not. Requires the machine learning feature of Elasticsearch.
+	MLJobMetrics bool `mapstructure:"ml_job_metrics"`
+	// NodeRollupMetrics indicates whether the heap usage and the indexing and search rates of the scraped nodes should
+	// also be rolled up into cluster level metrics, so that they don't need to be aggregated over the node level metrics.
+	NodeRollupMetrics bool `mapstructure:"node_rollup_metrics"`
+	// Indices restricts the index level metrics, the shard metrics and the ILM index errors, to the indices whose name
+	// or one of whose aliases or data stream matches one of the patterns. Patterns may contain * wildcards.
+	// If Indices is empty, the metrics of every index are scraped.
+	Indices []string `mapstructure:"indices"`
+	// RestrictedMode defines when a reduced set of node, cluster and shard metrics is scraped from the _cat APIs, which
+	// can be granted to users lacking the cluster:monitor privileges: never (default), always, or fallback to switch to
+	// the _cat APIs once Elasticsearch rejected a node stats or cluster health request with a 403 status code.
+	RestrictedMode string `mapstructure:"restricted_mode"`
 	// Username is the username used when making REST calls to elasticsearch. Must be specified if Password is. Not required.
 	Username string `mapstructure:"username"`
 	// Password is the password used when making REST calls to elasticsearch. Must be specified if Username is. Not required.
 	Password string `mapstructure:"password"`
+	// UsernameFile is the path of a file containing the username. Can not be specified with Username.
+	UsernameFile string `mapstructure:"username_file"`
+	// PasswordFile is the path of a file containing the password. Can not be specified with Password.
+	PasswordFile string `mapstructure:"password_file"`
+	// APIKey is the base64 encoded API key used when making REST calls to elasticsearch. Can not be specified with basic auth credentials.
+	APIKey string `mapstructure:"api_key"`
+	// APIKeyFile is the path of a file containing the base64 encoded API key. Can not be specified with APIKey.
+	APIKeyFile string `mapstructure:"api_key_file"`
+	// CredentialsReloadInterval is the interval at which the credentials files are read again (default 1m).
+	CredentialsReloadInterval time.Duration `mapstructure:"credentials_reload_interval"`
+	// Backoff defines how the receiver backs off when Elasticsearch rejects its requests with a 429 status code.
+	Backoff BackoffConfig `mapstructure:"backoff"`
+	// CollectionIntervals defines the intervals of the groups of endpoints which are scraped less often than
+	// every collection_interval.
+	CollectionIntervals CollectionIntervals `mapstructure:"collection_intervals"`
+	// CAFingerprint is the hex encoded SHA-256 fingerprint of a certificate of the chain presented by Elasticsearch,
+	// usually its self-signed CA, which is trusted instead of the system roots or tls.ca_file.
+	CAFingerprint string `mapstructure:"ca_fingerprint"`
+	// ConcurrentRequests is the maximum number of requests made concurrently during a scrape (default 4), so that a
+	// slow endpoint doesn't delay the requests of the other endpoints. The requests are made sequentially if 1.
+	ConcurrentRequests int `mapstructure:"concurrent_requests"`
+}
+
+// CollectionIntervals defines the intervals at which the groups of endpoints are scraped, so that the expensive
+// endpoints can be scraped less often than the cheap ones. The intervals are rounded to the closest multiple of
+// collection_interval. A group is scraped every collection_interval if its interval is 0 (default).
+type CollectionIntervals struct {
+	// ClusterHealth is the interval of the cluster health, ILM status, transform stats, ML job stats and pending tasks endpoints.
+	ClusterHealth time.Duration `mapstructure:"cluster_health"`
+	// NodeStats is the interval of the node stats endpoint.
+	NodeStats time.Duration `mapstructure:"node_stats"`
+	// IndexStats is the interval of the index stats and ILM explain endpoints, used by the shard metrics
+	// and the ILM index errors, and of the resolution of the indices option.
+	IndexStats time.Duration `mapstructure:"index_stats"`
+}
+
+// BackoffConfig defines how the receiver backs off when Elasticsearch is overloaded and rejects its requests
+// with a 429 status code. The backoff interval starts at InitialInterval and doubles after every rejection,
+// up to MaxInterval. It is at least the delay requested by the Retry-After header of the rejected responses.
+type BackoffConfig struct {
+	// MaxRetries is the number of times a rejected request is retried within a scrape, after waiting for the
+	// backoff interval (default 0).
+	MaxRetries int `mapstructure:"max_retries"`
+	// InitialInterval is the backoff interval after a first rejection (default 10s).
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval is the maximum backoff interval (default 5m).
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// SkipScrapes indicates whether the scrapes are skipped until the backoff interval elapsed after a scrape
+	// during which requests were rejected (default true).
+	SkipScrapes bool `mapstructure:"skip_scrapes"`
 }
 
 // Validate validates the given config, returning an error specifying any issues with the config.
 func (cfg *Config) Validate() error {
 	var combinedErr error
-	if err := invalidCredentials(cfg.Username, cfg.Password); err != nil {
+	if err := cfg.validateCredentials(); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	for _, pattern := range cfg.Indices {
+		if pattern == "" {
+			combinedErr = multierr.Append(combinedErr, errEmptyIndexPattern)
+			break
+		}
+	}
+
+	if err := cfg.validateNodeFilters(); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	if err := cfg.validateEmitClusterHealthFrom(); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	if err := cfg.validateRestrictedMode(); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	if err := cfg.Backoff.validate(); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	if err := cfg.CollectionIntervals.validate(cfg.CollectionInterval); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+
+	tls := cfg.TLSSetting
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		combinedErr = multierr.Append(combinedErr, errTLSCertKeyPair)
+	}
+
+	if err := cfg.validateCAFingerprint(); err != nil {
 		combinedErr = multierr.Append(combinedErr, err)
 	}
 
+	if cfg.ConcurrentRequests < 1 {
+		combinedErr = multierr.Append(combinedErr, errConcurrentRequests)
+	}
+
 	if cfg.Endpoint == "" {
 		return multierr.Append(combinedErr, errEmptyEndpoint)
 	}
@@ -80,6 +254,146 @@ func (cfg *Config) Validate() error {
 		return multierr.Append(combinedErr, errEndpointBadScheme)
 	}
 
+	// The TLS settings, e.g. the client certificate of a cluster only accepting mutual TLS, would be
+	// silently ignored by a plain HTTP connection.
+	if u.Scheme == "http" && (tls.CAFile != "" || tls.CertFile != "" || tls.ServerName != "" || cfg.CAFingerprint != "") {
+		return multierr.Append(combinedErr, errTLSWithHTTP)
+	}
+
+	return combinedErr
+}
+
+// validateCAFingerprint validates that the CA fingerprint is a SHA-256 fingerprint, which replaces the verification
+// of the server certificates against the roots, and that the TLS config of the transport can be set.
+func (cfg *Config) validateCAFingerprint() error {
+	if cfg.CAFingerprint == "" {
+		return nil
+	}
+	if _, err := parseFingerprint(cfg.CAFingerprint); err != nil {
+		return err
+	}
+	if cfg.TLSSetting.CAFile != "" || cfg.TLSSetting.InsecureSkipVerify {
+		return errCAFingerprintCAFile
+	}
+	if len(cfg.Headers) > 0 || (cfg.Compression != "" && cfg.Compression != "none") || cfg.Auth != nil {
+		return errCAFingerprintWrapped
+	}
+	return nil
+}
+
+// validateCredentials validates that each credential is set at most once, and that
+// basic auth credentials and an API key are not used together.
+func (cfg *Config) validateCredentials() error {
+	var combinedErr error
+	if cfg.Username != "" && cfg.UsernameFile != "" {
+		combinedErr = multierr.Append(combinedErr, errUsernameConflict)
+	}
+	if cfg.Password != "" && cfg.PasswordFile != "" {
+		combinedErr = multierr.Append(combinedErr, errPasswordConflict)
+	}
+	if cfg.APIKey != "" && cfg.APIKeyFile != "" {
+		combinedErr = multierr.Append(combinedErr, errAPIKeyConflict)
+	}
+
+	username := cfg.Username + cfg.UsernameFile
+	password := cfg.Password + cfg.PasswordFile
+	if (cfg.APIKey != "" || cfg.APIKeyFile != "") && (username != "" || password != "") {
+		combinedErr = multierr.Append(combinedErr, errAPIKeyAndBasicAuth)
+	}
+	if err := invalidCredentials(username, password); err != nil {
+		combinedErr = multierr.Append(combinedErr, err)
+	}
+	return combinedErr
+}
+
+// validateEmitClusterHealthFrom validates that the cluster level metrics are scraped, and only the local node is, if
+// the cluster level metrics are restricted to the receivers connected to some nodes.
+func (cfg *Config) validateEmitClusterHealthFrom() error {
+	if cfg.EmitClusterHealthFrom == "" {
+		return nil
+	}
+	if cfg.SkipClusterMetrics {
+		return errEmitClusterSkipped
+	}
+	if len(cfg.Nodes) != 1 || cfg.Nodes[0] != localNode {
+		return errEmitClusterNotLocal
+	}
+	if len(cfg.NodeRoles) > 0 || len(cfg.NodeAttributes) > 0 {
+		return errEmitClusterFilters
+	}
+	return nil
+}
+
+// validateRestrictedMode validates that the restricted mode is known, and that the cluster level metrics are not
+// restricted to the receivers connected to some nodes, as the roles of the nodes are not available from the _cat APIs.
+func (cfg *Config) validateRestrictedMode() error {
+	switch cfg.RestrictedMode {
+	case "", restrictedModeNever:
+		return nil
+	case restrictedModeAlways, restrictedModeFallback:
+	default:
+		return errUnknownRestricted
+	}
+	if cfg.EmitClusterHealthFrom != "" {
+		return errEmitClusterRestrict
+	}
+	return nil
+}
+
+// validateNodeFilters validates that the node roles are known, as unknown ones would be taken as attribute names by
+// Elasticsearch, and that the node attributes are not empty.
+func (cfg *Config) validateNodeFilters() error {
+	var combinedErr error
+	for _, role := range cfg.NodeRoles {
+		if !nodeFilterRoles[strings.TrimPrefix(role, excludedRolePrefix)] {
+			combinedErr = multierr.Append(combinedErr, fmt.Errorf("%w: unknown role %q", errUnknownNodeRole, role))
+		}
+	}
+	for name, value := range cfg.NodeAttributes {
+		if name == "" || value == "" {
+			combinedErr = multierr.Append(combinedErr, errEmptyNodeAttribute)
+			break
+		}
+	}
+	return combinedErr
+}
+
+// nodeSelectors returns the node filters of the node stats API selecting the nodes to scrape: the selectors of Nodes,
+// followed by the role filters of NodeRoles and the attribute filters of NodeAttributes, sorted by attribute name.
+// Elasticsearch applies the filters in order, the role filters of the excluded roles removing the nodes selected
+// by the previous filters.
+func (cfg *Config) nodeSelectors() []string {
+	selectors := append([]string{}, cfg.Nodes...)
+	for _, role := range cfg.NodeRoles {
+		if strings.HasPrefix(role, excludedRolePrefix) {
+			selectors = append(selectors, strings.TrimPrefix(role, excludedRolePrefix)+":false")
+		} else {
+			selectors = append(selectors, role+":true")
+		}
+	}
+	names := make([]string, 0, len(cfg.NodeAttributes))
+	for name := range cfg.NodeAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		selectors = append(selectors, name+":"+cfg.NodeAttributes[name])
+	}
+	return selectors
+}
+
+// validate validates that the backoff intervals are positive and consistent.
+func (cfg *BackoffConfig) validate() error {
+	var combinedErr error
+	if cfg.MaxRetries < 0 {
+		combinedErr = multierr.Append(combinedErr, errNegativeMaxRetries)
+	}
+	if cfg.InitialInterval <= 0 {
+		return multierr.Append(combinedErr, errInitialInterval)
+	}
+	if cfg.MaxInterval < cfg.InitialInterval {
+		combinedErr = multierr.Append(combinedErr, errMaxInterval)
+	}
 	return combinedErr
 }
 
@@ -94,3 +408,23 @@ func invalidCredentials(username, password string) error {
 	}
 	return nil
 }
+
+// validate validates that the intervals of the groups of endpoints are either 0 or not less than the
+// collection interval of the receiver.
+func (cfg *CollectionIntervals) validate(collectionInterval time.Duration) error {
+	var combinedErr error
+	intervals := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "cluster_health", interval: cfg.ClusterHealth},
+		{name: "node_stats", interval: cfg.NodeStats},
+		{name: "index_stats", interval: cfg.IndexStats},
+	}
+	for _, i := range intervals {
+		if i.interval != 0 && i.interval < collectionInterval {
+			combinedErr = multierr.Append(combinedErr, fmt.Errorf("collection_intervals.%s %w", i.name, errCollectionInterval))
+		}
+	}
+	return combinedErr
+}
@@ -16,6 +16,7 @@ package elasticsearchreceiver
 
 import (
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,6 +62,74 @@ func TestValidateCredentials(t *testing.T) {
 				require.NoError(t, cfg.Validate())
 			},
 		},
+		{
+			desc: "Username and username file are both specified",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.Username = "user"
+				cfg.UsernameFile = "username"
+				cfg.Password = "pass"
+				require.ErrorIs(t, cfg.Validate(), errUsernameConflict)
+			},
+		},
+		{
+			desc: "Password and password file are both specified",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.Username = "user"
+				cfg.Password = "pass"
+				cfg.PasswordFile = "password"
+				require.ErrorIs(t, cfg.Validate(), errPasswordConflict)
+			},
+		},
+		{
+			desc: "Username file and password file are both specified",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.UsernameFile = "username"
+				cfg.PasswordFile = "password"
+				require.NoError(t, cfg.Validate())
+			},
+		},
+		{
+			desc: "Password file is specified, username is empty",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.PasswordFile = "password"
+				require.ErrorIs(t, cfg.Validate(), errUsernameNotSpecified)
+			},
+		},
+		{
+			desc: "API key and API key file are both specified",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.APIKey = "key"
+				cfg.APIKeyFile = "api_key"
+				require.ErrorIs(t, cfg.Validate(), errAPIKeyConflict)
+			},
+		},
+		{
+			desc: "API key and basic auth are both specified",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				cfg := NewFactory().CreateDefaultConfig().(*Config)
+				cfg.APIKeyFile = "api_key"
+				cfg.Username = "user"
+				cfg.Password = "pass"
+				require.ErrorIs(t, cfg.Validate(), errAPIKeyAndBasicAuth)
+			},
+		},
 		{
 			desc: "Username and password are both not specified",
 			run: func(t *testing.T) {
@@ -143,6 +212,191 @@ func TestValidateEndpoint(t *testing.T) {
 	}
 }
 
+func TestValidateIndices(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Indices = []string{"logs", "metrics-*"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Indices = []string{"logs", ""}
+	require.ErrorIs(t, cfg.Validate(), errEmptyIndexPattern)
+}
+
+func TestValidateEmitClusterHealthFrom(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.EmitClusterHealthFrom = "elected_master"
+	require.ErrorIs(t, cfg.Validate(), errEmitClusterNotLocal)
+
+	cfg.Nodes = []string{"_local"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.NodeRoles = []string{"master"}
+	require.ErrorIs(t, cfg.Validate(), errEmitClusterFilters)
+
+	cfg.NodeRoles = nil
+	cfg.SkipClusterMetrics = true
+	require.ErrorIs(t, cfg.Validate(), errEmitClusterSkipped)
+}
+
+func TestValidateTLS(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "https://localhost:9200"
+	cfg.TLSSetting.CAFile = "ca.pem"
+	cfg.TLSSetting.CertFile = "client.pem"
+	cfg.TLSSetting.KeyFile = "client-key.pem"
+	cfg.TLSSetting.ServerName = "elasticsearch.internal"
+	require.NoError(t, cfg.Validate())
+
+	cfg.TLSSetting.KeyFile = ""
+	require.ErrorIs(t, cfg.Validate(), errTLSCertKeyPair)
+
+	cfg.TLSSetting.CertFile = ""
+	cfg.TLSSetting.KeyFile = "client-key.pem"
+	require.ErrorIs(t, cfg.Validate(), errTLSCertKeyPair)
+
+	cfg.TLSSetting.CertFile = "client.pem"
+	cfg.Endpoint = "http://localhost:9200"
+	require.ErrorIs(t, cfg.Validate(), errTLSWithHTTP)
+}
+
+func TestValidateCAFingerprint(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := "DB:D8:A8:45:D6:6C:B7:5D:3E:1C:10:94:EE:9F:F0:0E:B6:D2:53:30:2B:48:A0:4C:6C:3E:25:D9:AB:0A:4E:1B"
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "https://localhost:9200"
+	cfg.CAFingerprint = fingerprint
+	require.NoError(t, cfg.Validate())
+
+	cfg.CAFingerprint = strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	require.NoError(t, cfg.Validate())
+
+	cfg.CAFingerprint = fingerprint[:len(fingerprint)-3]
+	require.ErrorIs(t, cfg.Validate(), errInvalidCAFingerprint)
+
+	cfg.CAFingerprint = fingerprint
+	cfg.TLSSetting.CAFile = "ca.pem"
+	require.ErrorIs(t, cfg.Validate(), errCAFingerprintCAFile)
+
+	cfg.TLSSetting.CAFile = ""
+	cfg.Headers = map[string]string{"X-Opaque-Id": "otel"}
+	require.ErrorIs(t, cfg.Validate(), errCAFingerprintWrapped)
+
+	cfg.Headers = nil
+	cfg.Endpoint = "http://localhost:9200"
+	require.ErrorIs(t, cfg.Validate(), errTLSWithHTTP)
+}
+
+func TestValidateNodeFilters(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.NodeRoles = []string{"master", "-data", "ingest"}
+	cfg.NodeAttributes = map[string]string{"rack": "r1"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.NodeRoles = []string{"master", "hot"}
+	require.ErrorIs(t, cfg.Validate(), errUnknownNodeRole)
+	require.Contains(t, cfg.Validate().Error(), `unknown role "hot"`)
+
+	cfg.NodeRoles = nil
+	cfg.NodeAttributes = map[string]string{"rack": ""}
+	require.ErrorIs(t, cfg.Validate(), errEmptyNodeAttribute)
+}
+
+func TestValidateRestrictedMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.RestrictedMode = restrictedModeAlways
+	require.NoError(t, cfg.Validate())
+
+	cfg.RestrictedMode = restrictedModeFallback
+	require.NoError(t, cfg.Validate())
+
+	cfg.RestrictedMode = "sometimes"
+	require.ErrorIs(t, cfg.Validate(), errUnknownRestricted)
+
+	cfg.RestrictedMode = restrictedModeFallback
+	cfg.Nodes = []string{localNode}
+	cfg.EmitClusterHealthFrom = "master"
+	require.ErrorIs(t, cfg.Validate(), errEmitClusterRestrict)
+
+	cfg.RestrictedMode = restrictedModeNever
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	require.Equal(t, 4, cfg.ConcurrentRequests)
+	require.NoError(t, cfg.Validate())
+
+	cfg.ConcurrentRequests = 1
+	require.NoError(t, cfg.Validate())
+
+	cfg.ConcurrentRequests = 0
+	require.ErrorIs(t, cfg.Validate(), errConcurrentRequests)
+}
+
+func TestNodeSelectors(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	require.Equal(t, []string{"_all"}, cfg.nodeSelectors())
+
+	cfg.Nodes = []string{"_local"}
+	cfg.NodeRoles = []string{"ingest", "-master"}
+	cfg.NodeAttributes = map[string]string{"zone": "eu-*", "rack": "r1"}
+	require.Equal(t, []string{"_local", "ingest:true", "master:false", "rack:r1", "zone:eu-*"}, cfg.nodeSelectors())
+
+	cfg.Nodes = nil
+	cfg.NodeRoles = nil
+	cfg.NodeAttributes = nil
+	require.Empty(t, cfg.nodeSelectors())
+}
+
+func TestValidateBackoff(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Backoff.MaxRetries = -1
+	require.ErrorIs(t, cfg.Validate(), errNegativeMaxRetries)
+
+	cfg.Backoff.MaxRetries = 2
+	cfg.Backoff.InitialInterval = 0
+	require.ErrorIs(t, cfg.Validate(), errInitialInterval)
+
+	cfg.Backoff.InitialInterval = 10 * time.Minute
+	require.ErrorIs(t, cfg.Validate(), errMaxInterval)
+
+	cfg.Backoff.MaxInterval = cfg.Backoff.InitialInterval
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateCollectionIntervals(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.CollectionIntervals.NodeStats = 5 * time.Second
+	require.ErrorIs(t, cfg.Validate(), errCollectionInterval)
+	require.Contains(t, cfg.Validate().Error(), "collection_intervals.node_stats")
+
+	cfg.CollectionIntervals.NodeStats = time.Minute
+	cfg.CollectionIntervals.IndexStats = -time.Minute
+	require.ErrorIs(t, cfg.Validate(), errCollectionInterval)
+
+	cfg.CollectionIntervals.ClusterHealth = cfg.CollectionInterval
+	cfg.CollectionIntervals.IndexStats = 5 * time.Minute
+	require.NoError(t, cfg.Validate())
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
@@ -173,6 +427,10 @@ func TestLoadConfig(t *testing.T) {
 	expectedAdvancedRecv.Password = "password"
 	expectedAdvancedRecv.Endpoint = "http://example.com:9200"
 	expectedAdvancedRecv.ScraperControllerSettings.CollectionInterval = 2 * time.Minute
+	expectedAdvancedRecv.Backoff.MaxRetries = 1
+	expectedAdvancedRecv.Backoff.MaxInterval = 10 * time.Minute
+	expectedAdvancedRecv.CollectionIntervals.NodeStats = 4 * time.Minute
+	expectedAdvancedRecv.ConcurrentRequests = 8
 
 	require.Equal(t, expectedAdvancedRecv, advancedRecv)
 }
@@ -0,0 +1,136 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultCredentialsReloadInterval is the interval at which credentials files are read again.
+const defaultCredentialsReloadInterval = time.Minute
+
+// credentials builds the Authorization header of requests. Credentials configured
+// with files are read again once the reload interval elapsed, or after the server
+// rejected them, so that rotated credentials are used without restarting the collector.
+type credentials struct {
+	username, usernameFile string
+	password, passwordFile string
+	apiKey, apiKeyFile     string
+	reloadInterval         time.Duration
+	logger                 *zap.Logger
+
+	mu       sync.Mutex
+	header   string
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func newCredentials(logger *zap.Logger, c Config) (*credentials, error) {
+	reloadInterval := c.CredentialsReloadInterval
+	if reloadInterval <= 0 {
+		reloadInterval = defaultCredentialsReloadInterval
+	}
+	creds := &credentials{
+		username:       c.Username,
+		usernameFile:   c.UsernameFile,
+		password:       c.Password,
+		passwordFile:   c.PasswordFile,
+		apiKey:         c.APIKey,
+		apiKeyFile:     c.APIKeyFile,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+		now:            time.Now,
+	}
+	header, err := creds.load()
+	if err != nil {
+		return nil, err
+	}
+	creds.header = header
+	creds.loadedAt = creds.now()
+	return creds, nil
+}
+
+// fromFiles returns whether any of the credentials is read from a file.
+func (c *credentials) fromFiles() bool {
+	return c.usernameFile != "" || c.passwordFile != "" || c.apiKeyFile != ""
+}
+
+// authHeader returns the value of the Authorization header, or an empty string if
+// no credentials are configured.
+func (c *credentials) authHeader() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fromFiles() && c.now().Sub(c.loadedAt) >= c.reloadInterval {
+		header, err := c.load()
+		if err != nil {
+			c.logger.Warn("Failed to reload credentials, using the previous ones", zap.Error(err))
+		} else {
+			c.header = header
+		}
+		c.loadedAt = c.now()
+	}
+	return c.header
+}
+
+// invalidate forces the credentials files to be read again on the next request.
+func (c *credentials) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadedAt = time.Time{}
+}
+
+func (c *credentials) load() (string, error) {
+	apiKey, err := readCredential(c.apiKey, c.apiKeyFile)
+	if err != nil {
+		return "", err
+	}
+	if apiKey != "" {
+		return fmt.Sprintf("ApiKey %s", apiKey), nil
+	}
+
+	username, err := readCredential(c.username, c.usernameFile)
+	if err != nil {
+		return "", err
+	}
+	password, err := readCredential(c.password, c.passwordFile)
+	if err != nil {
+		return "", err
+	}
+	if username == "" || password == "" {
+		return "", nil
+	}
+	userPass := fmt.Sprintf("%s:%s", username, password)
+	authb64 := base64.StdEncoding.EncodeToString([]byte(userPass))
+	return fmt.Sprintf("Basic %s", authb64), nil
+}
+
+// readCredential returns value, or the content of file without surrounding whitespace if set.
+func readCredential(value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
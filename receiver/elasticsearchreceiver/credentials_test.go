@@ -0,0 +1,91 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCredentialsStatic(t *testing.T) {
+	creds, err := newCredentials(zap.NewNop(), Config{Username: "user", Password: "pass"})
+	require.NoError(t, err)
+	require.Equal(t, "Basic dXNlcjpwYXNz", creds.authHeader())
+
+	creds, err = newCredentials(zap.NewNop(), Config{APIKey: "a2V5"})
+	require.NoError(t, err)
+	require.Equal(t, "ApiKey a2V5", creds.authHeader())
+
+	creds, err = newCredentials(zap.NewNop(), Config{})
+	require.NoError(t, err)
+	require.Equal(t, "", creds.authHeader())
+}
+
+func TestCredentialsReload(t *testing.T) {
+	dir := t.TempDir()
+	usernameFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(usernameFile, []byte("user\n"), 0600))
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass\n"), 0600))
+
+	creds, err := newCredentials(zap.NewNop(), Config{
+		UsernameFile:              usernameFile,
+		PasswordFile:              passwordFile,
+		CredentialsReloadInterval: time.Minute,
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	creds.now = func() time.Time { return now }
+	require.Equal(t, "Basic dXNlcjpwYXNz", creds.authHeader())
+
+	// The rotated password is only read once the reload interval elapsed.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("rotated"), 0600))
+	require.Equal(t, "Basic dXNlcjpwYXNz", creds.authHeader())
+	now = now.Add(time.Minute)
+	require.Equal(t, "Basic dXNlcjpyb3RhdGVk", creds.authHeader())
+
+	// The previous credentials are kept if the files can't be read.
+	require.NoError(t, os.Remove(passwordFile))
+	now = now.Add(time.Minute)
+	require.Equal(t, "Basic dXNlcjpyb3RhdGVk", creds.authHeader())
+
+	// Invalidated credentials are read again on the next request.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass"), 0600))
+	creds.invalidate()
+	require.Equal(t, "Basic dXNlcjpwYXNz", creds.authHeader())
+}
+
+func TestCredentialsAPIKeyFile(t *testing.T) {
+	apiKeyFile := filepath.Join(t.TempDir(), "api_key")
+	require.NoError(t, os.WriteFile(apiKeyFile, []byte("a2V5\n"), 0600))
+
+	creds, err := newCredentials(zap.NewNop(), Config{APIKeyFile: apiKeyFile})
+	require.NoError(t, err)
+	require.Equal(t, "ApiKey a2V5", creds.authHeader())
+}
+
+func TestCredentialsMissingFile(t *testing.T) {
+	_, err := newCredentials(zap.NewNop(), Config{
+		Username:     "user",
+		PasswordFile: filepath.Join(t.TempDir(), "missing"),
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read credentials file")
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -33,10 +34,14 @@ const (
 	typeStr                   = "elasticsearch"
 	defaultCollectionInterval = 10 * time.Second
 	defaultHTTPClientTimeout  = 10 * time.Second
+	defaultBackoffInitial     = 10 * time.Second
+	defaultBackoffMax         = 5 * time.Minute
+	defaultConcurrentRequests = 4
 )
 
 // NewFactory creates a factory for elasticsearch receiver.
 func NewFactory() component.ReceiverFactory {
+	_ = view.Register(MetricViews()...)
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -54,8 +59,16 @@ func createDefaultConfig() config.Receiver {
 			Endpoint: defaultEndpoint,
 			Timeout:  defaultHTTPClientTimeout,
 		},
-		Metrics: metadata.DefaultMetricsSettings(),
-		Nodes:   []string{"_all"},
+		Metrics:                   metadata.DefaultMetricsSettings(),
+		Nodes:                     []string{"_all"},
+		RestrictedMode:            restrictedModeNever,
+		CredentialsReloadInterval: defaultCredentialsReloadInterval,
+		Backoff: BackoffConfig{
+			InitialInterval: defaultBackoffInitial,
+			MaxInterval:     defaultBackoffMax,
+			SkipScrapes:     true,
+		},
+		ConcurrentRequests: defaultConcurrentRequests,
 	}
 }
 
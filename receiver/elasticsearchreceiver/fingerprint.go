@@ -0,0 +1,97 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// parseFingerprint decodes a hex encoded SHA-256 fingerprint, whose bytes may be separated by colons
+// like in the output of openssl x509 -fingerprint.
+func parseFingerprint(fingerprint string) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+	if err != nil || len(decoded) != sha256.Size {
+		return nil, errInvalidCAFingerprint
+	}
+	return decoded, nil
+}
+
+// verifyFingerprint returns a tls.Config.VerifyConnection function accepting the connections whose certificate
+// chain contains the certificate with the SHA-256 fingerprint, like the ca_fingerprint option of the official
+// Elasticsearch clients. The chain of the server certificate is verified up to the pinned certificate, which is
+// trusted instead of the system roots. Like with the official clients, the host name is not verified, as the
+// certificates generated by Elasticsearch for its nodes don't necessarily cover the addresses they are reached at.
+func verifyFingerprint(fingerprint []byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		for i, cert := range cs.PeerCertificates {
+			sum := sha256.Sum256(cert.Raw)
+			if !bytes.Equal(sum[:], fingerprint) {
+				continue
+			}
+			if i == 0 {
+				return nil
+			}
+			roots := x509.NewCertPool()
+			roots.AddCert(cert)
+			intermediates := x509.NewCertPool()
+			for _, intermediate := range cs.PeerCertificates[1:i] {
+				intermediates.AddCert(intermediate)
+			}
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+			return err
+		}
+		return fmt.Errorf("the certificate chain of the server has no certificate with the fingerprint %x", fingerprint)
+	}
+}
+
+// newHTTPClient returns the client of the HTTP client settings, verifying the certificates of the server against
+// the CA fingerprint instead of the system or configured roots when one is set.
+func newHTTPClient(c Config, h component.Host) (*http.Client, error) {
+	if c.MaxIdleConnsPerHost == nil && c.ConcurrentRequests > http.DefaultMaxIdleConnsPerHost {
+		// Keep a connection alive for each of the concurrent requests, so that the following scrapes reuse them.
+		maxIdleConnsPerHost := c.ConcurrentRequests
+		c.MaxIdleConnsPerHost = &maxIdleConnsPerHost
+	}
+	client, err := c.HTTPClientSettings.ToClient(h.GetExtensions())
+	if err != nil || c.CAFingerprint == "" {
+		return client, err
+	}
+
+	fingerprint, err := parseFingerprint(c.CAFingerprint)
+	if err != nil {
+		return nil, err
+	}
+	// The headers, compression and auth settings wrap the transport, whose TLS config can't be set then.
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, errCAFingerprintWrapped
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	// The default verification is replaced by the verification of the fingerprint.
+	transport.TLSClientConfig.InsecureSkipVerify = true // #nosec G402
+	transport.TLSClientConfig.VerifyConnection = verifyFingerprint(fingerprint)
+	return client, nil
+}
@@ -0,0 +1,167 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+// colonFingerprint formats the SHA-256 fingerprint of a DER encoded certificate like openssl x509 -fingerprint.
+func colonFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	hexSum := strings.ToUpper(hex.EncodeToString(sum[:]))
+	parts := make([]string, 0, sha256.Size)
+	for i := 0; i < len(hexSum); i += 2 {
+		parts = append(parts, hexSum[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+func TestClusterHealthCAFingerprint(t *testing.T) {
+	health, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
+	require.NoError(t, err)
+	actualClusterHealth := model.ClusterHealth{}
+	require.NoError(t, json.Unmarshal(health, &actualClusterHealth))
+
+	// The server certificate is only valid for elasticsearch.internal, which isn't verified with a fingerprint.
+	certs := newTestCertificates(t, "elasticsearch.internal")
+	otherCerts := newTestCertificates(t, "elasticsearch.internal")
+	elasticsearchMock := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+		_, err := rw.Write(health)
+		require.NoError(t, err)
+	}))
+	// The server sends its certificate along with the self-signed CA, like the nodes configured by Elasticsearch.
+	server := certs.server
+	server.Certificate = append(server.Certificate, certs.caDER)
+	elasticsearchMock.TLS = &tls.Config{Certificates: []tls.Certificate{server}}
+	elasticsearchMock.StartTLS()
+	defer elasticsearchMock.Close()
+
+	testCases := []struct {
+		desc        string
+		fingerprint string
+		expectedErr string
+	}{
+		{
+			desc:        "CA fingerprint",
+			fingerprint: colonFingerprint(certs.caDER),
+		},
+		{
+			desc:        "server certificate fingerprint",
+			fingerprint: strings.ToLower(strings.ReplaceAll(colonFingerprint(certs.server.Certificate[0]), ":", "")),
+		},
+		{
+			desc:        "other CA fingerprint",
+			fingerprint: colonFingerprint(otherCerts.caDER),
+			expectedErr: "the certificate chain of the server has no certificate with the fingerprint",
+		},
+		{
+			desc:        "no fingerprint",
+			expectedErr: "certificate",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, func(t *testing.T) {
+			client, err := newElasticsearchClient(zap.NewNop(), Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint: elasticsearchMock.URL,
+				},
+				CAFingerprint: testCase.fingerprint,
+			}, componenttest.NewNopHost())
+			require.NoError(t, err)
+
+			clusterHealth, err := client.ClusterHealth(context.Background())
+			if testCase.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &actualClusterHealth, clusterHealth)
+		})
+	}
+}
+
+func TestVerifyFingerprintUntrustedChain(t *testing.T) {
+	certs := newTestCertificates(t, "elasticsearch.internal")
+	otherCerts := newTestCertificates(t, "elasticsearch.internal")
+	elasticsearchMock := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(200)
+	}))
+	// The pinned CA is sent by the server, but didn't issue its certificate.
+	server := certs.server
+	server.Certificate = append(server.Certificate, otherCerts.caDER)
+	elasticsearchMock.TLS = &tls.Config{Certificates: []tls.Certificate{server}}
+	elasticsearchMock.StartTLS()
+	defer elasticsearchMock.Close()
+
+	client, err := newElasticsearchClient(zap.NewNop(), Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: elasticsearchMock.URL,
+		},
+		CAFingerprint: colonFingerprint(otherCerts.caDER),
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	_, err = client.ClusterHealth(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "certificate signed by unknown authority")
+}
+
+func TestNewHTTPClientCAFingerprintWrapped(t *testing.T) {
+	_, err := newHTTPClient(Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: "https://localhost:9200",
+			Headers:  map[string]string{"X-Opaque-Id": "otel"},
+		},
+		CAFingerprint: colonFingerprint([]byte("certificate")),
+	}, componenttest.NewNopHost())
+	require.ErrorIs(t, err, errCAFingerprintWrapped)
+}
+
+func TestNewHTTPClientMaxIdleConnsPerHost(t *testing.T) {
+	client, err := newHTTPClient(Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://localhost:9200"},
+		ConcurrentRequests: 8,
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+	require.Equal(t, 8, client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+
+	// The per host limit of the idle connections isn't overridden if set.
+	maxIdleConnsPerHost := 1
+	client, err = newHTTPClient(Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://localhost:9200", MaxIdleConnsPerHost: &maxIdleConnsPerHost},
+		ConcurrentRequests: 8,
+	}, componenttest.NewNopHost())
+	require.NoError(t, err)
+	require.Equal(t, 1, client.Transport.(*http.Transport).MaxIdleConnsPerHost)
+}
@@ -0,0 +1,70 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+// indexFilter matches the names of indices, aliases and data streams against the patterns of the indices option.
+type indexFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func newIndexFilter(patterns []string) *indexFilter {
+	f := &indexFilter{}
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		f.patterns = append(f.patterns, regexp.MustCompile("^"+strings.Join(parts, ".*")+"$"))
+	}
+	return f
+}
+
+func (f *indexFilter) matches(name string) bool {
+	for _, pattern := range f.patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the indices matched by the filter through one of their aliases or their data stream.
+func (f *indexFilter) resolve(aliases model.IndexAliases, dataStreams *model.DataStreams) map[string]bool {
+	resolved := map[string]bool{}
+	for index, info := range aliases {
+		for alias := range info.Aliases {
+			if f.matches(alias) {
+				resolved[index] = true
+				break
+			}
+		}
+	}
+	for _, dataStream := range dataStreams.DataStreams {
+		if !f.matches(dataStream.Name) {
+			continue
+		}
+		for _, index := range dataStream.Indices {
+			resolved[index.IndexName] = true
+		}
+	}
+	return resolved
+}
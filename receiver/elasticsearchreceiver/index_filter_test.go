@@ -0,0 +1,60 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+func TestIndexFilterMatches(t *testing.T) {
+	t.Parallel()
+
+	f := newIndexFilter([]string{"logs", "metrics-*-prod", ".ds-traces*"})
+
+	require.True(t, f.matches("logs"))
+	require.False(t, f.matches("logs-2022.02.01"))
+	require.True(t, f.matches("metrics-app-prod"))
+	require.True(t, f.matches("metrics--prod"))
+	require.False(t, f.matches("metrics-app-dev"))
+	require.True(t, f.matches(".ds-traces-2022.02.01-000001"))
+	// The dot is not a regular expression wildcard.
+	require.False(t, f.matches("xds-traces"))
+}
+
+func TestIndexFilterResolve(t *testing.T) {
+	t.Parallel()
+
+	aliases := model.IndexAliases{
+		"logs-000001":    {Aliases: map[string]struct{}{"logs": {}}},
+		"logs-000002":    {Aliases: map[string]struct{}{"logs": {}, "logs-write": {}}},
+		"archive-000001": {Aliases: map[string]struct{}{"archive": {}}},
+	}
+	dataStreams := &model.DataStreams{DataStreams: []model.DataStream{
+		{Name: "metrics-app", Indices: []model.DataStreamIndexInfo{{IndexName: ".ds-metrics-app-000001"}, {IndexName: ".ds-metrics-app-000002"}}},
+		{Name: "metrics-db", Indices: []model.DataStreamIndexInfo{{IndexName: ".ds-metrics-db-000001"}}},
+	}}
+
+	f := newIndexFilter([]string{"logs", "metrics-app"})
+	require.Equal(t, map[string]bool{
+		"logs-000001":            true,
+		"logs-000002":            true,
+		".ds-metrics-app-000001": true,
+		".ds-metrics-app-000002": true,
+	}, f.resolve(aliases, dataStreams))
+}
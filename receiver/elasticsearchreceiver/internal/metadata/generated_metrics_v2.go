@@ -15,35 +15,51 @@ type MetricSettings struct {
 
 // MetricsSettings provides settings for elasticsearchreceiver metrics.
 type MetricsSettings struct {
-	ElasticsearchClusterDataNodes            MetricSettings `mapstructure:"elasticsearch.cluster.data_nodes"`
-	ElasticsearchClusterHealth               MetricSettings `mapstructure:"elasticsearch.cluster.health"`
-	ElasticsearchClusterNodes                MetricSettings `mapstructure:"elasticsearch.cluster.nodes"`
-	ElasticsearchClusterShards               MetricSettings `mapstructure:"elasticsearch.cluster.shards"`
-	ElasticsearchNodeCacheEvictions          MetricSettings `mapstructure:"elasticsearch.node.cache.evictions"`
-	ElasticsearchNodeCacheMemoryUsage        MetricSettings `mapstructure:"elasticsearch.node.cache.memory.usage"`
-	ElasticsearchNodeClusterConnections      MetricSettings `mapstructure:"elasticsearch.node.cluster.connections"`
-	ElasticsearchNodeClusterIo               MetricSettings `mapstructure:"elasticsearch.node.cluster.io"`
-	ElasticsearchNodeDocuments               MetricSettings `mapstructure:"elasticsearch.node.documents"`
-	ElasticsearchNodeFsDiskAvailable         MetricSettings `mapstructure:"elasticsearch.node.fs.disk.available"`
-	ElasticsearchNodeHTTPConnections         MetricSettings `mapstructure:"elasticsearch.node.http.connections"`
-	ElasticsearchNodeOpenFiles               MetricSettings `mapstructure:"elasticsearch.node.open_files"`
-	ElasticsearchNodeOperationsCompleted     MetricSettings `mapstructure:"elasticsearch.node.operations.completed"`
-	ElasticsearchNodeOperationsTime          MetricSettings `mapstructure:"elasticsearch.node.operations.time"`
-	ElasticsearchNodeShardsSize              MetricSettings `mapstructure:"elasticsearch.node.shards.size"`
-	ElasticsearchNodeThreadPoolTasksFinished MetricSettings `mapstructure:"elasticsearch.node.thread_pool.tasks.finished"`
-	ElasticsearchNodeThreadPoolTasksQueued   MetricSettings `mapstructure:"elasticsearch.node.thread_pool.tasks.queued"`
-	ElasticsearchNodeThreadPoolThreads       MetricSettings `mapstructure:"elasticsearch.node.thread_pool.threads"`
-	JvmClassesLoaded                         MetricSettings `mapstructure:"jvm.classes.loaded"`
-	JvmGcCollectionsCount                    MetricSettings `mapstructure:"jvm.gc.collections.count"`
-	JvmGcCollectionsElapsed                  MetricSettings `mapstructure:"jvm.gc.collections.elapsed"`
-	JvmMemoryHeapCommitted                   MetricSettings `mapstructure:"jvm.memory.heap.committed"`
-	JvmMemoryHeapMax                         MetricSettings `mapstructure:"jvm.memory.heap.max"`
-	JvmMemoryHeapUsed                        MetricSettings `mapstructure:"jvm.memory.heap.used"`
-	JvmMemoryNonheapCommitted                MetricSettings `mapstructure:"jvm.memory.nonheap.committed"`
-	JvmMemoryNonheapUsed                     MetricSettings `mapstructure:"jvm.memory.nonheap.used"`
-	JvmMemoryPoolMax                         MetricSettings `mapstructure:"jvm.memory.pool.max"`
-	JvmMemoryPoolUsed                        MetricSettings `mapstructure:"jvm.memory.pool.used"`
-	JvmThreadsCount                          MetricSettings `mapstructure:"jvm.threads.count"`
+	ElasticsearchClusterDataNodes                     MetricSettings `mapstructure:"elasticsearch.cluster.data_nodes"`
+	ElasticsearchClusterHealth                        MetricSettings `mapstructure:"elasticsearch.cluster.health"`
+	ElasticsearchClusterIlmIndicesErrors              MetricSettings `mapstructure:"elasticsearch.cluster.ilm.indices.errors"`
+	ElasticsearchClusterIlmStatus                     MetricSettings `mapstructure:"elasticsearch.cluster.ilm.status"`
+	ElasticsearchClusterMlJobState                    MetricSettings `mapstructure:"elasticsearch.cluster.ml.job.state"`
+	ElasticsearchClusterNodes                         MetricSettings `mapstructure:"elasticsearch.cluster.nodes"`
+	ElasticsearchClusterNodesJvmMemoryHeapMax         MetricSettings `mapstructure:"elasticsearch.cluster.nodes.jvm.memory.heap.max"`
+	ElasticsearchClusterNodesJvmMemoryHeapUsed        MetricSettings `mapstructure:"elasticsearch.cluster.nodes.jvm.memory.heap.used"`
+	ElasticsearchClusterNodesJvmMemoryHeapUtilization MetricSettings `mapstructure:"elasticsearch.cluster.nodes.jvm.memory.heap.utilization"`
+	ElasticsearchClusterNodesOperationsRate           MetricSettings `mapstructure:"elasticsearch.cluster.nodes.operations.rate"`
+	ElasticsearchClusterPendingTasks                  MetricSettings `mapstructure:"elasticsearch.cluster.pending_tasks"`
+	ElasticsearchClusterPendingTasksMaxAge            MetricSettings `mapstructure:"elasticsearch.cluster.pending_tasks.max_age"`
+	ElasticsearchClusterShards                        MetricSettings `mapstructure:"elasticsearch.cluster.shards"`
+	ElasticsearchClusterTransformFailures             MetricSettings `mapstructure:"elasticsearch.cluster.transform.failures"`
+	ElasticsearchClusterTransformState                MetricSettings `mapstructure:"elasticsearch.cluster.transform.state"`
+	ElasticsearchNodeCacheCount                       MetricSettings `mapstructure:"elasticsearch.node.cache.count"`
+	ElasticsearchNodeCacheEntries                     MetricSettings `mapstructure:"elasticsearch.node.cache.entries"`
+	ElasticsearchNodeCacheEvictions                   MetricSettings `mapstructure:"elasticsearch.node.cache.evictions"`
+	ElasticsearchNodeCacheMemoryUsage                 MetricSettings `mapstructure:"elasticsearch.node.cache.memory.usage"`
+	ElasticsearchNodeClusterConnections               MetricSettings `mapstructure:"elasticsearch.node.cluster.connections"`
+	ElasticsearchNodeClusterIo                        MetricSettings `mapstructure:"elasticsearch.node.cluster.io"`
+	ElasticsearchNodeDocuments                        MetricSettings `mapstructure:"elasticsearch.node.documents"`
+	ElasticsearchNodeFsDiskAvailable                  MetricSettings `mapstructure:"elasticsearch.node.fs.disk.available"`
+	ElasticsearchNodeHTTPConnections                  MetricSettings `mapstructure:"elasticsearch.node.http.connections"`
+	ElasticsearchNodeOpenFiles                        MetricSettings `mapstructure:"elasticsearch.node.open_files"`
+	ElasticsearchNodeOperationsCompleted              MetricSettings `mapstructure:"elasticsearch.node.operations.completed"`
+	ElasticsearchNodeOperationsTime                   MetricSettings `mapstructure:"elasticsearch.node.operations.time"`
+	ElasticsearchNodeShardsSize                       MetricSettings `mapstructure:"elasticsearch.node.shards.size"`
+	ElasticsearchNodeThreadPoolTasksFinished          MetricSettings `mapstructure:"elasticsearch.node.thread_pool.tasks.finished"`
+	ElasticsearchNodeThreadPoolTasksQueued            MetricSettings `mapstructure:"elasticsearch.node.thread_pool.tasks.queued"`
+	ElasticsearchNodeThreadPoolThreads                MetricSettings `mapstructure:"elasticsearch.node.thread_pool.threads"`
+	ElasticsearchShardDocuments                       MetricSettings `mapstructure:"elasticsearch.shard.documents"`
+	ElasticsearchShardStoreSize                       MetricSettings `mapstructure:"elasticsearch.shard.store.size"`
+	JvmClassesLoaded                                  MetricSettings `mapstructure:"jvm.classes.loaded"`
+	JvmGcCollectionsCount                             MetricSettings `mapstructure:"jvm.gc.collections.count"`
+	JvmGcCollectionsElapsed                           MetricSettings `mapstructure:"jvm.gc.collections.elapsed"`
+	JvmMemoryHeapCommitted                            MetricSettings `mapstructure:"jvm.memory.heap.committed"`
+	JvmMemoryHeapMax                                  MetricSettings `mapstructure:"jvm.memory.heap.max"`
+	JvmMemoryHeapUsed                                 MetricSettings `mapstructure:"jvm.memory.heap.used"`
+	JvmMemoryNonheapCommitted                         MetricSettings `mapstructure:"jvm.memory.nonheap.committed"`
+	JvmMemoryNonheapUsed                              MetricSettings `mapstructure:"jvm.memory.nonheap.used"`
+	JvmMemoryPoolMax                                  MetricSettings `mapstructure:"jvm.memory.pool.max"`
+	JvmMemoryPoolPeakUsed                             MetricSettings `mapstructure:"jvm.memory.pool.peak_used"`
+	JvmMemoryPoolUsed                                 MetricSettings `mapstructure:"jvm.memory.pool.used"`
+	JvmThreadsCount                                   MetricSettings `mapstructure:"jvm.threads.count"`
 }
 
 func DefaultMetricsSettings() MetricsSettings {
@@ -54,12 +70,51 @@ func DefaultMetricsSettings() MetricsSettings {
 		ElasticsearchClusterHealth: MetricSettings{
 			Enabled: true,
 		},
+		ElasticsearchClusterIlmIndicesErrors: MetricSettings{
+			Enabled: false,
+		},
+		ElasticsearchClusterIlmStatus: MetricSettings{
+			Enabled: false,
+		},
+		ElasticsearchClusterMlJobState: MetricSettings{
+			Enabled: true,
+		},
 		ElasticsearchClusterNodes: MetricSettings{
 			Enabled: true,
 		},
+		ElasticsearchClusterNodesJvmMemoryHeapMax: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchClusterNodesJvmMemoryHeapUsed: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchClusterNodesJvmMemoryHeapUtilization: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchClusterNodesOperationsRate: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchClusterPendingTasks: MetricSettings{
+			Enabled: false,
+		},
+		ElasticsearchClusterPendingTasksMaxAge: MetricSettings{
+			Enabled: false,
+		},
 		ElasticsearchClusterShards: MetricSettings{
 			Enabled: true,
 		},
+		ElasticsearchClusterTransformFailures: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchClusterTransformState: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchNodeCacheCount: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchNodeCacheEntries: MetricSettings{
+			Enabled: true,
+		},
 		ElasticsearchNodeCacheEvictions: MetricSettings{
 			Enabled: true,
 		},
@@ -102,6 +157,12 @@ func DefaultMetricsSettings() MetricsSettings {
 		ElasticsearchNodeThreadPoolThreads: MetricSettings{
 			Enabled: true,
 		},
+		ElasticsearchShardDocuments: MetricSettings{
+			Enabled: true,
+		},
+		ElasticsearchShardStoreSize: MetricSettings{
+			Enabled: true,
+		},
 		JvmClassesLoaded: MetricSettings{
 			Enabled: true,
 		},
@@ -129,6 +190,9 @@ func DefaultMetricsSettings() MetricsSettings {
 		JvmMemoryPoolMax: MetricSettings{
 			Enabled: true,
 		},
+		JvmMemoryPoolPeakUsed: MetricSettings{
+			Enabled: true,
+		},
 		JvmMemoryPoolUsed: MetricSettings{
 			Enabled: true,
 		},
@@ -138,23 +202,692 @@ func DefaultMetricsSettings() MetricsSettings {
 	}
 }
 
-type metricElasticsearchClusterDataNodes struct {
+type metricElasticsearchClusterDataNodes struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.data_nodes metric with initial data.
+func (m *metricElasticsearchClusterDataNodes) init() {
+	m.data.SetName("elasticsearch.cluster.data_nodes")
+	m.data.SetDescription("The number of data nodes in the cluster.")
+	m.data.SetUnit("{nodes}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+}
+
+func (m *metricElasticsearchClusterDataNodes) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterDataNodes) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterDataNodes) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterDataNodes(settings MetricSettings) metricElasticsearchClusterDataNodes {
+	m := metricElasticsearchClusterDataNodes{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterHealth struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.health metric with initial data.
+func (m *metricElasticsearchClusterHealth) init() {
+	m.data.SetName("elasticsearch.cluster.health")
+	m.data.SetDescription("The health status of the cluster.")
+	m.data.SetUnit("{status}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterHealth) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, healthStatusAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.HealthStatus, pdata.NewAttributeValueString(healthStatusAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterHealth) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterHealth) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterHealth(settings MetricSettings) metricElasticsearchClusterHealth {
+	m := metricElasticsearchClusterHealth{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterIlmIndicesErrors struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.ilm.indices.errors metric with initial data.
+func (m *metricElasticsearchClusterIlmIndicesErrors) init() {
+	m.data.SetName("elasticsearch.cluster.ilm.indices.errors")
+	m.data.SetDescription("The number of indices in the ERROR step of their index lifecycle management policy.")
+	m.data.SetUnit("{indices}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterIlmIndicesErrors) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, ilmPolicyAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.IlmPolicy, pdata.NewAttributeValueString(ilmPolicyAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterIlmIndicesErrors) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterIlmIndicesErrors) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterIlmIndicesErrors(settings MetricSettings) metricElasticsearchClusterIlmIndicesErrors {
+	m := metricElasticsearchClusterIlmIndicesErrors{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterIlmStatus struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.ilm.status metric with initial data.
+func (m *metricElasticsearchClusterIlmStatus) init() {
+	m.data.SetName("elasticsearch.cluster.ilm.status")
+	m.data.SetDescription("The operation mode of index lifecycle management.")
+	m.data.SetUnit("{status}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterIlmStatus) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, ilmStatusAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.IlmStatus, pdata.NewAttributeValueString(ilmStatusAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterIlmStatus) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterIlmStatus) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterIlmStatus(settings MetricSettings) metricElasticsearchClusterIlmStatus {
+	m := metricElasticsearchClusterIlmStatus{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterMlJobState struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.ml.job.state metric with initial data.
+func (m *metricElasticsearchClusterMlJobState) init() {
+	m.data.SetName("elasticsearch.cluster.ml.job.state")
+	m.data.SetDescription("The state of the machine learning anomaly detection job.")
+	m.data.SetUnit("{state}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterMlJobState) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, mlJobIDAttributeValue string, mlJobStateAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.MlJobID, pdata.NewAttributeValueString(mlJobIDAttributeValue))
+	dp.Attributes().Insert(A.MlJobState, pdata.NewAttributeValueString(mlJobStateAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterMlJobState) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterMlJobState) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterMlJobState(settings MetricSettings) metricElasticsearchClusterMlJobState {
+	m := metricElasticsearchClusterMlJobState{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterNodes struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.nodes metric with initial data.
+func (m *metricElasticsearchClusterNodes) init() {
+	m.data.SetName("elasticsearch.cluster.nodes")
+	m.data.SetDescription("The total number of nodes in the cluster.")
+	m.data.SetUnit("{nodes}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+}
+
+func (m *metricElasticsearchClusterNodes) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterNodes) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterNodes) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterNodes(settings MetricSettings) metricElasticsearchClusterNodes {
+	m := metricElasticsearchClusterNodes{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterNodesJvmMemoryHeapMax struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.nodes.jvm.memory.heap.max metric with initial data.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapMax) init() {
+	m.data.SetName("elasticsearch.cluster.nodes.jvm.memory.heap.max")
+	m.data.SetDescription("The sum of the maximum JVM heap memory of the scraped nodes.")
+	m.data.SetUnit("By")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+}
+
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapMax) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapMax) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapMax) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterNodesJvmMemoryHeapMax(settings MetricSettings) metricElasticsearchClusterNodesJvmMemoryHeapMax {
+	m := metricElasticsearchClusterNodesJvmMemoryHeapMax{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterNodesJvmMemoryHeapUsed struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.nodes.jvm.memory.heap.used metric with initial data.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUsed) init() {
+	m.data.SetName("elasticsearch.cluster.nodes.jvm.memory.heap.used")
+	m.data.SetDescription("The sum of the JVM heap memory used by the scraped nodes.")
+	m.data.SetUnit("By")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+}
+
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUsed) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUsed) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUsed) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterNodesJvmMemoryHeapUsed(settings MetricSettings) metricElasticsearchClusterNodesJvmMemoryHeapUsed {
+	m := metricElasticsearchClusterNodesJvmMemoryHeapUsed{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterNodesJvmMemoryHeapUtilization struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.nodes.jvm.memory.heap.utilization metric with initial data.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUtilization) init() {
+	m.data.SetName("elasticsearch.cluster.nodes.jvm.memory.heap.utilization")
+	m.data.SetDescription("The average across the scraped nodes of the fraction of the maximum JVM heap memory used.")
+	m.data.SetUnit("1")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+}
+
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUtilization) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val float64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUtilization) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterNodesJvmMemoryHeapUtilization) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterNodesJvmMemoryHeapUtilization(settings MetricSettings) metricElasticsearchClusterNodesJvmMemoryHeapUtilization {
+	m := metricElasticsearchClusterNodesJvmMemoryHeapUtilization{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterNodesOperationsRate struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.nodes.operations.rate metric with initial data.
+func (m *metricElasticsearchClusterNodesOperationsRate) init() {
+	m.data.SetName("elasticsearch.cluster.nodes.operations.rate")
+	m.data.SetDescription("The sum of the rates of the operations completed by the scraped nodes since the previous scrape.")
+	m.data.SetUnit("{operations}/s")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterNodesOperationsRate) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val float64, operationAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleVal(val)
+	dp.Attributes().Insert(A.Operation, pdata.NewAttributeValueString(operationAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterNodesOperationsRate) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterNodesOperationsRate) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterNodesOperationsRate(settings MetricSettings) metricElasticsearchClusterNodesOperationsRate {
+	m := metricElasticsearchClusterNodesOperationsRate{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterPendingTasks struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.pending_tasks metric with initial data.
+func (m *metricElasticsearchClusterPendingTasks) init() {
+	m.data.SetName("elasticsearch.cluster.pending_tasks")
+	m.data.SetDescription("The number of cluster state update tasks waiting to be executed by the elected master.")
+	m.data.SetUnit("{tasks}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterPendingTasks) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, taskPriorityAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.TaskPriority, pdata.NewAttributeValueString(taskPriorityAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterPendingTasks) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterPendingTasks) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterPendingTasks(settings MetricSettings) metricElasticsearchClusterPendingTasks {
+	m := metricElasticsearchClusterPendingTasks{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterPendingTasksMaxAge struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.pending_tasks.max_age metric with initial data.
+func (m *metricElasticsearchClusterPendingTasksMaxAge) init() {
+	m.data.SetName("elasticsearch.cluster.pending_tasks.max_age")
+	m.data.SetDescription("The time the oldest pending cluster state update task has been waiting to be executed.")
+	m.data.SetUnit("ms")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+}
+
+func (m *metricElasticsearchClusterPendingTasksMaxAge) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterPendingTasksMaxAge) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterPendingTasksMaxAge) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterPendingTasksMaxAge(settings MetricSettings) metricElasticsearchClusterPendingTasksMaxAge {
+	m := metricElasticsearchClusterPendingTasksMaxAge{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterShards struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.cluster.shards metric with initial data.
+func (m *metricElasticsearchClusterShards) init() {
+	m.data.SetName("elasticsearch.cluster.shards")
+	m.data.SetDescription("The number of shards in the cluster.")
+	m.data.SetUnit("{shards}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchClusterShards) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, shardStateAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.ShardState, pdata.NewAttributeValueString(shardStateAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchClusterShards) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchClusterShards) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchClusterShards(settings MetricSettings) metricElasticsearchClusterShards {
+	m := metricElasticsearchClusterShards{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchClusterTransformFailures struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills elasticsearch.cluster.data_nodes metric with initial data.
-func (m *metricElasticsearchClusterDataNodes) init() {
-	m.data.SetName("elasticsearch.cluster.data_nodes")
-	m.data.SetDescription("The number of data nodes in the cluster.")
-	m.data.SetUnit("{nodes}")
+// init fills elasticsearch.cluster.transform.failures metric with initial data.
+func (m *metricElasticsearchClusterTransformFailures) init() {
+	m.data.SetName("elasticsearch.cluster.transform.failures")
+	m.data.SetDescription("The number of failed operations of the transform.")
+	m.data.SetUnit("{failures}")
 	m.data.SetDataType(pdata.MetricDataTypeSum)
-	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetIsMonotonic(true)
 	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricElasticsearchClusterDataNodes) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+func (m *metricElasticsearchClusterTransformFailures) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, transformIDAttributeValue string, transformFailureTypeAttributeValue string) {
 	if !m.settings.Enabled {
 		return
 	}
@@ -162,17 +895,19 @@ func (m *metricElasticsearchClusterDataNodes) recordDataPoint(start pdata.Timest
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.TransformID, pdata.NewAttributeValueString(transformIDAttributeValue))
+	dp.Attributes().Insert(A.TransformFailureType, pdata.NewAttributeValueString(transformFailureTypeAttributeValue))
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricElasticsearchClusterDataNodes) updateCapacity() {
+func (m *metricElasticsearchClusterTransformFailures) updateCapacity() {
 	if m.data.Sum().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricElasticsearchClusterDataNodes) emit(metrics pdata.MetricSlice) {
+func (m *metricElasticsearchClusterTransformFailures) emit(metrics pdata.MetricSlice) {
 	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -180,8 +915,8 @@ func (m *metricElasticsearchClusterDataNodes) emit(metrics pdata.MetricSlice) {
 	}
 }
 
-func newMetricElasticsearchClusterDataNodes(settings MetricSettings) metricElasticsearchClusterDataNodes {
-	m := metricElasticsearchClusterDataNodes{settings: settings}
+func newMetricElasticsearchClusterTransformFailures(settings MetricSettings) metricElasticsearchClusterTransformFailures {
+	m := metricElasticsearchClusterTransformFailures{settings: settings}
 	if settings.Enabled {
 		m.data = pdata.NewMetric()
 		m.init()
@@ -189,24 +924,24 @@ func newMetricElasticsearchClusterDataNodes(settings MetricSettings) metricElast
 	return m
 }
 
-type metricElasticsearchClusterHealth struct {
+type metricElasticsearchClusterTransformState struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills elasticsearch.cluster.health metric with initial data.
-func (m *metricElasticsearchClusterHealth) init() {
-	m.data.SetName("elasticsearch.cluster.health")
-	m.data.SetDescription("The health status of the cluster.")
-	m.data.SetUnit("{status}")
+// init fills elasticsearch.cluster.transform.state metric with initial data.
+func (m *metricElasticsearchClusterTransformState) init() {
+	m.data.SetName("elasticsearch.cluster.transform.state")
+	m.data.SetDescription("The state of the transform.")
+	m.data.SetUnit("{state}")
 	m.data.SetDataType(pdata.MetricDataTypeSum)
 	m.data.Sum().SetIsMonotonic(false)
 	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricElasticsearchClusterHealth) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, healthStatusAttributeValue string) {
+func (m *metricElasticsearchClusterTransformState) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, transformIDAttributeValue string, transformStateAttributeValue string) {
 	if !m.settings.Enabled {
 		return
 	}
@@ -214,18 +949,19 @@ func (m *metricElasticsearchClusterHealth) recordDataPoint(start pdata.Timestamp
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntVal(val)
-	dp.Attributes().Insert(A.HealthStatus, pdata.NewAttributeValueString(healthStatusAttributeValue))
+	dp.Attributes().Insert(A.TransformID, pdata.NewAttributeValueString(transformIDAttributeValue))
+	dp.Attributes().Insert(A.TransformState, pdata.NewAttributeValueString(transformStateAttributeValue))
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricElasticsearchClusterHealth) updateCapacity() {
+func (m *metricElasticsearchClusterTransformState) updateCapacity() {
 	if m.data.Sum().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricElasticsearchClusterHealth) emit(metrics pdata.MetricSlice) {
+func (m *metricElasticsearchClusterTransformState) emit(metrics pdata.MetricSlice) {
 	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -233,8 +969,8 @@ func (m *metricElasticsearchClusterHealth) emit(metrics pdata.MetricSlice) {
 	}
 }
 
-func newMetricElasticsearchClusterHealth(settings MetricSettings) metricElasticsearchClusterHealth {
-	m := metricElasticsearchClusterHealth{settings: settings}
+func newMetricElasticsearchClusterTransformState(settings MetricSettings) metricElasticsearchClusterTransformState {
+	m := metricElasticsearchClusterTransformState{settings: settings}
 	if settings.Enabled {
 		m.data = pdata.NewMetric()
 		m.init()
@@ -242,23 +978,24 @@ func newMetricElasticsearchClusterHealth(settings MetricSettings) metricElastics
 	return m
 }
 
-type metricElasticsearchClusterNodes struct {
+type metricElasticsearchNodeCacheCount struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills elasticsearch.cluster.nodes metric with initial data.
-func (m *metricElasticsearchClusterNodes) init() {
-	m.data.SetName("elasticsearch.cluster.nodes")
-	m.data.SetDescription("The total number of nodes in the cluster.")
-	m.data.SetUnit("{nodes}")
+// init fills elasticsearch.node.cache.count metric with initial data.
+func (m *metricElasticsearchNodeCacheCount) init() {
+	m.data.SetName("elasticsearch.node.cache.count")
+	m.data.SetDescription("The number of lookups in the query cache, by whether they hit the cache.")
+	m.data.SetUnit("{count}")
 	m.data.SetDataType(pdata.MetricDataTypeSum)
-	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetIsMonotonic(true)
 	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricElasticsearchClusterNodes) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+func (m *metricElasticsearchNodeCacheCount) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, queryCacheCountTypeAttributeValue string) {
 	if !m.settings.Enabled {
 		return
 	}
@@ -266,17 +1003,18 @@ func (m *metricElasticsearchClusterNodes) recordDataPoint(start pdata.Timestamp,
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.QueryCacheCountType, pdata.NewAttributeValueString(queryCacheCountTypeAttributeValue))
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricElasticsearchClusterNodes) updateCapacity() {
+func (m *metricElasticsearchNodeCacheCount) updateCapacity() {
 	if m.data.Sum().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricElasticsearchClusterNodes) emit(metrics pdata.MetricSlice) {
+func (m *metricElasticsearchNodeCacheCount) emit(metrics pdata.MetricSlice) {
 	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -284,8 +1022,8 @@ func (m *metricElasticsearchClusterNodes) emit(metrics pdata.MetricSlice) {
 	}
 }
 
-func newMetricElasticsearchClusterNodes(settings MetricSettings) metricElasticsearchClusterNodes {
-	m := metricElasticsearchClusterNodes{settings: settings}
+func newMetricElasticsearchNodeCacheCount(settings MetricSettings) metricElasticsearchNodeCacheCount {
+	m := metricElasticsearchNodeCacheCount{settings: settings}
 	if settings.Enabled {
 		m.data = pdata.NewMetric()
 		m.init()
@@ -293,24 +1031,23 @@ func newMetricElasticsearchClusterNodes(settings MetricSettings) metricElasticse
 	return m
 }
 
-type metricElasticsearchClusterShards struct {
+type metricElasticsearchNodeCacheEntries struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills elasticsearch.cluster.shards metric with initial data.
-func (m *metricElasticsearchClusterShards) init() {
-	m.data.SetName("elasticsearch.cluster.shards")
-	m.data.SetDescription("The number of shards in the cluster.")
-	m.data.SetUnit("{shards}")
+// init fills elasticsearch.node.cache.entries metric with initial data.
+func (m *metricElasticsearchNodeCacheEntries) init() {
+	m.data.SetName("elasticsearch.node.cache.entries")
+	m.data.SetDescription("The number of entries in the query cache.")
+	m.data.SetUnit("{entries}")
 	m.data.SetDataType(pdata.MetricDataTypeSum)
 	m.data.Sum().SetIsMonotonic(false)
 	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
-	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricElasticsearchClusterShards) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, shardStateAttributeValue string) {
+func (m *metricElasticsearchNodeCacheEntries) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
 	if !m.settings.Enabled {
 		return
 	}
@@ -318,18 +1055,17 @@ func (m *metricElasticsearchClusterShards) recordDataPoint(start pdata.Timestamp
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntVal(val)
-	dp.Attributes().Insert(A.ShardState, pdata.NewAttributeValueString(shardStateAttributeValue))
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricElasticsearchClusterShards) updateCapacity() {
+func (m *metricElasticsearchNodeCacheEntries) updateCapacity() {
 	if m.data.Sum().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricElasticsearchClusterShards) emit(metrics pdata.MetricSlice) {
+func (m *metricElasticsearchNodeCacheEntries) emit(metrics pdata.MetricSlice) {
 	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -337,8 +1073,8 @@ func (m *metricElasticsearchClusterShards) emit(metrics pdata.MetricSlice) {
 	}
 }
 
-func newMetricElasticsearchClusterShards(settings MetricSettings) metricElasticsearchClusterShards {
-	m := metricElasticsearchClusterShards{settings: settings}
+func newMetricElasticsearchNodeCacheEntries(settings MetricSettings) metricElasticsearchNodeCacheEntries {
+	m := metricElasticsearchNodeCacheEntries{settings: settings}
 	if settings.Enabled {
 		m.data = pdata.NewMetric()
 		m.init()
@@ -1080,6 +1816,119 @@ func newMetricElasticsearchNodeThreadPoolThreads(settings MetricSettings) metric
 	return m
 }
 
+type metricElasticsearchShardDocuments struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.shard.documents metric with initial data.
+func (m *metricElasticsearchShardDocuments) init() {
+	m.data.SetName("elasticsearch.shard.documents")
+	m.data.SetDescription("The number of documents in the shard copy.")
+	m.data.SetUnit("{documents}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchShardDocuments) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, indexNameAttributeValue string, shardIDAttributeValue string, shardNodeAttributeValue string, shardTypeAttributeValue string, documentStateAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.IndexName, pdata.NewAttributeValueString(indexNameAttributeValue))
+	dp.Attributes().Insert(A.ShardID, pdata.NewAttributeValueString(shardIDAttributeValue))
+	dp.Attributes().Insert(A.ShardNode, pdata.NewAttributeValueString(shardNodeAttributeValue))
+	dp.Attributes().Insert(A.ShardType, pdata.NewAttributeValueString(shardTypeAttributeValue))
+	dp.Attributes().Insert(A.DocumentState, pdata.NewAttributeValueString(documentStateAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchShardDocuments) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchShardDocuments) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchShardDocuments(settings MetricSettings) metricElasticsearchShardDocuments {
+	m := metricElasticsearchShardDocuments{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricElasticsearchShardStoreSize struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills elasticsearch.shard.store.size metric with initial data.
+func (m *metricElasticsearchShardStoreSize) init() {
+	m.data.SetName("elasticsearch.shard.store.size")
+	m.data.SetDescription("The size of the shard copy on disk.")
+	m.data.SetUnit("By")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricElasticsearchShardStoreSize) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, indexNameAttributeValue string, shardIDAttributeValue string, shardNodeAttributeValue string, shardTypeAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.IndexName, pdata.NewAttributeValueString(indexNameAttributeValue))
+	dp.Attributes().Insert(A.ShardID, pdata.NewAttributeValueString(shardIDAttributeValue))
+	dp.Attributes().Insert(A.ShardNode, pdata.NewAttributeValueString(shardNodeAttributeValue))
+	dp.Attributes().Insert(A.ShardType, pdata.NewAttributeValueString(shardTypeAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricElasticsearchShardStoreSize) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricElasticsearchShardStoreSize) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricElasticsearchShardStoreSize(settings MetricSettings) metricElasticsearchShardStoreSize {
+	m := metricElasticsearchShardStoreSize{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricJvmClassesLoaded struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1531,6 +2380,57 @@ func newMetricJvmMemoryPoolMax(settings MetricSettings) metricJvmMemoryPoolMax {
 	return m
 }
 
+type metricJvmMemoryPoolPeakUsed struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills jvm.memory.pool.peak_used metric with initial data.
+func (m *metricJvmMemoryPoolPeakUsed) init() {
+	m.data.SetName("jvm.memory.pool.peak_used")
+	m.data.SetDescription("The highest memory pool memory usage since the JVM started")
+	m.data.SetUnit("By")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricJvmMemoryPoolPeakUsed) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, memoryPoolNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.MemoryPoolName, pdata.NewAttributeValueString(memoryPoolNameAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricJvmMemoryPoolPeakUsed) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricJvmMemoryPoolPeakUsed) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricJvmMemoryPoolPeakUsed(settings MetricSettings) metricJvmMemoryPoolPeakUsed {
+	m := metricJvmMemoryPoolPeakUsed{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricJvmMemoryPoolUsed struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1634,36 +2534,53 @@ func newMetricJvmThreadsCount(settings MetricSettings) metricJvmThreadsCount {
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
-	startTime                                      pdata.Timestamp
-	metricElasticsearchClusterDataNodes            metricElasticsearchClusterDataNodes
-	metricElasticsearchClusterHealth               metricElasticsearchClusterHealth
-	metricElasticsearchClusterNodes                metricElasticsearchClusterNodes
-	metricElasticsearchClusterShards               metricElasticsearchClusterShards
-	metricElasticsearchNodeCacheEvictions          metricElasticsearchNodeCacheEvictions
-	metricElasticsearchNodeCacheMemoryUsage        metricElasticsearchNodeCacheMemoryUsage
-	metricElasticsearchNodeClusterConnections      metricElasticsearchNodeClusterConnections
-	metricElasticsearchNodeClusterIo               metricElasticsearchNodeClusterIo
-	metricElasticsearchNodeDocuments               metricElasticsearchNodeDocuments
-	metricElasticsearchNodeFsDiskAvailable         metricElasticsearchNodeFsDiskAvailable
-	metricElasticsearchNodeHTTPConnections         metricElasticsearchNodeHTTPConnections
-	metricElasticsearchNodeOpenFiles               metricElasticsearchNodeOpenFiles
-	metricElasticsearchNodeOperationsCompleted     metricElasticsearchNodeOperationsCompleted
-	metricElasticsearchNodeOperationsTime          metricElasticsearchNodeOperationsTime
-	metricElasticsearchNodeShardsSize              metricElasticsearchNodeShardsSize
-	metricElasticsearchNodeThreadPoolTasksFinished metricElasticsearchNodeThreadPoolTasksFinished
-	metricElasticsearchNodeThreadPoolTasksQueued   metricElasticsearchNodeThreadPoolTasksQueued
-	metricElasticsearchNodeThreadPoolThreads       metricElasticsearchNodeThreadPoolThreads
-	metricJvmClassesLoaded                         metricJvmClassesLoaded
-	metricJvmGcCollectionsCount                    metricJvmGcCollectionsCount
-	metricJvmGcCollectionsElapsed                  metricJvmGcCollectionsElapsed
-	metricJvmMemoryHeapCommitted                   metricJvmMemoryHeapCommitted
-	metricJvmMemoryHeapMax                         metricJvmMemoryHeapMax
-	metricJvmMemoryHeapUsed                        metricJvmMemoryHeapUsed
-	metricJvmMemoryNonheapCommitted                metricJvmMemoryNonheapCommitted
-	metricJvmMemoryNonheapUsed                     metricJvmMemoryNonheapUsed
-	metricJvmMemoryPoolMax                         metricJvmMemoryPoolMax
-	metricJvmMemoryPoolUsed                        metricJvmMemoryPoolUsed
-	metricJvmThreadsCount                          metricJvmThreadsCount
+	startTime                                               pdata.Timestamp
+	instrumentationLibraryName                              string
+	metricElasticsearchClusterDataNodes                     metricElasticsearchClusterDataNodes
+	metricElasticsearchClusterHealth                        metricElasticsearchClusterHealth
+	metricElasticsearchClusterIlmIndicesErrors              metricElasticsearchClusterIlmIndicesErrors
+	metricElasticsearchClusterIlmStatus                     metricElasticsearchClusterIlmStatus
+	metricElasticsearchClusterMlJobState                    metricElasticsearchClusterMlJobState
+	metricElasticsearchClusterNodes                         metricElasticsearchClusterNodes
+	metricElasticsearchClusterNodesJvmMemoryHeapMax         metricElasticsearchClusterNodesJvmMemoryHeapMax
+	metricElasticsearchClusterNodesJvmMemoryHeapUsed        metricElasticsearchClusterNodesJvmMemoryHeapUsed
+	metricElasticsearchClusterNodesJvmMemoryHeapUtilization metricElasticsearchClusterNodesJvmMemoryHeapUtilization
+	metricElasticsearchClusterNodesOperationsRate           metricElasticsearchClusterNodesOperationsRate
+	metricElasticsearchClusterPendingTasks                  metricElasticsearchClusterPendingTasks
+	metricElasticsearchClusterPendingTasksMaxAge            metricElasticsearchClusterPendingTasksMaxAge
+	metricElasticsearchClusterShards                        metricElasticsearchClusterShards
+	metricElasticsearchClusterTransformFailures             metricElasticsearchClusterTransformFailures
+	metricElasticsearchClusterTransformState                metricElasticsearchClusterTransformState
+	metricElasticsearchNodeCacheCount                       metricElasticsearchNodeCacheCount
+	metricElasticsearchNodeCacheEntries                     metricElasticsearchNodeCacheEntries
+	metricElasticsearchNodeCacheEvictions                   metricElasticsearchNodeCacheEvictions
+	metricElasticsearchNodeCacheMemoryUsage                 metricElasticsearchNodeCacheMemoryUsage
+	metricElasticsearchNodeClusterConnections               metricElasticsearchNodeClusterConnections
+	metricElasticsearchNodeClusterIo                        metricElasticsearchNodeClusterIo
+	metricElasticsearchNodeDocuments                        metricElasticsearchNodeDocuments
+	metricElasticsearchNodeFsDiskAvailable                  metricElasticsearchNodeFsDiskAvailable
+	metricElasticsearchNodeHTTPConnections                  metricElasticsearchNodeHTTPConnections
+	metricElasticsearchNodeOpenFiles                        metricElasticsearchNodeOpenFiles
+	metricElasticsearchNodeOperationsCompleted              metricElasticsearchNodeOperationsCompleted
+	metricElasticsearchNodeOperationsTime                   metricElasticsearchNodeOperationsTime
+	metricElasticsearchNodeShardsSize                       metricElasticsearchNodeShardsSize
+	metricElasticsearchNodeThreadPoolTasksFinished          metricElasticsearchNodeThreadPoolTasksFinished
+	metricElasticsearchNodeThreadPoolTasksQueued            metricElasticsearchNodeThreadPoolTasksQueued
+	metricElasticsearchNodeThreadPoolThreads                metricElasticsearchNodeThreadPoolThreads
+	metricElasticsearchShardDocuments                       metricElasticsearchShardDocuments
+	metricElasticsearchShardStoreSize                       metricElasticsearchShardStoreSize
+	metricJvmClassesLoaded                                  metricJvmClassesLoaded
+	metricJvmGcCollectionsCount                             metricJvmGcCollectionsCount
+	metricJvmGcCollectionsElapsed                           metricJvmGcCollectionsElapsed
+	metricJvmMemoryHeapCommitted                            metricJvmMemoryHeapCommitted
+	metricJvmMemoryHeapMax                                  metricJvmMemoryHeapMax
+	metricJvmMemoryHeapUsed                                 metricJvmMemoryHeapUsed
+	metricJvmMemoryNonheapCommitted                         metricJvmMemoryNonheapCommitted
+	metricJvmMemoryNonheapUsed                              metricJvmMemoryNonheapUsed
+	metricJvmMemoryPoolMax                                  metricJvmMemoryPoolMax
+	metricJvmMemoryPoolPeakUsed                             metricJvmMemoryPoolPeakUsed
+	metricJvmMemoryPoolUsed                                 metricJvmMemoryPoolUsed
+	metricJvmThreadsCount                                   metricJvmThreadsCount
 }
 
 // metricBuilderOption applies changes to default metrics builder.
@@ -1676,38 +2593,63 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/elasticsearchreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		startTime:                                      pdata.NewTimestampFromTime(time.Now()),
-		metricElasticsearchClusterDataNodes:            newMetricElasticsearchClusterDataNodes(settings.ElasticsearchClusterDataNodes),
-		metricElasticsearchClusterHealth:               newMetricElasticsearchClusterHealth(settings.ElasticsearchClusterHealth),
-		metricElasticsearchClusterNodes:                newMetricElasticsearchClusterNodes(settings.ElasticsearchClusterNodes),
-		metricElasticsearchClusterShards:               newMetricElasticsearchClusterShards(settings.ElasticsearchClusterShards),
-		metricElasticsearchNodeCacheEvictions:          newMetricElasticsearchNodeCacheEvictions(settings.ElasticsearchNodeCacheEvictions),
-		metricElasticsearchNodeCacheMemoryUsage:        newMetricElasticsearchNodeCacheMemoryUsage(settings.ElasticsearchNodeCacheMemoryUsage),
-		metricElasticsearchNodeClusterConnections:      newMetricElasticsearchNodeClusterConnections(settings.ElasticsearchNodeClusterConnections),
-		metricElasticsearchNodeClusterIo:               newMetricElasticsearchNodeClusterIo(settings.ElasticsearchNodeClusterIo),
-		metricElasticsearchNodeDocuments:               newMetricElasticsearchNodeDocuments(settings.ElasticsearchNodeDocuments),
-		metricElasticsearchNodeFsDiskAvailable:         newMetricElasticsearchNodeFsDiskAvailable(settings.ElasticsearchNodeFsDiskAvailable),
-		metricElasticsearchNodeHTTPConnections:         newMetricElasticsearchNodeHTTPConnections(settings.ElasticsearchNodeHTTPConnections),
-		metricElasticsearchNodeOpenFiles:               newMetricElasticsearchNodeOpenFiles(settings.ElasticsearchNodeOpenFiles),
-		metricElasticsearchNodeOperationsCompleted:     newMetricElasticsearchNodeOperationsCompleted(settings.ElasticsearchNodeOperationsCompleted),
-		metricElasticsearchNodeOperationsTime:          newMetricElasticsearchNodeOperationsTime(settings.ElasticsearchNodeOperationsTime),
-		metricElasticsearchNodeShardsSize:              newMetricElasticsearchNodeShardsSize(settings.ElasticsearchNodeShardsSize),
-		metricElasticsearchNodeThreadPoolTasksFinished: newMetricElasticsearchNodeThreadPoolTasksFinished(settings.ElasticsearchNodeThreadPoolTasksFinished),
-		metricElasticsearchNodeThreadPoolTasksQueued:   newMetricElasticsearchNodeThreadPoolTasksQueued(settings.ElasticsearchNodeThreadPoolTasksQueued),
-		metricElasticsearchNodeThreadPoolThreads:       newMetricElasticsearchNodeThreadPoolThreads(settings.ElasticsearchNodeThreadPoolThreads),
-		metricJvmClassesLoaded:                         newMetricJvmClassesLoaded(settings.JvmClassesLoaded),
-		metricJvmGcCollectionsCount:                    newMetricJvmGcCollectionsCount(settings.JvmGcCollectionsCount),
-		metricJvmGcCollectionsElapsed:                  newMetricJvmGcCollectionsElapsed(settings.JvmGcCollectionsElapsed),
-		metricJvmMemoryHeapCommitted:                   newMetricJvmMemoryHeapCommitted(settings.JvmMemoryHeapCommitted),
-		metricJvmMemoryHeapMax:                         newMetricJvmMemoryHeapMax(settings.JvmMemoryHeapMax),
-		metricJvmMemoryHeapUsed:                        newMetricJvmMemoryHeapUsed(settings.JvmMemoryHeapUsed),
-		metricJvmMemoryNonheapCommitted:                newMetricJvmMemoryNonheapCommitted(settings.JvmMemoryNonheapCommitted),
-		metricJvmMemoryNonheapUsed:                     newMetricJvmMemoryNonheapUsed(settings.JvmMemoryNonheapUsed),
-		metricJvmMemoryPoolMax:                         newMetricJvmMemoryPoolMax(settings.JvmMemoryPoolMax),
-		metricJvmMemoryPoolUsed:                        newMetricJvmMemoryPoolUsed(settings.JvmMemoryPoolUsed),
-		metricJvmThreadsCount:                          newMetricJvmThreadsCount(settings.JvmThreadsCount),
+		startTime:                                               pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:                              "otelcol/elasticsearchreceiver",
+		metricElasticsearchClusterDataNodes:                     newMetricElasticsearchClusterDataNodes(settings.ElasticsearchClusterDataNodes),
+		metricElasticsearchClusterHealth:                        newMetricElasticsearchClusterHealth(settings.ElasticsearchClusterHealth),
+		metricElasticsearchClusterIlmIndicesErrors:              newMetricElasticsearchClusterIlmIndicesErrors(settings.ElasticsearchClusterIlmIndicesErrors),
+		metricElasticsearchClusterIlmStatus:                     newMetricElasticsearchClusterIlmStatus(settings.ElasticsearchClusterIlmStatus),
+		metricElasticsearchClusterMlJobState:                    newMetricElasticsearchClusterMlJobState(settings.ElasticsearchClusterMlJobState),
+		metricElasticsearchClusterNodes:                         newMetricElasticsearchClusterNodes(settings.ElasticsearchClusterNodes),
+		metricElasticsearchClusterNodesJvmMemoryHeapMax:         newMetricElasticsearchClusterNodesJvmMemoryHeapMax(settings.ElasticsearchClusterNodesJvmMemoryHeapMax),
+		metricElasticsearchClusterNodesJvmMemoryHeapUsed:        newMetricElasticsearchClusterNodesJvmMemoryHeapUsed(settings.ElasticsearchClusterNodesJvmMemoryHeapUsed),
+		metricElasticsearchClusterNodesJvmMemoryHeapUtilization: newMetricElasticsearchClusterNodesJvmMemoryHeapUtilization(settings.ElasticsearchClusterNodesJvmMemoryHeapUtilization),
+		metricElasticsearchClusterNodesOperationsRate:           newMetricElasticsearchClusterNodesOperationsRate(settings.ElasticsearchClusterNodesOperationsRate),
+		metricElasticsearchClusterPendingTasks:                  newMetricElasticsearchClusterPendingTasks(settings.ElasticsearchClusterPendingTasks),
+		metricElasticsearchClusterPendingTasksMaxAge:            newMetricElasticsearchClusterPendingTasksMaxAge(settings.ElasticsearchClusterPendingTasksMaxAge),
+		metricElasticsearchClusterShards:                        newMetricElasticsearchClusterShards(settings.ElasticsearchClusterShards),
+		metricElasticsearchClusterTransformFailures:             newMetricElasticsearchClusterTransformFailures(settings.ElasticsearchClusterTransformFailures),
+		metricElasticsearchClusterTransformState:                newMetricElasticsearchClusterTransformState(settings.ElasticsearchClusterTransformState),
+		metricElasticsearchNodeCacheCount:                       newMetricElasticsearchNodeCacheCount(settings.ElasticsearchNodeCacheCount),
+		metricElasticsearchNodeCacheEntries:                     newMetricElasticsearchNodeCacheEntries(settings.ElasticsearchNodeCacheEntries),
+		metricElasticsearchNodeCacheEvictions:                   newMetricElasticsearchNodeCacheEvictions(settings.ElasticsearchNodeCacheEvictions),
+		metricElasticsearchNodeCacheMemoryUsage:                 newMetricElasticsearchNodeCacheMemoryUsage(settings.ElasticsearchNodeCacheMemoryUsage),
+		metricElasticsearchNodeClusterConnections:               newMetricElasticsearchNodeClusterConnections(settings.ElasticsearchNodeClusterConnections),
+		metricElasticsearchNodeClusterIo:                        newMetricElasticsearchNodeClusterIo(settings.ElasticsearchNodeClusterIo),
+		metricElasticsearchNodeDocuments:                        newMetricElasticsearchNodeDocuments(settings.ElasticsearchNodeDocuments),
+		metricElasticsearchNodeFsDiskAvailable:                  newMetricElasticsearchNodeFsDiskAvailable(settings.ElasticsearchNodeFsDiskAvailable),
+		metricElasticsearchNodeHTTPConnections:                  newMetricElasticsearchNodeHTTPConnections(settings.ElasticsearchNodeHTTPConnections),
+		metricElasticsearchNodeOpenFiles:                        newMetricElasticsearchNodeOpenFiles(settings.ElasticsearchNodeOpenFiles),
+		metricElasticsearchNodeOperationsCompleted:              newMetricElasticsearchNodeOperationsCompleted(settings.ElasticsearchNodeOperationsCompleted),
+		metricElasticsearchNodeOperationsTime:                   newMetricElasticsearchNodeOperationsTime(settings.ElasticsearchNodeOperationsTime),
+		metricElasticsearchNodeShardsSize:                       newMetricElasticsearchNodeShardsSize(settings.ElasticsearchNodeShardsSize),
+		metricElasticsearchNodeThreadPoolTasksFinished:          newMetricElasticsearchNodeThreadPoolTasksFinished(settings.ElasticsearchNodeThreadPoolTasksFinished),
+		metricElasticsearchNodeThreadPoolTasksQueued:            newMetricElasticsearchNodeThreadPoolTasksQueued(settings.ElasticsearchNodeThreadPoolTasksQueued),
+		metricElasticsearchNodeThreadPoolThreads:                newMetricElasticsearchNodeThreadPoolThreads(settings.ElasticsearchNodeThreadPoolThreads),
+		metricElasticsearchShardDocuments:                       newMetricElasticsearchShardDocuments(settings.ElasticsearchShardDocuments),
+		metricElasticsearchShardStoreSize:                       newMetricElasticsearchShardStoreSize(settings.ElasticsearchShardStoreSize),
+		metricJvmClassesLoaded:                                  newMetricJvmClassesLoaded(settings.JvmClassesLoaded),
+		metricJvmGcCollectionsCount:                             newMetricJvmGcCollectionsCount(settings.JvmGcCollectionsCount),
+		metricJvmGcCollectionsElapsed:                           newMetricJvmGcCollectionsElapsed(settings.JvmGcCollectionsElapsed),
+		metricJvmMemoryHeapCommitted:                            newMetricJvmMemoryHeapCommitted(settings.JvmMemoryHeapCommitted),
+		metricJvmMemoryHeapMax:                                  newMetricJvmMemoryHeapMax(settings.JvmMemoryHeapMax),
+		metricJvmMemoryHeapUsed:                                 newMetricJvmMemoryHeapUsed(settings.JvmMemoryHeapUsed),
+		metricJvmMemoryNonheapCommitted:                         newMetricJvmMemoryNonheapCommitted(settings.JvmMemoryNonheapCommitted),
+		metricJvmMemoryNonheapUsed:                              newMetricJvmMemoryNonheapUsed(settings.JvmMemoryNonheapUsed),
+		metricJvmMemoryPoolMax:                                  newMetricJvmMemoryPoolMax(settings.JvmMemoryPoolMax),
+		metricJvmMemoryPoolPeakUsed:                             newMetricJvmMemoryPoolPeakUsed(settings.JvmMemoryPoolPeakUsed),
+		metricJvmMemoryPoolUsed:                                 newMetricJvmMemoryPoolUsed(settings.JvmMemoryPoolUsed),
+		metricJvmThreadsCount:                                   newMetricJvmThreadsCount(settings.JvmThreadsCount),
 	}
 	for _, op := range options {
 		op(mb)
@@ -1721,8 +2663,21 @@ func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption)
 func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricElasticsearchClusterDataNodes.emit(metrics)
 	mb.metricElasticsearchClusterHealth.emit(metrics)
+	mb.metricElasticsearchClusterIlmIndicesErrors.emit(metrics)
+	mb.metricElasticsearchClusterIlmStatus.emit(metrics)
+	mb.metricElasticsearchClusterMlJobState.emit(metrics)
 	mb.metricElasticsearchClusterNodes.emit(metrics)
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapMax.emit(metrics)
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapUsed.emit(metrics)
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapUtilization.emit(metrics)
+	mb.metricElasticsearchClusterNodesOperationsRate.emit(metrics)
+	mb.metricElasticsearchClusterPendingTasks.emit(metrics)
+	mb.metricElasticsearchClusterPendingTasksMaxAge.emit(metrics)
 	mb.metricElasticsearchClusterShards.emit(metrics)
+	mb.metricElasticsearchClusterTransformFailures.emit(metrics)
+	mb.metricElasticsearchClusterTransformState.emit(metrics)
+	mb.metricElasticsearchNodeCacheCount.emit(metrics)
+	mb.metricElasticsearchNodeCacheEntries.emit(metrics)
 	mb.metricElasticsearchNodeCacheEvictions.emit(metrics)
 	mb.metricElasticsearchNodeCacheMemoryUsage.emit(metrics)
 	mb.metricElasticsearchNodeClusterConnections.emit(metrics)
@@ -1737,6 +2692,8 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricElasticsearchNodeThreadPoolTasksFinished.emit(metrics)
 	mb.metricElasticsearchNodeThreadPoolTasksQueued.emit(metrics)
 	mb.metricElasticsearchNodeThreadPoolThreads.emit(metrics)
+	mb.metricElasticsearchShardDocuments.emit(metrics)
+	mb.metricElasticsearchShardStoreSize.emit(metrics)
 	mb.metricJvmClassesLoaded.emit(metrics)
 	mb.metricJvmGcCollectionsCount.emit(metrics)
 	mb.metricJvmGcCollectionsElapsed.emit(metrics)
@@ -1746,10 +2703,45 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricJvmMemoryNonheapCommitted.emit(metrics)
 	mb.metricJvmMemoryNonheapUsed.emit(metrics)
 	mb.metricJvmMemoryPoolMax.emit(metrics)
+	mb.metricJvmMemoryPoolPeakUsed.emit(metrics)
 	mb.metricJvmMemoryPoolUsed.emit(metrics)
 	mb.metricJvmThreadsCount.emit(metrics)
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// WithElasticsearchClusterName sets provided value as "elasticsearch.cluster.name" attribute for current resource.
+func WithElasticsearchClusterName(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("elasticsearch.cluster.name", val)
+	}
+}
+
+// WithElasticsearchNodeName sets provided value as "elasticsearch.node.name" attribute for current resource.
+func WithElasticsearchNodeName(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("elasticsearch.node.name", val)
+	}
+}
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // RecordElasticsearchClusterDataNodesDataPoint adds a data point to elasticsearch.cluster.data_nodes metric.
 func (mb *MetricsBuilder) RecordElasticsearchClusterDataNodesDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricElasticsearchClusterDataNodes.recordDataPoint(mb.startTime, ts, val)
@@ -1760,16 +2752,81 @@ func (mb *MetricsBuilder) RecordElasticsearchClusterHealthDataPoint(ts pdata.Tim
 	mb.metricElasticsearchClusterHealth.recordDataPoint(mb.startTime, ts, val, healthStatusAttributeValue)
 }
 
+// RecordElasticsearchClusterIlmIndicesErrorsDataPoint adds a data point to elasticsearch.cluster.ilm.indices.errors metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterIlmIndicesErrorsDataPoint(ts pdata.Timestamp, val int64, ilmPolicyAttributeValue string) {
+	mb.metricElasticsearchClusterIlmIndicesErrors.recordDataPoint(mb.startTime, ts, val, ilmPolicyAttributeValue)
+}
+
+// RecordElasticsearchClusterIlmStatusDataPoint adds a data point to elasticsearch.cluster.ilm.status metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterIlmStatusDataPoint(ts pdata.Timestamp, val int64, ilmStatusAttributeValue string) {
+	mb.metricElasticsearchClusterIlmStatus.recordDataPoint(mb.startTime, ts, val, ilmStatusAttributeValue)
+}
+
+// RecordElasticsearchClusterMlJobStateDataPoint adds a data point to elasticsearch.cluster.ml.job.state metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterMlJobStateDataPoint(ts pdata.Timestamp, val int64, mlJobIDAttributeValue string, mlJobStateAttributeValue string) {
+	mb.metricElasticsearchClusterMlJobState.recordDataPoint(mb.startTime, ts, val, mlJobIDAttributeValue, mlJobStateAttributeValue)
+}
+
 // RecordElasticsearchClusterNodesDataPoint adds a data point to elasticsearch.cluster.nodes metric.
 func (mb *MetricsBuilder) RecordElasticsearchClusterNodesDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricElasticsearchClusterNodes.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordElasticsearchClusterNodesJvmMemoryHeapMaxDataPoint adds a data point to elasticsearch.cluster.nodes.jvm.memory.heap.max metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterNodesJvmMemoryHeapMaxDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapMax.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordElasticsearchClusterNodesJvmMemoryHeapUsedDataPoint adds a data point to elasticsearch.cluster.nodes.jvm.memory.heap.used metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterNodesJvmMemoryHeapUsedDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapUsed.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordElasticsearchClusterNodesJvmMemoryHeapUtilizationDataPoint adds a data point to elasticsearch.cluster.nodes.jvm.memory.heap.utilization metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterNodesJvmMemoryHeapUtilizationDataPoint(ts pdata.Timestamp, val float64) {
+	mb.metricElasticsearchClusterNodesJvmMemoryHeapUtilization.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordElasticsearchClusterNodesOperationsRateDataPoint adds a data point to elasticsearch.cluster.nodes.operations.rate metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterNodesOperationsRateDataPoint(ts pdata.Timestamp, val float64, operationAttributeValue string) {
+	mb.metricElasticsearchClusterNodesOperationsRate.recordDataPoint(mb.startTime, ts, val, operationAttributeValue)
+}
+
+// RecordElasticsearchClusterPendingTasksDataPoint adds a data point to elasticsearch.cluster.pending_tasks metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterPendingTasksDataPoint(ts pdata.Timestamp, val int64, taskPriorityAttributeValue string) {
+	mb.metricElasticsearchClusterPendingTasks.recordDataPoint(mb.startTime, ts, val, taskPriorityAttributeValue)
+}
+
+// RecordElasticsearchClusterPendingTasksMaxAgeDataPoint adds a data point to elasticsearch.cluster.pending_tasks.max_age metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterPendingTasksMaxAgeDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricElasticsearchClusterPendingTasksMaxAge.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordElasticsearchClusterShardsDataPoint adds a data point to elasticsearch.cluster.shards metric.
 func (mb *MetricsBuilder) RecordElasticsearchClusterShardsDataPoint(ts pdata.Timestamp, val int64, shardStateAttributeValue string) {
 	mb.metricElasticsearchClusterShards.recordDataPoint(mb.startTime, ts, val, shardStateAttributeValue)
 }
 
+// RecordElasticsearchClusterTransformFailuresDataPoint adds a data point to elasticsearch.cluster.transform.failures metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterTransformFailuresDataPoint(ts pdata.Timestamp, val int64, transformIDAttributeValue string, transformFailureTypeAttributeValue string) {
+	mb.metricElasticsearchClusterTransformFailures.recordDataPoint(mb.startTime, ts, val, transformIDAttributeValue, transformFailureTypeAttributeValue)
+}
+
+// RecordElasticsearchClusterTransformStateDataPoint adds a data point to elasticsearch.cluster.transform.state metric.
+func (mb *MetricsBuilder) RecordElasticsearchClusterTransformStateDataPoint(ts pdata.Timestamp, val int64, transformIDAttributeValue string, transformStateAttributeValue string) {
+	mb.metricElasticsearchClusterTransformState.recordDataPoint(mb.startTime, ts, val, transformIDAttributeValue, transformStateAttributeValue)
+}
+
+// RecordElasticsearchNodeCacheCountDataPoint adds a data point to elasticsearch.node.cache.count metric.
+func (mb *MetricsBuilder) RecordElasticsearchNodeCacheCountDataPoint(ts pdata.Timestamp, val int64, queryCacheCountTypeAttributeValue string) {
+	mb.metricElasticsearchNodeCacheCount.recordDataPoint(mb.startTime, ts, val, queryCacheCountTypeAttributeValue)
+}
+
+// RecordElasticsearchNodeCacheEntriesDataPoint adds a data point to elasticsearch.node.cache.entries metric.
+func (mb *MetricsBuilder) RecordElasticsearchNodeCacheEntriesDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricElasticsearchNodeCacheEntries.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordElasticsearchNodeCacheEvictionsDataPoint adds a data point to elasticsearch.node.cache.evictions metric.
 func (mb *MetricsBuilder) RecordElasticsearchNodeCacheEvictionsDataPoint(ts pdata.Timestamp, val int64, cacheNameAttributeValue string) {
 	mb.metricElasticsearchNodeCacheEvictions.recordDataPoint(mb.startTime, ts, val, cacheNameAttributeValue)
@@ -1840,6 +2897,16 @@ func (mb *MetricsBuilder) RecordElasticsearchNodeThreadPoolThreadsDataPoint(ts p
 	mb.metricElasticsearchNodeThreadPoolThreads.recordDataPoint(mb.startTime, ts, val, threadPoolNameAttributeValue, threadStateAttributeValue)
 }
 
+// RecordElasticsearchShardDocumentsDataPoint adds a data point to elasticsearch.shard.documents metric.
+func (mb *MetricsBuilder) RecordElasticsearchShardDocumentsDataPoint(ts pdata.Timestamp, val int64, indexNameAttributeValue string, shardIDAttributeValue string, shardNodeAttributeValue string, shardTypeAttributeValue string, documentStateAttributeValue string) {
+	mb.metricElasticsearchShardDocuments.recordDataPoint(mb.startTime, ts, val, indexNameAttributeValue, shardIDAttributeValue, shardNodeAttributeValue, shardTypeAttributeValue, documentStateAttributeValue)
+}
+
+// RecordElasticsearchShardStoreSizeDataPoint adds a data point to elasticsearch.shard.store.size metric.
+func (mb *MetricsBuilder) RecordElasticsearchShardStoreSizeDataPoint(ts pdata.Timestamp, val int64, indexNameAttributeValue string, shardIDAttributeValue string, shardNodeAttributeValue string, shardTypeAttributeValue string) {
+	mb.metricElasticsearchShardStoreSize.recordDataPoint(mb.startTime, ts, val, indexNameAttributeValue, shardIDAttributeValue, shardNodeAttributeValue, shardTypeAttributeValue)
+}
+
 // RecordJvmClassesLoadedDataPoint adds a data point to jvm.classes.loaded metric.
 func (mb *MetricsBuilder) RecordJvmClassesLoadedDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricJvmClassesLoaded.recordDataPoint(mb.startTime, ts, val)
@@ -1885,6 +2952,11 @@ func (mb *MetricsBuilder) RecordJvmMemoryPoolMaxDataPoint(ts pdata.Timestamp, va
 	mb.metricJvmMemoryPoolMax.recordDataPoint(mb.startTime, ts, val, memoryPoolNameAttributeValue)
 }
 
+// RecordJvmMemoryPoolPeakUsedDataPoint adds a data point to jvm.memory.pool.peak_used metric.
+func (mb *MetricsBuilder) RecordJvmMemoryPoolPeakUsedDataPoint(ts pdata.Timestamp, val int64, memoryPoolNameAttributeValue string) {
+	mb.metricJvmMemoryPoolPeakUsed.recordDataPoint(mb.startTime, ts, val, memoryPoolNameAttributeValue)
+}
+
 // RecordJvmMemoryPoolUsedDataPoint adds a data point to jvm.memory.pool.used metric.
 func (mb *MetricsBuilder) RecordJvmMemoryPoolUsedDataPoint(ts pdata.Timestamp, val int64, memoryPoolNameAttributeValue string) {
 	mb.metricJvmMemoryPoolUsed.recordDataPoint(mb.startTime, ts, val, memoryPoolNameAttributeValue)
@@ -1916,42 +2988,75 @@ var Attributes = struct {
 	DiskUsageState string
 	// DocumentState (The state of the document.)
 	DocumentState string
-	// ElasticsearchClusterName (The name of the elasticsearch cluster.)
-	ElasticsearchClusterName string
-	// ElasticsearchNodeName (The name of the elasticsearch node.)
-	ElasticsearchNodeName string
 	// FsDirection (The direction of filesystem IO.)
 	FsDirection string
 	// HealthStatus (The health status of the cluster.)
 	HealthStatus string
+	// IlmPolicy (The name of the index lifecycle management policy.)
+	IlmPolicy string
+	// IlmStatus (The operation mode of index lifecycle management.)
+	IlmStatus string
+	// IndexName (The name of the index.)
+	IndexName string
 	// MemoryPoolName (The name of the JVM memory pool.)
 	MemoryPoolName string
+	// MlJobID (The ID of the machine learning job.)
+	MlJobID string
+	// MlJobState (The state of the machine learning job.)
+	MlJobState string
 	// Operation (The type of operation.)
 	Operation string
+	// QueryCacheCountType (Type of query cache count.)
+	QueryCacheCountType string
+	// ShardID (The number of the shard within its index.)
+	ShardID string
+	// ShardNode (The ID of the node the shard copy is allocated to.)
+	ShardNode string
 	// ShardState (The state of the shard.)
 	ShardState string
+	// ShardType (Whether the shard copy is the primary or a replica.)
+	ShardType string
+	// TaskPriority (The priority of the pending cluster state update task.)
+	TaskPriority string
 	// TaskState (The state of the task.)
 	TaskState string
 	// ThreadPoolName (The name of the thread pool.)
 	ThreadPoolName string
 	// ThreadState (The state of the thread.)
 	ThreadState string
+	// TransformFailureType (The type of operation which failed.)
+	TransformFailureType string
+	// TransformID (The ID of the transform.)
+	TransformID string
+	// TransformState (The state of the transform.)
+	TransformState string
 }{
 	"cache_name",
 	"name",
 	"direction",
 	"state",
 	"state",
-	"elasticsearch.cluster.name",
-	"elasticsearch.node.name",
 	"direction",
 	"status",
+	"policy",
+	"status",
+	"index",
 	"name",
+	"job",
+	"state",
 	"operation",
+	"type",
+	"shard",
+	"node",
 	"state",
+	"type",
+	"priority",
 	"state",
 	"thread_pool_name",
 	"state",
+	"type",
+	"transform",
+	"state",
 }
 
 // A is an alias for Attributes.
@@ -2013,6 +3118,32 @@ var AttributeHealthStatus = struct {
 	"red",
 }
 
+// AttributeIlmStatus are the possible values that the attribute "ilm_status" can have.
+var AttributeIlmStatus = struct {
+	Running  string
+	Stopping string
+	Stopped  string
+}{
+	"running",
+	"stopping",
+	"stopped",
+}
+
+// AttributeMlJobState are the possible values that the attribute "ml_job_state" can have.
+var AttributeMlJobState = struct {
+	Opening string
+	Opened  string
+	Closing string
+	Closed  string
+	Failed  string
+}{
+	"opening",
+	"opened",
+	"closing",
+	"closed",
+	"failed",
+}
+
 // AttributeOperation are the possible values that the attribute "operation" can have.
 var AttributeOperation = struct {
 	Index   string
@@ -2040,6 +3171,15 @@ var AttributeOperation = struct {
 	"warmer",
 }
 
+// AttributeQueryCacheCountType are the possible values that the attribute "query_cache_count_type" can have.
+var AttributeQueryCacheCountType = struct {
+	Hit  string
+	Miss string
+}{
+	"hit",
+	"miss",
+}
+
 // AttributeShardState are the possible values that the attribute "shard_state" can have.
 var AttributeShardState = struct {
 	Active       string
@@ -2053,6 +3193,32 @@ var AttributeShardState = struct {
 	"unassigned",
 }
 
+// AttributeShardType are the possible values that the attribute "shard_type" can have.
+var AttributeShardType = struct {
+	Primary string
+	Replica string
+}{
+	"primary",
+	"replica",
+}
+
+// AttributeTaskPriority are the possible values that the attribute "task_priority" can have.
+var AttributeTaskPriority = struct {
+	Immediate string
+	Urgent    string
+	High      string
+	Normal    string
+	Low       string
+	Languid   string
+}{
+	"immediate",
+	"urgent",
+	"high",
+	"normal",
+	"low",
+	"languid",
+}
+
 // AttributeTaskState are the possible values that the attribute "task_state" can have.
 var AttributeTaskState = struct {
 	Rejected  string
@@ -2070,3 +3236,29 @@ var AttributeThreadState = struct {
 	"active",
 	"idle",
 }
+
+// AttributeTransformFailureType are the possible values that the attribute "transform_failure_type" can have.
+var AttributeTransformFailureType = struct {
+	Index  string
+	Search string
+}{
+	"index",
+	"search",
+}
+
+// AttributeTransformState are the possible values that the attribute "transform_state" can have.
+var AttributeTransformState = struct {
+	Started  string
+	Indexing string
+	Aborting string
+	Stopping string
+	Stopped  string
+	Failed   string
+}{
+	"started",
+	"indexing",
+	"aborting",
+	"stopping",
+	"stopped",
+	"failed",
+}
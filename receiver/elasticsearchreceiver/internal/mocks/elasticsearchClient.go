@@ -15,6 +15,75 @@ type MockElasticsearchClient struct {
 	mock.Mock
 }
 
+// CatHealth provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) CatHealth(ctx context.Context) (model.CatHealth, error) {
+	ret := _m.Called(ctx)
+
+	var r0 model.CatHealth
+	if rf, ok := ret.Get(0).(func(context.Context) model.CatHealth); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(model.CatHealth)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CatNodes provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) CatNodes(ctx context.Context) (model.CatNodes, error) {
+	ret := _m.Called(ctx)
+
+	var r0 model.CatNodes
+	if rf, ok := ret.Get(0).(func(context.Context) model.CatNodes); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(model.CatNodes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CatShards provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) CatShards(ctx context.Context) (model.CatShards, error) {
+	ret := _m.Called(ctx)
+
+	var r0 model.CatShards
+	if rf, ok := ret.Get(0).(func(context.Context) model.CatShards); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(model.CatShards)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ClusterHealth provides a mock function with given fields: ctx
 func (_m *MockElasticsearchClient) ClusterHealth(ctx context.Context) (*model.ClusterHealth, error) {
 	ret := _m.Called(ctx)
@@ -38,6 +107,144 @@ func (_m *MockElasticsearchClient) ClusterHealth(ctx context.Context) (*model.Cl
 	return r0, r1
 }
 
+// DataStreams provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) DataStreams(ctx context.Context) (*model.DataStreams, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.DataStreams
+	if rf, ok := ret.Get(0).(func(context.Context) *model.DataStreams); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.DataStreams)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ILMExplain provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) ILMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.ILMExplain
+	if rf, ok := ret.Get(0).(func(context.Context) *model.ILMExplain); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ILMExplain)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ILMStatus provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) ILMStatus(ctx context.Context) (*model.ILMStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.ILMStatus
+	if rf, ok := ret.Get(0).(func(context.Context) *model.ILMStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ILMStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IndexAliases provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) IndexAliases(ctx context.Context) (model.IndexAliases, error) {
+	ret := _m.Called(ctx)
+
+	var r0 model.IndexAliases
+	if rf, ok := ret.Get(0).(func(context.Context) model.IndexAliases); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(model.IndexAliases)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IndexStats provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) IndexStats(ctx context.Context) (*model.IndexStats, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.IndexStats
+	if rf, ok := ret.Get(0).(func(context.Context) *model.IndexStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IndexStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MLJobStats provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) MLJobStats(ctx context.Context) (*model.MLJobStats, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.MLJobStats
+	if rf, ok := ret.Get(0).(func(context.Context) *model.MLJobStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MLJobStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NodeStats provides a mock function with given fields: ctx, nodes
 func (_m *MockElasticsearchClient) NodeStats(ctx context.Context, nodes []string) (*model.NodeStats, error) {
 	ret := _m.Called(ctx, nodes)
@@ -60,3 +267,72 @@ func (_m *MockElasticsearchClient) NodeStats(ctx context.Context, nodes []string
 
 	return r0, r1
 }
+
+// NodesInfo provides a mock function with given fields: ctx, nodes
+func (_m *MockElasticsearchClient) NodesInfo(ctx context.Context, nodes []string) (*model.NodesInfo, error) {
+	ret := _m.Called(ctx, nodes)
+
+	var r0 *model.NodesInfo
+	if rf, ok := ret.Get(0).(func(context.Context, []string) *model.NodesInfo); ok {
+		r0 = rf(ctx, nodes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.NodesInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, nodes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PendingTasks provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) PendingTasks(ctx context.Context) (*model.PendingTasks, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.PendingTasks
+	if rf, ok := ret.Get(0).(func(context.Context) *model.PendingTasks); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PendingTasks)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransformStats provides a mock function with given fields: ctx
+func (_m *MockElasticsearchClient) TransformStats(ctx context.Context) (*model.TransformStats, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *model.TransformStats
+	if rf, ok := ret.Get(0).(func(context.Context) *model.TransformStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TransformStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,83 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// The _cat endpoints return their values as strings, requested in bytes and milliseconds. The values Elasticsearch
+// can not compute, e.g. the documents of an unassigned shard, are null and left to 0.
+
+// CatHealth represents a response from elasticsearch's /_cat/health endpoint.
+type CatHealth []CatHealthRow
+
+// CatHealthRow is the health of a cluster in a response from elasticsearch's /_cat/health endpoint.
+type CatHealthRow struct {
+	Cluster            string `json:"cluster"`
+	Status             string `json:"status"`
+	NodeCount          int64  `json:"node.total,string"`
+	DataNodeCount      int64  `json:"node.data,string"`
+	ActiveShards       int64  `json:"shards,string"`
+	RelocatingShards   int64  `json:"relo,string"`
+	InitializingShards int64  `json:"init,string"`
+	UnassignedShards   int64  `json:"unassign,string"`
+}
+
+// CatNodes represents a response from elasticsearch's /_cat/nodes endpoint.
+type CatNodes []CatNode
+
+// CatNode is a node in a response from elasticsearch's /_cat/nodes endpoint.
+// The struct is not exhaustive; It only provides the columns requested by the client.
+type CatNode struct {
+	Name                   string `json:"name"`
+	HeapCurrent            int64  `json:"heap.current,string"`
+	HeapMax                int64  `json:"heap.max,string"`
+	FileDescriptors        int64  `json:"file_desc.current,string"`
+	DiskAvailable          int64  `json:"disk.avail,string"`
+	QueryCacheMemorySize   int64  `json:"query_cache.memory_size,string"`
+	QueryCacheEvictions    int64  `json:"query_cache.evictions,string"`
+	QueryCacheHitCount     int64  `json:"query_cache.hit_count,string"`
+	QueryCacheMissCount    int64  `json:"query_cache.miss_count,string"`
+	FieldDataMemorySize    int64  `json:"fielddata.memory_size,string"`
+	FieldDataEvictions     int64  `json:"fielddata.evictions,string"`
+	IndexingIndexTotal     int64  `json:"indexing.index_total,string"`
+	IndexingIndexTimeInMs  int64  `json:"indexing.index_time,string"`
+	IndexingDeleteTotal    int64  `json:"indexing.delete_total,string"`
+	IndexingDeleteTimeInMs int64  `json:"indexing.delete_time,string"`
+	GetTotal               int64  `json:"get.total,string"`
+	GetTimeInMs            int64  `json:"get.time,string"`
+	SearchQueryTotal       int64  `json:"search.query_total,string"`
+	SearchQueryTimeInMs    int64  `json:"search.query_time,string"`
+	SearchFetchTotal       int64  `json:"search.fetch_total,string"`
+	SearchFetchTimeInMs    int64  `json:"search.fetch_time,string"`
+	SearchScrollTotal      int64  `json:"search.scroll_total,string"`
+	SearchScrollTimeInMs   int64  `json:"search.scroll_time,string"`
+	MergesTotal            int64  `json:"merges.total,string"`
+	MergesTotalTimeInMs    int64  `json:"merges.total_time,string"`
+	RefreshTotal           int64  `json:"refresh.total,string"`
+	RefreshTimeInMs        int64  `json:"refresh.time,string"`
+	FlushTotal             int64  `json:"flush.total,string"`
+	FlushTotalTimeInMs     int64  `json:"flush.total_time,string"`
+}
+
+// CatShards represents a response from elasticsearch's /_cat/shards endpoint.
+type CatShards []CatShard
+
+// CatShard is a shard copy in a response from elasticsearch's /_cat/shards endpoint.
+type CatShard struct {
+	Index     string `json:"index"`
+	Shard     string `json:"shard"`
+	PriRep    string `json:"prirep"`
+	Node      string `json:"node"`
+	Documents int64  `json:"docs,string"`
+	StoreSize int64  `json:"store,string"`
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// ILMStatus represents a response from elasticsearch's /_ilm/status endpoint.
+type ILMStatus struct {
+	OperationMode string `json:"operation_mode"`
+}
+
+// ILMExplain represents a response from elasticsearch's /<index>/_ilm/explain endpoint.
+// The struct is not exhaustive; It does not provide all values returned by elasticsearch,
+// only the ones relevant to the metrics retrieved by the scraper.
+type ILMExplain struct {
+	Indices map[string]ILMIndexExplain `json:"indices"`
+}
+
+// ILMIndexExplain represents the current lifecycle step of a single index.
+type ILMIndexExplain struct {
+	Index  string `json:"index"`
+	Policy string `json:"policy"`
+	Step   string `json:"step"`
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// IndexStats represents a response from elasticsearch's /_stats?level=shards endpoint.
+// The struct is not exhaustive; It does not provide all values returned by elasticsearch,
+// only the ones relevant to the metrics retrieved by the scraper.
+type IndexStats struct {
+	Indices map[string]IndexStatsIndexInfo `json:"indices"`
+}
+
+// IndexStatsIndexInfo holds the stats of every copy of the shards of an index, keyed by shard number.
+type IndexStatsIndexInfo struct {
+	Shards map[string][]ShardStats `json:"shards"`
+}
+
+// ShardStats represents the stats of a single shard copy.
+type ShardStats struct {
+	Routing       ShardRouting  `json:"routing"`
+	DocumentStats DocumentStats `json:"docs"`
+	StoreInfo     StoreInfo     `json:"store"`
+}
+
+// ShardRouting represents where a shard copy is allocated.
+type ShardRouting struct {
+	Node    string `json:"node"`
+	Primary bool   `json:"primary"`
+}
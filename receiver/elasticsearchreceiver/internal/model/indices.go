@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// IndexAliases represents a response from elasticsearch's /_alias endpoint, keyed by index name.
+type IndexAliases map[string]IndexAliasesInfo
+
+// IndexAliasesInfo holds the aliases of an index, keyed by alias name.
+// The definitions of the aliases are not relevant to the scraper.
+type IndexAliasesInfo struct {
+	Aliases map[string]struct{} `json:"aliases"`
+}
+
+// DataStreams represents a response from elasticsearch's /_data_stream endpoint.
+// The struct is not exhaustive; It does not provide all values returned by elasticsearch,
+// only the ones relevant to the metrics retrieved by the scraper.
+type DataStreams struct {
+	DataStreams []DataStream `json:"data_streams"`
+}
+
+// DataStream represents a data stream and its backing indices.
+type DataStream struct {
+	Name    string                `json:"name"`
+	Indices []DataStreamIndexInfo `json:"indices"`
+}
+
+// DataStreamIndexInfo represents a backing index of a data stream.
+type DataStreamIndexInfo struct {
+	IndexName string `json:"index_name"`
+}
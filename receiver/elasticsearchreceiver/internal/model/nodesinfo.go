@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// NodesInfo represents a response from elasticsearch's /_nodes/<node_id> endpoint, filtered down to
+// the fields relevant to the scraper.
+type NodesInfo struct {
+	// Nodes holds the info of the selected nodes, keyed by node ID.
+	Nodes map[string]NodeInfo `json:"nodes"`
+}
+
+// NodeInfo represents the info of a node.
+type NodeInfo struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
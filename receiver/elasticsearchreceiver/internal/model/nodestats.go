@@ -44,7 +44,7 @@ type NodeStatsNodesInfoIndices struct {
 	RefreshOperations  BasicIndexOperation `json:"refresh"`
 	FlushOperations    BasicIndexOperation `json:"flush"`
 	WarmerOperations   BasicIndexOperation `json:"warmer"`
-	QueryCache         BasicCacheInfo      `json:"query_cache"`
+	QueryCache         QueryCacheInfo      `json:"query_cache"`
 	FieldDataCache     BasicCacheInfo      `json:"fielddata"`
 }
 
@@ -90,6 +90,15 @@ type BasicCacheInfo struct {
 	MemorySizeInBy int64 `json:"memory_size_in_bytes"`
 }
 
+type QueryCacheInfo struct {
+	BasicCacheInfo
+	TotalCount int64 `json:"total_count"`
+	HitCount   int64 `json:"hit_count"`
+	MissCount  int64 `json:"miss_count"`
+	CacheSize  int64 `json:"cache_size"`
+	CacheCount int64 `json:"cache_count"`
+}
+
 type JVMInfo struct {
 	UptimeInMs    int64         `json:"uptime_in_millis"`
 	JVMMemoryInfo JVMMemoryInfo `json:"mem"`
@@ -114,8 +123,9 @@ type JVMMemoryPools struct {
 }
 
 type JVMMemoryPoolInfo struct {
-	MemUsedBy int64 `json:"used_in_bytes"`
-	MemMaxBy  int64 `json:"max_in_bytes"`
+	MemUsedBy     int64 `json:"used_in_bytes"`
+	MemMaxBy      int64 `json:"max_in_bytes"`
+	MemPeakUsedBy int64 `json:"peak_used_in_bytes"`
 }
 
 type JVMThreadInfo struct {
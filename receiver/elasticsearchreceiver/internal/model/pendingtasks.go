@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// PendingTasks represents a response from elasticsearch's /_cluster/pending_tasks endpoint.
+type PendingTasks struct {
+	Tasks []PendingTask `json:"tasks"`
+}
+
+// PendingTask represents a cluster state update task waiting to be executed by the elected master.
+// The struct is not exhaustive; It does not provide all values returned by elasticsearch,
+// only the ones relevant to the metrics retrieved by the scraper.
+type PendingTask struct {
+	Priority          string `json:"priority"`
+	TimeInQueueMillis int64  `json:"time_in_queue_millis"`
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+
+// TransformStats represents a response from elasticsearch's /_transform/_all/_stats endpoint.
+// The struct is not exhaustive; It does not provide all values returned by elasticsearch,
+// only the ones relevant to the metrics retrieved by the scraper.
+type TransformStats struct {
+	Transforms []TransformStat `json:"transforms"`
+}
+
+// TransformStat represents the state and indexer stats of a single transform.
+type TransformStat struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	Stats struct {
+		IndexFailures  int64 `json:"index_failures"`
+		SearchFailures int64 `json:"search_failures"`
+	} `json:"stats"`
+}
@@ -0,0 +1,52 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	tagReceiverKey, _ = tag.NewKey("receiver")
+
+	statThrottledScrapes = stats.Int64("elasticsearch_receiver_throttled_scrapes", "Number of scrapes during which Elasticsearch rejected requests with a 429 status code", stats.UnitDimensionless)
+	statSkippedScrapes   = stats.Int64("elasticsearch_receiver_skipped_scrapes", "Number of scrapes skipped to back off after throttled scrapes", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metric views for the Elasticsearch receiver.
+func MetricViews() []*view.View {
+	countThrottledScrapes := &view.View{
+		Name:        statThrottledScrapes.Name(),
+		Measure:     statThrottledScrapes,
+		Description: statThrottledScrapes.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey},
+		Aggregation: view.Sum(),
+	}
+
+	countSkippedScrapes := &view.View{
+		Name:        statSkippedScrapes.Name(),
+		Measure:     statSkippedScrapes,
+		Description: statSkippedScrapes.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey},
+		Aggregation: view.Sum(),
+	}
+
+	return []*view.View{
+		countThrottledScrapes,
+		countSkippedScrapes,
+	}
+}
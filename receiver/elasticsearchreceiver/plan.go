@@ -0,0 +1,113 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+// The numbers of metrics recorded from the responses of the cluster level requests, reported as failed if the
+// request fails.
+const (
+	clusterHealthMetricCount  = 4
+	ilmStatusMetricCount      = 1
+	transformStatsMetricCount = 2
+	mlJobStatsMetricCount     = 1
+	pendingTasksMetricCount   = 2
+	ilmExplainMetricCount     = 1
+	indexStatsMetricCount     = 3
+	catShardsMetricCount      = 2
+)
+
+// requestPlan is the set of requests made by a scrape, resolved from the groups of endpoints which are due and
+// the configuration. The scraper makes the requests of the plan, and the prefetchClient starts the same requests
+// ahead of it. Whether the _cat APIs are used isn't part of the plan, as the scraper may switch to them during
+// the scrape, the requests of the plan are made to the _cat APIs instead when they are.
+type requestPlan struct {
+	// nodeStats requests the node level metrics.
+	nodeStats bool
+	// clusterHealth requests the cluster health, whose cluster name is the resource of the cluster level metrics.
+	clusterHealth bool
+	// clusterHealthMetrics records the cluster level metrics of the cluster health.
+	clusterHealthMetrics bool
+	ilmStatus            bool
+	transformStats       bool
+	mlJobStats           bool
+	pendingTasks         bool
+	// resolveIndices requests the aliases and the data streams resolving the indices option, on which the
+	// ILM explain and index stats requests depend.
+	resolveIndices bool
+	ilmExplain     bool
+	// shardStats requests the shard level metrics.
+	shardStats bool
+}
+
+// newRequestPlan returns the plan of a scrape, where nodeStatsDue, healthDue and indexStatsDue tell which groups
+// of endpoints are due.
+func (r *elasticsearchScraper) newRequestPlan(nodeStatsDue, healthDue, indexStatsDue bool) requestPlan {
+	plan := requestPlan{
+		nodeStats:     nodeStatsDue && len(r.cfg.nodeSelectors()) > 0,
+		clusterHealth: healthDue || indexStatsDue,
+	}
+	if healthDue {
+		plan.clusterHealthMetrics = true
+		plan.ilmStatus = r.cfg.Metrics.ElasticsearchClusterIlmStatus.Enabled
+		plan.transformStats = r.cfg.TransformMetrics
+		plan.mlJobStats = r.cfg.MLJobMetrics
+		plan.pendingTasks = r.cfg.Metrics.ElasticsearchClusterPendingTasks.Enabled || r.cfg.Metrics.ElasticsearchClusterPendingTasksMaxAge.Enabled
+	}
+	if indexStatsDue {
+		plan.ilmExplain = r.cfg.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled
+		plan.shardStats = r.cfg.ShardMetrics
+		plan.resolveIndices = r.indexFilter != nil && (plan.ilmExplain || plan.shardStats)
+	}
+	return plan
+}
+
+// clusterMetrics returns the number of cluster level metrics recorded by the plan, from the _cat APIs if catAPIs.
+func (p requestPlan) clusterMetrics(catAPIs bool) int {
+	count := 0
+	if p.clusterHealthMetrics {
+		count += clusterHealthMetricCount
+	}
+	if catAPIs {
+		if p.shardStats {
+			count += catShardsMetricCount
+		}
+		return count
+	}
+	if p.ilmStatus {
+		count += ilmStatusMetricCount
+	}
+	if p.transformStats {
+		count += transformStatsMetricCount
+	}
+	if p.mlJobStats {
+		count += mlJobStatsMetricCount
+	}
+	if p.pendingTasks {
+		count += pendingTasksMetricCount
+	}
+	return count + p.indexMetrics()
+}
+
+// indexMetrics returns the number of metrics of the selected indices recorded by the plan, which aren't recorded
+// if the indices can't be resolved.
+func (p requestPlan) indexMetrics() int {
+	count := 0
+	if p.ilmExplain {
+		count += ilmExplainMetricCount
+	}
+	if p.shardStats {
+		count += indexStatsMetricCount
+	}
+	return count
+}
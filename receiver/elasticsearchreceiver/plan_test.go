@@ -0,0 +1,88 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewRequestPlan(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.ShardMetrics = true
+	conf.TransformMetrics = true
+	conf.Indices = []string{"logs"}
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	require.Equal(t, requestPlan{
+		nodeStats:            true,
+		clusterHealth:        true,
+		clusterHealthMetrics: true,
+		transformStats:       true,
+		resolveIndices:       true,
+		shardStats:           true,
+	}, sc.newRequestPlan(true, true, true))
+
+	// The cluster health is requested for the resource of the index stats.
+	require.Equal(t, requestPlan{
+		clusterHealth:  true,
+		resolveIndices: true,
+		shardStats:     true,
+	}, sc.newRequestPlan(false, false, true))
+
+	require.Equal(t, requestPlan{
+		clusterHealth:        true,
+		clusterHealthMetrics: true,
+		transformStats:       true,
+	}, sc.newRequestPlan(false, true, false))
+
+	require.Equal(t, requestPlan{}, sc.newRequestPlan(false, false, false))
+}
+
+func TestNewRequestPlanNoIndicesOption(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled = true
+	conf.Nodes = []string{}
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	// The indices aren't resolved without the indices option, and no node is selected.
+	require.Equal(t, requestPlan{
+		clusterHealth: true,
+		ilmExplain:    true,
+	}, sc.newRequestPlan(true, false, true))
+}
+
+func TestRequestPlanClusterMetrics(t *testing.T) {
+	t.Parallel()
+
+	plan := requestPlan{
+		clusterHealth:        true,
+		clusterHealthMetrics: true,
+		ilmStatus:            true,
+		pendingTasks:         true,
+		shardStats:           true,
+	}
+	require.Equal(t, 4+1+2+3, plan.clusterMetrics(false))
+	require.Equal(t, 4+2, plan.clusterMetrics(true))
+	require.Equal(t, 3, plan.indexMetrics())
+
+	require.Equal(t, 0, requestPlan{}.clusterMetrics(false))
+}
@@ -0,0 +1,381 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+// prefetchRequest is the request of a call of the client, identified by key.
+type prefetchRequest struct {
+	key   string
+	fetch func(ctx context.Context) (interface{}, error)
+}
+
+// prefetchClient wraps an elasticsearchClient for the duration of a scrape, to make the requests of the scrape
+// concurrently ahead of the scraper. The scraper still records the metrics of the responses sequentially, in the
+// same order, the calls of the requests started with start waiting for their response instead of making a request.
+// The other calls are passed through.
+type prefetchClient struct {
+	elasticsearchClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	// sem limits the number of requests made concurrently.
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu    sync.Mutex
+	calls map[string]*prefetchCall
+}
+
+// prefetchCall is a request started ahead of the scraper, whose response is available once done is closed.
+type prefetchCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+	// skipped indicates the request wasn't made because the request it depends on failed.
+	skipped bool
+}
+
+var _ elasticsearchClient = (*prefetchClient)(nil)
+
+func newPrefetchClient(ctx context.Context, client elasticsearchClient, concurrency int) *prefetchClient {
+	ctx, cancel := context.WithCancel(ctx)
+	return &prefetchClient{
+		elasticsearchClient: client,
+		ctx:                 ctx,
+		cancel:              cancel,
+		sem:                 make(chan struct{}, concurrency),
+		calls:               map[string]*prefetchCall{},
+	}
+}
+
+// start makes the request in the background, unless it was already started during the scrape. It returns the key
+// of the request, on which the requests made only once it succeeded depend.
+func (p *prefetchClient) start(req prefetchRequest) string {
+	return p.startAfter("", req)
+}
+
+// startAfter makes the request in the background once the dependency succeeded, the same way the scraper only makes
+// it after a successful response of the dependency. The request is made right away if the dependency is empty, and
+// not made if the dependency wasn't started.
+func (p *prefetchClient) startAfter(dependency string, req prefetchRequest) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.calls[req.key] != nil {
+		return req.key
+	}
+	var dependencyCall *prefetchCall
+	if dependency != "" {
+		if dependencyCall = p.calls[dependency]; dependencyCall == nil {
+			return req.key
+		}
+	}
+	call := &prefetchCall{done: make(chan struct{})}
+	p.calls[req.key] = call
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(call.done)
+		if dependencyCall != nil {
+			select {
+			case <-dependencyCall.done:
+			case <-p.ctx.Done():
+				call.err = p.ctx.Err()
+				return
+			}
+			if dependencyCall.skipped || dependencyCall.err != nil {
+				call.skipped = true
+				return
+			}
+		}
+		select {
+		case p.sem <- struct{}{}:
+			defer func() { <-p.sem }()
+		case <-p.ctx.Done():
+			call.err = p.ctx.Err()
+			return
+		}
+		if call.err = p.ctx.Err(); call.err != nil {
+			return
+		}
+		call.value, call.err = req.fetch(p.ctx)
+	}()
+	return req.key
+}
+
+// result returns the response of the request if it was started, or makes it otherwise.
+func (p *prefetchClient) result(ctx context.Context, req prefetchRequest) (interface{}, error) {
+	p.mu.Lock()
+	call := p.calls[req.key]
+	p.mu.Unlock()
+	if call == nil {
+		return req.fetch(ctx)
+	}
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.skipped {
+		return req.fetch(ctx)
+	}
+	return call.value, call.err
+}
+
+// close cancels the requests whose responses were not used by the scrape and waits for them to return.
+func (p *prefetchClient) close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *prefetchClient) nodeStats(nodes []string) prefetchRequest {
+	return prefetchRequest{key: "NodeStats " + strings.Join(nodes, ","), fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.NodeStats(ctx, nodes)
+	}}
+}
+
+func (p *prefetchClient) NodeStats(ctx context.Context, nodes []string) (*model.NodeStats, error) {
+	value, err := p.result(ctx, p.nodeStats(nodes))
+	nodeStats, _ := value.(*model.NodeStats)
+	return nodeStats, err
+}
+
+func (p *prefetchClient) nodesInfo(nodes []string) prefetchRequest {
+	return prefetchRequest{key: "NodesInfo " + strings.Join(nodes, ","), fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.NodesInfo(ctx, nodes)
+	}}
+}
+
+func (p *prefetchClient) NodesInfo(ctx context.Context, nodes []string) (*model.NodesInfo, error) {
+	value, err := p.result(ctx, p.nodesInfo(nodes))
+	nodesInfo, _ := value.(*model.NodesInfo)
+	return nodesInfo, err
+}
+
+func (p *prefetchClient) clusterHealth() prefetchRequest {
+	return prefetchRequest{key: "ClusterHealth", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.ClusterHealth(ctx)
+	}}
+}
+
+func (p *prefetchClient) ClusterHealth(ctx context.Context) (*model.ClusterHealth, error) {
+	value, err := p.result(ctx, p.clusterHealth())
+	clusterHealth, _ := value.(*model.ClusterHealth)
+	return clusterHealth, err
+}
+
+func (p *prefetchClient) ilmStatus() prefetchRequest {
+	return prefetchRequest{key: "ILMStatus", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.ILMStatus(ctx)
+	}}
+}
+
+func (p *prefetchClient) ILMStatus(ctx context.Context) (*model.ILMStatus, error) {
+	value, err := p.result(ctx, p.ilmStatus())
+	ilmStatus, _ := value.(*model.ILMStatus)
+	return ilmStatus, err
+}
+
+func (p *prefetchClient) ilmExplain() prefetchRequest {
+	return prefetchRequest{key: "ILMExplain", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.ILMExplain(ctx)
+	}}
+}
+
+func (p *prefetchClient) ILMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	value, err := p.result(ctx, p.ilmExplain())
+	ilmExplain, _ := value.(*model.ILMExplain)
+	return ilmExplain, err
+}
+
+func (p *prefetchClient) indexStats() prefetchRequest {
+	return prefetchRequest{key: "IndexStats", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.IndexStats(ctx)
+	}}
+}
+
+func (p *prefetchClient) IndexStats(ctx context.Context) (*model.IndexStats, error) {
+	value, err := p.result(ctx, p.indexStats())
+	indexStats, _ := value.(*model.IndexStats)
+	return indexStats, err
+}
+
+func (p *prefetchClient) indexAliases() prefetchRequest {
+	return prefetchRequest{key: "IndexAliases", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.IndexAliases(ctx)
+	}}
+}
+
+func (p *prefetchClient) IndexAliases(ctx context.Context) (model.IndexAliases, error) {
+	value, err := p.result(ctx, p.indexAliases())
+	indexAliases, _ := value.(model.IndexAliases)
+	return indexAliases, err
+}
+
+func (p *prefetchClient) dataStreams() prefetchRequest {
+	return prefetchRequest{key: "DataStreams", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.DataStreams(ctx)
+	}}
+}
+
+func (p *prefetchClient) DataStreams(ctx context.Context) (*model.DataStreams, error) {
+	value, err := p.result(ctx, p.dataStreams())
+	dataStreams, _ := value.(*model.DataStreams)
+	return dataStreams, err
+}
+
+func (p *prefetchClient) transformStats() prefetchRequest {
+	return prefetchRequest{key: "TransformStats", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.TransformStats(ctx)
+	}}
+}
+
+func (p *prefetchClient) TransformStats(ctx context.Context) (*model.TransformStats, error) {
+	value, err := p.result(ctx, p.transformStats())
+	transformStats, _ := value.(*model.TransformStats)
+	return transformStats, err
+}
+
+func (p *prefetchClient) mlJobStats() prefetchRequest {
+	return prefetchRequest{key: "MLJobStats", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.MLJobStats(ctx)
+	}}
+}
+
+func (p *prefetchClient) MLJobStats(ctx context.Context) (*model.MLJobStats, error) {
+	value, err := p.result(ctx, p.mlJobStats())
+	mlJobStats, _ := value.(*model.MLJobStats)
+	return mlJobStats, err
+}
+
+func (p *prefetchClient) pendingTasks() prefetchRequest {
+	return prefetchRequest{key: "PendingTasks", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.PendingTasks(ctx)
+	}}
+}
+
+func (p *prefetchClient) PendingTasks(ctx context.Context) (*model.PendingTasks, error) {
+	value, err := p.result(ctx, p.pendingTasks())
+	pendingTasks, _ := value.(*model.PendingTasks)
+	return pendingTasks, err
+}
+
+func (p *prefetchClient) catHealth() prefetchRequest {
+	return prefetchRequest{key: "CatHealth", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.CatHealth(ctx)
+	}}
+}
+
+func (p *prefetchClient) CatHealth(ctx context.Context) (model.CatHealth, error) {
+	value, err := p.result(ctx, p.catHealth())
+	catHealth, _ := value.(model.CatHealth)
+	return catHealth, err
+}
+
+func (p *prefetchClient) catNodes() prefetchRequest {
+	return prefetchRequest{key: "CatNodes", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.CatNodes(ctx)
+	}}
+}
+
+func (p *prefetchClient) CatNodes(ctx context.Context) (model.CatNodes, error) {
+	value, err := p.result(ctx, p.catNodes())
+	catNodes, _ := value.(model.CatNodes)
+	return catNodes, err
+}
+
+func (p *prefetchClient) catShards() prefetchRequest {
+	return prefetchRequest{key: "CatShards", fetch: func(ctx context.Context) (interface{}, error) {
+		return p.elasticsearchClient.CatShards(ctx)
+	}}
+}
+
+func (p *prefetchClient) CatShards(ctx context.Context) (model.CatShards, error) {
+	value, err := p.result(ctx, p.catShards())
+	catShards, _ := value.(model.CatShards)
+	return catShards, err
+}
+
+// startRequests starts the requests of the plan, in the order the scraper makes them: the node stats, followed by
+// the cluster health and the requests depending on it. The requests of the cluster level metrics depending on the
+// role of the local node are started by startClusterRequests once the scraper checked the role, only the info of
+// the local node is started then.
+func (r *elasticsearchScraper) startRequests(p *prefetchClient, plan requestPlan) {
+	// The cluster health isn't requested once the node stats switched the scraper to the _cat APIs, it's only
+	// requested along with the node stats once the scraper can't fall back to the _cat APIs anymore.
+	var clusterDependency string
+	if plan.nodeStats {
+		if r.useCatAPIs {
+			p.startAfter(p.start(p.catHealth()), p.catNodes())
+		} else {
+			nodeStats := p.start(p.nodeStats(r.cfg.nodeSelectors()))
+			if r.cfg.RestrictedMode == restrictedModeFallback {
+				clusterDependency = nodeStats
+			}
+		}
+	}
+	if !plan.clusterHealth {
+		return
+	}
+	if r.cfg.EmitClusterHealthFrom != "" {
+		p.start(p.nodesInfo([]string{localNode}))
+		return
+	}
+	r.startClusterRequests(p, clusterDependency, plan)
+}
+
+// startClusterRequests starts the request of the cluster health after the dependency, followed by the requests of
+// the cluster level metrics of the plan once it succeeded.
+func (r *elasticsearchScraper) startClusterRequests(p *prefetchClient, dependency string, plan requestPlan) {
+	if r.useCatAPIs {
+		health := p.startAfter(dependency, p.catHealth())
+		if plan.shardStats {
+			p.startAfter(health, p.catShards())
+		}
+		return
+	}
+
+	health := p.startAfter(dependency, p.clusterHealth())
+	if plan.ilmStatus {
+		p.startAfter(health, p.ilmStatus())
+	}
+	if plan.transformStats {
+		p.startAfter(health, p.transformStats())
+	}
+	if plan.mlJobStats {
+		p.startAfter(health, p.mlJobStats())
+	}
+	if plan.pendingTasks {
+		p.startAfter(health, p.pendingTasks())
+	}
+
+	// The requests of the selected indices are made once the indices are resolved.
+	selector := health
+	if plan.resolveIndices {
+		selector = p.startAfter(p.startAfter(health, p.indexAliases()), p.dataStreams())
+	}
+	if plan.ilmExplain {
+		p.startAfter(selector, p.ilmExplain())
+	}
+	if plan.shardStats {
+		p.startAfter(selector, p.indexStats())
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/mocks"
+)
+
+func TestPrefetchClientResult(t *testing.T) {
+	t.Parallel()
+
+	p := newPrefetchClient(context.Background(), &mocks.MockElasticsearchClient{}, 2)
+	defer p.close()
+
+	var calls int32
+	req := prefetchRequest{key: "request", fetch: func(context.Context) (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}}
+	p.start(req)
+	p.start(req)
+
+	// The started request is made once, and its response is returned to every call during the scrape.
+	for i := 0; i < 2; i++ {
+		value, err := p.result(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), value)
+	}
+
+	// The requests which weren't started are made by the call.
+	value, err := p.result(context.Background(), prefetchRequest{key: "other", fetch: req.fetch})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), value)
+}
+
+func TestPrefetchClientDependency(t *testing.T) {
+	t.Parallel()
+
+	p := newPrefetchClient(context.Background(), &mocks.MockElasticsearchClient{}, 2)
+	defer p.close()
+
+	errDependency := errors.New("dependency failed")
+	failed := p.start(prefetchRequest{key: "failed", fetch: func(context.Context) (interface{}, error) {
+		return nil, errDependency
+	}})
+	succeeded := p.start(prefetchRequest{key: "succeeded", fetch: func(context.Context) (interface{}, error) {
+		return "dependency", nil
+	}})
+
+	var calls int32
+	fetch := func(context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return "dependent", nil
+	}
+	afterSucceeded := p.startAfter(succeeded, prefetchRequest{key: "after succeeded", fetch: fetch})
+	afterFailed := p.startAfter(failed, prefetchRequest{key: "after failed", fetch: fetch})
+	// The requests depending on a skipped one are skipped as well.
+	afterSkipped := p.startAfter(afterFailed, prefetchRequest{key: "after skipped", fetch: fetch})
+	p.startAfter("not started", prefetchRequest{key: "after not started", fetch: fetch})
+
+	_, err := p.result(context.Background(), prefetchRequest{key: failed})
+	require.ErrorIs(t, err, errDependency)
+	value, err := p.result(context.Background(), prefetchRequest{key: afterSucceeded})
+	require.NoError(t, err)
+	require.Equal(t, "dependent", value)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// The skipped requests are only made if called anyway.
+	p.wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	value, err = p.result(context.Background(), prefetchRequest{key: afterSkipped, fetch: fetch})
+	require.NoError(t, err)
+	require.Equal(t, "dependent", value)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestPrefetchClientConcurrency(t *testing.T) {
+	t.Parallel()
+
+	p := newPrefetchClient(context.Background(), &mocks.MockElasticsearchClient{}, 2)
+
+	var running, maxRunning int32
+	release := make(chan struct{})
+	for _, key := range []string{"a", "b", "c", "d"} {
+		p.start(prefetchRequest{key: key, fetch: func(context.Context) (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				highest := atomic.LoadInt32(&maxRunning)
+				if n <= highest || atomic.CompareAndSwapInt32(&maxRunning, highest, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&running, -1)
+			return nil, nil
+		}})
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 2 }, 5*time.Second, time.Millisecond)
+	close(release)
+	p.close()
+	require.Equal(t, int32(2), maxRunning)
+}
+
+func TestPrefetchClientClose(t *testing.T) {
+	t.Parallel()
+
+	p := newPrefetchClient(context.Background(), &mocks.MockElasticsearchClient{}, 1)
+	started := make(chan struct{})
+	p.start(prefetchRequest{key: "unused", fetch: func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+	<-started
+	var queuedCalls int32
+	p.start(prefetchRequest{key: "queued", fetch: func(context.Context) (interface{}, error) {
+		atomic.AddInt32(&queuedCalls, 1)
+		return nil, nil
+	}})
+
+	// Closing the client cancels the requests whose responses weren't used by the scrape.
+	p.close()
+	require.Equal(t, int32(0), queuedCalls)
+}
+
+func TestScraperConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	sc := newElasticSearchScraper(zap.NewNop(), createDefaultConfig().(*Config))
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// The node stats and the cluster health only return once both were requested, which times out if the
+	// requests are made sequentially.
+	nodeStatsRequested := make(chan struct{})
+	clusterHealthRequested := make(chan struct{})
+	waitFor := func(requested chan struct{}) func(mock.Arguments) {
+		return func(mock.Arguments) {
+			select {
+			case <-requested:
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Run(func(args mock.Arguments) {
+		close(nodeStatsRequested)
+		waitFor(clusterHealthRequested)(args)
+	}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Run(func(args mock.Arguments) {
+		close(clusterHealthRequested)
+		waitFor(nodeStatsRequested)(args)
+	}).Return(clusterHealth(t), nil)
+
+	sc.client = &mockClient
+
+	start := time.Now()
+	_, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+	mockClient.AssertExpectations(t)
+	require.Equal(t, &mockClient, sc.client)
+}
+
+func TestScraperSequentialRequests(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.ConcurrentRequests = 1
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	var order []string
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Run(func(mock.Arguments) {
+		order = append(order, "NodeStats")
+	}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Run(func(mock.Arguments) {
+		order = append(order, "ClusterHealth")
+	}).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+
+	sc.client = &mockClient
+
+	_, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"NodeStats", "ClusterHealth"}, order)
+}
@@ -0,0 +1,193 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+var errEmptyCatHealth = errors.New("the cluster health returned by the _cat APIs is empty")
+
+// catNodeMetrics is the number of node level metrics recorded from the _cat APIs.
+const catNodeMetrics = 9
+
+// fallbackToCatAPIs switches the scraper to the _cat APIs if the restricted mode is fallback and err reports that
+// Elasticsearch rejected a request with a 403 status code. It returns whether the scraper switched.
+func (r *elasticsearchScraper) fallbackToCatAPIs(err error) bool {
+	if r.cfg.RestrictedMode != restrictedModeFallback || !errors.Is(err, errUnauthorized) {
+		return false
+	}
+	r.logger.Warn("Elasticsearch rejected a request with a 403 status code, scraping a reduced set of metrics from the _cat APIs", zap.Error(err))
+	r.useCatAPIs = true
+	return true
+}
+
+// catClusterHealth returns the cluster health of the _cat health endpoint.
+func (r *elasticsearchScraper) catClusterHealth(ctx context.Context) (*model.ClusterHealth, error) {
+	catHealth, err := r.client.CatHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(catHealth) == 0 {
+		return nil, errEmptyCatHealth
+	}
+	row := catHealth[0]
+	return &model.ClusterHealth{
+		ClusterName:        row.Cluster,
+		ActiveShards:       row.ActiveShards,
+		RelocatingShards:   row.RelocatingShards,
+		InitializingShards: row.InitializingShards,
+		UnassignedShards:   row.UnassignedShards,
+		NodeCount:          row.NodeCount,
+		DataNodeCount:      row.DataNodeCount,
+		Status:             row.Status,
+	}, nil
+}
+
+// scrapeCatNodeMetrics records the subset of the node level metrics available from the _cat nodes endpoint, for
+// every node of the cluster. The cluster name of their resources is requested from the _cat health endpoint.
+func (r *elasticsearchScraper) scrapeCatNodeMetrics(ctx context.Context, now time.Time, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
+	clusterHealth, err := r.catClusterHealth(ctx)
+	if err != nil {
+		errs.AddPartial(catNodeMetrics, err)
+		return
+	}
+
+	catNodes, err := r.client.CatNodes(ctx)
+	if err != nil {
+		errs.AddPartial(catNodeMetrics, err)
+		return
+	}
+
+	var rollup *nodeRollup
+	if r.cfg.NodeRollupMetrics {
+		rollup = newNodeRollup()
+	}
+	for _, node := range catNodes {
+		r.metricsBuilder.RecordElasticsearchNodeCacheMemoryUsageDataPoint(r.now, node.FieldDataMemorySize, metadata.AttributeCacheName.Fielddata)
+		r.metricsBuilder.RecordElasticsearchNodeCacheMemoryUsageDataPoint(r.now, node.QueryCacheMemorySize, metadata.AttributeCacheName.Query)
+
+		r.metricsBuilder.RecordElasticsearchNodeCacheEvictionsDataPoint(r.now, node.FieldDataEvictions, metadata.AttributeCacheName.Fielddata)
+		r.metricsBuilder.RecordElasticsearchNodeCacheEvictionsDataPoint(r.now, node.QueryCacheEvictions, metadata.AttributeCacheName.Query)
+
+		r.metricsBuilder.RecordElasticsearchNodeCacheCountDataPoint(r.now, node.QueryCacheHitCount, metadata.AttributeQueryCacheCountType.Hit)
+		r.metricsBuilder.RecordElasticsearchNodeCacheCountDataPoint(r.now, node.QueryCacheMissCount, metadata.AttributeQueryCacheCountType.Miss)
+
+		r.metricsBuilder.RecordElasticsearchNodeFsDiskAvailableDataPoint(r.now, node.DiskAvailable)
+
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.IndexingIndexTotal, metadata.AttributeOperation.Index)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.IndexingDeleteTotal, metadata.AttributeOperation.Delete)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.GetTotal, metadata.AttributeOperation.Get)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.SearchQueryTotal, metadata.AttributeOperation.Query)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.SearchFetchTotal, metadata.AttributeOperation.Fetch)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.SearchScrollTotal, metadata.AttributeOperation.Scroll)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.MergesTotal, metadata.AttributeOperation.Merge)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.RefreshTotal, metadata.AttributeOperation.Refresh)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsCompletedDataPoint(r.now, node.FlushTotal, metadata.AttributeOperation.Flush)
+
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.IndexingIndexTimeInMs, metadata.AttributeOperation.Index)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.IndexingDeleteTimeInMs, metadata.AttributeOperation.Delete)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.GetTimeInMs, metadata.AttributeOperation.Get)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.SearchQueryTimeInMs, metadata.AttributeOperation.Query)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.SearchFetchTimeInMs, metadata.AttributeOperation.Fetch)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.SearchScrollTimeInMs, metadata.AttributeOperation.Scroll)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.MergesTotalTimeInMs, metadata.AttributeOperation.Merge)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.RefreshTimeInMs, metadata.AttributeOperation.Refresh)
+		r.metricsBuilder.RecordElasticsearchNodeOperationsTimeDataPoint(r.now, node.FlushTotalTimeInMs, metadata.AttributeOperation.Flush)
+
+		r.metricsBuilder.RecordElasticsearchNodeOpenFilesDataPoint(r.now, node.FileDescriptors)
+
+		r.metricsBuilder.RecordJvmMemoryHeapMaxDataPoint(r.now, node.HeapMax)
+		r.metricsBuilder.RecordJvmMemoryHeapUsedDataPoint(r.now, node.HeapCurrent)
+
+		r.metricsBuilder.EmitForResource(rms,
+			metadata.WithElasticsearchClusterName(clusterHealth.ClusterName),
+			metadata.WithElasticsearchNodeName(node.Name),
+		)
+
+		// The _cat nodes API doesn't return the node IDs, the nodes are identified by name instead.
+		if rollup != nil {
+			rollup.add(node.Name, node.HeapCurrent, node.HeapMax, nodeOperationTotals{
+				index: node.IndexingIndexTotal,
+				query: node.SearchQueryTotal,
+			})
+		}
+	}
+
+	if rollup != nil && len(catNodes) > 0 {
+		r.recordNodeRollup(now, clusterHealth.ClusterName, rollup, rms)
+	}
+}
+
+// scrapeCatClusterMetrics records the cluster level metrics of the plan from the _cat health endpoint, and the shard
+// metrics from the _cat shards endpoint. The ILM, transform and ML job metrics are not available from the _cat APIs.
+func (r *elasticsearchScraper) scrapeCatClusterMetrics(ctx context.Context, plan requestPlan, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
+	clusterHealth, err := r.catClusterHealth(ctx)
+	if err != nil {
+		errs.AddPartial(plan.clusterMetrics(true), err)
+		return
+	}
+
+	if plan.clusterHealthMetrics {
+		r.recordClusterHealth(clusterHealth, errs)
+	}
+
+	if plan.shardStats {
+		r.scrapeCatShardMetrics(ctx, r.catIndexSelector(), errs)
+	}
+
+	r.metricsBuilder.EmitForResource(rms, metadata.WithElasticsearchClusterName(clusterHealth.ClusterName))
+}
+
+// catIndexSelector returns the selector of the indices whose name matches the indices option. The aliases and data
+// streams aren't resolved, as their endpoints require the view_index_metadata index privilege.
+func (r *elasticsearchScraper) catIndexSelector() indexSelector {
+	if r.indexFilter == nil {
+		return func(string) bool { return true }
+	}
+	return r.indexFilter.matches
+}
+
+// scrapeCatShardMetrics records the store size and the active documents of every assigned shard copy of the
+// selected indices from the _cat shards endpoint, which does not report the deleted documents.
+func (r *elasticsearchScraper) scrapeCatShardMetrics(ctx context.Context, selectIndex indexSelector, errs *scrapererror.ScrapeErrors) {
+	catShards, err := r.client.CatShards(ctx)
+	if err != nil {
+		errs.AddPartial(catShardsMetricCount, err)
+		return
+	}
+
+	for _, shard := range catShards {
+		if shard.Node == "" || !selectIndex(shard.Index) {
+			continue
+		}
+		shardType := metadata.AttributeShardType.Replica
+		if shard.PriRep == "p" {
+			shardType = metadata.AttributeShardType.Primary
+		}
+
+		r.metricsBuilder.RecordElasticsearchShardStoreSizeDataPoint(r.now, shard.StoreSize, shard.Index, shard.Shard, shard.Node, shardType)
+		r.metricsBuilder.RecordElasticsearchShardDocumentsDataPoint(r.now, shard.Documents, shard.Index, shard.Shard, shard.Node, shardType, metadata.AttributeDocumentState.Active)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/metadata"
+)
+
+// nodeOperationTotals are the cumulative numbers of operations completed by a node, from which the rates of the
+// operations are computed.
+type nodeOperationTotals struct {
+	index int64
+	query int64
+}
+
+// nodeRollup accumulates the node level metrics of the nodes scraped by a scrape, to be rolled up into cluster level metrics.
+type nodeRollup struct {
+	heapUsed int64
+	heapMax  int64
+	// heapUtilization is the sum of the heap utilizations of the heapUtilizationNodes nodes having a maximum heap.
+	heapUtilization      float64
+	heapUtilizationNodes int
+	// operations holds the operation totals of the scraped nodes, keyed by node.
+	operations map[string]nodeOperationTotals
+}
+
+func newNodeRollup() *nodeRollup {
+	return &nodeRollup{operations: map[string]nodeOperationTotals{}}
+}
+
+// add adds the metrics of a node, identified by key across scrapes.
+func (n *nodeRollup) add(key string, heapUsed, heapMax int64, operations nodeOperationTotals) {
+	n.heapUsed += heapUsed
+	n.heapMax += heapMax
+	if heapMax > 0 {
+		n.heapUtilization += float64(heapUsed) / float64(heapMax)
+		n.heapUtilizationNodes++
+	}
+	n.operations[key] = operations
+}
+
+// recordNodeRollup records the cluster level metrics rolled up from the nodes of a scrape at now, and emits them
+// for the cluster. The rates of the operations are computed from the totals of the previous scrape of the nodes,
+// and are only recorded once a node was scraped twice. The nodes whose totals decreased, e.g. since they restarted,
+// are left out of the rates until their next scrape.
+func (r *elasticsearchScraper) recordNodeRollup(now time.Time, clusterName string, rollup *nodeRollup, rms pdata.ResourceMetricsSlice) {
+	r.metricsBuilder.RecordElasticsearchClusterNodesJvmMemoryHeapUsedDataPoint(r.now, rollup.heapUsed)
+	r.metricsBuilder.RecordElasticsearchClusterNodesJvmMemoryHeapMaxDataPoint(r.now, rollup.heapMax)
+	if rollup.heapUtilizationNodes > 0 {
+		r.metricsBuilder.RecordElasticsearchClusterNodesJvmMemoryHeapUtilizationDataPoint(r.now, rollup.heapUtilization/float64(rollup.heapUtilizationNodes))
+	}
+
+	if elapsed := now.Sub(r.previousNodeScrape).Seconds(); elapsed > 0 {
+		var indexed, queried int64
+		rated := false
+		for key, totals := range rollup.operations {
+			previous, ok := r.previousNodeOperations[key]
+			if !ok || totals.index < previous.index || totals.query < previous.query {
+				continue
+			}
+			indexed += totals.index - previous.index
+			queried += totals.query - previous.query
+			rated = true
+		}
+		if rated {
+			r.metricsBuilder.RecordElasticsearchClusterNodesOperationsRateDataPoint(r.now, float64(indexed)/elapsed, metadata.AttributeOperation.Index)
+			r.metricsBuilder.RecordElasticsearchClusterNodesOperationsRateDataPoint(r.now, float64(queried)/elapsed, metadata.AttributeOperation.Query)
+		}
+	}
+	r.previousNodeOperations = rollup.operations
+	r.previousNodeScrape = now
+
+	r.metricsBuilder.EmitForResource(rms, metadata.WithElasticsearchClusterName(clusterName))
+}
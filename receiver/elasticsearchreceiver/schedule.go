@@ -0,0 +1,40 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import "time"
+
+// scrapeSchedule tracks when a group of endpoints with its own collection interval is due to be scraped.
+// The group is scraped on every scrape if its interval is 0.
+type scrapeSchedule struct {
+	interval time.Duration
+	// tolerance lets the group be scraped by the scrape closest to the end of its interval, as the scrapes
+	// are triggered every collection_interval with some jitter.
+	tolerance time.Duration
+	last      time.Time
+}
+
+func newScrapeSchedule(interval, collectionInterval time.Duration) scrapeSchedule {
+	return scrapeSchedule{interval: interval, tolerance: collectionInterval / 2}
+}
+
+// due returns whether the group is scraped at now, and records now as its last scrape if it is.
+func (s *scrapeSchedule) due(now time.Time) bool {
+	if s.interval > 0 && !s.last.IsZero() && now.Sub(s.last)+s.tolerance < s.interval {
+		return false
+	}
+	s.last = now
+	return true
+}
@@ -0,0 +1,47 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapeSchedule(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	schedule := newScrapeSchedule(time.Minute, 10*time.Second)
+	require.True(t, schedule.due(start))
+	for i := 1; i < 6; i++ {
+		require.False(t, schedule.due(start.Add(time.Duration(i)*10*time.Second)))
+	}
+	// the scrape triggered slightly before the end of the interval is closest to it.
+	require.True(t, schedule.due(start.Add(time.Minute-time.Second)))
+	require.False(t, schedule.due(start.Add(time.Minute+9*time.Second)))
+	require.True(t, schedule.due(start.Add(2*time.Minute)))
+}
+
+func TestScrapeScheduleNoInterval(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	schedule := newScrapeSchedule(0, 10*time.Second)
+	for i := 0; i < 3; i++ {
+		require.True(t, schedule.due(start.Add(time.Duration(i)*10*time.Second)))
+	}
+}
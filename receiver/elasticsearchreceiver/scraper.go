@@ -18,19 +18,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
 )
 
 const instrumentationLibraryName = "otelcol/elasticsearch"
 
-var errUnknownClusterStatus = errors.New("unknown cluster status")
+var (
+	errUnknownClusterStatus  = errors.New("unknown cluster status")
+	errUnknownILMStatus      = errors.New("unknown ILM status")
+	errUnknownTransformState = errors.New("unknown transform state")
+	errUnknownMLJobState     = errors.New("unknown ML job state")
+	errUnknownTaskPriority   = errors.New("unknown pending task priority")
+	errLocalNodeNotFound     = errors.New("local node not found")
+)
 
 type elasticsearchScraper struct {
 	client         elasticsearchClient
@@ -38,64 +49,162 @@ type elasticsearchScraper struct {
 	cfg            *Config
 	metricsBuilder *metadata.MetricsBuilder
 	now            pdata.Timestamp
+	// indexFilter is nil if the metrics of every index are scraped.
+	indexFilter *indexFilter
+	// throttleObserver wraps client to record whether Elasticsearch rejected requests during a scrape.
+	throttleObserver *throttleObserver
+	backoff          scrapeBackoff
+	// The schedules of the groups of endpoints with their own collection intervals.
+	clusterHealthSchedule scrapeSchedule
+	nodeStatsSchedule     scrapeSchedule
+	indexStatsSchedule    scrapeSchedule
+	// useCatAPIs indicates whether the _cat APIs are scraped instead of the node stats and cluster health APIs.
+	useCatAPIs bool
+	// previousNodeOperations are the operation totals of the nodes at previousNodeScrape, the previous scrape of the
+	// node level metrics, from which the rates of the operations rolled up into cluster level metrics are computed.
+	previousNodeOperations map[string]nodeOperationTotals
+	previousNodeScrape     time.Time
 }
 
+// indexSelector reports whether the metrics of an index are scraped.
+type indexSelector func(index string) bool
+
 func newElasticSearchScraper(
 	logger *zap.Logger,
 	cfg *Config,
 ) *elasticsearchScraper {
-	return &elasticsearchScraper{
+	r := &elasticsearchScraper{
 		logger:         logger,
 		cfg:            cfg,
 		now:            pdata.NewTimestampFromTime(time.Now()),
-		metricsBuilder: metadata.NewMetricsBuilder(cfg.Metrics),
+		metricsBuilder: metadata.NewMetricsBuilder(cfg.Metrics, metadata.WithInstrumentationLibraryName(instrumentationLibraryName)),
+		backoff:        scrapeBackoff{cfg: cfg.Backoff},
+
+		clusterHealthSchedule: newScrapeSchedule(cfg.CollectionIntervals.ClusterHealth, cfg.CollectionInterval),
+		nodeStatsSchedule:     newScrapeSchedule(cfg.CollectionIntervals.NodeStats, cfg.CollectionInterval),
+		indexStatsSchedule:    newScrapeSchedule(cfg.CollectionIntervals.IndexStats, cfg.CollectionInterval),
+
+		useCatAPIs: cfg.RestrictedMode == restrictedModeAlways,
+	}
+	if len(cfg.Indices) > 0 {
+		r.indexFilter = newIndexFilter(cfg.Indices)
 	}
+	return r
 }
 
 func (r *elasticsearchScraper) start(_ context.Context, host component.Host) (err error) {
-	r.client, err = newElasticsearchClient(r.logger, *r.cfg, host)
-	return
+	if r.cfg.ShardMetrics && !r.cfg.SkipClusterMetrics {
+		r.logger.Warn("Shard level metrics are enabled, a data point is emitted for every copy of every shard in the cluster which may result in a high cardinality")
+	}
+	if r.cfg.RestrictedMode == restrictedModeAlways || r.cfg.RestrictedMode == restrictedModeFallback {
+		if nodes := r.cfg.nodeSelectors(); len(nodes) > 0 && (len(nodes) != 1 || nodes[0] != "_all") {
+			r.logger.Warn("The node filters are not supported by the _cat APIs, the node level metrics of every node are scraped in restricted mode")
+		}
+	}
+	client, err := newElasticsearchClient(r.logger, *r.cfg, host)
+	if err != nil {
+		return err
+	}
+	r.setClient(client)
+	return nil
+}
+
+// setClient sets the client used by the scraper, wrapped to back off when Elasticsearch rejects its requests.
+func (r *elasticsearchScraper) setClient(client elasticsearchClient) {
+	r.throttleObserver = &throttleObserver{elasticsearchClient: client}
+	r.client = r.throttleObserver
 }
 
 func (r *elasticsearchScraper) scrape(ctx context.Context) (pdata.Metrics, error) {
 	metrics := pdata.NewMetrics()
 	rms := metrics.ResourceMetrics()
 
+	now := time.Now()
+	if r.backoff.skip(now) {
+		r.logger.Debug("Skipping scrape to back off from Elasticsearch")
+		r.recordStat(ctx, statSkippedScrapes)
+		return metrics, nil
+	}
+	if r.throttleObserver != nil {
+		r.throttleObserver.reset()
+	}
+
 	errs := &scrapererror.ScrapeErrors{}
 
-	r.scrapeNodeMetrics(ctx, rms, errs)
-	r.scrapeClusterMetrics(ctx, rms, errs)
+	nodeStatsDue := r.nodeStatsSchedule.due(now)
+	var healthDue, indexStatsDue bool
+	if !r.cfg.SkipClusterMetrics {
+		healthDue = r.clusterHealthSchedule.due(now)
+		indexStatsDue = r.indexStatsSchedule.due(now)
+	}
+	plan := r.newRequestPlan(nodeStatsDue, healthDue, indexStatsDue)
+
+	// The requests are made concurrently ahead of the scrape through a prefetchClient, while the metrics of
+	// their responses are recorded in the same order as when they're made sequentially.
+	var prefetcher *prefetchClient
+	if r.cfg.ConcurrentRequests > 1 {
+		prefetcher = newPrefetchClient(ctx, r.client, r.cfg.ConcurrentRequests)
+		r.client = prefetcher
+		r.startRequests(prefetcher, plan)
+	}
+
+	if plan.nodeStats {
+		r.scrapeNodeMetrics(ctx, now, rms, errs)
+	}
+	r.scrapeClusterMetrics(ctx, plan, rms, errs)
+
+	if prefetcher != nil {
+		prefetcher.close()
+		r.client = prefetcher.elasticsearchClient
+	}
+
+	if r.throttleObserver != nil && r.throttleObserver.throttled {
+		interval := r.backoff.throttled(now, r.throttleObserver.retryAfter)
+		r.logger.Warn("Elasticsearch rejected requests with a 429 status code, backing off", zap.Duration("interval", interval), zap.Bool("skip_scrapes", r.cfg.Backoff.SkipScrapes))
+		r.recordStat(ctx, statThrottledScrapes)
+	} else {
+		r.backoff.reset()
+	}
 
 	return metrics, errs.Combine()
 }
 
+func (r *elasticsearchScraper) recordStat(ctx context.Context, measure *stats.Int64Measure) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagReceiverKey, r.cfg.ID().String())}, measure.M(1))
+}
+
 // scrapeNodeMetrics scrapes adds node-level metrics to the given MetricSlice from the NodeStats endpoint
-func (r *elasticsearchScraper) scrapeNodeMetrics(ctx context.Context, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
-	if len(r.cfg.Nodes) == 0 {
+func (r *elasticsearchScraper) scrapeNodeMetrics(ctx context.Context, now time.Time, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
+	if r.useCatAPIs {
+		r.scrapeCatNodeMetrics(ctx, now, rms, errs)
 		return
 	}
 
-	nodeStats, err := r.client.NodeStats(ctx, r.cfg.Nodes)
+	nodeStats, err := r.client.NodeStats(ctx, r.cfg.nodeSelectors())
 	if err != nil {
+		if r.fallbackToCatAPIs(err) {
+			r.scrapeCatNodeMetrics(ctx, now, rms, errs)
+			return
+		}
 		errs.AddPartial(26, err)
 		return
 	}
 
-	for _, info := range nodeStats.Nodes {
-		rm := rms.AppendEmpty()
-		resourceAttrs := rm.Resource().Attributes()
-		resourceAttrs.InsertString(metadata.A.ElasticsearchClusterName, nodeStats.ClusterName)
-		resourceAttrs.InsertString(metadata.A.ElasticsearchNodeName, info.Name)
-
-		ilms := rm.InstrumentationLibraryMetrics().AppendEmpty()
-		ilms.InstrumentationLibrary().SetName(instrumentationLibraryName)
-
+	var rollup *nodeRollup
+	if r.cfg.NodeRollupMetrics {
+		rollup = newNodeRollup()
+	}
+	for id, info := range nodeStats.Nodes {
 		r.metricsBuilder.RecordElasticsearchNodeCacheMemoryUsageDataPoint(r.now, info.Indices.FieldDataCache.MemorySizeInBy, metadata.AttributeCacheName.Fielddata)
 		r.metricsBuilder.RecordElasticsearchNodeCacheMemoryUsageDataPoint(r.now, info.Indices.QueryCache.MemorySizeInBy, metadata.AttributeCacheName.Query)
 
 		r.metricsBuilder.RecordElasticsearchNodeCacheEvictionsDataPoint(r.now, info.Indices.FieldDataCache.Evictions, metadata.AttributeCacheName.Fielddata)
 		r.metricsBuilder.RecordElasticsearchNodeCacheEvictionsDataPoint(r.now, info.Indices.QueryCache.Evictions, metadata.AttributeCacheName.Query)
 
+		r.metricsBuilder.RecordElasticsearchNodeCacheCountDataPoint(r.now, info.Indices.QueryCache.HitCount, metadata.AttributeQueryCacheCountType.Hit)
+		r.metricsBuilder.RecordElasticsearchNodeCacheCountDataPoint(r.now, info.Indices.QueryCache.MissCount, metadata.AttributeQueryCacheCountType.Miss)
+		r.metricsBuilder.RecordElasticsearchNodeCacheEntriesDataPoint(r.now, info.Indices.QueryCache.CacheSize)
+
 		r.metricsBuilder.RecordElasticsearchNodeFsDiskAvailableDataPoint(r.now, info.FS.Total.AvailableBytes)
 
 		r.metricsBuilder.RecordElasticsearchNodeClusterIoDataPoint(r.now, info.TransportStats.ReceivedBytes, metadata.AttributeDirection.Received)
@@ -165,34 +274,105 @@ func (r *elasticsearchScraper) scrapeNodeMetrics(ctx context.Context, rms pdata.
 		r.metricsBuilder.RecordJvmMemoryPoolUsedDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Survivor.MemUsedBy, "survivor")
 		r.metricsBuilder.RecordJvmMemoryPoolUsedDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Old.MemUsedBy, "old")
 
+		r.metricsBuilder.RecordJvmMemoryPoolPeakUsedDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Young.MemPeakUsedBy, "young")
+		r.metricsBuilder.RecordJvmMemoryPoolPeakUsedDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Survivor.MemPeakUsedBy, "survivor")
+		r.metricsBuilder.RecordJvmMemoryPoolPeakUsedDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Old.MemPeakUsedBy, "old")
+
 		r.metricsBuilder.RecordJvmMemoryPoolMaxDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Young.MemMaxBy, "young")
 		r.metricsBuilder.RecordJvmMemoryPoolMaxDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Survivor.MemMaxBy, "survivor")
 		r.metricsBuilder.RecordJvmMemoryPoolMaxDataPoint(r.now, info.JVMInfo.JVMMemoryInfo.MemoryPools.Old.MemMaxBy, "old")
 
 		r.metricsBuilder.RecordJvmThreadsCountDataPoint(r.now, info.JVMInfo.JVMThreadInfo.Count)
 
-		r.metricsBuilder.EmitNodeMetrics(ilms.Metrics())
+		r.metricsBuilder.EmitForResource(rms,
+			metadata.WithElasticsearchClusterName(nodeStats.ClusterName),
+			metadata.WithElasticsearchNodeName(info.Name),
+		)
+
+		if rollup != nil {
+			rollup.add(id, info.JVMInfo.JVMMemoryInfo.HeapUsedInBy, info.JVMInfo.JVMMemoryInfo.MaxHeapInBy, nodeOperationTotals{
+				index: info.Indices.IndexingOperations.IndexTotal,
+				query: info.Indices.SearchOperations.QueryTotal,
+			})
+		}
+	}
+
+	if rollup != nil && len(nodeStats.Nodes) > 0 {
+		r.recordNodeRollup(now, nodeStats.ClusterName, rollup, rms)
 	}
 }
 
-func (r *elasticsearchScraper) scrapeClusterMetrics(ctx context.Context, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
-	if r.cfg.SkipClusterMetrics {
+// scrapeClusterMetrics scrapes the cluster level metrics of the plan. The cluster health, whose cluster name is the
+// resource of the metrics, is requested if any group of cluster level metrics is due.
+func (r *elasticsearchScraper) scrapeClusterMetrics(ctx context.Context, plan requestPlan, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
+	if !plan.clusterHealth {
+		return
+	}
+
+	if r.cfg.EmitClusterHealthFrom != "" {
+		emit, err := r.emitClusterMetrics(ctx)
+		if err != nil {
+			errs.AddPartial(plan.clusterMetrics(r.useCatAPIs), err)
+			return
+		}
+		if !emit {
+			return
+		}
+		if prefetcher, ok := r.client.(*prefetchClient); ok {
+			r.startClusterRequests(prefetcher, "", plan)
+		}
+	}
+
+	if r.useCatAPIs {
+		r.scrapeCatClusterMetrics(ctx, plan, rms, errs)
 		return
 	}
 
 	clusterHealth, err := r.client.ClusterHealth(ctx)
 	if err != nil {
-		errs.AddPartial(4, err)
+		if r.fallbackToCatAPIs(err) {
+			r.scrapeCatClusterMetrics(ctx, plan, rms, errs)
+			return
+		}
+		errs.AddPartial(plan.clusterMetrics(false), err)
 		return
 	}
 
-	rm := rms.AppendEmpty()
-	resourceAttrs := rm.Resource().Attributes()
-	resourceAttrs.InsertString(metadata.A.ElasticsearchClusterName, clusterHealth.ClusterName)
+	if plan.clusterHealthMetrics {
+		r.recordClusterHealth(clusterHealth, errs)
+	}
+	if plan.ilmStatus {
+		r.scrapeILMStatus(ctx, errs)
+	}
+	if plan.transformStats {
+		r.scrapeTransformMetrics(ctx, errs)
+	}
+	if plan.mlJobStats {
+		r.scrapeMLJobMetrics(ctx, errs)
+	}
+	if plan.pendingTasks {
+		r.scrapePendingTasks(ctx, errs)
+	}
+
+	if plan.ilmExplain || plan.shardStats {
+		selectIndex, err := r.indexSelector(ctx, plan)
+		if err != nil {
+			errs.AddPartial(plan.indexMetrics(), err)
+		} else {
+			if plan.ilmExplain {
+				r.scrapeILMIndicesErrors(ctx, selectIndex, errs)
+			}
+			if plan.shardStats {
+				r.scrapeShardMetrics(ctx, selectIndex, errs)
+			}
+		}
+	}
 
-	ilms := rm.InstrumentationLibraryMetrics().AppendEmpty()
-	ilms.InstrumentationLibrary().SetName(instrumentationLibraryName)
+	r.metricsBuilder.EmitForResource(rms, metadata.WithElasticsearchClusterName(clusterHealth.ClusterName))
+}
 
+// recordClusterHealth records the cluster level metrics of the cluster health.
+func (r *elasticsearchScraper) recordClusterHealth(clusterHealth *model.ClusterHealth, errs *scrapererror.ScrapeErrors) {
 	r.metricsBuilder.RecordElasticsearchClusterNodesDataPoint(r.now, clusterHealth.NodeCount)
 
 	r.metricsBuilder.RecordElasticsearchClusterDataNodesDataPoint(r.now, clusterHealth.DataNodeCount)
@@ -218,6 +398,248 @@ func (r *elasticsearchScraper) scrapeClusterMetrics(ctx context.Context, rms pda
 	default:
 		errs.AddPartial(1, fmt.Errorf("health status %s: %w", clusterHealth.Status, errUnknownClusterStatus))
 	}
+}
 
-	r.metricsBuilder.EmitClusterMetrics(ilms.Metrics())
+// emitClusterMetrics returns whether the local node has the role the cluster level metrics are scraped from,
+// or has been elected as master if the role is elected_master.
+func (r *elasticsearchScraper) emitClusterMetrics(ctx context.Context) (bool, error) {
+	local, err := r.client.NodesInfo(ctx, []string{localNode})
+	if err != nil {
+		return false, fmt.Errorf("failed to get the local node: %w", err)
+	}
+	if len(local.Nodes) != 1 {
+		return false, errLocalNodeNotFound
+	}
+	var localID string
+	var localInfo model.NodeInfo
+	for id, info := range local.Nodes {
+		localID, localInfo = id, info
+	}
+
+	if r.cfg.EmitClusterHealthFrom == electedMasterRole {
+		master, err := r.client.NodesInfo(ctx, []string{"_master"})
+		if err != nil {
+			return false, fmt.Errorf("failed to get the elected master node: %w", err)
+		}
+		_, ok := master.Nodes[localID]
+		return ok, nil
+	}
+
+	for _, role := range localInfo.Roles {
+		if role == r.cfg.EmitClusterHealthFrom {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// indexSelector returns the selector of the indices matching the indices option, directly or through one of
+// their aliases or their data stream if the plan resolves them. Every index is selected if the option is empty.
+func (r *elasticsearchScraper) indexSelector(ctx context.Context, plan requestPlan) (indexSelector, error) {
+	if !plan.resolveIndices {
+		return func(string) bool { return true }, nil
+	}
+
+	aliases, err := r.client.IndexAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve index aliases: %w", err)
+	}
+	dataStreams, err := r.client.DataStreams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve data streams: %w", err)
+	}
+
+	resolved := r.indexFilter.resolve(aliases, dataStreams)
+	return func(index string) bool {
+		return resolved[index] || r.indexFilter.matches(index)
+	}, nil
+}
+
+// scrapeILMStatus records the index lifecycle management status from the ILM status endpoint.
+func (r *elasticsearchScraper) scrapeILMStatus(ctx context.Context, errs *scrapererror.ScrapeErrors) {
+	ilmStatus, err := r.client.ILMStatus(ctx)
+	if err != nil {
+		errs.AddPartial(ilmStatusMetricCount, err)
+		return
+	}
+
+	switch ilmStatus.OperationMode {
+	case "RUNNING":
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 1, metadata.AttributeIlmStatus.Running)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Stopping)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Stopped)
+	case "STOPPING":
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Running)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 1, metadata.AttributeIlmStatus.Stopping)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Stopped)
+	case "STOPPED":
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Running)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 0, metadata.AttributeIlmStatus.Stopping)
+		r.metricsBuilder.RecordElasticsearchClusterIlmStatusDataPoint(r.now, 1, metadata.AttributeIlmStatus.Stopped)
+	default:
+		errs.AddPartial(1, fmt.Errorf("ILM operation mode %s: %w", ilmStatus.OperationMode, errUnknownILMStatus))
+	}
+}
+
+// scrapeILMIndicesErrors records the ILM errors of the selected indices from the ILM explain endpoint.
+func (r *elasticsearchScraper) scrapeILMIndicesErrors(ctx context.Context, selectIndex indexSelector, errs *scrapererror.ScrapeErrors) {
+	ilmExplain, err := r.client.ILMExplain(ctx)
+	if err != nil {
+		errs.AddPartial(ilmExplainMetricCount, err)
+		return
+	}
+
+	errorsByPolicy := map[string]int64{}
+	for indexName, index := range ilmExplain.Indices {
+		if index.Step == "ERROR" && selectIndex(indexName) {
+			errorsByPolicy[index.Policy]++
+		}
+	}
+	for policy, count := range errorsByPolicy {
+		r.metricsBuilder.RecordElasticsearchClusterIlmIndicesErrorsDataPoint(r.now, count, policy)
+	}
+}
+
+// transformStates are the states a transform can be in, in the order of the values of the transform_state attribute.
+var transformStates = []string{
+	metadata.AttributeTransformState.Started,
+	metadata.AttributeTransformState.Indexing,
+	metadata.AttributeTransformState.Aborting,
+	metadata.AttributeTransformState.Stopping,
+	metadata.AttributeTransformState.Stopped,
+	metadata.AttributeTransformState.Failed,
+}
+
+// scrapeTransformMetrics records the state and the failures of every transform from the transform stats endpoint.
+func (r *elasticsearchScraper) scrapeTransformMetrics(ctx context.Context, errs *scrapererror.ScrapeErrors) {
+	transformStats, err := r.client.TransformStats(ctx)
+	if err != nil {
+		errs.AddPartial(transformStatsMetricCount, err)
+		return
+	}
+
+	for _, transform := range transformStats.Transforms {
+		r.metricsBuilder.RecordElasticsearchClusterTransformFailuresDataPoint(r.now, transform.Stats.IndexFailures, transform.ID, metadata.AttributeTransformFailureType.Index)
+		r.metricsBuilder.RecordElasticsearchClusterTransformFailuresDataPoint(r.now, transform.Stats.SearchFailures, transform.ID, metadata.AttributeTransformFailureType.Search)
+
+		if !containsState(transformStates, transform.State) {
+			errs.AddPartial(1, fmt.Errorf("transform %s state %s: %w", transform.ID, transform.State, errUnknownTransformState))
+			continue
+		}
+		for _, state := range transformStates {
+			r.metricsBuilder.RecordElasticsearchClusterTransformStateDataPoint(r.now, boolToInt64(state == transform.State), transform.ID, state)
+		}
+	}
+}
+
+// mlJobStates are the states an anomaly detection job can be in, in the order of the values of the ml_job_state attribute.
+var mlJobStates = []string{
+	metadata.AttributeMlJobState.Opening,
+	metadata.AttributeMlJobState.Opened,
+	metadata.AttributeMlJobState.Closing,
+	metadata.AttributeMlJobState.Closed,
+	metadata.AttributeMlJobState.Failed,
+}
+
+// scrapeMLJobMetrics records the state of every anomaly detection job from the ML job stats endpoint.
+func (r *elasticsearchScraper) scrapeMLJobMetrics(ctx context.Context, errs *scrapererror.ScrapeErrors) {
+	mlJobStats, err := r.client.MLJobStats(ctx)
+	if err != nil {
+		errs.AddPartial(mlJobStatsMetricCount, err)
+		return
+	}
+
+	for _, job := range mlJobStats.Jobs {
+		if !containsState(mlJobStates, job.State) {
+			errs.AddPartial(1, fmt.Errorf("ML job %s state %s: %w", job.JobID, job.State, errUnknownMLJobState))
+			continue
+		}
+		for _, state := range mlJobStates {
+			r.metricsBuilder.RecordElasticsearchClusterMlJobStateDataPoint(r.now, boolToInt64(state == job.State), job.JobID, state)
+		}
+	}
+}
+
+// taskPriorities are the priorities of the cluster state update tasks, in the order of the values of the
+// task_priority attribute.
+var taskPriorities = []string{
+	metadata.AttributeTaskPriority.Immediate,
+	metadata.AttributeTaskPriority.Urgent,
+	metadata.AttributeTaskPriority.High,
+	metadata.AttributeTaskPriority.Normal,
+	metadata.AttributeTaskPriority.Low,
+	metadata.AttributeTaskPriority.Languid,
+}
+
+// scrapePendingTasks records the number of pending cluster state update tasks by priority, and the time the
+// oldest one has been waiting, from the pending tasks endpoint. A long wait distinguishes a slow master from
+// a burst of tasks.
+func (r *elasticsearchScraper) scrapePendingTasks(ctx context.Context, errs *scrapererror.ScrapeErrors) {
+	pendingTasks, err := r.client.PendingTasks(ctx)
+	if err != nil {
+		errs.AddPartial(pendingTasksMetricCount, err)
+		return
+	}
+
+	counts := map[string]int64{}
+	var maxAge int64
+	for _, task := range pendingTasks.Tasks {
+		if task.TimeInQueueMillis > maxAge {
+			maxAge = task.TimeInQueueMillis
+		}
+		priority := strings.ToLower(task.Priority)
+		if !containsState(taskPriorities, priority) {
+			errs.AddPartial(1, fmt.Errorf("pending task priority %s: %w", task.Priority, errUnknownTaskPriority))
+			continue
+		}
+		counts[priority]++
+	}
+	for _, priority := range taskPriorities {
+		r.metricsBuilder.RecordElasticsearchClusterPendingTasksDataPoint(r.now, counts[priority], priority)
+	}
+	r.metricsBuilder.RecordElasticsearchClusterPendingTasksMaxAgeDataPoint(r.now, maxAge)
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// scrapeShardMetrics records the metrics of every shard copy of the selected indices from the index stats endpoint.
+func (r *elasticsearchScraper) scrapeShardMetrics(ctx context.Context, selectIndex indexSelector, errs *scrapererror.ScrapeErrors) {
+	indexStats, err := r.client.IndexStats(ctx)
+	if err != nil {
+		errs.AddPartial(indexStatsMetricCount, err)
+		return
+	}
+
+	for indexName, index := range indexStats.Indices {
+		if !selectIndex(indexName) {
+			continue
+		}
+		for shardID, shards := range index.Shards {
+			for _, shard := range shards {
+				shardType := metadata.AttributeShardType.Replica
+				if shard.Routing.Primary {
+					shardType = metadata.AttributeShardType.Primary
+				}
+
+				r.metricsBuilder.RecordElasticsearchShardStoreSizeDataPoint(r.now, shard.StoreInfo.SizeInBy, indexName, shardID, shard.Routing.Node, shardType)
+
+				r.metricsBuilder.RecordElasticsearchShardDocumentsDataPoint(r.now, shard.DocumentStats.ActiveCount, indexName, shardID, shard.Routing.Node, shardType, metadata.AttributeDocumentState.Active)
+				r.metricsBuilder.RecordElasticsearchShardDocumentsDataPoint(r.now, shard.DocumentStats.DeletedCount, indexName, shardID, shard.Routing.Node, shardType, metadata.AttributeDocumentState.Deleted)
+			}
+		}
+	}
 }
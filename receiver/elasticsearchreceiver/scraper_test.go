@@ -19,10 +19,13 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -32,6 +35,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/mocks"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
 )
@@ -39,6 +43,8 @@ import (
 const fullExpectedMetricsPath = "./testdata/expected_metrics/full.json"
 const skipClusterExpectedMetricsPath = "./testdata/expected_metrics/clusterSkip.json"
 const noNodesExpectedMetricsPath = "./testdata/expected_metrics/noNodes.json"
+const shardsExpectedMetricsPath = "./testdata/expected_metrics/shards.json"
+const restrictedExpectedMetricsPath = "./testdata/expected_metrics/restricted.json"
 
 func TestScraper(t *testing.T) {
 	t.Parallel()
@@ -61,6 +67,9 @@ func TestScraper(t *testing.T) {
 	require.NoError(t, err)
 
 	requireMetricsEqual(t, expectedMetrics, actualMetrics)
+	// The ILM metrics are disabled by default.
+	mockClient.AssertNotCalled(t, "ILMStatus", mock.Anything)
+	mockClient.AssertNotCalled(t, "ILMExplain", mock.Anything)
 }
 
 func TestScraperSkipClusterMetrics(t *testing.T) {
@@ -76,6 +85,8 @@ func TestScraperSkipClusterMetrics(t *testing.T) {
 
 	mockClient := mocks.MockElasticsearchClient{}
 	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
 
 	sc.client = &mockClient
@@ -89,6 +100,35 @@ func TestScraperSkipClusterMetrics(t *testing.T) {
 	requireMetricsEqual(t, expectedMetrics, actualMetrics)
 }
 
+func TestScraperNodeFilters(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.SkipClusterMetrics = true
+	conf.Nodes = []string{}
+	conf.NodeRoles = []string{"master", "-data"}
+	conf.NodeAttributes = map[string]string{"zone": "eu-*", "rack": "r1"}
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"master:true", "data:false", "rack:r1", "zone:eu-*"}).Return(nodeStats(t), nil)
+
+	sc.client = &mockClient
+
+	expectedMetrics, err := golden.ReadMetrics(skipClusterExpectedMetricsPath)
+	require.NoError(t, err)
+
+	actualMetrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	requireMetricsEqual(t, expectedMetrics, actualMetrics)
+	mockClient.AssertExpectations(t)
+}
+
 func TestScraperNoNodesMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -102,6 +142,8 @@ func TestScraperNoNodesMetrics(t *testing.T) {
 
 	mockClient := mocks.MockElasticsearchClient{}
 	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 	mockClient.On("NodeStats", mock.Anything, []string{}).Return(nodeStats(t), nil)
 
 	sc.client = &mockClient
@@ -115,6 +157,523 @@ func TestScraperNoNodesMetrics(t *testing.T) {
 	requireMetricsEqual(t, expectedMetrics, actualMetrics)
 }
 
+func TestScraperShardMetrics(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Nodes = []string{}
+	conf.ShardMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	mockClient.On("IndexStats", mock.Anything).Return(indexStats(t), nil)
+
+	sc.client = &mockClient
+
+	expectedMetrics, err := golden.ReadMetrics(shardsExpectedMetricsPath)
+	require.NoError(t, err)
+
+	actualMetrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	requireMetricsEqual(t, expectedMetrics, actualMetrics)
+}
+
+func TestScraperIndices(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc              string
+		indices           []string
+		expectedIndices   []string
+		expectedILMErrors map[string]int64
+	}{
+		{
+			desc:              "Alias",
+			indices:           []string{"logs"},
+			expectedIndices:   []string{"logs-2022.02.01"},
+			expectedILMErrors: map[string]int64{"logs": 1},
+		},
+		{
+			desc:              "Data stream and wildcard index names",
+			indices:           []string{"metrics-app", "logs-2022.01.1*"},
+			expectedIndices:   []string{},
+			expectedILMErrors: map[string]int64{"logs": 2, "metrics": 1},
+		},
+		{
+			desc:              "Wildcard alias",
+			indices:           []string{"logs-*"},
+			expectedIndices:   []string{"logs-2022.02.01"},
+			expectedILMErrors: map[string]int64{"logs": 2},
+		},
+		{
+			desc:              "Index name",
+			indices:           []string{"metrics"},
+			expectedIndices:   []string{"metrics"},
+			expectedILMErrors: map[string]int64{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := createDefaultConfig().(*Config)
+			conf.ShardMetrics = true
+			conf.Indices = testCase.indices
+			conf.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled = true
+
+			sc := newElasticSearchScraper(zap.NewNop(), conf)
+			require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+			mockClient := mocks.MockElasticsearchClient{}
+			mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+			mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+			mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+			mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+			mockClient.On("IndexStats", mock.Anything).Return(indexStats(t), nil)
+			mockClient.On("IndexAliases", mock.Anything).Return(indexAliases(t), nil)
+			mockClient.On("DataStreams", mock.Anything).Return(dataStreams(t), nil)
+			sc.client = &mockClient
+
+			m, err := sc.scrape(context.Background())
+			require.NoError(t, err)
+
+			indices := map[string]bool{}
+			ilmErrors := map[string]int64{}
+			rms := m.ResourceMetrics()
+			for i := 0; i < rms.Len(); i++ {
+				metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+				for j := 0; j < metrics.Len(); j++ {
+					metric := metrics.At(j)
+					switch metric.Name() {
+					case "elasticsearch.shard.store.size":
+						dps := metric.Sum().DataPoints()
+						for k := 0; k < dps.Len(); k++ {
+							index, _ := dps.At(k).Attributes().Get(metadata.A.IndexName)
+							indices[index.StringVal()] = true
+						}
+					case "elasticsearch.cluster.ilm.indices.errors":
+						dps := metric.Sum().DataPoints()
+						for k := 0; k < dps.Len(); k++ {
+							policy, _ := dps.At(k).Attributes().Get(metadata.A.IlmPolicy)
+							ilmErrors[policy.StringVal()] = dps.At(k).IntVal()
+						}
+					}
+				}
+			}
+
+			expectedIndices := map[string]bool{}
+			for _, index := range testCase.expectedIndices {
+				expectedIndices[index] = true
+			}
+			require.Equal(t, expectedIndices, indices)
+			require.Equal(t, testCase.expectedILMErrors, ilmErrors)
+		})
+	}
+}
+
+func TestScraperTransformAndMLJobMetrics(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.TransformMetrics = true
+	conf.MLJobMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	mockClient.On("TransformStats", mock.Anything).Return(transformStats(t), nil)
+	mockClient.On("MLJobStats", mock.Anything).Return(mlJobStats(t), nil)
+	sc.client = &mockClient
+
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	// The value of each metric keyed by the values of its attributes.
+	values := map[string]map[string]int64{}
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			var idKey, attrKey string
+			switch metric.Name() {
+			case "elasticsearch.cluster.transform.state":
+				idKey, attrKey = metadata.A.TransformID, metadata.A.TransformState
+			case "elasticsearch.cluster.transform.failures":
+				idKey, attrKey = metadata.A.TransformID, metadata.A.TransformFailureType
+			case "elasticsearch.cluster.ml.job.state":
+				idKey, attrKey = metadata.A.MlJobID, metadata.A.MlJobState
+			default:
+				continue
+			}
+			values[metric.Name()] = map[string]int64{}
+			dps := metric.Sum().DataPoints()
+			for k := 0; k < dps.Len(); k++ {
+				id, _ := dps.At(k).Attributes().Get(idKey)
+				attr, _ := dps.At(k).Attributes().Get(attrKey)
+				values[metric.Name()][id.StringVal()+"/"+attr.StringVal()] = dps.At(k).IntVal()
+			}
+		}
+	}
+
+	require.Equal(t, map[string]map[string]int64{
+		"elasticsearch.cluster.transform.state": {
+			"ecommerce-customers/started":  1,
+			"ecommerce-customers/indexing": 0,
+			"ecommerce-customers/aborting": 0,
+			"ecommerce-customers/stopping": 0,
+			"ecommerce-customers/stopped":  0,
+			"ecommerce-customers/failed":   0,
+			"web-sessions/started":         0,
+			"web-sessions/indexing":        0,
+			"web-sessions/aborting":        0,
+			"web-sessions/stopping":        0,
+			"web-sessions/stopped":         0,
+			"web-sessions/failed":          1,
+		},
+		"elasticsearch.cluster.transform.failures": {
+			"ecommerce-customers/index":  0,
+			"ecommerce-customers/search": 2,
+			"web-sessions/index":         5,
+			"web-sessions/search":        0,
+		},
+		"elasticsearch.cluster.ml.job.state": {
+			"high-latency/opening": 0,
+			"high-latency/opened":  1,
+			"high-latency/closing": 0,
+			"high-latency/closed":  0,
+			"high-latency/failed":  0,
+			"error-rate/opening":   0,
+			"error-rate/opened":    0,
+			"error-rate/closing":   0,
+			"error-rate/closed":    0,
+			"error-rate/failed":    1,
+		},
+	}, values)
+}
+
+func TestScraperILMMetrics(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Metrics.ElasticsearchClusterIlmStatus.Enabled = true
+	conf.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	sc.client = &mockClient
+
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	statuses := map[string]int64{}
+	indicesErrors := map[string]int64{}
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			switch metric.Name() {
+			case "elasticsearch.cluster.ilm.status":
+				dps := metric.Sum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					status, _ := dps.At(k).Attributes().Get(metadata.A.IlmStatus)
+					statuses[status.StringVal()] = dps.At(k).IntVal()
+				}
+			case "elasticsearch.cluster.ilm.indices.errors":
+				dps := metric.Sum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					policy, _ := dps.At(k).Attributes().Get(metadata.A.IlmPolicy)
+					indicesErrors[policy.StringVal()] = dps.At(k).IntVal()
+				}
+			}
+		}
+	}
+
+	require.Equal(t, map[string]int64{"running": 1, "stopping": 0, "stopped": 0}, statuses)
+	require.Equal(t, map[string]int64{"logs": 2, "metrics": 1}, indicesErrors)
+}
+
+func TestScraperPendingTasks(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Metrics.ElasticsearchClusterPendingTasks.Enabled = true
+	conf.Metrics.ElasticsearchClusterPendingTasksMaxAge.Enabled = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	mockClient.On("PendingTasks", mock.Anything).Return(pendingTasks(t), nil)
+	sc.client = &mockClient
+
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	counts := map[string]int64{}
+	var maxAge []int64
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			switch metric.Name() {
+			case "elasticsearch.cluster.pending_tasks":
+				dps := metric.Sum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					priority, _ := dps.At(k).Attributes().Get(metadata.A.TaskPriority)
+					counts[priority.StringVal()] = dps.At(k).IntVal()
+				}
+			case "elasticsearch.cluster.pending_tasks.max_age":
+				dps := metric.Gauge().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					maxAge = append(maxAge, dps.At(k).IntVal())
+				}
+			}
+		}
+	}
+
+	require.Equal(t, map[string]int64{
+		"immediate": 0,
+		"urgent":    1,
+		"high":      2,
+		"normal":    1,
+		"low":       0,
+		"languid":   0,
+	}, counts)
+	require.Equal(t, []int64{4215}, maxAge)
+}
+
+func TestScraperNodeRollupMetrics(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.SkipClusterMetrics = true
+	conf.NodeRollupMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	first := nodeStats(t)
+	node := first.Nodes["szaFXm55RIeu8X-PTv5unQ"]
+	other := node
+	other.Name = "es-node-2"
+	other.JVMInfo.JVMMemoryInfo.HeapUsedInBy = 268435456
+	first.Nodes["other"] = other
+
+	// The first node completed 100 index and 50 query operations, the other node restarted.
+	second := &model.NodeStats{ClusterName: first.ClusterName, Nodes: map[string]model.NodeStatsNodesInfo{}}
+	node.Indices.IndexingOperations.IndexTotal += 100
+	node.Indices.SearchOperations.QueryTotal += 50
+	second.Nodes["szaFXm55RIeu8X-PTv5unQ"] = node
+	other.Indices.IndexingOperations.IndexTotal = 0
+	second.Nodes["other"] = other
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(first, nil).Once()
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(second, nil).Once()
+	sc.client = &mockClient
+
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{
+		"elasticsearch.cluster.nodes.jvm.memory.heap.used":        305152000 + 268435456,
+		"elasticsearch.cluster.nodes.jvm.memory.heap.max":         2 * 536870912,
+		"elasticsearch.cluster.nodes.jvm.memory.heap.utilization": (305152000.0/536870912 + 0.5) / 2,
+	}, rollupMetrics(t, m))
+
+	sc.previousNodeScrape = sc.previousNodeScrape.Add(-10 * time.Second)
+	m, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	rollup := rollupMetrics(t, m)
+	require.InDelta(t, 10, rollup["elasticsearch.cluster.nodes.operations.rate/index"], 0.1)
+	require.InDelta(t, 5, rollup["elasticsearch.cluster.nodes.operations.rate/query"], 0.1)
+}
+
+func TestScraperNodeRollupMetricsRestrictedMode(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.RestrictedMode = restrictedModeAlways
+	conf.SkipClusterMetrics = true
+	conf.NodeRollupMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("CatHealth", mock.Anything).Return(catHealth(t), nil)
+	mockClient.On("CatNodes", mock.Anything).Return(catNodes(t), nil)
+	sc.client = &mockClient
+
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{
+		"elasticsearch.cluster.nodes.jvm.memory.heap.used":        305152000 + 289128448,
+		"elasticsearch.cluster.nodes.jvm.memory.heap.max":         2 * 536870912,
+		"elasticsearch.cluster.nodes.jvm.memory.heap.utilization": (305152000.0 + 289128448.0) / 536870912 / 2,
+	}, rollupMetrics(t, m))
+
+	m, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	rollup := rollupMetrics(t, m)
+	require.Equal(t, float64(0), rollup["elasticsearch.cluster.nodes.operations.rate/index"])
+	require.Contains(t, rollup, "elasticsearch.cluster.nodes.operations.rate/query")
+}
+
+// rollupMetrics returns the values of the cluster level metrics rolled up from the node level metrics, keyed by
+// name and operation, after checking that they are emitted for the cluster.
+func rollupMetrics(t *testing.T, m pdata.Metrics) map[string]float64 {
+	values := map[string]float64{}
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			if !strings.HasPrefix(metric.Name(), "elasticsearch.cluster.nodes.") {
+				continue
+			}
+			_, ok := rms.At(i).Resource().Attributes().Get("elasticsearch.node.name")
+			require.False(t, ok, "%s is emitted for a node", metric.Name())
+			dps := metric.Gauge().DataPoints()
+			for k := 0; k < dps.Len(); k++ {
+				key := metric.Name()
+				if operation, ok := dps.At(k).Attributes().Get(metadata.A.Operation); ok {
+					key += "/" + operation.StringVal()
+				}
+				if dps.At(k).Type() == pdata.MetricValueTypeInt {
+					values[key] = float64(dps.At(k).IntVal())
+				} else {
+					values[key] = dps.At(k).DoubleVal()
+				}
+			}
+		}
+	}
+	return values
+}
+
+func TestScraperEmitClusterHealthFrom(t *testing.T) {
+	t.Parallel()
+
+	otherMaster := &model.NodesInfo{Nodes: map[string]model.NodeInfo{"other": {Name: "other", Roles: []string{"master"}}}}
+	testCases := []struct {
+		desc                 string
+		emitFrom             string
+		master               *model.NodesInfo
+		expectClusterMetrics bool
+	}{
+		{
+			desc:                 "local node has the role",
+			emitFrom:             "master",
+			expectClusterMetrics: true,
+		},
+		{
+			desc:                 "local node does not have the role",
+			emitFrom:             "voting_only",
+			expectClusterMetrics: false,
+		},
+		{
+			desc:                 "local node is the elected master",
+			emitFrom:             "elected_master",
+			master:               localNodesInfo(t),
+			expectClusterMetrics: true,
+		},
+		{
+			desc:                 "local node is not the elected master",
+			emitFrom:             "elected_master",
+			master:               otherMaster,
+			expectClusterMetrics: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := createDefaultConfig().(*Config)
+			conf.Nodes = []string{"_local"}
+			conf.EmitClusterHealthFrom = tc.emitFrom
+			require.NoError(t, conf.Validate())
+
+			sc := newElasticSearchScraper(zap.NewNop(), conf)
+			require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+			mockClient := mocks.MockElasticsearchClient{}
+			mockClient.On("NodesInfo", mock.Anything, []string{"_local"}).Return(localNodesInfo(t), nil)
+			mockClient.On("NodesInfo", mock.Anything, []string{"_master"}).Return(tc.master, nil)
+			mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+			mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+			mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+			mockClient.On("NodeStats", mock.Anything, []string{"_local"}).Return(nodeStats(t), nil)
+			sc.client = &mockClient
+
+			expectedPath := skipClusterExpectedMetricsPath
+			if tc.expectClusterMetrics {
+				expectedPath = fullExpectedMetricsPath
+			}
+			expectedMetrics, err := golden.ReadMetrics(expectedPath)
+			require.NoError(t, err)
+
+			actualMetrics, err := sc.scrape(context.Background())
+			require.NoError(t, err)
+
+			requireMetricsEqual(t, expectedMetrics, actualMetrics)
+		})
+	}
+}
+
+func TestScraperEmitClusterHealthFromError(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Nodes = []string{"_local"}
+	conf.EmitClusterHealthFrom = "master"
+	conf.TransformMetrics = true
+	conf.ShardMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	err500 := errors.New("expected status 200 but got 500")
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodesInfo", mock.Anything, []string{"_local"}).Return(nil, err500)
+	mockClient.On("NodeStats", mock.Anything, []string{"_local"}).Return(nodeStats(t), nil)
+	sc.client = &mockClient
+
+	_, err := sc.scrape(context.Background())
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	var partialErr scrapererror.PartialScrapeError
+	require.True(t, errors.As(err, &partialErr))
+	// The cluster health, transform and shard metrics are skipped.
+	require.Equal(t, 4+2+3, partialErr.Failed)
+}
+
 func TestScraperFailedStart(t *testing.T) {
 	t.Parallel()
 
@@ -153,6 +712,8 @@ func TestScrapingError(t *testing.T) {
 				mockClient := mocks.MockElasticsearchClient{}
 				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nil, err404)
 				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 
 				sc := newElasticSearchScraper(zap.NewNop(), createDefaultConfig().(*Config))
 				err := sc.start(context.Background(), componenttest.NewNopHost())
@@ -176,6 +737,8 @@ func TestScrapingError(t *testing.T) {
 				mockClient := mocks.MockElasticsearchClient{}
 				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
 				mockClient.On("ClusterHealth", mock.Anything).Return(nil, err404)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 
 				sc := newElasticSearchScraper(zap.NewNop(), createDefaultConfig().(*Config))
 				err := sc.start(context.Background(), componenttest.NewNopHost())
@@ -200,6 +763,8 @@ func TestScrapingError(t *testing.T) {
 				mockClient := mocks.MockElasticsearchClient{}
 				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nil, err500)
 				mockClient.On("ClusterHealth", mock.Anything).Return(nil, err404)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 
 				sc := newElasticSearchScraper(zap.NewNop(), createDefaultConfig().(*Config))
 				err := sc.start(context.Background(), componenttest.NewNopHost())
@@ -225,6 +790,8 @@ func TestScrapingError(t *testing.T) {
 				mockClient := mocks.MockElasticsearchClient{}
 				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
 				mockClient.On("ClusterHealth", mock.Anything).Return(ch, nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
 
 				sc := newElasticSearchScraper(zap.NewNop(), createDefaultConfig().(*Config))
 				err := sc.start(context.Background(), componenttest.NewNopHost())
@@ -237,30 +804,666 @@ func TestScrapingError(t *testing.T) {
 				require.Contains(t, err.Error(), errUnknownClusterStatus.Error())
 			},
 		},
-	}
+		{
+			desc: "ILM explain fails, but other requests succeed",
+			run: func(t *testing.T) {
+				t.Parallel()
 
-	for _, testCase := range testCases {
-		t.Run(testCase.desc, testCase.run)
-	}
-}
+				err403 := errors.New("expected status 200 but got 403")
 
-func clusterHealth(t *testing.T) *model.ClusterHealth {
-	healthJSON, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
-	require.NoError(t, err)
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(nil, err403)
 
-	clusterHealth := model.ClusterHealth{}
-	require.NoError(t, json.Unmarshal(healthJSON, &clusterHealth))
+				conf := createDefaultConfig().(*Config)
+				conf.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
 
-	return &clusterHealth
-}
+				sc.client = &mockClient
 
-func nodeStats(t *testing.T) *model.NodeStats {
-	nodeJSON, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_linux.json")
-	require.NoError(t, err)
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Equal(t, err.Error(), err403.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+		{
+			desc: "Index stats fails, but other requests succeed",
+			run: func(t *testing.T) {
+				t.Parallel()
 
-	nodeStats := model.NodeStats{}
-	require.NoError(t, json.Unmarshal(nodeJSON, &nodeStats))
-	return &nodeStats
+				err403 := errors.New("expected status 200 but got 403")
+
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+				mockClient.On("IndexStats", mock.Anything).Return(nil, err403)
+
+				conf := createDefaultConfig().(*Config)
+				conf.ShardMetrics = true
+
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Equal(t, err.Error(), err403.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+		{
+			desc: "Index aliases fail, but other requests succeed",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				err403 := errors.New("expected status 200 but got 403")
+
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("IndexAliases", mock.Anything).Return(nil, err403)
+
+				conf := createDefaultConfig().(*Config)
+				conf.ShardMetrics = true
+				conf.Indices = []string{"logs"}
+
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), err403.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+				mockClient.AssertNotCalled(t, "ILMExplain", mock.Anything)
+				mockClient.AssertNotCalled(t, "IndexStats", mock.Anything)
+			},
+		},
+		{
+			desc: "ILM operation mode is invalid",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				is := ilmStatus(t)
+				is.OperationMode = "PAUSED"
+
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(is, nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+
+				conf := createDefaultConfig().(*Config)
+				conf.Metrics.ElasticsearchClusterIlmStatus.Enabled = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				_, err = sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), errUnknownILMStatus.Error())
+			},
+		},
+		{
+			desc: "Transform and ML job states are invalid",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				ts := transformStats(t)
+				ts.Transforms[0].State = "paused"
+				ms := mlJobStats(t)
+				ms.Jobs[0].State = "paused"
+
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+				mockClient.On("TransformStats", mock.Anything).Return(ts, nil)
+				mockClient.On("MLJobStats", mock.Anything).Return(ms, nil)
+
+				conf := createDefaultConfig().(*Config)
+				conf.TransformMetrics = true
+				conf.MLJobMetrics = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), errUnknownTransformState.Error())
+				require.Contains(t, err.Error(), errUnknownMLJobState.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+		{
+			desc: "Transform and ML job stats fail, but other requests succeed",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				err403 := errors.New("expected status 200 but got 403")
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+				mockClient.On("TransformStats", mock.Anything).Return(nil, err403)
+				mockClient.On("MLJobStats", mock.Anything).Return(nil, err403)
+
+				conf := createDefaultConfig().(*Config)
+				conf.TransformMetrics = true
+				conf.MLJobMetrics = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), err403.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+		{
+			desc: "Pending task priority is invalid",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				pt := pendingTasks(t)
+				pt.Tasks[0].Priority = "CRITICAL"
+
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+				mockClient.On("PendingTasks", mock.Anything).Return(pt, nil)
+
+				conf := createDefaultConfig().(*Config)
+				conf.Metrics.ElasticsearchClusterPendingTasks.Enabled = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), errUnknownTaskPriority.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+		{
+			desc: "Pending tasks fail, but other requests succeed",
+			run: func(t *testing.T) {
+				t.Parallel()
+
+				err403 := errors.New("expected status 200 but got 403")
+				mockClient := mocks.MockElasticsearchClient{}
+				mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+				mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+				mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+				mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+				mockClient.On("PendingTasks", mock.Anything).Return(nil, err403)
+
+				conf := createDefaultConfig().(*Config)
+				conf.Metrics.ElasticsearchClusterPendingTasksMaxAge.Enabled = true
+				sc := newElasticSearchScraper(zap.NewNop(), conf)
+				err := sc.start(context.Background(), componenttest.NewNopHost())
+				require.NoError(t, err)
+
+				sc.client = &mockClient
+
+				m, err := sc.scrape(context.Background())
+				require.True(t, scrapererror.IsPartialScrapeError(err))
+				require.Contains(t, err.Error(), err403.Error())
+				require.NotEqual(t, m.DataPointCount(), 0)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.desc, testCase.run)
+	}
+}
+
+func TestScraperBackoff(t *testing.T) {
+	view.Unregister(MetricViews()...)
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	conf := createDefaultConfig().(*Config)
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	throttledClient := mocks.MockElasticsearchClient{}
+	throttledClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	throttledClient.On("ClusterHealth", mock.Anything).Return(nil, &tooManyRequestsError{})
+	sc.setClient(&throttledClient)
+
+	start := time.Now()
+	_, err := sc.scrape(context.Background())
+	require.Contains(t, err.Error(), errTooManyRequests.Error())
+	require.Equal(t, 1, sc.backoff.throttledScrapes)
+	require.True(t, sc.backoff.resumeAt.After(start.Add(conf.Backoff.InitialInterval-time.Second)))
+
+	// The next scrape is skipped without sending any request.
+	metrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, metrics.ResourceMetrics().Len())
+	throttledClient.AssertNumberOfCalls(t, "NodeStats", 1)
+
+	// The backoff interval doubles after consecutive throttled scrapes.
+	sc.backoff.resumeAt = time.Time{}
+	start = time.Now()
+	_, err = sc.scrape(context.Background())
+	require.Contains(t, err.Error(), errTooManyRequests.Error())
+	require.Equal(t, 2, sc.backoff.throttledScrapes)
+	require.True(t, sc.backoff.resumeAt.After(start.Add(2*conf.Backoff.InitialInterval-time.Second)))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	sc.setClient(&mockClient)
+
+	sc.backoff.resumeAt = time.Time{}
+	_, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, sc.backoff.throttledScrapes)
+	require.False(t, sc.backoff.skip(time.Now()))
+
+	rows, err := view.RetrieveData(statThrottledScrapes.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+
+	rows, err = view.RetrieveData(statSkippedScrapes.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestScraperBackoffNoSkip(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Backoff.SkipScrapes = false
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nil, &tooManyRequestsError{})
+	mockClient.On("ClusterHealth", mock.Anything).Return(nil, &tooManyRequestsError{})
+	sc.setClient(&mockClient)
+
+	for i := 0; i < 2; i++ {
+		_, err := sc.scrape(context.Background())
+		require.Contains(t, err.Error(), errTooManyRequests.Error())
+	}
+	mockClient.AssertNumberOfCalls(t, "NodeStats", 2)
+}
+
+func TestScraperCollectionIntervals(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.ShardMetrics = true
+	conf.Metrics.ElasticsearchClusterIlmStatus.Enabled = true
+	conf.Metrics.ElasticsearchClusterIlmIndicesErrors.Enabled = true
+	conf.CollectionIntervals.NodeStats = time.Minute
+	conf.CollectionIntervals.IndexStats = 5 * time.Minute
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("ClusterHealth", mock.Anything).Return(clusterHealth(t), nil)
+	mockClient.On("ILMStatus", mock.Anything).Return(ilmStatus(t), nil)
+	mockClient.On("ILMExplain", mock.Anything).Return(ilmExplain(t), nil)
+	mockClient.On("IndexStats", mock.Anything).Return(indexStats(t), nil)
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nodeStats(t), nil)
+	sc.client = &mockClient
+
+	// Every group is scraped by the first scrape.
+	metrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(nodeStats(t).Nodes)+1, metrics.ResourceMetrics().Len())
+
+	// Only the cluster health is scraped by the next scrape.
+	metrics, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	clusterMetrics := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < clusterMetrics.Len(); i++ {
+		require.NotContains(t, clusterMetrics.At(i).Name(), "elasticsearch.shard.")
+	}
+	mockClient.AssertNumberOfCalls(t, "ClusterHealth", 2)
+	mockClient.AssertNumberOfCalls(t, "ILMStatus", 2)
+	mockClient.AssertNumberOfCalls(t, "NodeStats", 1)
+	mockClient.AssertNumberOfCalls(t, "IndexStats", 1)
+	mockClient.AssertNumberOfCalls(t, "ILMExplain", 1)
+
+	// The node stats are scraped again once their interval elapsed.
+	sc.nodeStatsSchedule.last = sc.nodeStatsSchedule.last.Add(-time.Minute)
+	_, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	mockClient.AssertNumberOfCalls(t, "NodeStats", 2)
+	mockClient.AssertNumberOfCalls(t, "IndexStats", 1)
+
+	// The index stats are scraped with the cluster health, even if the cluster health isn't due.
+	conf.CollectionIntervals.ClusterHealth = time.Minute
+	sc.clusterHealthSchedule = newScrapeSchedule(conf.CollectionIntervals.ClusterHealth, conf.CollectionInterval)
+	sc.clusterHealthSchedule.last = time.Now()
+	sc.indexStatsSchedule.last = sc.indexStatsSchedule.last.Add(-5 * time.Minute)
+	metrics, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	mockClient.AssertNumberOfCalls(t, "ClusterHealth", 4)
+	mockClient.AssertNumberOfCalls(t, "ILMStatus", 3)
+	mockClient.AssertNumberOfCalls(t, "IndexStats", 2)
+	mockClient.AssertNumberOfCalls(t, "ILMExplain", 2)
+}
+
+func TestScraperRestrictedMode(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.RestrictedMode = restrictedModeAlways
+	conf.ShardMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("CatHealth", mock.Anything).Return(catHealth(t), nil)
+	mockClient.On("CatNodes", mock.Anything).Return(catNodes(t), nil)
+	mockClient.On("CatShards", mock.Anything).Return(catShards(t), nil)
+
+	sc.client = &mockClient
+
+	expectedMetrics, err := golden.ReadMetrics(restrictedExpectedMetricsPath)
+	require.NoError(t, err)
+
+	actualMetrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+
+	requireMetricsEqual(t, expectedMetrics, actualMetrics)
+	mockClient.AssertNotCalled(t, "NodeStats", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "ClusterHealth", mock.Anything)
+}
+
+func TestScraperRestrictedModeIndices(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.RestrictedMode = restrictedModeAlways
+	conf.ShardMetrics = true
+	conf.Indices = []string{"logs"}
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+	require.NoError(t, sc.start(context.Background(), componenttest.NewNopHost()))
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("CatHealth", mock.Anything).Return(catHealth(t), nil)
+	mockClient.On("CatNodes", mock.Anything).Return(catNodes(t), nil)
+	mockClient.On("CatShards", mock.Anything).Return(catShards(t), nil)
+	sc.client = &mockClient
+
+	// The logs alias isn't resolved, no index is named logs.
+	m, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			require.NotContains(t, metrics.At(j).Name(), "elasticsearch.shard.")
+		}
+	}
+	mockClient.AssertNotCalled(t, "IndexAliases", mock.Anything)
+	mockClient.AssertNotCalled(t, "DataStreams", mock.Anything)
+
+	// The indices are selected by name.
+	sc.indexFilter = newIndexFilter([]string{"logs-*"})
+	m, err = sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.NotZero(t, m.DataPointCount())
+	found := false
+	rms = m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			if metrics.At(j).Name() == "elasticsearch.shard.store.size" {
+				found = true
+				require.Equal(t, 2, metrics.At(j).Sum().DataPoints().Len())
+			}
+		}
+	}
+	require.True(t, found)
+	mockClient.AssertNotCalled(t, "IndexAliases", mock.Anything)
+}
+
+func TestScraperRestrictedModeFallback(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.RestrictedMode = restrictedModeFallback
+	conf.ShardMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nil, errUnauthorized)
+	mockClient.On("CatHealth", mock.Anything).Return(catHealth(t), nil)
+	mockClient.On("CatNodes", mock.Anything).Return(catNodes(t), nil)
+	mockClient.On("CatShards", mock.Anything).Return(catShards(t), nil)
+
+	sc.client = &mockClient
+
+	expectedMetrics, err := golden.ReadMetrics(restrictedExpectedMetricsPath)
+	require.NoError(t, err)
+
+	// The scrape during which the node stats are unauthorized falls back to the _cat APIs,
+	// and the following ones don't request the node stats anymore.
+	for i := 0; i < 2; i++ {
+		actualMetrics, err := sc.scrape(context.Background())
+		require.NoError(t, err)
+		requireMetricsEqual(t, expectedMetrics, actualMetrics)
+	}
+	mockClient.AssertNumberOfCalls(t, "NodeStats", 1)
+	mockClient.AssertNotCalled(t, "ClusterHealth", mock.Anything)
+}
+
+func TestScraperRestrictedModeFallbackClusterHealth(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.Nodes = []string{}
+	conf.RestrictedMode = restrictedModeFallback
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("ClusterHealth", mock.Anything).Return(nil, errUnauthorized)
+	mockClient.On("CatHealth", mock.Anything).Return(catHealth(t), nil)
+
+	sc.client = &mockClient
+
+	actualMetrics, err := sc.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, actualMetrics.ResourceMetrics().Len())
+	require.True(t, sc.useCatAPIs)
+}
+
+func TestScraperUnauthorizedWithoutRestrictedMode(t *testing.T) {
+	t.Parallel()
+
+	conf := createDefaultConfig().(*Config)
+	conf.SkipClusterMetrics = true
+
+	sc := newElasticSearchScraper(zap.NewNop(), conf)
+
+	err := sc.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	mockClient := mocks.MockElasticsearchClient{}
+	mockClient.On("NodeStats", mock.Anything, []string{"_all"}).Return(nil, errUnauthorized)
+
+	sc.client = &mockClient
+
+	_, err = sc.scrape(context.Background())
+	require.EqualError(t, err, errUnauthorized.Error())
+	require.False(t, sc.useCatAPIs)
+}
+
+func clusterHealth(t *testing.T) *model.ClusterHealth {
+	healthJSON, err := ioutil.ReadFile("./testdata/sample_payloads/health.json")
+	require.NoError(t, err)
+
+	clusterHealth := model.ClusterHealth{}
+	require.NoError(t, json.Unmarshal(healthJSON, &clusterHealth))
+
+	return &clusterHealth
+}
+
+func ilmStatus(t *testing.T) *model.ILMStatus {
+	ilmStatusJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_status.json")
+	require.NoError(t, err)
+
+	ilmStatus := model.ILMStatus{}
+	require.NoError(t, json.Unmarshal(ilmStatusJSON, &ilmStatus))
+	return &ilmStatus
+}
+
+func pendingTasks(t *testing.T) *model.PendingTasks {
+	pendingTasksJSON, err := ioutil.ReadFile("./testdata/sample_payloads/pending_tasks.json")
+	require.NoError(t, err)
+
+	pendingTasks := model.PendingTasks{}
+	require.NoError(t, json.Unmarshal(pendingTasksJSON, &pendingTasks))
+	return &pendingTasks
+}
+
+func ilmExplain(t *testing.T) *model.ILMExplain {
+	ilmExplainJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ilm_explain.json")
+	require.NoError(t, err)
+
+	ilmExplain := model.ILMExplain{}
+	require.NoError(t, json.Unmarshal(ilmExplainJSON, &ilmExplain))
+	return &ilmExplain
+}
+
+func transformStats(t *testing.T) *model.TransformStats {
+	transformStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/transform_stats.json")
+	require.NoError(t, err)
+
+	transformStats := model.TransformStats{}
+	require.NoError(t, json.Unmarshal(transformStatsJSON, &transformStats))
+	return &transformStats
+}
+
+func mlJobStats(t *testing.T) *model.MLJobStats {
+	mlJobStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/ml_job_stats.json")
+	require.NoError(t, err)
+
+	mlJobStats := model.MLJobStats{}
+	require.NoError(t, json.Unmarshal(mlJobStatsJSON, &mlJobStats))
+	return &mlJobStats
+}
+
+func localNodesInfo(t *testing.T) *model.NodesInfo {
+	nodesInfoJSON, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_info_local.json")
+	require.NoError(t, err)
+
+	nodesInfo := model.NodesInfo{}
+	require.NoError(t, json.Unmarshal(nodesInfoJSON, &nodesInfo))
+	return &nodesInfo
+}
+
+func nodeStats(t *testing.T) *model.NodeStats {
+	nodeJSON, err := ioutil.ReadFile("./testdata/sample_payloads/nodes_linux.json")
+	require.NoError(t, err)
+
+	nodeStats := model.NodeStats{}
+	require.NoError(t, json.Unmarshal(nodeJSON, &nodeStats))
+	return &nodeStats
+}
+
+func indexStats(t *testing.T) *model.IndexStats {
+	indexStatsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/index_stats.json")
+	require.NoError(t, err)
+
+	indexStats := model.IndexStats{}
+	require.NoError(t, json.Unmarshal(indexStatsJSON, &indexStats))
+	return &indexStats
+}
+
+func indexAliases(t *testing.T) model.IndexAliases {
+	aliasesJSON, err := ioutil.ReadFile("./testdata/sample_payloads/aliases.json")
+	require.NoError(t, err)
+
+	indexAliases := model.IndexAliases{}
+	require.NoError(t, json.Unmarshal(aliasesJSON, &indexAliases))
+	return indexAliases
+}
+
+func dataStreams(t *testing.T) *model.DataStreams {
+	dataStreamsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/data_streams.json")
+	require.NoError(t, err)
+
+	dataStreams := model.DataStreams{}
+	require.NoError(t, json.Unmarshal(dataStreamsJSON, &dataStreams))
+	return &dataStreams
+}
+
+func catHealth(t *testing.T) model.CatHealth {
+	catHealthJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_health.json")
+	require.NoError(t, err)
+
+	catHealth := model.CatHealth{}
+	require.NoError(t, json.Unmarshal(catHealthJSON, &catHealth))
+	return catHealth
+}
+
+func catNodes(t *testing.T) model.CatNodes {
+	catNodesJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_nodes.json")
+	require.NoError(t, err)
+
+	catNodes := model.CatNodes{}
+	require.NoError(t, json.Unmarshal(catNodesJSON, &catNodes))
+	return catNodes
+}
+
+func catShards(t *testing.T) model.CatShards {
+	catShardsJSON, err := ioutil.ReadFile("./testdata/sample_payloads/cat_shards.json")
+	require.NoError(t, err)
+
+	catShards := model.CatShards{}
+	require.NoError(t, json.Unmarshal(catShardsJSON, &catShards))
+	return catShards
 }
 
 func requireMetricsEqual(t *testing.T, m1, m2 pdata.Metrics) {
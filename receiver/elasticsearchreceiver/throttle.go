@@ -0,0 +1,225 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/elasticsearchreceiver/internal/model"
+)
+
+var errTooManyRequests = errors.New("status 429, too many requests")
+
+// tooManyRequestsError is returned when Elasticsearch rejects a request with a 429 status code,
+// e.g. because its search thread pool queue is full.
+type tooManyRequestsError struct {
+	// retryAfter is the delay requested by the Retry-After header of the response, if any.
+	retryAfter time.Duration
+}
+
+func (e *tooManyRequestsError) Error() string {
+	return errTooManyRequests.Error()
+}
+
+func (e *tooManyRequestsError) Is(target error) bool {
+	return target == errTooManyRequests
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, given either in seconds or as an HTTP date.
+// It returns 0 if the header is empty or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// backoffInterval returns the time to wait after the given number of consecutive rejections, starting at 0.
+// The interval starts at InitialInterval and doubles after every rejection, at least retryAfter, up to MaxInterval.
+func backoffInterval(cfg BackoffConfig, rejections int, retryAfter time.Duration) time.Duration {
+	interval := cfg.InitialInterval
+	for i := 0; i < rejections && interval < cfg.MaxInterval; i++ {
+		interval *= 2
+	}
+	if retryAfter > interval {
+		interval = retryAfter
+	}
+	if interval > cfg.MaxInterval {
+		interval = cfg.MaxInterval
+	}
+	return interval
+}
+
+// scrapeBackoff tracks the consecutive scrapes throttled by Elasticsearch, to skip the following scrapes
+// until the backoff interval elapsed.
+type scrapeBackoff struct {
+	cfg BackoffConfig
+	// throttledScrapes is the number of consecutive throttled scrapes.
+	throttledScrapes int
+	resumeAt         time.Time
+}
+
+// skip reports whether a scrape starting at now must be skipped.
+func (b *scrapeBackoff) skip(now time.Time) bool {
+	return now.Before(b.resumeAt)
+}
+
+// throttled records a scrape throttled at now, and returns the time to wait before the next scrape.
+func (b *scrapeBackoff) throttled(now time.Time, retryAfter time.Duration) time.Duration {
+	interval := backoffInterval(b.cfg, b.throttledScrapes, retryAfter)
+	b.throttledScrapes++
+	if b.cfg.SkipScrapes {
+		b.resumeAt = now.Add(interval)
+	}
+	return interval
+}
+
+// reset records a scrape that wasn't throttled.
+func (b *scrapeBackoff) reset() {
+	b.throttledScrapes = 0
+	b.resumeAt = time.Time{}
+}
+
+// throttleObserver wraps an elasticsearchClient to record whether any request of a scrape was
+// rejected with a 429 status code.
+type throttleObserver struct {
+	elasticsearchClient
+	// mu guards the fields below, recorded by requests made concurrently.
+	mu        sync.Mutex
+	throttled bool
+	// retryAfter is the longest delay requested by the rejected requests.
+	retryAfter time.Duration
+}
+
+var _ elasticsearchClient = (*throttleObserver)(nil)
+
+// reset clears the requests recorded by a previous scrape.
+func (o *throttleObserver) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.throttled = false
+	o.retryAfter = 0
+}
+
+func (o *throttleObserver) observe(err error) {
+	var tooManyRequests *tooManyRequestsError
+	if !errors.As(err, &tooManyRequests) {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.throttled = true
+	if tooManyRequests.retryAfter > o.retryAfter {
+		o.retryAfter = tooManyRequests.retryAfter
+	}
+}
+
+func (o *throttleObserver) NodeStats(ctx context.Context, nodes []string) (*model.NodeStats, error) {
+	nodeStats, err := o.elasticsearchClient.NodeStats(ctx, nodes)
+	o.observe(err)
+	return nodeStats, err
+}
+
+func (o *throttleObserver) NodesInfo(ctx context.Context, nodes []string) (*model.NodesInfo, error) {
+	nodesInfo, err := o.elasticsearchClient.NodesInfo(ctx, nodes)
+	o.observe(err)
+	return nodesInfo, err
+}
+
+func (o *throttleObserver) ClusterHealth(ctx context.Context) (*model.ClusterHealth, error) {
+	clusterHealth, err := o.elasticsearchClient.ClusterHealth(ctx)
+	o.observe(err)
+	return clusterHealth, err
+}
+
+func (o *throttleObserver) ILMStatus(ctx context.Context) (*model.ILMStatus, error) {
+	ilmStatus, err := o.elasticsearchClient.ILMStatus(ctx)
+	o.observe(err)
+	return ilmStatus, err
+}
+
+func (o *throttleObserver) ILMExplain(ctx context.Context) (*model.ILMExplain, error) {
+	ilmExplain, err := o.elasticsearchClient.ILMExplain(ctx)
+	o.observe(err)
+	return ilmExplain, err
+}
+
+func (o *throttleObserver) IndexStats(ctx context.Context) (*model.IndexStats, error) {
+	indexStats, err := o.elasticsearchClient.IndexStats(ctx)
+	o.observe(err)
+	return indexStats, err
+}
+
+func (o *throttleObserver) IndexAliases(ctx context.Context) (model.IndexAliases, error) {
+	indexAliases, err := o.elasticsearchClient.IndexAliases(ctx)
+	o.observe(err)
+	return indexAliases, err
+}
+
+func (o *throttleObserver) DataStreams(ctx context.Context) (*model.DataStreams, error) {
+	dataStreams, err := o.elasticsearchClient.DataStreams(ctx)
+	o.observe(err)
+	return dataStreams, err
+}
+
+func (o *throttleObserver) TransformStats(ctx context.Context) (*model.TransformStats, error) {
+	transformStats, err := o.elasticsearchClient.TransformStats(ctx)
+	o.observe(err)
+	return transformStats, err
+}
+
+func (o *throttleObserver) MLJobStats(ctx context.Context) (*model.MLJobStats, error) {
+	mlJobStats, err := o.elasticsearchClient.MLJobStats(ctx)
+	o.observe(err)
+	return mlJobStats, err
+}
+
+func (o *throttleObserver) PendingTasks(ctx context.Context) (*model.PendingTasks, error) {
+	pendingTasks, err := o.elasticsearchClient.PendingTasks(ctx)
+	o.observe(err)
+	return pendingTasks, err
+}
+
+func (o *throttleObserver) CatHealth(ctx context.Context) (model.CatHealth, error) {
+	catHealth, err := o.elasticsearchClient.CatHealth(ctx)
+	o.observe(err)
+	return catHealth, err
+}
+
+func (o *throttleObserver) CatNodes(ctx context.Context) (model.CatNodes, error) {
+	catNodes, err := o.elasticsearchClient.CatNodes(ctx)
+	o.observe(err)
+	return catNodes, err
+}
+
+func (o *throttleObserver) CatShards(ctx context.Context) (model.CatShards, error) {
+	catShards, err := o.elasticsearchClient.CatShards(ctx)
+	o.observe(err)
+	return catShards, err
+}
@@ -0,0 +1,70 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchreceiver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	require.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	require.Equal(t, 2*time.Minute, parseRetryAfter(now.Add(2*time.Minute).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestBackoffInterval(t *testing.T) {
+	t.Parallel()
+
+	cfg := BackoffConfig{InitialInterval: 10 * time.Second, MaxInterval: time.Minute}
+	require.Equal(t, 10*time.Second, backoffInterval(cfg, 0, 0))
+	require.Equal(t, 20*time.Second, backoffInterval(cfg, 1, 0))
+	require.Equal(t, 40*time.Second, backoffInterval(cfg, 2, 0))
+	require.Equal(t, time.Minute, backoffInterval(cfg, 3, 0))
+	require.Equal(t, time.Minute, backoffInterval(cfg, 1000, 0))
+	require.Equal(t, 30*time.Second, backoffInterval(cfg, 0, 30*time.Second))
+	require.Equal(t, time.Minute, backoffInterval(cfg, 0, time.Hour))
+}
+
+func TestTooManyRequestsError(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("failed to get cluster health: %w", &tooManyRequestsError{retryAfter: time.Second})
+	require.ErrorIs(t, err, errTooManyRequests)
+	require.False(t, errors.Is(errUnauthorized, errTooManyRequests))
+
+	observer := &throttleObserver{}
+	observer.observe(errUnauthorized)
+	require.False(t, observer.throttled)
+	observer.observe(err)
+	observer.observe(&tooManyRequestsError{})
+	require.True(t, observer.throttled)
+	require.Equal(t, time.Second, observer.retryAfter)
+
+	observer.reset()
+	require.False(t, observer.throttled)
+	require.Equal(t, time.Duration(0), observer.retryAfter)
+}
@@ -83,8 +83,9 @@ func newMetricSystemCPUTime(settings MetricSettings) metricSystemCPUTime {
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
-	startTime           pdata.Timestamp
-	metricSystemCPUTime metricSystemCPUTime
+	startTime                  pdata.Timestamp
+	instrumentationLibraryName string
+	metricSystemCPUTime        metricSystemCPUTime
 }
 
 // metricBuilderOption applies changes to default metrics builder.
@@ -97,10 +98,19 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/cpu" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		startTime:           pdata.NewTimestampFromTime(time.Now()),
-		metricSystemCPUTime: newMetricSystemCPUTime(settings.SystemCPUTime),
+		startTime:                  pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName: "otelcol/cpu",
+		metricSystemCPUTime:        newMetricSystemCPUTime(settings.SystemCPUTime),
 	}
 	for _, op := range options {
 		op(mb)
@@ -115,6 +125,26 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricSystemCPUTime.emit(metrics)
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // RecordSystemCPUTimeDataPoint adds a data point to system.cpu.time metric.
 func (mb *MetricsBuilder) RecordSystemCPUTimeDataPoint(ts pdata.Timestamp, val float64, cpuAttributeValue string, stateAttributeValue string) {
 	mb.metricSystemCPUTime.recordDataPoint(mb.startTime, ts, val, cpuAttributeValue, stateAttributeValue)
@@ -15,6 +15,8 @@
 package kafkametricsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver"
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
@@ -33,6 +35,10 @@ type Config struct {
 	// TopicMatch topics to collect metrics on
 	TopicMatch string `mapstructure:"topic_match"`
 
+	// TopicConfigs lists the numeric topic configs, e.g. retention.ms, collected by the topics
+	// scraper, so that config drift across clusters can be detected. No config is collected if empty.
+	TopicConfigs []string `mapstructure:"topic_configs"`
+
 	// GroupMatch consumer groups to collect on
 	GroupMatch string `mapstructure:"group_match"`
 
@@ -44,4 +50,28 @@ type Config struct {
 
 	// ClientID is the id associated with the consumer that reads from topics in kafka.
 	ClientID string `mapstructure:"client_id"`
+
+	// Offsets configures the requests listing the partition offsets of the topics and consumers scrapers.
+	Offsets OffsetsConfig `mapstructure:"offsets"`
+}
+
+// OffsetsConfig configures how the offsets of the partitions are listed. The partitions are grouped by leader,
+// so that clusters with many partitions can be scraped within the collection interval.
+type OffsetsConfig struct {
+	// BatchSize is the maximum number of partitions whose offset is listed by a single request (default 1000).
+	BatchSize int `mapstructure:"batch_size"`
+
+	// MaxConcurrentRequests is the maximum number of offset requests in flight across the brokers (default 10).
+	MaxConcurrentRequests int `mapstructure:"max_concurrent_requests"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.Offsets.BatchSize <= 0 {
+		return fmt.Errorf("offsets.batch_size has to be positive. configured value %d", cfg.Offsets.BatchSize)
+	}
+	if cfg.Offsets.MaxConcurrentRequests <= 0 {
+		return fmt.Errorf("offsets.max_concurrent_requests has to be positive. configured value %d", cfg.Offsets.MaxConcurrentRequests)
+	}
+	return nil
 }
@@ -45,6 +45,7 @@ func TestLoadConfig(t *testing.T) {
 		Brokers:                   []string{"10.10.10.10:9092"},
 		ProtocolVersion:           "2.0.0",
 		TopicMatch:                "test_\\w+",
+		TopicConfigs:              []string{"retention.ms", "min.insync.replicas"},
 		GroupMatch:                "test_\\w+",
 		Authentication: kafkaexporter.Authentication{
 			TLS: &configtls.TLSClientSetting{
@@ -57,5 +58,21 @@ func TestLoadConfig(t *testing.T) {
 		},
 		ClientID: defaultClientID,
 		Scrapers: []string{"brokers", "topics", "consumers"},
+		Offsets: OffsetsConfig{
+			BatchSize:             defaultOffsetsBatchSize,
+			MaxConcurrentRequests: 5,
+		},
 	}, r)
 }
+
+func TestValidateOffsets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Offsets.BatchSize = 0
+	assert.EqualError(t, cfg.Validate(), "offsets.batch_size has to be positive. configured value 0")
+
+	cfg.Offsets.BatchSize = 1
+	cfg.Offsets.MaxConcurrentRequests = -1
+	assert.EqualError(t, cfg.Validate(), "offsets.max_concurrent_requests has to be positive. configured value -1")
+}
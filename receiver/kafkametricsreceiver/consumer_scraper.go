@@ -36,6 +36,7 @@ type consumerScraper struct {
 	groupFilter  *regexp.Regexp
 	topicFilter  *regexp.Regexp
 	clusterAdmin sarama.ClusterAdmin
+	offsets      *offsetFetcher
 	saramaConfig *sarama.Config
 	config       Config
 }
@@ -58,6 +59,7 @@ func (s *consumerScraper) start(context.Context, component.Host) error {
 	}
 	s.client = client
 	s.clusterAdmin = clusterAdmin
+	s.offsets = newOffsetFetcher(client, s.saramaConfig, s.config.Offsets)
 	return nil
 }
 
@@ -93,26 +95,25 @@ func (s *consumerScraper) scrape(context.Context) (pdata.Metrics, error) {
 		}
 	}
 	var scrapeError error
-	// partitionIds in matchedTopics
-	topicPartitions := map[string][]int32{}
-	// currentOffset for each partition in matchedTopics
-	topicPartitionOffset := map[string]map[int32]int64{}
+	matchedPartitions := map[string][]int32{}
 	for topic := range matchedTopics {
-		topicPartitionOffset[topic] = map[int32]int64{}
 		partitions, err := s.client.Partitions(topic)
 		if err != nil {
 			scrapeError = multierr.Append(scrapeError, err)
 			continue
 		}
+		matchedPartitions[topic] = partitions
+	}
+	// currentOffset for each partition in matchedTopics
+	topicPartitionOffset, _, err := s.offsets.fetch(matchedPartitions, sarama.OffsetNewest)
+	scrapeError = multierr.Append(scrapeError, err)
+	// partitionIds in matchedTopics with a currentOffset
+	topicPartitions := map[string][]int32{}
+	for topic, partitions := range matchedPartitions {
 		for _, p := range partitions {
-			var offset int64
-			offset, err = s.client.GetOffset(topic, p, sarama.OffsetNewest)
-			if err != nil {
-				scrapeError = multierr.Append(scrapeError, err)
-				continue
+			if _, ok := topicPartitionOffset[topic][p]; ok {
+				topicPartitions[topic] = append(topicPartitions[topic], p)
 			}
-			topicPartitions[topic] = append(topicPartitions[topic], p)
-			topicPartitionOffset[topic][p] = offset
 		}
 	}
 	consumerGroups, listErr := s.clusterAdmin.DescribeConsumerGroups(matchedGrpIds)
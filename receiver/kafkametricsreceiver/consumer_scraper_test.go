@@ -130,8 +130,10 @@ func TestConsumerScraper_createScraper_handles_invalid_group_match(t *testing.T)
 
 func TestConsumerScraper_scrape(t *testing.T) {
 	filter := regexp.MustCompile(defaultGroupMatch)
+	client := newMockClient()
 	cs := consumerScraper{
-		client:       newMockClient(),
+		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		clusterAdmin: newMockClusterAdmin(),
 		topicFilter:  filter,
@@ -149,6 +151,7 @@ func TestConsumerScraper_scrape_handlesListTopicError(t *testing.T) {
 	clusterAdmin.topics = nil
 	cs := consumerScraper{
 		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		clusterAdmin: clusterAdmin,
 		topicFilter:  filter,
@@ -162,8 +165,10 @@ func TestConsumerScraper_scrape_handlesListConsumerGroupError(t *testing.T) {
 	filter := regexp.MustCompile(defaultGroupMatch)
 	clusterAdmin := newMockClusterAdmin()
 	clusterAdmin.consumerGroups = nil
+	client := newMockClient()
 	cs := consumerScraper{
-		client:       newMockClient(),
+		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		clusterAdmin: clusterAdmin,
 		topicFilter:  filter,
@@ -177,8 +182,10 @@ func TestConsumerScraper_scrape_handlesDescribeConsumerError(t *testing.T) {
 	filter := regexp.MustCompile(defaultGroupMatch)
 	clusterAdmin := newMockClusterAdmin()
 	clusterAdmin.consumerGroupDescriptions = nil
+	client := newMockClient()
 	cs := consumerScraper{
-		client:       newMockClient(),
+		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		clusterAdmin: clusterAdmin,
 		topicFilter:  filter,
@@ -196,6 +203,7 @@ func TestConsumerScraper_scrape_handlesOffsetPartialError(t *testing.T) {
 	clusterAdmin.consumerGroupOffsets = nil
 	cs := consumerScraper{
 		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		groupFilter:  filter,
 		topicFilter:  filter,
@@ -213,6 +221,7 @@ func TestConsumerScraper_scrape_handlesPartitionPartialError(t *testing.T) {
 	clusterAdmin.consumerGroupOffsets = nil
 	cs := consumerScraper{
 		client:       client,
+		offsets:      newMockOffsetFetcher(client),
 		logger:       zap.NewNop(),
 		groupFilter:  filter,
 		topicFilter:  filter,
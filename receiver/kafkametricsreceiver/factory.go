@@ -47,6 +47,10 @@ func createDefaultConfig() config.Receiver {
 		GroupMatch:                defaultGroupMatch,
 		TopicMatch:                defaultTopicMatch,
 		ClientID:                  defaultClientID,
+		Offsets: OffsetsConfig{
+			BatchSize:             defaultOffsetsBatchSize,
+			MaxConcurrentRequests: defaultOffsetsMaxConcurrentRequests,
+		},
 	}
 }
 
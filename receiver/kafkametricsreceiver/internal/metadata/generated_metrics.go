@@ -51,6 +51,7 @@ type metricStruct struct {
 	KafkaPartitionOldestOffset   MetricIntf
 	KafkaPartitionReplicas       MetricIntf
 	KafkaPartitionReplicasInSync MetricIntf
+	KafkaTopicConfig             MetricIntf
 	KafkaTopicPartitions         MetricIntf
 }
 
@@ -67,6 +68,7 @@ func (m *metricStruct) Names() []string {
 		"kafka.partition.oldest_offset",
 		"kafka.partition.replicas",
 		"kafka.partition.replicas_in_sync",
+		"kafka.topic.config",
 		"kafka.topic.partitions",
 	}
 }
@@ -82,6 +84,7 @@ var metricsByName = map[string]MetricIntf{
 	"kafka.partition.oldest_offset":    Metrics.KafkaPartitionOldestOffset,
 	"kafka.partition.replicas":         Metrics.KafkaPartitionReplicas,
 	"kafka.partition.replicas_in_sync": Metrics.KafkaPartitionReplicasInSync,
+	"kafka.topic.config":               Metrics.KafkaTopicConfig,
 	"kafka.topic.partitions":           Metrics.KafkaTopicPartitions,
 }
 
@@ -182,6 +185,15 @@ var Metrics = &metricStruct{
 			metric.SetDataType(pdata.MetricDataTypeGauge)
 		},
 	},
+	&metricImpl{
+		"kafka.topic.config",
+		func(metric pdata.Metric) {
+			metric.SetName("kafka.topic.config")
+			metric.SetDescription("Value of a numeric config of topic, collected if listed in topic_configs. Boolean configs are reported as 0 or 1.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
 	&metricImpl{
 		"kafka.topic.partitions",
 		func(metric pdata.Metric) {
@@ -199,6 +211,8 @@ var M = Metrics
 
 // Attributes contains the possible metric attributes that can be used.
 var Attributes = struct {
+	// Config (The name of a topic config, e.g. retention.ms)
+	Config string
 	// Group (The ID (string) of a consumer group)
 	Group string
 	// Partition (The number (integer) of the partition)
@@ -206,6 +220,7 @@ var Attributes = struct {
 	// Topic (The ID (integer) of a topic)
 	Topic string
 }{
+	"config",
 	"group",
 	"partition",
 	"topic",
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkametricsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver"
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/multierr"
+)
+
+const (
+	defaultOffsetsBatchSize             = 1000
+	defaultOffsetsMaxConcurrentRequests = 10
+)
+
+// offsetBroker is the part of *sarama.Broker used to list the offsets of partitions.
+type offsetBroker interface {
+	ID() int32
+	GetAvailableOffsets(*sarama.OffsetRequest) (*sarama.OffsetResponse, error)
+	Close() error
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// offsetFetcher lists the offsets of many partitions with batched requests sent to the leaders of the partitions,
+// instead of a request per partition as sarama.Client.GetOffset does, which times out on large clusters.
+type offsetFetcher struct {
+	leader  func(topic string, partition int32) (offsetBroker, error)
+	version int16
+	config  OffsetsConfig
+}
+
+func newOffsetFetcher(client sarama.Client, saramaConfig *sarama.Config, config OffsetsConfig) *offsetFetcher {
+	f := &offsetFetcher{
+		leader: func(topic string, partition int32) (offsetBroker, error) {
+			broker, err := client.Leader(topic, partition)
+			if err != nil {
+				return nil, err
+			}
+			return broker, nil
+		},
+		config: config,
+	}
+	// Same request version as sarama.Client.GetOffset.
+	if saramaConfig != nil && saramaConfig.Version.IsAtLeast(sarama.V0_10_1_0) {
+		f.version = 1
+	}
+	return f
+}
+
+// fetch returns the offsets at the given time, e.g. sarama.OffsetNewest, of the partitions of each topic.
+// The offsets which could not be listed are missing from the result, their number is returned along with the errors.
+func (f *offsetFetcher) fetch(partitions map[string][]int32, time int64) (map[string]map[int32]int64, int, error) {
+	offsets := make(map[string]map[int32]int64, len(partitions))
+	var failed int
+	var errs error
+
+	brokers := map[int32]offsetBroker{}
+	leaderPartitions := map[int32][]topicPartition{}
+	for topic, topicPartitions := range partitions {
+		offsets[topic] = make(map[int32]int64, len(topicPartitions))
+		for _, partition := range topicPartitions {
+			broker, err := f.leader(topic, partition)
+			if err != nil {
+				failed++
+				errs = multierr.Append(errs, fmt.Errorf("failed to find the leader of partition %d of topic %q: %w", partition, topic, err))
+				continue
+			}
+			brokers[broker.ID()] = broker
+			leaderPartitions[broker.ID()] = append(leaderPartitions[broker.ID()], topicPartition{topic: topic, partition: partition})
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	requests := make(chan struct{}, f.config.MaxConcurrentRequests)
+	for id, tps := range leaderPartitions {
+		for start := 0; start < len(tps); start += f.config.BatchSize {
+			end := start + f.config.BatchSize
+			if end > len(tps) {
+				end = len(tps)
+			}
+			requests <- struct{}{}
+			wg.Add(1)
+			go func(broker offsetBroker, batch []topicPartition) {
+				defer func() {
+					<-requests
+					wg.Done()
+				}()
+				batchOffsets, err := f.fetchBatch(broker, batch, time)
+				mu.Lock()
+				defer mu.Unlock()
+				for tp, offset := range batchOffsets {
+					offsets[tp.topic][tp.partition] = offset
+				}
+				failed += len(batch) - len(batchOffsets)
+				errs = multierr.Append(errs, err)
+			}(brokers[id], tps[start:end])
+		}
+	}
+	wg.Wait()
+	return offsets, failed, errs
+}
+
+// fetchBatch lists the offsets of partitions led by the broker with a single request.
+func (f *offsetFetcher) fetchBatch(broker offsetBroker, batch []topicPartition, time int64) (map[topicPartition]int64, error) {
+	request := &sarama.OffsetRequest{Version: f.version}
+	for _, tp := range batch {
+		request.AddBlock(tp.topic, tp.partition, time, 1)
+	}
+	response, err := broker.GetAvailableOffsets(request)
+	if err != nil {
+		_ = broker.Close()
+		return nil, fmt.Errorf("failed to list the offsets of %d partitions from broker %d: %w", len(batch), broker.ID(), err)
+	}
+
+	var errs error
+	offsets := make(map[topicPartition]int64, len(batch))
+	for _, tp := range batch {
+		block := response.GetBlock(tp.topic, tp.partition)
+		switch {
+		case block == nil:
+			err = sarama.ErrIncompleteResponse
+		case block.Err != sarama.ErrNoError:
+			err = block.Err
+		case len(block.Offsets) != 1:
+			err = sarama.ErrOffsetOutOfRange
+		default:
+			offsets[tp] = block.Offsets[0]
+			continue
+		}
+		errs = multierr.Append(errs, fmt.Errorf("failed to list the offset of partition %d of topic %q: %w", tp.partition, tp.topic, err))
+	}
+	return offsets, errs
+}
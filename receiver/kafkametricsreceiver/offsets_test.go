@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkametricsreceiver
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetFetcher_fetch(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	offsetResponse := sarama.NewMockOffsetResponse(t).SetVersion(1)
+	for partition := int32(0); partition < 5; partition++ {
+		offsetResponse.SetOffset(testTopic, partition, sarama.OffsetNewest, int64(100+partition))
+	}
+	metadataResponse := sarama.NewMockMetadataResponse(t).
+		SetBroker(broker.Addr(), broker.BrokerID())
+	for partition := int32(0); partition < 5; partition++ {
+		metadataResponse.SetLeader(testTopic, partition, broker.BrokerID())
+	}
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": metadataResponse,
+		"OffsetRequest":   offsetResponse,
+	})
+
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_0_0_0
+	client, err := sarama.NewClient([]string{broker.Addr()}, saramaConfig)
+	require.NoError(t, err)
+	defer client.Close()
+
+	f := newOffsetFetcher(client, saramaConfig, OffsetsConfig{BatchSize: 2, MaxConcurrentRequests: 2})
+	offsets, failed, err := f.fetch(map[string][]int32{testTopic: {0, 1, 2, 3, 4}}, sarama.OffsetNewest)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, map[string]map[int32]int64{
+		testTopic: {0: 100, 1: 101, 2: 102, 3: 103, 4: 104},
+	}, offsets)
+
+	var requests int
+	for _, rr := range broker.History() {
+		if request, ok := rr.Request.(*sarama.OffsetRequest); ok {
+			assert.EqualValues(t, 1, request.Version)
+			requests++
+		}
+	}
+	assert.Equal(t, 3, requests)
+}
+
+func TestOffsetFetcher_fetchErrors(t *testing.T) {
+	broker := &fakeOffsetBroker{
+		id: 1,
+		blocks: map[int32]*sarama.OffsetResponseBlock{
+			0: {Offsets: []int64{10}},
+			1: {Err: sarama.ErrNotLeaderForPartition},
+			// partition 2 is missing from the response.
+		},
+	}
+	f := &offsetFetcher{
+		leader: func(topic string, partition int32) (offsetBroker, error) {
+			if partition == 3 {
+				return nil, sarama.ErrLeaderNotAvailable
+			}
+			return broker, nil
+		},
+		config: OffsetsConfig{BatchSize: 10, MaxConcurrentRequests: 1},
+	}
+	offsets, failed, err := f.fetch(map[string][]int32{testTopic: {0, 1, 2, 3}}, sarama.OffsetOldest)
+	assert.Equal(t, map[string]map[int32]int64{testTopic: {0: 10}}, offsets)
+	assert.Equal(t, 3, failed)
+	assert.ErrorIs(t, err, sarama.ErrNotLeaderForPartition)
+	assert.ErrorIs(t, err, sarama.ErrIncompleteResponse)
+	assert.ErrorIs(t, err, sarama.ErrLeaderNotAvailable)
+
+	broker.err = errors.New("connection reset")
+	offsets, failed, err = f.fetch(map[string][]int32{testTopic: {0, 1, 2}}, sarama.OffsetOldest)
+	assert.Equal(t, map[string]map[int32]int64{testTopic: {}}, offsets)
+	assert.Equal(t, 3, failed)
+	assert.EqualError(t, err, `failed to list the offsets of 3 partitions from broker 1: connection reset`)
+	assert.True(t, broker.closed)
+}
+
+func TestOffsetFetcher_fetchMaxConcurrentRequests(t *testing.T) {
+	brokers := map[int32]*fakeOffsetBroker{}
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	for id := int32(0); id < 4; id++ {
+		brokers[id] = &fakeOffsetBroker{
+			id:     id,
+			blocks: map[int32]*sarama.OffsetResponseBlock{},
+			onRequest: func() func() {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+				return func() {
+					mu.Lock()
+					inFlight--
+					mu.Unlock()
+				}
+			},
+		}
+	}
+	var partitions []int32
+	for partition := int32(0); partition < 40; partition++ {
+		brokers[partition%4].blocks[partition] = &sarama.OffsetResponseBlock{Offsets: []int64{int64(partition)}}
+		partitions = append(partitions, partition)
+	}
+	f := &offsetFetcher{
+		leader: func(topic string, partition int32) (offsetBroker, error) {
+			return brokers[partition%4], nil
+		},
+		config: OffsetsConfig{BatchSize: 3, MaxConcurrentRequests: 2},
+	}
+	offsets, failed, err := f.fetch(map[string][]int32{testTopic: partitions}, sarama.OffsetNewest)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failed)
+	assert.Len(t, offsets[testTopic], 40)
+	assert.LessOrEqual(t, maxInFlight, 2)
+}
+
+type fakeOffsetBroker struct {
+	id        int32
+	blocks    map[int32]*sarama.OffsetResponseBlock
+	err       error
+	closed    bool
+	onRequest func() func()
+}
+
+func (b *fakeOffsetBroker) ID() int32 {
+	return b.id
+}
+
+func (b *fakeOffsetBroker) GetAvailableOffsets(*sarama.OffsetRequest) (*sarama.OffsetResponse, error) {
+	if b.onRequest != nil {
+		defer b.onRequest()()
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	response := &sarama.OffsetResponse{}
+	for partition, block := range b.blocks {
+		response.AddTopicPartition(testTopic, partition, 0)
+		*response.GetBlock(testTopic, partition) = *block
+	}
+	return response, nil
+}
+
+func (b *fakeOffsetBroker) Close() error {
+	b.closed = true
+	return nil
+}
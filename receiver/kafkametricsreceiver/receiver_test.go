@@ -91,3 +91,39 @@ func TestNewReceiver_handles_scraper_error(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, r)
 }
+
+func TestNewReceiver_sasl_auth(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Scrapers = []string{"brokers"}
+	c.Authentication = kafkaexporter.Authentication{
+		SASL: &kafkaexporter.SASLConfig{
+			Username:  "client-id",
+			Password:  "client-secret",
+			Mechanism: "OAUTHBEARER",
+			OAuth:     kafkaexporter.OAuthConfig{TokenURL: "https://localhost/token"},
+		},
+	}
+	var saramaConfig *sarama.Config
+	mockScraper := func(_ context.Context, _ Config, sc *sarama.Config, _ *zap.Logger) (scraperhelper.Scraper, error) {
+		saramaConfig = sc
+		return nil, nil
+	}
+	allScrapers["brokers"] = mockScraper
+	r, err := newMetricsReceiver(context.Background(), *c, componenttest.NewNopReceiverCreateSettings(), consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+	assert.True(t, saramaConfig.Net.SASL.Enable)
+	assert.Equal(t, sarama.SASLMechanism(sarama.SASLTypeOAuth), saramaConfig.Net.SASL.Mechanism)
+	assert.NotNil(t, saramaConfig.Net.SASL.TokenProvider)
+}
+
+func TestNewReceiver_invalid_sasl_error(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Authentication = kafkaexporter.Authentication{
+		SASL: &kafkaexporter.SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "OAUTHBEARER"},
+	}
+	r, err := newMetricsReceiver(context.Background(), *c, componenttest.NewNopReceiverCreateSettings(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "token_url have to be provided")
+	assert.Nil(t, r)
+}
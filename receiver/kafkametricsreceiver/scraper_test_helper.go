@@ -134,6 +134,13 @@ type mockClusterAdmin struct {
 	consumerGroups            map[string]string
 	consumerGroupDescriptions []*sarama.GroupDescription
 	consumerGroupOffsets      *sarama.OffsetFetchResponse
+	topicConfigs              []sarama.ConfigEntry
+	closed                    bool
+}
+
+func (s *mockClusterAdmin) Close() error {
+	s.closed = true
+	return nil
 }
 
 func (s *mockClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
@@ -164,6 +171,21 @@ func (s *mockClusterAdmin) ListConsumerGroupOffsets(string, map[string][]int32)
 	return s.consumerGroupOffsets, nil
 }
 
+func (s *mockClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sarama.ConfigEntry, error) {
+	if s.topicConfigs == nil {
+		return nil, fmt.Errorf("mock describe config error")
+	}
+	var entries []sarama.ConfigEntry
+	for _, entry := range s.topicConfigs {
+		for _, name := range resource.ConfigNames {
+			if entry.Name == name {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
 func newMockClusterAdmin() *mockClusterAdmin {
 	clusterAdmin := new(mockClusterAdmin)
 	r := make(map[string]string)
@@ -199,5 +221,50 @@ func newMockClusterAdmin() *mockClusterAdmin {
 	}
 	clusterAdmin.consumerGroupOffsets = &offsetRes
 
+	clusterAdmin.topicConfigs = []sarama.ConfigEntry{
+		{Name: "retention.ms", Value: "604800000"},
+		{Name: "min.insync.replicas", Value: "2"},
+		{Name: "unclean.leader.election.enable", Value: "false"},
+		{Name: "cleanup.policy", Value: "delete"},
+	}
+
 	return clusterAdmin
 }
+
+// mockOffsetBroker answers offset requests with the offset of the client for each of its topics and partitions.
+type mockOffsetBroker struct {
+	client *mockSaramaClient
+}
+
+func (b *mockOffsetBroker) ID() int32 {
+	return 0
+}
+
+func (b *mockOffsetBroker) GetAvailableOffsets(*sarama.OffsetRequest) (*sarama.OffsetResponse, error) {
+	if b.client.offset == -1 {
+		return nil, fmt.Errorf("mock offset error")
+	}
+	response := &sarama.OffsetResponse{}
+	for _, topic := range b.client.topics {
+		for _, partition := range b.client.partitions {
+			response.AddTopicPartition(topic, partition, b.client.offset)
+		}
+	}
+	return response, nil
+}
+
+func (b *mockOffsetBroker) Close() error {
+	return nil
+}
+
+func newMockOffsetFetcher(client *mockSaramaClient) *offsetFetcher {
+	return &offsetFetcher{
+		leader: func(string, int32) (offsetBroker, error) {
+			return &mockOffsetBroker{client: client}, nil
+		},
+		config: OffsetsConfig{
+			BatchSize:             defaultOffsetsBatchSize,
+			MaxConcurrentRequests: defaultOffsetsMaxConcurrentRequests,
+		},
+	}
+}
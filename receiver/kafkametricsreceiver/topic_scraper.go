@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/Shopify/sarama"
@@ -25,13 +26,17 @@ import (
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkametricsreceiver/internal/metadata"
 )
 
 type topicScraper struct {
-	client       sarama.Client
+	client sarama.Client
+	// clusterAdmin is only set if topic configs are collected.
+	clusterAdmin sarama.ClusterAdmin
+	offsets      *offsetFetcher
 	logger       *zap.Logger
 	topicFilter  *regexp.Regexp
 	saramaConfig *sarama.Config
@@ -47,15 +52,28 @@ func (s *topicScraper) start(context.Context, component.Host) error {
 	if err != nil {
 		return fmt.Errorf("failed to create client while starting topics scraper: %w", err)
 	}
+	if len(s.config.TopicConfigs) > 0 {
+		clusterAdmin, err := newClusterAdmin(s.config.Brokers, s.saramaConfig)
+		if err != nil {
+			_ = client.Close()
+			return fmt.Errorf("failed to create cluster admin while starting topics scraper: %w", err)
+		}
+		s.clusterAdmin = clusterAdmin
+	}
 	s.client = client
+	s.offsets = newOffsetFetcher(client, s.saramaConfig, s.config.Offsets)
 	return nil
 }
 
 func (s *topicScraper) shutdown(context.Context) error {
+	var err error
+	if s.clusterAdmin != nil {
+		err = s.clusterAdmin.Close()
+	}
 	if !s.client.Closed() {
-		return s.client.Close()
+		err = multierr.Append(err, s.client.Close())
 	}
-	return nil
+	return err
 }
 
 func (s *topicScraper) scrape(context.Context) (pdata.Metrics, error) {
@@ -71,6 +89,8 @@ func (s *topicScraper) scrape(context.Context) (pdata.Metrics, error) {
 	md := pdata.NewMetrics()
 	ilm := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
 	ilm.InstrumentationLibrary().SetName(instrumentationLibName)
+	var matchedTopics []string
+	topicPartitions := map[string][]int32{}
 	for _, topic := range topics {
 		if !s.topicFilter.MatchString(topic) {
 			continue
@@ -83,18 +103,30 @@ func (s *topicScraper) scrape(context.Context) (pdata.Metrics, error) {
 		labels := pdata.NewAttributeMap()
 		labels.UpsertString(metadata.A.Topic, topic)
 		addIntGauge(ilm.Metrics(), metadata.M.KafkaTopicPartitions.Name(), now, labels, int64(len(partitions)))
-		for _, partition := range partitions {
+		if s.clusterAdmin != nil {
+			s.scrapeTopicConfigs(ilm.Metrics(), now, topic, &scrapeErrors)
+		}
+		matchedTopics = append(matchedTopics, topic)
+		topicPartitions[topic] = partitions
+	}
+
+	currentOffsets, failed, err := s.offsets.fetch(topicPartitions, sarama.OffsetNewest)
+	if err != nil {
+		scrapeErrors.AddPartial(failed, err)
+	}
+	oldestOffsets, failed, err := s.offsets.fetch(topicPartitions, sarama.OffsetOldest)
+	if err != nil {
+		scrapeErrors.AddPartial(failed, err)
+	}
+	for _, topic := range matchedTopics {
+		labels := pdata.NewAttributeMap()
+		labels.UpsertString(metadata.A.Topic, topic)
+		for _, partition := range topicPartitions[topic] {
 			labels.UpsertInt(metadata.A.Partition, int64(partition))
-			currentOffset, err := s.client.GetOffset(topic, partition, sarama.OffsetNewest)
-			if err != nil {
-				scrapeErrors.AddPartial(1, err)
-			} else {
+			if currentOffset, ok := currentOffsets[topic][partition]; ok {
 				addIntGauge(ilm.Metrics(), metadata.M.KafkaPartitionCurrentOffset.Name(), now, labels, currentOffset)
 			}
-			oldestOffset, err := s.client.GetOffset(topic, partition, sarama.OffsetOldest)
-			if err != nil {
-				scrapeErrors.AddPartial(1, err)
-			} else {
+			if oldestOffset, ok := oldestOffsets[topic][partition]; ok {
 				addIntGauge(ilm.Metrics(), metadata.M.KafkaPartitionOldestOffset.Name(), now, labels, oldestOffset)
 			}
 			replicas, err := s.client.Replicas(topic, partition)
@@ -114,6 +146,44 @@ func (s *topicScraper) scrape(context.Context) (pdata.Metrics, error) {
 	return md, scrapeErrors.Combine()
 }
 
+// scrapeTopicConfigs adds the topic configs listed in TopicConfigs which are set, either explicitly or by default.
+func (s *topicScraper) scrapeTopicConfigs(ms pdata.MetricSlice, now pdata.Timestamp, topic string, scrapeErrors *scrapererror.ScrapeErrors) {
+	entries, err := s.clusterAdmin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.TopicResource,
+		Name:        topic,
+		ConfigNames: s.config.TopicConfigs,
+	})
+	if err != nil {
+		scrapeErrors.AddPartial(len(s.config.TopicConfigs), fmt.Errorf("failed to describe configs of topic %q: %w", topic, err))
+		return
+	}
+	labels := pdata.NewAttributeMap()
+	labels.UpsertString(metadata.A.Topic, topic)
+	for _, entry := range entries {
+		if entry.Sensitive {
+			continue
+		}
+		value, err := parseTopicConfigValue(entry.Value)
+		if err != nil {
+			scrapeErrors.AddPartial(1, fmt.Errorf("config %q of topic %q is not numeric: %w", entry.Name, topic, err))
+			continue
+		}
+		labels.UpsertString(metadata.A.Config, entry.Name)
+		addDoubleGauge(ms, metadata.M.KafkaTopicConfig.Name(), now, labels, value)
+	}
+}
+
+// parseTopicConfigValue parses the value of a numeric or boolean topic config.
+func parseTopicConfigValue(value string) (float64, error) {
+	switch value {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
 func createTopicsScraper(_ context.Context, cfg Config, saramaConfig *sarama.Config, logger *zap.Logger) (scraperhelper.Scraper, error) {
 	topicFilter, err := regexp.Compile(cfg.TopicMatch)
 	if err != nil {
@@ -142,3 +212,13 @@ func addIntGauge(ms pdata.MetricSlice, name string, now pdata.Timestamp, labels
 	dp.SetIntVal(value)
 	labels.CopyTo(dp.Attributes())
 }
+
+func addDoubleGauge(ms pdata.MetricSlice, name string, now pdata.Timestamp, labels pdata.AttributeMap, value float64) {
+	m := ms.AppendEmpty()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeGauge)
+	dp := m.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(now)
+	dp.SetDoubleVal(value)
+	labels.CopyTo(dp.Attributes())
+}
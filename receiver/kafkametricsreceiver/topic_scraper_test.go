@@ -111,6 +111,7 @@ func TestTopicScraper_scrapes(t *testing.T) {
 	match := regexp.MustCompile(config.TopicMatch)
 	scraper := topicScraper{
 		client:      client,
+		offsets:     newMockOffsetFetcher(client),
 		logger:      zap.NewNop(),
 		topicFilter: match,
 	}
@@ -144,6 +145,7 @@ func TestTopicScraper_scrape_handlesTopicError(t *testing.T) {
 	match := regexp.MustCompile(config.TopicMatch)
 	scraper := topicScraper{
 		client:      client,
+		offsets:     newMockOffsetFetcher(client),
 		logger:      zap.NewNop(),
 		topicFilter: match,
 	}
@@ -158,6 +160,7 @@ func TestTopicScraper_scrape_handlesPartitionError(t *testing.T) {
 	match := regexp.MustCompile(config.TopicMatch)
 	scraper := topicScraper{
 		client:      client,
+		offsets:     newMockOffsetFetcher(client),
 		logger:      zap.NewNop(),
 		topicFilter: match,
 	}
@@ -175,9 +178,97 @@ func TestTopicScraper_scrape_handlesPartialScrapeErrors(t *testing.T) {
 	match := regexp.MustCompile(config.TopicMatch)
 	scraper := topicScraper{
 		client:      client,
+		offsets:     newMockOffsetFetcher(client),
 		logger:      zap.NewNop(),
 		topicFilter: match,
 	}
 	_, err := scraper.scrape(context.Background())
 	assert.Error(t, err)
 }
+
+func TestTopicScraper_startScraperCreatesClusterAdmin(t *testing.T) {
+	newSaramaClient = mockNewSaramaClient
+	newClusterAdmin = mockNewClusterAdmin
+	scraper := topicScraper{
+		logger:       zap.NewNop(),
+		saramaConfig: sarama.NewConfig(),
+		config:       Config{TopicConfigs: []string{"retention.ms"}},
+	}
+	require.NoError(t, scraper.start(context.Background(), nil))
+	require.NotNil(t, scraper.clusterAdmin)
+
+	scraper.client.(*mockSaramaClient).Mock.On("Closed").Return(false).On("Close").Return(nil)
+	require.NoError(t, scraper.shutdown(context.Background()))
+	assert.True(t, scraper.clusterAdmin.(*mockClusterAdmin).closed)
+}
+
+func TestTopicScraper_startScraperHandlesClusterAdminError(t *testing.T) {
+	client := newMockClient()
+	client.Mock.On("Close").Return(nil)
+	newSaramaClient = func([]string, *sarama.Config) (sarama.Client, error) {
+		return client, nil
+	}
+	newClusterAdmin = func([]string, *sarama.Config) (sarama.ClusterAdmin, error) {
+		return nil, fmt.Errorf("no cluster admin here")
+	}
+	ms, err := createTopicsScraper(context.Background(), Config{TopicConfigs: []string{"retention.ms"}}, sarama.NewConfig(), zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, ms.Start(context.Background(), nil))
+	client.AssertExpectations(t)
+}
+
+func TestTopicScraper_scrapesTopicConfigs(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	client := newMockClient()
+	scraper := topicScraper{
+		client:       client,
+		offsets:      newMockOffsetFetcher(client),
+		clusterAdmin: newMockClusterAdmin(),
+		logger:       zap.NewNop(),
+		topicFilter:  regexp.MustCompile(config.TopicMatch),
+		config:       Config{TopicConfigs: []string{"retention.ms", "min.insync.replicas", "unclean.leader.election.enable"}},
+	}
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	configs := map[string]float64{}
+	ms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != metadata.M.KafkaTopicConfig.Name() {
+			continue
+		}
+		dp := m.Gauge().DataPoints().At(0)
+		topic, _ := dp.Attributes().Get(metadata.A.Topic)
+		assert.Equal(t, testTopic, topic.StringVal())
+		name, _ := dp.Attributes().Get(metadata.A.Config)
+		configs[name.StringVal()] = dp.DoubleVal()
+	}
+	assert.Equal(t, map[string]float64{
+		"retention.ms":                   604800000,
+		"min.insync.replicas":            2,
+		"unclean.leader.election.enable": 0,
+	}, configs)
+}
+
+func TestTopicScraper_scrapeTopicConfigs_handlesErrors(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	clusterAdmin := newMockClusterAdmin()
+	client := newMockClient()
+	scraper := topicScraper{
+		client:       client,
+		offsets:      newMockOffsetFetcher(client),
+		clusterAdmin: clusterAdmin,
+		logger:       zap.NewNop(),
+		topicFilter:  regexp.MustCompile(config.TopicMatch),
+		config:       Config{TopicConfigs: []string{"retention.ms", "cleanup.policy"}},
+	}
+	_, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `config "cleanup.policy" of topic "test_topic" is not numeric`)
+
+	clusterAdmin.topicConfigs = nil
+	_, err = scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to describe configs")
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const autoEncoding = "auto"
+
+// autoTracesUnmarshaler detects the encoding of every message, so that a topic written
+// to by producers using different encodings can be consumed by a single receiver. The
+// messages compressed with zstd are decompressed by the consumer beforehand.
+type autoTracesUnmarshaler struct {
+	otlpProto   TracesUnmarshaler
+	jaegerProto TracesUnmarshaler
+	jaegerJSON  TracesUnmarshaler
+	zipkinJSON  TracesUnmarshaler
+}
+
+var _ TracesUnmarshaler = (*autoTracesUnmarshaler)(nil)
+
+func (a autoTracesUnmarshaler) Unmarshal(data []byte) (pdata.Traces, error) {
+	return a.detect(data).Unmarshal(data)
+}
+
+// detect returns the unmarshaler of the encoding the message is serialized with.
+func (a autoTracesUnmarshaler) detect(data []byte) TracesUnmarshaler {
+	switch {
+	case len(data) == 0:
+		return a.otlpProto
+	case data[0] == '[':
+		// Zipkin V2 JSON is a list of spans.
+		return a.zipkinJSON
+	case data[0] == '{':
+		return a.jaegerJSON
+	case isJaegerProtoSpan(data):
+		return a.jaegerProto
+	default:
+		return a.otlpProto
+	}
+}
+
+func (a autoTracesUnmarshaler) Encoding() string {
+	return autoEncoding
+}
+
+// isJaegerProtoSpan returns whether data starts like a Jaeger proto Span: a 16 bytes trace ID
+// (field 1) followed by an 8 bytes span ID (field 2). An OTLP request rather starts with a
+// ResourceSpans (field 1) which can't be followed by a field 2.
+func isJaegerProtoSpan(data []byte) bool {
+	const traceIDLen, spanIDLen = 16, 8
+	return len(data) >= 2+traceIDLen+2 &&
+		data[0] == 0x0a && data[1] == traceIDLen &&
+		data[2+traceIDLen] == 0x12 && data[3+traceIDLen] == spanIDLen
+}
+
+// autoMetricsUnmarshaler deserializes the messages, decompressed by the consumer
+// beforehand, with the only supported metrics encoding.
+type autoMetricsUnmarshaler struct {
+	otlpProto MetricsUnmarshaler
+}
+
+var _ MetricsUnmarshaler = (*autoMetricsUnmarshaler)(nil)
+
+func (a autoMetricsUnmarshaler) Unmarshal(data []byte) (pdata.Metrics, error) {
+	return a.otlpProto.Unmarshal(data)
+}
+
+func (a autoMetricsUnmarshaler) Encoding() string {
+	return autoEncoding
+}
+
+// autoLogsUnmarshaler deserializes the messages, decompressed by the consumer
+// beforehand, with the only supported logs encoding.
+type autoLogsUnmarshaler struct {
+	otlpProto LogsUnmarshaler
+}
+
+var _ LogsUnmarshaler = (*autoLogsUnmarshaler)(nil)
+
+func (a autoLogsUnmarshaler) Unmarshal(data []byte) (pdata.Logs, error) {
+	return a.otlpProto.Unmarshal(data)
+}
+
+func (a autoLogsUnmarshaler) Encoding() string {
+	return autoEncoding
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	zipkinreporter "github.com/openzipkin/zipkin-go/reporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+
+	jaegertranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+)
+
+func TestUnmarshalAutoTraces(t *testing.T) {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "my_service")
+	span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("foo")
+	span.SetStartTimestamp(pdata.Timestamp(1597759000))
+	span.SetEndTimestamp(pdata.Timestamp(1597769000))
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	otlpBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+
+	batches, err := jaegertranslator.InternalTracesToJaegerProto(td)
+	require.NoError(t, err)
+	batches[0].Spans[0].Process = batches[0].Process
+	jaegerProtoBytes, err := batches[0].Spans[0].Marshal()
+	require.NoError(t, err)
+	jaegerJSONBytes := new(bytes.Buffer)
+	require.NoError(t, (&jsonpb.Marshaler{}).Marshal(jaegerJSONBytes, batches[0].Spans[0]))
+
+	spans, err := v2FromTranslator.FromTraces(td)
+	require.NoError(t, err)
+	zipkinJSONBytes, err := zipkinreporter.JSONSerializer{}.Serialize(spans)
+	require.NoError(t, err)
+
+	unmarshalers := defaultTracesUnmarshalers()
+	auto := unmarshalers[autoEncoding]
+	require.NotNil(t, auto)
+	assert.Equal(t, autoEncoding, auto.Encoding())
+
+	tests := []struct {
+		encoding string
+		bytes    []byte
+	}{
+		{encoding: "otlp_proto", bytes: otlpBytes},
+		{encoding: "jaeger_proto", bytes: jaegerProtoBytes},
+		{encoding: "jaeger_json", bytes: jaegerJSONBytes.Bytes()},
+		{encoding: "zipkin_json", bytes: zipkinJSONBytes},
+	}
+	for _, test := range tests {
+		t.Run(test.encoding, func(t *testing.T) {
+			expected, err := unmarshalers[test.encoding].Unmarshal(test.bytes)
+			require.NoError(t, err)
+
+			got, err := auto.Unmarshal(test.bytes)
+			require.NoError(t, err)
+			assert.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestUnmarshalAutoMetrics(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufMetricsMarshaler().MarshalMetrics(md)
+	require.NoError(t, err)
+
+	auto := defaultMetricsUnmarshalers()[autoEncoding]
+	require.NotNil(t, auto)
+	got, err := auto.Unmarshal(otlpBytes)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+}
+
+func TestUnmarshalAutoLogs(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufLogsMarshaler().MarshalLogs(ld)
+	require.NoError(t, err)
+
+	auto := defaultLogsUnmarshalers()[autoEncoding]
+	require.NotNil(t, auto)
+	got, err := auto.Unmarshal(otlpBytes)
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+}
@@ -15,6 +15,8 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -42,6 +44,52 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+type PauseOnError struct {
+	// If true, the consumption of a partition is paused while the pipeline returns errors that are
+	// not permanent, e.g. when the memory_limiter processor refuses data, and the message is consumed
+	// again once the pause is over (default disabled).
+	Enabled bool `mapstructure:"enabled"`
+	// Time to wait before consuming the message again after the first failure (default 1s).
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// The upper bound of the time to wait between attempts, it is doubled after every failure (default 30s).
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+}
+
+type RateLimit struct {
+	// The maximum number of messages consumed per second by the receiver, from all the partitions
+	// it claims (default 0, unlimited).
+	MessagesPerSecond float64 `mapstructure:"messages_per_second"`
+	// The maximum number of message bytes consumed per second by the receiver, from all the
+	// partitions it claims (default 0, unlimited).
+	BytesPerSecond int `mapstructure:"bytes_per_second"`
+}
+
+type UnmarshalErrors struct {
+	// What to do with the messages which fail to unmarshal: fail (default) stops consuming the
+	// partition until the next session of the consumer group, skip marks the message as consumed,
+	// and dead_letter publishes the message to DeadLetterTopic before skipping it. The messages are
+	// counted by the kafka_receiver_unmarshal_failed_messages metric.
+	Policy string `mapstructure:"policy"`
+	// The topic the messages are published to with the dead_letter policy, with headers recording
+	// the error and the topic, partition and offset of the message.
+	DeadLetterTopic string `mapstructure:"dead_letter_topic"`
+}
+
+type StaticAssignment struct {
+	// The partitions of the topic to consume from without joining the consumer group, e.g. to replay
+	// a range of offsets or where the consumer group coordination is forbidden. The offsets of the
+	// consumed messages are neither read from nor committed to the consumer group (default none, the
+	// partitions are assigned by the consumer group).
+	Partitions []PartitionOffset `mapstructure:"partitions"`
+}
+
+type PartitionOffset struct {
+	// The partition to consume from.
+	Partition int32 `mapstructure:"partition"`
+	// The offset of the first message to consume: oldest, newest or an offset (default oldest).
+	Offset string `mapstructure:"offset"`
+}
+
 // Config defines configuration for Kafka receiver.
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -53,6 +101,10 @@ type Config struct {
 	Topic string `mapstructure:"topic"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
+	// The maximum size of a message, before and after decompression (default 10MiB, 0 is unlimited).
+	// Larger messages are skipped by the consumer, and messages decompressed to a larger payload with
+	// the auto and otlp_proto_zstd encodings fail to unmarshal.
+	MaxMessageBytes int32 `mapstructure:"max_message_bytes"`
 	// The consumer group that receiver will be consuming messages from (default "otel-collector")
 	GroupID string `mapstructure:"group_id"`
 	// The consumer client ID that receiver will use (default "otel-collector")
@@ -64,16 +116,79 @@ type Config struct {
 
 	Authentication kafkaexporter.Authentication `mapstructure:"auth"`
 
+	// The interval at which the TLS and SASL credentials files are checked for modifications, the
+	// consumer group is reconnected with the new credentials once they are modified (default 1m, 0 disables).
+	CredentialsReloadInterval time.Duration `mapstructure:"credentials_reload_interval"`
+
 	// Controls the auto-commit functionality
 	AutoCommit AutoCommit `mapstructure:"autocommit"`
 
 	// Controls the way the messages are marked as consumed
 	MessageMarking MessageMarking `mapstructure:"message_marking"`
+
+	// Controls the pausing of partitions when the pipeline pushes back
+	PauseOnError PauseOnError `mapstructure:"pause_on_error"`
+
+	// Limits the rate at which messages are consumed, e.g. when replaying a large backlog
+	RateLimit RateLimit `mapstructure:"rate_limit"`
+
+	// Controls the handling of the messages which fail to unmarshal
+	UnmarshalErrors UnmarshalErrors `mapstructure:"unmarshal_errors"`
+
+	// If true, the traces, metrics and logs pipelines of the receiver share a single consumer group,
+	// and the messages are dispatched to the pipeline of the signal of their otlp_signal header.
+	MultiSignal bool `mapstructure:"multi_signal"`
+
+	// Assigns the partitions to consume from statically instead of joining the consumer group
+	StaticAssignment StaticAssignment `mapstructure:"static_assignment"`
 }
 
 var _ config.Receiver = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.PauseOnError.Enabled && cfg.PauseOnError.InitialInterval <= 0 {
+		return errors.New("pause_on_error.initial_interval must be positive")
+	}
+	if cfg.PauseOnError.Enabled && cfg.PauseOnError.MaxInterval < cfg.PauseOnError.InitialInterval {
+		return errors.New("pause_on_error.max_interval can not be lower than initial_interval")
+	}
+	if cfg.MaxMessageBytes < 0 {
+		return errors.New("max_message_bytes can not be negative")
+	}
+	if cfg.CredentialsReloadInterval < 0 {
+		return errors.New("credentials_reload_interval can not be negative")
+	}
+	if cfg.RateLimit.MessagesPerSecond < 0 {
+		return errors.New("rate_limit.messages_per_second can not be negative")
+	}
+	if cfg.RateLimit.BytesPerSecond < 0 {
+		return errors.New("rate_limit.bytes_per_second can not be negative")
+	}
+	switch cfg.UnmarshalErrors.Policy {
+	case "", unmarshalErrorFail, unmarshalErrorSkip:
+	case unmarshalErrorDeadLetter:
+		if cfg.UnmarshalErrors.DeadLetterTopic == "" {
+			return errors.New("unmarshal_errors.dead_letter_topic must be set with the dead_letter policy")
+		}
+		if cfg.UnmarshalErrors.DeadLetterTopic == cfg.Topic {
+			return errors.New("unmarshal_errors.dead_letter_topic can not be the consumed topic")
+		}
+	default:
+		return fmt.Errorf("unmarshal_errors.policy %q must be one of fail, skip or dead_letter", cfg.UnmarshalErrors.Policy)
+	}
+	partitions := map[int32]bool{}
+	for _, p := range cfg.StaticAssignment.Partitions {
+		if p.Partition < 0 {
+			return fmt.Errorf("static_assignment.partitions can not contain the negative partition %d", p.Partition)
+		}
+		if partitions[p.Partition] {
+			return fmt.Errorf("static_assignment.partitions contains partition %d more than once", p.Partition)
+		}
+		partitions[p.Partition] = true
+		if _, err := parseStaticOffset(p.Offset); err != nil {
+			return fmt.Errorf("static_assignment.partitions offset of partition %d: %w", p.Partition, err)
+		}
+	}
 	return nil
 }
@@ -44,6 +44,7 @@ func TestLoadConfig(t *testing.T) {
 		ReceiverSettings: config.NewReceiverSettings(config.NewComponentID(typeStr)),
 		Topic:            "spans",
 		Encoding:         "otlp_proto",
+		MaxMessageBytes:  defaultMaxMessageBytes,
 		Brokers:          []string{"foo:123", "bar:456"},
 		ClientID:         "otel-collector",
 		GroupID:          "otel-collector",
@@ -56,6 +57,7 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		CredentialsReloadInterval: time.Minute,
 		Metadata: kafkaexporter.Metadata{
 			Full: true,
 			Retry: kafkaexporter.MetadataRetry{
@@ -67,5 +69,192 @@ func TestLoadConfig(t *testing.T) {
 			Enable:   true,
 			Interval: 1 * time.Second,
 		},
+		PauseOnError: PauseOnError{
+			Enabled:         true,
+			InitialInterval: 2 * time.Second,
+			MaxInterval:     30 * time.Second,
+		},
+		RateLimit: RateLimit{
+			MessagesPerSecond: 100,
+		},
+		UnmarshalErrors: UnmarshalErrors{
+			Policy:          unmarshalErrorDeadLetter,
+			DeadLetterTopic: "spans_dlq",
+		},
 	}, r)
 }
+
+func TestValidatePauseOnError(t *testing.T) {
+	tests := []struct {
+		name    string
+		pause   PauseOnError
+		wantErr string
+	}{
+		{
+			name:  "disabled",
+			pause: PauseOnError{Enabled: false},
+		},
+		{
+			name:  "valid",
+			pause: PauseOnError{Enabled: true, InitialInterval: time.Second, MaxInterval: time.Minute},
+		},
+		{
+			name:    "no initial interval",
+			pause:   PauseOnError{Enabled: true, MaxInterval: time.Minute},
+			wantErr: "pause_on_error.initial_interval must be positive",
+		},
+		{
+			name:    "max interval lower than initial interval",
+			pause:   PauseOnError{Enabled: true, InitialInterval: time.Minute, MaxInterval: time.Second},
+			wantErr: "pause_on_error.max_interval can not be lower than initial_interval",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.PauseOnError = tt.pause
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCredentialsReloadInterval(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CredentialsReloadInterval = 0
+	assert.NoError(t, cfg.Validate())
+	cfg.CredentialsReloadInterval = -time.Second
+	assert.EqualError(t, cfg.Validate(), "credentials_reload_interval can not be negative")
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit RateLimit
+		wantErr   string
+	}{
+		{
+			name: "unlimited",
+		},
+		{
+			name:      "valid",
+			rateLimit: RateLimit{MessagesPerSecond: 0.5, BytesPerSecond: 1 << 20},
+		},
+		{
+			name:      "negative messages per second",
+			rateLimit: RateLimit{MessagesPerSecond: -1},
+			wantErr:   "rate_limit.messages_per_second can not be negative",
+		},
+		{
+			name:      "negative bytes per second",
+			rateLimit: RateLimit{BytesPerSecond: -1},
+			wantErr:   "rate_limit.bytes_per_second can not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.RateLimit = tt.rateLimit
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		unmarshalErrors UnmarshalErrors
+		wantErr         string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:            "skip",
+			unmarshalErrors: UnmarshalErrors{Policy: unmarshalErrorSkip},
+		},
+		{
+			name:            "dead letter",
+			unmarshalErrors: UnmarshalErrors{Policy: unmarshalErrorDeadLetter, DeadLetterTopic: "otlp_spans_dlq"},
+		},
+		{
+			name:            "dead letter without topic",
+			unmarshalErrors: UnmarshalErrors{Policy: unmarshalErrorDeadLetter},
+			wantErr:         "unmarshal_errors.dead_letter_topic must be set with the dead_letter policy",
+		},
+		{
+			name:            "dead letter to the consumed topic",
+			unmarshalErrors: UnmarshalErrors{Policy: unmarshalErrorDeadLetter, DeadLetterTopic: defaultTopic},
+			wantErr:         "unmarshal_errors.dead_letter_topic can not be the consumed topic",
+		},
+		{
+			name:            "unknown policy",
+			unmarshalErrors: UnmarshalErrors{Policy: "retry"},
+			wantErr:         `unmarshal_errors.policy "retry" must be one of fail, skip or dead_letter`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.UnmarshalErrors = tt.unmarshalErrors
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateStaticAssignment(t *testing.T) {
+	tests := []struct {
+		name       string
+		partitions []PartitionOffset
+		wantErr    string
+	}{
+		{
+			name: "consumer group",
+		},
+		{
+			name:       "valid",
+			partitions: []PartitionOffset{{Partition: 0}, {Partition: 1, Offset: "newest"}, {Partition: 2, Offset: "42"}},
+		},
+		{
+			name:       "negative partition",
+			partitions: []PartitionOffset{{Partition: -1}},
+			wantErr:    "static_assignment.partitions can not contain the negative partition -1",
+		},
+		{
+			name:       "duplicate partition",
+			partitions: []PartitionOffset{{Partition: 1}, {Partition: 1, Offset: "42"}},
+			wantErr:    "static_assignment.partitions contains partition 1 more than once",
+		},
+		{
+			name:       "invalid offset",
+			partitions: []PartitionOffset{{Partition: 1, Offset: "latest"}},
+			wantErr:    `static_assignment.partitions offset of partition 1: "latest" must be oldest, newest or a non-negative offset`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.StaticAssignment.Partitions = tt.partitions
+			err := cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+)
+
+// newConsumerGroup creates the consumer group of the receiver. If credentials are read from files,
+// the consumer group is reconnected with the new credentials once the files are modified.
+func newConsumerGroup(config Config, saramaConfig *sarama.Config, logger *zap.Logger) (sarama.ConsumerGroup, error) {
+	newGroup := func(c *sarama.Config) (sarama.ConsumerGroup, error) {
+		return sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	}
+	if len(config.StaticAssignment.Partitions) > 0 {
+		offsets, err := newStaticOffsets(config.StaticAssignment)
+		if err != nil {
+			return nil, err
+		}
+		newGroup = func(c *sarama.Config) (sarama.ConsumerGroup, error) {
+			return newStaticConsumerGroup(config.Brokers, c, offsets)
+		}
+	}
+	group, err := newGroup(saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	files := credentialsFiles(config.Authentication)
+	if len(files) == 0 || config.CredentialsReloadInterval <= 0 {
+		return group, nil
+	}
+	r := &reloadingConsumerGroup{
+		group:    group,
+		files:    files,
+		modTimes: modTimes(files),
+		interval: config.CredentialsReloadInterval,
+		logger:   logger,
+		newConfig: func() (*sarama.Config, error) {
+			// The settings other than the authentication are kept, the TLS certificates are loaded again.
+			c := *saramaConfig
+			if err := kafkaexporter.ConfigureAuthentication(config.Authentication, &c); err != nil {
+				return nil, err
+			}
+			return &c, nil
+		},
+		newGroup: newGroup,
+		stop:     make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.watch()
+	return r, nil
+}
+
+// credentialsFiles returns the TLS and SASL files of the authentication.
+func credentialsFiles(auth kafkaexporter.Authentication) []string {
+	var files []string
+	if auth.TLS != nil {
+		for _, file := range []string{auth.TLS.CAFile, auth.TLS.CertFile, auth.TLS.KeyFile} {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+	}
+	if auth.SASL != nil {
+		for _, file := range []string{auth.SASL.UsernameFile, auth.SASL.PasswordFile} {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+// modTimes returns the modification time of every file, the zero time if it can't be read.
+func modTimes(files []string) []time.Time {
+	times := make([]time.Time, len(files))
+	for i, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// reloadingConsumerGroup is a consumer group which is replaced by a new one, connected with the
+// reloaded credentials, once their files are modified. The brokers only authenticate connections
+// when they are opened, so rotated SCRAM passwords and short-lived certificates would otherwise
+// break the consumer group once its connections are reopened.
+type reloadingConsumerGroup struct {
+	files     []string
+	modTimes  []time.Time
+	interval  time.Duration
+	logger    *zap.Logger
+	newConfig func() (*sarama.Config, error)
+	newGroup  func(*sarama.Config) (sarama.ConsumerGroup, error)
+
+	mu    sync.Mutex
+	group sarama.ConsumerGroup
+	// cancelSession ends the session of the consumer group being replaced.
+	cancelSession context.CancelFunc
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ sarama.ConsumerGroup = (*reloadingConsumerGroup)(nil)
+
+// Consume joins the current consumer group. It returns nil once the consumer group is replaced,
+// so that the consume loop joins the new one.
+func (r *reloadingConsumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.mu.Lock()
+	group := r.group
+	r.cancelSession = cancel
+	r.mu.Unlock()
+
+	err := group.Consume(sessionCtx, topics, handler)
+	if ctx.Err() == nil && sessionCtx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (r *reloadingConsumerGroup) Errors() <-chan error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.group.Errors()
+}
+
+func (r *reloadingConsumerGroup) Close() error {
+	close(r.stop)
+	r.wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.group.Close()
+}
+
+// watch checks the credentials files every interval until the consumer group is closed.
+func (r *reloadingConsumerGroup) watch() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reloadIfModified()
+		}
+	}
+}
+
+// reloadIfModified replaces the consumer group if one of the credentials files was modified. The
+// previous consumer group is kept if the new one can't be created, e.g. while the files are being
+// written, and the reload is attempted again after the next interval.
+func (r *reloadingConsumerGroup) reloadIfModified() {
+	times := modTimes(r.files)
+	modified := false
+	for i := range times {
+		if !times[i].Equal(r.modTimes[i]) {
+			modified = true
+		}
+	}
+	if !modified {
+		return
+	}
+
+	c, err := r.newConfig()
+	if err != nil {
+		r.logger.Warn("Failed to reload the credentials, keeping the previous ones", zap.Error(err))
+		return
+	}
+	group, err := r.newGroup(c)
+	if err != nil {
+		r.logger.Warn("Failed to connect with the reloaded credentials, keeping the previous ones", zap.Error(err))
+		return
+	}
+	r.modTimes = times
+
+	r.mu.Lock()
+	previous := r.group
+	r.group = group
+	if r.cancelSession != nil {
+		r.cancelSession()
+	}
+	r.mu.Unlock()
+	if err := previous.Close(); err != nil {
+		r.logger.Warn("Failed to close the previous consumer group", zap.Error(err))
+	}
+	r.logger.Info("Reconnected the consumer group with the reloaded credentials")
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+)
+
+// blockingConsumerGroup is a consumer group whose sessions last until their context is done.
+type blockingConsumerGroup struct {
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ sarama.ConsumerGroup = (*blockingConsumerGroup)(nil)
+
+func (g *blockingConsumerGroup) Consume(ctx context.Context, _ []string, _ sarama.ConsumerGroupHandler) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (g *blockingConsumerGroup) Errors() <-chan error {
+	return nil
+}
+
+func (g *blockingConsumerGroup) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+	return nil
+}
+
+func (g *blockingConsumerGroup) isClosed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed
+}
+
+func TestCredentialsFiles(t *testing.T) {
+	assert.Empty(t, credentialsFiles(kafkaexporter.Authentication{
+		SASL: &kafkaexporter.SASLConfig{Username: "jdoe", Password: "pass"},
+	}))
+	assert.Equal(t, []string{"ca.pem", "cert.pem", "key.pem", "password"}, credentialsFiles(kafkaexporter.Authentication{
+		TLS: &configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem"},
+		},
+		SASL: &kafkaexporter.SASLConfig{Username: "jdoe", PasswordFile: "password"},
+	}))
+}
+
+func TestReloadingConsumerGroup(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass"), 0600))
+
+	previous := &blockingConsumerGroup{}
+	next := &blockingConsumerGroup{}
+	var newGroupErr error
+	newGroups := 0
+	r := &reloadingConsumerGroup{
+		group:     previous,
+		files:     []string{passwordFile},
+		modTimes:  modTimes([]string{passwordFile}),
+		logger:    zap.NewNop(),
+		newConfig: func() (*sarama.Config, error) { return sarama.NewConfig(), nil },
+		newGroup: func(*sarama.Config) (sarama.ConsumerGroup, error) {
+			newGroups++
+			if newGroupErr != nil {
+				return nil, newGroupErr
+			}
+			return next, nil
+		},
+		stop: make(chan struct{}),
+	}
+
+	consumed := make(chan error)
+	go func() {
+		consumed <- r.Consume(context.Background(), []string{"spans"}, nil)
+	}()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.cancelSession != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Nothing happens while the file isn't modified.
+	r.reloadIfModified()
+	assert.Equal(t, 0, newGroups)
+
+	// The previous consumer group is kept if the new one fails to connect.
+	rotated := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(passwordFile, rotated, rotated))
+	newGroupErr = errors.New("SASL authentication failed")
+	r.reloadIfModified()
+	assert.Equal(t, 1, newGroups)
+	assert.Same(t, sarama.ConsumerGroup(previous), r.group)
+	assert.False(t, previous.isClosed())
+
+	// The reload is attempted again, the session of the previous consumer group ends without error.
+	newGroupErr = nil
+	r.reloadIfModified()
+	assert.Equal(t, 2, newGroups)
+	assert.Same(t, sarama.ConsumerGroup(next), r.group)
+	assert.True(t, previous.isClosed())
+	select {
+	case err := <-consumed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the session of the previous consumer group did not end")
+	}
+
+	// The file is only reloaded once.
+	r.reloadIfModified()
+	assert.Equal(t, 2, newGroups)
+
+	require.NoError(t, r.Close())
+	assert.True(t, next.isClosed())
+}
+
+func TestReloadingConsumerGroupConsumeCanceled(t *testing.T) {
+	r := &reloadingConsumerGroup{group: &blockingConsumerGroup{}, stop: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, r.Consume(ctx, []string{"spans"}, nil), context.Canceled)
+}
+
+func TestReloadingConsumerGroupWatch(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("pass"), 0600))
+
+	next := &blockingConsumerGroup{}
+	r := &reloadingConsumerGroup{
+		group:     &blockingConsumerGroup{},
+		files:     []string{passwordFile},
+		modTimes:  modTimes([]string{passwordFile}),
+		interval:  10 * time.Millisecond,
+		logger:    zap.NewNop(),
+		newConfig: func() (*sarama.Config, error) { return sarama.NewConfig(), nil },
+		newGroup:  func(*sarama.Config) (sarama.ConsumerGroup, error) { return next, nil },
+		stop:      make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.watch()
+
+	rotated := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(passwordFile, rotated, rotated))
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.group == sarama.ConsumerGroup(next)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, r.Close())
+	assert.True(t, next.isClosed())
+}
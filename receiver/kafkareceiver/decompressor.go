@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/Shopify/sarama"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// contentEncodingHeader is the header recording the compression of the messages, set by the
+	// kafka exporter with the otlp_proto_zstd encoding.
+	contentEncodingHeader = "content-encoding"
+	contentEncodingZstd   = "zstd"
+	// contentEncodingIdentity is the content encoding of the messages which aren't compressed.
+	contentEncodingIdentity = "identity"
+)
+
+// zstdMagic is the magic number starting every zstd frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// decompressor decompresses the zstd messages consumed with the encodings supporting compression.
+type decompressor struct {
+	decoder  *zstd.Decoder
+	maxBytes int32
+}
+
+// newDecompressor returns the decompressor of the messages consumed with the auto and otlp_proto_zstd
+// encodings, or nil for the other encodings as their messages are never decompressed.
+func newDecompressor(config Config) (*decompressor, error) {
+	if config.Encoding != autoEncoding && config.Encoding != zstdEncoding {
+		return nil, nil
+	}
+	// DecodeAll decodes a single message with each of the decoders, so the memory used by the
+	// decompressor is bounded by the number of decoders times the maximum size of a message.
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(runtime.GOMAXPROCS(0))}
+	if config.MaxMessageBytes > 0 {
+		opts = append(opts, zstd.WithDecoderMaxMemory(uint64(config.MaxMessageBytes)))
+	}
+	decoder, err := zstd.NewReader(nil, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	return &decompressor{decoder: decoder, maxBytes: config.MaxMessageBytes}, nil
+}
+
+// decompress returns the decompressed value of the message according to its content-encoding header.
+// The value of the messages without the header is decompressed if it starts like a zstd frame, so that
+// the messages of producers not setting the header can be consumed. Payloads decompressed to more than
+// max_message_bytes are rejected.
+func (d *decompressor) decompress(message *sarama.ConsumerMessage) ([]byte, error) {
+	if d == nil {
+		return message.Value, nil
+	}
+	contentEncoding, ok := messageHeader(message, contentEncodingHeader)
+	if !ok && bytes.HasPrefix(message.Value, zstdMagic) {
+		contentEncoding = contentEncodingZstd
+	}
+	switch contentEncoding {
+	case "", contentEncodingIdentity:
+		return message.Value, nil
+	case contentEncodingZstd:
+	default:
+		return nil, fmt.Errorf("unsupported %s %q", contentEncodingHeader, contentEncoding)
+	}
+	decompressed, err := d.decoder.DecodeAll(message.Value, nil)
+	if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+		return nil, fmt.Errorf("decompressed zstd payload exceeds max_message_bytes %d", d.maxBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress zstd payload: %w", err)
+	}
+	return decompressed, nil
+}
+
+// close releases the decoders.
+func (d *decompressor) close() {
+	if d != nil {
+		d.decoder.Close()
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func compress(t *testing.T, data []byte) []byte {
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer encoder.Close()
+	return encoder.EncodeAll(data, nil)
+}
+
+func TestNewDecompressor(t *testing.T) {
+	d, err := newDecompressor(Config{Encoding: defaultEncoding})
+	require.NoError(t, err)
+	assert.Nil(t, d)
+
+	// The messages of the encodings not supporting compression are never decompressed.
+	compressed := &sarama.ConsumerMessage{Value: compress(t, []byte("foo"))}
+	value, err := d.decompress(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, compressed.Value, value)
+
+	for _, encoding := range []string{autoEncoding, zstdEncoding} {
+		d, err = newDecompressor(Config{Encoding: encoding, MaxMessageBytes: defaultMaxMessageBytes})
+		require.NoError(t, err)
+		assert.NotNil(t, d)
+		d.close()
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+
+	d, err := newDecompressor(Config{Encoding: autoEncoding, MaxMessageBytes: defaultMaxMessageBytes})
+	require.NoError(t, err)
+	defer d.close()
+
+	// messages which aren't compressed are deserialized too
+	for _, data := range [][]byte{compress(t, otlpBytes), otlpBytes} {
+		value, err := d.decompress(&sarama.ConsumerMessage{Value: data})
+		require.NoError(t, err)
+		got, err := defaultTracesUnmarshalers()[autoEncoding].Unmarshal(value)
+		require.NoError(t, err)
+		assert.Equal(t, td, got)
+	}
+}
+
+func TestDecompress_error(t *testing.T) {
+	d, err := newDecompressor(Config{Encoding: zstdEncoding, MaxMessageBytes: 1024})
+	require.NoError(t, err)
+	defer d.close()
+
+	invalid := append(append([]byte{}, zstdMagic...), []byte("+$%")...)
+	_, err = d.decompress(&sarama.ConsumerMessage{Value: invalid})
+	assert.Error(t, err)
+
+	// The payload is small once compressed, but exceeds max_message_bytes once decompressed.
+	large := compress(t, bytes.Repeat([]byte("a"), 4096))
+	require.Less(t, len(large), 1024)
+	_, err = d.decompress(&sarama.ConsumerMessage{Value: large})
+	assert.EqualError(t, err, "decompressed zstd payload exceeds max_message_bytes 1024")
+}
+
+func TestDecompressContentEncoding(t *testing.T) {
+	d, err := newDecompressor(Config{Encoding: zstdEncoding, MaxMessageBytes: defaultMaxMessageBytes})
+	require.NoError(t, err)
+	defer d.close()
+
+	header := func(value string) []*sarama.RecordHeader {
+		return []*sarama.RecordHeader{{Key: []byte(contentEncodingHeader), Value: []byte(value)}}
+	}
+	// The payload of a message which isn't compressed may start like a zstd frame.
+	zstdLike := append(append([]byte{}, zstdMagic...), []byte("foo")...)
+
+	value, err := d.decompress(&sarama.ConsumerMessage{Value: zstdLike, Headers: header(contentEncodingIdentity)})
+	require.NoError(t, err)
+	assert.Equal(t, zstdLike, value)
+
+	value, err = d.decompress(&sarama.ConsumerMessage{Value: compress(t, []byte("foo")), Headers: header(contentEncodingZstd)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo"), value)
+
+	// The header is trusted over the content of the message.
+	_, err = d.decompress(&sarama.ConsumerMessage{Value: []byte("foo"), Headers: header(contentEncodingZstd)})
+	assert.Error(t, err)
+
+	_, err = d.decompress(&sarama.ConsumerMessage{Value: []byte("foo"), Headers: header("gzip")})
+	assert.EqualError(t, err, `unsupported content-encoding "gzip"`)
+}
@@ -47,6 +47,13 @@ const (
 	defaultAutoCommitEnable = true
 	// default from sarama.NewConfig()
 	defaultAutoCommitInterval = 1 * time.Second
+
+	defaultPauseInitialInterval = 1 * time.Second
+	defaultPauseMaxInterval     = 30 * time.Second
+
+	defaultCredentialsReloadInterval = time.Minute
+
+	defaultMaxMessageBytes = 10 << 20
 )
 
 // FactoryOption applies changes to kafkaExporterFactory.
@@ -108,6 +115,7 @@ func createDefaultConfig() config.Receiver {
 		Brokers:          []string{defaultBroker},
 		ClientID:         defaultClientID,
 		GroupID:          defaultGroupID,
+		MaxMessageBytes:  defaultMaxMessageBytes,
 		Metadata: kafkaexporter.Metadata{
 			Full: defaultMetadataFull,
 			Retry: kafkaexporter.MetadataRetry{
@@ -115,6 +123,7 @@ func createDefaultConfig() config.Receiver {
 				Backoff: defaultMetadataRetryBackoff,
 			},
 		},
+		CredentialsReloadInterval: defaultCredentialsReloadInterval,
 		AutoCommit: AutoCommit{
 			Enable:   defaultAutoCommitEnable,
 			Interval: defaultAutoCommitInterval,
@@ -123,6 +132,14 @@ func createDefaultConfig() config.Receiver {
 			After:   false,
 			OnError: false,
 		},
+		PauseOnError: PauseOnError{
+			Enabled:         false,
+			InitialInterval: defaultPauseInitialInterval,
+			MaxInterval:     defaultPauseMaxInterval,
+		},
+		UnmarshalErrors: UnmarshalErrors{
+			Policy: unmarshalErrorFail,
+		},
 	}
 }
 
@@ -139,6 +156,16 @@ func (f *kafkaReceiverFactory) createTracesReceiver(
 	nextConsumer consumer.Traces,
 ) (component.TracesReceiver, error) {
 	c := cfg.(*Config)
+	if c.MultiSignal {
+		r, err := getOrCreateMultiSignalReceiver(c, set)
+		if err != nil {
+			return nil, err
+		}
+		if err = r.Unwrap().(*kafkaMultiSignalConsumer).setTracesConsumer(f.tracesUnmarshalers, nextConsumer); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
 	r, err := newTracesReceiver(*c, set, f.tracesUnmarshalers, nextConsumer)
 	if err != nil {
 		return nil, err
@@ -153,6 +180,16 @@ func (f *kafkaReceiverFactory) createMetricsReceiver(
 	nextConsumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
 	c := cfg.(*Config)
+	if c.MultiSignal {
+		r, err := getOrCreateMultiSignalReceiver(c, set)
+		if err != nil {
+			return nil, err
+		}
+		if err = r.Unwrap().(*kafkaMultiSignalConsumer).setMetricsConsumer(f.metricsUnmarshalers, nextConsumer); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
 	r, err := newMetricsReceiver(*c, set, f.metricsUnmarshalers, nextConsumer)
 	if err != nil {
 		return nil, err
@@ -167,6 +204,16 @@ func (f *kafkaReceiverFactory) createLogsReceiver(
 	nextConsumer consumer.Logs,
 ) (component.LogsReceiver, error) {
 	c := cfg.(*Config)
+	if c.MultiSignal {
+		r, err := getOrCreateMultiSignalReceiver(c, set)
+		if err != nil {
+			return nil, err
+		}
+		if err = r.Unwrap().(*kafkaMultiSignalConsumer).setLogsConsumer(f.logsUnmarshalers, nextConsumer); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
 	r, err := newLogsReceiver(*c, set, f.logsUnmarshalers, nextConsumer)
 	if err != nil {
 		return nil, err
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 
@@ -50,6 +51,15 @@ type kafkaTracesConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauseOnError      PauseOnError
+	rateLimit         RateLimit
+	unmarshalErrors   UnmarshalErrors
+
+	// brokers and saramaConfig are used to create the producer of the dead-letter topic.
+	brokers      []string
+	saramaConfig *sarama.Config
+	errorHandler *unmarshalErrorHandler
+	decompressor *decompressor
 }
 
 // kafkaMetricsConsumer uses sarama to consume and handle messages from kafka.
@@ -65,6 +75,15 @@ type kafkaMetricsConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauseOnError      PauseOnError
+	rateLimit         RateLimit
+	unmarshalErrors   UnmarshalErrors
+
+	// brokers and saramaConfig are used to create the producer of the dead-letter topic.
+	brokers      []string
+	saramaConfig *sarama.Config
+	errorHandler *unmarshalErrorHandler
+	decompressor *decompressor
 }
 
 // kafkaLogsConsumer uses sarama to consume and handle messages from kafka.
@@ -80,6 +99,15 @@ type kafkaLogsConsumer struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauseOnError      PauseOnError
+	rateLimit         RateLimit
+	unmarshalErrors   UnmarshalErrors
+
+	// brokers and saramaConfig are used to create the producer of the dead-letter topic.
+	brokers      []string
+	saramaConfig *sarama.Config
+	errorHandler *unmarshalErrorHandler
+	decompressor *decompressor
 }
 
 var _ component.Receiver = (*kafkaTracesConsumer)(nil)
@@ -97,6 +125,7 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	c.Consumer.Fetch.Max = config.MaxMessageBytes
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -107,7 +136,11 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	decompressor, err := newDecompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newConsumerGroup(config, c, set.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +153,21 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
+		pauseOnError:      config.PauseOnError,
+		rateLimit:         config.RateLimit,
+		unmarshalErrors:   config.UnmarshalErrors,
+		brokers:           config.Brokers,
+		saramaConfig:      c,
+		decompressor:      decompressor,
 	}, nil
 }
 
 func (c *kafkaTracesConsumer) Start(context.Context, component.Host) error {
+	errorHandler, err := newUnmarshalErrorHandler(c.id, c.settings.Logger, c.unmarshalErrors, c.brokers, c.saramaConfig)
+	if err != nil {
+		return err
+	}
+	c.errorHandler = errorHandler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	consumerGroup := &tracesConsumerGroupHandler{
@@ -139,6 +183,10 @@ func (c *kafkaTracesConsumer) Start(context.Context, component.Host) error {
 		}),
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
+		pauser:            partitionPauser{id: c.id, logger: c.settings.Logger, cfg: c.pauseOnError},
+		limiter:           newConsumptionLimiter(c.rateLimit),
+		unmarshalErrors:   c.errorHandler,
+		decompressor:      c.decompressor,
 	}
 	go c.consumeLoop(ctx, consumerGroup) // nolint:errcheck
 	<-consumerGroup.ready
@@ -163,7 +211,13 @@ func (c *kafkaTracesConsumer) consumeLoop(ctx context.Context, handler sarama.Co
 
 func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	err := c.consumerGroup.Close()
+	// The producer is closed once the consumption stopped.
+	if closeErr := c.errorHandler.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	c.decompressor.close()
+	return err
 }
 
 func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]MetricsUnmarshaler, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -177,6 +231,7 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	c.Consumer.Fetch.Max = config.MaxMessageBytes
 	c.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
 	c.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
 
@@ -190,7 +245,11 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	decompressor, err := newDecompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newConsumerGroup(config, c, set.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -203,10 +262,21 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
+		pauseOnError:      config.PauseOnError,
+		rateLimit:         config.RateLimit,
+		unmarshalErrors:   config.UnmarshalErrors,
+		brokers:           config.Brokers,
+		saramaConfig:      c,
+		decompressor:      decompressor,
 	}, nil
 }
 
 func (c *kafkaMetricsConsumer) Start(context.Context, component.Host) error {
+	errorHandler, err := newUnmarshalErrorHandler(c.id, c.settings.Logger, c.unmarshalErrors, c.brokers, c.saramaConfig)
+	if err != nil {
+		return err
+	}
+	c.errorHandler = errorHandler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
@@ -222,6 +292,10 @@ func (c *kafkaMetricsConsumer) Start(context.Context, component.Host) error {
 		}),
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
+		pauser:            partitionPauser{id: c.id, logger: c.settings.Logger, cfg: c.pauseOnError},
+		limiter:           newConsumptionLimiter(c.rateLimit),
+		unmarshalErrors:   c.errorHandler,
+		decompressor:      c.decompressor,
 	}
 	go c.consumeLoop(ctx, metricsConsumerGroup)
 	<-metricsConsumerGroup.ready
@@ -246,7 +320,13 @@ func (c *kafkaMetricsConsumer) consumeLoop(ctx context.Context, handler sarama.C
 
 func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	err := c.consumerGroup.Close()
+	// The producer is closed once the consumption stopped.
+	if closeErr := c.errorHandler.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	c.decompressor.close()
+	return err
 }
 
 func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]LogsUnmarshaler, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -260,6 +340,7 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	c.Consumer.Fetch.Max = config.MaxMessageBytes
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -270,7 +351,11 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	decompressor, err := newDecompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newConsumerGroup(config, c, set.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -283,10 +368,21 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 		settings:          set,
 		autocommitEnabled: config.AutoCommit.Enable,
 		messageMarking:    config.MessageMarking,
+		pauseOnError:      config.PauseOnError,
+		rateLimit:         config.RateLimit,
+		unmarshalErrors:   config.UnmarshalErrors,
+		brokers:           config.Brokers,
+		saramaConfig:      c,
+		decompressor:      decompressor,
 	}, nil
 }
 
 func (c *kafkaLogsConsumer) Start(context.Context, component.Host) error {
+	errorHandler, err := newUnmarshalErrorHandler(c.id, c.settings.Logger, c.unmarshalErrors, c.brokers, c.saramaConfig)
+	if err != nil {
+		return err
+	}
+	c.errorHandler = errorHandler
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	logsConsumerGroup := &logsConsumerGroupHandler{
@@ -302,6 +398,10 @@ func (c *kafkaLogsConsumer) Start(context.Context, component.Host) error {
 		}),
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
+		pauser:            partitionPauser{id: c.id, logger: c.settings.Logger, cfg: c.pauseOnError},
+		limiter:           newConsumptionLimiter(c.rateLimit),
+		unmarshalErrors:   c.errorHandler,
+		decompressor:      c.decompressor,
 	}
 	go c.consumeLoop(ctx, logsConsumerGroup)
 	<-logsConsumerGroup.ready
@@ -326,7 +426,13 @@ func (c *kafkaLogsConsumer) consumeLoop(ctx context.Context, handler sarama.Cons
 
 func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	err := c.consumerGroup.Close()
+	// The producer is closed once the consumption stopped.
+	if closeErr := c.errorHandler.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	c.decompressor.close()
+	return err
 }
 
 type tracesConsumerGroupHandler struct {
@@ -342,6 +448,10 @@ type tracesConsumerGroupHandler struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauser            partitionPauser
+	limiter           *consumptionLimiter
+	unmarshalErrors   *unmarshalErrorHandler
+	decompressor      *decompressor
 }
 
 type metricsConsumerGroupHandler struct {
@@ -357,6 +467,10 @@ type metricsConsumerGroupHandler struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauser            partitionPauser
+	limiter           *consumptionLimiter
+	unmarshalErrors   *unmarshalErrorHandler
+	decompressor      *decompressor
 }
 
 type logsConsumerGroupHandler struct {
@@ -372,6 +486,10 @@ type logsConsumerGroupHandler struct {
 
 	autocommitEnabled bool
 	messageMarking    MessageMarking
+	pauser            partitionPauser
+	limiter           *consumptionLimiter
+	unmarshalErrors   *unmarshalErrorHandler
+	decompressor      *decompressor
 }
 
 var _ sarama.ConsumerGroupHandler = (*tracesConsumerGroupHandler)(nil)
@@ -393,12 +511,25 @@ func (c *tracesConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession
 	return nil
 }
 
+// unmarshal deserializes the value of the message, decompressed if needed.
+func (c *tracesConsumerGroupHandler) unmarshal(message *sarama.ConsumerMessage) (pdata.Traces, error) {
+	value, err := c.decompressor.decompress(message)
+	if err != nil {
+		return pdata.NewTraces(), err
+	}
+	return c.unmarshaler.Unmarshal(value)
+}
+
 func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
 	if !c.autocommitEnabled {
 		defer session.Commit()
 	}
 	for message := range claim.Messages() {
+		if err := c.limiter.wait(session.Context(), len(message.Value)); err != nil {
+			// The session is done, the message is consumed again by the next session.
+			return nil
+		}
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
@@ -407,25 +538,31 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			session.MarkMessage(message, "")
 		}
 
-		ctx := c.obsrecv.StartTracesOp(session.Context())
 		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String())}
-		_ = stats.RecordWithTags(ctx, statsTags,
+		_ = stats.RecordWithTags(session.Context(), statsTags,
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		traces, err := c.unmarshaler.Unmarshal(message.Value)
+		traces, err := c.unmarshal(message)
 		if err != nil {
-			c.logger.Error("failed to unmarshal message", zap.Error(err))
-			if c.messageMarking.After && c.messageMarking.OnError {
-				session.MarkMessage(message, "")
+			if err = handleUnmarshalError(session, message, err, c.logger, c.unmarshalErrors, c.messageMarking, c.autocommitEnabled); err != nil {
+				return err
 			}
-			return err
+			continue
 		}
 
 		spanCount := traces.SpanCount()
-		err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
-		c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
+		err = c.pauser.consume(session, claim, func() error {
+			td := traces
+			if c.pauser.retries() {
+				td = traces.Clone()
+			}
+			ctx := c.obsrecv.StartTracesOp(session.Context())
+			err := c.nextConsumer.ConsumeTraces(session.Context(), td)
+			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
+			return err
+		})
 		if err != nil {
 			if c.messageMarking.After && c.messageMarking.OnError {
 				session.MarkMessage(message, "")
@@ -457,6 +594,15 @@ func (c *metricsConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSessio
 	return nil
 }
 
+// unmarshal deserializes the value of the message, decompressed if needed.
+func (c *metricsConsumerGroupHandler) unmarshal(message *sarama.ConsumerMessage) (pdata.Metrics, error) {
+	value, err := c.decompressor.decompress(message)
+	if err != nil {
+		return pdata.NewMetrics(), err
+	}
+	return c.unmarshaler.Unmarshal(value)
+}
+
 func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
 	if !c.autocommitEnabled {
@@ -464,6 +610,10 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 	}
 
 	for message := range claim.Messages() {
+		if err := c.limiter.wait(session.Context(), len(message.Value)); err != nil {
+			// The session is done, the message is consumed again by the next session.
+			return nil
+		}
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
@@ -472,25 +622,31 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			session.MarkMessage(message, "")
 		}
 
-		ctx := c.obsrecv.StartMetricsOp(session.Context())
 		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String())}
-		_ = stats.RecordWithTags(ctx, statsTags,
+		_ = stats.RecordWithTags(session.Context(), statsTags,
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		metrics, err := c.unmarshaler.Unmarshal(message.Value)
+		metrics, err := c.unmarshal(message)
 		if err != nil {
-			c.logger.Error("failed to unmarshal message", zap.Error(err))
-			if c.messageMarking.After && c.messageMarking.OnError {
-				session.MarkMessage(message, "")
+			if err = handleUnmarshalError(session, message, err, c.logger, c.unmarshalErrors, c.messageMarking, c.autocommitEnabled); err != nil {
+				return err
 			}
-			return err
+			continue
 		}
 
 		dataPointCount := metrics.DataPointCount()
-		err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
-		c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
+		err = c.pauser.consume(session, claim, func() error {
+			md := metrics
+			if c.pauser.retries() {
+				md = metrics.Clone()
+			}
+			ctx := c.obsrecv.StartMetricsOp(session.Context())
+			err := c.nextConsumer.ConsumeMetrics(session.Context(), md)
+			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
+			return err
+		})
 		if err != nil {
 			if c.messageMarking.After && c.messageMarking.OnError {
 				session.MarkMessage(message, "")
@@ -526,12 +682,25 @@ func (c *logsConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession)
 	return nil
 }
 
+// unmarshal deserializes the value of the message, decompressed if needed.
+func (c *logsConsumerGroupHandler) unmarshal(message *sarama.ConsumerMessage) (pdata.Logs, error) {
+	value, err := c.decompressor.decompress(message)
+	if err != nil {
+		return pdata.NewLogs(), err
+	}
+	return c.unmarshaler.Unmarshal(value)
+}
+
 func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
 	if !c.autocommitEnabled {
 		defer session.Commit()
 	}
 	for message := range claim.Messages() {
+		if err := c.limiter.wait(session.Context(), len(message.Value)); err != nil {
+			// The session is done, the message is consumed again by the next session.
+			return nil
+		}
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
@@ -540,26 +709,32 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			session.MarkMessage(message, "")
 		}
 
-		ctx := c.obsrecv.StartLogsOp(session.Context())
 		_ = stats.RecordWithTags(
-			ctx,
+			session.Context(),
 			[]tag.Mutator{tag.Insert(tagInstanceName, c.id.String())},
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		logs, err := c.unmarshaler.Unmarshal(message.Value)
+		logs, err := c.unmarshal(message)
 		if err != nil {
-			c.logger.Error("failed to unmarshal message", zap.Error(err))
-			if c.messageMarking.After && c.messageMarking.OnError {
-				session.MarkMessage(message, "")
+			if err = handleUnmarshalError(session, message, err, c.logger, c.unmarshalErrors, c.messageMarking, c.autocommitEnabled); err != nil {
+				return err
 			}
-			return err
+			continue
 		}
 
-		err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
-		// TODO
-		c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logs.LogRecordCount(), err)
+		logRecordCount := logs.LogRecordCount()
+		err = c.pauser.consume(session, claim, func() error {
+			ld := logs
+			if c.pauser.retries() {
+				ld = logs.Clone()
+			}
+			ctx := c.obsrecv.StartLogsOp(session.Context())
+			err := c.nextConsumer.ConsumeLogs(session.Context(), ld)
+			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
+			return err
+		})
 		if err != nil {
 			if c.messageMarking.After && c.messageMarking.OnError {
 				session.MarkMessage(message, "")
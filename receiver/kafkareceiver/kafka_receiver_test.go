@@ -635,6 +635,7 @@ func (t testConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
 }
 
 type testConsumerGroupSession struct {
+	ctx context.Context
 }
 
 func (t testConsumerGroupSession) Commit() {
@@ -664,6 +665,9 @@ func (t testConsumerGroupSession) ResetOffset(string, int32, int64, string) {
 func (t testConsumerGroupSession) MarkMessage(*sarama.ConsumerMessage, string) {}
 
 func (t testConsumerGroupSession) Context() context.Context {
+	if t.ctx != nil {
+		return t.ctx
+	}
 	return context.Background()
 }
 
@@ -22,6 +22,7 @@ import (
 
 var (
 	tagInstanceName, _ = tag.NewKey("name")
+	tagPolicy, _       = tag.NewKey("policy")
 
 	statMessageCount     = stats.Int64("kafka_receiver_messages", "Number of received messages", stats.UnitDimensionless)
 	statMessageOffset    = stats.Int64("kafka_receiver_current_offset", "Current message offset", stats.UnitDimensionless)
@@ -29,6 +30,11 @@ var (
 
 	statPartitionStart = stats.Int64("kafka_receiver_partition_start", "Number of started partitions", stats.UnitDimensionless)
 	statPartitionClose = stats.Int64("kafka_receiver_partition_close", "Number of finished partitions", stats.UnitDimensionless)
+
+	statPartitionPause  = stats.Int64("kafka_receiver_partition_pause", "Number of partitions paused because the pipeline returned errors", stats.UnitDimensionless)
+	statPartitionResume = stats.Int64("kafka_receiver_partition_resume", "Number of paused partitions resumed", stats.UnitDimensionless)
+
+	statUnmarshalFailed = stats.Int64("kafka_receiver_unmarshal_failed_messages", "Number of messages which failed to unmarshal", stats.UnitDimensionless)
 )
 
 // MetricViews return metric views for Kafka receiver.
@@ -75,11 +81,38 @@ func MetricViews() []*view.View {
 		Aggregation: view.Sum(),
 	}
 
+	countPartitionPause := &view.View{
+		Name:        statPartitionPause.Name(),
+		Measure:     statPartitionPause,
+		Description: statPartitionPause.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countPartitionResume := &view.View{
+		Name:        statPartitionResume.Name(),
+		Measure:     statPartitionResume,
+		Description: statPartitionResume.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countUnmarshalFailed := &view.View{
+		Name:        statUnmarshalFailed.Name(),
+		Measure:     statUnmarshalFailed,
+		Description: statUnmarshalFailed.Description(),
+		TagKeys:     []tag.Key{tagInstanceName, tagPolicy},
+		Aggregation: view.Sum(),
+	}
+
 	return []*view.View{
 		countMessages,
 		lastValueOffset,
 		lastValueOffsetLag,
 		countPartitionStart,
 		countPartitionClose,
+		countPartitionPause,
+		countPartitionResume,
+		countUnmarshalFailed,
 	}
 }
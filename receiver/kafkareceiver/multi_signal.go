@@ -0,0 +1,419 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+const (
+	// signalHeader is the header recording the signal of the messages, set by the kafka
+	// exporter with signal_header enabled.
+	signalHeader = "otlp_signal"
+
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+	signalLogs    = "logs"
+)
+
+var errMissingSignal = errors.New("message has no " + signalHeader + " header")
+
+// This is the map of the receivers created for the configurations with multi_signal enabled.
+// The factory is asked for the traces, metrics and logs receivers separately, but they must
+// share a single consumer group reading the topic.
+var multiSignalReceivers = sharedcomponent.NewSharedComponents()
+
+// kafkaMultiSignalConsumer uses sarama to consume messages of any signal from kafka, and
+// dispatches them to the consumer of their signal.
+type kafkaMultiSignalConsumer struct {
+	id                config.ComponentID
+	encoding          string
+	consumerGroup     sarama.ConsumerGroup
+	topics            []string
+	cancelConsumeLoop context.CancelFunc
+
+	tracesConsumer     consumer.Traces
+	tracesUnmarshaler  TracesUnmarshaler
+	metricsConsumer    consumer.Metrics
+	metricsUnmarshaler MetricsUnmarshaler
+	logsConsumer       consumer.Logs
+	logsUnmarshaler    LogsUnmarshaler
+
+	settings component.ReceiverCreateSettings
+
+	autocommitEnabled bool
+	messageMarking    MessageMarking
+	pauseOnError      PauseOnError
+	rateLimit         RateLimit
+	unmarshalErrors   UnmarshalErrors
+
+	// brokers and saramaConfig are used to create the producer of the dead-letter topic.
+	brokers      []string
+	saramaConfig *sarama.Config
+	errorHandler *unmarshalErrorHandler
+	decompressor *decompressor
+}
+
+var _ component.Receiver = (*kafkaMultiSignalConsumer)(nil)
+
+// getOrCreateMultiSignalReceiver returns the receiver shared by the pipelines using config.
+func getOrCreateMultiSignalReceiver(config *Config, set component.ReceiverCreateSettings) (*sharedcomponent.SharedComponent, error) {
+	var err error
+	r := multiSignalReceivers.GetOrAdd(config, func() component.Component {
+		var recv *kafkaMultiSignalConsumer
+		recv, err = newMultiSignalReceiver(*config, set)
+		return recv
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newMultiSignalReceiver(config Config, set component.ReceiverCreateSettings) (*kafkaMultiSignalConsumer, error) {
+	c := sarama.NewConfig()
+	c.ClientID = config.ClientID
+	c.Metadata.Full = config.Metadata.Full
+	c.Metadata.Retry.Max = config.Metadata.Retry.Max
+	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	c.Consumer.Fetch.Max = config.MaxMessageBytes
+	c.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
+	c.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
+
+	if config.ProtocolVersion != "" {
+		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
+		if err != nil {
+			return nil, err
+		}
+		c.Version = version
+	}
+	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
+		return nil, err
+	}
+	decompressor, err := newDecompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newConsumerGroup(config, c, set.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaMultiSignalConsumer{
+		id:                config.ID(),
+		encoding:          config.Encoding,
+		consumerGroup:     client,
+		topics:            []string{config.Topic},
+		settings:          set,
+		autocommitEnabled: config.AutoCommit.Enable,
+		messageMarking:    config.MessageMarking,
+		pauseOnError:      config.PauseOnError,
+		rateLimit:         config.RateLimit,
+		unmarshalErrors:   config.UnmarshalErrors,
+		brokers:           config.Brokers,
+		saramaConfig:      c,
+		decompressor:      decompressor,
+	}, nil
+}
+
+func (c *kafkaMultiSignalConsumer) setTracesConsumer(unmarshalers map[string]TracesUnmarshaler, nextConsumer consumer.Traces) error {
+	unmarshaler := unmarshalers[c.encoding]
+	if unmarshaler == nil {
+		return errUnrecognizedEncoding
+	}
+	c.tracesUnmarshaler = unmarshaler
+	c.tracesConsumer = nextConsumer
+	return nil
+}
+
+func (c *kafkaMultiSignalConsumer) setMetricsConsumer(unmarshalers map[string]MetricsUnmarshaler, nextConsumer consumer.Metrics) error {
+	unmarshaler := unmarshalers[c.encoding]
+	if unmarshaler == nil {
+		return errUnrecognizedEncoding
+	}
+	c.metricsUnmarshaler = unmarshaler
+	c.metricsConsumer = nextConsumer
+	return nil
+}
+
+func (c *kafkaMultiSignalConsumer) setLogsConsumer(unmarshalers map[string]LogsUnmarshaler, nextConsumer consumer.Logs) error {
+	unmarshaler := unmarshalers[c.encoding]
+	if unmarshaler == nil {
+		return errUnrecognizedEncoding
+	}
+	c.logsUnmarshaler = unmarshaler
+	c.logsConsumer = nextConsumer
+	return nil
+}
+
+func (c *kafkaMultiSignalConsumer) Start(context.Context, component.Host) error {
+	errorHandler, err := newUnmarshalErrorHandler(c.id, c.settings.Logger, c.unmarshalErrors, c.brokers, c.saramaConfig)
+	if err != nil {
+		return err
+	}
+	c.errorHandler = errorHandler
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelConsumeLoop = cancel
+	consumerGroup := &multiSignalConsumerGroupHandler{
+		id:                 c.id,
+		logger:             c.settings.Logger,
+		tracesUnmarshaler:  c.tracesUnmarshaler,
+		tracesConsumer:     c.tracesConsumer,
+		metricsUnmarshaler: c.metricsUnmarshaler,
+		metricsConsumer:    c.metricsConsumer,
+		logsUnmarshaler:    c.logsUnmarshaler,
+		logsConsumer:       c.logsConsumer,
+		ready:              make(chan bool),
+		obsrecv: obsreport.NewReceiver(obsreport.ReceiverSettings{
+			ReceiverID:             c.id,
+			Transport:              transport,
+			ReceiverCreateSettings: c.settings,
+		}),
+		autocommitEnabled: c.autocommitEnabled,
+		messageMarking:    c.messageMarking,
+		pauser:            partitionPauser{id: c.id, logger: c.settings.Logger, cfg: c.pauseOnError},
+		limiter:           newConsumptionLimiter(c.rateLimit),
+		unmarshalErrors:   c.errorHandler,
+		decompressor:      c.decompressor,
+	}
+	go c.consumeLoop(ctx, consumerGroup) // nolint:errcheck
+	<-consumerGroup.ready
+	return nil
+}
+
+func (c *kafkaMultiSignalConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) error {
+	for {
+		// `Consume` should be called inside an infinite loop, when a
+		// server-side rebalance happens, the consumer session will need to be
+		// recreated to get the new claims
+		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
+			c.settings.Logger.Error("Error from consumer", zap.Error(err))
+		}
+		// check if context was cancelled, signaling that the consumer should stop
+		if ctx.Err() != nil {
+			c.settings.Logger.Info("Consumer stopped", zap.Error(ctx.Err()))
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *kafkaMultiSignalConsumer) Shutdown(context.Context) error {
+	c.cancelConsumeLoop()
+	err := c.consumerGroup.Close()
+	// The producer is closed once the consumption stopped.
+	if closeErr := c.errorHandler.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	c.decompressor.close()
+	return err
+}
+
+type multiSignalConsumerGroupHandler struct {
+	id                 config.ComponentID
+	tracesUnmarshaler  TracesUnmarshaler
+	tracesConsumer     consumer.Traces
+	metricsUnmarshaler MetricsUnmarshaler
+	metricsConsumer    consumer.Metrics
+	logsUnmarshaler    LogsUnmarshaler
+	logsConsumer       consumer.Logs
+	ready              chan bool
+	readyCloser        sync.Once
+
+	logger *zap.Logger
+
+	obsrecv *obsreport.Receiver
+
+	autocommitEnabled bool
+	messageMarking    MessageMarking
+	pauser            partitionPauser
+	limiter           *consumptionLimiter
+	unmarshalErrors   *unmarshalErrorHandler
+	decompressor      *decompressor
+}
+
+var _ sarama.ConsumerGroupHandler = (*multiSignalConsumerGroupHandler)(nil)
+
+func (c *multiSignalConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	c.readyCloser.Do(func() {
+		close(c.ready)
+	})
+	statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.Name())}
+	_ = stats.RecordWithTags(session.Context(), statsTags, statPartitionStart.M(1))
+	return nil
+}
+
+func (c *multiSignalConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.Name())}
+	_ = stats.RecordWithTags(session.Context(), statsTags, statPartitionClose.M(1))
+	return nil
+}
+
+// unmarshal unmarshals the message with the unmarshaler of the signal of its header, and returns
+// the function sending the data to the consumer of the signal, a copy of it when the pauser retries.
+// It returns a nil function for the signals which are not received by any pipeline.
+func (c *multiSignalConsumerGroupHandler) unmarshal(message *sarama.ConsumerMessage) (func(context.Context) error, error) {
+	signal := messageSignal(message)
+	switch {
+	case signal == "":
+		return nil, errMissingSignal
+	case signal == signalTraces && c.tracesConsumer != nil:
+		value, err := c.decompressor.decompress(message)
+		if err != nil {
+			return nil, err
+		}
+		traces, err := c.tracesUnmarshaler.Unmarshal(value)
+		if err != nil {
+			return nil, err
+		}
+		spanCount := traces.SpanCount()
+		return func(ctx context.Context) error {
+			td := traces
+			if c.pauser.retries() {
+				td = traces.Clone()
+			}
+			obsCtx := c.obsrecv.StartTracesOp(ctx)
+			err := c.tracesConsumer.ConsumeTraces(ctx, td)
+			c.obsrecv.EndTracesOp(obsCtx, c.tracesUnmarshaler.Encoding(), spanCount, err)
+			return err
+		}, nil
+	case signal == signalMetrics && c.metricsConsumer != nil:
+		value, err := c.decompressor.decompress(message)
+		if err != nil {
+			return nil, err
+		}
+		metrics, err := c.metricsUnmarshaler.Unmarshal(value)
+		if err != nil {
+			return nil, err
+		}
+		dataPointCount := metrics.DataPointCount()
+		return func(ctx context.Context) error {
+			md := metrics
+			if c.pauser.retries() {
+				md = metrics.Clone()
+			}
+			obsCtx := c.obsrecv.StartMetricsOp(ctx)
+			err := c.metricsConsumer.ConsumeMetrics(ctx, md)
+			c.obsrecv.EndMetricsOp(obsCtx, c.metricsUnmarshaler.Encoding(), dataPointCount, err)
+			return err
+		}, nil
+	case signal == signalLogs && c.logsConsumer != nil:
+		value, err := c.decompressor.decompress(message)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := c.logsUnmarshaler.Unmarshal(value)
+		if err != nil {
+			return nil, err
+		}
+		logRecordCount := logs.LogRecordCount()
+		return func(ctx context.Context) error {
+			ld := logs
+			if c.pauser.retries() {
+				ld = logs.Clone()
+			}
+			obsCtx := c.obsrecv.StartLogsOp(ctx)
+			err := c.logsConsumer.ConsumeLogs(ctx, ld)
+			c.obsrecv.EndLogsOp(obsCtx, c.logsUnmarshaler.Encoding(), logRecordCount, err)
+			return err
+		}, nil
+	case signal == signalTraces || signal == signalMetrics || signal == signalLogs:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown %s header %q", signalHeader, signal)
+}
+
+func (c *multiSignalConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	if !c.autocommitEnabled {
+		defer session.Commit()
+	}
+	for message := range claim.Messages() {
+		if err := c.limiter.wait(session.Context(), len(message.Value)); err != nil {
+			// The session is done, the message is consumed again by the next session.
+			return nil
+		}
+		c.logger.Debug("Kafka message claimed",
+			zap.String("value", string(message.Value)),
+			zap.Time("timestamp", message.Timestamp),
+			zap.String("topic", message.Topic))
+		if !c.messageMarking.After {
+			session.MarkMessage(message, "")
+		}
+
+		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String())}
+		_ = stats.RecordWithTags(session.Context(), statsTags,
+			statMessageCount.M(1),
+			statMessageOffset.M(message.Offset),
+			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
+
+		consume, err := c.unmarshal(message)
+		if err != nil {
+			if err = handleUnmarshalError(session, message, err, c.logger, c.unmarshalErrors, c.messageMarking, c.autocommitEnabled); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The messages of the signals not received by any pipeline are skipped.
+		if consume != nil {
+			err = c.pauser.consume(session, claim, func() error {
+				return consume(session.Context())
+			})
+		}
+		if err != nil {
+			if c.messageMarking.After && c.messageMarking.OnError {
+				session.MarkMessage(message, "")
+			}
+			return err
+		}
+		if c.messageMarking.After {
+			session.MarkMessage(message, "")
+		}
+		if !c.autocommitEnabled {
+			session.Commit()
+		}
+	}
+	return nil
+}
+
+// messageSignal returns the value of the signal header of the message.
+func messageSignal(message *sarama.ConsumerMessage) string {
+	signal, _ := messageHeader(message, signalHeader)
+	return signal
+}
+
+// messageHeader returns the value of the header of the message with the given key, and whether it is set.
+func messageHeader(message *sarama.ConsumerMessage, key string) (string, bool) {
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value), true
+		}
+	}
+	return "", false
+}
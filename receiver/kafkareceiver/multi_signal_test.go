@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func TestCreateMultiSignalReceivers(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MultiSignal = true
+	cfg.ProtocolVersion = "2.0.0"
+	// disable contacting broker at startup
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{
+		tracesUnmarshalers:  defaultTracesUnmarshalers(),
+		metricsUnmarshalers: defaultMetricsUnmarshalers(),
+		logsUnmarshalers:    defaultLogsUnmarshalers(),
+	}
+	set := componenttest.NewNopReceiverCreateSettings()
+	traces, err := f.createTracesReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	metrics, err := f.createMetricsReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	logs, err := f.createLogsReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, traces, metrics)
+	assert.Same(t, traces, logs)
+}
+
+func TestCreateMultiSignalReceivers_encoding_err(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MultiSignal = true
+	cfg.Encoding = "jaeger_proto"
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{
+		tracesUnmarshalers:  defaultTracesUnmarshalers(),
+		metricsUnmarshalers: defaultMetricsUnmarshalers(),
+	}
+	set := componenttest.NewNopReceiverCreateSettings()
+	_, err := f.createTracesReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	_, err = f.createMetricsReceiver(context.Background(), set, cfg, consumertest.NewNop())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+}
+
+func signalMessage(signal string, value []byte) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{{Key: []byte(signalHeader), Value: []byte(signal)}},
+		Value:   value,
+	}
+}
+
+func TestMultiSignalConsumerGroupHandler(t *testing.T) {
+	traces, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(testdata.GenerateTracesOneSpan())
+	require.NoError(t, err)
+	metrics, err := otlp.NewProtobufMetricsMarshaler().MarshalMetrics(testdata.GenerateMetricsOneMetric())
+	require.NoError(t, err)
+	logs, err := otlp.NewProtobufLogsMarshaler().MarshalLogs(testdata.GenerateLogsOneLogRecord())
+	require.NoError(t, err)
+
+	tracesSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+	c := multiSignalConsumerGroupHandler{
+		tracesUnmarshaler:  defaultTracesUnmarshalers()[defaultEncoding],
+		tracesConsumer:     tracesSink,
+		metricsUnmarshaler: defaultMetricsUnmarshalers()[defaultEncoding],
+		metricsConsumer:    metricsSink,
+		logger:             zap.NewNop(),
+		ready:              make(chan bool),
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+		unmarshalErrors:    &unmarshalErrorHandler{logger: zap.NewNop(), cfg: UnmarshalErrors{Policy: unmarshalErrorSkip}},
+	}
+
+	groupClaim := testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	done := make(chan error)
+	go func() {
+		done <- c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+	}()
+
+	groupClaim.messageChan <- signalMessage(signalTraces, traces)
+	groupClaim.messageChan <- signalMessage(signalMetrics, metrics)
+	// No pipeline receives the logs, which are skipped.
+	groupClaim.messageChan <- signalMessage(signalLogs, logs)
+	// The messages without signal are handled by the unmarshal errors policy.
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: traces}
+	groupClaim.messageChan <- signalMessage("profiles", traces)
+	close(groupClaim.messageChan)
+	require.NoError(t, <-done)
+
+	assert.Len(t, tracesSink.AllTraces(), 1)
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+}
+
+func TestMultiSignalConsumerGroupHandler_error_signal(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *sarama.ConsumerMessage
+		err     string
+	}{
+		{
+			name:    "missing",
+			message: &sarama.ConsumerMessage{},
+			err:     "message has no otlp_signal header",
+		},
+		{
+			name:    "unknown",
+			message: signalMessage("profiles", nil),
+			err:     `unknown otlp_signal header "profiles"`,
+		},
+		{
+			name:    "unmarshal",
+			message: signalMessage(signalTraces, []byte("!@#")),
+			err:     "unexpected EOF",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := multiSignalConsumerGroupHandler{
+				tracesUnmarshaler: defaultTracesUnmarshalers()[defaultEncoding],
+				tracesConsumer:    consumertest.NewNop(),
+				logger:            zap.NewNop(),
+				ready:             make(chan bool),
+				obsrecv:           obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+			}
+			groupClaim := testConsumerGroupClaim{
+				messageChan: make(chan *sarama.ConsumerMessage, 1),
+			}
+			groupClaim.messageChan <- test.message
+			close(groupClaim.messageChan)
+			assert.EqualError(t, c.ConsumeClaim(testConsumerGroupSession{}, groupClaim), test.err)
+		})
+	}
+}
+
+func TestMultiSignalReceiverStart(t *testing.T) {
+	c := kafkaMultiSignalConsumer{
+		tracesConsumer: consumertest.NewNop(),
+		settings:       componenttest.NewNopReceiverCreateSettings(),
+		consumerGroup:  &testConsumerGroup{},
+	}
+
+	require.NoError(t, c.Start(context.Background(), nil))
+	require.NoError(t, c.Shutdown(context.Background()))
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// partitionPauser pauses the consumption of a partition while the next consumer
+// pushes back. Messages are not read from the claim while paused, so that sarama
+// stops fetching the partition once its buffers are full.
+type partitionPauser struct {
+	id     config.ComponentID
+	logger *zap.Logger
+	cfg    PauseOnError
+}
+
+// retries returns whether the data refused by the pipeline is consumed again. Every call of
+// fn is then given a copy of the data, as the processors modifying the data in place may have
+// changed it before the pipeline refused it.
+func (p *partitionPauser) retries() bool {
+	return p.cfg.Enabled
+}
+
+// consume calls fn until it succeeds, it returns a permanent error or the session is done.
+// The error of the last call is returned. fn is called once if pausing is disabled.
+func (p *partitionPauser) consume(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, fn func() error) error {
+	err := fn()
+	if !p.cfg.Enabled || err == nil || consumererror.IsPermanent(err) {
+		return err
+	}
+
+	statsTags := []tag.Mutator{tag.Insert(tagInstanceName, p.id.String())}
+	_ = stats.RecordWithTags(session.Context(), statsTags, statPartitionPause.M(1))
+	p.logger.Warn("Pausing partition, the pipeline refused the message",
+		zap.String("topic", claim.Topic()),
+		zap.Int32("partition", claim.Partition()),
+		zap.Error(err))
+
+	interval := p.cfg.InitialInterval
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-session.Context().Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if err = fn(); err == nil || consumererror.IsPermanent(err) {
+			_ = stats.RecordWithTags(session.Context(), statsTags, statPartitionResume.M(1))
+			p.logger.Info("Resuming partition",
+				zap.String("topic", claim.Topic()),
+				zap.Int32("partition", claim.Partition()))
+			return err
+		}
+
+		interval *= 2
+		if interval > p.cfg.MaxInterval {
+			interval = p.cfg.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+func newTestPauser(enabled bool) partitionPauser {
+	return partitionPauser{
+		logger: zap.NewNop(),
+		cfg: PauseOnError{
+			Enabled:         enabled,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     2 * time.Millisecond,
+		},
+	}
+}
+
+func TestPartitionPauser(t *testing.T) {
+	errRefused := errors.New("data refused")
+	tests := []struct {
+		name      string
+		enabled   bool
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			enabled:   true,
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "disabled",
+			enabled:   false,
+			errs:      []error{errRefused, nil},
+			wantCalls: 1,
+			wantErr:   errRefused,
+		},
+		{
+			name:      "resumed",
+			enabled:   true,
+			errs:      []error{errRefused, errRefused, errRefused, nil},
+			wantCalls: 4,
+		},
+		{
+			name:      "permanent error",
+			enabled:   true,
+			errs:      []error{consumererror.NewPermanent(errRefused), nil},
+			wantCalls: 1,
+			wantErr:   consumererror.NewPermanent(errRefused),
+		},
+		{
+			name:      "resumed with permanent error",
+			enabled:   true,
+			errs:      []error{errRefused, consumererror.NewPermanent(errRefused), nil},
+			wantCalls: 2,
+			wantErr:   consumererror.NewPermanent(errRefused),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPauser(tt.enabled)
+			calls := 0
+			err := p.consume(testConsumerGroupSession{}, &testConsumerGroupClaim{}, func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestPartitionPauser_sessionDone(t *testing.T) {
+	errRefused := errors.New("data refused")
+	ctx, cancel := context.WithCancel(context.Background())
+	p := newTestPauser(true)
+	calls := 0
+	err := p.consume(testConsumerGroupSession{ctx: ctx}, &testConsumerGroupClaim{}, func() error {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return errRefused
+	})
+	assert.Equal(t, errRefused, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestTracesConsumerGroupHandler_pause(t *testing.T) {
+	next := &refusingTracesConsumer{refusals: 2}
+	c := tracesConsumerGroupHandler{
+		unmarshaler:  newPdataTracesUnmarshaler(otlp.NewProtobufTracesUnmarshaler(), defaultEncoding),
+		logger:       zap.NewNop(),
+		ready:        make(chan bool),
+		nextConsumer: next,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+		pauser:       newTestPauser(true),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(testConsumerGroupSession{}, groupClaim))
+		wg.Done()
+	}()
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	bts, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, 3, next.calls)
+	assert.Equal(t, 1, next.sink.SpanCount())
+}
+
+func TestMultiSignalConsumerGroupHandler_pause(t *testing.T) {
+	next := &refusingTracesConsumer{refusals: 2}
+	c := multiSignalConsumerGroupHandler{
+		tracesUnmarshaler: defaultTracesUnmarshalers()[defaultEncoding],
+		tracesConsumer:    next,
+		logger:            zap.NewNop(),
+		ready:             make(chan bool),
+		obsrecv:           obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+		pauser:            newTestPauser(true),
+	}
+
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	done := make(chan error)
+	go func() {
+		done <- c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+	}()
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	bts, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	groupClaim.messageChan <- signalMessage(signalTraces, bts)
+	close(groupClaim.messageChan)
+	require.NoError(t, <-done)
+
+	assert.Equal(t, 3, next.calls)
+	assert.Equal(t, 1, next.sink.SpanCount())
+}
+
+// refusingTracesConsumer refuses the first traces it is given, like the memory_limiter does, after
+// modifying them in place like the processors before it may have done.
+type refusingTracesConsumer struct {
+	refusals int
+	calls    int
+	sink     consumertest.TracesSink
+}
+
+var _ consumer.Traces = (*refusingTracesConsumer)(nil)
+
+func (c *refusingTracesConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{}
+}
+
+func (c *refusingTracesConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	c.calls++
+	if c.calls <= c.refusals {
+		td.ResourceSpans().RemoveIf(func(pdata.ResourceSpans) bool { return true })
+		return errors.New("data refused due to high memory usage")
+	}
+	return c.sink.ConsumeTraces(ctx, td)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// consumptionLimiter limits the rate at which the messages of all the partitions claimed by
+// a receiver are consumed. Messages are not read from the claims while waiting, so that sarama
+// stops fetching the partitions once its buffers are full.
+type consumptionLimiter struct {
+	messages *rate.Limiter
+	bytes    *rate.Limiter
+}
+
+// newConsumptionLimiter returns nil if neither rate is limited.
+func newConsumptionLimiter(cfg RateLimit) *consumptionLimiter {
+	if cfg.MessagesPerSecond <= 0 && cfg.BytesPerSecond <= 0 {
+		return nil
+	}
+	l := &consumptionLimiter{}
+	if cfg.MessagesPerSecond > 0 {
+		l.messages = rate.NewLimiter(rate.Limit(cfg.MessagesPerSecond), int(math.Ceil(cfg.MessagesPerSecond)))
+	}
+	if cfg.BytesPerSecond > 0 {
+		l.bytes = rate.NewLimiter(rate.Limit(cfg.BytesPerSecond), cfg.BytesPerSecond)
+	}
+	return l
+}
+
+// wait blocks until a message of size bytes can be consumed, or ctx is done.
+// Messages larger than the bytes consumed per second wait for more than a second.
+func (l *consumptionLimiter) wait(ctx context.Context, size int) error {
+	if l == nil {
+		return nil
+	}
+	if l.messages != nil {
+		if err := l.messages.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if l.bytes != nil {
+		for size > 0 {
+			n := size
+			if burst := l.bytes.Burst(); n > burst {
+				n = burst
+			}
+			if err := l.bytes.WaitN(ctx, n); err != nil {
+				return err
+			}
+			size -= n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+func TestConsumptionLimiter_unlimited(t *testing.T) {
+	l := newConsumptionLimiter(RateLimit{})
+	assert.Nil(t, l)
+	assert.NoError(t, l.wait(context.Background(), 1<<30))
+}
+
+func TestConsumptionLimiter_messages(t *testing.T) {
+	l := newConsumptionLimiter(RateLimit{MessagesPerSecond: 10})
+	start := time.Now()
+	for i := 0; i < 12; i++ {
+		require.NoError(t, l.wait(context.Background(), 1))
+	}
+	// The first 10 messages are consumed at once, the next ones every 100ms.
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestConsumptionLimiter_bytes(t *testing.T) {
+	l := newConsumptionLimiter(RateLimit{BytesPerSecond: 1000})
+	start := time.Now()
+	// Messages larger than the bytes consumed per second are allowed.
+	require.NoError(t, l.wait(context.Background(), 1300))
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestConsumptionLimiter_contextDone(t *testing.T) {
+	l := newConsumptionLimiter(RateLimit{MessagesPerSecond: 1})
+	require.NoError(t, l.wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, l.wait(ctx, 1))
+}
+
+func TestLogsConsumerGroupHandler_rateLimitSessionDone(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	c := logsConsumerGroupHandler{
+		unmarshaler:  newPdataLogsUnmarshaler(otlp.NewProtobufLogsUnmarshaler(), defaultEncoding),
+		logger:       zap.NewNop(),
+		ready:        make(chan bool),
+		nextConsumer: sink,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+		limiter:      newConsumptionLimiter(RateLimit{MessagesPerSecond: 1}),
+	}
+	require.NoError(t, c.limiter.wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage, 1),
+	}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{}
+	close(groupClaim.messageChan)
+
+	assert.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: ctx}, groupClaim))
+	assert.Equal(t, 0, len(sink.AllLogs()))
+}
@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+const (
+	staticOffsetOldest = "oldest"
+	staticOffsetNewest = "newest"
+)
+
+// parseStaticOffset returns the sarama offset of the offset of a statically assigned partition.
+func parseStaticOffset(offset string) (int64, error) {
+	switch offset {
+	case "", staticOffsetOldest:
+		return sarama.OffsetOldest, nil
+	case staticOffsetNewest:
+		return sarama.OffsetNewest, nil
+	}
+	o, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil || o < 0 {
+		return 0, fmt.Errorf("%q must be %s, %s or a non-negative offset", offset, staticOffsetOldest, staticOffsetNewest)
+	}
+	return o, nil
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// staticOffsets are the offsets of the next messages to consume from the statically assigned partitions. They
+// are kept in memory, and are shared by the static consumer groups replacing each other when the credentials
+// are reloaded, so that the consumption resumes where it stopped.
+type staticOffsets struct {
+	// initial holds the configured offsets of the partitions.
+	initial map[int32]int64
+
+	mu     sync.Mutex
+	marked map[topicPartition]int64
+}
+
+func newStaticOffsets(assignment StaticAssignment) (*staticOffsets, error) {
+	o := &staticOffsets{initial: map[int32]int64{}, marked: map[topicPartition]int64{}}
+	for _, p := range assignment.Partitions {
+		offset, err := parseStaticOffset(p.Offset)
+		if err != nil {
+			return nil, err
+		}
+		o.initial[p.Partition] = offset
+	}
+	return o, nil
+}
+
+// partitions returns the statically assigned partitions.
+func (o *staticOffsets) partitions() []int32 {
+	partitions := make([]int32, 0, len(o.initial))
+	for partition := range o.initial {
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+// next returns the offset of the next message to consume from a partition, the configured offset until a
+// message is marked.
+func (o *staticOffsets) next(topic string, partition int32) int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if offset, ok := o.marked[topicPartition{topic, partition}]; ok {
+		return offset
+	}
+	return o.initial[partition]
+}
+
+// mark sets the offset of the next message to consume from a partition. Unless reset, the offset is
+// only moved forward, like the offsets marked in the sessions of consumer groups.
+func (o *staticOffsets) mark(topic string, partition int32, offset int64, reset bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	tp := topicPartition{topic, partition}
+	if current, ok := o.marked[tp]; ok && !reset && offset <= current {
+		return
+	}
+	o.marked[tp] = offset
+}
+
+// staticConsumerGroup consumes the statically assigned partitions without joining a consumer group. It
+// implements sarama.ConsumerGroup for the consumer group handlers to consume the partitions as they consume
+// the claims of a consumer group session: every session claims all the assigned partitions, and ends once
+// one of them stops being consumed. The offsets marked by a session are consumed from by the next one.
+type staticConsumerGroup struct {
+	consumer sarama.Consumer
+	offsets  *staticOffsets
+	// retryBackoff is the time waited after a session failed, before the next one starts.
+	retryBackoff time.Duration
+
+	errors    chan error
+	closeOnce sync.Once
+}
+
+var _ sarama.ConsumerGroup = (*staticConsumerGroup)(nil)
+
+func newStaticConsumerGroup(brokers []string, saramaConfig *sarama.Config, offsets *staticOffsets) (sarama.ConsumerGroup, error) {
+	consumer, err := sarama.NewConsumer(brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &staticConsumerGroup{
+		consumer:     consumer,
+		offsets:      offsets,
+		retryBackoff: saramaConfig.Consumer.Retry.Backoff,
+		errors:       make(chan error),
+	}, nil
+}
+
+// Consume runs a session consuming the assigned partitions of the topics, until ctx is done or the
+// handler stops consuming one of them. It returns the first error of the handler.
+func (g *staticConsumerGroup) Consume(ctx context.Context, topics []string, handler sarama.ConsumerGroupHandler) error {
+	claims := map[string][]int32{}
+	for _, topic := range topics {
+		claims[topic] = g.offsets.partitions()
+	}
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	session := &staticSession{ctx: sessionCtx, cancel: cancel, claims: claims, offsets: g.offsets}
+	if err := handler.Setup(session); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var err error
+	for topic, partitions := range claims {
+		for _, partition := range partitions {
+			wg.Add(1)
+			go func(topic string, partition int32) {
+				defer wg.Done()
+				// Like the sessions of consumer groups, the session ends once a partition stops being consumed.
+				defer cancel()
+				if claimErr := g.consumeClaim(session, handler, topic, partition); claimErr != nil {
+					errOnce.Do(func() { err = claimErr })
+				}
+			}(topic, partition)
+		}
+	}
+	wg.Wait()
+	if cleanupErr := handler.Cleanup(session); err == nil {
+		err = cleanupErr
+	}
+
+	// The failed sessions are not restarted right away, as the consume loop would otherwise spin
+	// while the pipeline refuses the messages or the partitions can't be consumed.
+	if err != nil && ctx.Err() == nil {
+		timer := time.NewTimer(g.retryBackoff)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// consumeClaim consumes a partition from its next offset until the session is done or the handler returns.
+func (g *staticConsumerGroup) consumeClaim(session *staticSession, handler sarama.ConsumerGroupHandler, topic string, partition int32) error {
+	offset := g.offsets.next(topic, partition)
+	pc, err := g.consumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return fmt.Errorf("failed to consume partition %d of topic %q from offset %d: %w", partition, topic, offset, err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		<-session.Context().Done()
+		pc.AsyncClose()
+		close(closed)
+	}()
+
+	err = handler.ConsumeClaim(session, &staticClaim{topic: topic, partition: partition, initialOffset: offset, pc: pc})
+	session.cancel()
+	<-closed
+	// The messages and errors channels are drained for the partition consumer to be closed.
+	go func() {
+		for range pc.Messages() {
+		}
+	}()
+	for range pc.Errors() {
+	}
+	return err
+}
+
+// Errors returns a channel which is closed with the consumer group; the errors of the sessions are returned
+// by Consume.
+func (g *staticConsumerGroup) Errors() <-chan error {
+	return g.errors
+}
+
+func (g *staticConsumerGroup) Close() error {
+	g.closeOnce.Do(func() {
+		close(g.errors)
+	})
+	return g.consumer.Close()
+}
+
+// staticSession is a session of a static consumer group, whose claims are the assigned partitions.
+type staticSession struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	claims  map[string][]int32
+	offsets *staticOffsets
+}
+
+var _ sarama.ConsumerGroupSession = (*staticSession)(nil)
+
+func (s *staticSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func (s *staticSession) MemberID() string {
+	return ""
+}
+
+func (s *staticSession) GenerationID() int32 {
+	return 0
+}
+
+func (s *staticSession) MarkOffset(topic string, partition int32, offset int64, _ string) {
+	s.offsets.mark(topic, partition, offset, false)
+}
+
+// Commit does nothing, the offsets are kept in memory without a consumer group to commit them to.
+func (s *staticSession) Commit() {}
+
+func (s *staticSession) ResetOffset(topic string, partition int32, offset int64, _ string) {
+	s.offsets.mark(topic, partition, offset, true)
+}
+
+func (s *staticSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *staticSession) Context() context.Context {
+	return s.ctx
+}
+
+// staticClaim is the claim of an assigned partition, consumed by a partition consumer.
+type staticClaim struct {
+	topic         string
+	partition     int32
+	initialOffset int64
+	pc            sarama.PartitionConsumer
+}
+
+var _ sarama.ConsumerGroupClaim = (*staticClaim)(nil)
+
+func (c *staticClaim) Topic() string {
+	return c.topic
+}
+
+func (c *staticClaim) Partition() int32 {
+	return c.partition
+}
+
+func (c *staticClaim) InitialOffset() int64 {
+	return c.initialOffset
+}
+
+func (c *staticClaim) HighWaterMarkOffset() int64 {
+	return c.pc.HighWaterMarkOffset()
+}
+
+func (c *staticClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.pc.Messages()
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestParseStaticOffset(t *testing.T) {
+	tests := []struct {
+		offset  string
+		want    int64
+		wantErr string
+	}{
+		{offset: "", want: sarama.OffsetOldest},
+		{offset: "oldest", want: sarama.OffsetOldest},
+		{offset: "newest", want: sarama.OffsetNewest},
+		{offset: "42", want: 42},
+		{offset: "-1", wantErr: `"-1" must be oldest, newest or a non-negative offset`},
+		{offset: "latest", wantErr: `"latest" must be oldest, newest or a non-negative offset`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.offset, func(t *testing.T) {
+			offset, err := parseStaticOffset(tt.offset)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, offset)
+			}
+		})
+	}
+}
+
+func TestStaticOffsets(t *testing.T) {
+	offsets, err := newStaticOffsets(StaticAssignment{Partitions: []PartitionOffset{{Partition: 0, Offset: "10"}, {Partition: 1}}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int32{0, 1}, offsets.partitions())
+	assert.Equal(t, int64(10), offsets.next("spans", 0))
+	assert.Equal(t, sarama.OffsetOldest, offsets.next("spans", 1))
+
+	offsets.mark("spans", 0, 12, false)
+	assert.Equal(t, int64(12), offsets.next("spans", 0))
+	// Marked offsets are only moved forward, unless reset.
+	offsets.mark("spans", 0, 11, false)
+	assert.Equal(t, int64(12), offsets.next("spans", 0))
+	offsets.mark("spans", 0, 11, true)
+	assert.Equal(t, int64(11), offsets.next("spans", 0))
+	assert.Equal(t, sarama.OffsetOldest, offsets.next("spans", 1))
+}
+
+// markingHandler marks the consumed messages, and stops consuming a partition once a message fails.
+type markingHandler struct {
+	messages chan *sarama.ConsumerMessage
+	fail     error
+}
+
+func (h *markingHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *markingHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *markingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for message := range claim.Messages() {
+		session.MarkMessage(message, "")
+		h.messages <- message
+		if string(message.Value) == "fail" {
+			return h.fail
+		}
+	}
+	return nil
+}
+
+func TestStaticConsumerGroup(t *testing.T) {
+	offsets, err := newStaticOffsets(StaticAssignment{Partitions: []PartitionOffset{{Partition: 0, Offset: "5"}, {Partition: 1}}})
+	require.NoError(t, err)
+	first := mocks.NewConsumer(t, nil)
+	first.ExpectConsumePartition("spans", 0, 5).YieldMessage(&sarama.ConsumerMessage{Value: []byte("fail")})
+	first.ExpectConsumePartition("spans", 1, sarama.OffsetOldest)
+	g := &staticConsumerGroup{consumer: first, offsets: offsets, retryBackoff: time.Millisecond, errors: make(chan error)}
+
+	handler := &markingHandler{messages: make(chan *sarama.ConsumerMessage, 10), fail: errors.New("failed")}
+	assert.Equal(t, handler.fail, g.Consume(context.Background(), []string{"spans"}, handler))
+	require.Len(t, handler.messages, 1)
+	failed := <-handler.messages
+	require.NoError(t, first.Close())
+
+	// The next session resumes from the marked offset of the first partition, and from the configured offset
+	// of the partition which had no messages.
+	second := mocks.NewConsumer(t, nil)
+	second.ExpectConsumePartition("spans", 0, failed.Offset+1).YieldMessage(&sarama.ConsumerMessage{Value: []byte("next")})
+	second.ExpectConsumePartition("spans", 1, sarama.OffsetOldest)
+	g.consumer = second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- g.Consume(ctx, []string{"spans"}, handler)
+	}()
+	assert.Equal(t, "next", string((<-handler.messages).Value))
+	cancel()
+	assert.NoError(t, <-done)
+
+	require.NoError(t, g.Close())
+	_, open := <-g.Errors()
+	assert.False(t, open)
+}
+
+func TestStaticConsumerGroupConsumePartitionError(t *testing.T) {
+	offsets, err := newStaticOffsets(StaticAssignment{Partitions: []PartitionOffset{{Partition: 0, Offset: "5"}}})
+	require.NoError(t, err)
+	consumer := mocks.NewConsumer(t, nil)
+	consumer.ExpectConsumePartition("spans", 0, 5)
+	_, err = consumer.ConsumePartition("spans", 0, 5)
+	require.NoError(t, err)
+
+	g := &staticConsumerGroup{consumer: consumer, offsets: offsets, retryBackoff: time.Millisecond, errors: make(chan error)}
+	err = g.Consume(context.Background(), []string{"spans"}, &markingHandler{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to consume partition 0 of topic "spans" from offset 5`)
+	require.NoError(t, g.Close())
+}
+
+func TestTracesReceiverStaticAssignment(t *testing.T) {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	value, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(traces)
+	require.NoError(t, err)
+
+	// The broker doesn't handle the consumer group requests.
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(defaultTopic, 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).SetVersion(1).
+			SetOffset(defaultTopic, 0, sarama.OffsetOldest, 0).
+			SetOffset(defaultTopic, 0, sarama.OffsetNewest, 3),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).SetVersion(4).
+			SetMessage(defaultTopic, 0, 2, sarama.ByteEncoder(value)).
+			SetHighWaterMark(defaultTopic, 0, 3),
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.StaticAssignment = StaticAssignment{Partitions: []PartitionOffset{{Partition: 0, Offset: "2"}}}
+	sink := &consumertest.TracesSink{}
+	r, err := NewFactory().CreateTracesReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, "span", sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func TestNewConsumerGroupStaticAssignmentError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.StaticAssignment = StaticAssignment{Partitions: []PartitionOffset{{Partition: 0, Offset: "latest"}}}
+	_, err := newConsumerGroup(*cfg, sarama.NewConfig(), zap.NewNop())
+	assert.EqualError(t, err, `"latest" must be oldest, newest or a non-negative offset`)
+}
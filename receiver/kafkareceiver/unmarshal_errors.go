@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// unmarshalErrorFail stops consuming the partition until the next session of the consumer group.
+	unmarshalErrorFail = "fail"
+	// unmarshalErrorSkip marks the message as consumed and consumes the next one.
+	unmarshalErrorSkip = "skip"
+	// unmarshalErrorDeadLetter publishes the message to the dead-letter topic before skipping it.
+	unmarshalErrorDeadLetter = "dead_letter"
+
+	// The headers added to the messages published to the dead-letter topic.
+	deadLetterHeaderError     = "otel-error"
+	deadLetterHeaderTopic     = "otel-original-topic"
+	deadLetterHeaderPartition = "otel-original-partition"
+	deadLetterHeaderOffset    = "otel-original-offset"
+)
+
+// unmarshalErrorHandler applies the unmarshal_errors policy to the messages which fail to unmarshal.
+type unmarshalErrorHandler struct {
+	id     config.ComponentID
+	logger *zap.Logger
+	cfg    UnmarshalErrors
+	// producer publishes the messages to the dead-letter topic, it is nil with the other policies.
+	producer sarama.SyncProducer
+}
+
+// newUnmarshalErrorHandler creates the producer of the dead-letter topic if the policy requires it.
+func newUnmarshalErrorHandler(id config.ComponentID, logger *zap.Logger, cfg UnmarshalErrors, brokers []string, saramaConfig *sarama.Config) (*unmarshalErrorHandler, error) {
+	h := &unmarshalErrorHandler{id: id, logger: logger, cfg: cfg}
+	if cfg.Policy != unmarshalErrorDeadLetter {
+		return h, nil
+	}
+	producerConfig := *saramaConfig
+	producerConfig.Producer.Return.Successes = true
+	producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+	producer, err := sarama.NewSyncProducer(brokers, &producerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the producer of the dead-letter topic: %w", err)
+	}
+	h.producer = producer
+	return h, nil
+}
+
+// handleUnmarshalError logs the error of a message which failed to unmarshal and applies the unmarshal_errors
+// policy of handler, marking the message according to marking. It returns the error to stop consuming the
+// partition with, or nil if the message is skipped and the next one can be consumed.
+func handleUnmarshalError(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, err error,
+	logger *zap.Logger, handler *unmarshalErrorHandler, marking MessageMarking, autocommitEnabled bool) error {
+	logger.Error("failed to unmarshal message", zap.Error(err))
+	if err = handler.handle(session.Context(), message, err); err != nil {
+		if marking.After && marking.OnError {
+			session.MarkMessage(message, "")
+		}
+		return err
+	}
+	// The message is skipped.
+	if marking.After {
+		session.MarkMessage(message, "")
+	}
+	if !autocommitEnabled {
+		session.Commit()
+	}
+	return nil
+}
+
+// handle returns the error to stop consuming the partition with, or nil if the message is handled
+// and the next one can be consumed. A nil handler applies the fail policy.
+func (h *unmarshalErrorHandler) handle(ctx context.Context, message *sarama.ConsumerMessage, err error) error {
+	if h == nil {
+		return err
+	}
+	_ = stats.RecordWithTags(ctx,
+		[]tag.Mutator{tag.Insert(tagInstanceName, h.id.String()), tag.Insert(tagPolicy, h.policy())},
+		statUnmarshalFailed.M(1))
+
+	switch h.cfg.Policy {
+	case unmarshalErrorSkip:
+		return nil
+	case unmarshalErrorDeadLetter:
+		if perr := h.publish(message, err); perr != nil {
+			// The message is not skipped unless it can be found in the dead-letter topic.
+			return fmt.Errorf("failed to publish message to the dead-letter topic %s: %w", h.cfg.DeadLetterTopic, perr)
+		}
+		h.logger.Debug("Message published to the dead-letter topic",
+			zap.String("topic", message.Topic),
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset))
+		return nil
+	default:
+		return err
+	}
+}
+
+// publish copies the message to the dead-letter topic, with headers recording where the message
+// comes from and why it failed to unmarshal.
+func (h *unmarshalErrorHandler) publish(message *sarama.ConsumerMessage, err error) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+4)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderError), Value: []byte(err.Error())},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderPartition), Value: []byte(strconv.FormatInt(int64(message.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(deadLetterHeaderOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+	)
+	deadLetter := &sarama.ProducerMessage{
+		Topic:   h.cfg.DeadLetterTopic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		deadLetter.Key = sarama.ByteEncoder(message.Key)
+	}
+	_, _, err = h.producer.SendMessage(deadLetter)
+	return err
+}
+
+func (h *unmarshalErrorHandler) policy() string {
+	if h.cfg.Policy == "" {
+		return unmarshalErrorFail
+	}
+	return h.cfg.Policy
+}
+
+// close closes the producer of the dead-letter topic.
+func (h *unmarshalErrorHandler) close() error {
+	if h == nil || h.producer == nil {
+		return nil
+	}
+	return h.producer.Close()
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+var errInvalidMessage = errors.New("invalid message")
+
+func TestUnmarshalErrorHandler_fail(t *testing.T) {
+	var h *unmarshalErrorHandler
+	assert.Equal(t, errInvalidMessage, h.handle(context.Background(), &sarama.ConsumerMessage{}, errInvalidMessage))
+	assert.NoError(t, h.close())
+
+	h, err := newUnmarshalErrorHandler(config.NewComponentID(typeStr), zap.NewNop(), UnmarshalErrors{Policy: unmarshalErrorFail}, nil, sarama.NewConfig())
+	require.NoError(t, err)
+	assert.Nil(t, h.producer)
+	assert.Equal(t, errInvalidMessage, h.handle(context.Background(), &sarama.ConsumerMessage{}, errInvalidMessage))
+}
+
+func TestUnmarshalErrorHandler_skip(t *testing.T) {
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	h, err := newUnmarshalErrorHandler(config.NewComponentID(typeStr), zap.NewNop(), UnmarshalErrors{Policy: unmarshalErrorSkip}, nil, sarama.NewConfig())
+	require.NoError(t, err)
+	assert.NoError(t, h.handle(context.Background(), &sarama.ConsumerMessage{}, errInvalidMessage))
+	assert.NoError(t, h.handle(context.Background(), &sarama.ConsumerMessage{}, errInvalidMessage))
+
+	rows, err := view.RetrieveData(statUnmarshalFailed.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Contains(t, rows[0].Tags, tag.Tag{Key: tagPolicy, Value: unmarshalErrorSkip})
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestUnmarshalErrorHandler_deadLetter(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	h := &unmarshalErrorHandler{
+		id:       config.NewComponentID(typeStr),
+		logger:   zap.NewNop(),
+		cfg:      UnmarshalErrors{Policy: unmarshalErrorDeadLetter, DeadLetterTopic: "otlp_spans_dlq"},
+		producer: producer,
+	}
+	message := &sarama.ConsumerMessage{
+		Topic:     "otlp_spans",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("!@#"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("tenant"), Value: []byte("acme")}},
+	}
+
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, "otlp_spans_dlq", msg.Topic)
+		assert.Equal(t, sarama.ByteEncoder("key"), msg.Key)
+		assert.Equal(t, sarama.ByteEncoder("!@#"), msg.Value)
+		assert.Equal(t, []sarama.RecordHeader{
+			{Key: []byte("tenant"), Value: []byte("acme")},
+			{Key: []byte(deadLetterHeaderError), Value: []byte(errInvalidMessage.Error())},
+			{Key: []byte(deadLetterHeaderTopic), Value: []byte("otlp_spans")},
+			{Key: []byte(deadLetterHeaderPartition), Value: []byte("3")},
+			{Key: []byte(deadLetterHeaderOffset), Value: []byte("42")},
+		}, msg.Headers)
+		return nil
+	})
+	assert.NoError(t, h.handle(context.Background(), message, errInvalidMessage))
+
+	// The message is not skipped if it can't be published.
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	err := h.handle(context.Background(), message, errInvalidMessage)
+	assert.ErrorIs(t, err, sarama.ErrOutOfBrokers)
+	assert.NoError(t, h.close())
+}
+
+func TestLogsConsumerGroupHandler_skipUnmarshalErrors(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	c := logsConsumerGroupHandler{
+		unmarshaler:  newPdataLogsUnmarshaler(otlp.NewProtobufLogsUnmarshaler(), defaultEncoding),
+		logger:       zap.NewNop(),
+		ready:        make(chan bool),
+		nextConsumer: sink,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+		unmarshalErrors: &unmarshalErrorHandler{
+			id:     config.NewComponentID(typeStr),
+			logger: zap.NewNop(),
+			cfg:    UnmarshalErrors{Policy: unmarshalErrorSkip},
+		},
+	}
+	logs, err := otlp.NewProtobufLogsMarshaler().MarshalLogs(testdata.GenerateLogsOneLogRecord())
+	require.NoError(t, err)
+
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage, 2),
+	}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: logs}
+	close(groupClaim.messageChan)
+
+	require.NoError(t, c.ConsumeClaim(testConsumerGroupSession{}, groupClaim))
+	assert.Len(t, sink.AllLogs(), 1)
+}
@@ -57,26 +57,38 @@ func defaultTracesUnmarshalers() map[string]TracesUnmarshaler {
 	zipkinProto := newPdataTracesUnmarshaler(zipkinv2.NewProtobufTracesUnmarshaler(false, false), "zipkin_proto")
 	zipkinJSON := newPdataTracesUnmarshaler(zipkinv2.NewJSONTracesUnmarshaler(false), "zipkin_json")
 	zipkinThrift := newPdataTracesUnmarshaler(zipkinv1.NewThriftTracesUnmarshaler(), "zipkin_thrift")
+	auto := autoTracesUnmarshaler{otlpProto: otlpPb, jaegerProto: jaegerProto, jaegerJSON: jaegerJSON, zipkinJSON: zipkinJSON}
+	otlpZstd := zstdTracesUnmarshaler{otlpProto: otlpPb}
 	return map[string]TracesUnmarshaler{
 		otlpPb.Encoding():       otlpPb,
+		otlpZstd.Encoding():     otlpZstd,
 		jaegerProto.Encoding():  jaegerProto,
 		jaegerJSON.Encoding():   jaegerJSON,
 		zipkinProto.Encoding():  zipkinProto,
 		zipkinJSON.Encoding():   zipkinJSON,
 		zipkinThrift.Encoding(): zipkinThrift,
+		auto.Encoding():         auto,
 	}
 }
 
 func defaultMetricsUnmarshalers() map[string]MetricsUnmarshaler {
 	otlpPb := newPdataMetricsUnmarshaler(otlp.NewProtobufMetricsUnmarshaler(), defaultEncoding)
+	auto := autoMetricsUnmarshaler{otlpProto: otlpPb}
+	otlpZstd := zstdMetricsUnmarshaler{otlpProto: otlpPb}
 	return map[string]MetricsUnmarshaler{
-		otlpPb.Encoding(): otlpPb,
+		otlpPb.Encoding():   otlpPb,
+		otlpZstd.Encoding(): otlpZstd,
+		auto.Encoding():     auto,
 	}
 }
 
 func defaultLogsUnmarshalers() map[string]LogsUnmarshaler {
 	otlpPb := newPdataLogsUnmarshaler(otlp.NewProtobufLogsUnmarshaler(), defaultEncoding)
+	auto := autoLogsUnmarshaler{otlpProto: otlpPb}
+	otlpZstd := zstdLogsUnmarshaler{otlpProto: otlpPb}
 	return map[string]LogsUnmarshaler{
-		otlpPb.Encoding(): otlpPb,
+		otlpPb.Encoding():   otlpPb,
+		otlpZstd.Encoding(): otlpZstd,
+		auto.Encoding():     auto,
 	}
 }
@@ -24,11 +24,13 @@ import (
 func TestDefaultTracesUnMarshaler(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
+		"otlp_proto_zstd",
 		"jaeger_proto",
 		"jaeger_json",
 		"zipkin_proto",
 		"zipkin_json",
 		"zipkin_thrift",
+		"auto",
 	}
 	marshalers := defaultTracesUnmarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -44,6 +46,8 @@ func TestDefaultTracesUnMarshaler(t *testing.T) {
 func TestDefaultMetricsUnMarshaler(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
+		"otlp_proto_zstd",
+		"auto",
 	}
 	marshalers := defaultMetricsUnmarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -59,6 +63,8 @@ func TestDefaultMetricsUnMarshaler(t *testing.T) {
 func TestDefaultLogsUnMarshaler(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
+		"otlp_proto_zstd",
+		"auto",
 	}
 	marshalers := defaultLogsUnmarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// zstdEncoding is the encoding of the otlp_proto messages compressed with zstd by the Kafka exporter.
+// Messages which aren't compressed are deserialized as otlp_proto, so that producers can switch
+// encodings without the messages already produced to the topic being lost.
+const zstdEncoding = "otlp_proto_zstd"
+
+// zstdTracesUnmarshaler deserializes the messages, decompressed by the consumer beforehand, as otlp_proto.
+type zstdTracesUnmarshaler struct {
+	otlpProto TracesUnmarshaler
+}
+
+var _ TracesUnmarshaler = (*zstdTracesUnmarshaler)(nil)
+
+func (z zstdTracesUnmarshaler) Unmarshal(data []byte) (pdata.Traces, error) {
+	return z.otlpProto.Unmarshal(data)
+}
+
+func (z zstdTracesUnmarshaler) Encoding() string {
+	return zstdEncoding
+}
+
+// zstdMetricsUnmarshaler deserializes the messages, decompressed by the consumer beforehand, as otlp_proto.
+type zstdMetricsUnmarshaler struct {
+	otlpProto MetricsUnmarshaler
+}
+
+var _ MetricsUnmarshaler = (*zstdMetricsUnmarshaler)(nil)
+
+func (z zstdMetricsUnmarshaler) Unmarshal(data []byte) (pdata.Metrics, error) {
+	return z.otlpProto.Unmarshal(data)
+}
+
+func (z zstdMetricsUnmarshaler) Encoding() string {
+	return zstdEncoding
+}
+
+// zstdLogsUnmarshaler deserializes the messages, decompressed by the consumer beforehand, as otlp_proto.
+type zstdLogsUnmarshaler struct {
+	otlpProto LogsUnmarshaler
+}
+
+var _ LogsUnmarshaler = (*zstdLogsUnmarshaler)(nil)
+
+func (z zstdLogsUnmarshaler) Unmarshal(data []byte) (pdata.Logs, error) {
+	return z.otlpProto.Unmarshal(data)
+}
+
+func (z zstdLogsUnmarshaler) Encoding() string {
+	return zstdEncoding
+}
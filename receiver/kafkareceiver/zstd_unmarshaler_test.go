@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestUnmarshalZstdTraces(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+
+	unmarshaler := defaultTracesUnmarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, unmarshaler.Encoding())
+	got, err := unmarshaler.Unmarshal(otlpBytes)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestUnmarshalZstdMetrics(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufMetricsMarshaler().MarshalMetrics(md)
+	require.NoError(t, err)
+
+	unmarshaler := defaultMetricsUnmarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, unmarshaler.Encoding())
+	got, err := unmarshaler.Unmarshal(otlpBytes)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+}
+
+func TestUnmarshalZstdLogs(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty().SetName("foo")
+	otlpBytes, err := otlp.NewProtobufLogsMarshaler().MarshalLogs(ld)
+	require.NoError(t, err)
+
+	unmarshaler := defaultLogsUnmarshalers()[zstdEncoding]
+	assert.Equal(t, zstdEncoding, unmarshaler.Encoding())
+	got, err := unmarshaler.Unmarshal(otlpBytes)
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql/driver"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ER_ACCESS_DENIED_NO_PASSWORD_ERROR is returned to users authenticated by auth_socket which
+// don't connect through the unix socket as the operating system user of the same name.
+const errorNumberAccessDeniedNoPassword = 1698
+
+// loadServerPublicKey reads the PEM encoded RSA public key of the server, used by the
+// caching_sha2_password and sha256_password plugins to encrypt the password without TLS.
+func loadServerPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in server public key file %q", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("server public key file %q does not contain an RSA public key", path)
+	}
+	return rsaKey, nil
+}
+
+// authErrorConnector explains the errors of the authentication plugins, which the driver
+// reports in terms of DSN parameters, with the settings of the receiver.
+type authErrorConnector struct {
+	driver.Connector
+}
+
+func (c authErrorConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, explainAuthError(err)
+	}
+	return conn, nil
+}
+
+// explainAuthError wraps err with the settings enabling the authentication plugin that failed.
+func explainAuthError(err error) error {
+	switch {
+	case errors.Is(err, mysql.ErrNativePassword):
+		return fmt.Errorf("%w: set allow_native_passwords to true", err)
+	case errors.Is(err, mysql.ErrCleartextPassword):
+		return fmt.Errorf("%w: set allow_cleartext_passwords to true, with TLS or the unix transport", err)
+	case errors.Is(err, mysql.ErrOldPassword):
+		return fmt.Errorf("%w: the pre-4.1 password hashing isn't supported by the receiver", err)
+	case errors.Is(err, mysql.ErrUnknownPlugin):
+		return fmt.Errorf("%w: the user must be identified with a supported plugin, e.g. caching_sha2_password", err)
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errorNumberAccessDeniedNoPassword {
+		return fmt.Errorf("%w: users identified with auth_socket must connect with the unix transport as the operating system user of the same name", err)
+	}
+	return err
+}
+
+// isAuthPluginError returns true if the authentication plugin of the user isn't enabled or supported.
+func isAuthPluginError(err error) bool {
+	return errors.Is(err, mysql.ErrNativePassword) || errors.Is(err, mysql.ErrCleartextPassword) ||
+		errors.Is(err, mysql.ErrOldPassword) || errors.Is(err, mysql.ErrUnknownPlugin)
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql/driver"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+)
+
+func writePublicKey(t *testing.T, path string, key interface{}) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+}
+
+func TestLoadServerPublicKey(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaFile := filepath.Join(dir, "public_key.pem")
+	writePublicKey(t, rsaFile, &rsaKey.PublicKey)
+
+	pubKey, err := loadServerPublicKey(rsaFile)
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, pubKey)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecFile := filepath.Join(dir, "ec_key.pem")
+	writePublicKey(t, ecFile, &ecKey.PublicKey)
+	_, err = loadServerPublicKey(ecFile)
+	assert.EqualError(t, err, `server public key file "`+ecFile+`" does not contain an RSA public key`)
+
+	notPEM := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a key"), 0600))
+	_, err = loadServerPublicKey(notPEM)
+	assert.EqualError(t, err, `no PEM data found in server public key file "`+notPEM+`"`)
+
+	_, err = loadServerPublicKey(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+}
+
+func TestNewMySQLClientServerPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	cfg := createDefaultConfig().(*Config)
+	cfg.ServerPublicKeyFile = filepath.Join(t.TempDir(), "public_key.pem")
+	writePublicKey(t, cfg.ServerPublicKeyFile, &rsaKey.PublicKey)
+
+	client, err := newMySQLClient(cfg, config.MetricsDataType)
+	require.NoError(t, err)
+	assert.Contains(t, client.(*mySQLClient).connStr, "serverPubKey=otel-mysql-metrics")
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Close())
+
+	cfg.ServerPublicKeyFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = newMySQLClient(cfg, config.MetricsDataType)
+	assert.Error(t, err)
+}
+
+func TestExplainAuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "native password", err: mysql.ErrNativePassword, expected: "set allow_native_passwords to true"},
+		{name: "cleartext password", err: mysql.ErrCleartextPassword, expected: "set allow_cleartext_passwords to true"},
+		{name: "old password", err: mysql.ErrOldPassword, expected: "pre-4.1 password hashing"},
+		{name: "unknown plugin", err: mysql.ErrUnknownPlugin, expected: "supported plugin"},
+		{name: "auth_socket", err: &mysql.MySQLError{Number: 1698, Message: "Access denied for user 'otel'@'localhost'"}, expected: "unix transport"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := explainAuthError(tt.err)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	other := &mysql.MySQLError{Number: 1045, Message: "Access denied"}
+	assert.Equal(t, error(other), explainAuthError(other))
+}
+
+type failingConnector struct {
+	driver.Connector
+	err error
+}
+
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func TestAuthErrorConnector(t *testing.T) {
+	connector := authErrorConnector{failingConnector{err: mysql.ErrNativePassword}}
+	_, err := connector.Connect(context.Background())
+	require.ErrorIs(t, err, mysql.ErrNativePassword)
+	assert.Contains(t, err.Error(), "allow_native_passwords")
+	assert.True(t, isConnectionError(err))
+}
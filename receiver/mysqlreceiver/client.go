@@ -15,28 +15,120 @@
 package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
 
 import (
+	"context"
+	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	// registers the mysql driver
 	"github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/collector/config"
 )
 
 type client interface {
 	Connect() error
 	getGlobalStats() (map[string]string, error)
 	getInnodbStats() (map[string]string, error)
+	getSchemaSizes() ([]schemaSize, error)
+	getIndexUsage() ([]indexUsage, error)
+	getTransactionStats(longThreshold time.Duration) (transactionStats, error)
+	getMaxConnections() (int64, error)
+	getErrorLog(afterMicros int64) ([]errorLogRecord, error)
+	getErrorLogEnd() (int64, error)
+	getProxySQLConnectionPool() ([]proxySQLBackend, error)
+	getProxySQLQueryRules() ([]proxySQLQueryRule, error)
+	getProxySQLGlobalStats() (map[string]string, error)
+	ping(ctx context.Context) error
+	poolStats() sql.DBStats
 	Close() error
 }
 
+// schemaSize is the size in bytes of the data and indexes of the tables in a schema.
+type schemaSize struct {
+	schema     string
+	dataBytes  int64
+	indexBytes int64
+}
+
+// indexUsage is the number of rows read from a table through one of its indexes, or by full
+// table scans when index is empty, and the number of I/O operations on it since the server started.
+type indexUsage struct {
+	schema     string
+	table      string
+	index      string
+	rowsRead   int64
+	operations int64
+}
+
+// transactionStats summarizes the running InnoDB transactions and the ones waiting for a lock.
+type transactionStats struct {
+	longTransactions  int64
+	maxTransactionAge int64
+	lockWaits         int64
+	maxLockWaitAge    int64
+}
+
+// errorLogRecord is a record of the error log, its errorCode and subsystem are only
+// set by MySQL 8.0.
+type errorLogRecord struct {
+	timestamp time.Time
+	threadID  int64
+	priority  string
+	errorCode string
+	subsystem string
+	message   string
+}
+
+// proxySQLBackend is the connection pool of ProxySQL to a backend server of a hostgroup.
+type proxySQLBackend struct {
+	hostgroup string
+	host      string
+	port      string
+	status    string
+	connUsed  int64
+	connFree  int64
+	connOK    int64
+	connErr   int64
+	queries   int64
+	latencyUs int64
+}
+
+// proxySQLQueryRule is the number of queries matched by a query rule of ProxySQL.
+type proxySQLQueryRule struct {
+	ruleID string
+	hits   int64
+}
+
+// ER_UNSUPPORTED_PS is returned when preparing a statement that can't be prepared.
+const errorNumberUnsupportedPreparedStatement = 1295
+
 type mySQLClient struct {
-	connStr string
-	client  *sql.DB
+	connStr    string
+	tlsKey     string
+	pubKeyKey  string
+	connection ConnectionConfig
+	client     *sql.DB
+	// unprepared is set for the admin interface of ProxySQL, which doesn't support
+	// prepared statements.
+	unprepared bool
+
+	// statements are the prepared statements of the queries, reused across scrapes. A query
+	// mapped to nil can't be prepared and is sent as is. The mutex is shared by the copies of
+	// the client passed to Query.
+	statementsMu *sync.Mutex
+	statements   map[string]*sql.Stmt
 }
 
 var _ client = (*mySQLClient)(nil)
 
-func newMySQLClient(conf *Config) client {
+func newMySQLClient(conf *Config, dataType config.DataType) (client, error) {
+	conf, err := conf.withOptionFile()
+	if err != nil {
+		return nil, err
+	}
 	driverConf := mysql.Config{
 		User:                 conf.Username,
 		Passwd:               conf.Password,
@@ -44,37 +136,323 @@ func newMySQLClient(conf *Config) client {
 		Addr:                 conf.Endpoint,
 		DBName:               conf.Database,
 		AllowNativePasswords: conf.AllowNativePasswords,
+		// mysql_clear_password sends the password as is, e.g. to authenticate with PAM or LDAP.
+		AllowCleartextPasswords: conf.AllowCleartextPasswords,
+	}
+
+	tlsConfig, err := loadTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		// The driver only references TLS configs by name, every receiver registers its own.
+		driverConf.TLSConfig = "otel-" + conf.ID().String() + "-" + string(dataType)
+		if err = mysql.RegisterTLSConfig(driverConf.TLSConfig, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+	if conf.ServerPublicKeyFile != "" {
+		pubKey, err := loadServerPublicKey(conf.ServerPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		// Without a registered key, the key is requested from the server when it isn't
+		// sent over TLS or the unix socket.
+		driverConf.ServerPubKey = "otel-" + conf.ID().String() + "-" + string(dataType)
+		mysql.RegisterServerPubKey(driverConf.ServerPubKey, pubKey)
 	}
 	connStr := driverConf.FormatDSN()
 
 	return &mySQLClient{
-		connStr: connStr,
+		connStr:      connStr,
+		tlsKey:       driverConf.TLSConfig,
+		pubKeyKey:    driverConf.ServerPubKey,
+		connection:   conf.Connection,
+		unprepared:   conf.Mode == modeProxySQL,
+		statementsMu: &sync.Mutex{},
+		statements:   map[string]*sql.Stmt{},
+	}, nil
+}
+
+// loadTLSConfig returns the TLS config of the connections, or nil if TLS is disabled.
+// The client certificate is provided by a certificateReloader instead of being loaded once.
+func loadTLSConfig(conf *Config) (*tls.Config, error) {
+	tlsConfig, err := conf.TLS.LoadTLSConfig()
+	if err != nil || tlsConfig == nil {
+		return nil, err
+	}
+	if conf.TLS.CertFile != "" {
+		reloader, err := newCertificateReloader(conf.TLS.CertFile, conf.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = nil
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
 	}
+	return tlsConfig, nil
 }
 
 func (c *mySQLClient) Connect() error {
-	clientDB, err := sql.Open("mysql", c.connStr)
+	driverConf, err := mysql.ParseDSN(c.connStr)
 	if err != nil {
 		return fmt.Errorf("unable to connect to database: %w", err)
 	}
-	c.client = clientDB
+	connector, err := mysql.NewConnector(driverConf)
+	if err != nil {
+		return fmt.Errorf("unable to connect to database: %w", err)
+	}
+	c.client = sql.OpenDB(authErrorConnector{connector})
+	// The connections are kept open between scrapes instead of paying for a new handshake
+	// every time, they are only closed once older than max_lifetime.
+	c.client.SetMaxOpenConns(c.connection.MaxOpen)
+	c.client.SetMaxIdleConns(c.connection.MaxOpen)
+	c.client.SetConnMaxIdleTime(0)
+	c.client.SetConnMaxLifetime(c.connection.MaxLifetime)
 	return nil
 }
 
+// ping checks a connection to the server is alive, database/sql replaces it otherwise.
+func (c *mySQLClient) ping(ctx context.Context) error {
+	return c.client.PingContext(ctx)
+}
+
+// poolStats returns the stats of the connections of the client, from its connection until now.
+func (c *mySQLClient) poolStats() sql.DBStats {
+	if c.client == nil {
+		return sql.DBStats{}
+	}
+	return c.client.Stats()
+}
+
+// prepare returns the prepared statement of the query, preparing it the first time the query
+// is run. database/sql prepares it again on the connections opened afterwards. It returns nil
+// if the server can't prepare the query.
+func (c *mySQLClient) prepare(query string) (*sql.Stmt, error) {
+	if c.unprepared {
+		return nil, nil
+	}
+	c.statementsMu.Lock()
+	defer c.statementsMu.Unlock()
+	if stmt, ok := c.statements[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := c.client.Prepare(query)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errorNumberUnsupportedPreparedStatement {
+		c.statements[query] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.statements[query] = stmt
+	return stmt, nil
+}
+
+// query runs the query with its prepared statement.
+func (c *mySQLClient) query(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := c.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.client.Query(query, args...)
+	}
+	return stmt.Query(args...)
+}
+
+// queryRow runs the query with its prepared statement, it returns at most one row.
+func (c *mySQLClient) queryRow(query string, args ...interface{}) (*sql.Row, error) {
+	stmt, err := c.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return c.client.QueryRow(query, args...), nil
+	}
+	return stmt.QueryRow(args...), nil
+}
+
 // getGlobalStats queries the db for global status metrics.
 func (c *mySQLClient) getGlobalStats() (map[string]string, error) {
-	query := "SHOW GLOBAL STATUS;"
-	return Query(*c, query)
+	query := "SHOW GLOBAL STATUS"
+	return c.queryStats(query)
 }
 
 // getInnodbStats queries the db for innodb metrics.
 func (c *mySQLClient) getInnodbStats() (map[string]string, error) {
-	query := "SELECT name, count FROM information_schema.innodb_metrics WHERE name LIKE '%buffer_pool_size%';"
-	return Query(*c, query)
+	query := "SELECT name, count FROM information_schema.innodb_metrics WHERE name LIKE '%buffer_pool_size%'"
+	return c.queryStats(query)
 }
 
+// getSchemaSizes queries the db for the data and index sizes of the tables, summed by schema.
+func (c *mySQLClient) getSchemaSizes() ([]schemaSize, error) {
+	query := "SELECT table_schema, COALESCE(SUM(data_length), 0), COALESCE(SUM(index_length), 0) " +
+		"FROM information_schema.tables WHERE table_schema NOT IN ('information_schema', 'performance_schema') " +
+		"GROUP BY table_schema"
+	rows, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var sizes []schemaSize
+	for rows.Next() {
+		var size schemaSize
+		if err := rows.Scan(&size.schema, &size.dataBytes, &size.indexBytes); err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, rows.Err()
+}
+
+// getIndexUsage queries the db for the I/O operations of the tables by index, the rows of a table
+// read without an index have no index name.
+func (c *mySQLClient) getIndexUsage() ([]indexUsage, error) {
+	query := "SELECT object_schema, object_name, COALESCE(index_name, ''), count_read, count_star " +
+		"FROM performance_schema.table_io_waits_summary_by_index_usage " +
+		"WHERE object_schema NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')"
+	rows, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var usages []indexUsage
+	for rows.Next() {
+		var usage indexUsage
+		if err := rows.Scan(&usage.schema, &usage.table, &usage.index, &usage.rowsRead, &usage.operations); err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+	return usages, rows.Err()
+}
+
+// getTransactionStats queries the db for the number of transactions running for longer than
+// longThreshold and waiting for a lock, and the age of the oldest ones, in seconds.
+func (c *mySQLClient) getTransactionStats(longThreshold time.Duration) (transactionStats, error) {
+	query := "SELECT " +
+		"COUNT(CASE WHEN trx_started <= NOW() - INTERVAL ? SECOND THEN 1 END), " +
+		"COALESCE(MAX(TIMESTAMPDIFF(SECOND, trx_started, NOW())), 0), " +
+		"COUNT(CASE WHEN trx_state = 'LOCK WAIT' THEN 1 END), " +
+		"COALESCE(MAX(CASE WHEN trx_state = 'LOCK WAIT' THEN TIMESTAMPDIFF(SECOND, trx_wait_started, NOW()) END), 0) " +
+		"FROM information_schema.innodb_trx"
+	var stats transactionStats
+	row, err := c.queryRow(query, int64(longThreshold/time.Second))
+	if err != nil {
+		return stats, err
+	}
+	err = row.Scan(&stats.longTransactions, &stats.maxTransactionAge, &stats.lockWaits, &stats.maxLockWaitAge)
+	return stats, err
+}
+
+// getMaxConnections queries the db for the maximum number of simultaneous client connections allowed.
+func (c *mySQLClient) getMaxConnections() (int64, error) {
+	row, err := c.queryRow("SELECT @@GLOBAL.max_connections")
+	if err != nil {
+		return 0, err
+	}
+	var maxConnections int64
+	err = row.Scan(&maxConnections)
+	return maxConnections, err
+}
+
+// getErrorLog queries the db for the records of performance_schema.error_log logged after
+// afterMicros, in microseconds since the epoch, ordered by their timestamp.
+func (c *mySQLClient) getErrorLog(afterMicros int64) ([]errorLogRecord, error) {
+	query := "SELECT CAST(UNIX_TIMESTAMP(logged) * 1000000 AS SIGNED) AS logged_micros, COALESCE(thread_id, 0), prio, " +
+		"COALESCE(error_code, ''), COALESCE(subsystem, ''), data FROM performance_schema.error_log " +
+		"HAVING logged_micros > ? ORDER BY logged_micros"
+	rows, err := c.query(query, afterMicros)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []errorLogRecord
+	for rows.Next() {
+		var record errorLogRecord
+		var micros int64
+		if err := rows.Scan(&micros, &record.threadID, &record.priority, &record.errorCode, &record.subsystem, &record.message); err != nil {
+			return nil, err
+		}
+		record.timestamp = time.UnixMicro(micros)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// getErrorLogEnd queries the db for the timestamp of the last record of performance_schema.error_log,
+// in microseconds since the epoch, 0 if the table is empty.
+func (c *mySQLClient) getErrorLogEnd() (int64, error) {
+	query := "SELECT COALESCE(CAST(UNIX_TIMESTAMP(MAX(logged)) * 1000000 AS SIGNED), 0) FROM performance_schema.error_log"
+	row, err := c.queryRow(query)
+	if err != nil {
+		return 0, err
+	}
+	var micros int64
+	err = row.Scan(&micros)
+	return micros, err
+}
+
+// getProxySQLConnectionPool queries the admin interface of ProxySQL for the connection pools
+// to the backend servers.
+func (c *mySQLClient) getProxySQLConnectionPool() ([]proxySQLBackend, error) {
+	query := "SELECT hostgroup, srv_host, srv_port, status, ConnUsed, ConnFree, ConnOK, ConnERR, Queries, Latency_us " +
+		"FROM stats.stats_mysql_connection_pool"
+	rows, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var backends []proxySQLBackend
+	for rows.Next() {
+		var b proxySQLBackend
+		if err := rows.Scan(&b.hostgroup, &b.host, &b.port, &b.status, &b.connUsed, &b.connFree, &b.connOK, &b.connErr,
+			&b.queries, &b.latencyUs); err != nil {
+			return nil, err
+		}
+		backends = append(backends, b)
+	}
+	return backends, rows.Err()
+}
+
+// getProxySQLQueryRules queries the admin interface of ProxySQL for the hits of the query rules.
+func (c *mySQLClient) getProxySQLQueryRules() ([]proxySQLQueryRule, error) {
+	query := "SELECT rule_id, hits FROM stats.stats_mysql_query_rules"
+	rows, err := c.query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rules []proxySQLQueryRule
+	for rows.Next() {
+		var rule proxySQLQueryRule
+		if err := rows.Scan(&rule.ruleID, &rule.hits); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// getProxySQLGlobalStats queries the admin interface of ProxySQL for the connection pool
+// variables of its global status.
+func (c *mySQLClient) getProxySQLGlobalStats() (map[string]string, error) {
+	query := "SELECT Variable_Name, Variable_Value FROM stats.stats_mysql_global WHERE Variable_Name LIKE 'ConnPool_%'"
+	return c.queryStats(query)
+}
+
+// Query runs a query returning name and value pairs.
+//
+// Deprecated: Query is only used by the receiver and will be removed, the queries of the client
+// are run with prepared statements.
 func Query(c mySQLClient, query string) (map[string]string, error) {
-	rows, err := c.client.Query(query)
+	return c.queryStats(query)
+}
+
+// queryStats runs a query returning name and value pairs.
+func (c *mySQLClient) queryStats(query string) (map[string]string, error) {
+	rows, err := c.query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -92,8 +470,43 @@ func Query(c mySQLClient, query string) (map[string]string, error) {
 }
 
 func (c *mySQLClient) Close() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.tlsKey != "" {
+		mysql.DeregisterTLSConfig(c.tlsKey)
+	}
+	if c.pubKeyKey != "" {
+		mysql.DeregisterServerPubKey(c.pubKeyKey)
+	}
+	if c.client == nil {
+		return nil
+	}
+	c.statementsMu.Lock()
+	for _, stmt := range c.statements {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	c.statements = map[string]*sql.Stmt{}
+	c.statementsMu.Unlock()
+	return c.client.Close()
+}
+
+// healthCheck pings the server every interval until ctx is done, so that a broken connection is
+// replaced before the next scrape and an idle one isn't closed by the wait_timeout of the server.
+// onError is called with the errors of the failed pings.
+func healthCheck(ctx context.Context, c client, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, interval)
+			err := c.ping(pingCtx)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
 	}
-	return nil
 }
@@ -15,10 +15,20 @@
 package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
+const (
+	modeMySQL    = "mysql"
+	modeProxySQL = "proxysql"
+)
+
 type Config struct {
 	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
 	Username                                string `mapstructure:"username,omitempty"`
@@ -26,4 +36,124 @@ type Config struct {
 	Database                                string `mapstructure:"database,omitempty"`
 	AllowNativePasswords                    bool   `mapstructure:"allow_native_passwords,omitempty"`
 	confignet.NetAddr                       `mapstructure:",squash"`
+	// AllowCleartextPasswords enables the mysql_clear_password plugin, used by the users
+	// authenticated with PAM or LDAP. Requires TLS or the unix transport.
+	AllowCleartextPasswords bool `mapstructure:"allow_cleartext_passwords,omitempty"`
+	// ServerPublicKeyFile is the path of the PEM encoded RSA public key of the server, used
+	// by caching_sha2_password and sha256_password to encrypt the password without TLS.
+	// The key is requested from the server if not set.
+	ServerPublicKeyFile string `mapstructure:"server_public_key_file,omitempty"`
+	// TLS configures the connection to the server. The client certificate is
+	// reloaded from disk once rotated.
+	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+	// AggregationTemporality of the monotonic sums, either cumulative or delta.
+	// Deltas are computed from the values of consecutive scrapes.
+	AggregationTemporality string `mapstructure:"aggregation_temporality,omitempty"`
+	// SchemaSizes enables the collection of the data and index sizes of the schemas
+	// from information_schema.tables.
+	SchemaSizes bool `mapstructure:"schema_sizes,omitempty"`
+	// IndexUsage enables the collection of the rows read through the indexes and by full
+	// table scans, and of the unused indexes, from performance_schema.
+	IndexUsage bool `mapstructure:"index_usage,omitempty"`
+	// Transactions enables the collection of the long transaction and lock wait metrics
+	// from information_schema.innodb_trx.
+	Transactions bool `mapstructure:"transactions,omitempty"`
+	// ConnectionStates enables the collection of the threads of the client connections by
+	// state, of the threads created, and of the highest number of connections compared to max_connections.
+	ConnectionStates bool `mapstructure:"connection_states,omitempty"`
+	// LongTransactionThreshold is the duration after which a running transaction is long.
+	LongTransactionThreshold time.Duration `mapstructure:"long_transaction_threshold,omitempty"`
+	// ErrorLog configures where the logs receiver reads the records of the error log from.
+	ErrorLog ErrorLogConfig `mapstructure:"error_log,omitempty"`
+	// Connection configures the connections to the server, which are reused across scrapes.
+	Connection ConnectionConfig `mapstructure:"connection,omitempty"`
+	// Mode is either mysql to scrape a MySQL server, or proxysql to scrape the stats of
+	// the admin interface of a ProxySQL server fronting MySQL servers.
+	Mode string `mapstructure:"mode,omitempty"`
+	// OptionFile is a MySQL option file the connection settings are read from, taking
+	// precedence over the settings of the config.
+	OptionFile OptionFileConfig `mapstructure:"option_file,omitempty"`
+}
+
+// OptionFileConfig defines the MySQL option file to read and its option groups.
+type OptionFileConfig struct {
+	// Path of the option file, e.g. ~/.my.cnf, where ~ is the home directory of the user
+	// running the collector.
+	Path string `mapstructure:"path,omitempty"`
+	// Groups are the option groups read from the file, the options of a group overriding
+	// those of the previous groups. Defaults to client.
+	Groups []string `mapstructure:"groups,omitempty"`
+}
+
+// ConnectionConfig defines the pool of connections to the server.
+type ConnectionConfig struct {
+	// MaxOpen is the maximum number of connections opened to the server. Defaults to 1.
+	MaxOpen int `mapstructure:"max_open,omitempty"`
+	// MaxLifetime is the duration after which a connection is closed and opened again.
+	// Defaults to 0, meaning connections are reused until they break.
+	MaxLifetime time.Duration `mapstructure:"max_lifetime,omitempty"`
+	// HealthCheckInterval is the interval at which the connection is checked, so that a broken
+	// connection is replaced before a scrape and an idle one isn't closed by the wait_timeout
+	// of the server. Defaults to 30s, 0 disables the health check.
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval,omitempty"`
+}
+
+// ErrorLogConfig defines the source of the error log records.
+type ErrorLogConfig struct {
+	// Source is either performance_schema to query the performance_schema.error_log table of
+	// MySQL 8.0.22+, or file to tail the error log file at Path.
+	Source string `mapstructure:"source,omitempty"`
+	// Path of the error log file, the value of the log_error system variable.
+	Path string `mapstructure:"path,omitempty"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.AllowCleartextPasswords && cfg.TLS.Insecure && cfg.Transport != "unix" {
+		return errors.New("allow_cleartext_passwords requires TLS or the unix transport")
+	}
+	if cfg.LongTransactionThreshold < 0 {
+		return errors.New("long_transaction_threshold must not be negative")
+	}
+	if cfg.Connection.MaxOpen < 1 {
+		return errors.New("connection.max_open must be positive")
+	}
+	if cfg.Connection.MaxLifetime < 0 {
+		return errors.New("connection.max_lifetime must not be negative")
+	}
+	if cfg.Connection.HealthCheckInterval < 0 {
+		return errors.New("connection.health_check_interval must not be negative")
+	}
+	switch cfg.AggregationTemporality {
+	case "", temporalityCumulative, temporalityDelta:
+	default:
+		return fmt.Errorf("invalid aggregation_temporality %q: can be either %q or %q", cfg.AggregationTemporality, temporalityCumulative, temporalityDelta)
+	}
+	switch cfg.Mode {
+	case "", modeMySQL:
+	case modeProxySQL:
+		if cfg.SchemaSizes || cfg.IndexUsage || cfg.Transactions || cfg.ConnectionStates {
+			return errors.New("schema_sizes, index_usage, transactions and connection_states are not supported in proxysql mode")
+		}
+	default:
+		return fmt.Errorf("invalid mode %q: can be either %q or %q", cfg.Mode, modeMySQL, modeProxySQL)
+	}
+	if len(cfg.OptionFile.Groups) > 0 && cfg.OptionFile.Path == "" {
+		return errors.New("option_file.path must be set with option_file.groups")
+	}
+	for _, group := range cfg.OptionFile.Groups {
+		if group == "" {
+			return errors.New("option_file.groups must not contain empty group names")
+		}
+	}
+	switch cfg.ErrorLog.Source {
+	case "", errorLogSourceTable:
+	case errorLogSourceFile:
+		if cfg.ErrorLog.Path == "" {
+			return errors.New("error_log.path must be set when error_log.source is file")
+		}
+	default:
+		return fmt.Errorf("invalid error_log.source %q: can be either %q or %q", cfg.ErrorLog.Source, errorLogSourceTable, errorLogSourceFile)
+	}
+	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	temporalityCumulative = "cumulative"
+	temporalityDelta      = "delta"
+)
+
+// deltaPoint is the last cumulative value seen for a data point.
+type deltaPoint struct {
+	intVal    int64
+	doubleVal float64
+	timestamp pdata.Timestamp
+}
+
+// deltaCalculator converts monotonic cumulative sums to delta sums by computing
+// the difference between the values returned by consecutive scrapes.
+type deltaCalculator struct {
+	previous map[string]deltaPoint
+}
+
+func newDeltaCalculator() *deltaCalculator {
+	return &deltaCalculator{previous: map[string]deltaPoint{}}
+}
+
+// convert rewrites the monotonic cumulative sums of ms in place. Data points seen for
+// the first time are dropped since there is no previous value to compute a delta from.
+// A value lower than the previous one means the server counters were reset, e.g. by a
+// restart, so the value itself is reported as the delta.
+func (d *deltaCalculator) convert(ms pdata.MetricSlice) {
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.DataType() != pdata.MetricDataTypeSum || !m.Sum().IsMonotonic() ||
+			m.Sum().AggregationTemporality() != pdata.MetricAggregationTemporalityCumulative {
+			continue
+		}
+		m.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+		m.Sum().DataPoints().RemoveIf(func(dp pdata.NumberDataPoint) bool {
+			key := pointKey(m.Name(), dp.Attributes())
+			prev, ok := d.previous[key]
+			d.previous[key] = deltaPoint{intVal: dp.IntVal(), doubleVal: dp.DoubleVal(), timestamp: dp.Timestamp()}
+			if !ok {
+				return true
+			}
+
+			dp.SetStartTimestamp(prev.timestamp)
+			switch dp.Type() {
+			case pdata.MetricValueTypeInt:
+				if dp.IntVal() >= prev.intVal {
+					dp.SetIntVal(dp.IntVal() - prev.intVal)
+				}
+			case pdata.MetricValueTypeDouble:
+				if dp.DoubleVal() >= prev.doubleVal {
+					dp.SetDoubleVal(dp.DoubleVal() - prev.doubleVal)
+				}
+			}
+			return false
+		})
+	}
+}
+
+// pointKey identifies a data point by its metric name and attributes.
+func pointKey(name string, attrs pdata.AttributeMap) string {
+	var b strings.Builder
+	b.WriteString(name)
+	attrs.Sort().Range(func(k string, v pdata.AttributeValue) bool {
+		fmt.Fprintf(&b, ";%s=%s", k, v.AsString())
+		return true
+	})
+	return b.String()
+}
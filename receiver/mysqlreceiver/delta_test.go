@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func newSum(ms pdata.MetricSlice, name string, monotonic bool) pdata.NumberDataPointSlice {
+	m := ms.AppendEmpty()
+	m.SetName(name)
+	m.SetDataType(pdata.MetricDataTypeSum)
+	m.Sum().SetIsMonotonic(monotonic)
+	m.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	return m.Sum().DataPoints()
+}
+
+func addIntPoint(dps pdata.NumberDataPointSlice, kind string, value int64, ts pdata.Timestamp) {
+	dp := dps.AppendEmpty()
+	dp.Attributes().InsertString("kind", kind)
+	dp.SetIntVal(value)
+	dp.SetTimestamp(ts)
+}
+
+func TestDeltaCalculator(t *testing.T) {
+	d := newDeltaCalculator()
+
+	// The first scrape has no previous value to compute deltas from.
+	ms := pdata.NewMetricSlice()
+	counter := newSum(ms, "counter", true)
+	addIntPoint(counter, "a", 10, 1)
+	addIntPoint(counter, "b", 20, 1)
+	gauge := newSum(ms, "gauge", false)
+	addIntPoint(gauge, "a", 5, 1)
+	d.convert(ms)
+	require.Equal(t, 0, ms.At(0).Sum().DataPoints().Len())
+	assert.Equal(t, pdata.MetricAggregationTemporalityDelta, ms.At(0).Sum().AggregationTemporality())
+	require.Equal(t, 1, ms.At(1).Sum().DataPoints().Len())
+	assert.Equal(t, pdata.MetricAggregationTemporalityCumulative, ms.At(1).Sum().AggregationTemporality())
+
+	// The counter of b is reset between scrapes.
+	ms = pdata.NewMetricSlice()
+	counter = newSum(ms, "counter", true)
+	addIntPoint(counter, "a", 15, 2)
+	addIntPoint(counter, "b", 3, 2)
+	double := newSum(ms, "double", true)
+	dp := double.AppendEmpty()
+	dp.SetDoubleVal(1.5)
+	dp.SetTimestamp(2)
+	d.convert(ms)
+	dps := ms.At(0).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, int64(5), dps.At(0).IntVal())
+	assert.Equal(t, pdata.Timestamp(1), dps.At(0).StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(2), dps.At(0).Timestamp())
+	assert.Equal(t, int64(3), dps.At(1).IntVal())
+	assert.Equal(t, pdata.Timestamp(1), dps.At(1).StartTimestamp())
+	assert.Equal(t, 0, ms.At(1).Sum().DataPoints().Len())
+
+	ms = pdata.NewMetricSlice()
+	double = newSum(ms, "double", true)
+	dp = double.AppendEmpty()
+	dp.SetDoubleVal(4)
+	dp.SetTimestamp(3)
+	d.convert(ms)
+	dps = ms.At(0).Sum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	assert.Equal(t, 2.5, dps.At(0).DoubleVal())
+	assert.Equal(t, pdata.Timestamp(2), dps.At(0).StartTimestamp())
+}
@@ -0,0 +1,169 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	errorLogSourceTable = "performance_schema"
+	errorLogSourceFile  = "file"
+)
+
+// errorLogLine matches the first line of the records of the error log file, e.g.
+// "2022-01-25T10:00:00.123456Z 0 [System] [MY-010116] [Server] starting as process 1" with MySQL
+// 8.0, or "2022-01-25T10:00:00.123456Z 0 [Note] mysqld: ready for connections." with MySQL 5.7.
+var errorLogLine = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\S+) (\d+) \[(\w+)\] (?:\[(MY-\d+)\] \[(\w+)\] )?(.*)$`)
+
+// errorLogSource reads the records of the error log which haven't been read yet.
+type errorLogSource interface {
+	// seekEnd skips the records logged so far.
+	seekEnd() error
+	read() ([]errorLogRecord, error)
+}
+
+// tableErrorLog reads the records of the performance_schema.error_log table.
+type tableErrorLog struct {
+	sqlclient client
+	// afterMicros is the timestamp of the last record read, in microseconds since the epoch.
+	afterMicros int64
+}
+
+var _ errorLogSource = (*tableErrorLog)(nil)
+
+func (t *tableErrorLog) seekEnd() error {
+	micros, err := t.sqlclient.getErrorLogEnd()
+	if err != nil {
+		return err
+	}
+	t.afterMicros = micros
+	return nil
+}
+
+func (t *tableErrorLog) read() ([]errorLogRecord, error) {
+	records, err := t.sqlclient.getErrorLog(t.afterMicros)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 {
+		t.afterMicros = records[len(records)-1].timestamp.UnixMicro()
+	}
+	return records, nil
+}
+
+// fileErrorLog tails the error log file, which is read from the beginning once rotated or truncated.
+type fileErrorLog struct {
+	path string
+	// info is the info of the file read last, and offset the end of its last line read.
+	info   os.FileInfo
+	offset int64
+}
+
+var _ errorLogSource = (*fileErrorLog)(nil)
+
+func (f *fileErrorLog) seekEnd() error {
+	info, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		// The records will be read from the beginning of the file once created.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.info = info
+	f.offset = info.Size()
+	return nil
+}
+
+func (f *fileErrorLog) read() ([]errorLogRecord, error) {
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if f.info == nil || !os.SameFile(f.info, info) || info.Size() < f.offset {
+		f.offset = 0
+	}
+	f.info = info
+	if _, err = file.Seek(f.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, info.Size()-f.offset))
+	if err != nil {
+		return nil, err
+	}
+	// The last line is only read once complete, the server may still be writing it.
+	end := bytes.LastIndexByte(data, '\n') + 1
+	f.offset += int64(end)
+	return parseErrorLog(data[:end]), nil
+}
+
+// parseErrorLog parses the lines of the error log file. The lines not starting with a timestamp,
+// e.g. stack traces, are appended to the message of the previous record.
+func parseErrorLog(data []byte) []errorLogRecord {
+	var records []errorLogRecord
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		if record, ok := parseErrorLogLine(line); ok {
+			records = append(records, record)
+		} else if len(records) > 0 {
+			records[len(records)-1].message += "\n" + line
+		} else {
+			records = append(records, errorLogRecord{message: line})
+		}
+	}
+	return records
+}
+
+func parseErrorLogLine(line string) (errorLogRecord, bool) {
+	match := errorLogLine.FindStringSubmatch(line)
+	if match == nil {
+		return errorLogRecord{}, false
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, match[1])
+	if err != nil {
+		return errorLogRecord{}, false
+	}
+	threadID, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return errorLogRecord{}, false
+	}
+	return errorLogRecord{
+		timestamp: timestamp,
+		threadID:  threadID,
+		priority:  match[3],
+		errorCode: match[4],
+		subsystem: match[5],
+		message:   match[6],
+	}, true
+}
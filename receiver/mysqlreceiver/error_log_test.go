@@ -0,0 +1,148 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorLog(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "error_log", "mysqld.log"))
+	require.NoError(t, err)
+
+	records := parseErrorLog(data)
+	require.Len(t, records, 4)
+	assert.Equal(t, errorLogRecord{
+		timestamp: time.Date(2022, 1, 25, 10, 0, 0, 123456000, time.UTC),
+		threadID:  0,
+		priority:  "System",
+		errorCode: "MY-010116",
+		subsystem: "Server",
+		message:   "/usr/sbin/mysqld (mysqld 8.0.28) starting as process 1",
+	}, records[0])
+	assert.Equal(t, "Warning", records[1].priority)
+
+	// MySQL 5.7 records have neither error code nor subsystem.
+	assert.Equal(t, int64(12), records[2].threadID)
+	assert.Equal(t, "Note", records[2].priority)
+	assert.Empty(t, records[2].errorCode)
+	assert.Empty(t, records[2].subsystem)
+	assert.Equal(t, "mysqld: ready for connections.", records[2].message)
+	assert.True(t, time.Date(2022, 1, 25, 9, 0, 2, 500000000, time.UTC).Equal(records[2].timestamp))
+
+	assert.Equal(t, "Error", records[3].priority)
+	assert.Equal(t, "Operating system error number 2 in a file operation.\nStack trace:\n  mysqld(my_print_stacktrace+0x2c)", records[3].message)
+}
+
+func TestParseErrorLogContinuation(t *testing.T) {
+	records := parseErrorLog([]byte("  at frame 2\r\n2022-01-25T10:00:00Z 3 [Note] done\r\n"))
+	require.Len(t, records, 2)
+	assert.Equal(t, errorLogRecord{message: "  at frame 2"}, records[0])
+	assert.Equal(t, "done", records[1].message)
+}
+
+func TestFileErrorLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mysqld.log")
+	source := &fileErrorLog{path: path}
+
+	// The records of a file created after the receiver was started are all read.
+	require.NoError(t, source.seekEnd())
+	writeErrorLog(t, path, os.O_CREATE|os.O_TRUNC, "2022-01-25T10:00:00Z 0 [Note] first\n")
+	records, err := source.read()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "first", records[0].message)
+
+	// Incomplete lines are read once complete.
+	writeErrorLog(t, path, os.O_APPEND, "2022-01-25T10:00:01Z 0 [Note] sec")
+	records, err = source.read()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	writeErrorLog(t, path, os.O_APPEND, "ond\n")
+	records, err = source.read()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "second", records[0].message)
+
+	// The file is read from the beginning once truncated.
+	writeErrorLog(t, path, os.O_TRUNC, "2022-01-25T10:00:02Z 0 [Note] 3\n")
+	records, err = source.read()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "3", records[0].message)
+
+	// The new file is read from the beginning once rotated, even if it is larger than the offset.
+	require.NoError(t, os.Rename(path, path+".1"))
+	writeErrorLog(t, path, os.O_CREATE, "2022-01-25T10:00:03Z 0 [Note] rotated, longer than the previous file\n")
+	records, err = source.read()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "rotated, longer than the previous file", records[0].message)
+
+	records, err = source.read()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestFileErrorLogSeekEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mysqld.log")
+	writeErrorLog(t, path, os.O_CREATE, "2022-01-25T10:00:00Z 0 [Note] before start\n")
+	source := &fileErrorLog{path: path}
+	require.NoError(t, source.seekEnd())
+
+	writeErrorLog(t, path, os.O_APPEND, "2022-01-25T10:00:01Z 0 [Note] after start\n")
+	records, err := source.read()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "after start", records[0].message)
+}
+
+func TestTableErrorLog(t *testing.T) {
+	first := errorLogRecord{timestamp: time.UnixMicro(1643104800123456), priority: "Note", message: "first"}
+	second := errorLogRecord{timestamp: time.UnixMicro(1643104800123457), priority: "Error", message: "second"}
+	sqlclient := &mockClient{errorLog: []errorLogRecord{first}}
+	source := &tableErrorLog{sqlclient: sqlclient}
+
+	require.NoError(t, source.seekEnd())
+	// Only the timestamp of the last record is queried.
+	assert.Equal(t, first.timestamp.UnixMicro(), source.afterMicros)
+	records, err := source.read()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	sqlclient.errorLog = append(sqlclient.errorLog, second)
+	records, err = source.read()
+	require.NoError(t, err)
+	assert.Equal(t, []errorLogRecord{second}, records)
+
+	sqlclient.errorLogErr = errors.New("table doesn't exist")
+	_, err = source.read()
+	assert.Error(t, err)
+}
+
+func writeErrorLog(t *testing.T, path string, flag int, data string) {
+	f, err := os.OpenFile(path, flag|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
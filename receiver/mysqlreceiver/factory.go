@@ -20,9 +20,11 @@ import (
 	"context"
 	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
@@ -30,13 +32,18 @@ import (
 
 const (
 	typeStr = "mysql"
+
+	defaultLongTransactionThreshold = time.Minute
+	defaultHealthCheckInterval      = 30 * time.Second
 )
 
 func NewFactory() component.ReceiverFactory {
+	_ = view.Register(MetricViews()...)
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
@@ -51,6 +58,19 @@ func createDefaultConfig() config.Receiver {
 			Endpoint:  "localhost:3306",
 			Transport: "tcp",
 		},
+		TLS: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		AggregationTemporality:   temporalityCumulative,
+		LongTransactionThreshold: defaultLongTransactionThreshold,
+		ErrorLog: ErrorLogConfig{
+			Source: errorLogSourceTable,
+		},
+		Connection: ConnectionConfig{
+			MaxOpen:             1,
+			HealthCheckInterval: defaultHealthCheckInterval,
+		},
+		Mode: modeMySQL,
 	}
 }
 
@@ -75,3 +95,13 @@ func createMetricsReceiver(
 		scraperhelper.AddScraper(scraper),
 	)
 }
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateSettings,
+	rConf config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	cfg := rConf.(*Config)
+	return newErrorLogReceiver(params.Logger, cfg, consumer), nil
+}
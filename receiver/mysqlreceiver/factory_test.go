@@ -42,6 +42,97 @@ func TestValidConfig(t *testing.T) {
 	require.NoError(t, cfg.Validate())
 }
 
+func TestInvalidAggregationTemporality(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.AggregationTemporality = "gauge"
+	require.EqualError(t, cfg.Validate(), `invalid aggregation_temporality "gauge": can be either "cumulative" or "delta"`)
+}
+
+func TestInvalidLongTransactionThreshold(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.LongTransactionThreshold = -time.Second
+	require.EqualError(t, cfg.Validate(), "long_transaction_threshold must not be negative")
+}
+
+func TestInvalidErrorLog(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ErrorLog.Source = "syslog"
+	require.EqualError(t, cfg.Validate(), `invalid error_log.source "syslog": can be either "performance_schema" or "file"`)
+
+	cfg.ErrorLog.Source = "file"
+	require.EqualError(t, cfg.Validate(), "error_log.path must be set when error_log.source is file")
+}
+
+func TestInvalidConnection(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Connection.MaxOpen = 0
+	require.EqualError(t, cfg.Validate(), "connection.max_open must be positive")
+
+	cfg.Connection.MaxOpen = 1
+	cfg.Connection.MaxLifetime = -time.Second
+	require.EqualError(t, cfg.Validate(), "connection.max_lifetime must not be negative")
+
+	cfg.Connection.MaxLifetime = 0
+	cfg.Connection.HealthCheckInterval = -time.Second
+	require.EqualError(t, cfg.Validate(), "connection.health_check_interval must not be negative")
+
+	cfg.Connection.HealthCheckInterval = 0
+	require.NoError(t, cfg.Validate())
+}
+
+func TestInvalidMode(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Mode = "mariadb"
+	require.EqualError(t, cfg.Validate(), `invalid mode "mariadb": can be either "mysql" or "proxysql"`)
+
+	cfg.Mode = "proxysql"
+	require.NoError(t, cfg.Validate())
+
+	cfg.SchemaSizes = true
+	require.EqualError(t, cfg.Validate(), "schema_sizes, index_usage, transactions and connection_states are not supported in proxysql mode")
+
+	cfg.SchemaSizes = false
+	cfg.IndexUsage = true
+	require.EqualError(t, cfg.Validate(), "schema_sizes, index_usage, transactions and connection_states are not supported in proxysql mode")
+
+	cfg.IndexUsage = false
+	cfg.ConnectionStates = true
+	require.EqualError(t, cfg.Validate(), "schema_sizes, index_usage, transactions and connection_states are not supported in proxysql mode")
+}
+
+func TestInvalidCleartextPasswords(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.AllowCleartextPasswords = true
+	require.EqualError(t, cfg.Validate(), "allow_cleartext_passwords requires TLS or the unix transport")
+
+	cfg.Transport = "unix"
+	cfg.Endpoint = "/var/run/mysqld/mysqld.sock"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Transport = "tcp"
+	cfg.TLS.Insecure = false
+	require.NoError(t, cfg.Validate())
+}
+
+func TestInvalidOptionFile(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.OptionFile.Groups = []string{"client"}
+	require.EqualError(t, cfg.Validate(), "option_file.path must be set with option_file.groups")
+
+	cfg.OptionFile.Path = "~/.my.cnf"
+	require.NoError(t, cfg.Validate())
+
+	cfg.OptionFile.Groups = []string{"client", ""}
+	require.EqualError(t, cfg.Validate(), "option_file.groups must not contain empty group names")
+}
+
 func TestCreateMetricsReceiver(t *testing.T) {
 	factory := NewFactory()
 	metricsReceiver, err := factory.CreateMetricsReceiver(
@@ -63,3 +154,15 @@ func TestCreateMetricsReceiver(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, metricsReceiver)
 }
+
+func TestCreateLogsReceiver(t *testing.T) {
+	factory := NewFactory()
+	logsReceiver, err := factory.CreateLogsReceiver(
+		context.Background(),
+		componenttest.NewNopReceiverCreateSettings(),
+		factory.CreateDefaultConfig(),
+		consumertest.NewNop(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, logsReceiver)
+}
@@ -11,7 +11,10 @@ require (
 	go.uber.org/zap v1.20.0
 )
 
-require github.com/testcontainers/testcontainers-go v0.12.0
+require (
+	github.com/testcontainers/testcontainers-go v0.12.0
+	go.opencensus.io v0.23.0
+)
 
 require (
 	github.com/Azure/go-ansiterm v0.0.0-20170929234023-d6e3b3328b78 // indirect
@@ -49,7 +52,6 @@ require (
 	github.com/sirupsen/logrus v1.8.1 // indirect
 	github.com/spf13/afero v1.6.0 // indirect
 	github.com/spf13/cast v1.4.1 // indirect
-	go.opencensus.io v0.23.0 // indirect
 	go.opentelemetry.io/otel v1.3.0 // indirect
 	go.opentelemetry.io/otel/metric v0.26.0 // indirect
 	go.opentelemetry.io/otel/trace v1.3.0 // indirect
@@ -41,20 +41,38 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	MysqlBufferPoolOperations MetricIntf
-	MysqlBufferPoolPages      MetricIntf
-	MysqlBufferPoolSize       MetricIntf
-	MysqlCommands             MetricIntf
-	MysqlDoubleWrites         MetricIntf
-	MysqlHandlers             MetricIntf
-	MysqlLocks                MetricIntf
-	MysqlLogOperations        MetricIntf
-	MysqlOperations           MetricIntf
-	MysqlPageOperations       MetricIntf
-	MysqlRowLocks             MetricIntf
-	MysqlRowOperations        MetricIntf
-	MysqlSorts                MetricIntf
-	MysqlThreads              MetricIntf
+	MysqlBufferPoolOperations              MetricIntf
+	MysqlBufferPoolPages                   MetricIntf
+	MysqlBufferPoolSize                    MetricIntf
+	MysqlCommands                          MetricIntf
+	MysqlConnectionLimit                   MetricIntf
+	MysqlDoubleWrites                      MetricIntf
+	MysqlHandlers                          MetricIntf
+	MysqlIndexRowsRead                     MetricIntf
+	MysqlIndexUnused                       MetricIntf
+	MysqlLockWaits                         MetricIntf
+	MysqlLockWaitsMaxAge                   MetricIntf
+	MysqlLocks                             MetricIntf
+	MysqlLogOperations                     MetricIntf
+	MysqlOperations                        MetricIntf
+	MysqlPageOperations                    MetricIntf
+	MysqlProxysqlConnectionPoolConnections MetricIntf
+	MysqlProxysqlConnectionPoolConnects    MetricIntf
+	MysqlProxysqlConnectionPoolGets        MetricIntf
+	MysqlProxysqlConnectionPoolLatency     MetricIntf
+	MysqlProxysqlConnectionPoolQueries     MetricIntf
+	MysqlProxysqlQueryRuleHits             MetricIntf
+	MysqlProxysqlServerStatus              MetricIntf
+	MysqlRowLocks                          MetricIntf
+	MysqlRowOperations                     MetricIntf
+	MysqlSchemaSize                        MetricIntf
+	MysqlSorts                             MetricIntf
+	MysqlTableFullScanRowsRead             MetricIntf
+	MysqlThreads                           MetricIntf
+	MysqlThreadsCount                      MetricIntf
+	MysqlThreadsCreated                    MetricIntf
+	MysqlTransactionsLong                  MetricIntf
+	MysqlTransactionsMaxAge                MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
@@ -64,34 +82,70 @@ func (m *metricStruct) Names() []string {
 		"mysql.buffer_pool_pages",
 		"mysql.buffer_pool_size",
 		"mysql.commands",
+		"mysql.connection.limit",
 		"mysql.double_writes",
 		"mysql.handlers",
+		"mysql.index.rows_read",
+		"mysql.index.unused",
+		"mysql.lock_waits",
+		"mysql.lock_waits.max_age",
 		"mysql.locks",
 		"mysql.log_operations",
 		"mysql.operations",
 		"mysql.page_operations",
+		"mysql.proxysql.connection_pool.connections",
+		"mysql.proxysql.connection_pool.connects",
+		"mysql.proxysql.connection_pool.gets",
+		"mysql.proxysql.connection_pool.latency",
+		"mysql.proxysql.connection_pool.queries",
+		"mysql.proxysql.query_rule.hits",
+		"mysql.proxysql.server.status",
 		"mysql.row_locks",
 		"mysql.row_operations",
+		"mysql.schema.size",
 		"mysql.sorts",
+		"mysql.table.full_scan.rows_read",
 		"mysql.threads",
+		"mysql.threads.count",
+		"mysql.threads.created",
+		"mysql.transactions.long",
+		"mysql.transactions.max_age",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"mysql.buffer_pool_operations": Metrics.MysqlBufferPoolOperations,
-	"mysql.buffer_pool_pages":      Metrics.MysqlBufferPoolPages,
-	"mysql.buffer_pool_size":       Metrics.MysqlBufferPoolSize,
-	"mysql.commands":               Metrics.MysqlCommands,
-	"mysql.double_writes":          Metrics.MysqlDoubleWrites,
-	"mysql.handlers":               Metrics.MysqlHandlers,
-	"mysql.locks":                  Metrics.MysqlLocks,
-	"mysql.log_operations":         Metrics.MysqlLogOperations,
-	"mysql.operations":             Metrics.MysqlOperations,
-	"mysql.page_operations":        Metrics.MysqlPageOperations,
-	"mysql.row_locks":              Metrics.MysqlRowLocks,
-	"mysql.row_operations":         Metrics.MysqlRowOperations,
-	"mysql.sorts":                  Metrics.MysqlSorts,
-	"mysql.threads":                Metrics.MysqlThreads,
+	"mysql.buffer_pool_operations":               Metrics.MysqlBufferPoolOperations,
+	"mysql.buffer_pool_pages":                    Metrics.MysqlBufferPoolPages,
+	"mysql.buffer_pool_size":                     Metrics.MysqlBufferPoolSize,
+	"mysql.commands":                             Metrics.MysqlCommands,
+	"mysql.connection.limit":                     Metrics.MysqlConnectionLimit,
+	"mysql.double_writes":                        Metrics.MysqlDoubleWrites,
+	"mysql.handlers":                             Metrics.MysqlHandlers,
+	"mysql.index.rows_read":                      Metrics.MysqlIndexRowsRead,
+	"mysql.index.unused":                         Metrics.MysqlIndexUnused,
+	"mysql.lock_waits":                           Metrics.MysqlLockWaits,
+	"mysql.lock_waits.max_age":                   Metrics.MysqlLockWaitsMaxAge,
+	"mysql.locks":                                Metrics.MysqlLocks,
+	"mysql.log_operations":                       Metrics.MysqlLogOperations,
+	"mysql.operations":                           Metrics.MysqlOperations,
+	"mysql.page_operations":                      Metrics.MysqlPageOperations,
+	"mysql.proxysql.connection_pool.connections": Metrics.MysqlProxysqlConnectionPoolConnections,
+	"mysql.proxysql.connection_pool.connects":    Metrics.MysqlProxysqlConnectionPoolConnects,
+	"mysql.proxysql.connection_pool.gets":        Metrics.MysqlProxysqlConnectionPoolGets,
+	"mysql.proxysql.connection_pool.latency":     Metrics.MysqlProxysqlConnectionPoolLatency,
+	"mysql.proxysql.connection_pool.queries":     Metrics.MysqlProxysqlConnectionPoolQueries,
+	"mysql.proxysql.query_rule.hits":             Metrics.MysqlProxysqlQueryRuleHits,
+	"mysql.proxysql.server.status":               Metrics.MysqlProxysqlServerStatus,
+	"mysql.row_locks":                            Metrics.MysqlRowLocks,
+	"mysql.row_operations":                       Metrics.MysqlRowOperations,
+	"mysql.schema.size":                          Metrics.MysqlSchemaSize,
+	"mysql.sorts":                                Metrics.MysqlSorts,
+	"mysql.table.full_scan.rows_read":            Metrics.MysqlTableFullScanRowsRead,
+	"mysql.threads":                              Metrics.MysqlThreads,
+	"mysql.threads.count":                        Metrics.MysqlThreadsCount,
+	"mysql.threads.created":                      Metrics.MysqlThreadsCreated,
+	"mysql.transactions.long":                    Metrics.MysqlTransactionsLong,
+	"mysql.transactions.max_age":                 Metrics.MysqlTransactionsMaxAge,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -145,6 +199,15 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.connection.limit",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.connection.limit")
+			metric.SetDescription("The highest number of connections open at the same time since the server started, and the maximum number of connections allowed.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
 	&metricImpl{
 		"mysql.double_writes",
 		func(metric pdata.Metric) {
@@ -167,6 +230,44 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.index.rows_read",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.index.rows_read")
+			metric.SetDescription("The number of rows read from a table through an index.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.index.unused",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.index.unused")
+			metric.SetDescription("The number of secondary indexes of a schema not used since the server started.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"mysql.lock_waits",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.lock_waits")
+			metric.SetDescription("The number of InnoDB transactions waiting for a lock.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"mysql.lock_waits.max_age",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.lock_waits.max_age")
+			metric.SetDescription("The time the longest waiting InnoDB transaction has been waiting for a lock.")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
 	&metricImpl{
 		"mysql.locks",
 		func(metric pdata.Metric) {
@@ -211,6 +312,79 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.proxysql.connection_pool.connections",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.connection_pool.connections")
+			metric.SetDescription("The number of connections of the ProxySQL connection pool to a backend server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(false)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.connection_pool.connects",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.connection_pool.connects")
+			metric.SetDescription("The number of connections established by ProxySQL to a backend server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.connection_pool.gets",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.connection_pool.gets")
+			metric.SetDescription("The number of connections requested from the ProxySQL connection pool, the immediate ones were served by an idle connection.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.connection_pool.latency",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.connection_pool.latency")
+			metric.SetDescription("The ping latency of a backend server measured by ProxySQL.")
+			metric.SetUnit("us")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.connection_pool.queries",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.connection_pool.queries")
+			metric.SetDescription("The number of queries routed by ProxySQL to a backend server.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.query_rule.hits",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.query_rule.hits")
+			metric.SetDescription("The number of queries matched by a ProxySQL query rule.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.proxysql.server.status",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.proxysql.server.status")
+			metric.SetDescription("Whether a backend server of ProxySQL has the status, 1 for its current status and 0 for the others.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
 	&metricImpl{
 		"mysql.row_locks",
 		func(metric pdata.Metric) {
@@ -233,6 +407,17 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.schema.size",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.schema.size")
+			metric.SetDescription("The size of the data and indexes of the tables in a schema.")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(false)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
 	&metricImpl{
 		"mysql.sorts",
 		func(metric pdata.Metric) {
@@ -244,6 +429,17 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.table.full_scan.rows_read",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.table.full_scan.rows_read")
+			metric.SetDescription("The number of rows read from a table by full table scans.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
 	&metricImpl{
 		"mysql.threads",
 		func(metric pdata.Metric) {
@@ -255,6 +451,44 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"mysql.threads.count",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.threads.count")
+			metric.SetDescription("The number of threads handling client connections, by state.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"mysql.threads.created",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.threads.created")
+			metric.SetDescription("The number of threads created to handle client connections.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"mysql.transactions.long",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.transactions.long")
+			metric.SetDescription("The number of InnoDB transactions running for longer than the long transaction threshold.")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"mysql.transactions.max_age",
+		func(metric pdata.Metric) {
+			metric.SetName("mysql.transactions.max_age")
+			metric.SetDescription("The age of the oldest running InnoDB transaction.")
+			metric.SetUnit("s")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
 }
 
 // M contains a set of methods for each metric that help with
@@ -271,10 +505,14 @@ var Attributes = struct {
 	BufferPoolSize string
 	// Command (The command types.)
 	Command string
+	// ConnectionLimit (The connection count compared to the connection limit.)
+	ConnectionLimit string
 	// DoubleWrites (The doublewrite types.)
 	DoubleWrites string
 	// Handler (The handler types.)
 	Handler string
+	// Index (The name of the index.)
+	Index string
 	// Locks (The table locks type.)
 	Locks string
 	// LogOperations (The log operation types.)
@@ -283,12 +521,34 @@ var Attributes = struct {
 	Operations string
 	// PageOperations (The page operation types.)
 	PageOperations string
+	// ProxysqlConnectResult (The result of the connections established to a backend server.)
+	ProxysqlConnectResult string
+	// ProxysqlConnectionState (The state of the connections to a backend server.)
+	ProxysqlConnectionState string
+	// ProxysqlHostgroup (The ProxySQL hostgroup of the backend server.)
+	ProxysqlHostgroup string
+	// ProxysqlPoolGet (The result of the requests of a connection from the connection pool.)
+	ProxysqlPoolGet string
+	// ProxysqlRuleID (The ID of the query rule.)
+	ProxysqlRuleID string
+	// ProxysqlServer (The address of the backend server, as host:port.)
+	ProxysqlServer string
+	// ProxysqlServerStatus (The status of a backend server.)
+	ProxysqlServerStatus string
 	// RowLocks (The row lock type.)
 	RowLocks string
 	// RowOperations (The row operation type.)
 	RowOperations string
+	// Schema (The name of the schema.)
+	Schema string
+	// SchemaSize (The schema size types.)
+	SchemaSize string
 	// Sorts (The sort count type.)
 	Sorts string
+	// Table (The name of the table.)
+	Table string
+	// ThreadState (The state of the threads handling the client connections.)
+	ThreadState string
 	// Threads (The thread count type.)
 	Threads string
 }{
@@ -299,12 +559,25 @@ var Attributes = struct {
 	"kind",
 	"kind",
 	"kind",
+	"index",
+	"kind",
 	"operation",
 	"operation",
 	"operation",
+	"result",
+	"state",
+	"hostgroup",
+	"kind",
+	"rule_id",
+	"server",
+	"status",
 	"kind",
 	"operation",
+	"schema",
+	"kind",
 	"kind",
+	"table",
+	"state",
 	"kind",
 }
 
@@ -375,6 +648,15 @@ var AttributeCommand = struct {
 	"send_long_data",
 }
 
+// AttributeConnectionLimit are the possible values that the attribute "connection_limit" can have.
+var AttributeConnectionLimit = struct {
+	MaxUsed    string
+	MaxAllowed string
+}{
+	"max_used",
+	"max_allowed",
+}
+
 // AttributeDoubleWrites are the possible values that the attribute "double_writes" can have.
 var AttributeDoubleWrites = struct {
 	PagesWritten string
@@ -467,6 +749,48 @@ var AttributePageOperations = struct {
 	"written",
 }
 
+// AttributeProxysqlConnectResult are the possible values that the attribute "proxysql_connect_result" can have.
+var AttributeProxysqlConnectResult = struct {
+	Ok    string
+	Error string
+}{
+	"ok",
+	"error",
+}
+
+// AttributeProxysqlConnectionState are the possible values that the attribute "proxysql_connection_state" can have.
+var AttributeProxysqlConnectionState = struct {
+	Used string
+	Free string
+}{
+	"used",
+	"free",
+}
+
+// AttributeProxysqlPoolGet are the possible values that the attribute "proxysql_pool_get" can have.
+var AttributeProxysqlPoolGet = struct {
+	Immediate string
+	Success   string
+	Failure   string
+}{
+	"immediate",
+	"success",
+	"failure",
+}
+
+// AttributeProxysqlServerStatus are the possible values that the attribute "proxysql_server_status" can have.
+var AttributeProxysqlServerStatus = struct {
+	Online      string
+	Shunned     string
+	OfflineSoft string
+	OfflineHard string
+}{
+	"online",
+	"shunned",
+	"offline_soft",
+	"offline_hard",
+}
+
 // AttributeRowLocks are the possible values that the attribute "row_locks" can have.
 var AttributeRowLocks = struct {
 	Waits string
@@ -489,6 +813,15 @@ var AttributeRowOperations = struct {
 	"updated",
 }
 
+// AttributeSchemaSize are the possible values that the attribute "schema_size" can have.
+var AttributeSchemaSize = struct {
+	Data  string
+	Index string
+}{
+	"data",
+	"index",
+}
+
 // AttributeSorts are the possible values that the attribute "sorts" can have.
 var AttributeSorts = struct {
 	MergePasses string
@@ -502,6 +835,17 @@ var AttributeSorts = struct {
 	"scan",
 }
 
+// AttributeThreadState are the possible values that the attribute "thread_state" can have.
+var AttributeThreadState = struct {
+	Cached    string
+	Connected string
+	Running   string
+}{
+	"cached",
+	"connected",
+	"running",
+}
+
 // AttributeThreads are the possible values that the attribute "threads" can have.
 var AttributeThreads = struct {
 	Cached    string
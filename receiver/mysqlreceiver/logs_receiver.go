@@ -0,0 +1,179 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// errorLogReceiver reads the records of the error log of the server every collection interval,
+// and sends the records logged after it was started as logs.
+type errorLogReceiver struct {
+	logger   *zap.Logger
+	config   *Config
+	consumer consumer.Logs
+
+	sqlclient client
+	source    errorLogSource
+	// positioned is whether the records logged before the receiver was started were skipped.
+	positioned bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ component.LogsReceiver = (*errorLogReceiver)(nil)
+
+func newErrorLogReceiver(logger *zap.Logger, config *Config, consumer consumer.Logs) *errorLogReceiver {
+	return &errorLogReceiver{
+		logger:   logger,
+		config:   config,
+		consumer: consumer,
+	}
+}
+
+func (r *errorLogReceiver) Start(_ context.Context, _ component.Host) error {
+	if r.config.ErrorLog.Source == errorLogSourceFile {
+		r.source = &fileErrorLog{path: r.config.ErrorLog.Path}
+	} else {
+		sqlclient, err := newMySQLClient(r.config, config.LogsDataType)
+		if err != nil {
+			return err
+		}
+		if err = sqlclient.Connect(); err != nil {
+			return err
+		}
+		r.sqlclient = sqlclient
+		r.source = &tableErrorLog{sqlclient: sqlclient}
+	}
+	r.seekEnd()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	if interval := r.config.Connection.HealthCheckInterval; r.sqlclient != nil && interval > 0 {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			healthCheck(ctx, r.sqlclient, interval, func(err error) {
+				r.logger.Warn("MySQL connection health check failed", zap.Error(err))
+			})
+		}()
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.poll(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *errorLogReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	if r.sqlclient == nil {
+		return nil
+	}
+	return r.sqlclient.Close()
+}
+
+// seekEnd skips the records logged before the receiver was started, it is retried by the
+// next polls until it succeeds.
+func (r *errorLogReceiver) seekEnd() {
+	if err := r.source.seekEnd(); err != nil {
+		r.logger.Warn("Failed to skip the records of the error log logged before the receiver was started", zap.Error(err))
+		return
+	}
+	r.positioned = true
+}
+
+// poll reads the new records of the error log and sends them to the next consumer.
+func (r *errorLogReceiver) poll(ctx context.Context) {
+	if !r.positioned {
+		r.seekEnd()
+		return
+	}
+	records, err := r.source.read()
+	if err != nil {
+		r.logger.Error("Failed to read the error log", zap.Error(err))
+		return
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err = r.consumer.ConsumeLogs(ctx, errorLogToLogs(records)); err != nil {
+		r.logger.Error("Failed to send the records of the error log", zap.Error(err))
+	}
+}
+
+// errorLogToLogs converts the records of the error log to log records, whose severity is
+// mapped from their priority.
+func errorLogToLogs(records []errorLogRecord) pdata.Logs {
+	ld := pdata.NewLogs()
+	ill := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty()
+	ill.InstrumentationLibrary().SetName("otel/mysql")
+	logs := ill.Logs()
+	logs.EnsureCapacity(len(records))
+	for _, record := range records {
+		lr := logs.AppendEmpty()
+		lr.Body().SetStringVal(record.message)
+		if record.timestamp.IsZero() {
+			// The first line of the record was read before the receiver was started or rotated.
+			continue
+		}
+		lr.SetTimestamp(pdata.NewTimestampFromTime(record.timestamp))
+		lr.SetSeverityText(record.priority)
+		lr.SetSeverityNumber(severityNumber(record.priority))
+		lr.Attributes().InsertInt("mysql.thread_id", record.threadID)
+		if record.errorCode != "" {
+			lr.Attributes().InsertString("mysql.error_code", record.errorCode)
+		}
+		if record.subsystem != "" {
+			lr.Attributes().InsertString("mysql.subsystem", record.subsystem)
+		}
+	}
+	return ld
+}
+
+func severityNumber(priority string) pdata.SeverityNumber {
+	switch priority {
+	case "Error":
+		return pdata.SeverityNumberERROR
+	case "Warning":
+		return pdata.SeverityNumberWARN
+	case "System", "Note":
+		return pdata.SeverityNumberINFO
+	default:
+		return pdata.SeverityNumberUNDEFINED
+	}
+}
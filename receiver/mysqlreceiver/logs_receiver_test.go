@@ -0,0 +1,110 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestErrorLogToLogs(t *testing.T) {
+	timestamp := time.Date(2022, 1, 25, 10, 0, 0, 0, time.UTC)
+	ld := errorLogToLogs([]errorLogRecord{
+		{timestamp: timestamp, threadID: 8, priority: "Error", errorCode: "MY-012592", subsystem: "InnoDB", message: "error"},
+		{timestamp: timestamp, threadID: 12, priority: "Note", message: "note"},
+		{message: "continuation"},
+	})
+
+	ill := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0)
+	assert.Equal(t, "otel/mysql", ill.InstrumentationLibrary().Name())
+	logs := ill.Logs()
+	require.Equal(t, 3, logs.Len())
+
+	lr := logs.At(0)
+	assert.Equal(t, pdata.NewTimestampFromTime(timestamp), lr.Timestamp())
+	assert.Equal(t, "Error", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "error", lr.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{
+		"mysql.thread_id":  int64(8),
+		"mysql.error_code": "MY-012592",
+		"mysql.subsystem":  "InnoDB",
+	}, lr.Attributes().AsRaw())
+
+	lr = logs.At(1)
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+	assert.Equal(t, map[string]interface{}{"mysql.thread_id": int64(12)}, lr.Attributes().AsRaw())
+
+	lr = logs.At(2)
+	assert.Equal(t, pdata.Timestamp(0), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+	assert.Equal(t, "continuation", lr.Body().StringVal())
+	assert.Equal(t, 0, lr.Attributes().Len())
+}
+
+func TestErrorLogReceiverFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mysqld.log")
+	writeErrorLog(t, path, os.O_CREATE, "2022-01-25T10:00:00Z 0 [Note] before start\n")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	cfg.ErrorLog = ErrorLogConfig{Source: errorLogSourceFile, Path: path}
+	sink := new(consumertest.LogsSink)
+	receiver := newErrorLogReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, receiver.Shutdown(context.Background())) }()
+
+	writeErrorLog(t, path, os.O_APPEND, "2022-01-25T10:00:01Z 7 [Warning] [MY-013242] [Server] after start\n")
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "after start", lr.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+}
+
+func TestErrorLogReceiverRetriesSeekEnd(t *testing.T) {
+	sqlclient := &mockClient{
+		errorLog:    []errorLogRecord{{timestamp: time.UnixMicro(1643104800000000), priority: "Note", message: "before start"}},
+		errorLogErr: errors.New("connection refused"),
+	}
+	sink := new(consumertest.LogsSink)
+	receiver := newErrorLogReceiver(zap.NewNop(), createDefaultConfig().(*Config), sink)
+	receiver.source = &tableErrorLog{sqlclient: sqlclient}
+
+	receiver.seekEnd()
+	assert.False(t, receiver.positioned)
+	receiver.poll(context.Background())
+	assert.False(t, receiver.positioned)
+
+	// The records logged before the first successful read are skipped.
+	sqlclient.errorLogErr = nil
+	receiver.poll(context.Background())
+	assert.True(t, receiver.positioned)
+	assert.Equal(t, 0, sink.LogRecordCount())
+
+	sqlclient.errorLog = append(sqlclient.errorLog, errorLogRecord{timestamp: time.UnixMicro(1643104800000001), priority: "Error", message: "after start"})
+	receiver.poll(context.Background())
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
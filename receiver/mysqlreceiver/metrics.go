@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// The values of the statement tag of the query self-telemetry, a fixed set so that the
+// cardinality of the views is bounded whatever statements the scraper runs.
+const (
+	statementGlobalStats    = "global_stats"
+	statementInnodbStats    = "innodb_stats"
+	statementSchemaSizes    = "schema_sizes"
+	statementIndexUsage     = "index_usage"
+	statementTransactions   = "transactions"
+	statementMaxConnections = "max_connections"
+
+	statementProxySQLConnectionPool = "proxysql_connection_pool"
+	statementProxySQLQueryRules     = "proxysql_query_rules"
+	statementProxySQLGlobalStats    = "proxysql_global_stats"
+
+	// statementOther is the statement tag of the statements not in knownStatements.
+	statementOther = "other"
+)
+
+var knownStatements = map[string]struct{}{
+	statementGlobalStats:            {},
+	statementInnodbStats:            {},
+	statementSchemaSizes:            {},
+	statementIndexUsage:             {},
+	statementTransactions:           {},
+	statementMaxConnections:         {},
+	statementProxySQLConnectionPool: {},
+	statementProxySQLQueryRules:     {},
+	statementProxySQLGlobalStats:    {},
+}
+
+// statementTag returns the statement tag of statement, statementOther if it isn't a known statement.
+func statementTag(statement string) string {
+	if _, ok := knownStatements[statement]; ok {
+		return statement
+	}
+	return statementOther
+}
+
+var (
+	tagReceiverKey, _  = tag.NewKey("receiver")
+	tagStatementKey, _ = tag.NewKey("statement")
+	tagStateKey, _     = tag.NewKey("state")
+	tagReasonKey, _    = tag.NewKey("reason")
+
+	statConnectionErrors = stats.Int64("mysql_receiver_connection_errors", "Number of failed connections to the MySQL server", stats.UnitDimensionless)
+	statQueryErrors      = stats.Int64("mysql_receiver_query_errors", "Number of failed queries", stats.UnitDimensionless)
+	statQueryDuration    = stats.Float64("mysql_receiver_query_duration", "Duration of the queries run against the MySQL server", stats.UnitMilliseconds)
+	statRowsParsed       = stats.Int64("mysql_receiver_rows_parsed", "Number of rows parsed from query results", stats.UnitDimensionless)
+
+	// The stats of the connection pool of the receiver, reported after every scrape.
+	statPoolConnections       = stats.Int64("mysql_receiver_pool_connections", "Number of connections opened by the receiver to the MySQL server", stats.UnitDimensionless)
+	statPoolWaits             = stats.Int64("mysql_receiver_pool_waits", "Number of times a query waited for a connection of the pool", stats.UnitDimensionless)
+	statPoolWaitDuration      = stats.Float64("mysql_receiver_pool_wait_duration", "Time spent by the queries waiting for a connection of the pool", stats.UnitMilliseconds)
+	statPoolClosedConnections = stats.Int64("mysql_receiver_pool_closed_connections", "Number of connections closed by the pool", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metric views for the MySQL receiver.
+func MetricViews() []*view.View {
+	statementTagKeys := []tag.Key{tagReceiverKey, tagStatementKey}
+
+	countConnectionErrors := &view.View{
+		Name:        statConnectionErrors.Name(),
+		Measure:     statConnectionErrors,
+		Description: statConnectionErrors.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey},
+		Aggregation: view.Sum(),
+	}
+
+	countQueryErrors := &view.View{
+		Name:        statQueryErrors.Name(),
+		Measure:     statQueryErrors,
+		Description: statQueryErrors.Description(),
+		TagKeys:     statementTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	distributionQueryDuration := &view.View{
+		Name:        statQueryDuration.Name(),
+		Measure:     statQueryDuration,
+		Description: statQueryDuration.Description(),
+		TagKeys:     statementTagKeys,
+		Aggregation: view.Distribution(1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+	}
+
+	countRowsParsed := &view.View{
+		Name:        statRowsParsed.Name(),
+		Measure:     statRowsParsed,
+		Description: statRowsParsed.Description(),
+		TagKeys:     statementTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	lastPoolConnections := &view.View{
+		Name:        statPoolConnections.Name(),
+		Measure:     statPoolConnections,
+		Description: statPoolConnections.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey, tagStateKey},
+		Aggregation: view.LastValue(),
+	}
+
+	countPoolWaits := &view.View{
+		Name:        statPoolWaits.Name(),
+		Measure:     statPoolWaits,
+		Description: statPoolWaits.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey},
+		Aggregation: view.Sum(),
+	}
+
+	sumPoolWaitDuration := &view.View{
+		Name:        statPoolWaitDuration.Name(),
+		Measure:     statPoolWaitDuration,
+		Description: statPoolWaitDuration.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey},
+		Aggregation: view.Sum(),
+	}
+
+	countPoolClosedConnections := &view.View{
+		Name:        statPoolClosedConnections.Name(),
+		Measure:     statPoolClosedConnections,
+		Description: statPoolClosedConnections.Description(),
+		TagKeys:     []tag.Key{tagReceiverKey, tagReasonKey},
+		Aggregation: view.Sum(),
+	}
+
+	return []*view.View{
+		countConnectionErrors,
+		countQueryErrors,
+		distributionQueryDuration,
+		countRowsParsed,
+		lastPoolConnections,
+		countPoolWaits,
+		sumPoolWaitDuration,
+		countPoolClosedConnections,
+	}
+}
+
+// MySQL server errors returned when a connection can't be established or is refused,
+// e.g. when the monitoring user exceeds its connection limits.
+var connectionErrorNumbers = map[uint16]struct{}{
+	1040: {}, // ER_CON_COUNT_ERROR
+	1045: {}, // ER_ACCESS_DENIED_ERROR
+	1129: {}, // ER_HOST_IS_BLOCKED
+	1130: {}, // ER_HOST_NOT_PRIVILEGED
+	1203: {}, // ER_TOO_MANY_USER_CONNECTIONS
+	1226: {}, // ER_USER_LIMIT_REACHED
+}
+
+// isConnectionError returns true if err was caused by a failure to connect to the MySQL server.
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || isAuthPluginError(err) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		_, ok := connectionErrorNumbers[mysqlErr.Number]
+		return ok
+	}
+	return false
+}
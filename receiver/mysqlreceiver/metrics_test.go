@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "bad connection", err: driver.ErrBadConn, expected: true},
+		{name: "invalid connection", err: fmt.Errorf("query failed: %w", mysql.ErrInvalidConn), expected: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, expected: true},
+		{name: "user limit reached", err: &mysql.MySQLError{Number: 1226, Message: "User 'otel' has exceeded the 'max_questions' resource"}, expected: true},
+		{name: "too many connections", err: &mysql.MySQLError{Number: 1040, Message: "Too many connections"}, expected: true},
+		{name: "auth plugin not enabled", err: explainAuthError(mysql.ErrNativePassword), expected: true},
+		{name: "syntax error", err: &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}, expected: false},
+		{name: "other error", err: errors.New("other"), expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isConnectionError(tt.err))
+		})
+	}
+}
+
+func TestStatementTag(t *testing.T) {
+	assert.Equal(t, statementGlobalStats, statementTag(statementGlobalStats))
+	assert.Equal(t, statementProxySQLQueryRules, statementTag(statementProxySQLQueryRules))
+	assert.Equal(t, statementOther, statementTag("SELECT * FROM information_schema.tables WHERE table_schema = 'app'"))
+}
+
+type failingClient struct {
+	mockClient
+	innodbErr error
+}
+
+func (c *failingClient) getInnodbStats() (map[string]string, error) {
+	return nil, c.innodbErr
+}
+
+func TestScrapeSelfTelemetry(t *testing.T) {
+	view.Unregister(MetricViews()...)
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.SetIDName("telemetry")
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &failingClient{innodbErr: &mysql.MySQLError{Number: 1226, Message: "User 'otel' has exceeded the 'max_questions' resource"}}
+
+	md, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Greater(t, md.MetricCount(), 0)
+
+	globalStats, err := readFile("global_stats")
+	require.NoError(t, err)
+
+	expectedTags := map[string]string{"receiver": config.NewComponentIDWithName(typeStr, "telemetry").String()}
+	rows, err := view.RetrieveData(statConnectionErrors.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, expectedTags, tagsToMap(rows[0]))
+	assert.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+
+	rows, err = view.RetrieveData(statQueryErrors.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, statementInnodbStats, tagsToMap(rows[0])["statement"])
+
+	rows, err = view.RetrieveData(statRowsParsed.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, statementGlobalStats, tagsToMap(rows[0])["statement"])
+	assert.Equal(t, float64(len(globalStats)), rows[0].Data.(*view.SumData).Value)
+
+	rows, err = view.RetrieveData(statQueryDuration.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		assert.Equal(t, int64(1), row.Data.(*view.DistributionData).Count)
+	}
+}
+
+func TestScrapePoolStats(t *testing.T) {
+	view.Unregister(MetricViews()...)
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.SetIDName("pool")
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	client := &mockClient{pool: sql.DBStats{
+		OpenConnections:   2,
+		InUse:             1,
+		Idle:              1,
+		WaitCount:         3,
+		WaitDuration:      30 * time.Millisecond,
+		MaxLifetimeClosed: 1,
+	}}
+	scraper.sqlclient = client
+	_, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	// The totals of the pool are reported as the increases since the previous scrape.
+	client.pool.InUse = 2
+	client.pool.Idle = 0
+	client.pool.WaitCount = 5
+	client.pool.WaitDuration = 50 * time.Millisecond
+	client.pool.MaxIdleClosed = 4
+	_, err = scraper.scrape(context.Background())
+	require.NoError(t, err)
+
+	rows, err := view.RetrieveData(statPoolConnections.Name())
+	require.NoError(t, err)
+	connections := map[string]float64{}
+	for _, row := range rows {
+		connections[tagsToMap(row)["state"]] = row.Data.(*view.LastValueData).Value
+	}
+	assert.Equal(t, map[string]float64{"in_use": 2, "idle": 0}, connections)
+
+	rows, err = view.RetrieveData(statPoolWaits.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, map[string]string{"receiver": config.NewComponentIDWithName(typeStr, "pool").String()}, tagsToMap(rows[0]))
+	assert.Equal(t, float64(5), rows[0].Data.(*view.SumData).Value)
+
+	rows, err = view.RetrieveData(statPoolWaitDuration.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.InDelta(t, float64(50), rows[0].Data.(*view.SumData).Value, 0.001)
+
+	rows, err = view.RetrieveData(statPoolClosedConnections.Name())
+	require.NoError(t, err)
+	closed := map[string]float64{}
+	for _, row := range rows {
+		closed[tagsToMap(row)["reason"]] = row.Data.(*view.SumData).Value
+	}
+	assert.Equal(t, map[string]float64{"max_idle": 4, "max_idle_time": 0, "max_lifetime": 1}, closed)
+}
+
+func TestPoolStatDelta(t *testing.T) {
+	assert.Equal(t, int64(2), poolStatDelta(5, 3))
+	assert.Equal(t, int64(0), poolStatDelta(3, 3))
+	// The totals start over once the client reconnected.
+	assert.Equal(t, int64(1), poolStatDelta(1, 3))
+}
+
+func tagsToMap(row *view.Row) map[string]string {
+	tags := make(map[string]string, len(row.Tags))
+	for _, tag := range row.Tags {
+		tags[tag.Key.Name()] = tag.Value
+	}
+	return tags
+}
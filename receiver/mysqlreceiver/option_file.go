@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxOptionFileIncludeDepth bounds the nesting of the !include and !includedir directives,
+// which would otherwise loop on a file including itself.
+const maxOptionFileIncludeDepth = 10
+
+// defaultOptionFileGroups are the option groups read when none is configured, [client] being
+// the group read by all the MySQL client programs.
+var defaultOptionFileGroups = []string{"client"}
+
+// readOptionFile returns the options of the given groups of a MySQL option file, e.g. ~/.my.cnf.
+// The options of a group override the options of the previous groups. The names of the options
+// are normalized to dashes, e.g. ssl_ca is ssl-ca.
+func readOptionFile(path string, groups []string) (map[string]string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	p := &optionFileParser{
+		groups: map[string]int{},
+		values: make([]map[string]string, len(groups)),
+	}
+	for i, group := range groups {
+		p.groups[group] = i
+		p.values[i] = map[string]string{}
+	}
+	if err := p.parseFile(path, 0); err != nil {
+		return nil, err
+	}
+	options := map[string]string{}
+	for _, values := range p.values {
+		for name, value := range values {
+			options[name] = value
+		}
+	}
+	return options, nil
+}
+
+// optionFileParser collects the options of the selected groups of option files.
+type optionFileParser struct {
+	// groups maps the names of the selected groups to their index in values.
+	groups map[string]int
+	values []map[string]string
+}
+
+func (p *optionFileParser) parseFile(path string, depth int) error {
+	if depth > maxOptionFileIncludeDepth {
+		return fmt.Errorf("option file %q exceeds the maximum depth of %d includes", path, maxOptionFileIncludeDepth)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	// Like the MySQL client programs, world-writable files are not trusted with credentials.
+	if info.Mode().Perm()&0002 != 0 {
+		return fmt.Errorf("option file %q is world-writable", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// group is the index of the current group in values, -1 if the group isn't selected.
+	group, inGroup := -1, false
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || line[0] == '#' || line[0] == ';':
+		case strings.HasPrefix(line, "!includedir "):
+			dir := resolveIncluded(path, strings.TrimSpace(strings.TrimPrefix(line, "!includedir ")))
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || (filepath.Ext(entry.Name()) != ".cnf" && filepath.Ext(entry.Name()) != ".ini") {
+					continue
+				}
+				if err := p.parseFile(filepath.Join(dir, entry.Name()), depth+1); err != nil {
+					return err
+				}
+			}
+		case strings.HasPrefix(line, "!include "):
+			included := resolveIncluded(path, strings.TrimSpace(strings.TrimPrefix(line, "!include ")))
+			if err := p.parseFile(included, depth+1); err != nil {
+				return err
+			}
+		case line[0] == '[':
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return fmt.Errorf("%s:%d: invalid group %q", path, n+1, line)
+			}
+			var ok bool
+			group, ok = p.groups[strings.TrimSpace(line[1:end])]
+			if !ok {
+				group = -1
+			}
+			inGroup = true
+		case !inGroup:
+			return fmt.Errorf("%s:%d: option %q is not in a group", path, n+1, line)
+		case group >= 0:
+			name, value := parseOption(line)
+			p.values[group][name] = value
+		}
+	}
+	return nil
+}
+
+// resolveIncluded returns the path of an included file or directory, relative to the including file.
+func resolveIncluded(including, included string) string {
+	if filepath.IsAbs(included) {
+		return included
+	}
+	return filepath.Join(filepath.Dir(including), included)
+}
+
+// parseOption parses an option line, either name or name=value. The value is either quoted,
+// or ends with a # comment.
+func parseOption(line string) (string, string) {
+	name, value := line, ""
+	if i := strings.IndexByte(line, '='); i >= 0 {
+		name, value = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	name = strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"), "loose-")
+
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return name, unescapeOptionValue(value[1 : end+1])
+		}
+	}
+	if i := strings.IndexByte(value, '#'); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return name, unescapeOptionValue(value)
+}
+
+// unescapeOptionValue replaces the escape sequences of the option values. A backslash
+// followed by another character is kept, e.g. in Windows paths.
+func unescapeOptionValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 's':
+			b.WriteByte(' ')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			continue
+		}
+		i++
+	}
+	return b.String()
+}
+
+// withOptionFile returns a copy of the config with the connection settings read from its option
+// file, which take precedence over the settings of the config. The config is returned as is
+// without an option file.
+func (cfg *Config) withOptionFile() (*Config, error) {
+	if cfg.OptionFile.Path == "" {
+		return cfg, nil
+	}
+	groups := cfg.OptionFile.Groups
+	if len(groups) == 0 {
+		groups = defaultOptionFileGroups
+	}
+	options, err := readOptionFile(cfg.OptionFile.Path, groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read option file: %w", err)
+	}
+
+	c := *cfg
+	if user, ok := options["user"]; ok {
+		c.Username = user
+	}
+	if password, ok := options["password"]; ok {
+		c.Password = password
+	}
+	if database, ok := options["database"]; ok {
+		c.Database = database
+	}
+
+	// Like the MySQL client programs, the socket is used unless connecting to another host than localhost.
+	host, hasHost := options["host"]
+	port, hasPort := options["port"]
+	if socket, ok := options["socket"]; ok && (!hasHost || host == "localhost") {
+		c.Transport = "unix"
+		c.Endpoint = socket
+	} else if hasHost || hasPort {
+		currentHost, currentPort := "localhost", "3306"
+		if c.Transport == "tcp" {
+			if h, p, err := net.SplitHostPort(c.Endpoint); err == nil {
+				currentHost, currentPort = h, p
+			}
+		}
+		if !hasHost {
+			host = currentHost
+		}
+		if !hasPort {
+			port = currentPort
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid port %q in option file", port)
+		}
+		c.Transport = "tcp"
+		c.Endpoint = net.JoinHostPort(host, port)
+	}
+
+	caFile, hasCA := options["ssl-ca"]
+	certFile, hasCert := options["ssl-cert"]
+	keyFile, hasKey := options["ssl-key"]
+	if hasCA || hasCert || hasKey {
+		c.TLS.Insecure = false
+		if hasCA {
+			c.TLS.CAFile = caFile
+		}
+		if hasCert {
+			c.TLS.CertFile = certFile
+		}
+		if hasKey {
+			c.TLS.KeyFile = keyFile
+		}
+	}
+	return &c, nil
+}
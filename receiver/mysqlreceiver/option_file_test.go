@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestReadOptionFile(t *testing.T) {
+	path := filepath.Join("testdata", "option_file", "my.cnf")
+	tests := []struct {
+		name     string
+		groups   []string
+		expected map[string]string
+	}{
+		{
+			name:   "client",
+			groups: []string{"client"},
+			expected: map[string]string{
+				"user":     "otel",
+				"password": "pass#word",
+				"host":     "db.example.com",
+				"port":     "3307",
+				"ssl-ca":   "/etc/mysql/ca.pem",
+				"ssl-cert": "/etc/mysql/client-cert.pem",
+				"ssl-key":  "/etc/mysql/client-key.pem",
+			},
+		},
+		{
+			name:   "client and otelcol",
+			groups: []string{"client", "otelcol"},
+			expected: map[string]string{
+				"user":     "otel",
+				"password": `C:\path \x`,
+				"host":     "db.example.com",
+				"port":     "3307",
+				"database": "metrics",
+				"ssl-ca":   "/etc/mysql/ca.pem",
+				"ssl-cert": "/etc/mysql/client-cert.pem",
+				"ssl-key":  "/etc/mysql/client-key.pem",
+			},
+		},
+		{
+			name:     "unknown group",
+			groups:   []string{"mysqladmin"},
+			expected: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := readOptionFile(path, tt.groups)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, options)
+		})
+	}
+}
+
+func TestReadOptionFileErrors(t *testing.T) {
+	_, err := readOptionFile(filepath.Join("testdata", "option_file", "no_group.cnf"), defaultOptionFileGroups)
+	assert.EqualError(t, err, filepath.Join("testdata", "option_file", "no_group.cnf")+`:1: option "user = otel" is not in a group`)
+
+	_, err = readOptionFile(filepath.Join("testdata", "option_file", "missing.cnf"), defaultOptionFileGroups)
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	loop := filepath.Join(dir, "loop.cnf")
+	require.NoError(t, os.WriteFile(loop, []byte("!include loop.cnf\n"), 0600))
+	_, err = readOptionFile(loop, defaultOptionFileGroups)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum depth of 10 includes")
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	writable := filepath.Join(dir, "writable.cnf")
+	require.NoError(t, os.WriteFile(writable, []byte("[client]\nuser = otel\n"), 0600))
+	require.NoError(t, os.Chmod(writable, 0666))
+	_, err = readOptionFile(writable, defaultOptionFileGroups)
+	assert.EqualError(t, err, `option file "`+writable+`" is world-writable`)
+}
+
+func TestConfigWithOptionFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	same, err := cfg.withOptionFile()
+	require.NoError(t, err)
+	assert.Same(t, cfg, same)
+
+	cfg.Password = "ignored"
+	cfg.OptionFile = OptionFileConfig{
+		Path:   filepath.Join("testdata", "option_file", "my.cnf"),
+		Groups: []string{"client", "otelcol"},
+	}
+	c, err := cfg.withOptionFile()
+	require.NoError(t, err)
+	assert.Equal(t, "otel", c.Username)
+	assert.Equal(t, `C:\path \x`, c.Password)
+	assert.Equal(t, "metrics", c.Database)
+	assert.Equal(t, "tcp", c.Transport)
+	assert.Equal(t, "db.example.com:3307", c.Endpoint)
+	assert.False(t, c.TLS.Insecure)
+	assert.Equal(t, "/etc/mysql/ca.pem", c.TLS.CAFile)
+	assert.Equal(t, "/etc/mysql/client-cert.pem", c.TLS.CertFile)
+	assert.Equal(t, "/etc/mysql/client-key.pem", c.TLS.KeyFile)
+	// The config itself is left as is.
+	assert.Equal(t, "ignored", cfg.Password)
+	assert.True(t, cfg.TLS.Insecure)
+
+	cfg.OptionFile = OptionFileConfig{Path: filepath.Join("testdata", "option_file", "socket.cnf")}
+	c, err = cfg.withOptionFile()
+	require.NoError(t, err)
+	assert.Equal(t, "otel", c.Username)
+	assert.Equal(t, "ignored", c.Password)
+	assert.Equal(t, "unix", c.Transport)
+	assert.Equal(t, "/var/run/mysqld/mysqld.sock", c.Endpoint)
+	assert.True(t, c.TLS.Insecure)
+
+	port := filepath.Join(t.TempDir(), "port.cnf")
+	require.NoError(t, os.WriteFile(port, []byte("[client]\nport = 3307\n"), 0600))
+	cfg.OptionFile = OptionFileConfig{Path: port}
+	c, err = cfg.withOptionFile()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:3307", c.Endpoint)
+
+	require.NoError(t, os.WriteFile(port, []byte("[client]\nport = mysql\n"), 0600))
+	_, err = cfg.withOptionFile()
+	assert.EqualError(t, err, `invalid port "mysql" in option file`)
+}
+
+func TestNewMySQLClientOptionFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.OptionFile = OptionFileConfig{Path: filepath.Join("testdata", "option_file", "socket.cnf")}
+	client, err := newMySQLClient(cfg, config.MetricsDataType)
+	require.NoError(t, err)
+	assert.Contains(t, client.(*mySQLClient).connStr, "otel@unix(/var/run/mysqld/mysqld.sock)/")
+
+	cfg.OptionFile = OptionFileConfig{Path: filepath.Join("testdata", "option_file", "missing.cnf")}
+	_, err = newMySQLClient(cfg, config.MetricsDataType)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read option file")
+}
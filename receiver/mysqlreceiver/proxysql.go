@@ -0,0 +1,152 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver/internal/metadata"
+)
+
+// proxySQLServerStatuses maps the statuses of the backend servers of ProxySQL to the values of
+// the proxysql_server_status attribute. SHUNNED_REPLICATION_LAG is reported by the connection
+// pool for the servers shunned because of their replication lag.
+var proxySQLServerStatuses = map[string]string{
+	"ONLINE":                  metadata.AttributeProxysqlServerStatus.Online,
+	"SHUNNED":                 metadata.AttributeProxysqlServerStatus.Shunned,
+	"SHUNNED_REPLICATION_LAG": metadata.AttributeProxysqlServerStatus.Shunned,
+	"OFFLINE_SOFT":            metadata.AttributeProxysqlServerStatus.OfflineSoft,
+	"OFFLINE_HARD":            metadata.AttributeProxysqlServerStatus.OfflineHard,
+}
+
+// proxySQLPoolGets maps the variables of the global status of ProxySQL to the values of the
+// proxysql_pool_get attribute.
+var proxySQLPoolGets = map[string]string{
+	"ConnPool_get_conn_immediate": metadata.AttributeProxysqlPoolGet.Immediate,
+	"ConnPool_get_conn_success":   metadata.AttributeProxysqlPoolGet.Success,
+	"ConnPool_get_conn_failure":   metadata.AttributeProxysqlPoolGet.Failure,
+}
+
+// scrapeProxySQL adds the metrics of the stats schema of the admin interface of ProxySQL to
+// the metric slice. The connection pool is required, the query rules and the global
+// status only fail the scrape partially.
+func (m *mySQLScraper) scrapeProxySQL(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp) error {
+	start := time.Now()
+	backends, err := m.sqlclient.getProxySQLConnectionPool()
+	m.recordQuery(ctx, statementProxySQLConnectionPool, start, len(backends), err)
+	if err != nil {
+		m.logger.Error("Failed to fetch ProxySQL connection pool", zap.Error(err))
+		return err
+	}
+
+	connections := initMetric(ms, metadata.M.MysqlProxysqlConnectionPoolConnections).Sum().DataPoints()
+	connects := initMetric(ms, metadata.M.MysqlProxysqlConnectionPoolConnects).Sum().DataPoints()
+	queries := initMetric(ms, metadata.M.MysqlProxysqlConnectionPoolQueries).Sum().DataPoints()
+	latency := initMetric(ms, metadata.M.MysqlProxysqlConnectionPoolLatency).Gauge().DataPoints()
+	statuses := initMetric(ms, metadata.M.MysqlProxysqlServerStatus).Gauge().DataPoints()
+	for _, b := range backends {
+		labels := pdata.NewAttributeMap()
+		labels.Insert(metadata.A.ProxysqlHostgroup, pdata.NewAttributeValueString(b.hostgroup))
+		labels.Insert(metadata.A.ProxysqlServer, pdata.NewAttributeValueString(net.JoinHostPort(b.host, b.port)))
+		addToIntMetric(queries, labels, b.queries, now)
+		addToIntMetric(latency, labels, b.latencyUs, now)
+
+		status, ok := proxySQLServerStatuses[strings.ToUpper(b.status)]
+		if !ok {
+			m.logInvalid("status", "status", b.status)
+		} else {
+			for _, s := range []string{
+				metadata.AttributeProxysqlServerStatus.Online,
+				metadata.AttributeProxysqlServerStatus.Shunned,
+				metadata.AttributeProxysqlServerStatus.OfflineSoft,
+				metadata.AttributeProxysqlServerStatus.OfflineHard,
+			} {
+				labels.Upsert(metadata.A.ProxysqlServerStatus, pdata.NewAttributeValueString(s))
+				var value int64
+				if s == status {
+					value = 1
+				}
+				addToIntMetric(statuses, labels, value, now)
+			}
+			labels.Delete(metadata.A.ProxysqlServerStatus)
+		}
+
+		labels.Insert(metadata.A.ProxysqlConnectionState, pdata.NewAttributeValueString(metadata.AttributeProxysqlConnectionState.Used))
+		addToIntMetric(connections, labels, b.connUsed, now)
+		labels.Update(metadata.A.ProxysqlConnectionState, pdata.NewAttributeValueString(metadata.AttributeProxysqlConnectionState.Free))
+		addToIntMetric(connections, labels, b.connFree, now)
+		labels.Delete(metadata.A.ProxysqlConnectionState)
+
+		labels.Insert(metadata.A.ProxysqlConnectResult, pdata.NewAttributeValueString(metadata.AttributeProxysqlConnectResult.Ok))
+		addToIntMetric(connects, labels, b.connOK, now)
+		labels.Update(metadata.A.ProxysqlConnectResult, pdata.NewAttributeValueString(metadata.AttributeProxysqlConnectResult.Error))
+		addToIntMetric(connects, labels, b.connErr, now)
+	}
+
+	errs := &scrapererror.ScrapeErrors{}
+	m.scrapeProxySQLQueryRules(ctx, ms, now, errs)
+	m.scrapeProxySQLGlobalStats(ctx, ms, now, errs)
+	return errs.Combine()
+}
+
+// scrapeProxySQLQueryRules adds the hits of every query rule to the metric slice.
+func (m *mySQLScraper) scrapeProxySQLQueryRules(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	start := time.Now()
+	rules, err := m.sqlclient.getProxySQLQueryRules()
+	m.recordQuery(ctx, statementProxySQLQueryRules, start, len(rules), err)
+	if err != nil {
+		m.logger.Error("Failed to fetch ProxySQL query rules", zap.Error(err))
+		errs.AddPartial(1, err)
+		return
+	}
+
+	hits := initMetric(ms, metadata.M.MysqlProxysqlQueryRuleHits).Sum().DataPoints()
+	for _, rule := range rules {
+		labels := pdata.NewAttributeMap()
+		labels.Insert(metadata.A.ProxysqlRuleID, pdata.NewAttributeValueString(rule.ruleID))
+		addToIntMetric(hits, labels, rule.hits, now)
+	}
+}
+
+// scrapeProxySQLGlobalStats adds the requests of connections from the connection pool to the
+// metric slice, so that the efficiency of the pool can be computed from the immediate ones.
+func (m *mySQLScraper) scrapeProxySQLGlobalStats(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	globalStats, err := m.query(ctx, statementProxySQLGlobalStats, m.sqlclient.getProxySQLGlobalStats)
+	if err != nil {
+		m.logger.Error("Failed to fetch ProxySQL global stats", zap.Error(err))
+		errs.AddPartial(1, err)
+		return
+	}
+
+	gets := initMetric(ms, metadata.M.MysqlProxysqlConnectionPoolGets).Sum().DataPoints()
+	for k, v := range globalStats {
+		kind, ok := proxySQLPoolGets[k]
+		if !ok {
+			continue
+		}
+		if i, ok := m.parseInt(k, v); ok {
+			labels := pdata.NewAttributeMap()
+			labels.Insert(metadata.A.ProxysqlPoolGet, pdata.NewAttributeValueString(kind))
+			addToIntMetric(gets, labels, i, now)
+		}
+	}
+}
@@ -16,12 +16,18 @@ package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"strconv"
+	"sync"
 	"time"
 
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver/internal/metadata"
@@ -31,33 +37,63 @@ type mySQLScraper struct {
 	sqlclient client
 	logger    *zap.Logger
 	config    *Config
+	deltas    *deltaCalculator
+	// poolStats are the stats of the connection pool of sqlclient at the previous scrape.
+	poolStats sql.DBStats
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 func newMySQLScraper(
 	logger *zap.Logger,
 	config *Config,
 ) *mySQLScraper {
-	return &mySQLScraper{
+	m := &mySQLScraper{
 		logger: logger,
 		config: config,
 	}
+	if config.AggregationTemporality == temporalityDelta {
+		m.deltas = newDeltaCalculator()
+	}
+	return m
 }
 
 // start starts the scraper by initializing the db client connection.
-func (m *mySQLScraper) start(_ context.Context, host component.Host) error {
-	sqlclient := newMySQLClient(m.config)
+func (m *mySQLScraper) start(ctx context.Context, host component.Host) error {
+	sqlclient, err := newMySQLClient(m.config, config.MetricsDataType)
+	if err != nil {
+		return err
+	}
 
-	err := sqlclient.Connect()
+	err = sqlclient.Connect()
 	if err != nil {
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagReceiverKey, m.config.ID().String())}, statConnectionErrors.M(1))
 		return err
 	}
 	m.sqlclient = sqlclient
 
+	if interval := m.config.Connection.HealthCheckInterval; interval > 0 {
+		healthCtx, cancel := context.WithCancel(context.Background())
+		m.cancel = cancel
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			healthCheck(healthCtx, sqlclient, interval, func(err error) {
+				m.logger.Warn("MySQL connection health check failed", zap.Error(err))
+				_ = stats.RecordWithTags(healthCtx, []tag.Mutator{tag.Upsert(tagReceiverKey, m.config.ID().String())}, statConnectionErrors.M(1))
+			})
+		}()
+	}
 	return nil
 }
 
-// shutdown closes the db connection
+// shutdown stops the health check and closes the db connection
 func (m *mySQLScraper) shutdown(context.Context) error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
 	if m.sqlclient == nil {
 		return nil
 	}
@@ -91,11 +127,104 @@ func addToIntMetric(metric pdata.NumberDataPointSlice, labels pdata.AttributeMap
 	}
 }
 
+// query runs one of the statements of a scrape and records its duration, the number
+// of rows it returned, and whether it failed because of a connection error.
+func (m *mySQLScraper) query(ctx context.Context, statement string, fn func() (map[string]string, error)) (map[string]string, error) {
+	start := time.Now()
+	values, err := fn()
+	m.recordQuery(ctx, statement, start, len(values), err)
+	return values, err
+}
+
+// querySchemaSizes runs the schema sizes statement and records it like query does.
+func (m *mySQLScraper) querySchemaSizes(ctx context.Context) ([]schemaSize, error) {
+	start := time.Now()
+	sizes, err := m.sqlclient.getSchemaSizes()
+	m.recordQuery(ctx, statementSchemaSizes, start, len(sizes), err)
+	return sizes, err
+}
+
+// queryIndexUsage runs the index usage statement and records it like query does.
+func (m *mySQLScraper) queryIndexUsage(ctx context.Context) ([]indexUsage, error) {
+	start := time.Now()
+	usages, err := m.sqlclient.getIndexUsage()
+	m.recordQuery(ctx, statementIndexUsage, start, len(usages), err)
+	return usages, err
+}
+
+// queryTransactionStats runs the transactions statement and records it like query does.
+func (m *mySQLScraper) queryTransactionStats(ctx context.Context) (transactionStats, error) {
+	start := time.Now()
+	stats, err := m.sqlclient.getTransactionStats(m.config.LongTransactionThreshold)
+	m.recordQuery(ctx, statementTransactions, start, 1, err)
+	return stats, err
+}
+
+// queryMaxConnections runs the max connections statement and records it like query does.
+func (m *mySQLScraper) queryMaxConnections(ctx context.Context) (int64, error) {
+	start := time.Now()
+	maxConnections, err := m.sqlclient.getMaxConnections()
+	m.recordQuery(ctx, statementMaxConnections, start, 1, err)
+	return maxConnections, err
+}
+
+// recordQuery records the self-telemetry of a statement started at start.
+func (m *mySQLScraper) recordQuery(ctx context.Context, statement string, start time.Time, rows int, err error) {
+	measurements := []stats.Measurement{statQueryDuration.M(float64(time.Since(start)) / float64(time.Millisecond))}
+	if err != nil {
+		measurements = append(measurements, statQueryErrors.M(1))
+		if isConnectionError(err) {
+			measurements = append(measurements, statConnectionErrors.M(1))
+		}
+	} else {
+		measurements = append(measurements, statRowsParsed.M(int64(rows)))
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, m.config.ID().String()), tag.Upsert(tagStatementKey, statementTag(statement))},
+		measurements...,
+	)
+}
+
+// recordPoolStats records the self-telemetry of the connection pool of the client, the connections it holds and
+// the waits and closed connections since the previous scrape, so that connections leaked by the scraper are detected.
+func (m *mySQLScraper) recordPoolStats(ctx context.Context) {
+	current := m.sqlclient.poolStats()
+	previous := m.poolStats
+	m.poolStats = current
+
+	receiver := tag.Upsert(tagReceiverKey, m.config.ID().String())
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{receiver, tag.Upsert(tagStateKey, "in_use")}, statPoolConnections.M(int64(current.InUse)))
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{receiver, tag.Upsert(tagStateKey, "idle")}, statPoolConnections.M(int64(current.Idle)))
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{receiver},
+		statPoolWaits.M(poolStatDelta(current.WaitCount, previous.WaitCount)),
+		statPoolWaitDuration.M(float64(poolStatDelta(int64(current.WaitDuration), int64(previous.WaitDuration)))/float64(time.Millisecond)),
+	)
+	closed := map[string]int64{
+		"max_idle":      poolStatDelta(current.MaxIdleClosed, previous.MaxIdleClosed),
+		"max_idle_time": poolStatDelta(current.MaxIdleTimeClosed, previous.MaxIdleTimeClosed),
+		"max_lifetime":  poolStatDelta(current.MaxLifetimeClosed, previous.MaxLifetimeClosed),
+	}
+	for reason, count := range closed {
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{receiver, tag.Upsert(tagReasonKey, reason)}, statPoolClosedConnections.M(count))
+	}
+}
+
+// poolStatDelta returns the increase of a total of the pool stats since the previous scrape, the total itself if it
+// was reset since.
+func poolStatDelta(current, previous int64) int64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
+
 // scrape scrapes the mysql db metric stats, transforms them and labels them into a metric slices.
-func (m *mySQLScraper) scrape(context.Context) (pdata.Metrics, error) {
+func (m *mySQLScraper) scrape(ctx context.Context) (pdata.Metrics, error) {
 	if m.sqlclient == nil {
 		return pdata.Metrics{}, errors.New("failed to connect to http client")
 	}
+	defer m.recordPoolStats(ctx)
 
 	// metric initialization
 	md := pdata.NewMetrics()
@@ -103,6 +232,17 @@ func (m *mySQLScraper) scrape(context.Context) (pdata.Metrics, error) {
 	ilm.InstrumentationLibrary().SetName("otel/mysql")
 	now := pdata.NewTimestampFromTime(time.Now())
 
+	if m.config.Mode == modeProxySQL {
+		err := m.scrapeProxySQL(ctx, ilm.Metrics(), now)
+		if err != nil && !scrapererror.IsPartialScrapeError(err) {
+			return pdata.Metrics{}, err
+		}
+		if m.deltas != nil {
+			m.deltas.convert(ilm.Metrics())
+		}
+		return md, err
+	}
+
 	bufferPoolPages := initMetric(ilm.Metrics(), metadata.M.MysqlBufferPoolPages).Sum().DataPoints()
 	bufferPoolOperations := initMetric(ilm.Metrics(), metadata.M.MysqlBufferPoolOperations).Sum().DataPoints()
 	bufferPoolSize := initMetric(ilm.Metrics(), metadata.M.MysqlBufferPoolSize).Sum().DataPoints()
@@ -119,9 +259,11 @@ func (m *mySQLScraper) scrape(context.Context) (pdata.Metrics, error) {
 	threads := initMetric(ilm.Metrics(), metadata.M.MysqlThreads).Sum().DataPoints()
 
 	// collect innodb metrics.
-	innodbStats, err := m.sqlclient.getInnodbStats()
+	errs := &scrapererror.ScrapeErrors{}
+	innodbStats, err := m.query(ctx, statementInnodbStats, m.sqlclient.getInnodbStats)
 	if err != nil {
 		m.logger.Error("Failed to fetch InnoDB stats", zap.Error(err))
+		errs.AddPartial(1, err)
 	}
 
 	for k, v := range innodbStats {
@@ -136,10 +278,11 @@ func (m *mySQLScraper) scrape(context.Context) (pdata.Metrics, error) {
 	}
 
 	// collect global status metrics.
-	globalStats, err := m.sqlclient.getGlobalStats()
+	globalStats, err := m.query(ctx, statementGlobalStats, m.sqlclient.getGlobalStats)
 	if err != nil {
 		m.logger.Error("Failed to fetch global stats", zap.Error(err))
-		return pdata.Metrics{}, err
+		// None of the metrics of the scrape are collected, they are reported as errored metric points of the scraper.
+		return pdata.NewMetrics(), scrapererror.NewPartialScrapeError(err, ilm.Metrics().Len()+m.optionalMetrics())
 	}
 
 	for k, v := range globalStats {
@@ -505,7 +648,185 @@ func (m *mySQLScraper) scrape(context.Context) (pdata.Metrics, error) {
 			}
 		}
 	}
-	return md, nil
+
+	// collect schema sizes.
+	if m.config.SchemaSizes {
+		m.scrapeSchemaSizes(ctx, ilm.Metrics(), now, errs)
+	}
+
+	// collect index usage.
+	if m.config.IndexUsage {
+		m.scrapeIndexUsage(ctx, ilm.Metrics(), now, errs)
+	}
+
+	// collect long transactions and lock waits.
+	if m.config.Transactions {
+		m.scrapeTransactions(ctx, ilm.Metrics(), now, errs)
+	}
+
+	// collect the threads of the client connections and the connection limit.
+	if m.config.ConnectionStates {
+		m.scrapeConnectionStates(ctx, ilm.Metrics(), now, globalStats, errs)
+	}
+
+	if m.deltas != nil {
+		m.deltas.convert(ilm.Metrics())
+	}
+	return md, errs.Combine()
+}
+
+// The number of metrics collected by the optional statements, failed when the statements fail.
+const (
+	schemaSizesMetrics     = 1
+	indexUsageMetrics      = 3
+	transactionsMetrics    = 4
+	connectionStateMetrics = 3
+	connectionLimitMetrics = 1
+)
+
+// optionalMetrics returns the number of metrics collected by the optional statements enabled by the config.
+func (m *mySQLScraper) optionalMetrics() int {
+	n := 0
+	if m.config.SchemaSizes {
+		n += schemaSizesMetrics
+	}
+	if m.config.IndexUsage {
+		n += indexUsageMetrics
+	}
+	if m.config.Transactions {
+		n += transactionsMetrics
+	}
+	if m.config.ConnectionStates {
+		n += connectionStateMetrics
+	}
+	return n
+}
+
+// scrapeSchemaSizes adds the data and index sizes of every schema to the metric slice.
+func (m *mySQLScraper) scrapeSchemaSizes(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	sizes, err := m.querySchemaSizes(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch schema sizes", zap.Error(err))
+		errs.AddPartial(schemaSizesMetrics, err)
+		return
+	}
+
+	schemaSizes := initMetric(ms, metadata.M.MysqlSchemaSize).Sum().DataPoints()
+	for _, size := range sizes {
+		labels := pdata.NewAttributeMap()
+		labels.Insert(metadata.A.Schema, pdata.NewAttributeValueString(size.schema))
+		labels.Insert(metadata.A.SchemaSize, pdata.NewAttributeValueString("data"))
+		addToIntMetric(schemaSizes, labels, size.dataBytes, now)
+		labels.Update(metadata.A.SchemaSize, pdata.NewAttributeValueString("index"))
+		addToIntMetric(schemaSizes, labels, size.indexBytes, now)
+	}
+}
+
+// scrapeIndexUsage adds the rows read through every index and by full table scans, and the
+// number of unused secondary indexes of every schema, to the metric slice.
+func (m *mySQLScraper) scrapeIndexUsage(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	usages, err := m.queryIndexUsage(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch index usage", zap.Error(err))
+		errs.AddPartial(indexUsageMetrics, err)
+		return
+	}
+
+	indexReads := initMetric(ms, metadata.M.MysqlIndexRowsRead).Sum().DataPoints()
+	fullScanReads := initMetric(ms, metadata.M.MysqlTableFullScanRowsRead).Sum().DataPoints()
+	unused := map[string]int64{}
+	var schemas []string
+	for _, usage := range usages {
+		if _, ok := unused[usage.schema]; !ok {
+			unused[usage.schema] = 0
+			schemas = append(schemas, usage.schema)
+		}
+		labels := pdata.NewAttributeMap()
+		labels.Insert(metadata.A.Schema, pdata.NewAttributeValueString(usage.schema))
+		labels.Insert(metadata.A.Table, pdata.NewAttributeValueString(usage.table))
+		if usage.index == "" {
+			addToIntMetric(fullScanReads, labels, usage.rowsRead, now)
+			continue
+		}
+		labels.Insert(metadata.A.Index, pdata.NewAttributeValueString(usage.index))
+		addToIntMetric(indexReads, labels, usage.rowsRead, now)
+		// The primary key is needed even if no query uses it.
+		if usage.operations == 0 && usage.index != "PRIMARY" {
+			unused[usage.schema]++
+		}
+	}
+
+	unusedIndexes := initMetric(ms, metadata.M.MysqlIndexUnused).Gauge().DataPoints()
+	for _, schema := range schemas {
+		labels := pdata.NewAttributeMap()
+		labels.Insert(metadata.A.Schema, pdata.NewAttributeValueString(schema))
+		addToIntMetric(unusedIndexes, labels, unused[schema], now)
+	}
+}
+
+// scrapeTransactions adds the long transaction and lock wait gauges to the metric slice.
+func (m *mySQLScraper) scrapeTransactions(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	stats, err := m.queryTransactionStats(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch transaction stats", zap.Error(err))
+		errs.AddPartial(transactionsMetrics, err)
+		return
+	}
+
+	labels := pdata.NewAttributeMap()
+	addToIntMetric(initMetric(ms, metadata.M.MysqlTransactionsLong).Gauge().DataPoints(), labels, stats.longTransactions, now)
+	addToIntMetric(initMetric(ms, metadata.M.MysqlTransactionsMaxAge).Gauge().DataPoints(), labels, stats.maxTransactionAge, now)
+	addToIntMetric(initMetric(ms, metadata.M.MysqlLockWaits).Gauge().DataPoints(), labels, stats.lockWaits, now)
+	addToIntMetric(initMetric(ms, metadata.M.MysqlLockWaitsMaxAge).Gauge().DataPoints(), labels, stats.maxLockWaitAge, now)
+}
+
+// scrapeConnectionStates adds the threads of the client connections by state, the threads created and the
+// highest number of connections since the server started, from the global stats, along with max_connections.
+func (m *mySQLScraper) scrapeConnectionStates(ctx context.Context, ms pdata.MetricSlice, now pdata.Timestamp, globalStats map[string]string, errs *scrapererror.ScrapeErrors) {
+	threads := initMetric(ms, metadata.M.MysqlThreadsCount).Gauge().DataPoints()
+	for _, state := range []struct {
+		stat  string
+		state string
+	}{
+		{"Threads_cached", metadata.AttributeThreadState.Cached},
+		{"Threads_connected", metadata.AttributeThreadState.Connected},
+		{"Threads_running", metadata.AttributeThreadState.Running},
+	} {
+		v, ok := globalStats[state.stat]
+		if !ok {
+			continue
+		}
+		if i, ok := m.parseInt(state.stat, v); ok {
+			labels := pdata.NewAttributeMap()
+			labels.Insert(metadata.A.ThreadState, pdata.NewAttributeValueString(state.state))
+			addToIntMetric(threads, labels, i, now)
+		}
+	}
+
+	created := initMetric(ms, metadata.M.MysqlThreadsCreated).Sum().DataPoints()
+	if v, ok := globalStats["Threads_created"]; ok {
+		if i, ok := m.parseInt("Threads_created", v); ok {
+			addToIntMetric(created, pdata.NewAttributeMap(), i, now)
+		}
+	}
+
+	limits := initMetric(ms, metadata.M.MysqlConnectionLimit).Gauge().DataPoints()
+	if v, ok := globalStats["Max_used_connections"]; ok {
+		if i, ok := m.parseInt("Max_used_connections", v); ok {
+			labels := pdata.NewAttributeMap()
+			labels.Insert(metadata.A.ConnectionLimit, pdata.NewAttributeValueString(metadata.AttributeConnectionLimit.MaxUsed))
+			addToIntMetric(limits, labels, i, now)
+		}
+	}
+	maxConnections, err := m.queryMaxConnections(ctx)
+	if err != nil {
+		m.logger.Error("Failed to fetch max connections", zap.Error(err))
+		errs.AddPartial(connectionLimitMetrics, err)
+		return
+	}
+	labels := pdata.NewAttributeMap()
+	labels.Insert(metadata.A.ConnectionLimit, pdata.NewAttributeValueString(metadata.AttributeConnectionLimit.MaxAllowed))
+	addToIntMetric(limits, labels, maxConnections, now)
 }
 
 // parseFloat converts string to float64.
@@ -17,14 +17,19 @@ package mysqlreceiver
 import (
 	"bufio"
 	"context"
+	"database/sql"
+	"errors"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest"
@@ -55,9 +60,463 @@ func TestScrape(t *testing.T) {
 	require.NoError(t, scrapertest.CompareMetricSlices(eMetricSlice, aMetricSlice))
 }
 
+func TestScrapeDelta(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		AggregationTemporality: temporalityDelta,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{}
+
+	for i := 0; i < 2; i++ {
+		actualMetrics, err := scraper.scrape(context.Background())
+		require.NoError(t, err)
+		ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			sum := ms.At(j).Sum()
+			if !sum.IsMonotonic() {
+				require.Equal(t, pdata.MetricAggregationTemporalityCumulative, sum.AggregationTemporality())
+				require.NotZero(t, sum.DataPoints().Len())
+				continue
+			}
+			require.Equal(t, pdata.MetricAggregationTemporalityDelta, sum.AggregationTemporality())
+			if i == 0 {
+				require.Zero(t, sum.DataPoints().Len(), ms.At(j).Name())
+				continue
+			}
+			require.NotZero(t, sum.DataPoints().Len(), ms.At(j).Name())
+			for k := 0; k < sum.DataPoints().Len(); k++ {
+				require.Zero(t, sum.DataPoints().At(k).IntVal(), ms.At(j).Name())
+			}
+		}
+	}
+}
+
+func TestScrapeSchemaSizes(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		SchemaSizes: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	sizes := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "mysql.schema.size" {
+			continue
+		}
+		dps := ms.At(i).Sum().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			schema, _ := dps.At(j).Attributes().Get("schema")
+			kind, _ := dps.At(j).Attributes().Get("kind")
+			sizes[schema.StringVal()+"/"+kind.StringVal()] = dps.At(j).IntVal()
+		}
+	}
+	require.Equal(t, map[string]int64{
+		"otel/data":   16384,
+		"otel/index":  32768,
+		"mysql/data":  2523136,
+		"mysql/index": 376832,
+	}, sizes)
+}
+
+func TestScrapeIndexUsage(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		IndexUsage: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		var dps pdata.NumberDataPointSlice
+		switch ms.At(i).Name() {
+		case "mysql.index.rows_read", "mysql.table.full_scan.rows_read":
+			dps = ms.At(i).Sum().DataPoints()
+		case "mysql.index.unused":
+			dps = ms.At(i).Gauge().DataPoints()
+		default:
+			continue
+		}
+		for j := 0; j < dps.Len(); j++ {
+			key := ms.At(i).Name()
+			for _, attr := range []string{"schema", "table", "index"} {
+				if v, ok := dps.At(j).Attributes().Get(attr); ok {
+					key += "/" + v.StringVal()
+				}
+			}
+			values[key] = dps.At(j).IntVal()
+		}
+	}
+	require.Equal(t, map[string]int64{
+		"mysql.index.rows_read/otel/orders/PRIMARY":      1200,
+		"mysql.index.rows_read/otel/orders/idx_customer": 340,
+		"mysql.index.rows_read/otel/orders/idx_created":  0,
+		"mysql.index.rows_read/otel/customers/PRIMARY":   0,
+		"mysql.table.full_scan.rows_read/otel/orders":    5000,
+		"mysql.table.full_scan.rows_read/otel/customers": 80,
+		"mysql.index.rows_read/shop/products/idx_sku":    15,
+		"mysql.table.full_scan.rows_read/shop/products":  0,
+		"mysql.index.unused/otel":                        1,
+		"mysql.index.unused/shop":                        0,
+	}, values)
+}
+
+func TestScrapeIndexUsageError(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		IndexUsage: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{indexUsageErr: errors.New("performance_schema is disabled")}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Contains(t, err.Error(), "performance_schema is disabled")
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		require.NotContains(t, []string{"mysql.index.rows_read", "mysql.index.unused", "mysql.table.full_scan.rows_read"}, ms.At(i).Name())
+	}
+}
+
+func TestScrapeGlobalStatsError(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		IndexUsage:   true,
+		Transactions: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{globalStatsErr: errors.New("access denied")}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	// Every metric of the scrape is reported as failed.
+	var partialErr scrapererror.PartialScrapeError
+	require.True(t, errors.As(err, &partialErr))
+	require.Equal(t, 14+indexUsageMetrics+transactionsMetrics, partialErr.Failed)
+	require.Equal(t, 0, actualMetrics.MetricCount())
+}
+
+func TestScrapeInnodbStatsError(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{innodbErr: errors.New("access denied")}
+
+	// The InnoDB stats only provide the total size of the buffer pool, the scrape fails partially.
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Greater(t, actualMetrics.MetricCount(), 0)
+}
+
+func TestScrapeTransactions(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		Transactions:             true,
+		LongTransactionThreshold: 30 * time.Second,
+	}
+
+	client := &mockClient{}
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = client
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, client.longThreshold)
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	gauges := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).DataType() != pdata.MetricDataTypeGauge {
+			continue
+		}
+		dps := ms.At(i).Gauge().DataPoints()
+		require.Equal(t, 1, dps.Len())
+		require.Zero(t, dps.At(0).Attributes().Len())
+		gauges[ms.At(i).Name()] = dps.At(0).IntVal()
+	}
+	require.Equal(t, map[string]int64{
+		"mysql.transactions.long":    2,
+		"mysql.transactions.max_age": 325,
+		"mysql.lock_waits":           1,
+		"mysql.lock_waits.max_age":   12,
+	}, gauges)
+}
+
+func TestScrapeConnectionStates(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		ConnectionStates: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	values := map[string]map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		var dps pdata.NumberDataPointSlice
+		var attr string
+		switch ms.At(i).Name() {
+		case "mysql.threads.count":
+			dps, attr = ms.At(i).Gauge().DataPoints(), "state"
+		case "mysql.threads.created":
+			require.True(t, ms.At(i).Sum().IsMonotonic())
+			require.Equal(t, pdata.MetricAggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+			dps = ms.At(i).Sum().DataPoints()
+			require.Equal(t, 1, dps.Len())
+			require.Equal(t, 0, dps.At(0).Attributes().Len())
+			values[ms.At(i).Name()] = map[string]int64{"": dps.At(0).IntVal()}
+			continue
+		case "mysql.connection.limit":
+			dps, attr = ms.At(i).Gauge().DataPoints(), "kind"
+		default:
+			continue
+		}
+		values[ms.At(i).Name()] = map[string]int64{}
+		for j := 0; j < dps.Len(); j++ {
+			v, ok := dps.At(j).Attributes().Get(attr)
+			require.True(t, ok)
+			values[ms.At(i).Name()][v.StringVal()] = dps.At(j).IntVal()
+		}
+	}
+	require.Equal(t, map[string]map[string]int64{
+		"mysql.threads.count":    {"cached": 448, "connected": 449, "running": 451},
+		"mysql.threads.created":  {"": 450},
+		"mysql.connection.limit": {"max_used": 297, "max_allowed": 500},
+	}, values)
+}
+
+func TestScrapeConnectionStatesError(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		ConnectionStates: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{maxConnsErr: errors.New("access denied")}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Contains(t, err.Error(), "access denied")
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() == "mysql.connection.limit" {
+			dps := ms.At(i).Gauge().DataPoints()
+			require.Equal(t, 1, dps.Len())
+			v, _ := dps.At(0).Attributes().Get("kind")
+			require.Equal(t, "max_used", v.StringVal())
+		}
+	}
+}
+
+func TestScrapeTransactionsError(t *testing.T) {
+	cfg := &Config{
+		Username: "otel",
+		Password: "otel",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:3306",
+		},
+		Transactions: true,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{transactionsErr: errors.New("access denied")}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Contains(t, err.Error(), "access denied")
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		require.NotEqual(t, pdata.MetricDataTypeGauge, ms.At(i).DataType(), ms.At(i).Name())
+	}
+}
+
+func TestScrapeProxySQL(t *testing.T) {
+	cfg := &Config{
+		Username: "admin",
+		Password: "admin",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:6032",
+		},
+		Mode: modeProxySQL,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		var dps pdata.NumberDataPointSlice
+		if ms.At(i).DataType() == pdata.MetricDataTypeGauge {
+			dps = ms.At(i).Gauge().DataPoints()
+		} else {
+			dps = ms.At(i).Sum().DataPoints()
+		}
+		for j := 0; j < dps.Len(); j++ {
+			key := ms.At(i).Name()
+			dps.At(j).Attributes().Sort().Range(func(k string, v pdata.AttributeValue) bool {
+				key += " " + k + "=" + v.AsString()
+				return true
+			})
+			values[key] = dps.At(j).IntVal()
+		}
+	}
+	require.Equal(t, map[string]int64{
+		"mysql.proxysql.connection_pool.connections hostgroup=10 server=mysql-1:3306 state=used": 4,
+		"mysql.proxysql.connection_pool.connections hostgroup=10 server=mysql-1:3306 state=free": 6,
+		"mysql.proxysql.connection_pool.connections hostgroup=20 server=mysql-2:3306 state=used": 0,
+		"mysql.proxysql.connection_pool.connections hostgroup=20 server=mysql-2:3306 state=free": 2,
+		"mysql.proxysql.connection_pool.connects hostgroup=10 result=ok server=mysql-1:3306":     12,
+		"mysql.proxysql.connection_pool.connects hostgroup=10 result=error server=mysql-1:3306":  1,
+		"mysql.proxysql.connection_pool.connects hostgroup=20 result=ok server=mysql-2:3306":     2,
+		"mysql.proxysql.connection_pool.connects hostgroup=20 result=error server=mysql-2:3306":  0,
+		"mysql.proxysql.connection_pool.queries hostgroup=10 server=mysql-1:3306":                1500,
+		"mysql.proxysql.connection_pool.queries hostgroup=20 server=mysql-2:3306":                30,
+		"mysql.proxysql.connection_pool.latency hostgroup=10 server=mysql-1:3306":                230,
+		"mysql.proxysql.connection_pool.latency hostgroup=20 server=mysql-2:3306":                410,
+		"mysql.proxysql.server.status hostgroup=10 server=mysql-1:3306 status=online":            1,
+		"mysql.proxysql.server.status hostgroup=10 server=mysql-1:3306 status=shunned":           0,
+		"mysql.proxysql.server.status hostgroup=10 server=mysql-1:3306 status=offline_soft":      0,
+		"mysql.proxysql.server.status hostgroup=10 server=mysql-1:3306 status=offline_hard":      0,
+		"mysql.proxysql.server.status hostgroup=20 server=mysql-2:3306 status=online":            0,
+		"mysql.proxysql.server.status hostgroup=20 server=mysql-2:3306 status=shunned":           1,
+		"mysql.proxysql.server.status hostgroup=20 server=mysql-2:3306 status=offline_soft":      0,
+		"mysql.proxysql.server.status hostgroup=20 server=mysql-2:3306 status=offline_hard":      0,
+		"mysql.proxysql.query_rule.hits rule_id=1":                                               1200,
+		"mysql.proxysql.query_rule.hits rule_id=2":                                               300,
+		"mysql.proxysql.connection_pool.gets kind=immediate":                                     1450,
+		"mysql.proxysql.connection_pool.gets kind=success":                                       1530,
+		"mysql.proxysql.connection_pool.gets kind=failure":                                       2,
+	}, values)
+}
+
+func TestScrapeProxySQLQueryRulesError(t *testing.T) {
+	cfg := &Config{
+		Username: "admin",
+		Password: "admin",
+		NetAddr: confignet.NetAddr{
+			Endpoint: "localhost:6032",
+		},
+		Mode: modeProxySQL,
+	}
+
+	scraper := newMySQLScraper(zap.NewNop(), cfg)
+	scraper.sqlclient = &mockClient{queryRulesErr: errors.New("no such table: stats_mysql_query_rules")}
+
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	ms := actualMetrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 6, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		require.NotEqual(t, "mysql.proxysql.query_rule.hits", ms.At(i).Name())
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	sqlclient := &mockClient{pingErr: errors.New("invalid connection")}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		healthCheck(ctx, sqlclient, 10*time.Millisecond, func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+	}()
+
+	select {
+	case err := <-errs:
+		require.EqualError(t, err, "invalid connection")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the failed ping was not reported")
+	}
+	cancel()
+	<-done
+}
+
 var _ client = (*mockClient)(nil)
 
-type mockClient struct{}
+type mockClient struct {
+	longThreshold   time.Duration
+	transactionsErr error
+	maxConnsErr     error
+	indexUsageErr   error
+	errorLog        []errorLogRecord
+	errorLogErr     error
+	pingErr         error
+	queryRulesErr   error
+	globalStatsErr  error
+	innodbErr       error
+	pool            sql.DBStats
+}
 
 func readFile(fname string) (map[string]string, error) {
 	var stats = map[string]string{}
@@ -80,13 +539,119 @@ func (c *mockClient) Connect() error {
 }
 
 func (c *mockClient) getGlobalStats() (map[string]string, error) {
+	if c.globalStatsErr != nil {
+		return nil, c.globalStatsErr
+	}
 	return readFile("global_stats")
 }
 
 func (c *mockClient) getInnodbStats() (map[string]string, error) {
+	if c.innodbErr != nil {
+		return nil, c.innodbErr
+	}
 	return readFile("innodb_stats")
 }
 
+func (c *mockClient) getSchemaSizes() ([]schemaSize, error) {
+	return []schemaSize{
+		{schema: "otel", dataBytes: 16384, indexBytes: 32768},
+		{schema: "mysql", dataBytes: 2523136, indexBytes: 376832},
+	}, nil
+}
+
+func (c *mockClient) getIndexUsage() ([]indexUsage, error) {
+	if c.indexUsageErr != nil {
+		return nil, c.indexUsageErr
+	}
+	return []indexUsage{
+		{schema: "otel", table: "orders", index: "PRIMARY", rowsRead: 1200, operations: 1450},
+		{schema: "otel", table: "orders", index: "idx_customer", rowsRead: 340, operations: 360},
+		{schema: "otel", table: "orders", index: "idx_created"},
+		{schema: "otel", table: "orders", rowsRead: 5000, operations: 5210},
+		{schema: "otel", table: "customers", index: "PRIMARY"},
+		{schema: "otel", table: "customers", rowsRead: 80, operations: 85},
+		{schema: "shop", table: "products", index: "idx_sku", rowsRead: 15, operations: 15},
+		{schema: "shop", table: "products"},
+	}, nil
+}
+
+func (c *mockClient) getTransactionStats(longThreshold time.Duration) (transactionStats, error) {
+	c.longThreshold = longThreshold
+	if c.transactionsErr != nil {
+		return transactionStats{}, c.transactionsErr
+	}
+	return transactionStats{
+		longTransactions:  2,
+		maxTransactionAge: 325,
+		lockWaits:         1,
+		maxLockWaitAge:    12,
+	}, nil
+}
+
+func (c *mockClient) getMaxConnections() (int64, error) {
+	if c.maxConnsErr != nil {
+		return 0, c.maxConnsErr
+	}
+	return 500, nil
+}
+
+func (c *mockClient) getErrorLog(afterMicros int64) ([]errorLogRecord, error) {
+	if c.errorLogErr != nil {
+		return nil, c.errorLogErr
+	}
+	var records []errorLogRecord
+	for _, record := range c.errorLog {
+		if record.timestamp.UnixMicro() > afterMicros {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (c *mockClient) getErrorLogEnd() (int64, error) {
+	if c.errorLogErr != nil {
+		return 0, c.errorLogErr
+	}
+	var micros int64
+	for _, record := range c.errorLog {
+		if record.timestamp.UnixMicro() > micros {
+			micros = record.timestamp.UnixMicro()
+		}
+	}
+	return micros, nil
+}
+
+func (c *mockClient) getProxySQLConnectionPool() ([]proxySQLBackend, error) {
+	return []proxySQLBackend{
+		{hostgroup: "10", host: "mysql-1", port: "3306", status: "ONLINE", connUsed: 4, connFree: 6, connOK: 12, connErr: 1, queries: 1500, latencyUs: 230},
+		{hostgroup: "20", host: "mysql-2", port: "3306", status: "SHUNNED_REPLICATION_LAG", connFree: 2, connOK: 2, queries: 30, latencyUs: 410},
+	}, nil
+}
+
+func (c *mockClient) getProxySQLQueryRules() ([]proxySQLQueryRule, error) {
+	if c.queryRulesErr != nil {
+		return nil, c.queryRulesErr
+	}
+	return []proxySQLQueryRule{{ruleID: "1", hits: 1200}, {ruleID: "2", hits: 300}}, nil
+}
+
+func (c *mockClient) getProxySQLGlobalStats() (map[string]string, error) {
+	return map[string]string{
+		"ConnPool_get_conn_immediate":         "1450",
+		"ConnPool_get_conn_success":           "1530",
+		"ConnPool_get_conn_failure":           "2",
+		"ConnPool_get_conn_latency_awareness": "0",
+	}, nil
+}
+
+func (c *mockClient) ping(context.Context) error {
+	return c.pingErr
+}
+
+func (c *mockClient) poolStats() sql.DBStats {
+	return c.pool
+}
+
 func (c *mockClient) Close() error {
 	return nil
 }
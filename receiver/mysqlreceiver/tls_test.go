@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// writeCertificate writes a self-signed certificate for commonName valid until
+// notAfter, with the given modification time.
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, notAfter, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	now := time.Now()
+	writeCertificate(t, certFile, keyFile, "first", now.Add(time.Hour), now.Add(-time.Minute))
+
+	reloader, err := newCertificateReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	cert, err := reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	// A rotated certificate is used for the next connections.
+	writeCertificate(t, certFile, keyFile, "rotated", now.Add(2*time.Hour), now)
+	cert, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", cert.Leaf.Subject.CommonName)
+
+	// The valid certificate is kept while the files are being rewritten.
+	require.NoError(t, os.WriteFile(certFile, []byte("partial"), 0600))
+	require.NoError(t, os.Chtimes(certFile, now.Add(time.Minute), now.Add(time.Minute)))
+	cert, err = reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", cert.Leaf.Subject.CommonName)
+}
+
+func TestCertificateReloaderExpired(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	now := time.Now()
+	writeCertificate(t, certFile, keyFile, "expired", now.Add(-time.Minute), now.Add(-time.Minute))
+
+	reloader, err := newCertificateReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	// An expired certificate is reloaded even if its files were not modified.
+	writeCertificate(t, certFile, keyFile, "renewed", now.Add(time.Hour), now.Add(-time.Minute))
+	cert, err := reloader.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "renewed", cert.Leaf.Subject.CommonName)
+
+	// An expired certificate that can't be reloaded fails the connection.
+	require.NoError(t, os.Remove(keyFile))
+	reloader.cert.Leaf.NotAfter = now.Add(-time.Second)
+	_, err = reloader.GetClientCertificate(nil)
+	assert.Error(t, err)
+}
+
+func TestNewCertificateReloaderMissingFiles(t *testing.T) {
+	_, err := newCertificateReloader("missing.crt", "missing.key")
+	assert.Error(t, err)
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	tlsConfig, err := loadTLSConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "TLS is disabled by default")
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writeCertificate(t, certFile, keyFile, "client", time.Now().Add(time.Hour), time.Now())
+	cfg.TLS = configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: certFile, KeyFile: keyFile},
+	}
+	tlsConfig, err = loadTLSConfig(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig)
+	assert.Empty(t, tlsConfig.Certificates)
+	require.NotNil(t, tlsConfig.GetClientCertificate)
+
+	client, err := newMySQLClient(cfg, config.MetricsDataType)
+	require.NoError(t, err)
+	assert.Contains(t, client.(*mySQLClient).connStr, "tls=otel-mysql-metrics")
+	require.NoError(t, client.Close())
+}
@@ -31,8 +31,11 @@ import (
 	"github.com/prometheus/prometheus/discovery/kubernetes"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/service/featuregate"
 	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 )
 
 const (
@@ -58,7 +61,64 @@ type Config struct {
 	BufferCount             int                      `mapstructure:"buffer_count"`
 	UseStartTimeMetric      bool                     `mapstructure:"use_start_time_metric"`
 	StartTimeMetricRegex    string                   `mapstructure:"start_time_metric_regex"`
-	pdataDirect             bool
+	// DuplicateSamples defines how samples of a series exposed more than once in a single scrape
+	// are handled, possible values are: keep_last (default), keep_first or reject.
+	DuplicateSamples string `mapstructure:"duplicate_samples"`
+	// MissingMetadata defines how metrics scraped without metadata are handled, possible
+	// values are: gauge (default) to convert them to gauges, gauge_unknown_targets to also
+	// convert all the metrics of the targets which can't be found, or drop.
+	MissingMetadata string `mapstructure:"missing_metadata"`
+	// TargetLabelsAsAttributes lists the labels of the scrape targets, e.g. job, instance or
+	// labels set by relabel_configs, which are added to the attributes of every data point.
+	TargetLabelsAsAttributes []string `mapstructure:"target_labels_as_attributes"`
+	// InfoMetrics defines how the info metrics, the gauges named *_info whose series have a value
+	// of 1 and labels describing the target, are handled, possible values are: gauge (default) to
+	// keep them as gauges, or resource to add their labels to the attributes of the resource.
+	InfoMetrics string `mapstructure:"info_metrics"`
+	// TraceScrapes enables a span, emitted with the internal tracer of the collector, for the
+	// transaction of every scrape with the job and instance of the target, the number of samples
+	// appended and data points sent, and the result of the commit.
+	TraceScrapes bool `mapstructure:"trace_scrapes"`
+	// ServiceDiscoveries lists the service discovery mechanisms the scrape configs are allowed
+	// to use, e.g. static, file, kubernetes or ec2. Defaults to all the compiled in mechanisms.
+	ServiceDiscoveries []string `mapstructure:"service_discoveries"`
+	// JobsCache configures the cache of the scrape targets used to adjust the start time of
+	// cumulative metrics when use_start_time_metric is disabled.
+	JobsCache JobsCacheConfig `mapstructure:"jobs_cache"`
+	// RemoteWrite enables an endpoint ingesting the Prometheus remote-write protocol, so that
+	// Prometheus servers can push their samples to the receiver.
+	RemoteWrite *RemoteWriteConfig `mapstructure:"remote_write"`
+	// ScrapeAuthenticators sets the credentials of scrape jobs from client authenticator
+	// extensions, e.g. for short-lived tokens, instead of inlining them in their scrape config.
+	ScrapeAuthenticators []ScrapeAuthenticatorConfig `mapstructure:"scrape_authenticators"`
+	// DrainTimeout is how long the receiver waits on shutdown for the scrapes in flight to commit
+	// their samples before the scrapes are stopped. Defaults to 0, stopping them right away.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+	// LabelValueLimit limits the length of the label values of the scraped series, so that a
+	// target embedding e.g. stack traces in its labels doesn't explode downstream storage.
+	LabelValueLimit LabelValueLimitConfig `mapstructure:"label_value_limit"`
+	// SampleAge bounds the timestamps of the scraped samples relative to the collector wall clock,
+	// so that targets with skewed clocks don't send out-of-order writes to downstream systems.
+	SampleAge SampleAgeConfig `mapstructure:"sample_age"`
+	// TargetHealth defines when the receiver reports its targets as unhealthy to the health_check extension.
+	TargetHealth TargetHealthConfig `mapstructure:"target_health"`
+	// Temporality is the aggregation temporality of the counters and histograms, possible
+	// values are: cumulative (default), or delta to emit the difference between consecutive
+	// scrapes for backends which can't ingest cumulative data.
+	Temporality string `mapstructure:"temporality"`
+	// JobResourceAttributes adds static attributes, e.g. deployment.environment, to the resource of
+	// the metrics scraped by a job, instead of a resource processor in every pipeline.
+	JobResourceAttributes []JobResourceAttributesConfig `mapstructure:"job_resource_attributes"`
+	// TargetResourceAttributes configures the port and scheme attributes of the resources of the
+	// targets, which backends treating every attribute as an identifying dimension may not need.
+	TargetResourceAttributes TargetResourceAttributesConfig `mapstructure:"target_resource_attributes"`
+	// KubernetesAnnotations generates a scrape config for the pods annotated with prometheus.io/scrape,
+	// instead of writing the kubernetes_sd_configs and relabel_configs of the annotations by hand.
+	KubernetesAnnotations KubernetesAnnotationsConfig `mapstructure:"kubernetes_annotations"`
+	// Debug enables an endpoint exposing the active targets, their last scrape errors and the
+	// metadata of their metrics, like the /targets page of Prometheus.
+	Debug       *DebugConfig `mapstructure:"debug"`
+	pdataDirect bool
 
 	// ConfigPlaceholder is just an entry to make the configuration pass a check
 	// that requires that all keys present in the config actually exist on the
@@ -66,6 +126,99 @@ type Config struct {
 	ConfigPlaceholder interface{} `mapstructure:"config"`
 }
 
+// JobsCacheConfig defines how the entries of the jobs cache are evicted.
+type JobsCacheConfig struct {
+	// GCInterval is the interval at which the targets not scraped since the previous garbage
+	// collection are evicted from the cache. It defaults to the longest scrape interval plus
+	// one minute, with a minimum of two minutes.
+	GCInterval time.Duration `mapstructure:"gc_interval"`
+	// MaxEntries is the maximum number of targets held by the cache, the least recently scraped
+	// target is evicted when the cache is full. Defaults to 0, meaning unlimited.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// LabelValueLimitConfig defines how the label values longer than a maximum length are handled.
+type LabelValueLimitConfig struct {
+	// MaxLength is the maximum length of the label values in bytes. Defaults to 0, meaning unlimited.
+	MaxLength int `mapstructure:"max_length"`
+	// Action is applied to the series having label values longer than MaxLength, possible
+	// values are: drop (default), or truncate to truncate the values to MaxLength, which may
+	// merge distinct series whose values only differ after MaxLength.
+	Action string `mapstructure:"action"`
+}
+
+// SampleAgeConfig defines how the samples whose timestamps are too far from the collector wall clock are handled.
+type SampleAgeConfig struct {
+	// MaxAge is the maximum age of the samples. Defaults to 0, meaning unlimited.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// MaxFuture is the maximum duration the samples can be ahead of the collector wall clock.
+	// Defaults to 0, meaning unlimited.
+	MaxFuture time.Duration `mapstructure:"max_future"`
+	// Action is applied to the samples out of range, possible values are: drop (default), or
+	// clamp to set their timestamps to the closest bound of the range. A series is clamped once
+	// per timestamp of its samples, the samples exposed again with the same timestamp are dropped.
+	Action string `mapstructure:"action"`
+}
+
+// TargetHealthConfig defines when the jobs whose targets are down are unhealthy.
+type TargetHealthConfig struct {
+	// MaxDownRatio is the maximum ratio of the scraped targets of a job which can be down for the job to be
+	// healthy, between 0, making the job unhealthy as soon as one of its targets is down, and 1, never
+	// making it unhealthy. Defaults to 0.5.
+	MaxDownRatio float64 `mapstructure:"max_down_ratio"`
+}
+
+// JobResourceAttributesConfig defines the resource attributes of the metrics scraped by a job.
+type JobResourceAttributesConfig struct {
+	// JobName is the name of the scrape config whose resources get the attributes.
+	JobName string `mapstructure:"job_name"`
+	// Attributes are added to the resources of the targets of the job, the attributes set by
+	// the receiver, e.g. service.name or instance, take precedence.
+	Attributes map[string]string `mapstructure:"attributes"`
+}
+
+// TargetResourceAttributesConfig defines the port and scheme attributes added to the resources of the targets.
+type TargetResourceAttributesConfig struct {
+	// Naming is the naming of the attributes, possible values are: legacy (default) for port and
+	// scheme, or semconv for server.port and url.scheme.
+	Naming string `mapstructure:"naming"`
+	// Port enables the attribute of the port of the target, enabled by default.
+	Port bool `mapstructure:"port"`
+	// Scheme enables the attribute of the scheme used to scrape the target, enabled by default.
+	Scheme bool `mapstructure:"scheme"`
+}
+
+// KubernetesAnnotationsConfig defines the scrape config generated for the pods annotated with the
+// prometheus.io/scrape, prometheus.io/scheme, prometheus.io/path and prometheus.io/port annotations.
+type KubernetesAnnotationsConfig struct {
+	// Enabled adds the scrape config to the scrape_configs of the Prometheus configuration.
+	Enabled bool `mapstructure:"enabled"`
+	// JobName is the name of the scrape config, kubernetes-pods by default.
+	JobName string `mapstructure:"job_name"`
+	// Namespaces are the namespaces the pods are discovered in. Defaults to all the namespaces.
+	Namespaces []string `mapstructure:"namespaces"`
+	// ExcludeNamespaces are the namespaces whose pods are not scraped, e.g. kube-system.
+	ExcludeNamespaces []string `mapstructure:"exclude_namespaces"`
+	// NodeName restricts the scraped pods to the ones running on the node, e.g. the node of
+	// the collector when it is deployed as a daemon set.
+	NodeName string `mapstructure:"node_name"`
+	// ScrapeInterval is the interval at which the pods are scraped. Defaults to the global
+	// scrape_interval of the Prometheus configuration.
+	ScrapeInterval time.Duration `mapstructure:"scrape_interval"`
+}
+
+// RemoteWriteConfig defines the HTTP server receiving remote-write requests on
+// the /api/v1/write path.
+type RemoteWriteConfig struct {
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+}
+
+// DebugConfig defines the HTTP server exposing the scrape targets on the /targets page, and
+// on the /api/v1/targets and /api/v1/targets/metadata endpoints of the Prometheus HTTP API.
+type DebugConfig struct {
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+}
+
 var _ config.Receiver = (*Config)(nil)
 var _ config.Unmarshallable = (*Config)(nil)
 
@@ -133,11 +286,116 @@ func checkSDFile(filename string) error {
 
 // Validate checks the receiver configuration is valid.
 func (cfg *Config) Validate() error {
+	switch cfg.DuplicateSamples {
+	case "", internal.DuplicateSamplesKeepLast, internal.DuplicateSamplesKeepFirst, internal.DuplicateSamplesReject:
+	default:
+		return fmt.Errorf("invalid duplicate_samples %q: can be either %q, %q or %q", cfg.DuplicateSamples,
+			internal.DuplicateSamplesKeepLast, internal.DuplicateSamplesKeepFirst, internal.DuplicateSamplesReject)
+	}
+
+	switch cfg.MissingMetadata {
+	case internal.MissingMetadataGauge, internal.MissingMetadataGaugeUnknownTargets, internal.MissingMetadataDrop:
+	default:
+		return fmt.Errorf("invalid missing_metadata %q: can be either %q, %q or %q", cfg.MissingMetadata,
+			internal.MissingMetadataGauge, internal.MissingMetadataGaugeUnknownTargets, internal.MissingMetadataDrop)
+	}
+
+	switch cfg.InfoMetrics {
+	case "", internal.InfoMetricsGauge, internal.InfoMetricsResource:
+	default:
+		return fmt.Errorf("invalid info_metrics %q: can be either %q or %q", cfg.InfoMetrics,
+			internal.InfoMetricsGauge, internal.InfoMetricsResource)
+	}
+
+	for _, name := range cfg.TargetLabelsAsAttributes {
+		if name == "" {
+			return errors.New("target_labels_as_attributes cannot contain empty label names")
+		}
+	}
+
+	for _, name := range cfg.ServiceDiscoveries {
+		if name == "" {
+			return errors.New("service_discoveries cannot contain empty names")
+		}
+	}
+
+	if cfg.JobsCache.GCInterval < 0 {
+		return fmt.Errorf("jobs_cache.gc_interval has to be positive, got %v", cfg.JobsCache.GCInterval)
+	}
+	if cfg.JobsCache.MaxEntries < 0 {
+		return fmt.Errorf("jobs_cache.max_entries has to be positive, got %d", cfg.JobsCache.MaxEntries)
+	}
+
+	if cfg.LabelValueLimit.MaxLength < 0 {
+		return fmt.Errorf("label_value_limit.max_length has to be positive, got %d", cfg.LabelValueLimit.MaxLength)
+	}
+	switch cfg.LabelValueLimit.Action {
+	case "", internal.LabelValueLimitTruncate, internal.LabelValueLimitDrop:
+	default:
+		return fmt.Errorf("invalid label_value_limit.action %q: can be either %q or %q", cfg.LabelValueLimit.Action,
+			internal.LabelValueLimitTruncate, internal.LabelValueLimitDrop)
+	}
+
+	if cfg.SampleAge.MaxAge < 0 {
+		return fmt.Errorf("sample_age.max_age has to be positive, got %v", cfg.SampleAge.MaxAge)
+	}
+	if cfg.SampleAge.MaxFuture < 0 {
+		return fmt.Errorf("sample_age.max_future has to be positive, got %v", cfg.SampleAge.MaxFuture)
+	}
+	switch cfg.SampleAge.Action {
+	case "", internal.SampleAgeDrop, internal.SampleAgeClamp:
+	default:
+		return fmt.Errorf("invalid sample_age.action %q: can be either %q or %q", cfg.SampleAge.Action,
+			internal.SampleAgeDrop, internal.SampleAgeClamp)
+	}
+
+	if cfg.TargetHealth.MaxDownRatio < 0 || cfg.TargetHealth.MaxDownRatio > 1 {
+		return fmt.Errorf("target_health.max_down_ratio has to be between 0 and 1, got %v", cfg.TargetHealth.MaxDownRatio)
+	}
+
+	switch cfg.TargetResourceAttributes.Naming {
+	case "", internal.ResourceAttributesNamingLegacy, internal.ResourceAttributesNamingSemconv:
+	default:
+		return fmt.Errorf("invalid target_resource_attributes.naming %q: can be either %q or %q", cfg.TargetResourceAttributes.Naming,
+			internal.ResourceAttributesNamingLegacy, internal.ResourceAttributesNamingSemconv)
+	}
+
+	switch cfg.Temporality {
+	case "", internal.TemporalityCumulative, internal.TemporalityDelta:
+	default:
+		return fmt.Errorf("invalid temporality %q: can be either %q or %q", cfg.Temporality,
+			internal.TemporalityCumulative, internal.TemporalityDelta)
+	}
+
+	if cfg.DrainTimeout < 0 {
+		return fmt.Errorf("drain_timeout has to be positive, got %v", cfg.DrainTimeout)
+	}
+
+	if cfg.RemoteWrite != nil && cfg.RemoteWrite.Endpoint == "" {
+		return errors.New("remote_write.endpoint has to be set")
+	}
+
+	if cfg.Debug != nil && cfg.Debug.Endpoint == "" {
+		return errors.New("debug.endpoint has to be set")
+	}
+
+	if err := cfg.KubernetesAnnotations.validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.validateScrapeAuthenticators(); err != nil {
+		return err
+	}
+
+	if err := cfg.validateJobResourceAttributes(); err != nil {
+		return err
+	}
+
 	promConfig := cfg.PrometheusConfig
 	if promConfig == nil {
 		return nil // noop receiver
 	}
-	if len(promConfig.ScrapeConfigs) == 0 {
+	if len(promConfig.ScrapeConfigs) == 0 && cfg.RemoteWrite == nil {
 		return errors.New("no Prometheus scrape_configs")
 	}
 
@@ -168,6 +426,12 @@ func (cfg *Config) Validate() error {
 	}
 
 	for _, sc := range cfg.PrometheusConfig.ScrapeConfigs {
+		if cfg.JobsCache.GCInterval > 0 && cfg.JobsCache.GCInterval <= time.Duration(sc.ScrapeInterval) {
+			// The targets of the job would be evicted between consecutive scrapes.
+			return fmt.Errorf("jobs_cache.gc_interval %v has to be greater than the scrape interval %v of job %v",
+				cfg.JobsCache.GCInterval, time.Duration(sc.ScrapeInterval), sc.JobName)
+		}
+
 		for _, rc := range sc.MetricRelabelConfigs {
 			if rc.TargetLabel == "__name__" {
 				// TODO(#2297): Remove validation after renaming is fixed
@@ -186,6 +450,9 @@ func (cfg *Config) Validate() error {
 		}
 
 		for _, c := range sc.ServiceDiscoveryConfigs {
+			if !cfg.serviceDiscoveryEnabled(c.Name()) {
+				return fmt.Errorf("service discovery %q of scrape job %q is not enabled in service_discoveries", c.Name(), sc.JobName)
+			}
 			switch c := c.(type) {
 			case *kubernetes.SDConfig:
 				if err := checkTLSConfig(c.HTTPClientConfig.TLSConfig); err != nil {
@@ -214,6 +481,86 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// validateScrapeAuthenticators checks every scrape authenticator references a distinct job that
+// doesn't set its own credentials.
+func (cfg *Config) validateScrapeAuthenticators() error {
+	jobs := make(map[string]bool, len(cfg.ScrapeAuthenticators))
+	for _, sa := range cfg.ScrapeAuthenticators {
+		if sa.JobName == "" {
+			return errors.New("scrape_authenticators.job_name has to be set")
+		}
+		if jobs[sa.JobName] {
+			return fmt.Errorf("job %q is referenced by more than one scrape authenticator", sa.JobName)
+		}
+		jobs[sa.JobName] = true
+		if sa.AuthenticatorID == (config.ComponentID{}) {
+			return fmt.Errorf("the scrape authenticator of job %q has no authenticator", sa.JobName)
+		}
+		if sa.RefreshInterval < 0 {
+			return fmt.Errorf("the refresh_interval of the scrape authenticator of job %q has to be positive, got %v", sa.JobName, sa.RefreshInterval)
+		}
+
+		i := scrapeConfigIndex(cfg.PrometheusConfig, sa.JobName)
+		if i < 0 {
+			return fmt.Errorf("scrape authenticator references unknown job %q", sa.JobName)
+		}
+		hc := cfg.PrometheusConfig.ScrapeConfigs[i].HTTPClientConfig
+		if hc.Authorization != nil || hc.BasicAuth != nil || hc.OAuth2 != nil || hc.BearerToken != "" || hc.BearerTokenFile != "" {
+			return fmt.Errorf("job %q cannot set its own credentials when it has a scrape authenticator", sa.JobName)
+		}
+	}
+	return nil
+}
+
+// validateJobResourceAttributes checks the resource attributes are set once for every job, which has
+// to be a configured scrape job.
+func (cfg *Config) validateJobResourceAttributes() error {
+	jobs := make(map[string]bool, len(cfg.JobResourceAttributes))
+	for _, ra := range cfg.JobResourceAttributes {
+		if ra.JobName == "" {
+			return errors.New("job_resource_attributes.job_name has to be set")
+		}
+		if jobs[ra.JobName] {
+			return fmt.Errorf("job %q is referenced more than once in job_resource_attributes", ra.JobName)
+		}
+		jobs[ra.JobName] = true
+		for name := range ra.Attributes {
+			if name == "" {
+				return fmt.Errorf("the resource attributes of job %q cannot contain empty names", ra.JobName)
+			}
+		}
+		if scrapeConfigIndex(cfg.PrometheusConfig, ra.JobName) < 0 {
+			return fmt.Errorf("job_resource_attributes references unknown job %q", ra.JobName)
+		}
+	}
+	return nil
+}
+
+// jobResourceAttributes returns the resource attributes of JobResourceAttributes keyed by job name.
+func (cfg *Config) jobResourceAttributes() map[string]map[string]string {
+	if len(cfg.JobResourceAttributes) == 0 {
+		return nil
+	}
+	attrs := make(map[string]map[string]string, len(cfg.JobResourceAttributes))
+	for _, ra := range cfg.JobResourceAttributes {
+		attrs[ra.JobName] = ra.Attributes
+	}
+	return attrs
+}
+
+// serviceDiscoveryEnabled returns whether the scrape configs can use the named service discovery.
+func (cfg *Config) serviceDiscoveryEnabled(name string) bool {
+	if len(cfg.ServiceDiscoveries) == 0 {
+		return true
+	}
+	for _, enabled := range cfg.ServiceDiscoveries {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Unmarshal a config.Parser into the config struct.
 func (cfg *Config) Unmarshal(componentParser *config.Map) error {
 	if componentParser == nil {
@@ -229,10 +576,17 @@ func (cfg *Config) Unmarshal(componentParser *config.Map) error {
 
 	// Unmarshal prometheus's config values. Since prometheus uses `yaml` tags, so use `yaml`.
 	promCfg, err := componentParser.Sub(prometheusConfigKey)
-	if err != nil || len(promCfg.ToStringMap()) == 0 {
+	if err != nil {
 		return err
 	}
-	out, err := yaml.Marshal(promCfg.ToStringMap())
+	promCfgMap := promCfg.ToStringMap()
+	if cfg.KubernetesAnnotations.Enabled {
+		cfg.KubernetesAnnotations.addScrapeConfig(promCfgMap)
+	}
+	if len(promCfgMap) == 0 {
+		return nil
+	}
+	out, err := yaml.Marshal(promCfgMap)
 	if err != nil {
 		return fmt.Errorf("prometheus receiver failed to marshal config to yaml: %s", err)
 	}
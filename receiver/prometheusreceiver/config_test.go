@@ -20,10 +20,13 @@ import (
 	"testing"
 	"time"
 
+	commonconfig "github.com/prometheus/common/config"
+	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/service/servicetest"
 )
 
@@ -48,6 +51,19 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, time.Duration(r1.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval), 5*time.Second)
 	assert.Equal(t, r1.UseStartTimeMetric, true)
 	assert.Equal(t, r1.StartTimeMetricRegex, "^(.+_)*process_start_time_seconds$")
+	assert.Equal(t, r1.DuplicateSamples, "keep_first")
+	assert.Equal(t, r1.MissingMetadata, "drop")
+	assert.Equal(t, r1.TargetLabelsAsAttributes, []string{"job", "instance"})
+	assert.Equal(t, r1.InfoMetrics, "resource")
+	assert.Equal(t, r1.TraceScrapes, true)
+	assert.Equal(t, r1.ServiceDiscoveries, []string{"static", "file"})
+	assert.Equal(t, r1.JobsCache, JobsCacheConfig{GCInterval: 10 * time.Minute, MaxEntries: 1000})
+	assert.Equal(t, r1.DrainTimeout, 30*time.Second)
+	assert.Equal(t, r1.LabelValueLimit, LabelValueLimitConfig{MaxLength: 256, Action: "drop"})
+	assert.Equal(t, r1.SampleAge, SampleAgeConfig{MaxAge: time.Hour, MaxFuture: 5 * time.Minute, Action: "clamp"})
+	assert.Equal(t, r1.Temporality, "delta")
+	assert.Equal(t, r1.JobResourceAttributes, []JobResourceAttributesConfig{{JobName: "demo", Attributes: map[string]string{"deployment.environment": "prod"}}})
+	assert.Equal(t, r1.TargetResourceAttributes, TargetResourceAttributesConfig{Naming: "semconv", Port: true, Scheme: false})
 }
 
 func TestLoadConfigFailsOnUnknownSection(t *testing.T) {
@@ -254,3 +270,291 @@ func TestFileSDConfigYamlNilTargetGroup(t *testing.T) {
 	gotErrMsg := err.Error()
 	require.Equal(t, wantErrMsg, gotErrMsg)
 }
+
+func TestInvalidDuplicateSamples(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-duplicate-samples.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid duplicate_samples "keep_all"`)
+	assert.NotNil(t, cfg)
+}
+
+func TestInvalidMissingMetadata(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-missing-metadata.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid missing_metadata "keep"`)
+	assert.NotNil(t, cfg)
+}
+
+func TestInvalidInfoMetrics(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-info-metrics.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid info_metrics "scope"`)
+	assert.NotNil(t, cfg)
+}
+
+func TestInvalidTargetLabelsAsAttributes(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-target-labels-as-attributes.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target_labels_as_attributes cannot contain empty label names")
+	assert.NotNil(t, cfg)
+}
+
+func TestServiceDiscoveryNotEnabled(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-service-discoveries.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `service discovery "kubernetes" of scrape job "demo" is not enabled in service_discoveries`)
+	assert.NotNil(t, cfg)
+}
+
+func TestInvalidJobsCacheGCInterval(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "invalid-config-jobs-cache-gc-interval.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jobs_cache.gc_interval 5s has to be greater than the scrape interval 10s of job demo")
+	assert.NotNil(t, cfg)
+}
+
+func TestLoadConfigRemoteWrite(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config-remote-write.yaml"), factories)
+	require.NoError(t, err)
+
+	r0 := cfg.Receivers[config.NewComponentID(typeStr)].(*Config)
+	require.NotNil(t, r0.RemoteWrite)
+	assert.Equal(t, "0.0.0.0:9090", r0.RemoteWrite.Endpoint)
+	assert.Nil(t, r0.PrometheusConfig)
+}
+
+func TestValidateNegativeDrainTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DrainTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "drain_timeout has to be positive, got -1s")
+}
+
+func TestValidateRemoteWriteWithoutEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.RemoteWrite = &RemoteWriteConfig{}
+	assert.EqualError(t, cfg.Validate(), "remote_write.endpoint has to be set")
+}
+
+func TestValidateDebugWithoutEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Debug = &DebugConfig{}
+	assert.EqualError(t, cfg.Validate(), "debug.endpoint has to be set")
+}
+
+func TestLoadConfigScrapeAuthenticators(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config-scrape-authenticators.yaml"), factories)
+	require.NoError(t, err)
+
+	r0 := cfg.Receivers[config.NewComponentID(typeStr)].(*Config)
+	assert.Equal(t, []ScrapeAuthenticatorConfig{{
+		JobName:         "demo",
+		Authentication:  configauth.Authentication{AuthenticatorID: config.NewComponentID("nop")},
+		RefreshInterval: 30 * time.Second,
+	}}, r0.ScrapeAuthenticators)
+}
+
+func TestValidateJobResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		attrs   []JobResourceAttributesConfig
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			attrs: []JobResourceAttributesConfig{{JobName: "demo", Attributes: map[string]string{"deployment.environment": "prod"}}},
+		},
+		{
+			name:    "no job name",
+			attrs:   []JobResourceAttributesConfig{{Attributes: map[string]string{"deployment.environment": "prod"}}},
+			wantErr: "job_resource_attributes.job_name has to be set",
+		},
+		{
+			name:    "unknown job",
+			attrs:   []JobResourceAttributesConfig{{JobName: "other"}},
+			wantErr: `job_resource_attributes references unknown job "other"`,
+		},
+		{
+			name:    "duplicate job",
+			attrs:   []JobResourceAttributesConfig{{JobName: "demo"}, {JobName: "demo"}},
+			wantErr: `job "demo" is referenced more than once in job_resource_attributes`,
+		},
+		{
+			name:    "empty attribute name",
+			attrs:   []JobResourceAttributesConfig{{JobName: "demo", Attributes: map[string]string{"": "prod"}}},
+			wantErr: `the resource attributes of job "demo" cannot contain empty names`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.PrometheusConfig = &promconfig.Config{ScrapeConfigs: []*promconfig.ScrapeConfig{{JobName: "demo"}}}
+			cfg.JobResourceAttributes = tt.attrs
+			if tt.wantErr == "" {
+				assert.NoError(t, cfg.Validate())
+			} else {
+				assert.EqualError(t, cfg.Validate(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateScrapeAuthenticators(t *testing.T) {
+	auth := configauth.Authentication{AuthenticatorID: config.NewComponentID("oauth2client")}
+	tests := []struct {
+		name    string
+		auths   []ScrapeAuthenticatorConfig
+		client  commonconfig.HTTPClientConfig
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			auths: []ScrapeAuthenticatorConfig{{JobName: "demo", Authentication: auth}},
+		},
+		{
+			name:    "no job name",
+			auths:   []ScrapeAuthenticatorConfig{{Authentication: auth}},
+			wantErr: "scrape_authenticators.job_name has to be set",
+		},
+		{
+			name:    "unknown job",
+			auths:   []ScrapeAuthenticatorConfig{{JobName: "other", Authentication: auth}},
+			wantErr: `scrape authenticator references unknown job "other"`,
+		},
+		{
+			name:    "duplicate job",
+			auths:   []ScrapeAuthenticatorConfig{{JobName: "demo", Authentication: auth}, {JobName: "demo", Authentication: auth}},
+			wantErr: `job "demo" is referenced by more than one scrape authenticator`,
+		},
+		{
+			name:    "no authenticator",
+			auths:   []ScrapeAuthenticatorConfig{{JobName: "demo"}},
+			wantErr: `the scrape authenticator of job "demo" has no authenticator`,
+		},
+		{
+			name:    "negative refresh interval",
+			auths:   []ScrapeAuthenticatorConfig{{JobName: "demo", Authentication: auth, RefreshInterval: -time.Second}},
+			wantErr: `the refresh_interval of the scrape authenticator of job "demo" has to be positive, got -1s`,
+		},
+		{
+			name:    "job credentials",
+			auths:   []ScrapeAuthenticatorConfig{{JobName: "demo", Authentication: auth}},
+			client:  commonconfig.HTTPClientConfig{BearerToken: "token"},
+			wantErr: `job "demo" cannot set its own credentials when it has a scrape authenticator`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.PrometheusConfig = &promconfig.Config{ScrapeConfigs: []*promconfig.ScrapeConfig{{JobName: "demo", HTTPClientConfig: tt.client}}}
+			cfg.ScrapeAuthenticators = tt.auths
+			if tt.wantErr == "" {
+				assert.NoError(t, cfg.Validate())
+			} else {
+				assert.EqualError(t, cfg.Validate(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLabelValueLimit(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LabelValueLimit.MaxLength = -1
+	assert.EqualError(t, cfg.Validate(), "label_value_limit.max_length has to be positive, got -1")
+
+	cfg.LabelValueLimit = LabelValueLimitConfig{MaxLength: 256, Action: "reject"}
+	assert.EqualError(t, cfg.Validate(), `invalid label_value_limit.action "reject": can be either "truncate" or "drop"`)
+}
+
+func TestValidateMissingMetadata(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, "gauge", cfg.MissingMetadata)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MissingMetadata = "gauge_unknown_targets"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MissingMetadata = ""
+	assert.EqualError(t, cfg.Validate(), `invalid missing_metadata "": can be either "gauge", "gauge_unknown_targets" or "drop"`)
+}
+
+func TestValidateSampleAge(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SampleAge.MaxAge = -time.Minute
+	assert.EqualError(t, cfg.Validate(), "sample_age.max_age has to be positive, got -1m0s")
+
+	cfg.SampleAge = SampleAgeConfig{MaxFuture: -time.Minute, Action: "drop"}
+	assert.EqualError(t, cfg.Validate(), "sample_age.max_future has to be positive, got -1m0s")
+
+	cfg.SampleAge = SampleAgeConfig{MaxAge: time.Hour, Action: "reject"}
+	assert.EqualError(t, cfg.Validate(), `invalid sample_age.action "reject": can be either "drop" or "clamp"`)
+}
+
+func TestValidateTargetHealth(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TargetHealth.MaxDownRatio = -0.1
+	assert.EqualError(t, cfg.Validate(), "target_health.max_down_ratio has to be between 0 and 1, got -0.1")
+
+	cfg.TargetHealth.MaxDownRatio = 1.5
+	assert.EqualError(t, cfg.Validate(), "target_health.max_down_ratio has to be between 0 and 1, got 1.5")
+
+	cfg.TargetHealth.MaxDownRatio = 1
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateTemporality(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Temporality = "delta"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Temporality = "gauge"
+	assert.EqualError(t, cfg.Validate(), `invalid temporality "gauge": can be either "cumulative" or "delta"`)
+}
+
+func TestValidateTargetResourceAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TargetResourceAttributes.Naming = "semconv"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TargetResourceAttributes.Naming = "otel"
+	assert.EqualError(t, cfg.Validate(), `invalid target_resource_attributes.naming "otel": can be either "legacy" or "semconv"`)
+}
@@ -0,0 +1,255 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+const (
+	debugTargetsPath     = "/targets"
+	debugAPITargetsPath  = "/api/v1/targets"
+	debugAPIMetadataPath = "/api/v1/targets/metadata"
+)
+
+// targetsProvider provides the targets of the scrape manager.
+type targetsProvider interface {
+	TargetsActive() map[string][]*scrape.Target
+	TargetsDropped() map[string][]*scrape.Target
+}
+
+// apiResponse is the envelope of the responses of the Prometheus HTTP API.
+type apiResponse struct {
+	Status string      `json:"status"`
+	Data   interface{} `json:"data"`
+}
+
+// targetsData mirrors the data of the /api/v1/targets endpoint of Prometheus.
+type targetsData struct {
+	ActiveTargets  []activeTarget  `json:"activeTargets"`
+	DroppedTargets []droppedTarget `json:"droppedTargets"`
+}
+
+type activeTarget struct {
+	DiscoveredLabels   map[string]string   `json:"discoveredLabels"`
+	Labels             map[string]string   `json:"labels"`
+	ScrapePool         string              `json:"scrapePool"`
+	ScrapeURL          string              `json:"scrapeUrl"`
+	LastError          string              `json:"lastError"`
+	LastScrape         time.Time           `json:"lastScrape"`
+	LastScrapeDuration float64             `json:"lastScrapeDuration"`
+	Health             scrape.TargetHealth `json:"health"`
+}
+
+type droppedTarget struct {
+	DiscoveredLabels map[string]string `json:"discoveredLabels"`
+}
+
+// targetMetadata mirrors the items of the data of the /api/v1/targets/metadata endpoint of Prometheus.
+type targetMetadata struct {
+	Target map[string]string `json:"target"`
+	Metric string            `json:"metric"`
+	Type   string            `json:"type"`
+	Help   string            `json:"help"`
+	Unit   string            `json:"unit"`
+}
+
+// newDebugHandler returns the handler of the pages and API endpoints exposing the targets of the
+// scrape manager, their last scrape and the metadata of their metrics.
+func newDebugHandler(targets targetsProvider, logger *zap.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(debugTargetsPath, func(w http.ResponseWriter, r *http.Request) {
+		pools := targetPools(activeTargets(targets, r.URL.Query().Get("job")))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := targetsPage.Execute(w, pools); err != nil {
+			logger.Debug("Failed to write the targets page", zap.Error(err))
+		}
+	})
+	mux.HandleFunc(debugAPITargetsPath, func(w http.ResponseWriter, r *http.Request) {
+		job := r.URL.Query().Get("job")
+		data := targetsData{ActiveTargets: activeTargets(targets, job), DroppedTargets: []droppedTarget{}}
+		for _, target := range sortedTargets(targets.TargetsDropped(), job) {
+			data.DroppedTargets = append(data.DroppedTargets, droppedTarget{DiscoveredLabels: target.DiscoveredLabels().Map()})
+		}
+		writeAPIResponse(w, data, logger)
+	})
+	mux.HandleFunc(debugAPIMetadataPath, func(w http.ResponseWriter, r *http.Request) {
+		metric := r.URL.Query().Get("metric")
+		metadata := []targetMetadata{}
+		for _, target := range sortedTargets(targets.TargetsActive(), r.URL.Query().Get("job")) {
+			for _, md := range target.MetadataList() {
+				if metric != "" && md.Metric != metric {
+					continue
+				}
+				metadata = append(metadata, targetMetadata{
+					Target: target.Labels().Map(),
+					Metric: md.Metric,
+					Type:   string(md.Type),
+					Help:   md.Help,
+					Unit:   md.Unit,
+				})
+			}
+		}
+		writeAPIResponse(w, metadata, logger)
+	})
+	return mux
+}
+
+func writeAPIResponse(w http.ResponseWriter, data interface{}, logger *zap.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(apiResponse{Status: "success", Data: data}); err != nil {
+		logger.Debug("Failed to write the API response", zap.Error(err))
+	}
+}
+
+// poolNames returns the sorted names of the scrape pools, i.e. the jobs, or only job if it is set.
+func poolNames(targets map[string][]*scrape.Target, job string) []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		if job == "" || name == job {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedTargets returns the targets of the pools returned by poolNames, sorted by pool and labels.
+func sortedTargets(targets map[string][]*scrape.Target, job string) []*scrape.Target {
+	var sorted []*scrape.Target
+	for _, name := range poolNames(targets, job) {
+		poolTargets := append([]*scrape.Target(nil), targets[name]...)
+		sort.Slice(poolTargets, func(i, j int) bool {
+			return labels.Compare(poolTargets[i].Labels(), poolTargets[j].Labels()) < 0
+		})
+		sorted = append(sorted, poolTargets...)
+	}
+	return sorted
+}
+
+func activeTargets(targets targetsProvider, job string) []activeTarget {
+	active := []activeTarget{}
+	byPool := targets.TargetsActive()
+	for _, pool := range poolNames(byPool, job) {
+		for _, target := range sortedTargets(byPool, pool) {
+			at := activeTarget{
+				DiscoveredLabels:   target.DiscoveredLabels().Map(),
+				Labels:             target.Labels().Map(),
+				ScrapePool:         pool,
+				ScrapeURL:          target.URL().String(),
+				LastScrape:         target.LastScrape(),
+				LastScrapeDuration: target.LastScrapeDuration().Seconds(),
+				Health:             target.Health(),
+			}
+			if err := target.LastError(); err != nil {
+				at.LastError = err.Error()
+			}
+			active = append(active, at)
+		}
+	}
+	return active
+}
+
+// targetPool is a job of the targets page.
+type targetPool struct {
+	Name    string
+	Up      int
+	Targets []activeTarget
+}
+
+func targetPools(targets []activeTarget) []targetPool {
+	var pools []targetPool
+	for _, target := range targets {
+		if len(pools) == 0 || pools[len(pools)-1].Name != target.ScrapePool {
+			pools = append(pools, targetPool{Name: target.ScrapePool})
+		}
+		pool := &pools[len(pools)-1]
+		pool.Targets = append(pool.Targets, target)
+		if target.Health == scrape.HealthGood {
+			pool.Up++
+		}
+	}
+	return pools
+}
+
+var targetsPage = template.Must(template.New("targets").Funcs(template.FuncMap{
+	"since": func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return time.Since(t).Truncate(time.Millisecond).String() + " ago"
+	},
+	"seconds": func(s float64) string {
+		return time.Duration(s * float64(time.Second)).Truncate(time.Microsecond).String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>Targets</title></head>
+<body>
+<h1>Targets</h1>
+{{range .}}
+<h2 id="{{.Name}}">{{.Name}} ({{.Up}}/{{len .Targets}} up)</h2>
+<table border="1" cellpadding="4">
+<tr><th>Endpoint</th><th>State</th><th>Labels</th><th>Last Scrape</th><th>Scrape Duration</th><th>Error</th></tr>
+{{range .Targets}}<tr>
+<td><a href="{{.ScrapeURL}}">{{.ScrapeURL}}</a></td>
+<td>{{.Health}}</td>
+<td>{{range $name, $value := .Labels}}{{$name}}="{{$value}}" {{end}}</td>
+<td>{{since .LastScrape}}</td>
+<td>{{seconds .LastScrapeDuration}}</td>
+<td>{{.LastError}}</td>
+</tr>
+{{end}}</table>
+{{else}}
+<p>No active targets.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// startDebug starts the server exposing the scrape targets.
+func (r *pReceiver) startDebug(host component.Host) error {
+	ln, err := r.cfg.Debug.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.cfg.Debug.Endpoint, err)
+	}
+
+	r.debugServer, err = r.cfg.Debug.ToServer(host, r.settings.TelemetrySettings, newDebugHandler(r.scrapeManager, r.settings.Logger))
+	if err != nil {
+		_ = ln.Close()
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if errHTTP := r.debugServer.Serve(ln); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			r.settings.Logger.Error("Debug server failed", zap.Error(errHTTP))
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+type fakeTargetsProvider struct {
+	active  map[string][]*scrape.Target
+	dropped map[string][]*scrape.Target
+}
+
+func (p *fakeTargetsProvider) TargetsActive() map[string][]*scrape.Target {
+	return p.active
+}
+
+func (p *fakeTargetsProvider) TargetsDropped() map[string][]*scrape.Target {
+	return p.dropped
+}
+
+// fakeMetadataStore is the metadata cache of a target.
+type fakeMetadataStore []scrape.MetricMetadata
+
+func (s fakeMetadataStore) ListMetadata() []scrape.MetricMetadata { return s }
+
+func (s fakeMetadataStore) GetMetadata(metric string) (scrape.MetricMetadata, bool) {
+	for _, md := range s {
+		if md.Metric == metric {
+			return md, true
+		}
+	}
+	return scrape.MetricMetadata{}, false
+}
+
+func (s fakeMetadataStore) SizeMetadata() int { return 0 }
+
+func (s fakeMetadataStore) LengthMetadata() int { return len(s) }
+
+func newDebugTarget(job, instance string, err error, metadata ...scrape.MetricMetadata) *scrape.Target {
+	target := scrape.NewTarget(
+		labels.FromStrings(model.JobLabel, job, model.InstanceLabel, instance, model.SchemeLabel, "http", model.AddressLabel, instance, model.MetricsPathLabel, "/metrics"),
+		labels.FromStrings(model.AddressLabel, instance),
+		nil)
+	target.Report(time.Now(), 42*time.Millisecond, err)
+	target.SetMetadataStore(fakeMetadataStore(metadata))
+	return target
+}
+
+func newTestDebugHandler() http.Handler {
+	return newDebugHandler(&fakeTargetsProvider{
+		active: map[string][]*scrape.Target{
+			"node": {
+				newDebugTarget("node", "b:9100", errors.New("connection refused")),
+				newDebugTarget("node", "a:9100", nil,
+					scrape.MetricMetadata{Metric: "node_cpu_seconds_total", Type: textparse.MetricTypeCounter, Help: "Seconds the CPUs spent in each mode."}),
+			},
+			"app": {
+				newDebugTarget("app", "a:8080", nil,
+					scrape.MetricMetadata{Metric: "http_requests_total", Type: textparse.MetricTypeCounter, Help: "Requests."},
+					scrape.MetricMetadata{Metric: "http_request_duration_seconds", Type: textparse.MetricTypeHistogram, Unit: "seconds"}),
+			},
+		},
+		dropped: map[string][]*scrape.Target{
+			"node": {scrape.NewTarget(nil, labels.FromStrings(model.AddressLabel, "c:9100"), nil)},
+		},
+	}, zap.NewNop())
+}
+
+func getDebug(t *testing.T, handler http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec
+}
+
+func TestDebugAPITargets(t *testing.T) {
+	handler := newTestDebugHandler()
+
+	var resp struct {
+		Status string      `json:"status"`
+		Data   targetsData `json:"data"`
+	}
+	rec := getDebug(t, handler, debugAPITargetsPath)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	require.Len(t, resp.Data.ActiveTargets, 3)
+	var instances, pools, health []string
+	for _, target := range resp.Data.ActiveTargets {
+		instances = append(instances, target.Labels[model.InstanceLabel])
+		pools = append(pools, target.ScrapePool)
+		health = append(health, string(target.Health))
+	}
+	assert.Equal(t, []string{"a:8080", "a:9100", "b:9100"}, instances)
+	assert.Equal(t, []string{"app", "node", "node"}, pools)
+	assert.Equal(t, []string{"up", "up", "down"}, health)
+	assert.Equal(t, "connection refused", resp.Data.ActiveTargets[2].LastError)
+	assert.Equal(t, "http://b:9100/metrics", resp.Data.ActiveTargets[2].ScrapeURL)
+	assert.Equal(t, 0.042, resp.Data.ActiveTargets[2].LastScrapeDuration)
+	assert.Equal(t, map[string]string{model.AddressLabel: "b:9100"}, resp.Data.ActiveTargets[2].DiscoveredLabels)
+	assert.Equal(t, []droppedTarget{{DiscoveredLabels: map[string]string{model.AddressLabel: "c:9100"}}}, resp.Data.DroppedTargets)
+
+	rec = getDebug(t, handler, debugAPITargetsPath+"?job=app")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.ActiveTargets, 1)
+	assert.Equal(t, "app", resp.Data.ActiveTargets[0].ScrapePool)
+	assert.Empty(t, resp.Data.DroppedTargets)
+}
+
+func TestDebugAPIMetadata(t *testing.T) {
+	handler := newTestDebugHandler()
+
+	var resp struct {
+		Status string           `json:"status"`
+		Data   []targetMetadata `json:"data"`
+	}
+	rec := getDebug(t, handler, debugAPIMetadataPath)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	var metrics []string
+	for _, md := range resp.Data {
+		metrics = append(metrics, md.Metric)
+	}
+	assert.Equal(t, []string{"http_requests_total", "http_request_duration_seconds", "node_cpu_seconds_total"}, metrics)
+	assert.Equal(t, "histogram", resp.Data[1].Type)
+	assert.Equal(t, "seconds", resp.Data[1].Unit)
+	assert.Equal(t, "a:8080", resp.Data[1].Target[model.InstanceLabel])
+
+	rec = getDebug(t, handler, debugAPIMetadataPath+"?job=node&metric=node_cpu_seconds_total")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []targetMetadata{{
+		Target: map[string]string{model.JobLabel: "node", model.InstanceLabel: "a:9100"},
+		Metric: "node_cpu_seconds_total",
+		Type:   "counter",
+		Help:   "Seconds the CPUs spent in each mode.",
+	}}, resp.Data)
+
+	rec = getDebug(t, handler, debugAPIMetadataPath+"?metric=unknown")
+	assert.JSONEq(t, `{"status":"success","data":[]}`, rec.Body.String())
+}
+
+func TestDebugTargetsPage(t *testing.T) {
+	rec := getDebug(t, newTestDebugHandler(), debugTargetsPath)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	page := rec.Body.String()
+	assert.Contains(t, page, "app (1/1 up)")
+	assert.Contains(t, page, "node (1/2 up)")
+	assert.Contains(t, page, `<a href="http://b:9100/metrics">http://b:9100/metrics</a>`)
+	assert.Contains(t, page, "connection refused")
+	assert.Contains(t, page, "42ms")
+
+	empty := newDebugHandler(&fakeTargetsProvider{}, zap.NewNop())
+	assert.Contains(t, getDebug(t, empty, debugTargetsPath).Body.String(), "No active targets.")
+}
+
+func TestDebugServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	endpoint := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Debug = &DebugConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: endpoint}}
+	require.NoError(t, cfg.Validate())
+
+	receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, receiver.Shutdown(context.Background())) })
+
+	resp, err := http.Get("http://" + endpoint + debugAPITargetsPath)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"status":"success","data":{"activeTargets":[],"droppedTargets":[]}}`, string(body))
+}
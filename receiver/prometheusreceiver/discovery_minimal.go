@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build prometheus_minimal_sd
+// +build prometheus_minimal_sd
+
+package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+
+// Only the static, file, kubernetes and ec2 service discoveries are compiled in, the
+// dependencies of the other mechanisms make up a large part of the binary size.
+import (
+	_ "github.com/prometheus/prometheus/discovery/aws"        // register ec2 and lightsail
+	_ "github.com/prometheus/prometheus/discovery/file"       // register file
+	_ "github.com/prometheus/prometheus/discovery/kubernetes" // register kubernetes
+)
@@ -18,12 +18,14 @@ import (
 	"context"
 	"errors"
 
-	_ "github.com/prometheus/prometheus/discovery/install" // init() of this package registers service discovery impl.
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.opentelemetry.io/collector/service/featuregate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 )
 
 // This file implements config for Prometheus receiver.
@@ -36,6 +38,7 @@ var errRenamingDisallowed = errors.New("metric renaming using metric_relabel_con
 
 // NewFactory creates a new Prometheus receiver factory.
 func NewFactory() component.ReceiverFactory {
+	_ = view.Register(internal.MetricViews()...)
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
@@ -45,7 +48,19 @@ func NewFactory() component.ReceiverFactory {
 func createDefaultConfig() config.Receiver {
 	return &Config{
 		ReceiverSettings: config.NewReceiverSettings(config.NewComponentID(typeStr)),
-		pdataDirect:      featuregate.IsEnabled(pdataPipelineGate.ID),
+		DuplicateSamples: internal.DuplicateSamplesKeepLast,
+		MissingMetadata:  internal.MissingMetadataGauge,
+		InfoMetrics:      internal.InfoMetricsGauge,
+		LabelValueLimit:  LabelValueLimitConfig{Action: internal.LabelValueLimitDrop},
+		SampleAge:        SampleAgeConfig{Action: internal.SampleAgeDrop},
+		TargetHealth:     TargetHealthConfig{MaxDownRatio: defaultMaxDownRatio},
+		Temporality:      internal.TemporalityCumulative,
+		TargetResourceAttributes: TargetResourceAttributesConfig{
+			Naming: internal.ResourceAttributesNamingLegacy,
+			Port:   true,
+			Scheme: true,
+		},
+		pdataDirect: featuregate.IsEnabled(pdataPipelineGate.ID),
 	}
 }
 
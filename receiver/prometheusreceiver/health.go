@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+
+import (
+	"sort"
+
+	"github.com/prometheus/prometheus/scrape"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+)
+
+// defaultMaxDownRatio is the default maximum ratio of the scraped targets of a job which can be down
+// for the job to be healthy.
+const defaultMaxDownRatio = 0.5
+
+// healthCheckRegistry is implemented by the extensions the receiver reports the health of its
+// targets to, e.g. the health_check extension.
+type healthCheckRegistry interface {
+	RegisterHealthCheck(id config.ComponentID, check func() (bool, interface{})) func()
+}
+
+// scrapeHealthDetails are the details of the health of the receiver.
+type scrapeHealthDetails struct {
+	Jobs []jobHealth `json:"jobs"`
+}
+
+// jobHealth summarizes the health of the active targets of a job.
+type jobHealth struct {
+	Job       string `json:"job"`
+	Healthy   bool   `json:"healthy"`
+	Up        int    `json:"up"`
+	Down      int    `json:"down"`
+	Unknown   int    `json:"unknown"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// registerHealthChecks registers the health of the scraped targets with the extensions supporting it.
+func (r *pReceiver) registerHealthChecks(host component.Host) {
+	for _, ext := range host.GetExtensions() {
+		if registry, ok := ext.(healthCheckRegistry); ok {
+			r.unregisterHealthChecks = append(r.unregisterHealthChecks, registry.RegisterHealthCheck(r.cfg.ID(), r.checkHealth))
+		}
+	}
+}
+
+// checkHealth reports the receiver as healthy unless the ratio of the scraped targets of a job which
+// are down exceeds target_health.max_down_ratio.
+func (r *pReceiver) checkHealth() (bool, interface{}) {
+	healthy, jobs := scrapeHealth(r.scrapeManager.TargetsActive(), r.cfg.TargetHealth.MaxDownRatio)
+	return healthy, scrapeHealthDetails{Jobs: jobs}
+}
+
+// scrapeHealth summarizes the health of the targets of each job, sorted by job name. A job is unhealthy
+// when more than maxDownRatio of its scraped targets are down, targets which haven't been scraped yet
+// have an unknown health and aren't counted.
+func scrapeHealth(targets map[string][]*scrape.Target, maxDownRatio float64) (bool, []jobHealth) {
+	healthy := true
+	jobs := make([]jobHealth, 0, len(targets))
+	for job, jobTargets := range targets {
+		jh := jobHealth{Job: job}
+		for _, target := range jobTargets {
+			switch target.Health() {
+			case scrape.HealthGood:
+				jh.Up++
+			case scrape.HealthBad:
+				jh.Down++
+				if err := target.LastError(); err != nil {
+					jh.LastError = err.Error()
+				}
+			default:
+				jh.Unknown++
+			}
+		}
+		jh.Healthy = jh.Down == 0 || float64(jh.Down)/float64(jh.Up+jh.Down) <= maxDownRatio
+		healthy = healthy && jh.Healthy
+		jobs = append(jobs, jh)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Job < jobs[j].Job })
+	return healthy, jobs
+}
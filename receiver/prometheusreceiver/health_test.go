@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func newHealthTarget(instance string, err error, scraped bool) *scrape.Target {
+	target := scrape.NewTarget(labels.FromStrings(model.InstanceLabel, instance), nil, nil)
+	if scraped {
+		target.Report(time.Now(), time.Second, err)
+	}
+	return target
+}
+
+func TestScrapeHealth(t *testing.T) {
+	healthy, jobs := scrapeHealth(nil, defaultMaxDownRatio)
+	assert.True(t, healthy)
+	assert.Empty(t, jobs)
+
+	targets := map[string][]*scrape.Target{
+		"node": {
+			newHealthTarget("a:9100", nil, true),
+			newHealthTarget("b:9100", nil, false),
+		},
+		"app": {
+			newHealthTarget("a:8080", nil, true),
+			newHealthTarget("b:8080", nil, true),
+			newHealthTarget("c:8080", errors.New("connection refused"), true),
+		},
+		"db": {
+			newHealthTarget("a:5432", errors.New("timeout"), true),
+			newHealthTarget("b:5432", nil, false),
+		},
+	}
+	healthy, jobs = scrapeHealth(targets, defaultMaxDownRatio)
+	assert.False(t, healthy)
+	assert.Equal(t, []jobHealth{
+		{Job: "app", Healthy: true, Up: 2, Down: 1, LastError: "connection refused"},
+		{Job: "db", Healthy: false, Down: 1, Unknown: 1, LastError: "timeout"},
+		{Job: "node", Healthy: true, Up: 1, Unknown: 1},
+	}, jobs)
+
+	// A single target down makes the job unhealthy.
+	healthy, jobs = scrapeHealth(targets, 0)
+	assert.False(t, healthy)
+	assert.False(t, jobs[0].Healthy)
+
+	// No target down makes the job unhealthy.
+	healthy, _ = scrapeHealth(targets, 1)
+	assert.True(t, healthy)
+}
+
+type healthCheckHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h healthCheckHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+type fakeHealthCheckExtension struct {
+	component.Extension
+	checks map[config.ComponentID]func() (bool, interface{})
+}
+
+func (e *fakeHealthCheckExtension) RegisterHealthCheck(id config.ComponentID, check func() (bool, interface{})) func() {
+	e.checks[id] = check
+	return func() { delete(e.checks, id) }
+}
+
+func TestRegisterHealthChecks(t *testing.T) {
+	ext := &fakeHealthCheckExtension{
+		checks: map[config.ComponentID]func() (bool, interface{}){},
+	}
+	host := healthCheckHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{
+			config.NewComponentID("health_check"): ext,
+		},
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, receiver.Start(context.Background(), host))
+
+	check, ok := ext.checks[cfg.ID()]
+	require.True(t, ok)
+	healthy, details := check()
+	assert.True(t, healthy)
+	assert.Equal(t, scrapeHealthDetails{Jobs: []jobHealth{}}, details)
+
+	require.NoError(t, receiver.Shutdown(context.Background()))
+	assert.Empty(t, ext.checks)
+}
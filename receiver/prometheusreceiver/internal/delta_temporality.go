@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// Aggregation temporalities of the counters and histograms emitted by the receiver.
+const (
+	// TemporalityCumulative keeps the cumulative values scraped from the targets.
+	TemporalityCumulative = "cumulative"
+	// TemporalityDelta emits the difference between the values of consecutive scrapes.
+	TemporalityDelta = "delta"
+)
+
+// deltaPoint is the previous cumulative point of a timeseries.
+type deltaPoint struct {
+	// lastSeen is the time the timeseries was last consumed, used to evict the timeseries of
+	// stopped targets.
+	lastSeen  time.Time
+	timestamp pdata.Timestamp
+	values    pointValuesPdata
+}
+
+// deltaConsumer converts the cumulative monotonic sums and histograms consumed to delta
+// temporality before passing them to the next consumer. The first point of a timeseries is
+// only recorded, as no delta can be computed from it.
+type deltaConsumer struct {
+	next       consumer.Metrics
+	gcInterval time.Duration
+
+	sync.Mutex
+	points map[string]*deltaPoint
+	lastGC time.Time
+}
+
+var _ consumer.Metrics = (*deltaConsumer)(nil)
+
+// NewDeltaConsumer returns a consumer converting the cumulative metrics to delta temporality
+// before passing them to next. The timeseries not consumed for gcInterval are forgotten.
+func NewDeltaConsumer(next consumer.Metrics, gcInterval time.Duration) consumer.Metrics {
+	return &deltaConsumer{
+		next:       next,
+		gcInterval: gcInterval,
+		points:     map[string]*deltaPoint{},
+		lastGC:     time.Now(),
+	}
+}
+
+func (c *deltaConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+func (c *deltaConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	c.convert(md)
+	if md.MetricCount() == 0 {
+		return nil
+	}
+	return c.next.ConsumeMetrics(ctx, md)
+}
+
+// convert converts the cumulative metrics of md to delta temporality and removes the metrics
+// left without data points.
+func (c *deltaConsumer) convert(md pdata.Metrics) {
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resource := attributesSignature(rm.Resource().Attributes())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilms.At(j).Metrics().RemoveIf(func(metric pdata.Metric) bool {
+				prefix := resource + "/" + metric.Name() + "/"
+				switch metric.DataType() {
+				case pdata.MetricDataTypeSum:
+					sum := metric.Sum()
+					if !sum.IsMonotonic() || sum.AggregationTemporality() != pdata.MetricAggregationTemporalityCumulative {
+						return false
+					}
+					c.convertSum(now, prefix, sum)
+					return sum.DataPoints().Len() == 0
+				case pdata.MetricDataTypeHistogram:
+					histogram := metric.Histogram()
+					if histogram.AggregationTemporality() != pdata.MetricAggregationTemporalityCumulative {
+						return false
+					}
+					c.convertHistogram(now, prefix, histogram)
+					return histogram.DataPoints().Len() == 0
+				}
+				return false
+			})
+		}
+	}
+	if now.Sub(c.lastGC) > c.gcInterval {
+		c.gc(now)
+	}
+}
+
+func (c *deltaConsumer) convertSum(now time.Time, prefix string, sum pdata.Sum) {
+	sum.SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	sum.DataPoints().RemoveIf(func(point pdata.NumberDataPoint) bool {
+		current := pointValuesPdata{value: point.DoubleVal()}
+		previous, reset := c.update(now, prefix+attributesSignature(point.Attributes()), point, current, sumResetReason)
+		if previous == nil {
+			return true
+		}
+		if reset {
+			return false
+		}
+		point.SetStartTimestamp(previous.timestamp)
+		point.SetDoubleVal(current.value - previous.values.value)
+		return false
+	})
+}
+
+func (c *deltaConsumer) convertHistogram(now time.Time, prefix string, histogram pdata.Histogram) {
+	histogram.SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	histogram.DataPoints().RemoveIf(func(point pdata.HistogramDataPoint) bool {
+		current := pointValuesPdata{
+			count:        point.Count(),
+			sum:          point.Sum(),
+			bounds:       point.ExplicitBounds(),
+			bucketCounts: point.BucketCounts(),
+		}
+		previous, reset := c.update(now, prefix+attributesSignature(point.Attributes()), point, current, histogramResetReason)
+		if previous == nil {
+			return true
+		}
+		if reset {
+			return false
+		}
+		point.SetStartTimestamp(previous.timestamp)
+		point.SetCount(point.Count() - previous.values.count)
+		point.SetSum(point.Sum() - previous.values.sum)
+		bucketCounts := make([]uint64, len(current.bucketCounts))
+		for i, count := range current.bucketCounts {
+			bucketCounts[i] = count - previous.values.bucketCounts[i]
+		}
+		point.SetBucketCounts(bucketCounts)
+		return false
+	})
+}
+
+// deltaPointPdata is implemented by the points converted to delta temporality.
+type deltaPointPdata interface {
+	Timestamp() pdata.Timestamp
+	Flags() pdata.MetricDataPointFlags
+}
+
+// update records the current values of the point of the timeseries sig, and returns a copy of
+// the previous point of the timeseries, or nil if the point has to be dropped: when it's the
+// initial point of the timeseries or a staleness marker, which ends the timeseries. reset is
+// true when the timeseries was reset since the previous point, in which case the current
+// values are the delta since the reset.
+func (c *deltaConsumer) update(now time.Time, sig string, point deltaPointPdata, current pointValuesPdata,
+	resetReason func(previous, current *pointValuesPdata) string) (previous *deltaPoint, reset bool) {
+	if point.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
+		delete(c.points, sig)
+		return nil, false
+	}
+	dp, ok := c.points[sig]
+	if !ok {
+		dp = &deltaPoint{}
+		c.points[sig] = dp
+	} else {
+		previous = &deltaPoint{timestamp: dp.timestamp}
+		previous.values.copyFrom(dp.values)
+		reset = resetReason(&previous.values, &current) != ""
+	}
+	dp.lastSeen = now
+	dp.timestamp = point.Timestamp()
+	dp.values.copyFrom(current)
+	return previous, reset
+}
+
+// gc removes the timeseries which weren't consumed since the previous garbage collection, the
+// caller has to hold the lock.
+func (c *deltaConsumer) gc(now time.Time) {
+	for sig, dp := range c.points {
+		if dp.lastSeen.Before(c.lastGC) {
+			delete(c.points, sig)
+		}
+	}
+	c.lastGC = now
+}
+
+// attributesSignature returns a signature of the names and values of attrs. They are quoted, so
+// that the separators they contain can't make distinct attributes have the same signature.
+func attributesSignature(attrs pdata.AttributeMap) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(name string, value pdata.AttributeValue) bool {
+		pairs = append(pairs, strconv.Quote(name)+"="+strconv.Quote(value.AsString()))
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// scrapeMetrics returns the metrics of a scrape of the target instance at ts, holding a
+// counter of value counter, a histogram of count and bucketCounts, and a gauge.
+func scrapeMetrics(instance string, ts int64, counter float64, count uint64, bucketCounts []uint64) pdata.Metrics {
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("instance", instance)
+	metrics := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests_total")
+	sum.SetDataType(pdata.MetricDataTypeSum)
+	sum.Sum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	sdp := sum.Sum().DataPoints().AppendEmpty()
+	sdp.Attributes().InsertString("method", "GET")
+	sdp.SetStartTimestamp(pdata.Timestamp(1))
+	sdp.SetTimestamp(pdata.Timestamp(ts))
+	sdp.SetDoubleVal(counter)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetDataType(pdata.MetricDataTypeHistogram)
+	histogram.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	hdp := histogram.Histogram().DataPoints().AppendEmpty()
+	hdp.SetStartTimestamp(pdata.Timestamp(1))
+	hdp.SetTimestamp(pdata.Timestamp(ts))
+	hdp.SetCount(count)
+	hdp.SetSum(float64(count))
+	hdp.SetExplicitBounds([]float64{1})
+	hdp.SetBucketCounts(bucketCounts)
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("up")
+	gauge.SetDataType(pdata.MetricDataTypeGauge)
+	gauge.Gauge().DataPoints().AppendEmpty().SetDoubleVal(1)
+	return md
+}
+
+func TestDeltaConsumer(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	c := NewDeltaConsumer(sink, time.Hour)
+
+	// the initial points are dropped.
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 10, 5, 3, []uint64{1, 2})))
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 20, 12, 7, []uint64{2, 5})))
+	// a distinct target doesn't use the points of the first one.
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("b", 20, 100, 50, []uint64{20, 30})))
+	// the counters of the first target are reset.
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 30, 2, 1, []uint64{1, 0})))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 4)
+
+	first := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, first.Len())
+	assert.Equal(t, "up", first.At(0).Name())
+	other := mds[2].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, other.Len())
+	assert.Equal(t, "up", other.At(0).Name())
+
+	delta := mds[1].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, delta.Len())
+	assert.Equal(t, pdata.MetricAggregationTemporalityDelta, delta.At(0).Sum().AggregationTemporality())
+	sdp := delta.At(0).Sum().DataPoints().At(0)
+	assert.Equal(t, pdata.Timestamp(10), sdp.StartTimestamp())
+	assert.Equal(t, pdata.Timestamp(20), sdp.Timestamp())
+	assert.Equal(t, 7.0, sdp.DoubleVal())
+	assert.Equal(t, pdata.MetricAggregationTemporalityDelta, delta.At(1).Histogram().AggregationTemporality())
+	hdp := delta.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, pdata.Timestamp(10), hdp.StartTimestamp())
+	assert.Equal(t, uint64(4), hdp.Count())
+	assert.Equal(t, 4.0, hdp.Sum())
+	assert.Equal(t, []uint64{1, 3}, hdp.BucketCounts())
+
+	reset := mds[3].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, reset.Len())
+	sdp = reset.At(0).Sum().DataPoints().At(0)
+	assert.Equal(t, pdata.Timestamp(1), sdp.StartTimestamp())
+	assert.Equal(t, 2.0, sdp.DoubleVal())
+	hdp = reset.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, pdata.Timestamp(1), hdp.StartTimestamp())
+	assert.Equal(t, uint64(1), hdp.Count())
+	assert.Equal(t, []uint64{1, 0}, hdp.BucketCounts())
+}
+
+func TestDeltaConsumerStaleness(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	c := NewDeltaConsumer(sink, time.Hour)
+
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 10, 5, 3, []uint64{1, 2})))
+	stale := scrapeMetrics("a", 20, 0, 5, []uint64{2, 3})
+	metrics := stale.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.At(0).Sum().DataPoints().At(0).SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+	require.NoError(t, c.ConsumeMetrics(context.Background(), stale))
+	// the timeseries ended by the staleness marker starts again.
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 30, 8, 5, []uint64{2, 3})))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 3)
+	metrics = mds[1].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "latency", metrics.At(0).Name())
+	metrics = mds[2].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "latency", metrics.At(0).Name())
+}
+
+func TestDeltaConsumerGC(t *testing.T) {
+	c := NewDeltaConsumer(consumertest.NewNop(), time.Millisecond).(*deltaConsumer)
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("a", 10, 5, 3, []uint64{1, 2})))
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("b", 10, 5, 3, []uint64{1, 2})))
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, c.ConsumeMetrics(context.Background(), scrapeMetrics("b", 20, 6, 4, []uint64{1, 3})))
+	assert.Len(t, c.points, 2)
+	for sig := range c.points {
+		assert.Contains(t, sig, `"instance"="b"`)
+	}
+}
+
+func TestAttributesSignature(t *testing.T) {
+	joined := pdata.NewAttributeMap()
+	joined.InsertString("a", "1,b=2")
+	split := pdata.NewAttributeMap()
+	split.InsertString("a", "1")
+	split.InsertString("b", "2")
+	assert.NotEqual(t, attributesSignature(joined), attributesSignature(split))
+
+	reordered := pdata.NewAttributeMap()
+	reordered.InsertString("b", "2")
+	reordered.InsertString("a", "1")
+	assert.Equal(t, attributesSignature(split), attributesSignature(reordered))
+}
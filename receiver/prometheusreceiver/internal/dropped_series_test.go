@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+// droppedSeries returns the number of dropped series recorded by reason.
+func droppedSeries(t *testing.T) map[string]float64 {
+	rows, err := view.RetrieveData(statDroppedSeries.Name())
+	require.NoError(t, err)
+	dropped := map[string]float64{}
+	for _, row := range rows {
+		var reason string
+		for _, tag := range row.Tags {
+			switch tag.Key {
+			case tagReasonKey:
+				reason = tag.Value
+			case tagJobKey:
+				assert.Equal(t, "test", tag.Value)
+			case tagReceiverKey:
+				assert.Equal(t, "prometheus", tag.Value)
+			}
+		}
+		dropped[reason] += row.Data.(*view.SumData).Value
+	}
+	return dropped
+}
+
+func TestDroppedSeries(t *testing.T) {
+	rID := config.NewComponentID("prometheus")
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"counter":                    {Metric: "counter", Type: textparse.MetricTypeCounter},
+		"hist":                       {Metric: "hist", Type: textparse.MetricTypeHistogram},
+		"process_start_time_seconds": {Metric: "process_start_time_seconds", Type: textparse.MetricTypeGauge},
+	})}
+	target := []string{model.JobLabel, "test", model.InstanceLabel, "localhost:8080"}
+	series := func(name string, pairs ...string) labels.Labels {
+		return labels.FromStrings(append(append([]string{model.MetricNameLabel, name}, target...), pairs...)...)
+	}
+	staleNaN := math.Float64frombits(value.StaleNaN)
+
+	type sample struct {
+		ls labels.Labels
+		v  float64
+	}
+	tests := []struct {
+		name               string
+		missingMetadata    string
+		useStartTimeMetric bool
+		labelValueAction   string
+		samples            []sample
+		wantErr            error
+		want               map[string]float64
+	}{
+		{
+			name:            "missing_metadata",
+			missingMetadata: MissingMetadataDrop,
+			samples: []sample{
+				{ls: series("counter"), v: 1},
+				{ls: series("foo", "a", "1"), v: 1},
+				{ls: series("foo", "a", "2"), v: 1},
+			},
+			want: map[string]float64{dropReasonMissingMetadata: 2},
+		},
+		{
+			name: "invalid_type",
+			samples: []sample{
+				{ls: series("counter"), v: 1},
+				// A histogram without count.
+				{ls: series("hist_bucket", model.BucketLabel, "+Inf"), v: 1},
+				{ls: series("hist_sum"), v: 1},
+			},
+			want: map[string]float64{dropReasonInvalidType: 1},
+		},
+		{
+			name:             "limit",
+			labelValueAction: LabelValueLimitDrop,
+			samples: []sample{
+				{ls: series("counter"), v: 1},
+				{ls: series("counter", "trace", strings.Repeat("x", 100)), v: 1},
+			},
+			want: map[string]float64{dropReasonLimit: 1},
+		},
+		{
+			name:               "stale_only",
+			useStartTimeMetric: true,
+			samples: []sample{
+				{ls: series("process_start_time_seconds"), v: staleNaN},
+				{ls: series("counter"), v: staleNaN},
+			},
+			wantErr: errNoStartTimeMetrics,
+			want:    map[string]float64{dropReasonStaleOnly: 2},
+		},
+		{
+			name:               "no_start_time",
+			useStartTimeMetric: true,
+			samples: []sample{
+				{ls: series("counter"), v: 1},
+			},
+			wantErr: errNoStartTimeMetrics,
+			want:    map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		for _, pdataDirect := range []bool{false, true} {
+			name := tt.name
+			if pdataDirect {
+				name += "_pdata"
+			}
+			t.Run(name, func(t *testing.T) {
+				views := MetricViews()
+				require.NoError(t, view.Register(views...))
+				defer view.Unregister(views...)
+
+				sink := new(consumertest.MetricsSink)
+				jobsMap := NewJobsMapPdata(time.Minute, 0, rID)
+				var commit func() error
+				var appendSample func(labels.Labels, int64, float64) error
+				if pdataDirect {
+					tx := newTransactionPdata(context.Background(), &txConfig{jobsMap: jobsMap, useStartTimeMetric: tt.useStartTimeMetric, receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), missingMetadata: tt.missingMetadata, labelValueMaxLength: 10, labelValueAction: tt.labelValueAction, resourceAttrKeys: DefaultResourceAttributeKeys})
+					appendSample = func(ls labels.Labels, ts int64, v float64) error {
+						_, err := tx.Append(0, ls, ts, v)
+						return err
+					}
+					commit = tx.Commit
+				} else {
+					tx := newTransaction(context.Background(), &txConfig{jobsMap: jobsMap, useStartTimeMetric: tt.useStartTimeMetric, receiverID: rID, ms: ms, sink: sink, missingMetadata: tt.missingMetadata, labelValueMaxLength: 10, labelValueAction: tt.labelValueAction, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+					appendSample = func(ls labels.Labels, ts int64, v float64) error {
+						_, err := tx.Append(0, ls, ts, v)
+						return err
+					}
+					commit = tx.Commit
+				}
+
+				ts := time.Now().Unix() * 1000
+				for _, s := range tt.samples {
+					require.NoError(t, appendSample(s.ls, ts, s.v))
+				}
+				err := commit()
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+				assert.Equal(t, tt.want, droppedSeries(t))
+			})
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+)
+
+// Policies applied when a target exposes the same series more than once in a single scrape.
+const (
+	// DuplicateSamplesKeepLast keeps the last sample of a duplicated series.
+	DuplicateSamplesKeepLast = "keep_last"
+	// DuplicateSamplesKeepFirst keeps the first sample of a duplicated series and drops the
+	// following ones, which is what the Prometheus server does.
+	DuplicateSamplesKeepFirst = "keep_first"
+	// DuplicateSamplesReject fails the whole scrape when a duplicated series is found.
+	DuplicateSamplesReject = "reject"
+)
+
+var errDuplicateSample = errors.New("duplicate sample for series")
+
+// duplicateSampleDetector tracks the series appended during a single transaction and applies
+// the configured policy to the samples of series that were already seen.
+type duplicateSampleDetector struct {
+	policy     string
+	receiverID config.ComponentID
+	// seen holds the label set of the first series seen per hash, and collisions the ones of
+	// the following series having the same hash, so that they aren't taken for duplicates.
+	seen       map[uint64]labels.Labels
+	collisions map[uint64][]labels.Labels
+}
+
+func newDuplicateSampleDetector(policy string, receiverID config.ComponentID) *duplicateSampleDetector {
+	return &duplicateSampleDetector{
+		policy:     policy,
+		receiverID: receiverID,
+		seen:       make(map[uint64]labels.Labels),
+	}
+}
+
+// check returns nil if the sample for ls has to be appended. For duplicates, it returns
+// storage.ErrDuplicateSampleForTimestamp if the sample has to be dropped, and an error
+// aborting the scrape if duplicates are rejected.
+func (d *duplicateSampleDetector) check(ctx context.Context, ls labels.Labels) error {
+	if !d.seenBefore(ls) {
+		return nil
+	}
+
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, d.receiverID.String()), tag.Upsert(tagJobKey, ls.Get(model.JobLabel))},
+		statDuplicateSamples.M(1),
+	)
+	switch d.policy {
+	case DuplicateSamplesKeepFirst:
+		return storage.ErrDuplicateSampleForTimestamp
+	case DuplicateSamplesReject:
+		return fmt.Errorf("%w %s", errDuplicateSample, ls.String())
+	default:
+		return nil
+	}
+}
+
+// seenBefore returns whether the series of ls was already seen, and tracks it otherwise.
+func (d *duplicateSampleDetector) seenBefore(ls labels.Labels) bool {
+	h := ls.Hash()
+	seen, ok := d.seen[h]
+	if !ok {
+		d.seen[h] = ls
+		return false
+	}
+	if labels.Equal(seen, ls) {
+		return true
+	}
+	for _, seen := range d.collisions[h] {
+		if labels.Equal(seen, ls) {
+			return true
+		}
+	}
+	if d.collisions == nil {
+		d.collisions = make(map[uint64][]labels.Labels)
+	}
+	d.collisions[h] = append(d.collisions[h], ls)
+	return false
+}
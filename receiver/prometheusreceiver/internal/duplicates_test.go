@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestDuplicateSampleDetector(t *testing.T) {
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	other := labels.FromStrings(model.MetricNameLabel, "bar", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+	tests := []struct {
+		policy string
+		err    error
+	}{
+		{policy: "", err: nil},
+		{policy: DuplicateSamplesKeepLast, err: nil},
+		{policy: DuplicateSamplesKeepFirst, err: storage.ErrDuplicateSampleForTimestamp},
+		{policy: DuplicateSamplesReject, err: errDuplicateSample},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			d := newDuplicateSampleDetector(tt.policy, config.NewComponentID("prometheus"))
+			require.NoError(t, d.check(context.Background(), ls))
+			require.NoError(t, d.check(context.Background(), other))
+
+			err := d.check(context.Background(), ls)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.err), "unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestDuplicateSampleDetectorHashCollision(t *testing.T) {
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	colliding := labels.FromStrings(model.MetricNameLabel, "bar", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+	d := newDuplicateSampleDetector(DuplicateSamplesReject, config.NewComponentID("prometheus"))
+	// A series seen before whose hash collides with the one of ls.
+	d.seen[ls.Hash()] = colliding
+	require.NoError(t, d.check(context.Background(), ls))
+	assert.True(t, errors.Is(d.check(context.Background(), ls), errDuplicateSample))
+}
+
+func TestDuplicateSampleDetectorMetric(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	d := newDuplicateSampleDetector(DuplicateSamplesKeepFirst, config.NewComponentID("prometheus"))
+	for i := 0; i < 3; i++ {
+		_ = d.check(context.Background(), ls)
+	}
+
+	rows, err := view.RetrieveData(statDuplicateSamples.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+	assert.ElementsMatch(t, []string{"prometheus", "test"}, []string{rows[0].Tags[0].Value, rows[0].Tags[1].Value})
+}
+
+func TestTransactionDuplicateSamples(t *testing.T) {
+	ms := &metadataService{
+		sm: &mockScrapeManager{targets: map[string][]*scrape.Target{
+			"test": {scrape.NewTarget(labels.FromStrings(model.InstanceLabel, "localhost:8080"), labels.FromStrings(model.SchemeLabel, "http"), nil)},
+		}},
+	}
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	ts := time.Now().Unix() * 1000
+
+	tests := []struct {
+		policy   string
+		expected float64
+	}{
+		{policy: DuplicateSamplesKeepLast, expected: 2},
+		{policy: DuplicateSamplesKeepFirst, expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), duplicateSamples: tt.policy, resourceAttrKeys: DefaultResourceAttributeKeys})
+			_, err := tr.Append(0, ls, ts, 1)
+			require.NoError(t, err)
+			_, err = tr.Append(0, ls, ts, 2)
+			if tt.policy == DuplicateSamplesKeepFirst {
+				require.ErrorIs(t, err, storage.ErrDuplicateSampleForTimestamp)
+			} else {
+				require.NoError(t, err)
+			}
+			tr.metricBuilder.startTime = 1.0
+			require.NoError(t, tr.Commit())
+
+			require.Len(t, sink.AllMetrics(), 1)
+			metrics := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 1, metrics.Len())
+			dps := metrics.At(0).Gauge().DataPoints()
+			require.Equal(t, 1, dps.Len())
+			assert.Equal(t, tt.expected, dps.At(0).DoubleVal())
+		})
+	}
+
+	t.Run(DuplicateSamplesReject, func(t *testing.T) {
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), duplicateSamples: DuplicateSamplesReject, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+		_, err := tr.Append(0, ls, ts, 1)
+		require.NoError(t, err)
+		_, err = tr.Append(0, ls, ts, 2)
+		require.ErrorIs(t, err, errDuplicateSample)
+		assert.Contains(t, err.Error(), `foo`)
+		require.NoError(t, tr.Rollback())
+	})
+}
+
+func TestAddComplexValueReplacesDuplicateBoundary(t *testing.T) {
+	values := addComplexValue(nil, 1, 0.5)
+	values = addComplexValue(values, 2, 1)
+	values = addComplexValue(values, 3, 0.5)
+	require.Len(t, values, 2)
+	assert.Equal(t, &dataPoint{value: 3, boundary: 0.5}, values[0])
+	assert.Equal(t, &dataPoint{value: 2, boundary: 1}, values[1])
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// externalLabelsChunkSize is the minimum number of labels of the chunks the labels of the samples
+// are copied to by an externalLabelsAppender.
+const externalLabelsChunkSize = 1024
+
+// externalLabelsAppender adds the external labels to the labels of the samples of a transaction.
+// The labels of the samples are retained by the metric groups until the transaction is committed,
+// they are therefore copied with the external labels to chunks shared by many samples rather than
+// allocated per sample. The labels passed by the scrape loop are never modified.
+type externalLabelsAppender struct {
+	external labels.Labels
+	chunk    labels.Labels
+}
+
+func newExternalLabelsAppender(external labels.Labels) *externalLabelsAppender {
+	return &externalLabelsAppender{external: external}
+}
+
+// append returns ls followed by the external labels, or ls itself if there are no external labels.
+func (a *externalLabelsAppender) append(ls labels.Labels) labels.Labels {
+	if len(a.external) == 0 {
+		return ls
+	}
+	n := len(ls) + len(a.external)
+	if cap(a.chunk)-len(a.chunk) < n {
+		size := externalLabelsChunkSize
+		if n > size {
+			size = n
+		}
+		a.chunk = make(labels.Labels, 0, size)
+	}
+	start := len(a.chunk)
+	a.chunk = append(a.chunk, ls...)
+	a.chunk = append(a.chunk, a.external...)
+	// The capacity is limited so that appending to the labels of a sample can't overwrite the next ones.
+	return a.chunk[start:len(a.chunk):len(a.chunk)]
+}
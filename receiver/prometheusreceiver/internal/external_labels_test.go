@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestExternalLabelsAppender(t *testing.T) {
+	external := labels.FromStrings("cluster", "prod", "region", "eu")
+	a := newExternalLabelsAppender(external)
+
+	// The labels of the scrape loop may have spare capacity, they must not be overwritten.
+	scraped := make(labels.Labels, 0, 8)
+	scraped = append(scraped, labels.Label{Name: "__name__", Value: "foo"})
+	first := a.append(scraped)
+	second := a.append(labels.FromStrings("__name__", "bar", "job", "test"))
+
+	assert.Equal(t, labels.Labels{{Name: "__name__", Value: "foo"}}, scraped)
+	assert.Equal(t, labels.FromStrings("__name__", "foo", "cluster", "prod", "region", "eu"), first)
+	assert.Equal(t, labels.Labels{
+		{Name: "__name__", Value: "bar"},
+		{Name: "job", Value: "test"},
+		{Name: "cluster", Value: "prod"},
+		{Name: "region", Value: "eu"},
+	}, second)
+
+	// Appending to the labels of a sample must not overwrite the labels of the next one.
+	_ = append(first, labels.Label{Name: "extra", Value: "x"})
+	assert.Equal(t, "bar", second.Get("__name__"))
+}
+
+func TestExternalLabelsAppenderChunks(t *testing.T) {
+	a := newExternalLabelsAppender(labels.FromStrings("cluster", "prod"))
+
+	var all []labels.Labels
+	for i := 0; i < externalLabelsChunkSize; i++ {
+		all = append(all, a.append(labels.FromStrings("__name__", fmt.Sprintf("metric_%d", i))))
+	}
+	large := make(labels.Labels, externalLabelsChunkSize+1)
+	for i := range large {
+		large[i] = labels.Label{Name: fmt.Sprintf("label_%d", i), Value: "v"}
+	}
+	all = append(all, a.append(large))
+
+	for i, ls := range all[:externalLabelsChunkSize] {
+		require.Equal(t, labels.FromStrings("__name__", fmt.Sprintf("metric_%d", i), "cluster", "prod"), ls)
+	}
+	require.Len(t, all[externalLabelsChunkSize], externalLabelsChunkSize+2)
+	require.Equal(t, "prod", all[externalLabelsChunkSize].Get("cluster"))
+}
+
+func TestExternalLabelsAppenderWithoutExternalLabels(t *testing.T) {
+	a := newExternalLabelsAppender(nil)
+	ls := labels.FromStrings("__name__", "foo")
+	assert.Equal(t, ls, a.append(ls))
+	assert.Nil(t, a.chunk)
+}
+
+// BenchmarkAppendExternalLabels appends the samples of a scrape of 1000 series to a transaction.
+func BenchmarkAppendExternalLabels(b *testing.B) {
+	const numSeries = 1000
+	target := scrape.NewTarget(
+		labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "test"),
+		labels.FromStrings(model.AddressLabel, "localhost:8080", model.SchemeLabel, "http"),
+		nil)
+	ms := &metadataService{sm: &mockScrapeManager{targets: map[string][]*scrape.Target{"test": {target}}}}
+	series := make([]labels.Labels, numSeries)
+	for i := range series {
+		series[i] = labels.FromStrings(
+			model.MetricNameLabel, "foo",
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			"id", fmt.Sprint(i))
+	}
+	external := labels.FromStrings("cluster", "prod", "region", "eu")
+
+	for _, bb := range []struct {
+		name     string
+		external labels.Labels
+	}{
+		{name: "none", external: nil},
+		{name: "two", external: external},
+	} {
+		b.Run("transaction/"+bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), externalLabels: bb.external, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+				for j, ls := range series {
+					if _, err := tr.Append(0, ls, int64(j), 1); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run("transactionPdata/"+bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), externalLabels: bb.external, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
+				for j, ls := range series {
+					if _, err := tr.Append(0, ls, int64(j), 1); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+// targetMetadataProvider provides the metadata of a single target.
+type targetMetadataProvider struct {
+	job, instance string
+	mc            MetadataCache
+}
+
+func (p *targetMetadataProvider) Get(job, instance string) (MetadataCache, error) {
+	if job != p.job || instance != p.instance {
+		return nil, &targetNotFoundError{job: job, instance: instance}
+	}
+	return p.mc, nil
+}
+
+func newHonorLabelsAppenders(ms metadataProvider, honorLabelsJobs map[string]bool) map[string]func(sink *consumertest.MetricsSink) storage.Appender {
+	rID := config.NewComponentID("prometheus")
+	return map[string]func(sink *consumertest.MetricsSink) storage.Appender{
+		"opencensus": func(sink *consumertest.MetricsSink) storage.Appender {
+			return newTransaction(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, missingMetadata: MissingMetadataDrop, honorLabelsJobs: honorLabelsJobs, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+		},
+		"pdata": func(sink *consumertest.MetricsSink) storage.Appender {
+			return newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), missingMetadata: MissingMetadataDrop, honorLabelsJobs: honorLabelsJobs, resourceAttrKeys: DefaultResourceAttributeKeys})
+		},
+	}
+}
+
+func TestHonorLabels(t *testing.T) {
+	ms := &targetMetadataProvider{job: "pushgateway", instance: "pushgateway:9091", mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"foo": {Metric: "foo", Type: textparse.MetricTypeGauge},
+		"up":  {Metric: "up", Type: textparse.MetricTypeGauge},
+	})}
+
+	for name, newAppender := range newHonorLabelsAppenders(ms, map[string]bool{"pushgateway": true}) {
+		t.Run(name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newAppender(sink)
+			ts := time.Now().Unix() * 1000
+			for _, s := range []struct {
+				ls labels.Labels
+				v  float64
+			}{
+				{labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "batch", model.InstanceLabel, "host1:8080"), 1},
+				{labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "batch", model.InstanceLabel, "host2:8080"), 2},
+				{labels.FromStrings(model.MetricNameLabel, "up", model.JobLabel, "pushgateway", model.InstanceLabel, "pushgateway:9091"), 1},
+				{labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "batch", model.InstanceLabel, "host1:8080", "a", "b"), 3},
+			} {
+				_, err := tr.Append(0, s.ls, ts, s.v)
+				require.NoError(t, err)
+			}
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			rms := mds[0].ResourceMetrics()
+			require.Equal(t, 3, rms.Len())
+
+			want := []struct {
+				job, instance string
+				metric        string
+				points        int
+			}{
+				{job: "pushgateway", instance: "pushgateway:9091", metric: "up", points: 1},
+				{job: "batch", instance: "host1:8080", metric: "foo", points: 2},
+				{job: "batch", instance: "host2:8080", metric: "foo", points: 1},
+			}
+			for i, w := range want {
+				rm := rms.At(i)
+				job, _ := rm.Resource().Attributes().Get(jobAttr)
+				instance, _ := rm.Resource().Attributes().Get(instanceAttr)
+				assert.Equal(t, w.job, job.StringVal())
+				assert.Equal(t, w.instance, instance.StringVal())
+				metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+				require.Equal(t, 1, metrics.Len())
+				assert.Equal(t, w.metric, metrics.At(0).Name())
+				assert.Equal(t, w.points, metrics.At(0).Gauge().DataPoints().Len())
+			}
+		})
+	}
+}
+
+func TestHonorLabelsTargetNotFound(t *testing.T) {
+	ms := &targetMetadataProvider{job: "pushgateway", instance: "pushgateway:9091", mc: newMockMetadataCache(nil)}
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "batch", model.InstanceLabel, "host1:8080")
+
+	for name, newAppender := range newHonorLabelsAppenders(ms, map[string]bool{"pushgateway": true}) {
+		t.Run(name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newAppender(sink)
+			_, err := tr.Append(0, ls, time.Now().Unix()*1000, 1.0)
+			require.NoError(t, err)
+			// The target is looked up from the first sample on commit, the missing metadata policy applies.
+			require.Error(t, tr.Commit())
+			assert.Empty(t, sink.AllMetrics())
+		})
+	}
+
+	for name, newAppender := range newHonorLabelsAppenders(ms, nil) {
+		t.Run(name+" disabled", func(t *testing.T) {
+			tr := newAppender(new(consumertest.MetricsSink))
+			_, err := tr.Append(0, ls, time.Now().Unix()*1000, 1.0)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestHonorLabelsPerJob(t *testing.T) {
+	ms := &targetMetadataProvider{job: "node", instance: "node:9100", mc: newMockMetadataCache(nil)}
+	honorLabelsJobs := map[string]bool{"pushgateway": true, "node": false}
+
+	for name, newAppender := range newHonorLabelsAppenders(ms, honorLabelsJobs) {
+		t.Run(name, func(t *testing.T) {
+			// The job of the series is the one of a scrape config without honor_labels, so the
+			// series carries the labels of its target.
+			tr := newAppender(new(consumertest.MetricsSink))
+			_, err := tr.Append(0, labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "node", model.InstanceLabel, "other:9100"), time.Now().Unix()*1000, 1.0)
+			require.Error(t, err)
+
+			// The job of the series may have been honored by the pushgateway scrape config.
+			tr = newAppender(new(consumertest.MetricsSink))
+			_, err = tr.Append(0, labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "batch", model.InstanceLabel, "host1:8080"), time.Now().Unix()*1000, 1.0)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// Policies applied to the info metrics, the gauges named *_info whose series have a value of 1
+// and labels describing the target, e.g. build_info or node_uname_info.
+const (
+	// InfoMetricsGauge keeps the info metrics as gauges.
+	InfoMetricsGauge = "gauge"
+	// InfoMetricsResource adds the labels of the info metrics to the attributes of the resource
+	// of the target and drops the info metrics.
+	InfoMetricsResource = "resource"
+)
+
+const infoMetricSuffix = "_info"
+
+// convertInfoMetrics adds the attributes of the data point of the info metrics of md to the
+// attributes of their resource and removes the info metrics. The attributes already set on the
+// resource, and the ones of the info metrics converted first, are kept. The data points of stale
+// series are dropped without being converted.
+func convertInfoMetrics(md pdata.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		attrs := rm.Resource().Attributes()
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilms.At(j).Metrics().RemoveIf(func(metric pdata.Metric) bool {
+				if !isInfoMetric(metric) {
+					return false
+				}
+				if n, dp := liveDataPoints(metric.Gauge().DataPoints()); n == 1 {
+					dp.Attributes().Range(func(name string, value pdata.AttributeValue) bool {
+						attrs.Insert(name, value)
+						return true
+					})
+				}
+				return true
+			})
+		}
+	}
+}
+
+// isInfoMetric returns whether metric is an info metric having a single series. The info metrics
+// having several series per target, e.g. one per CPU or per device, are kept as gauges, as their
+// labels would overwrite each other on the resource. The stale series aren't counted, e.g. the
+// build_info of the previous version of a target being upgraded.
+func isInfoMetric(metric pdata.Metric) bool {
+	if metric.DataType() != pdata.MetricDataTypeGauge || !strings.HasSuffix(metric.Name(), infoMetricSuffix) {
+		return false
+	}
+	dps := metric.Gauge().DataPoints()
+	if dps.Len() == 0 {
+		return false
+	}
+	switch n, dp := liveDataPoints(dps); n {
+	case 0:
+		return true
+	case 1:
+		switch dp.Type() {
+		case pdata.MetricValueTypeInt:
+			return dp.IntVal() == 1
+		case pdata.MetricValueTypeDouble:
+			return dp.DoubleVal() == 1
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+}
+
+// liveDataPoints returns the number of data points of dps which aren't flagged as stale, and the
+// last of them.
+func liveDataPoints(dps pdata.NumberDataPointSlice) (int, pdata.NumberDataPoint) {
+	n, last := 0, pdata.NewNumberDataPoint()
+	for i := 0; i < dps.Len(); i++ {
+		if dp := dps.At(i); !dp.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
+			n, last = n+1, dp
+		}
+	}
+	return n, last
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestConvertInfoMetrics(t *testing.T) {
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("job", "test")
+	metrics := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	info := metrics.AppendEmpty()
+	info.SetName("build_info")
+	info.SetDataType(pdata.MetricDataTypeGauge)
+	dp := info.Gauge().DataPoints().AppendEmpty()
+	dp.SetDoubleVal(1)
+	dp.Attributes().InsertString("version", "1.2.3")
+	dp.Attributes().InsertString("job", "overridden")
+
+	staleInfo := metrics.AppendEmpty()
+	staleInfo.SetName("kernel_info")
+	staleInfo.SetDataType(pdata.MetricDataTypeGauge)
+	stale := staleInfo.Gauge().DataPoints().AppendEmpty()
+	stale.SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+	stale.Attributes().InsertString("revision", "abc")
+
+	upgraded := metrics.AppendEmpty()
+	upgraded.SetName("node_uname_info")
+	upgraded.SetDataType(pdata.MetricDataTypeGauge)
+	old := upgraded.Gauge().DataPoints().AppendEmpty()
+	old.SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+	old.Attributes().InsertString("release", "5.4")
+	live := upgraded.Gauge().DataPoints().AppendEmpty()
+	live.SetDoubleVal(1)
+	live.Attributes().InsertString("release", "5.10")
+
+	multi := metrics.AppendEmpty()
+	multi.SetName("disk_info")
+	multi.SetDataType(pdata.MetricDataTypeGauge)
+	for _, device := range []string{"sda", "sdb"} {
+		dp := multi.Gauge().DataPoints().AppendEmpty()
+		dp.SetDoubleVal(1)
+		dp.Attributes().InsertString("device", device)
+	}
+
+	notOne := metrics.AppendEmpty()
+	notOne.SetName("cpu_info")
+	notOne.SetDataType(pdata.MetricDataTypeGauge)
+	notOne.Gauge().DataPoints().AppendEmpty().SetDoubleVal(4)
+
+	notGauge := metrics.AppendEmpty()
+	notGauge.SetName("requests_info")
+	notGauge.SetDataType(pdata.MetricDataTypeSum)
+	notGauge.Sum().DataPoints().AppendEmpty().SetDoubleVal(1)
+
+	other := metrics.AppendEmpty()
+	other.SetName("up")
+	other.SetDataType(pdata.MetricDataTypeGauge)
+	other.Gauge().DataPoints().AppendEmpty().SetDoubleVal(1)
+
+	convertInfoMetrics(md)
+
+	assert.Equal(t, map[string]interface{}{"job": "test", "version": "1.2.3", "release": "5.10"}, rm.Resource().Attributes().AsRaw())
+	require.Equal(t, 4, metrics.Len())
+	assert.Equal(t, "disk_info", metrics.At(0).Name())
+	assert.Equal(t, "cpu_info", metrics.At(1).Name())
+	assert.Equal(t, "requests_info", metrics.At(2).Name())
+	assert.Equal(t, "up", metrics.At(3).Name())
+}
+
+func TestInfoMetricsResource(t *testing.T) {
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"build_info": {Metric: "build_info", Type: textparse.MetricTypeGauge},
+		"foo":        {Metric: "foo", Type: textparse.MetricTypeGauge},
+	})}
+	rID := config.NewComponentID("prometheus")
+
+	tests := []struct {
+		name        string
+		newAppender func(sink *consumertest.MetricsSink) storage.Appender
+	}{
+		{
+			name: "opencensus",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransaction(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, infoMetrics: InfoMetricsResource, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+			},
+		},
+		{
+			name: "pdata",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), infoMetrics: InfoMetricsResource, resourceAttrKeys: DefaultResourceAttributeKeys})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := tt.newAppender(sink)
+			ts := time.Now().Unix() * 1000
+			// The series of the version before the upgrade of the target went stale.
+			_, err := tr.Append(0, labels.FromStrings(model.MetricNameLabel, "build_info", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "version", "1.2.2"), ts, math.Float64frombits(value.StaleNaN))
+			require.NoError(t, err)
+			_, err = tr.Append(0, labels.FromStrings(model.MetricNameLabel, "build_info", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "version", "1.2.3"), ts, 1.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080"), ts, 2.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			rm := mds[0].ResourceMetrics().At(0)
+			version, ok := rm.Resource().Attributes().Get("version")
+			require.True(t, ok)
+			assert.Equal(t, "1.2.3", version.StringVal())
+			metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 1, metrics.Len())
+			assert.Equal(t, "foo", metrics.At(0).Name())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestJobResourceAttributes(t *testing.T) {
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"foo": {Metric: "foo", Type: textparse.MetricTypeGauge},
+	})}
+	rID := config.NewComponentID("prometheus")
+	jobAttrs := map[string]map[string]string{
+		"test":  {"deployment.environment": "prod", "instance": "overridden"},
+		"other": {"deployment.environment": "staging"},
+	}
+
+	tests := []struct {
+		name        string
+		newAppender func(sink *consumertest.MetricsSink) storage.Appender
+	}{
+		{
+			name: "opencensus",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransaction(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, jobResourceAttrs: jobAttrs, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+			},
+		},
+		{
+			name: "pdata",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), jobResourceAttrs: jobAttrs, resourceAttrKeys: DefaultResourceAttributeKeys})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := tt.newAppender(sink)
+			ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+			_, err := tr.Append(0, ls, time.Now().Unix()*1000, 1.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			attrs := mds[0].ResourceMetrics().At(0).Resource().Attributes()
+			env, ok := attrs.Get("deployment.environment")
+			require.True(t, ok)
+			assert.Equal(t, "prod", env.StringVal())
+			instance, ok := attrs.Get("instance")
+			require.True(t, ok)
+			assert.Equal(t, "localhost:8080", instance.StringVal())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+)
+
+// Actions applied to the series having label values longer than the configured limit.
+const (
+	// LabelValueLimitTruncate truncates the label values to the limit.
+	LabelValueLimitTruncate = "truncate"
+	// LabelValueLimitDrop drops the samples of the series.
+	LabelValueLimitDrop = "drop"
+)
+
+// labelValueLimiter applies the configured action to the label values exceeding maxLength
+// bytes. The metric name and the job and instance labels, which identify the metric and the
+// target of the series, are left untouched.
+type labelValueLimiter struct {
+	maxLength  int
+	action     string
+	receiverID config.ComponentID
+}
+
+func newLabelValueLimiter(maxLength int, action string, receiverID config.ComponentID) *labelValueLimiter {
+	return &labelValueLimiter{
+		maxLength:  maxLength,
+		action:     action,
+		receiverID: receiverID,
+	}
+}
+
+// apply returns the labels of the sample to append, and whether the sample has to be appended.
+func (l *labelValueLimiter) apply(ctx context.Context, ls labels.Labels) (labels.Labels, bool) {
+	if l.maxLength <= 0 {
+		return ls, true
+	}
+	var limited labels.Labels
+	for i, lbl := range ls {
+		if len(lbl.Value) <= l.maxLength || !limitable(lbl.Name) {
+			continue
+		}
+		if l.action == LabelValueLimitDrop {
+			l.record(ctx, ls, statLabelValueLimitDroppedSamples.M(1))
+			recordDroppedSeries(ctx, l.receiverID, ls.Get(model.JobLabel), dropReasonLimit, 1)
+			return ls, false
+		}
+		if limited == nil {
+			// ls may be held by the scrape loop, the truncated values are set on a copy.
+			limited = make(labels.Labels, len(ls))
+			copy(limited, ls)
+		}
+		limited[i].Value = truncate(lbl.Value, l.maxLength)
+		l.record(ctx, ls, statTruncatedLabels.M(1))
+	}
+	if limited == nil {
+		return ls, true
+	}
+	return limited, true
+}
+
+func (l *labelValueLimiter) record(ctx context.Context, ls labels.Labels, m stats.Measurement) {
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, l.receiverID.String()), tag.Upsert(tagJobKey, ls.Get(model.JobLabel))},
+		m,
+	)
+}
+
+func limitable(name string) bool {
+	return name != model.MetricNameLabel && name != model.JobLabel && name != model.InstanceLabel
+}
+
+// truncate returns the longest prefix of value of at most maxLength bytes which doesn't
+// split a UTF-8 encoded character.
+func truncate(value string, maxLength int) string {
+	for maxLength > 0 && !utf8.RuneStart(value[maxLength]) {
+		maxLength--
+	}
+	return value[:maxLength]
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestLabelValueLimiter(t *testing.T) {
+	long := strings.Repeat("x", 10)
+	ls := labels.FromStrings(model.MetricNameLabel, long, model.JobLabel, long, model.InstanceLabel, long, "a", "short", "b", long)
+
+	tests := []struct {
+		name      string
+		maxLength int
+		action    string
+		ls        labels.Labels
+		want      labels.Labels
+		wantKeep  bool
+	}{
+		{
+			name:     "unlimited",
+			action:   LabelValueLimitDrop,
+			ls:       ls,
+			want:     ls,
+			wantKeep: true,
+		},
+		{
+			name:      "truncate",
+			maxLength: 5,
+			action:    LabelValueLimitTruncate,
+			ls:        ls,
+			want:      labels.FromStrings(model.MetricNameLabel, long, model.JobLabel, long, model.InstanceLabel, long, "a", "short", "b", "xxxxx"),
+			wantKeep:  true,
+		},
+		{
+			name:      "truncate utf8",
+			maxLength: 4,
+			action:    LabelValueLimitTruncate,
+			ls:        labels.FromStrings("a", "abcé"),
+			want:      labels.FromStrings("a", "abc"),
+			wantKeep:  true,
+		},
+		{
+			name:      "drop",
+			maxLength: 5,
+			action:    LabelValueLimitDrop,
+			ls:        ls,
+			want:      ls,
+			wantKeep:  false,
+		},
+		{
+			name:      "within limit",
+			maxLength: 10,
+			action:    LabelValueLimitDrop,
+			ls:        ls,
+			want:      ls,
+			wantKeep:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.ls.Copy()
+			l := newLabelValueLimiter(tt.maxLength, tt.action, config.NewComponentID("prometheus"))
+			got, keep := l.apply(context.Background(), tt.ls)
+			assert.Equal(t, tt.wantKeep, keep)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, original, tt.ls, "the labels of the sample must not be modified")
+		})
+	}
+}
+
+func TestLabelValueLimiterMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", "a", "too long", "b", "too long")
+	truncate := newLabelValueLimiter(3, LabelValueLimitTruncate, config.NewComponentID("prometheus"))
+	_, keep := truncate.apply(context.Background(), ls)
+	assert.True(t, keep)
+	drop := newLabelValueLimiter(3, LabelValueLimitDrop, config.NewComponentID("prometheus"))
+	_, keep = drop.apply(context.Background(), ls)
+	assert.False(t, keep)
+
+	rows, err := view.RetrieveData(statTruncatedLabels.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+	assert.ElementsMatch(t, []string{"prometheus", "test"}, []string{rows[0].Tags[0].Value, rows[0].Tags[1].Value})
+
+	rows, err = view.RetrieveData(statLabelValueLimitDroppedSamples.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestTransactionLabelValueLimit(t *testing.T) {
+	rID := config.NewComponentID("prometheus")
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(nil)}
+	ts := time.Now().Unix() * 1000
+	short := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "trace", "ok")
+	long := labels.FromStrings(model.MetricNameLabel, "bar", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "trace", strings.Repeat("x", 100))
+
+	tests := []struct {
+		action    string
+		wantNames []string
+		wantValue string
+	}{
+		{action: LabelValueLimitTruncate, wantNames: []string{"foo", "bar"}, wantValue: "xxxxxxxxxx"},
+		{action: LabelValueLimitDrop, wantNames: []string{"foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), labelValueMaxLength: 10, labelValueAction: tt.action, resourceAttrKeys: DefaultResourceAttributeKeys})
+			_, err := tr.Append(0, short, ts, 1.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, long, ts, 1.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			var names []string
+			for i := 0; i < metrics.Len(); i++ {
+				names = append(names, metrics.At(i).Name())
+				if metrics.At(i).Name() == "bar" {
+					value, ok := metrics.At(i).Gauge().DataPoints().At(0).Attributes().Get("trace")
+					require.True(t, ok)
+					assert.Equal(t, tt.wantValue, value.StringVal())
+				}
+			}
+			assert.ElementsMatch(t, tt.wantNames, names)
+		})
+	}
+}
@@ -15,8 +15,8 @@
 package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 
 import (
-	"errors"
 	"sync"
+	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -29,45 +29,163 @@ type MetadataCache interface {
 	SharedLabels() labels.Labels
 }
 
+// metadataProvider provides the metadata of the metrics collected for a job and instance.
+type metadataProvider interface {
+	Get(job, instance string) (MetadataCache, error)
+}
+
 type ScrapeManager interface {
 	TargetsAll() map[string][]*scrape.Target
 }
 
+// missRebuildInterval is the minimum interval between two rebuilds of the cache caused by
+// lookups of unknown targets: the targets of the scrape manager only change when its reloader
+// runs, every 5 seconds, so rebuilding the cache more often would return the same targets.
+const missRebuildInterval = 5 * time.Second
+
 type metadataService struct {
-	sync.Mutex
+	sync.RWMutex
 	stopped bool
 	sm      ScrapeManager
+
+	// targets caches the metadata handles per job and instance, so that transactions
+	// don't have to go through the scrape manager's target map, which is guarded by the
+	// same lock used for scraping and reloading.
+	targets map[string]map[string]*mCache
+	// builtAt is the time targets was last built from the scrape manager.
+	builtAt time.Time
+	// generation is incremented by every target update sent by discovery, and builtGeneration
+	// is the generation targets was built for.
+	generation      uint64
+	builtGeneration uint64
+	// rebuilding is closed once the rebuild in progress, if any, is done.
+	rebuilding chan struct{}
 }
 
+// Close stops the lookups of targets. It waits for the rebuild in progress, if any, so that
+// the scrape manager's targets are no longer collected once it returns: the scrape manager
+// holds the lock guarding its targets while it waits for the scrapes to stop.
 func (s *metadataService) Close() {
 	s.Lock()
 	s.stopped = true
+	rebuilding := s.rebuilding
 	s.Unlock()
+	if rebuilding != nil {
+		<-rebuilding
+	}
 }
 
-func (s *metadataService) Get(job, instance string) (MetadataCache, error) {
+// refresh invalidates the cached metadata handles after a target update.
+func (s *metadataService) refresh() {
 	s.Lock()
-	defer s.Unlock()
+	s.generation++
+	s.Unlock()
+}
 
+func (s *metadataService) Get(job, instance string) (MetadataCache, error) {
+	s.RLock()
 	// If we're already stopped return early so that we don't call scrapeManager.TargetsAll()
 	// which will result in deadlock if scrapeManager is being stopped.
 	if s.stopped {
+		s.RUnlock()
 		return nil, errAlreadyStopped
 	}
+	mc, ok := s.targets[job][instance]
+	valid := s.isCacheValid()
+	builtAt := s.builtAt
+	recent := time.Since(builtAt) < missRebuildInterval
+	s.RUnlock()
+	if ok && valid {
+		return mc, nil
+	}
+	if valid && recent {
+		return s.lookup(job, instance)
+	}
+	if err := s.rebuild(builtAt); err != nil {
+		return nil, err
+	}
+	return s.lookup(job, instance)
+}
 
-	targetGroup, ok := s.sm.TargetsAll()[job]
+// lookup returns the cached metadata handle of the target of job and instance.
+func (s *metadataService) lookup(job, instance string) (MetadataCache, error) {
+	s.RLock()
+	defer s.RUnlock()
+	instances, ok := s.targets[job]
+	if !ok {
+		return nil, &targetNotFoundError{job: job}
+	}
+	mc, ok := instances[instance]
 	if !ok {
-		return nil, errors.New("unable to find a target group with job=" + job)
+		return nil, &targetNotFoundError{job: job, instance: instance}
+	}
+	return mc, nil
+}
+
+// targetNotFoundError is returned when the scrape manager has no target with the job and instance,
+// e.g. because the target was removed while it was scraped.
+type targetNotFoundError struct {
+	job, instance string
+}
+
+func (e *targetNotFoundError) Error() string {
+	if e.instance == "" {
+		return "unable to find a target group with job=" + e.job
+	}
+	return "unable to find a target with job=" + e.job + ", and instance=" + e.instance
+}
+
+// isCacheValid returns whether the cache was built since the last target update. The
+// scrape manager may apply an update after the cache was rebuilt for it, the targets it
+// adds are then found by the rebuilds of the lookups missing them. Callers must hold the lock.
+func (s *metadataService) isCacheValid() bool {
+	return s.targets != nil && s.builtGeneration == s.generation
+}
+
+// rebuild rebuilds the cached metadata handles from the scrape manager's targets. The targets
+// are collected without holding the lock, so that the lookups of cached targets aren't blocked,
+// and concurrent calls wait for the rebuild in progress instead of starting another one. The
+// cache isn't rebuilt if it was rebuilt since the caller found it built at builtAt.
+func (s *metadataService) rebuild(builtAt time.Time) error {
+	s.Lock()
+	if s.stopped {
+		s.Unlock()
+		return errAlreadyStopped
+	}
+	if !s.builtAt.Equal(builtAt) {
+		s.Unlock()
+		return nil
 	}
+	if rebuilding := s.rebuilding; rebuilding != nil {
+		s.Unlock()
+		<-rebuilding
+		return nil
+	}
+	rebuilding := make(chan struct{})
+	s.rebuilding = rebuilding
+	generation := s.generation
+	s.Unlock()
 
-	// from the same targetGroup, instance is not going to be duplicated
-	for _, target := range targetGroup {
-		if target.Labels().Get(model.InstanceLabel) == instance {
-			return &mCache{target}, nil
+	builtAt = time.Now()
+	targetsAll := s.sm.TargetsAll()
+	targets := make(map[string]map[string]*mCache, len(targetsAll))
+	for job, targetGroup := range targetsAll {
+		instances := make(map[string]*mCache, len(targetGroup))
+		// from the same targetGroup, instance is not going to be duplicated
+		for _, target := range targetGroup {
+			instances[target.Labels().Get(model.InstanceLabel)] = &mCache{target}
 		}
+		targets[job] = instances
 	}
 
-	return nil, errors.New("unable to find a target with job=" + job + ", and instance=" + instance)
+	s.Lock()
+	s.targets = targets
+	s.builtAt = builtAt
+	s.builtGeneration = generation
+	s.rebuilding = nil
+	s.Unlock()
+	close(rebuilding)
+	return nil
 }
 
 // adapter to get metadata from scrape.Target
@@ -0,0 +1,266 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingScrapeManager struct {
+	targets map[string][]*scrape.Target
+	calls   int64
+}
+
+func (sm *countingScrapeManager) TargetsAll() map[string][]*scrape.Target {
+	atomic.AddInt64(&sm.calls, 1)
+	return sm.targets
+}
+
+func newTestTarget(instance string) *scrape.Target {
+	return scrape.NewTarget(
+		labels.FromStrings(model.InstanceLabel, instance),
+		labels.FromStrings(model.SchemeLabel, "http"),
+		nil,
+	)
+}
+
+func TestMetadataServiceGet(t *testing.T) {
+	sm := &countingScrapeManager{targets: map[string][]*scrape.Target{
+		"job": {newTestTarget("localhost:8080"), newTestTarget("localhost:8081")},
+	}}
+	ms := &metadataService{sm: sm}
+
+	mc, err := ms.Get("job", "localhost:8080")
+	require.NoError(t, err)
+	assert.Equal(t, "http", mc.SharedLabels().Get(model.SchemeLabel))
+	_, err = ms.Get("job", "localhost:8081")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, sm.calls, "cached targets should be reused")
+
+	_, err = ms.Get("job", "localhost:9090")
+	assert.EqualError(t, err, "unable to find a target with job=job, and instance=localhost:9090")
+	_, err = ms.Get("unknown", "localhost:8080")
+	assert.EqualError(t, err, "unable to find a target group with job=unknown")
+	assert.EqualValues(t, 1, sm.calls, "cache misses right after a rebuild should reuse the cache")
+
+	ms.builtAt = ms.builtAt.Add(-missRebuildInterval)
+	_, err = ms.Get("job", "localhost:9090")
+	assert.EqualError(t, err, "unable to find a target with job=job, and instance=localhost:9090")
+	_, err = ms.Get("unknown", "localhost:8080")
+	assert.EqualError(t, err, "unable to find a target group with job=unknown")
+	assert.EqualValues(t, 2, sm.calls, "cache misses should rebuild the cache once per interval")
+}
+
+// blockingScrapeManager returns its targets once release is closed.
+type blockingScrapeManager struct {
+	countingScrapeManager
+	release chan struct{}
+}
+
+func (sm *blockingScrapeManager) TargetsAll() map[string][]*scrape.Target {
+	<-sm.release
+	return sm.countingScrapeManager.TargetsAll()
+}
+
+func TestMetadataServiceConcurrentRebuild(t *testing.T) {
+	sm := &blockingScrapeManager{
+		countingScrapeManager: countingScrapeManager{targets: map[string][]*scrape.Target{
+			"job": {newTestTarget("localhost:8080")},
+		}},
+		release: make(chan struct{}),
+	}
+	ms := &metadataService{sm: sm}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ms.Get("job", "localhost:8080")
+			errs <- err
+		}()
+	}
+	// The lookups wait for the same rebuild, which doesn't block the lock.
+	require.Eventually(t, func() bool {
+		ms.RLock()
+		defer ms.RUnlock()
+		return ms.rebuilding != nil
+	}, 5*time.Second, time.Millisecond)
+	close(sm.release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, sm.calls)
+}
+
+func TestMetadataServiceRefresh(t *testing.T) {
+	sm := &countingScrapeManager{targets: map[string][]*scrape.Target{
+		"job": {newTestTarget("localhost:8080")},
+	}}
+	ms := &metadataService{sm: sm}
+
+	old, err := ms.Get("job", "localhost:8080")
+	require.NoError(t, err)
+
+	// The target is replaced, e.g. because its labels changed.
+	sm.targets = map[string][]*scrape.Target{"job": {newTestTarget("localhost:8080")}}
+	ms.refresh()
+	mc, err := ms.Get("job", "localhost:8080")
+	require.NoError(t, err)
+	assert.NotSame(t, old, mc)
+	assert.EqualValues(t, 2, sm.calls)
+
+	// The rebuilt cache is reused until the next update.
+	_, err = ms.Get("job", "localhost:8080")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, sm.calls)
+
+	// Updates keep invalidating the cache once each, however often they are sent.
+	for i := 0; i < 3; i++ {
+		ms.refresh()
+		_, err = ms.Get("job", "localhost:8080")
+		require.NoError(t, err)
+		_, err = ms.Get("job", "localhost:8080")
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 5, sm.calls)
+}
+
+func TestMetadataServiceClosed(t *testing.T) {
+	sm := &countingScrapeManager{targets: map[string][]*scrape.Target{
+		"job": {newTestTarget("localhost:8080")},
+	}}
+	ms := &metadataService{sm: sm}
+	_, err := ms.Get("job", "localhost:8080")
+	require.NoError(t, err)
+
+	ms.Close()
+	_, err = ms.Get("job", "localhost:8080")
+	assert.Equal(t, errAlreadyStopped, err)
+}
+
+// stoppingScrapeManager models the locking of scrape.Manager: TargetsAll takes the lock
+// which Stop holds while waiting for the scrapes to stop.
+type stoppingScrapeManager struct {
+	mtxScrape sync.Mutex
+	targets   map[string][]*scrape.Target
+	scrapes   sync.WaitGroup
+	// collecting is closed once TargetsAll is called, which waits for release to take the lock.
+	collecting chan struct{}
+	release    chan struct{}
+}
+
+func (sm *stoppingScrapeManager) TargetsAll() map[string][]*scrape.Target {
+	close(sm.collecting)
+	<-sm.release
+	sm.mtxScrape.Lock()
+	defer sm.mtxScrape.Unlock()
+	return sm.targets
+}
+
+func (sm *stoppingScrapeManager) Stop() {
+	sm.mtxScrape.Lock()
+	defer sm.mtxScrape.Unlock()
+	sm.scrapes.Wait()
+}
+
+func TestMetadataServiceShutdownDuringGet(t *testing.T) {
+	sm := &stoppingScrapeManager{
+		targets:    map[string][]*scrape.Target{"job": {newTestTarget("localhost:8080")}},
+		collecting: make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	ms := &metadataService{sm: sm}
+
+	sm.scrapes.Add(1)
+	go func() {
+		defer sm.scrapes.Done()
+		_, _ = ms.Get("job", "localhost:8080")
+	}()
+	<-sm.collecting
+
+	// The receiver's shutdown closes the metadata service before it stops the scrape manager.
+	stopped := make(chan struct{})
+	go func() {
+		ms.Close()
+		sm.Stop()
+		close(stopped)
+	}()
+	require.Eventually(t, func() bool {
+		ms.RLock()
+		defer ms.RUnlock()
+		return ms.stopped
+	}, 5*time.Second, time.Millisecond)
+	close(sm.release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown deadlocked with the lookup in progress")
+	}
+	_, err := ms.Get("job", "localhost:8080")
+	assert.Equal(t, errAlreadyStopped, err)
+}
+
+func TestWatchTargetUpdates(t *testing.T) {
+	o := &OcaStore{mc: &metadataService{sm: &countingScrapeManager{}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tsets := make(chan map[string][]*targetgroup.Group)
+	out := o.WatchTargetUpdates(ctx, tsets)
+	update := map[string][]*targetgroup.Group{"job": {{Source: "test"}}}
+	tsets <- update
+	assert.Equal(t, update, <-out)
+	assert.EqualValues(t, 1, o.mc.generation)
+}
+
+func BenchmarkMetadataServiceGet(b *testing.B) {
+	const numTargets = 10000
+	instances := make([]string, 0, numTargets)
+	targets := make([]*scrape.Target, 0, numTargets)
+	for i := 0; i < numTargets; i++ {
+		instances = append(instances, fmt.Sprintf("10.0.%d.%d:8080", i/256, i%256))
+		targets = append(targets, newTestTarget(instances[i]))
+	}
+	ms := &metadataService{sm: &countingScrapeManager{targets: map[string][]*scrape.Target{"job": targets}}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := ms.Get("job", instances[i%numTargets]); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
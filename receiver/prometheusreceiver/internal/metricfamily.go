@@ -216,7 +216,7 @@ func (mf *metricFamily) Add(metricName string, ls labels.Labels, t int64, v floa
 				mf.droppedTimeseries++
 				return err
 			}
-			mg.complexValue = append(mg.complexValue, &dataPoint{value: v, boundary: boundary})
+			mg.complexValue = addComplexValue(mg.complexValue, v, boundary)
 		}
 	default:
 		mg.value = v
@@ -225,6 +225,19 @@ func (mf *metricFamily) Add(metricName string, ls labels.Labels, t int64, v floa
 	return nil
 }
 
+// addComplexValue adds the value of a bucket or quantile to values. If the boundary was
+// already added during the scrape, the previous value is replaced so that the last
+// sample of a duplicated series wins, like for the other metric types.
+func addComplexValue(values []*dataPoint, v float64, boundary float64) []*dataPoint {
+	for _, dp := range values {
+		if dp.boundary == boundary {
+			dp.value = v
+			return values
+		}
+	}
+	return append(values, &dataPoint{value: v, boundary: boundary})
+}
+
 func (mf *metricFamily) ToMetric() (*metricspb.Metric, int, int) {
 	timeseries := make([]*metricspb.TimeSeries, 0, len(mf.groups))
 	switch mf.mtype {
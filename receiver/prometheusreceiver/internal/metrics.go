@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+)
+
+var (
+	tagReceiverKey, _ = tag.NewKey("receiver")
+	tagJobKey, _      = tag.NewKey("job")
+	tagReasonKey, _   = tag.NewKey("reason")
+
+	statDuplicateSamples = stats.Int64("prometheus_receiver_duplicate_samples", "Number of duplicate samples detected in scrapes", stats.UnitDimensionless)
+	statMissingMetadata  = stats.Int64("prometheus_receiver_missing_metadata", "Number of metrics scraped without metadata", stats.UnitDimensionless)
+	statJobsMapEvictions = stats.Int64("prometheus_receiver_jobs_map_evictions", "Number of targets evicted from the cache used to adjust the start time of cumulative metrics", stats.UnitDimensionless)
+	statJobsMapSize      = stats.Int64("prometheus_receiver_jobs_map_size", "Number of targets in the cache used to adjust the start time of cumulative metrics", stats.UnitDimensionless)
+	statCounterResets    = stats.Int64("prometheus_receiver_counter_resets", "Number of resets detected in the series of cumulative metrics", stats.UnitDimensionless)
+	statDroppedSeries    = stats.Int64("prometheus_receiver_dropped_series", "Number of series dropped from scrapes", stats.UnitDimensionless)
+
+	statTruncatedLabels               = stats.Int64("prometheus_receiver_truncated_labels", "Number of label values truncated to the label_value_limit", stats.UnitDimensionless)
+	statLabelValueLimitDroppedSamples = stats.Int64("prometheus_receiver_label_value_limit_dropped_samples", "Number of samples dropped because of label values exceeding the label_value_limit", stats.UnitDimensionless)
+	statOutOfRangeSamples             = stats.Int64("prometheus_receiver_out_of_range_samples", "Number of samples whose timestamps are out of the sample_age range", stats.UnitDimensionless)
+)
+
+// Reasons of the evictions from the JobsMapPdata.
+const (
+	evictionReasonGC         = "gc"
+	evictionReasonMaxEntries = "max_entries"
+)
+
+// Reasons of the resets detected by the MetricsAdjusterPdata.
+const (
+	resetReasonDecrease     = "decrease"
+	resetReasonBucketLayout = "bucket_layout"
+)
+
+// Reasons of the series dropped by the receiver.
+const (
+	// dropReasonMissingMetadata drops the series of the metrics without metadata, with the drop missing_metadata policy.
+	dropReasonMissingMetadata = "missing_metadata"
+	// dropReasonInvalidType drops the series whose samples don't make a valid point of the type of their metric,
+	// e.g. a histogram bucket without le label.
+	dropReasonInvalidType = "invalid_type"
+	// dropReasonLimit drops the series exceeding a limit, e.g. the label_value_limit with the drop action.
+	dropReasonLimit = "limit"
+	// dropReasonStaleOnly drops the series of the scrapes made of staleness markers only, whose start time can't be
+	// determined with use_start_time_metric.
+	dropReasonStaleOnly = "stale_only"
+	// dropReasonSampleAge drops the samples whose timestamps are out of the sample_age range, with the drop action.
+	dropReasonSampleAge = "sample_age"
+	// dropReasonIncomplete drops the series of the remote-write histogram and summary points whose count, or buckets,
+	// weren't received within the hold time.
+	dropReasonIncomplete = "incomplete"
+)
+
+// recordDroppedSeries records n series of job dropped by the receiver for reason.
+func recordDroppedSeries(ctx context.Context, receiverID config.ComponentID, job, reason string, n int) {
+	if n <= 0 {
+		return
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, receiverID.String()), tag.Upsert(tagJobKey, job), tag.Upsert(tagReasonKey, reason)},
+		statDroppedSeries.M(int64(n)),
+	)
+}
+
+// MetricViews returns the metric views for the Prometheus receiver.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        statDuplicateSamples.Name(),
+			Measure:     statDuplicateSamples,
+			Description: statDuplicateSamples.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statMissingMetadata.Name(),
+			Measure:     statMissingMetadata,
+			Description: statMissingMetadata.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statJobsMapEvictions.Name(),
+			Measure:     statJobsMapEvictions,
+			Description: statJobsMapEvictions.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagReasonKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statJobsMapSize.Name(),
+			Measure:     statJobsMapSize,
+			Description: statJobsMapSize.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey},
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        statCounterResets.Name(),
+			Measure:     statCounterResets,
+			Description: statCounterResets.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey, tagReasonKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statDroppedSeries.Name(),
+			Measure:     statDroppedSeries,
+			Description: statDroppedSeries.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey, tagReasonKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statTruncatedLabels.Name(),
+			Measure:     statTruncatedLabels,
+			Description: statTruncatedLabels.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statLabelValueLimitDroppedSamples.Name(),
+			Measure:     statLabelValueLimitDroppedSamples,
+			Description: statLabelValueLimitDroppedSamples.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statOutOfRangeSamples.Name(),
+			Measure:     statOutOfRangeSamples,
+			Description: statOutOfRangeSamples.Description(),
+			TagKeys:     []tag.Key{tagReceiverKey, tagJobKey, tagReasonKey},
+			Aggregation: view.Sum(),
+		},
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/model/value"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -59,6 +60,8 @@ type metricBuilder struct {
 	intervalStartTimeMs  int64
 	logger               *zap.Logger
 	families             map[string]*metricFamily
+	// fresh is set once a sample which isn't a staleness marker is added.
+	fresh bool
 }
 
 // newMetricBuilder creates a MetricBuilder which is allowed to feed all the datapoints from a single prometheus
@@ -132,11 +135,14 @@ func (b *metricBuilder) AddDataPoint(ls labels.Labels, t int64, v float64) error
 					zap.String("target_labels", fmt.Sprintf("%v", lm)))
 			}
 		}
-	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName):
+	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName) && !value.IsStaleNaN(v):
 		b.startTime = v
 	}
 
 	b.hasData = true
+	if !value.IsStaleNaN(v) {
+		b.fresh = true
+	}
 
 	curMF, ok := b.families[metricName]
 	if !ok {
@@ -269,8 +275,13 @@ func convToOCAMetricType(metricType textparse.MetricType) metricspb.MetricDescri
    code borrowed from the original promreceiver
 */
 
+// heuristicalMetricAndKnownUnits returns the UCUM unit of a metric, converted from the unit of its OpenMetrics
+// metadata if any, or else guessed from the suffix of its name. Parsed units which can't be converted are kept as is.
 func heuristicalMetricAndKnownUnits(metricName, parsedUnit string) string {
 	if parsedUnit != "" {
+		if unit := ucumUnit(parsedUnit); unit != "" {
+			return unit
+		}
 		return parsedUnit
 	}
 	lastUnderscoreIndex := strings.LastIndex(metricName, "_")
@@ -278,10 +289,15 @@ func heuristicalMetricAndKnownUnits(metricName, parsedUnit string) string {
 		return ""
 	}
 
+	return ucumUnit(metricName[lastUnderscoreIndex+1:])
+}
+
+// ucumUnit returns the UCUM unit of a unit name, or an empty string if it isn't known. Besides the usual
+// names and abbreviations, it covers the base units recommended by OpenMetrics.
+func ucumUnit(name string) string {
 	unit := ""
 
-	supposedUnit := metricName[lastUnderscoreIndex+1:]
-	switch strings.ToLower(supposedUnit) {
+	switch strings.ToLower(name) {
 	case "millisecond", "milliseconds", "ms":
 		unit = "ms"
 	case "second", "seconds", "s":
@@ -306,6 +322,22 @@ func heuristicalMetricAndKnownUnits(metricName, parsedUnit string) string {
 		unit = "mm"
 	case "nanogram", "ng", "nanograms":
 		unit = "ng"
+	case "ratio":
+		unit = "1"
+	case "percent":
+		unit = "%"
+	case "celsius":
+		unit = "Cel"
+	case "kelvin":
+		unit = "K"
+	case "volt", "volts":
+		unit = "V"
+	case "ampere", "amperes":
+		unit = "A"
+	case "joule", "joules":
+		unit = "J"
+	case "hertz":
+		unit = "Hz"
 	}
 
 	return unit
@@ -1443,6 +1443,11 @@ func Test_heuristicalMetricAndKnownUnits(t *testing.T) {
 		want       string
 	}{
 		{"test", "ms", "ms"},
+		{"test", "seconds", "s"},
+		{"test", "bytes", "By"},
+		{"test", "ratio", "1"},
+		{"test", "volts", "V"},
+		{"test_seconds", "furlongs", "furlongs"},
 		{"millisecond", "", ""},
 		{"test_millisecond", "", "ms"},
 		{"test_milliseconds", "", "ms"},
@@ -1485,6 +1490,11 @@ func Test_heuristicalMetricAndKnownUnits(t *testing.T) {
 		{"test_milimetre", "", "mm"},
 		{"test_milimetres", "", "mm"},
 		{"test_mm", "", "mm"},
+		{"test_ratio", "", "1"},
+		{"test_celsius", "", "Cel"},
+		{"test_amperes", "", "A"},
+		{"test_joules", "", "J"},
+		{"test_hertz", "", "Hz"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.metricName, func(t *testing.T) {
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/scrape"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+// Policies applied to metrics whose metadata is missing, e.g. because the target
+// restarted or was reloaded during the scrape.
+const (
+	// MissingMetadataGauge converts metrics without metadata to gauges.
+	MissingMetadataGauge = "gauge"
+	// MissingMetadataGaugeUnknownTargets converts metrics without metadata to gauges, as well
+	// as all the metrics of the targets which can't be found.
+	MissingMetadataGaugeUnknownTargets = "gauge_unknown_targets"
+	// MissingMetadataDrop drops the samples of metrics without metadata.
+	MissingMetadataDrop = "drop"
+)
+
+// missingMetadataHandler tracks the metrics without metadata appended during a single
+// transaction, reports them and applies the configured policy to their samples.
+type missingMetadataHandler struct {
+	policy     string
+	receiverID config.ComponentID
+	// keep caches, per metric name, whether its samples are kept.
+	keep map[string]bool
+	// unknownTargets is shared by the transactions of the receiver.
+	unknownTargets *unknownTargets
+}
+
+func newMissingMetadataHandler(policy string, receiverID config.ComponentID, unknownTargets *unknownTargets) *missingMetadataHandler {
+	return &missingMetadataHandler{
+		policy:         policy,
+		receiverID:     receiverID,
+		keep:           make(map[string]bool),
+		unknownTargets: unknownTargets,
+	}
+}
+
+// targetMetadata returns the metadata of the target of job and instance. Only when the target can't
+// be found and the policy is gauge_unknown_targets, an empty metadata cache is returned, so that all the metrics of
+// the target are converted to gauges. With deferUnknownTarget, errTargetNotResolved is returned for
+// such targets instead. Any other error, e.g. errAlreadyStopped, is returned as is.
+func (h *missingMetadataHandler) targetMetadata(ms metadataProvider, job, instance string, deferUnknownTarget bool, logger *zap.Logger) (MetadataCache, error) {
+	mc, err := ms.Get(job, instance)
+	var notFound *targetNotFoundError
+	if err == nil || !errors.As(err, &notFound) {
+		if err == nil {
+			h.unknownTargets.remove(job, instance)
+		}
+		return mc, err
+	}
+	if deferUnknownTarget {
+		return nil, errTargetNotResolved
+	}
+	if h.policy != MissingMetadataGaugeUnknownTargets {
+		return nil, err
+	}
+	if h.unknownTargets.add(job, instance) {
+		logger.Warn("Metadata of the target not found, its metrics are converted to gauges", zap.Error(err))
+	}
+	return emptyMetadataCache{}, nil
+}
+
+// check returns whether the sample for ls has to be appended, given the metadata of its target.
+func (h *missingMetadataHandler) check(ctx context.Context, mc MetadataCache, ls labels.Labels) bool {
+	metricName := ls.Get(model.MetricNameLabel)
+	if keep, ok := h.keep[metricName]; ok {
+		if !keep {
+			recordDroppedSeries(ctx, h.receiverID, ls.Get(model.JobLabel), dropReasonMissingMetadata, 1)
+		}
+		return keep
+	}
+	keep := true
+	if !hasMetadata(metricName, mc) {
+		_ = stats.RecordWithTags(
+			ctx,
+			[]tag.Mutator{tag.Upsert(tagReceiverKey, h.receiverID.String()), tag.Upsert(tagJobKey, ls.Get(model.JobLabel))},
+			statMissingMetadata.M(1),
+		)
+		keep = h.policy != MissingMetadataDrop
+	}
+	h.keep[metricName] = keep
+	if !keep {
+		recordDroppedSeries(ctx, h.receiverID, ls.Get(model.JobLabel), dropReasonMissingMetadata, 1)
+	}
+	return keep
+}
+
+// hasMetadata returns whether the metadata of metricName, or of the family it belongs to, is known.
+func hasMetadata(metricName string, mc MetadataCache) bool {
+	if _, ok := internalMetricMetadata[metricName]; ok {
+		return true
+	}
+	if _, ok := mc.Metadata(metricName); ok {
+		return true
+	}
+	_, ok := mc.Metadata(normalizeMetricName(metricName))
+	return ok
+}
+
+// maxUnknownTargets bounds the number of targets tracked by unknownTargets, which are all
+// forgotten once it is reached.
+const maxUnknownTargets = 1000
+
+// unknownTargets tracks the targets whose metadata couldn't be found, so that the conversion
+// of their metrics to gauges is logged once per target instead of on every scrape.
+type unknownTargets struct {
+	mu      sync.Mutex
+	targets map[string]struct{}
+}
+
+func newUnknownTargets() *unknownTargets {
+	return &unknownTargets{targets: make(map[string]struct{})}
+}
+
+// add tracks the target of job and instance, and returns whether it wasn't tracked yet.
+// A nil *unknownTargets tracks no target.
+func (u *unknownTargets) add(job, instance string) bool {
+	if u == nil {
+		return true
+	}
+	key := job + "\xff" + instance
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.targets[key]; ok {
+		return false
+	}
+	if len(u.targets) >= maxUnknownTargets {
+		u.targets = make(map[string]struct{})
+	}
+	u.targets[key] = struct{}{}
+	return true
+}
+
+// remove forgets the target of job and instance once its metadata is found.
+func (u *unknownTargets) remove(job, instance string) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.targets) > 0 {
+		delete(u.targets, job+"\xff"+instance)
+	}
+}
+
+// emptyMetadataCache is used for targets that can't be found, its metrics are all unknown-typed.
+type emptyMetadataCache struct{}
+
+func (emptyMetadataCache) Metadata(string) (scrape.MetricMetadata, bool) {
+	return scrape.MetricMetadata{}, false
+}
+
+func (emptyMetadataCache) SharedLabels() labels.Labels {
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type mockMetadataProvider struct {
+	mc  MetadataCache
+	err error
+}
+
+func (p *mockMetadataProvider) Get(string, string) (MetadataCache, error) {
+	return p.mc, p.err
+}
+
+func TestMissingMetadataTargetNotFound(t *testing.T) {
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	notFound := &targetNotFoundError{job: "test", instance: "localhost:8080"}
+
+	tests := []struct {
+		name    string
+		policy  string
+		err     error
+		wantErr error
+	}{
+		{name: "gauge", policy: MissingMetadataGauge, err: notFound, wantErr: notFound},
+		{name: "gauge_unknown_targets", policy: MissingMetadataGaugeUnknownTargets, err: notFound},
+		{name: "drop", policy: MissingMetadataDrop, err: notFound, wantErr: notFound},
+		{name: "gauge_unknown_targets stopped", policy: MissingMetadataGaugeUnknownTargets, err: errAlreadyStopped, wantErr: errAlreadyStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := &mockMetadataProvider{err: tt.err}
+			sink := new(consumertest.MetricsSink)
+			tr := newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")), receiverID: config.NewComponentID("prometheus"), ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), missingMetadata: tt.policy, resourceAttrKeys: DefaultResourceAttributeKeys})
+			_, err := tr.Append(0, ls, time.Now().Unix()*1000, 1.0)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 1, metrics.Len())
+			assert.Equal(t, "foo", metrics.At(0).Name())
+			assert.Equal(t, pdata.MetricDataTypeGauge, metrics.At(0).DataType())
+		})
+	}
+}
+
+func TestMissingMetadataTargetNotFoundLoggedOnce(t *testing.T) {
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	ms := &mockMetadataProvider{err: &targetNotFoundError{job: "test", instance: "localhost:8080"}}
+	core, logs := observer.New(zap.WarnLevel)
+	set := componenttest.NewNopReceiverCreateSettings()
+	set.Logger = zap.New(core)
+	unknown := newUnknownTargets()
+
+	for i := 0; i < 3; i++ {
+		tr := newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")), receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), settings: set, missingMetadata: MissingMetadataGaugeUnknownTargets, unknownTargets: unknown, resourceAttrKeys: DefaultResourceAttributeKeys})
+		_, err := tr.Append(0, ls, time.Now().Unix()*1000, 1.0)
+		require.NoError(t, err)
+		require.NoError(t, tr.Commit())
+	}
+	assert.Equal(t, 1, logs.Len())
+
+	// The target is logged again if it's missing again after its metadata was found.
+	unknown.remove("test", "localhost:8080")
+	assert.True(t, unknown.add("test", "localhost:8080"))
+	assert.False(t, unknown.add("test", "localhost:8080"))
+}
+
+func TestMissingMetadataDrop(t *testing.T) {
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"counter": {Metric: "counter", Type: textparse.MetricTypeCounter},
+	})}
+	known := labels.FromStrings(model.MetricNameLabel, "counter", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	unknown := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+	sink := new(consumertest.MetricsSink)
+	tr := newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")), receiverID: config.NewComponentID("prometheus"), ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), missingMetadata: MissingMetadataDrop, resourceAttrKeys: DefaultResourceAttributeKeys})
+	ts := time.Now().Unix() * 1000
+	_, err := tr.Append(0, known, ts, 1.0)
+	require.NoError(t, err)
+	_, err = tr.Append(0, unknown, ts, 1.0)
+	require.NoError(t, err)
+	require.NoError(t, tr.Commit())
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	metrics := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "counter", metrics.At(0).Name())
+}
+
+func TestMissingMetadataMetric(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	mc := newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"counter": {Metric: "counter", Type: textparse.MetricTypeCounter},
+	})
+	h := newMissingMetadataHandler(MissingMetadataGauge, config.NewComponentID("prometheus"), nil)
+	for _, name := range []string{"counter", "foo", "foo", "bar", "up"} {
+		ls := labels.FromStrings(model.MetricNameLabel, name, model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+		assert.True(t, h.check(context.Background(), mc, ls))
+	}
+
+	rows, err := view.RetrieveData(statMissingMetadata.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+	assert.ElementsMatch(t, []string{"prometheus", "test"}, []string{rows[0].Tags[0].Value, rows[0].Tags[1].Value})
+}
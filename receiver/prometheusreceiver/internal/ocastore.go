@@ -17,9 +17,11 @@ package internal // import "github.com/open-telemetry/opentelemetry-collector-co
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/scrape"
@@ -51,10 +53,44 @@ type OcaStore struct {
 	receiverID           config.ComponentID
 	externalLabels       labels.Labels
 	pdataDirect          bool
+	opts                 OcaStoreOptions
+	unknownTargets       *unknownTargets
+	clampedSeries        *clampedSeries
+
+	// inFlightMu guards the transactions of the scrapes in flight, which are waited for by Drain.
+	inFlightMu sync.Mutex
+	inFlight   int
+	draining   bool
+	drained    chan struct{}
 
 	settings component.ReceiverCreateSettings
 }
 
+// OcaStoreOptions are the settings of the receiver applied to the samples appended to an
+// OcaStore, the zero value disables all of them.
+type OcaStoreOptions struct {
+	// JobsMapMaxEntries bounds the number of timeseries tracked to adjust the cumulative
+	// metrics, 0 means unbounded.
+	JobsMapMaxEntries int
+	DuplicateSamples  string
+	MissingMetadata   string
+	TargetLabels      []string
+	// HonorLabelsJobs maps the name of every scrape config to its honor_labels, it is nil
+	// when no scrape config honors labels.
+	HonorLabelsJobs map[string]bool
+	InfoMetrics     string
+	TraceScrapes    bool
+	// LabelValueMaxLength disables the label value limit when 0.
+	LabelValueMaxLength int
+	LabelValueAction    string
+	JobResourceAttrs    map[string]map[string]string
+	ResourceAttrKeys    ResourceAttributeKeys
+	// SampleMaxAge and SampleMaxFuture disable the sample age limit when both are 0.
+	SampleMaxAge    time.Duration
+	SampleMaxFuture time.Duration
+	SampleAgeAction string
+}
+
 // NewOcaStore returns an ocaStore instance, which can be acted as prometheus' scrape.Appendable
 func NewOcaStore(
 	ctx context.Context,
@@ -65,10 +101,11 @@ func NewOcaStore(
 	startTimeMetricRegex string,
 	receiverID config.ComponentID,
 	externalLabels labels.Labels,
-	pdataDirect bool) *OcaStore {
+	pdataDirect bool,
+	opts OcaStoreOptions) *OcaStore {
 	var jobsMap *JobsMapPdata
 	if !useStartTimeMetric {
-		jobsMap = NewJobsMapPdata(gcInterval)
+		jobsMap = NewJobsMapPdata(gcInterval, opts.JobsMapMaxEntries, receiverID)
 	}
 	return &OcaStore{
 		running:              runningStateInit,
@@ -81,6 +118,10 @@ func NewOcaStore(
 		receiverID:           receiverID,
 		externalLabels:       externalLabels,
 		pdataDirect:          pdataDirect,
+		opts:                 opts,
+		unknownTargets:       newUnknownTargets(),
+		clampedSeries:        newClampedSeries(),
+		drained:              make(chan struct{}),
 	}
 }
 
@@ -92,35 +133,41 @@ func (o *OcaStore) SetScrapeManager(scrapeManager *scrape.Manager) {
 	}
 }
 
+// WatchTargetUpdates forwards the target updates sent by discovery to the returned
+// channel, which should be passed to scrape.Manager.Run, and invalidates the cached
+// target metadata on every update. Forwarding stops when ctx is done.
+func (o *OcaStore) WatchTargetUpdates(ctx context.Context, tsets <-chan map[string][]*targetgroup.Group) <-chan map[string][]*targetgroup.Group {
+	out := make(chan map[string][]*targetgroup.Group)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ts := <-tsets:
+				if o.mc != nil {
+					o.mc.refresh()
+				}
+				select {
+				case out <- ts:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
 func (o *OcaStore) Appender(context.Context) storage.Appender {
 	state := atomic.LoadInt32(&o.running)
 	if state == runningStateReady {
-		if o.pdataDirect {
-			return newTransactionPdata(
-				o.ctx,
-				&txConfig{
-					jobsMap:              o.jobsMap,
-					useStartTimeMetric:   o.useStartTimeMetric,
-					startTimeMetricRegex: o.startTimeMetricRegex,
-					receiverID:           o.receiverID,
-					ms:                   o.mc,
-					sink:                 o.sink,
-					externalLabels:       o.externalLabels,
-					settings:             o.settings,
-				},
-			)
+		o.inFlightMu.Lock()
+		defer o.inFlightMu.Unlock()
+		if o.draining {
+			return noop
 		}
-		return newTransaction(
-			o.ctx,
-			o.jobsMap,
-			o.useStartTimeMetric,
-			o.startTimeMetricRegex,
-			o.receiverID,
-			o.mc,
-			o.sink,
-			o.externalLabels,
-			o.settings,
-		)
+		o.inFlight++
+		return &inFlightAppender{Appender: o.newAppender(o.ctx, o.mc), store: o}
 	} else if state == runningStateInit {
 		panic("ScrapeManager is not set")
 	}
@@ -128,6 +175,78 @@ func (o *OcaStore) Appender(context.Context) storage.Appender {
 	return noop
 }
 
+// remoteWriteAppender returns an appender for series received through remote write, whose
+// metadata is provided by ms instead of the scrape targets.
+func (o *OcaStore) remoteWriteAppender(ctx context.Context, ms metadataProvider) storage.Appender {
+	if atomic.LoadInt32(&o.running) != runningStateReady {
+		return noop
+	}
+	return o.newAppender(ctx, ms)
+}
+
+func (o *OcaStore) newAppender(ctx context.Context, ms metadataProvider) storage.Appender {
+	txc := &txConfig{
+		jobsMap:              o.jobsMap,
+		useStartTimeMetric:   o.useStartTimeMetric,
+		startTimeMetricRegex: o.startTimeMetricRegex,
+		receiverID:           o.receiverID,
+		ms:                   ms,
+		sink:                 o.sink,
+		externalLabels:       o.externalLabels,
+		settings:             o.settings,
+		duplicateSamples:     o.opts.DuplicateSamples,
+		missingMetadata:      o.opts.MissingMetadata,
+		unknownTargets:       o.unknownTargets,
+		clampedSeries:        o.clampedSeries,
+		targetLabels:         o.opts.TargetLabels,
+		honorLabelsJobs:      o.opts.HonorLabelsJobs,
+		infoMetrics:          o.opts.InfoMetrics,
+		traceScrapes:         o.opts.TraceScrapes,
+		labelValueMaxLength:  o.opts.LabelValueMaxLength,
+		labelValueAction:     o.opts.LabelValueAction,
+		jobResourceAttrs:     o.opts.JobResourceAttrs,
+		resourceAttrKeys:     o.opts.ResourceAttrKeys,
+		sampleMaxAge:         o.opts.SampleMaxAge,
+		sampleMaxFuture:      o.opts.SampleMaxFuture,
+		sampleAgeAction:      o.opts.SampleAgeAction,
+	}
+	if o.pdataDirect {
+		return newTransactionPdata(ctx, txc)
+	}
+	return newTransaction(ctx, txc)
+}
+
+// Drain stops handing out appenders to new scrapes, and waits until the transactions of the
+// scrapes in flight are committed or rolled back, or ctx is done. It returns the number of
+// transactions still in flight.
+func (o *OcaStore) Drain(ctx context.Context) int {
+	o.inFlightMu.Lock()
+	o.draining = true
+	inFlight := o.inFlight
+	o.inFlightMu.Unlock()
+	if inFlight == 0 {
+		return 0
+	}
+	select {
+	case <-o.drained:
+		return 0
+	case <-ctx.Done():
+		o.inFlightMu.Lock()
+		defer o.inFlightMu.Unlock()
+		return o.inFlight
+	}
+}
+
+// transactionDone is called once the transaction of a scrape is committed or rolled back.
+func (o *OcaStore) transactionDone() {
+	o.inFlightMu.Lock()
+	defer o.inFlightMu.Unlock()
+	o.inFlight--
+	if o.draining && o.inFlight == 0 {
+		close(o.drained)
+	}
+}
+
 // Close OcaStore as well as the internal metadataService.
 func (o *OcaStore) Close() {
 	if atomic.CompareAndSwapInt32(&o.running, runningStateReady, runningStateStop) {
@@ -135,6 +254,24 @@ func (o *OcaStore) Close() {
 	}
 }
 
+// inFlightAppender tracks the transaction of a scrape until it is committed or rolled back.
+// Only the first call to Commit or Rollback ends it.
+type inFlightAppender struct {
+	storage.Appender
+	store *OcaStore
+	once  sync.Once
+}
+
+func (a *inFlightAppender) Commit() error {
+	defer a.once.Do(a.store.transactionDone)
+	return a.Appender.Commit()
+}
+
+func (a *inFlightAppender) Rollback() error {
+	defer a.once.Do(a.store.transactionDone)
+	return a.Appender.Rollback()
+}
+
 // noopAppender, always return error on any operations
 type noopAppender struct{}
 
@@ -27,7 +27,7 @@ import (
 )
 
 func TestOcaStore(t *testing.T) {
-	o := NewOcaStore(context.Background(), nil, testTelemetry.ToReceiverCreateSettings(), 2*time.Minute, false, "", config.NewComponentID("prometheus"), nil, false)
+	o := NewOcaStore(context.Background(), nil, testTelemetry.ToReceiverCreateSettings(), 2*time.Minute, false, "", config.NewComponentID("prometheus"), nil, false, OcaStoreOptions{ResourceAttrKeys: DefaultResourceAttributeKeys})
 	o.SetScrapeManager(&scrape.Manager{})
 
 	app := o.Appender(context.Background())
@@ -39,6 +39,31 @@ func TestOcaStore(t *testing.T) {
 	assert.Equal(t, noop, app)
 }
 
+func TestOcaStoreDrain(t *testing.T) {
+	o := NewOcaStore(context.Background(), nil, testTelemetry.ToReceiverCreateSettings(), 2*time.Minute, false, "", config.NewComponentID("prometheus"), nil, false, OcaStoreOptions{ResourceAttrKeys: DefaultResourceAttributeKeys})
+	o.SetScrapeManager(&scrape.Manager{})
+
+	committed := o.Appender(context.Background())
+	rolledBack := o.Appender(context.Background())
+	require.NoError(t, rolledBack.Rollback())
+	assert.Equal(t, 1, o.inFlight)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 1, o.Drain(ctx))
+	assert.Equal(t, noop, o.Appender(context.Background()), "no appender is handed out while draining")
+
+	drained := make(chan int)
+	go func() {
+		drained <- o.Drain(context.Background())
+	}()
+	require.NoError(t, committed.Commit())
+	require.NoError(t, committed.Rollback(), "a transaction only ends once")
+	assert.Equal(t, 0, <-drained)
+	assert.Equal(t, 0, o.inFlight)
+	o.Close()
+}
+
 func TestNoopAppender(t *testing.T) {
 	if _, err := noop.Append(0, labels.FromStrings("t", "v"), 1, 1); err == nil {
 		t.Error("expecting error from Add method of noopApender")
@@ -286,7 +286,7 @@ func (mf *metricFamilyPdata) Add(metricName string, ls labels.Labels, t int64, v
 				mf.droppedTimeseries++
 				return err
 			}
-			mg.complexValue = append(mg.complexValue, &dataPoint{value: v, boundary: boundary})
+			mg.complexValue = addComplexValue(mg.complexValue, v, boundary)
 		}
 	default:
 		mg.value = v
@@ -308,6 +308,7 @@ func (mf *metricFamilyPdata) ToMetricPdata(metrics *pdata.MetricSlice) (int, int
 	metric := pdata.NewMetric()
 	metric.SetDataType(mf.mtype)
 	metric.SetName(mf.name)
+	metric.SetUnit(heuristicalMetricAndKnownUnits(mf.name, mf.metadata.Unit))
 
 	pointCount := 0
 
@@ -341,3 +341,41 @@ func TestMetricGroupData_toNumberDataUnitTest(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricFamilyPdata_unit(t *testing.T) {
+	mc := byLookupMetadataCache{
+		"a_seconds": scrape.MetricMetadata{
+			Metric: "a_seconds",
+			Type:   textparse.MetricTypeGauge,
+			Unit:   "seconds",
+		},
+		"b_celsius": scrape.MetricMetadata{
+			Metric: "b_celsius",
+			Type:   textparse.MetricTypeGauge,
+		},
+		"c_furlongs": scrape.MetricMetadata{
+			Metric: "c_furlongs",
+			Type:   textparse.MetricTypeGauge,
+			Unit:   "furlongs",
+		},
+	}
+	tests := []struct {
+		metricName string
+		want       string
+	}{
+		{metricName: "a_seconds", want: "s"},
+		{metricName: "b_celsius", want: "Cel"},
+		{metricName: "c_furlongs", want: "furlongs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metricName, func(t *testing.T) {
+			mp := newMetricFamilyPdata(tt.metricName, mc, zap.NewNop())
+			require.NoError(t, mp.Add(tt.metricName, labels.Labels{{Name: "a", Value: "A"}}, 11, 1))
+
+			metrics := pdata.NewMetricSlice()
+			mp.ToMetricPdata(&metrics)
+			require.Equal(t, 1, metrics.Len())
+			require.Equal(t, tt.want, metrics.At(0).Unit())
+		})
+	}
+}
@@ -15,11 +15,16 @@
 package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
 
 import (
+	"container/list"
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 )
@@ -45,6 +50,10 @@ import (
 // the JobsMap is locked and any timeseriesMaps that are unmarked are removed from the JobsMap
 // otherwise the timeseriesMap is gc'd
 //
+// If the JobsMap is limited to a maximum number of entries, the least recently accessed
+// timeseriesMap is evicted before a new one is added to a full JobsMap, so that the memory used
+// by the JobsMap stays bounded even when targets churn faster than the gcInterval.
+//
 // The gc for the timeseriesMap is straightforward - the map is locked and, for each timeseriesinfo
 // in the map, if it has not been marked, it is removed otherwise it is unmarked.
 //
@@ -59,11 +68,35 @@ import (
 //    timeseriesMap so the current approach is used instead.
 
 // timeseriesinfo contains the information necessary to adjust from the initial point and to detect
-// resets.
+// resets. The values of the previous point are copied, rather than referenced, so that the detection
+// doesn't depend on the order of the points in the scrapes nor on the metrics sent to the next consumers.
 type timeseriesinfoPdata struct {
-	mark     bool
-	initial  *pdata.Metric
-	previous *pdata.Metric
+	mark bool
+	// initialized is set once a point with a recorded value of the timeseries was adjusted.
+	initialized bool
+	// startTime is the start time of the timeseries, the one of its initial point or of its last reset.
+	startTime pdata.Timestamp
+	// previous holds the values of the last point with a recorded value, the staleness markers, e.g.
+	// of failed scrapes, don't reset the timeseries if its values didn't decrease.
+	previous pointValuesPdata
+}
+
+// pointValuesPdata holds the values of a point compared to the ones of the next point to detect resets.
+type pointValuesPdata struct {
+	value        float64
+	count        uint64
+	sum          float64
+	bounds       []float64
+	bucketCounts []uint64
+}
+
+// copyFrom copies the values of src, reusing the slices of v.
+func (v *pointValuesPdata) copyFrom(src pointValuesPdata) {
+	v.value = src.value
+	v.count = src.count
+	v.sum = src.sum
+	v.bounds = append(v.bounds[:0], src.bounds...)
+	v.bucketCounts = append(v.bucketCounts[:0], src.bucketCounts...)
 }
 
 // timeseriesMap maps from a timeseries instance (metric * label values) to the timeseries info for
@@ -75,6 +108,12 @@ type timeseriesMapPdata struct {
 
 	mark   bool
 	tsiMap map[string]*timeseriesinfoPdata
+	// lruElement is the element of the timeseriesMap in the recently used list of the JobsMap,
+	// nil if the JobsMap isn't limited or once it's removed. It's protected by the lruMu of the JobsMap.
+	lruElement *list.Element
+	// receiverID and job tag the counter resets detected in the timeseries.
+	receiverID config.ComponentID
+	job        string
 }
 
 // Get the timeseriesinfo for the timeseries associated with the metric and label values.
@@ -144,11 +183,30 @@ type JobsMapPdata struct {
 	gcInterval time.Duration
 	lastGC     time.Time
 	jobsMap    map[string]*timeseriesMapPdata
+	// maxEntries is the maximum number of entries of jobsMap, 0 means unlimited.
+	maxEntries int
+	// lru holds the signatures of the entries of jobsMap from the most to the least recently
+	// accessed if maxEntries is set. It's protected by lruMu rather than by the mutex of the
+	// JobsMap, which get() only holds as a reader when the entry exists.
+	lruMu      sync.Mutex
+	lru        *list.List
+	receiverID config.ComponentID
 }
 
-// NewJobsMap creates a new (empty) JobsMapPdata.
-func NewJobsMapPdata(gcInterval time.Duration) *JobsMapPdata {
-	return &JobsMapPdata{gcInterval: gcInterval, lastGC: time.Now(), jobsMap: make(map[string]*timeseriesMapPdata)}
+// NewJobsMapPdata creates a new (empty) JobsMapPdata, holding at most maxEntries job instances
+// unless maxEntries is 0.
+func NewJobsMapPdata(gcInterval time.Duration, maxEntries int, receiverID config.ComponentID) *JobsMapPdata {
+	jm := &JobsMapPdata{
+		gcInterval: gcInterval,
+		lastGC:     time.Now(),
+		jobsMap:    make(map[string]*timeseriesMapPdata),
+		maxEntries: maxEntries,
+		receiverID: receiverID,
+	}
+	if maxEntries > 0 {
+		jm.lru = list.New()
+	}
+	return jm
 }
 
 // Remove jobs and timeseries that have aged out.
@@ -157,6 +215,7 @@ func (jm *JobsMapPdata) gc() {
 	defer jm.Unlock()
 	// once the structure is locked, confirm that gc() is still necessary
 	if time.Since(jm.lastGC) > jm.gcInterval {
+		evicted := 0
 		for sig, tsm := range jm.jobsMap {
 			tsm.RLock()
 			tsmNotMarked := !tsm.mark
@@ -164,13 +223,90 @@ func (jm *JobsMapPdata) gc() {
 			tsm.RUnlock()
 			if tsmNotMarked {
 				delete(jm.jobsMap, sig)
+				jm.removeRecentlyUsed(tsm)
+				evicted++
 			} else {
 				// a full lock will be obtained in here, if required.
 				tsm.gc()
 			}
 		}
 		jm.lastGC = time.Now()
+		jm.recordEvictions(evictionReasonGC, evicted)
+		jm.recordSize()
+	}
+}
+
+// evictLeastRecentlyUsed removes the least recently accessed entry of the JobsMapPdata,
+// the caller has to hold the lock.
+func (jm *JobsMapPdata) evictLeastRecentlyUsed() {
+	jm.lruMu.Lock()
+	defer jm.lruMu.Unlock()
+	oldest := jm.lru.Back()
+	if oldest == nil {
+		return
+	}
+	sig := jm.lru.Remove(oldest).(string)
+	jm.jobsMap[sig].lruElement = nil
+	delete(jm.jobsMap, sig)
+	jm.recordEvictions(evictionReasonMaxEntries, 1)
+}
+
+// addRecentlyUsed adds the new entry tsm of sig as the most recently accessed one, the caller
+// has to hold the lock.
+func (jm *JobsMapPdata) addRecentlyUsed(sig string, tsm *timeseriesMapPdata) {
+	if jm.lru == nil {
+		return
+	}
+	jm.lruMu.Lock()
+	tsm.lruElement = jm.lru.PushFront(sig)
+	jm.lruMu.Unlock()
+}
+
+// markRecentlyUsed moves tsm to the front of the recently used entries. It only requires the
+// read lock, tsm is skipped if it was removed in the meantime.
+func (jm *JobsMapPdata) markRecentlyUsed(tsm *timeseriesMapPdata) {
+	if jm.lru == nil {
+		return
+	}
+	jm.lruMu.Lock()
+	if tsm.lruElement != nil {
+		jm.lru.MoveToFront(tsm.lruElement)
+	}
+	jm.lruMu.Unlock()
+}
+
+// removeRecentlyUsed removes tsm from the recently used entries, the caller has to hold the lock.
+func (jm *JobsMapPdata) removeRecentlyUsed(tsm *timeseriesMapPdata) {
+	if jm.lru == nil {
+		return
+	}
+	jm.lruMu.Lock()
+	if tsm.lruElement != nil {
+		jm.lru.Remove(tsm.lruElement)
+		tsm.lruElement = nil
+	}
+	jm.lruMu.Unlock()
+}
+
+// recordEvictions reports the number of entries evicted from the JobsMapPdata for reason.
+func (jm *JobsMapPdata) recordEvictions(reason string, evicted int) {
+	if evicted == 0 {
+		return
 	}
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, jm.receiverID.String()), tag.Upsert(tagReasonKey, reason)},
+		statJobsMapEvictions.M(int64(evicted)),
+	)
+}
+
+// recordSize reports the number of entries of the JobsMapPdata, the caller has to hold the lock.
+func (jm *JobsMapPdata) recordSize() {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, jm.receiverID.String())},
+		statJobsMapSize.M(int64(len(jm.jobsMap))),
+	)
 }
 
 func (jm *JobsMapPdata) maybeGC() {
@@ -190,6 +326,7 @@ func (jm *JobsMapPdata) get(job, instance string) *timeseriesMapPdata {
 	jm.RUnlock()
 	defer jm.maybeGC()
 	if ok {
+		jm.markRecentlyUsed(tsm)
 		return tsm
 	}
 	jm.Lock()
@@ -198,10 +335,18 @@ func (jm *JobsMapPdata) get(job, instance string) *timeseriesMapPdata {
 	// and then create a new timeseriesMap if required.
 	tsm2, ok2 := jm.jobsMap[sig]
 	if ok2 {
+		jm.markRecentlyUsed(tsm2)
 		return tsm2
 	}
+	if jm.maxEntries > 0 && len(jm.jobsMap) >= jm.maxEntries {
+		jm.evictLeastRecentlyUsed()
+	}
 	tsm2 = newTimeseriesMapPdata()
+	tsm2.receiverID = jm.receiverID
+	tsm2.job = job
 	jm.jobsMap[sig] = tsm2
+	jm.addRecentlyUsed(sig, tsm2)
+	jm.recordSize()
 	return tsm2
 }
 
@@ -211,6 +356,8 @@ func (jm *JobsMapPdata) get(job, instance string) *timeseriesMapPdata {
 type MetricsAdjusterPdata struct {
 	tsm    *timeseriesMapPdata
 	logger *zap.Logger
+	// resets counts the counter resets detected by reason, they are recorded once the metrics are adjusted.
+	resets map[string]int
 }
 
 // NewMetricsAdjuster is a constructor for MetricsAdjuster.
@@ -218,6 +365,7 @@ func NewMetricsAdjusterPdata(tsm *timeseriesMapPdata, logger *zap.Logger) *Metri
 	return &MetricsAdjusterPdata{
 		tsm:    tsm,
 		logger: logger,
+		resets: map[string]int{},
 	}
 }
 
@@ -234,6 +382,7 @@ func (ma *MetricsAdjusterPdata) AdjustMetricSlice(metricL *pdata.MetricSlice) in
 		metric := metricL.At(i)
 		resets += ma.adjustMetric(&metric)
 	}
+	ma.recordResets()
 	return resets
 }
 
@@ -256,25 +405,33 @@ func (ma *MetricsAdjusterPdata) AdjustMetrics(metrics *pdata.Metrics) int {
 			}
 		}
 	}
+	ma.recordResets()
 	return resets
 }
 
-// Returns the number of timeseries with reset start times.
-func (ma *MetricsAdjusterPdata) adjustMetric(metric *pdata.Metric) int {
-	switch metric.DataType() {
-	case pdata.MetricDataTypeGauge:
-		// gauges don't need to be adjusted so no additional processing is necessary
-		return 0
-	default:
-		return ma.adjustMetricPoints(metric)
+// recordResets reports the counter resets detected since the previous call, the initial points of the
+// timeseries aren't counted.
+func (ma *MetricsAdjusterPdata) recordResets() {
+	for reason, resets := range ma.resets {
+		_ = stats.RecordWithTags(
+			context.Background(),
+			[]tag.Mutator{
+				tag.Upsert(tagReceiverKey, ma.tsm.receiverID.String()),
+				tag.Upsert(tagJobKey, ma.tsm.job),
+				tag.Upsert(tagReasonKey, reason),
+			},
+			statCounterResets.M(int64(resets)),
+		)
+		delete(ma.resets, reason)
 	}
 }
 
-// Returns  the number of timeseries that had reset start times.
-func (ma *MetricsAdjusterPdata) adjustMetricPoints(metric *pdata.Metric) int {
+// Returns the number of timeseries with reset start times.
+func (ma *MetricsAdjusterPdata) adjustMetric(metric *pdata.Metric) int {
 	switch dataType := metric.DataType(); dataType {
 	case pdata.MetricDataTypeGauge:
-		return ma.adjustMetricGauge(metric)
+		// gauges don't need to be adjusted so no additional processing is necessary
+		return 0
 
 	case pdata.MetricDataTypeHistogram:
 		return ma.adjustMetricHistogram(metric)
@@ -292,46 +449,45 @@ func (ma *MetricsAdjusterPdata) adjustMetricPoints(metric *pdata.Metric) int {
 	}
 }
 
-// Returns true if 'current' was adjusted and false if 'current' is an the initial occurrence or a
-// reset of the timeseries.
-func (ma *MetricsAdjusterPdata) adjustMetricGauge(current *pdata.Metric) (resets int) {
-	currentPoints := current.Gauge().DataPoints()
+// cumulativePointPdata is implemented by the points of the cumulative metrics.
+type cumulativePointPdata interface {
+	StartTimestamp() pdata.Timestamp
+	SetStartTimestamp(pdata.Timestamp)
+	Flags() pdata.MetricDataPointFlags
+}
 
-	for i := 0; i < currentPoints.Len(); i++ {
-		currentGauge := currentPoints.At(i)
-		tsi := ma.tsm.get(current, currentGauge.Attributes())
-		previous := tsi.previous
-		tsi.previous = current
-		if tsi.initial == nil {
-			// initial || reset timeseries.
-			tsi.initial = current
-			resets++
-			continue
-		}
-		initialPoints := tsi.initial.Gauge().DataPoints()
-		previousPoints := previous.Gauge().DataPoints()
-		if i >= initialPoints.Len() || i >= previousPoints.Len() {
-			ma.logger.Debug("Adjusting Points, all lengths should be equal",
-				zap.Int("len(current)", currentPoints.Len()),
-				zap.Int("len(initial)", initialPoints.Len()),
-				zap.Int("len(previous)", previousPoints.Len()))
-			// initial || reset timeseries.
-			tsi.initial = current
-			resets++
-			continue
+// adjustPoint sets the start time of point, whose values are current, to the start time of the
+// timeseries tsi, unless it's the initial point or a reset of the timeseries. resetReason returns
+// the reason of the reset of the timeseries between the previous and the current values, or an
+// empty string if the timeseries wasn't reset.
+// Returns true if point is the initial point or a reset of the timeseries.
+func (ma *MetricsAdjusterPdata) adjustPoint(tsi *timeseriesinfoPdata, point cumulativePointPdata, current pointValuesPdata,
+	resetReason func(previous, current *pointValuesPdata) string) bool {
+	if point.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
+		// staleness markers have no values to compare to the next points.
+		if tsi.initialized {
+			point.SetStartTimestamp(tsi.startTime)
 		}
+		return false
+	}
 
-		currentGauge, previousGauge := currentPoints.At(i), previousPoints.At(i)
-		if currentGauge.DoubleVal() < previousGauge.DoubleVal() {
-			// reset detected
-			tsi.initial = current
-			continue
+	reason := ""
+	if tsi.initialized {
+		reason = resetReason(&tsi.previous, &current)
+	}
+	tsi.previous.copyFrom(current)
+
+	if !tsi.initialized || reason != "" {
+		// initial || reset timeseries.
+		tsi.initialized = true
+		tsi.startTime = point.StartTimestamp()
+		if reason != "" {
+			ma.resets[reason]++
 		}
-		initialGauge := initialPoints.At(i)
-		currentGauge.SetStartTimestamp(initialGauge.StartTimestamp())
-		resets++
+		return true
 	}
-	return
+	point.SetStartTimestamp(tsi.startTime)
+	return false
 }
 
 func (ma *MetricsAdjusterPdata) adjustMetricHistogram(current *pdata.Metric) (resets int) {
@@ -343,145 +499,89 @@ func (ma *MetricsAdjusterPdata) adjustMetricHistogram(current *pdata.Metric) (re
 
 	// Note: Sum of Squared Deviation not currently supported.
 	currentPoints := histogram.DataPoints()
-
-	if currentPoints.Len() == 0 {
-		return 0
-	}
-
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentDist := currentPoints.At(i)
 		tsi := ma.tsm.get(current, currentDist.Attributes())
-		previous := tsi.previous
-		if !currentDist.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			tsi.previous = current
+		values := pointValuesPdata{
+			count:        currentDist.Count(),
+			sum:          currentDist.Sum(),
+			bounds:       currentDist.ExplicitBounds(),
+			bucketCounts: currentDist.BucketCounts(),
 		}
-		if tsi.initial == nil {
-			// initial || reset timeseries.
-			tsi.initial = current
+		if ma.adjustPoint(tsi, currentDist, values, histogramResetReason) {
 			resets++
-			continue
 		}
-		initialPoints := tsi.initial.Histogram().DataPoints()
-		previousPoints := previous.Histogram().DataPoints()
-		if i >= initialPoints.Len() || i >= previousPoints.Len() {
-			ma.logger.Debug("Adjusting Points, all lengths should be equal",
-				zap.Int("len(current)", currentPoints.Len()),
-				zap.Int("len(initial)", initialPoints.Len()),
-				zap.Int("len(previous)", previousPoints.Len()))
-			// initial || reset timeseries.
-			tsi.initial = current
-			resets++
-			continue
-		}
-		if currentDist.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			currentDist.SetStartTimestamp(initialPoints.At(i).StartTimestamp())
-			continue
+	}
+	return
+}
+
+// histogramResetReason detects the resets of histograms from their count, sum and bucket counts, so
+// that a restart whose count caught up with the previous one is still detected when the
+// distribution of its observations differs.
+func histogramResetReason(previous, current *pointValuesPdata) string {
+	if current.count < previous.count || current.sum < previous.sum {
+		return resetReasonDecrease
+	}
+	if !equalBounds(previous.bounds, current.bounds) || len(previous.bucketCounts) != len(current.bucketCounts) {
+		return resetReasonBucketLayout
+	}
+	for i, count := range current.bucketCounts {
+		if count < previous.bucketCounts[i] {
+			return resetReasonDecrease
 		}
-		previousDist := previousPoints.At(i)
-		if currentDist.Count() < previousDist.Count() || currentDist.Sum() < previousDist.Sum() {
-			// reset detected
-			tsi.initial = current
-			resets++
-			continue
+	}
+	return ""
+}
+
+func equalBounds(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
 		}
-		initialDist := initialPoints.At(i)
-		currentDist.SetStartTimestamp(initialDist.StartTimestamp())
 	}
-	return
+	return true
 }
 
 func (ma *MetricsAdjusterPdata) adjustMetricSum(current *pdata.Metric) (resets int) {
 	currentPoints := current.Sum().DataPoints()
-
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentSum := currentPoints.At(i)
 		tsi := ma.tsm.get(current, currentSum.Attributes())
-		previous := tsi.previous
-		if !currentSum.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			tsi.previous = current
-		}
-		if tsi.initial == nil {
-			// initial || reset timeseries.
-			tsi.initial = current
-			resets++
-			continue
-		}
-		initialPoints := tsi.initial.Sum().DataPoints()
-		previousPoints := previous.Sum().DataPoints()
-		if i >= initialPoints.Len() || i >= previousPoints.Len() {
-			ma.logger.Debug("Adjusting Points, all lengths should be equal",
-				zap.Int("len(current)", currentPoints.Len()),
-				zap.Int("len(initial)", initialPoints.Len()),
-				zap.Int("len(previous)", previousPoints.Len()))
-			tsi.initial = current
+		if ma.adjustPoint(tsi, currentSum, pointValuesPdata{value: currentSum.DoubleVal()}, sumResetReason) {
 			resets++
-			continue
-		}
-		if currentSum.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			currentSum.SetStartTimestamp(initialPoints.At(i).StartTimestamp())
-			continue
 		}
-		previousSum := previousPoints.At(i)
-		if currentSum.DoubleVal() < previousSum.DoubleVal() {
-			// reset detected
-			tsi.initial = current
-			resets++
-			continue
-		}
-		initialSum := initialPoints.At(i)
-		currentSum.SetStartTimestamp(initialSum.StartTimestamp())
 	}
-
 	return
 }
 
+func sumResetReason(previous, current *pointValuesPdata) string {
+	if current.value < previous.value {
+		return resetReasonDecrease
+	}
+	return ""
+}
+
 func (ma *MetricsAdjusterPdata) adjustMetricSummary(current *pdata.Metric) (resets int) {
 	currentPoints := current.Summary().DataPoints()
-
 	for i := 0; i < currentPoints.Len(); i++ {
 		currentSummary := currentPoints.At(i)
 		tsi := ma.tsm.get(current, currentSummary.Attributes())
-		previous := tsi.previous
-		if !currentSummary.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			tsi.previous = current
-		}
-		if tsi.initial == nil {
-			// initial || reset timeseries.
-			tsi.initial = current
-			resets++
-			continue
-		}
-		initialPoints := tsi.initial.Summary().DataPoints()
-		previousPoints := previous.Summary().DataPoints()
-		if i >= initialPoints.Len() || i >= previousPoints.Len() {
-			ma.logger.Debug("Adjusting Points, all lengths should be equal",
-				zap.Int("len(current)", currentPoints.Len()),
-				zap.Int("len(initial)", initialPoints.Len()),
-				zap.Int("len(previous)", previousPoints.Len()))
-			tsi.initial = current
+		values := pointValuesPdata{count: currentSummary.Count(), sum: currentSummary.Sum()}
+		if ma.adjustPoint(tsi, currentSummary, values, summaryResetReason) {
 			resets++
-			continue
-		}
-		if currentSummary.Flags().HasFlag(pdata.MetricDataPointFlagNoRecordedValue) {
-			currentSummary.SetStartTimestamp(initialPoints.At(i).StartTimestamp())
-			continue
 		}
-		previousSummary := previousPoints.At(i)
-		if (currentSummary.Count() != 0 &&
-			previousSummary.Count() != 0 &&
-			currentSummary.Count() < previousSummary.Count()) ||
-
-			(currentSummary.Sum() != 0 &&
-				previousSummary.Sum() != 0 &&
-				currentSummary.Sum() < previousSummary.Sum()) {
-			// reset detected
-			tsi.initial = current
-			resets++
-			continue
-		}
-		initialSummary := initialPoints.At(i)
-		currentSummary.SetStartTimestamp(initialSummary.StartTimestamp())
 	}
-
 	return
 }
+
+// summaryResetReason detects the resets of summaries from their count and sum, which may be missing.
+func summaryResetReason(previous, current *pointValuesPdata) string {
+	if (current.count != 0 && previous.count != 0 && current.count < previous.count) ||
+		(current.sum != 0 && previous.sum != 0 && current.sum < previous.sum) {
+		return resetReasonDecrease
+	}
+	return ""
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 )
@@ -139,7 +141,7 @@ func Test_gauge_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_cumulative_pdata(t *testing.T) {
@@ -315,7 +317,7 @@ func Test_cumulative_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func populateSummary(sdp *pdata.SummaryDataPoint, timestamp pdata.Timestamp, count uint64, sum float64, quantilePercents, quantileValues []float64) {
@@ -439,7 +441,7 @@ func Test_summary_no_count_pdata(t *testing.T) {
 		},
 	}
 
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_summary_flag_norecordedvalue(t *testing.T) {
@@ -506,7 +508,7 @@ func Test_summary_flag_norecordedvalue(t *testing.T) {
 		},
 	}
 
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_summary_pdata(t *testing.T) {
@@ -553,7 +555,7 @@ func Test_summary_pdata(t *testing.T) {
 		},
 	}
 
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 var (
@@ -616,7 +618,7 @@ func Test_cumulativeDistribution_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_histogram_flag_norecordedvalue(t *testing.T) {
@@ -657,7 +659,7 @@ func Test_histogram_flag_norecordedvalue(t *testing.T) {
 		},
 	}
 
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_multiMetrics_pdata(t *testing.T) {
@@ -723,7 +725,7 @@ func Test_multiMetrics_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_multiTimeseries_pdata(t *testing.T) {
@@ -783,7 +785,7 @@ func Test_multiTimeseries_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 var (
@@ -816,7 +818,7 @@ func Test_emptyLabels_pdata(t *testing.T) {
 			0,
 		},
 	}
-	runScriptPdata(t, NewJobsMapPdata(time.Minute).get("job", "0"), script)
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
 }
 
 func Test_tsGC_pdata(t *testing.T) {
@@ -873,7 +875,7 @@ func Test_tsGC_pdata(t *testing.T) {
 		},
 	}
 
-	jobsMap := NewJobsMapPdata(time.Minute)
+	jobsMap := NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus"))
 
 	// run round 1
 	runScriptPdata(t, jobsMap.get("job", "0"), script1)
@@ -937,7 +939,7 @@ func Test_jobGC_pdata(t *testing.T) {
 	}
 
 	gcInterval := 10 * time.Millisecond
-	jobsMap := NewJobsMapPdata(gcInterval)
+	jobsMap := NewJobsMapPdata(gcInterval, 0, config.NewComponentID("prometheus"))
 
 	// run job 1, round 1 - all entries marked
 	runScriptPdata(t, jobsMap.get("job", "0"), job1Script1)
@@ -955,6 +957,177 @@ func Test_jobGC_pdata(t *testing.T) {
 	runScriptPdata(t, jobsMap.get("job", "0"), job1Script2)
 }
 
+func Test_jobMaxEntries_pdata(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	jobsMap := NewJobsMapPdata(time.Minute, 2, config.NewComponentID("prometheus"))
+	tsm0 := jobsMap.get("job", "0")
+	tsm1 := jobsMap.get("job", "1")
+	// access job 0 again, so that job 1 is the least recently used
+	time.Sleep(time.Millisecond)
+	assert.Same(t, tsm0, jobsMap.get("job", "0"))
+
+	// adding job 2 evicts job 1
+	jobsMap.get("job", "2")
+	assert.Len(t, jobsMap.jobsMap, 2)
+	assert.Same(t, tsm0, jobsMap.get("job", "0"))
+	assert.NotSame(t, tsm1, jobsMap.get("job", "1"))
+
+	rows, err := view.RetrieveData(statJobsMapEvictions.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	// job 1 is evicted, then job 2 when job 1 is re-added
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+	assert.ElementsMatch(t, []string{"prometheus", evictionReasonMaxEntries}, []string{rows[0].Tags[0].Value, rows[0].Tags[1].Value})
+
+	rows, err = view.RetrieveData(statJobsMapSize.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.LastValueData).Value)
+}
+
+func Test_jobMaxEntriesGC_pdata(t *testing.T) {
+	jobsMap := NewJobsMapPdata(time.Minute, 2, config.NewComponentID("prometheus"))
+	jobsMap.get("job", "0")
+	tsm1 := jobsMap.get("job", "1")
+	// only job 1 is adjusted between the gcs, the second one removes job 0 from the recently used entries.
+	jobsMap.lastGC = time.Time{}
+	jobsMap.gc()
+	tsm1.mark = true
+	jobsMap.lastGC = time.Time{}
+	jobsMap.gc()
+	require.Len(t, jobsMap.jobsMap, 1)
+	assert.Equal(t, 1, jobsMap.lru.Len())
+
+	// adding jobs 2 and 3 evicts job 1, then job 2.
+	jobsMap.get("job", "2")
+	jobsMap.get("job", "3")
+	require.Len(t, jobsMap.jobsMap, 2)
+	assert.Equal(t, 2, jobsMap.lru.Len())
+	assert.Contains(t, jobsMap.jobsMap, "job:2")
+	assert.Contains(t, jobsMap.jobsMap, "job:3")
+}
+
+func cumulativePoint(startTs, ts pdata.Timestamp, value float64) *pdata.NumberDataPoint {
+	point := doublePoint(ts, value)
+	point.SetStartTimestamp(startTs)
+	return point
+}
+
+func staleCumulativePoint(startTs, ts pdata.Timestamp) *pdata.NumberDataPoint {
+	point := cumulativePoint(startTs, ts, 0)
+	point.SetFlags(pdata.NewMetricDataPointFlags(pdata.MetricDataPointFlagNoRecordedValue))
+	return point
+}
+
+func cumulativeDistPoint(startTs, ts pdata.Timestamp, bounds []float64, counts []uint64) *pdata.HistogramDataPoint {
+	point := distPoint(ts, bounds, counts)
+	point.SetStartTimestamp(startTs)
+	return point
+}
+
+func Test_resetAfterStaleness_pdata(t *testing.T) {
+	script := []*metricsAdjusterTestPdata{
+		{
+			"ResetAfterStaleness: round 1 - initial instance, start time is established",
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt1Ms, cumulativePoint(pdt1Ms, pdt1Ms, 44))),
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt1Ms, cumulativePoint(pdt1Ms, pdt1Ms, 44))),
+			1,
+		}, {
+			"ResetAfterStaleness: round 2 - staleness marker, instance adjusted based on round 1",
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt2Ms, staleCumulativePoint(pdt2Ms, pdt2Ms))),
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt1Ms, staleCumulativePoint(pdt1Ms, pdt2Ms))),
+			0,
+		}, {
+			"ResetAfterStaleness: round 3 - instance reset (value less than the one before the staleness marker), start time is reset",
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt3Ms, cumulativePoint(pdt3Ms, pdt3Ms, 40))),
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt3Ms, cumulativePoint(pdt3Ms, pdt3Ms, 40))),
+			1,
+		}, {
+			"ResetAfterStaleness: round 4 - staleness marker, instance adjusted based on round 3",
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt4Ms, staleCumulativePoint(pdt4Ms, pdt4Ms))),
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt3Ms, staleCumulativePoint(pdt3Ms, pdt4Ms))),
+			0,
+		}, {
+			"ResetAfterStaleness: round 5 - instance adjusted based on round 3",
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt5Ms, cumulativePoint(pdt5Ms, pdt5Ms, 80))),
+			metricSlice(sumMetric(c1, k1v1k2v2, pdt3Ms, cumulativePoint(pdt3Ms, pdt5Ms, 80))),
+			0,
+		},
+	}
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
+}
+
+func Test_histogramBucketsReset_pdata(t *testing.T) {
+	bounds1 := []float64{1, 2, 4, 8}
+	script := []*metricsAdjusterTestPdata{
+		{
+			"HistogramBucketsReset: round 1 - initial instance, start time is established",
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt1Ms, cumulativeDistPoint(pdt1Ms, pdt1Ms, bounds0, []uint64{4, 2, 3, 7}))),
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt1Ms, cumulativeDistPoint(pdt1Ms, pdt1Ms, bounds0, []uint64{4, 2, 3, 7}))),
+			1,
+		}, {
+			"HistogramBucketsReset: round 2 - instance reset (bucket count less than previous, count and sum greater), start time is reset",
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt2Ms, cumulativeDistPoint(pdt2Ms, pdt2Ms, bounds0, []uint64{2, 8, 3, 7}))),
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt2Ms, cumulativeDistPoint(pdt2Ms, pdt2Ms, bounds0, []uint64{2, 8, 3, 7}))),
+			1,
+		}, {
+			"HistogramBucketsReset: round 3 - instance reset (bounds changed), start time is reset",
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt3Ms, cumulativeDistPoint(pdt3Ms, pdt3Ms, bounds1, []uint64{2, 8, 3, 7, 1}))),
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt3Ms, cumulativeDistPoint(pdt3Ms, pdt3Ms, bounds1, []uint64{2, 8, 3, 7, 1}))),
+			1,
+		}, {
+			"HistogramBucketsReset: round 4 - instance adjusted based on round 3",
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt4Ms, cumulativeDistPoint(pdt4Ms, pdt4Ms, bounds1, []uint64{3, 8, 5, 7, 1}))),
+			metricSlice(histogramMetric(cd1, k1v1k2v2, pdt3Ms, cumulativeDistPoint(pdt3Ms, pdt4Ms, bounds1, []uint64{3, 8, 5, 7, 1}))),
+			0,
+		},
+	}
+	runScriptPdata(t, NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), script)
+}
+
+func Test_counterResetsTelemetry_pdata(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	ma := NewMetricsAdjusterPdata(NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus")).get("job", "0"), zap.NewNop())
+	// the initial points aren't counted as resets
+	assert.Equal(t, 2, ma.AdjustMetricSlice(metricSlice(
+		sumMetric(c1, k1v1k2v2, pdt1Ms, cumulativePoint(pdt1Ms, pdt1Ms, 44)),
+		histogramMetric(cd1, k1v1k2v2, pdt1Ms, cumulativeDistPoint(pdt1Ms, pdt1Ms, bounds0, []uint64{4, 2, 3, 7})),
+	)))
+	assert.Equal(t, 0, ma.AdjustMetricSlice(metricSlice(
+		sumMetric(c1, k1v1k2v2, pdt2Ms, staleCumulativePoint(pdt2Ms, pdt2Ms)),
+		histogramMetric(cd1, k1v1k2v2, pdt2Ms, cumulativeDistPoint(pdt2Ms, pdt2Ms, bounds0, []uint64{4, 2, 3, 7})),
+	)))
+	assert.Equal(t, 2, ma.AdjustMetricSlice(metricSlice(
+		sumMetric(c1, k1v1k2v2, pdt3Ms, cumulativePoint(pdt3Ms, pdt3Ms, 40)),
+		histogramMetric(cd1, k1v1k2v2, pdt3Ms, cumulativeDistPoint(pdt3Ms, pdt3Ms, []float64{1, 2, 4, 8}, []uint64{4, 2, 3, 7, 1})),
+	)))
+
+	rows, err := view.RetrieveData(statCounterResets.Name())
+	require.NoError(t, err)
+	resets := map[string]float64{}
+	for _, row := range rows {
+		var reason string
+		for _, tag := range row.Tags {
+			switch tag.Key {
+			case tagReceiverKey:
+				assert.Equal(t, "prometheus", tag.Value)
+			case tagJobKey:
+				assert.Equal(t, "job", tag.Value)
+			case tagReasonKey:
+				reason = tag.Value
+			}
+		}
+		resets[reason] = row.Data.(*view.SumData).Value
+	}
+	assert.Equal(t, map[string]float64{resetReasonDecrease: 1, resetReasonBucketLayout: 1}, resets)
+}
+
 type metricsAdjusterTestPdata struct {
 	description string
 	metrics     *pdata.MetricSlice
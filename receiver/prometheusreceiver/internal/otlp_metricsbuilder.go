@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/model/value"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
 )
@@ -93,6 +94,8 @@ type metricBuilderPdata struct {
 	startTime            float64
 	intervalStartTimeMs  int64
 	logger               *zap.Logger
+	// fresh is set once a sample which isn't a staleness marker is added.
+	fresh bool
 }
 
 // newMetricBuilder creates a MetricBuilder which is allowed to feed all the datapoints from a single prometheus
@@ -166,11 +169,14 @@ func (b *metricBuilderPdata) AddDataPoint(ls labels.Labels, t int64, v float64)
 					zap.String("target_labels", fmt.Sprintf("%v", lm)))
 			}
 		}
-	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName):
+	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName) && !value.IsStaleNaN(v):
 		b.startTime = v
 	}
 
 	b.hasData = true
+	if !value.IsStaleNaN(v) {
+		b.fresh = true
+	}
 
 	curMF, ok := b.families[metricName]
 	if !ok {
@@ -16,6 +16,7 @@ package internal // import "github.com/open-telemetry/opentelemetry-collector-co
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"time"
 
@@ -38,8 +39,8 @@ type transactionPdata struct {
 	useStartTimeMetric   bool
 	startTimeMetricRegex string
 	sink                 consumer.Metrics
-	metadataService      *metadataService
-	externalLabels       labels.Labels
+	metadataService      metadataProvider
+	externalLabels       *externalLabelsAppender
 	nodeResource         *pdata.Resource
 	logger               *zap.Logger
 	receiverID           config.ComponentID
@@ -48,6 +49,33 @@ type transactionPdata struct {
 	jobsMap              *JobsMapPdata
 	obsrecv              *obsreport.Receiver
 	startTimeMs          int64
+	duplicates           *duplicateSampleDetector
+	missingMetadata      *missingMetadataHandler
+	labelValueLimit      *labelValueLimiter
+	sampleAge            *sampleAgeLimiter
+	targetLabels         []string
+	targetAttributes     map[string]string
+	jobResourceAttrs     map[string]map[string]string
+	resourceAttrKeys     ResourceAttributeKeys
+	honorLabelsJobs      map[string]bool
+	infoMetrics          string
+	span                 *scrapeSpan
+	// honorLabels is whether the scrape config of the target honors labels.
+	honorLabels bool
+	// pending holds the samples appended before the target of the scrape is resolved.
+	pending []pendingSample
+	// honored holds the resources of the series whose job or instance, honored from the
+	// scraped data with honor_labels, differ from the ones of the target.
+	honored      map[string]*honoredResourcePdata
+	honoredOrder []*honoredResourcePdata
+}
+
+// honoredResourcePdata builds the metrics of the series of a scrape sharing a job and instance
+// honored from the scraped data.
+type honoredResourcePdata struct {
+	job, instance string
+	resource      *pdata.Resource
+	metricBuilder *metricBuilderPdata
 }
 
 type txConfig struct {
@@ -55,13 +83,29 @@ type txConfig struct {
 	useStartTimeMetric   bool
 	startTimeMetricRegex string
 	receiverID           config.ComponentID
-	ms                   *metadataService
+	ms                   metadataProvider
 	sink                 consumer.Metrics
 	externalLabels       labels.Labels
 	settings             component.ReceiverCreateSettings
+	duplicateSamples     string
+	missingMetadata      string
+	unknownTargets       *unknownTargets
+	clampedSeries        *clampedSeries
+	targetLabels         []string
+	honorLabelsJobs      map[string]bool
+	infoMetrics          string
+	traceScrapes         bool
+	labelValueMaxLength  int
+	labelValueAction     string
+	jobResourceAttrs     map[string]map[string]string
+	resourceAttrKeys     ResourceAttributeKeys
+	sampleMaxAge         time.Duration
+	sampleMaxFuture      time.Duration
+	sampleAgeAction      string
 }
 
 func newTransactionPdata(ctx context.Context, txc *txConfig) *transactionPdata {
+	ctx, span := startScrapeSpan(ctx, txc.settings, txc.receiverID, txc.traceScrapes)
 	return &transactionPdata{
 		id:                   atomic.AddInt64(&idSeq, 1),
 		ctx:                  ctx,
@@ -72,9 +116,19 @@ func newTransactionPdata(ctx context.Context, txc *txConfig) *transactionPdata {
 		startTimeMetricRegex: txc.startTimeMetricRegex,
 		receiverID:           txc.receiverID,
 		metadataService:      txc.ms,
-		externalLabels:       txc.externalLabels,
+		externalLabels:       newExternalLabelsAppender(txc.externalLabels),
 		logger:               txc.settings.Logger,
 		obsrecv:              obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: txc.receiverID, Transport: transport, ReceiverCreateSettings: txc.settings}),
+		duplicates:           newDuplicateSampleDetector(txc.duplicateSamples, txc.receiverID),
+		missingMetadata:      newMissingMetadataHandler(txc.missingMetadata, txc.receiverID, txc.unknownTargets),
+		labelValueLimit:      newLabelValueLimiter(txc.labelValueMaxLength, txc.labelValueAction, txc.receiverID),
+		sampleAge:            newSampleAgeLimiter(txc.sampleMaxAge, txc.sampleMaxFuture, txc.sampleAgeAction, txc.receiverID, txc.clampedSeries),
+		targetLabels:         txc.targetLabels,
+		jobResourceAttrs:     txc.jobResourceAttrs,
+		resourceAttrKeys:     txc.resourceAttrKeys,
+		honorLabelsJobs:      txc.honorLabelsJobs,
+		infoMetrics:          txc.infoMetrics,
+		span:                 span,
 	}
 }
 
@@ -85,82 +139,201 @@ func (t *transactionPdata) Append(ref storage.SeriesRef, labels labels.Labels, a
 		return 0, errTransactionAborted
 	default:
 	}
+	t.span.appended()
+
+	labels, keep := t.labelValueLimit.apply(t.ctx, labels)
+	if !keep {
+		return 0, nil
+	}
+	atMs, keep = t.sampleAge.apply(t.ctx, labels, atMs)
+	if !keep {
+		return 0, nil
+	}
 
-	if len(t.externalLabels) != 0 {
-		labels = append(labels, t.externalLabels...)
+	if err := t.duplicates.check(t.ctx, labels); err != nil {
+		return 0, err
 	}
 
+	labels = t.externalLabels.append(labels)
+
 	if t.isNew {
-		t.startTimeMs = atMs
-		if err := t.initTransaction(labels); err != nil {
+		if len(t.pending) == 0 {
+			t.startTimeMs = atMs
+		}
+		if err := t.initTransaction(labels, mayBeHonored(t.honorLabelsJobs, labels)); err != nil {
+			if errors.Is(err, errTargetNotResolved) {
+				t.pending = append(t.pending, pendingSample{ls: labels, t: atMs, v: value})
+				return 0, nil
+			}
+			return 0, err
+		}
+		if err := t.appendPending(); err != nil {
 			return 0, err
 		}
 	}
+	return 0, t.addDataPoint(labels, atMs, value)
+}
 
-	return 0, t.metricBuilder.AddDataPoint(labels, atMs, value)
+func (t *transactionPdata) addDataPoint(ls labels.Labels, atMs int64, value float64) error {
+	if !t.missingMetadata.check(t.ctx, t.metricBuilder.mc, ls) {
+		return nil
+	}
+	return t.builderFor(ls).AddDataPoint(ls, atMs, value)
+}
+
+// appendPending adds the samples appended before the target of the scrape was resolved.
+func (t *transactionPdata) appendPending() error {
+	pending := t.pending
+	t.pending = nil
+	for _, s := range pending {
+		if err := t.addDataPoint(s.ls, s.t, s.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builderFor returns the builder of the resource of the series. With honor_labels, the series
+// whose job or instance differ from the ones of the target get a resource of their own.
+func (t *transactionPdata) builderFor(ls labels.Labels) *metricBuilderPdata {
+	if !t.honorLabels {
+		return t.metricBuilder
+	}
+	job, instance := ls.Get(model.JobLabel), ls.Get(model.InstanceLabel)
+	if job == t.job && instance == t.instance {
+		return t.metricBuilder
+	}
+	key := honoredResourceKey(job, instance)
+	hr, ok := t.honored[key]
+	if !ok {
+		hr = &honoredResourcePdata{
+			job:           job,
+			instance:      instance,
+			resource:      CreateNodeAndResourcePdata(job, instance, t.metricBuilder.mc.SharedLabels().Get(model.SchemeLabel), t.jobResourceAttrs[t.job], t.resourceAttrKeys),
+			metricBuilder: newMetricBuilderPdata(t.metricBuilder.mc, t.useStartTimeMetric, t.startTimeMetricRegex, t.logger, t.startTimeMs),
+		}
+		if t.honored == nil {
+			t.honored = make(map[string]*honoredResourcePdata)
+		}
+		t.honored[key] = hr
+		t.honoredOrder = append(t.honoredOrder, hr)
+	}
+	return hr.metricBuilder
 }
 
 func (t *transactionPdata) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
 	return 0, nil
 }
 
-func (t *transactionPdata) initTransaction(labels labels.Labels) error {
+// initTransaction discovers the target of the scrape, see transaction.initTransaction.
+func (t *transactionPdata) initTransaction(labels labels.Labels, deferUnknownTarget bool) error {
 	job, instance := labels.Get(model.JobLabel), labels.Get(model.InstanceLabel)
 	if job == "" || instance == "" {
 		return errNoJobInstance
 	}
-	metadataCache, err := t.metadataService.Get(job, instance)
+	metadataCache, err := t.missingMetadata.targetMetadata(t.metadataService, job, instance, deferUnknownTarget, t.logger)
 	if err != nil {
 		return err
 	}
-	if t.jobsMap != nil {
-		t.job = job
-		t.instance = instance
-	}
-	t.nodeResource = CreateNodeAndResourcePdata(job, instance, metadataCache.SharedLabels().Get(model.SchemeLabel))
+	t.job = job
+	t.instance = instance
+	t.honorLabels = t.honorLabelsJobs[job]
+	t.span.setTarget(job, instance)
+	t.nodeResource = CreateNodeAndResourcePdata(job, instance, metadataCache.SharedLabels().Get(model.SchemeLabel), t.jobResourceAttrs[job], t.resourceAttrKeys)
+	t.targetAttributes = targetAttributes(t.targetLabels, labels)
 	t.metricBuilder = newMetricBuilderPdata(metadataCache, t.useStartTimeMetric, t.startTimeMetricRegex, t.logger, t.startTimeMs)
 	t.isNew = false
 	return nil
 }
 
 func (t *transactionPdata) Commit() error {
+	numPoints, err := t.commit()
+	t.span.commit(numPoints, err)
+	return err
+}
+
+// commit returns the number of data points submitted to the consumers.
+func (t *transactionPdata) commit() (int, error) {
 	if t.isNew {
-		return nil
+		if len(t.pending) == 0 {
+			return 0, nil
+		}
+		// None of the samples carry the job and instance of a target, the target is looked up
+		// from the first sample as if honor_labels was disabled.
+		if err := t.initTransaction(t.pending[0].ls, false); err != nil {
+			t.pending = nil
+			return 0, err
+		}
+		if err := t.appendPending(); err != nil {
+			return 0, err
+		}
 	}
 
 	t.startTimeMs = -1
 
 	ctx := t.obsrecv.StartMetricsOp(t.ctx)
-	metricsL, numPoints, _, err := t.metricBuilder.Build()
+	metricsL, numPoints, droppedPoints, err := t.metricBuilder.Build()
 	if err != nil {
 		t.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
-		return err
+		return 0, err
 	}
+	recordDroppedSeries(t.ctx, t.receiverID, t.job, dropReasonInvalidType, droppedPoints)
 
-	if t.useStartTimeMetric {
-		if t.metricBuilder.startTime == 0.0 {
-			err = errNoStartTimeMetrics
-			t.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
-			return err
+	if t.useStartTimeMetric && t.metricBuilder.startTime == 0.0 {
+		if !t.metricBuilder.fresh {
+			recordDroppedSeries(t.ctx, t.receiverID, t.job, dropReasonStaleOnly, numPoints-droppedPoints)
 		}
-		// Otherwise adjust the startTimestamp for all the metrics.
-		t.adjustStartTimestampPdata(metricsL)
-	} else {
-		// TODO: Derive numPoints in this case.
-		_ = NewMetricsAdjusterPdata(t.jobsMap.get(t.job, t.instance), t.logger).AdjustMetricSlice(metricsL)
+		err = errNoStartTimeMetrics
+		t.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
+		return 0, err
 	}
+	t.adjustMetrics(metricsL, t.job, t.instance)
 
+	metrics := pdata.NewMetrics()
 	if metricsL.Len() > 0 {
-		metrics := t.metricSliceToMetrics(metricsL)
-		t.sink.ConsumeMetrics(ctx, *metrics)
+		t.metricSliceToMetrics(metricsL, t.nodeResource).ResourceMetrics().MoveAndAppendTo(metrics.ResourceMetrics())
+	}
+	for _, hr := range t.honoredOrder {
+		honoredL, honoredPoints, honoredDropped, err := hr.metricBuilder.Build()
+		if err != nil {
+			t.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
+			return 0, err
+		}
+		recordDroppedSeries(t.ctx, t.receiverID, hr.job, dropReasonInvalidType, honoredDropped)
+		numPoints += honoredPoints
+		t.adjustMetrics(honoredL, hr.job, hr.instance)
+		if honoredL.Len() > 0 {
+			t.metricSliceToMetrics(honoredL, hr.resource).ResourceMetrics().MoveAndAppendTo(metrics.ResourceMetrics())
+		}
+	}
+
+	if t.infoMetrics == InfoMetricsResource {
+		convertInfoMetrics(metrics)
+	}
+
+	if metrics.ResourceMetrics().Len() > 0 {
+		t.sink.ConsumeMetrics(ctx, metrics)
 	}
 
 	t.obsrecv.EndMetricsOp(ctx, dataformat, numPoints, nil)
-	return nil
+	return numPoints, nil
+}
+
+// adjustMetrics adds the target attributes to the metrics of a resource of the scrape and adjusts their start time.
+func (t *transactionPdata) adjustMetrics(metricsL *pdata.MetricSlice, job, instance string) {
+	addTargetAttributes(*metricsL, t.targetAttributes)
+	if t.useStartTimeMetric {
+		t.adjustStartTimestampPdata(metricsL)
+	} else {
+		// TODO: Derive numPoints in this case.
+		_ = NewMetricsAdjusterPdata(t.jobsMap.get(job, instance), t.logger).AdjustMetricSlice(metricsL)
+	}
 }
 
 func (t *transactionPdata) Rollback() error {
 	t.startTimeMs = -1
+	t.pending = nil
+	t.span.rollback()
 	return nil
 }
 
@@ -205,11 +378,11 @@ func (t transactionPdata) adjustStartTimestampPdata(metricsL *pdata.MetricSlice)
 	}
 }
 
-func (t *transactionPdata) metricSliceToMetrics(metricsL *pdata.MetricSlice) *pdata.Metrics {
+func (t *transactionPdata) metricSliceToMetrics(metricsL *pdata.MetricSlice, resource *pdata.Resource) *pdata.Metrics {
 	metrics := pdata.NewMetrics()
 	rms := metrics.ResourceMetrics().AppendEmpty()
 	ilm := rms.InstrumentationLibraryMetrics().AppendEmpty()
 	metricsL.CopyTo(ilm.Metrics())
-	t.nodeResource.CopyTo(rms.Resource())
+	resource.CopyTo(rms.Resource())
 	return &metrics
 }
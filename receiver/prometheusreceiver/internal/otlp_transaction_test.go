@@ -62,7 +62,7 @@ func Test_transaction_pdata(t *testing.T) {
 
 	t.Run("Commit Without Adding", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, nomc, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
@@ -70,7 +70,7 @@ func Test_transaction_pdata(t *testing.T) {
 
 	t.Run("Rollback does nothing", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, nomc, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if got := tr.Rollback(); got != nil {
 			t.Errorf("expecting nil from Rollback() but got err %v", got)
 		}
@@ -79,7 +79,7 @@ func Test_transaction_pdata(t *testing.T) {
 	badLabels := labels.Labels([]labels.Label{{Name: "foo", Value: "bar"}})
 	t.Run("Add One No Target", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, nomc, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if _, got := tr.Append(0, badLabels, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -91,7 +91,7 @@ func Test_transaction_pdata(t *testing.T) {
 		{Name: "foo", Value: "bar"}})
 	t.Run("Add One Job not found", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, nomc, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if _, got := tr.Append(0, jobNotFoundLb, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -102,7 +102,7 @@ func Test_transaction_pdata(t *testing.T) {
 		{Name: "__name__", Value: "foo"}})
 	t.Run("Add One Good", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, sink, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -110,7 +110,7 @@ func Test_transaction_pdata(t *testing.T) {
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
-		expectedNodeResource := CreateNodeAndResourcePdata("test", "localhost:8080", "http")
+		expectedNodeResource := CreateNodeAndResourcePdata("test", "localhost:8080", "http", nil, DefaultResourceAttributeKeys)
 		mds := sink.AllMetrics()
 		if len(mds) != 1 {
 			t.Fatalf("wanted one batch, got %v\n", sink.AllMetrics())
@@ -123,7 +123,7 @@ func Test_transaction_pdata(t *testing.T) {
 
 	t.Run("Error when start time is zero", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransactionPdata(context.Background(), &txConfig{nil, true, "", rID, ms, sink, nil, componenttest.NewNopReceiverCreateSettings()})
+		tr := newTransactionPdata(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
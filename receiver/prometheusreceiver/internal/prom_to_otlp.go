@@ -40,8 +40,10 @@ func isDiscernibleHost(host string) bool {
 	return true
 }
 
-// CreateNodeAndResourcePdata creates the resource data added to OTLP payloads.
-func CreateNodeAndResourcePdata(job, instance, scheme string) *pdata.Resource {
+// CreateNodeAndResourcePdata creates the resource data added to OTLP payloads. The port and scheme
+// attributes are added with the keys of attrKeys, and the attributes configured for the scrape job
+// are added to the resource, without overriding the ones of the target.
+func CreateNodeAndResourcePdata(job, instance, scheme string, jobAttrs map[string]string, attrKeys ResourceAttributeKeys) *pdata.Resource {
 	host, port, err := net.SplitHostPort(instance)
 	if err != nil {
 		host = instance
@@ -54,8 +56,15 @@ func CreateNodeAndResourcePdata(job, instance, scheme string) *pdata.Resource {
 	}
 	attrs.UpsertString(jobAttr, job)
 	attrs.UpsertString(instanceAttr, instance)
-	attrs.UpsertString(portAttr, port)
-	attrs.UpsertString(schemeAttr, scheme)
+	if attrKeys.Port != "" {
+		attrs.UpsertString(attrKeys.Port, port)
+	}
+	if attrKeys.Scheme != "" {
+		attrs.UpsertString(attrKeys.Scheme, scheme)
+	}
+	for k, v := range jobAttrs {
+		attrs.InsertString(k, v)
+	}
 
 	return &resource
 }
@@ -27,7 +27,7 @@ import (
 // Parity test to ensure that createNodeAndResource produces identical results to createNodeAndResourcePdata.
 func TestCreateNodeAndResourceEquivalence(t *testing.T) {
 	job, instance, scheme := "converter", "ocmetrics", "http"
-	ocNode, ocResource := createNodeAndResource(job, instance, scheme)
+	ocNode, ocResource := createNodeAndResource(job, instance, scheme, nil, DefaultResourceAttributeKeys)
 	mdFromOC := opencensus.OCToMetrics(ocNode, ocResource,
 		// We need to pass in a dummy set of metrics
 		// just to populate and allow for full conversion.
@@ -43,7 +43,7 @@ func TestCreateNodeAndResourceEquivalence(t *testing.T) {
 	)
 
 	fromOCResource := mdFromOC.ResourceMetrics().At(0).Resource().Attributes().Sort()
-	byDirectOTLPResource := CreateNodeAndResourcePdata(job, instance, scheme).Attributes().Sort()
+	byDirectOTLPResource := CreateNodeAndResourcePdata(job, instance, scheme, nil, DefaultResourceAttributeKeys).Attributes().Sort()
 
 	require.Equal(t, byDirectOTLPResource, fromOCResource)
 }
@@ -129,7 +129,7 @@ func TestCreateNodeAndResourcePromToOTLP(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got := CreateNodeAndResourcePdata(tt.job, tt.instance, tt.scheme)
+			got := CreateNodeAndResourcePdata(tt.job, tt.instance, tt.scheme, nil, DefaultResourceAttributeKeys)
 			require.Equal(t, got, tt.want)
 		})
 	}
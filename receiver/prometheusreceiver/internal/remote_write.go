@@ -0,0 +1,430 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote"
+	"go.uber.org/zap"
+)
+
+// remoteWriteHandler ingests Prometheus remote-write requests. The series of each job and
+// instance are appended to the same transactions used for scrapes, so that they go through
+// the same conversion and metrics adjustment.
+type remoteWriteHandler struct {
+	store    *OcaStore
+	metadata *remoteWriteMetadata
+	pending  *remoteWritePending
+	logger   *zap.Logger
+}
+
+// NewRemoteWriteHandler returns a http.Handler accepting snappy compressed protobuf
+// remote-write requests and appending their samples to store. The samples of histograms
+// and summaries held until their points are complete are appended in the background
+// until ctx is done.
+func NewRemoteWriteHandler(ctx context.Context, store *OcaStore, logger *zap.Logger) http.Handler {
+	h := &remoteWriteHandler{
+		store:    store,
+		metadata: newRemoteWriteMetadata(maxRemoteWriteMetadata),
+		pending:  newRemoteWritePending(maxRemoteWritePendingSamples),
+		logger:   logger,
+	}
+	go h.flushPending(ctx)
+	return h
+}
+
+func (h *remoteWriteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := remote.DecodeWriteRequest(r.Body)
+	if err != nil {
+		h.logger.Debug("Error decoding remote write request", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ignored := h.metadata.update(req.Metadata); ignored > 0 {
+		h.logger.Debug("Metadata of remote write metric families ignored, the metadata cache is full",
+			zap.Int("families", ignored), zap.Int("limit", maxRemoteWriteMetadata))
+	}
+
+	if err = h.write(r.Context(), req.Timeseries); err != nil {
+		h.logger.Debug("Error appending remote write request", zap.Error(err))
+		// Senders retry on server errors only, requests that can never be appended
+		// are answered with a client error.
+		status := http.StatusInternalServerError
+		if errors.Is(err, errNoJobInstance) || errors.Is(err, errDuplicateSample) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// remoteWriteTarget is the job and instance of remote-write series.
+type remoteWriteTarget struct{ job, instance string }
+
+// remoteWriteBatchKey identifies the samples of a target appended in the same transaction,
+// the samples of a scrape sharing its timestamp.
+type remoteWriteBatchKey struct {
+	target    remoteWriteTarget
+	timestamp int64
+}
+
+// remoteWriteBatches collects the samples of a write by target and timestamp.
+type remoteWriteBatches struct {
+	targets []remoteWriteTarget
+	// timestamps holds the timestamps of the samples of every target, in the order they were added.
+	timestamps map[remoteWriteTarget][]int64
+	samples    map[remoteWriteBatchKey][]labeledSample
+}
+
+func (b *remoteWriteBatches) add(key remoteWriteBatchKey, samples ...labeledSample) {
+	if b.samples == nil {
+		b.timestamps = make(map[remoteWriteTarget][]int64)
+		b.samples = make(map[remoteWriteBatchKey][]labeledSample)
+	}
+	if _, ok := b.samples[key]; !ok {
+		if _, ok := b.timestamps[key.target]; !ok {
+			b.targets = append(b.targets, key.target)
+		}
+		b.timestamps[key.target] = append(b.timestamps[key.target], key.timestamp)
+	}
+	b.samples[key] = append(b.samples[key], samples...)
+}
+
+// write appends the samples of every job, instance and timestamp in their own transactions.
+// Prometheus shards remote-write series by series, so the buckets, count and sum of the points
+// of histograms and summaries are usually sent in distinct requests: their samples are held until
+// remoteWriteHoldTime has passed, and appended together then.
+func (h *remoteWriteHandler) write(ctx context.Context, timeseries []prompb.TimeSeries) error {
+	var batches remoteWriteBatches
+	for _, ts := range timeseries {
+		ls := labelProtosToLabels(ts.Labels)
+		t := remoteWriteTarget{job: ls.Get(model.JobLabel), instance: ls.Get(model.InstanceLabel)}
+		metadata, family := metadataForMetric(ls.Get(model.MetricNameLabel), h.metadata)
+		complexType := metadata.Type == textparse.MetricTypeHistogram || metadata.Type == textparse.MetricTypeSummary
+		for _, s := range ts.Samples {
+			sample := labeledSample{labels: ls, timestamp: s.Timestamp, value: s.Value}
+			if complexType && h.pending.hold(remoteWritePendingKey{target: t, family: family, timestamp: s.Timestamp}, metadata.Type, sample) {
+				continue
+			}
+			batches.add(remoteWriteBatchKey{target: t, timestamp: s.Timestamp}, sample)
+		}
+	}
+	h.addReleased(ctx, &batches)
+	return h.appendBatches(ctx, &batches)
+}
+
+// flushPending appends the held points once they have been held for remoteWriteHoldTime,
+// when no later request releases them, until ctx is done.
+func (h *remoteWriteHandler) flushPending(ctx context.Context) {
+	ticker := time.NewTicker(remoteWriteHoldTime / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			var batches remoteWriteBatches
+			h.addReleased(ctx, &batches)
+			if err := h.appendBatches(ctx, &batches); err != nil {
+				h.logger.Debug("Error appending held remote write samples", zap.Error(err))
+			}
+		}
+	}
+}
+
+// addReleased adds the samples of the held families released by the pending points to batches.
+// The points missing their count, or the buckets of histograms, are dropped: they can't be
+// converted.
+func (h *remoteWriteHandler) addReleased(ctx context.Context, batches *remoteWriteBatches) {
+	for _, family := range h.pending.release() {
+		samples, incomplete := family.completeSamples()
+		if incomplete > 0 {
+			h.logger.Debug("Incomplete remote write points dropped, their count or buckets weren't received",
+				zap.String("job", family.key.target.job), zap.String("instance", family.key.target.instance),
+				zap.String("metric", family.key.family), zap.Int("series", incomplete))
+			recordDroppedSeries(ctx, h.store.receiverID, family.key.target.job, dropReasonIncomplete, incomplete)
+		}
+		if len(samples) > 0 {
+			batches.add(remoteWriteBatchKey{target: family.key.target, timestamp: family.key.timestamp}, samples...)
+		}
+	}
+}
+
+func (h *remoteWriteHandler) appendBatches(ctx context.Context, batches *remoteWriteBatches) error {
+	for _, t := range batches.targets {
+		for _, timestamp := range batches.timestamps[t] {
+			if err := h.appendBatch(ctx, batches.samples[remoteWriteBatchKey{target: t, timestamp: timestamp}]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *remoteWriteHandler) appendBatch(ctx context.Context, samples []labeledSample) error {
+	app := h.store.remoteWriteAppender(ctx, h.metadata)
+	for _, s := range samples {
+		if _, err := app.Append(0, s.labels, s.timestamp, s.value); err != nil {
+			if errors.Is(err, storage.ErrDuplicateSampleForTimestamp) {
+				continue
+			}
+			_ = app.Rollback()
+			return err
+		}
+	}
+	return app.Commit()
+}
+
+type labeledSample struct {
+	labels    labels.Labels
+	timestamp int64
+	value     float64
+}
+
+const (
+	// remoteWriteHoldTime is how long the samples of histograms and summaries are held for the
+	// rest of their points to be received. The queues of Prometheus send their samples within
+	// 5 seconds by default.
+	remoteWriteHoldTime = 10 * time.Second
+	// maxRemoteWritePendingSamples bounds the number of samples held, the samples which don't
+	// fit are appended with the samples of their request.
+	maxRemoteWritePendingSamples = 100000
+)
+
+// remoteWritePendingKey identifies the samples of a histogram or summary family of a target at a timestamp.
+type remoteWritePendingKey struct {
+	target    remoteWriteTarget
+	family    string
+	timestamp int64
+}
+
+// remoteWritePendingFamily holds the samples of the points of a family received so far.
+type remoteWritePendingFamily struct {
+	key        remoteWritePendingKey
+	metricType textparse.MetricType
+	samples    []labeledSample
+	heldAt     time.Time
+}
+
+// completeSamples returns the samples of the complete points of the family, and the number of
+// series of the incomplete ones: histograms need their count and a bucket, summaries their count.
+func (f *remoteWritePendingFamily) completeSamples() ([]labeledSample, int) {
+	type point struct{ hasCount, hasBoundary bool }
+	points := make(map[string]*point)
+	keys := make([]string, len(f.samples))
+	for i, s := range f.samples {
+		keys[i] = remoteWritePointKey(s.labels)
+		p, ok := points[keys[i]]
+		if !ok {
+			p = &point{}
+			points[keys[i]] = p
+		}
+		name := s.labels.Get(model.MetricNameLabel)
+		switch {
+		case name == f.key.family+metricsSuffixCount:
+			p.hasCount = true
+		case name == f.key.family+metricsSuffixBucket, name == f.key.family:
+			p.hasBoundary = true
+		}
+	}
+
+	complete := f.samples[:0:0]
+	incomplete := 0
+	for i, s := range f.samples {
+		p := points[keys[i]]
+		if p.hasCount && (p.hasBoundary || f.metricType == textparse.MetricTypeSummary) {
+			complete = append(complete, s)
+		} else {
+			incomplete++
+		}
+	}
+	return complete, incomplete
+}
+
+// remoteWritePointKey returns the key of the point of a histogram or summary sample, its labels
+// apart from the metric name and the bucket or quantile.
+func remoteWritePointKey(ls labels.Labels) string {
+	return labels.NewBuilder(ls).Del(model.MetricNameLabel, model.BucketLabel, model.QuantileLabel).Labels().String()
+}
+
+// remoteWritePending holds the samples of histograms and summaries for remoteWriteHoldTime.
+type remoteWritePending struct {
+	sync.Mutex
+	families map[remoteWritePendingKey]*remoteWritePendingFamily
+	// order holds the families in the order they were first held.
+	order   []*remoteWritePendingFamily
+	samples int
+	limit   int
+	now     func() time.Time
+}
+
+func newRemoteWritePending(limit int) *remoteWritePending {
+	return &remoteWritePending{
+		families: make(map[remoteWritePendingKey]*remoteWritePendingFamily),
+		limit:    limit,
+		now:      time.Now,
+	}
+}
+
+// hold holds sample of the family of key, and returns false if the limit of held samples is reached.
+func (p *remoteWritePending) hold(key remoteWritePendingKey, metricType textparse.MetricType, sample labeledSample) bool {
+	p.Lock()
+	defer p.Unlock()
+	if p.samples >= p.limit {
+		return false
+	}
+	family, ok := p.families[key]
+	if !ok {
+		family = &remoteWritePendingFamily{key: key, metricType: metricType, heldAt: p.now()}
+		p.families[key] = family
+		p.order = append(p.order, family)
+	}
+	family.samples = append(family.samples, sample)
+	p.samples++
+	return true
+}
+
+// release returns the families held for remoteWriteHoldTime, which are no longer held.
+func (p *remoteWritePending) release() []*remoteWritePendingFamily {
+	p.Lock()
+	defer p.Unlock()
+	now := p.now()
+	n := 0
+	for n < len(p.order) && now.Sub(p.order[n].heldAt) >= remoteWriteHoldTime {
+		delete(p.families, p.order[n].key)
+		p.samples -= len(p.order[n].samples)
+		n++
+	}
+	released := p.order[:n:n]
+	p.order = p.order[n:]
+	return released
+}
+
+func labelProtosToLabels(labelPairs []prompb.Label) labels.Labels {
+	ls := make([]labels.Label, 0, len(labelPairs))
+	for _, l := range labelPairs {
+		ls = append(ls, labels.Label{Name: l.Name, Value: l.Value})
+	}
+	return labels.New(ls...)
+}
+
+const (
+	// maxRemoteWriteMetadata bounds the number of metric families whose metadata is cached.
+	maxRemoteWriteMetadata = 10000
+	// remoteWriteMetadataTTL is the time after which the metadata of a metric family which
+	// wasn't sent again can be evicted. Prometheus sends the metadata of all the metric
+	// families every minute by default.
+	remoteWriteMetadataTTL = 10 * time.Minute
+)
+
+// remoteWriteMetadata caches the metric metadata sent by remote-write senders, which is
+// sent separately from the samples. Metrics whose metadata was not received yet are unknown-typed.
+type remoteWriteMetadata struct {
+	sync.RWMutex
+	metadata map[string]remoteWriteMetricMetadata
+	limit    int
+	now      func() time.Time
+}
+
+// remoteWriteMetricMetadata is the metadata of a metric family, and the time it was last sent.
+type remoteWriteMetricMetadata struct {
+	scrape.MetricMetadata
+	updatedAt time.Time
+}
+
+var _ MetadataCache = (*remoteWriteMetadata)(nil)
+
+func newRemoteWriteMetadata(limit int) *remoteWriteMetadata {
+	return &remoteWriteMetadata{
+		metadata: make(map[string]remoteWriteMetricMetadata),
+		limit:    limit,
+		now:      time.Now,
+	}
+}
+
+// update caches the metadata of the metric families. Once the limit is reached, the metadata
+// not sent for remoteWriteMetadataTTL is evicted, and the metadata of new families which still
+// doesn't fit is ignored. It returns the number of families ignored.
+func (m *remoteWriteMetadata) update(metadata []prompb.MetricMetadata) int {
+	if len(metadata) == 0 {
+		return 0
+	}
+	m.Lock()
+	defer m.Unlock()
+	now := m.now()
+	ignored := 0
+	expired := false
+	for _, md := range metadata {
+		if _, ok := m.metadata[md.MetricFamilyName]; !ok && len(m.metadata) >= m.limit {
+			if !expired {
+				m.expire(now)
+				expired = true
+			}
+			if len(m.metadata) >= m.limit {
+				ignored++
+				continue
+			}
+		}
+		m.metadata[md.MetricFamilyName] = remoteWriteMetricMetadata{
+			MetricMetadata: scrape.MetricMetadata{
+				Metric: md.MetricFamilyName,
+				Type:   textparse.MetricType(strings.ToLower(md.Type.String())),
+				Help:   md.Help,
+				Unit:   md.Unit,
+			},
+			updatedAt: now,
+		}
+	}
+	return ignored
+}
+
+// expire evicts the metadata not sent for remoteWriteMetadataTTL, the caller has to hold the lock.
+func (m *remoteWriteMetadata) expire(now time.Time) {
+	for name, md := range m.metadata {
+		if now.Sub(md.updatedAt) > remoteWriteMetadataTTL {
+			delete(m.metadata, name)
+		}
+	}
+}
+
+// Get returns the metadata of all jobs and instances, the metadata of remote-write senders
+// is not scoped to a target.
+func (m *remoteWriteMetadata) Get(string, string) (MetadataCache, error) {
+	return m, nil
+}
+
+func (m *remoteWriteMetadata) Metadata(metricName string) (scrape.MetricMetadata, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	md, ok := m.metadata[metricName]
+	return md.MetricMetadata, ok
+}
+
+func (m *remoteWriteMetadata) SharedLabels() labels.Labels {
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func newRemoteWriteTestHandler(t *testing.T, sink *consumertest.MetricsSink) *remoteWriteHandler {
+	o := NewOcaStore(context.Background(), sink, testTelemetry.ToReceiverCreateSettings(), 2*time.Minute, false, "", config.NewComponentID("prometheus"), nil, true, OcaStoreOptions{DuplicateSamples: DuplicateSamplesKeepLast, MissingMetadata: MissingMetadataGauge, ResourceAttrKeys: DefaultResourceAttributeKeys})
+	o.SetScrapeManager(&scrape.Manager{})
+	t.Cleanup(o.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return NewRemoteWriteHandler(ctx, o, zap.NewNop()).(*remoteWriteHandler)
+}
+
+func remoteWriteRequest(t *testing.T, req *prompb.WriteRequest) *http.Request {
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	return httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(snappy.Encode(nil, data)))
+}
+
+func remoteWriteSeries(name, job string, ts int64, v float64, extraLabels ...prompb.Label) prompb.TimeSeries {
+	return prompb.TimeSeries{
+		Labels: append([]prompb.Label{
+			{Name: model.MetricNameLabel, Value: name},
+			{Name: model.JobLabel, Value: job},
+			{Name: model.InstanceLabel, Value: "localhost:8080"},
+		}, extraLabels...),
+		Samples: []prompb.Sample{{Timestamp: ts, Value: v}},
+	}
+}
+
+func TestRemoteWriteHandler(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	handler := newRemoteWriteTestHandler(t, sink)
+	ts := time.Now().Unix() * 1000
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{
+			{MetricFamilyName: "requests_total", Type: prompb.MetricMetadata_COUNTER, Help: "Number of requests"},
+		},
+	}))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Len(t, sink.AllMetrics(), 0)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			remoteWriteSeries("requests_total", "api", ts, 10),
+			remoteWriteSeries("temperature", "api", ts, 21.5),
+			remoteWriteSeries("requests_total", "worker", ts, 3),
+			// The series of a later sample of requests_total for the api job.
+			remoteWriteSeries("requests_total", "api", ts+15000, 12),
+		},
+	}))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	// One transaction per job for the first samples, and another one for the later sample.
+	batches := sink.AllMetrics()
+	require.Len(t, batches, 3)
+
+	jobs := make([]string, 0, len(batches))
+	types := make(map[string]pdata.MetricDataType)
+	for _, md := range batches {
+		rm := md.ResourceMetrics().At(0)
+		job, _ := rm.Resource().Attributes().Get("service.name")
+		jobs = append(jobs, job.StringVal())
+		metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			types[metrics.At(i).Name()] = metrics.At(i).DataType()
+		}
+	}
+	assert.Equal(t, []string{"api", "api", "worker"}, jobs)
+	assert.Equal(t, pdata.MetricDataTypeSum, types["requests_total"])
+	assert.Equal(t, pdata.MetricDataTypeGauge, types["temperature"])
+}
+
+func TestRemoteWriteHandlerSplitPoints(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	sink := new(consumertest.MetricsSink)
+	handler := newRemoteWriteTestHandler(t, sink)
+	now := time.Now()
+	handler.pending.now = func() time.Time { return now }
+	ts := now.Unix() * 1000
+	le := func(v string) prompb.Label { return prompb.Label{Name: model.BucketLabel, Value: v} }
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{
+			{MetricFamilyName: "latency", Type: prompb.MetricMetadata_HISTOGRAM},
+			{MetricFamilyName: "size", Type: prompb.MetricMetadata_HISTOGRAM},
+			{MetricFamilyName: "rpc_duration", Type: prompb.MetricMetadata_SUMMARY},
+		},
+		Timeseries: []prompb.TimeSeries{
+			remoteWriteSeries("latency_bucket", "test", ts, 2, le("1")),
+			remoteWriteSeries("latency_bucket", "test", ts, 5, le("+Inf")),
+			remoteWriteSeries("rpc_duration", "test", ts, 0.1, prompb.Label{Name: model.QuantileLabel, Value: "0.5"}),
+			// The count of the size histogram is never received.
+			remoteWriteSeries("size_bucket", "test", ts, 1, le("+Inf")),
+			remoteWriteSeries("size_sum", "test", ts, 10),
+		},
+	}))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	// The count and sum of the points are sent in another request.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			remoteWriteSeries("latency_count", "test", ts, 5),
+			remoteWriteSeries("latency_sum", "test", ts, 7),
+			remoteWriteSeries("rpc_duration_count", "test", ts, 3),
+			remoteWriteSeries("rpc_duration_sum", "test", ts, 0.6),
+		},
+	}))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Len(t, sink.AllMetrics(), 0, "the points are held until the hold time has passed")
+
+	now = now.Add(remoteWriteHoldTime)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{}))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	batches := sink.AllMetrics()
+	require.Len(t, batches, 1)
+	metrics := batches[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	byName := make(map[string]pdata.Metric)
+	for i := 0; i < metrics.Len(); i++ {
+		byName[metrics.At(i).Name()] = metrics.At(i)
+	}
+	require.Len(t, byName, 2)
+	require.Equal(t, pdata.MetricDataTypeHistogram, byName["latency"].DataType())
+	hdp := byName["latency"].Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(5), hdp.Count())
+	assert.Equal(t, 7.0, hdp.Sum())
+	assert.Equal(t, []uint64{2, 3}, hdp.BucketCounts())
+	require.Equal(t, pdata.MetricDataTypeSummary, byName["rpc_duration"].DataType())
+	sdp := byName["rpc_duration"].Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(3), sdp.Count())
+	assert.Equal(t, 1, sdp.QuantileValues().Len())
+
+	assert.Equal(t, map[string]float64{dropReasonIncomplete: 2}, droppedSeries(t))
+}
+
+func TestRemoteWritePendingLimit(t *testing.T) {
+	p := newRemoteWritePending(1)
+	key := remoteWritePendingKey{target: remoteWriteTarget{job: "test", instance: "localhost:8080"}, family: "latency"}
+	assert.True(t, p.hold(key, textparse.MetricTypeHistogram, labeledSample{labels: labels.FromStrings(model.MetricNameLabel, "latency_count")}))
+	assert.False(t, p.hold(key, textparse.MetricTypeHistogram, labeledSample{labels: labels.FromStrings(model.MetricNameLabel, "latency_sum")}))
+	assert.Empty(t, p.release())
+
+	now := time.Now().Add(remoteWriteHoldTime)
+	p.now = func() time.Time { return now }
+	released := p.release()
+	require.Len(t, released, 1)
+	assert.Len(t, released[0].samples, 1)
+	assert.True(t, p.hold(key, textparse.MetricTypeHistogram, labeledSample{labels: labels.FromStrings(model.MetricNameLabel, "latency_sum")}))
+}
+
+func TestRemoteWriteHandlerErrors(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	handler := newRemoteWriteTestHandler(t, sink)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader([]byte("invalid"))))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	series := remoteWriteSeries("requests_total", "api", time.Now().Unix()*1000, 1)
+	series.Labels = series.Labels[:1]
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, remoteWriteRequest(t, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{series}}))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), errNoJobInstance.Error())
+
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestRemoteWriteMetadataLimit(t *testing.T) {
+	now := time.Now()
+	m := newRemoteWriteMetadata(2)
+	m.now = func() time.Time { return now }
+	family := func(name string) prompb.MetricMetadata {
+		return prompb.MetricMetadata{MetricFamilyName: name, Type: prompb.MetricMetadata_COUNTER}
+	}
+
+	assert.Equal(t, 0, m.update([]prompb.MetricMetadata{family("a"), family("b")}))
+	// The cache is full, the metadata of the cached families is still updated.
+	assert.Equal(t, 1, m.update([]prompb.MetricMetadata{family("b"), family("c")}))
+	_, ok := m.Metadata("c")
+	assert.False(t, ok)
+
+	// The metadata which wasn't sent again for remoteWriteMetadataTTL is evicted.
+	now = now.Add(remoteWriteMetadataTTL / 2)
+	assert.Equal(t, 0, m.update([]prompb.MetricMetadata{family("b")}))
+	now = now.Add(remoteWriteMetadataTTL/2 + time.Second)
+	assert.Equal(t, 0, m.update([]prompb.MetricMetadata{family("c")}))
+	_, ok = m.Metadata("a")
+	assert.False(t, ok)
+	for _, name := range []string{"b", "c"} {
+		md, ok := m.Metadata(name)
+		require.True(t, ok)
+		assert.Equal(t, "counter", string(md.Type))
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+// Namings of the port and scheme attributes of the resources of the targets.
+const (
+	// ResourceAttributesNamingLegacy names the attributes port and scheme.
+	ResourceAttributesNamingLegacy = "legacy"
+	// ResourceAttributesNamingSemconv names the attributes server.port and url.scheme, like the
+	// Prometheus and OpenMetrics compatibility specification.
+	ResourceAttributesNamingSemconv = "semconv"
+
+	serverPortAttr = "server.port"
+	urlSchemeAttr  = "url.scheme"
+)
+
+// ResourceAttributeKeys are the keys of the port and scheme attributes added to the resources of
+// the targets. The attributes whose key is empty are not added.
+type ResourceAttributeKeys struct {
+	Port   string
+	Scheme string
+}
+
+// DefaultResourceAttributeKeys adds both attributes with their legacy names.
+var DefaultResourceAttributeKeys = ResourceAttributeKeys{Port: portAttr, Scheme: schemeAttr}
+
+// NewResourceAttributeKeys returns the keys of the enabled port and scheme attributes in the given naming.
+func NewResourceAttributeKeys(naming string, port, scheme bool) ResourceAttributeKeys {
+	keys := DefaultResourceAttributeKeys
+	if naming == ResourceAttributesNamingSemconv {
+		keys = ResourceAttributeKeys{Port: serverPortAttr, Scheme: urlSchemeAttr}
+	}
+	if !port {
+		keys.Port = ""
+	}
+	if !scheme {
+		keys.Scheme = ""
+	}
+	return keys
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestNewResourceAttributeKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		naming string
+		port   bool
+		scheme bool
+		want   ResourceAttributeKeys
+	}{
+		{name: "legacy", naming: ResourceAttributesNamingLegacy, port: true, scheme: true, want: ResourceAttributeKeys{Port: "port", Scheme: "scheme"}},
+		{name: "default naming", port: true, scheme: true, want: DefaultResourceAttributeKeys},
+		{name: "semconv", naming: ResourceAttributesNamingSemconv, port: true, scheme: true, want: ResourceAttributeKeys{Port: "server.port", Scheme: "url.scheme"}},
+		{name: "no port", naming: ResourceAttributesNamingSemconv, scheme: true, want: ResourceAttributeKeys{Scheme: "url.scheme"}},
+		{name: "no scheme", naming: ResourceAttributesNamingLegacy, port: true, want: ResourceAttributeKeys{Port: "port"}},
+		{name: "none", naming: ResourceAttributesNamingLegacy},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewResourceAttributeKeys(tt.naming, tt.port, tt.scheme))
+		})
+	}
+}
+
+func TestResourceAttributeKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys ResourceAttributeKeys
+		want map[string]string
+	}{
+		{
+			name: "legacy",
+			keys: DefaultResourceAttributeKeys,
+			want: map[string]string{"service.name": "job", "host.name": "example.com", "job": "job", "instance": "example.com:8080", "port": "8080", "scheme": "https"},
+		},
+		{
+			name: "semconv",
+			keys: NewResourceAttributeKeys(ResourceAttributesNamingSemconv, true, true),
+			want: map[string]string{"service.name": "job", "host.name": "example.com", "job": "job", "instance": "example.com:8080", "server.port": "8080", "url.scheme": "https"},
+		},
+		{
+			name: "disabled",
+			keys: ResourceAttributeKeys{},
+			want: map[string]string{"service.name": "job", "host.name": "example.com", "job": "job", "instance": "example.com:8080"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			resource := CreateNodeAndResourcePdata("job", "example.com:8080", "https", nil, tt.keys)
+			got := map[string]string{}
+			resource.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+				got[k] = v.StringVal()
+				return true
+			})
+			assert.Equal(t, tt.want, got)
+
+			// The service.name and host.name attributes are taken from the node in the OpenCensus pipeline.
+			_, ocResource := createNodeAndResource("job", "example.com:8080", "https", nil, tt.keys)
+			for k := range ocResource.Labels {
+				assert.Contains(t, tt.want, k)
+			}
+			assert.Len(t, ocResource.Labels, len(tt.want)-2)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/config"
+)
+
+// Actions applied to the samples whose timestamps are out of the configured sample age range.
+const (
+	// SampleAgeDrop drops the samples.
+	SampleAgeDrop = "drop"
+	// SampleAgeClamp sets the timestamps of the samples to the closest bound of the range. A series
+	// is clamped once per timestamp of its samples, the following samples with the same timestamp are
+	// dropped, so that the stale sample of a series which isn't updated isn't reported as a new point
+	// on every scrape.
+	SampleAgeClamp = "clamp"
+)
+
+// Reasons of the samples out of the sample age range.
+const (
+	sampleAgeReasonTooOld = "too_old"
+	sampleAgeReasonTooNew = "too_new"
+)
+
+// sampleAgeLimiter applies the configured action to the samples whose timestamps are more than
+// maxAge in the past or more than maxFuture in the future of the collector wall clock, as
+// exposed by targets with skewed clocks or stale explicit timestamps.
+type sampleAgeLimiter struct {
+	maxAge     time.Duration
+	maxFuture  time.Duration
+	action     string
+	receiverID config.ComponentID
+	// clamped is shared by the transactions of the receiver.
+	clamped *clampedSeries
+	now     func() time.Time
+}
+
+func newSampleAgeLimiter(maxAge, maxFuture time.Duration, action string, receiverID config.ComponentID, clamped *clampedSeries) *sampleAgeLimiter {
+	return &sampleAgeLimiter{
+		maxAge:     maxAge,
+		maxFuture:  maxFuture,
+		action:     action,
+		receiverID: receiverID,
+		clamped:    clamped,
+		now:        time.Now,
+	}
+}
+
+// apply returns the timestamp of the sample to append, and whether the sample has to be appended.
+func (l *sampleAgeLimiter) apply(ctx context.Context, ls labels.Labels, t int64) (int64, bool) {
+	if l.maxAge <= 0 && l.maxFuture <= 0 {
+		return t, true
+	}
+	now := l.now()
+	var bound int64
+	var reason string
+	switch {
+	case l.maxAge > 0 && t < timestampMs(now.Add(-l.maxAge)):
+		bound, reason = timestampMs(now.Add(-l.maxAge)), sampleAgeReasonTooOld
+	case l.maxFuture > 0 && t > timestampMs(now.Add(l.maxFuture)):
+		bound, reason = timestampMs(now.Add(l.maxFuture)), sampleAgeReasonTooNew
+	default:
+		if l.action == SampleAgeClamp {
+			l.clamped.remove(ls)
+		}
+		return t, true
+	}
+	job := ls.Get(model.JobLabel)
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, l.receiverID.String()), tag.Upsert(tagJobKey, job), tag.Upsert(tagReasonKey, reason)},
+		statOutOfRangeSamples.M(1),
+	)
+	if l.action == SampleAgeClamp && l.clamped.add(ls, t) {
+		return bound, true
+	}
+	recordDroppedSeries(ctx, l.receiverID, job, dropReasonSampleAge, 1)
+	return t, false
+}
+
+// maxClampedSeries bounds the number of series tracked by clampedSeries, which are all
+// forgotten once it is reached.
+const maxClampedSeries = 100000
+
+// clampedSeries tracks the series whose last sample was clamped, with the original timestamp
+// of the sample, so that a sample is clamped once instead of on every scrape exposing it.
+type clampedSeries struct {
+	mu     sync.Mutex
+	series map[uint64]int64
+}
+
+func newClampedSeries() *clampedSeries {
+	return &clampedSeries{series: make(map[uint64]int64)}
+}
+
+// add tracks the series of ls as clamped at timestamp t, and returns whether its last clamped
+// sample had another timestamp. A nil *clampedSeries tracks no series.
+func (c *clampedSeries) add(ls labels.Labels, t int64) bool {
+	if c == nil {
+		return true
+	}
+	h := ls.Hash()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if last, ok := c.series[h]; ok && last == t {
+		return false
+	}
+	if len(c.series) >= maxClampedSeries {
+		c.series = make(map[uint64]int64)
+	}
+	c.series[h] = t
+	return true
+}
+
+// remove forgets the series of ls once one of its samples is in range.
+func (c *clampedSeries) remove(ls labels.Labels) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.series) > 0 {
+		delete(c.series, ls.Hash())
+	}
+}
+
+func timestampMs(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
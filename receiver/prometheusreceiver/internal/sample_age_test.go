@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestSampleAgeLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test")
+	nowMs := timestampMs(now)
+
+	tests := []struct {
+		name      string
+		maxAge    time.Duration
+		maxFuture time.Duration
+		action    string
+		t         int64
+		want      int64
+		wantKeep  bool
+	}{
+		{
+			name:     "unlimited",
+			action:   SampleAgeDrop,
+			t:        nowMs - time.Hour.Milliseconds(),
+			want:     nowMs - time.Hour.Milliseconds(),
+			wantKeep: true,
+		},
+		{
+			name:      "in range",
+			maxAge:    time.Minute,
+			maxFuture: time.Minute,
+			action:    SampleAgeDrop,
+			t:         nowMs - time.Second.Milliseconds(),
+			want:      nowMs - time.Second.Milliseconds(),
+			wantKeep:  true,
+		},
+		{
+			name:   "too old dropped",
+			maxAge: time.Minute,
+			action: SampleAgeDrop,
+			t:      nowMs - time.Hour.Milliseconds(),
+			want:   nowMs - time.Hour.Milliseconds(),
+		},
+		{
+			name:      "too new dropped",
+			maxFuture: time.Minute,
+			action:    SampleAgeDrop,
+			t:         nowMs + time.Hour.Milliseconds(),
+			want:      nowMs + time.Hour.Milliseconds(),
+		},
+		{
+			name:     "too old clamped",
+			maxAge:   time.Minute,
+			action:   SampleAgeClamp,
+			t:        nowMs - time.Hour.Milliseconds(),
+			want:     nowMs - time.Minute.Milliseconds(),
+			wantKeep: true,
+		},
+		{
+			name:      "too new clamped",
+			maxFuture: time.Minute,
+			action:    SampleAgeClamp,
+			t:         nowMs + time.Hour.Milliseconds(),
+			want:      nowMs + time.Minute.Milliseconds(),
+			wantKeep:  true,
+		},
+		{
+			name:     "future unlimited",
+			maxAge:   time.Minute,
+			action:   SampleAgeDrop,
+			t:        nowMs + time.Hour.Milliseconds(),
+			want:     nowMs + time.Hour.Milliseconds(),
+			wantKeep: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newSampleAgeLimiter(tt.maxAge, tt.maxFuture, tt.action, config.NewComponentID("prometheus"), newClampedSeries())
+			l.now = func() time.Time { return now }
+			got, keep := l.apply(context.Background(), ls, tt.t)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantKeep, keep)
+		})
+	}
+}
+
+func TestSampleAgeLimiterMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test")
+	nowMs := timestampMs(time.Now())
+	clamp := newSampleAgeLimiter(time.Minute, time.Minute, SampleAgeClamp, config.NewComponentID("prometheus"), newClampedSeries())
+	_, keep := clamp.apply(context.Background(), ls, nowMs-time.Hour.Milliseconds())
+	assert.True(t, keep)
+	drop := newSampleAgeLimiter(time.Minute, time.Minute, SampleAgeDrop, config.NewComponentID("prometheus"), nil)
+	_, keep = drop.apply(context.Background(), ls, nowMs+time.Hour.Milliseconds())
+	assert.False(t, keep)
+
+	rows, err := view.RetrieveData(statOutOfRangeSamples.Name())
+	require.NoError(t, err)
+	got := map[string]float64{}
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == tagReasonKey {
+				got[tg.Value] = row.Data.(*view.SumData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{sampleAgeReasonTooOld: 1, sampleAgeReasonTooNew: 1}, got)
+
+	rows, err = view.RetrieveData(statDroppedSeries.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(1), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestSampleAgeLimiterClampOnce(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nowMs := timestampMs(now)
+	ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test")
+	clamped := newClampedSeries()
+	apply := func(t int64) (int64, bool) {
+		// Every scrape has its own limiter, the clamped series are shared.
+		l := newSampleAgeLimiter(time.Minute, 0, SampleAgeClamp, config.NewComponentID("prometheus"), clamped)
+		l.now = func() time.Time { return now }
+		return l.apply(context.Background(), ls, t)
+	}
+	stale := nowMs - time.Hour.Milliseconds()
+
+	got, keep := apply(stale)
+	assert.True(t, keep)
+	assert.Equal(t, nowMs-time.Minute.Milliseconds(), got)
+	// The same stale sample is dropped by the following scrapes.
+	now = now.Add(15 * time.Second)
+	_, keep = apply(stale)
+	assert.False(t, keep)
+	// A sample with another timestamp is clamped.
+	_, keep = apply(stale + 1000)
+	assert.True(t, keep)
+	// Once the series is in range again, it's clamped again when it goes out of range.
+	_, keep = apply(timestampMs(now))
+	assert.True(t, keep)
+	_, keep = apply(stale + 1000)
+	assert.True(t, keep)
+}
+
+func TestTransactionSampleAgeClampOnce(t *testing.T) {
+	rID := config.NewComponentID("prometheus")
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(nil)}
+	nowMs := timestampMs(time.Now())
+	stale := labels.FromStrings(model.MetricNameLabel, "bar", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	recent := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+	sink := new(consumertest.MetricsSink)
+	txc := &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys, sampleMaxAge: time.Hour, sampleAgeAction: SampleAgeClamp, clampedSeries: newClampedSeries()}
+	// Two scrapes of the same stale sample.
+	for i := int64(0); i < 2; i++ {
+		tr := newTransactionPdata(context.Background(), txc)
+		_, err := tr.Append(0, recent, nowMs+i*15000, 1.0)
+		require.NoError(t, err)
+		_, err = tr.Append(0, stale, nowMs-2*time.Hour.Milliseconds(), 1.0)
+		require.NoError(t, err)
+		require.NoError(t, tr.Commit())
+	}
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 2)
+	var names [][]string
+	for _, md := range mds {
+		metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		var scrape []string
+		for i := 0; i < metrics.Len(); i++ {
+			scrape = append(scrape, metrics.At(i).Name())
+		}
+		names = append(names, scrape)
+	}
+	assert.ElementsMatch(t, []string{"foo", "bar"}, names[0])
+	assert.Equal(t, []string{"foo"}, names[1])
+}
+
+func TestTransactionSampleAge(t *testing.T) {
+	rID := config.NewComponentID("prometheus")
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(nil)}
+	nowMs := timestampMs(time.Now())
+	recent := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+	stale := labels.FromStrings(model.MetricNameLabel, "bar", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+	tests := []struct {
+		action    string
+		wantNames []string
+	}{
+		{action: SampleAgeDrop, wantNames: []string{"foo"}},
+		{action: SampleAgeClamp, wantNames: []string{"foo", "bar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys, sampleMaxAge: time.Hour, sampleMaxFuture: time.Hour, sampleAgeAction: tt.action, clampedSeries: newClampedSeries()})
+			_, err := tr.Append(0, recent, nowMs, 1.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, stale, nowMs-2*time.Hour.Milliseconds(), 1.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			var names []string
+			for i := 0; i < metrics.Len(); i++ {
+				names = append(names, metrics.At(i).Name())
+				if metrics.At(i).Name() == "bar" {
+					ts := metrics.At(i).Gauge().DataPoints().At(0).Timestamp().AsTime()
+					assert.WithinDuration(t, time.Now().Add(-time.Hour), ts, time.Minute)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantNames, names)
+		})
+	}
+}
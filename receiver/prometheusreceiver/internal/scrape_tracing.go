@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	scrapeTracerName = "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+	scrapeSpanName   = "prometheus/scrape"
+
+	scrapeResultCommitted  = "committed"
+	scrapeResultFailed     = "failed"
+	scrapeResultRolledBack = "rolled_back"
+)
+
+// scrapeSpan traces a transaction, from the start of the scrape of a target to the commit or
+// rollback of its samples. The span is the parent of the spans of the downstream consumers.
+// A nil scrapeSpan traces nothing.
+type scrapeSpan struct {
+	span    trace.Span
+	samples int64
+}
+
+// startScrapeSpan starts the span of a transaction with the internal tracer of the collector,
+// it returns ctx and a nil scrapeSpan if the transactions aren't traced.
+func startScrapeSpan(ctx context.Context, set component.ReceiverCreateSettings, receiverID config.ComponentID, traceScrapes bool) (context.Context, *scrapeSpan) {
+	if !traceScrapes || set.TracerProvider == nil {
+		return ctx, nil
+	}
+	ctx, span := set.TracerProvider.Tracer(scrapeTracerName).Start(ctx, scrapeSpanName,
+		trace.WithAttributes(attribute.String("receiver", receiverID.String())))
+	return ctx, &scrapeSpan{span: span}
+}
+
+// setTarget records the target of the scrape once it is resolved.
+func (s *scrapeSpan) setTarget(job, instance string) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attribute.String(jobAttr, job), attribute.String(instanceAttr, instance))
+}
+
+// appended counts a sample appended to the transaction.
+func (s *scrapeSpan) appended() {
+	if s == nil {
+		return
+	}
+	s.samples++
+}
+
+// commit ends the span of a committed transaction, numPoints is the number of data points
+// sent to the next consumer.
+func (s *scrapeSpan) commit(numPoints int, err error) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attribute.Int("data_points", numPoints))
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+		s.end(scrapeResultFailed)
+		return
+	}
+	s.end(scrapeResultCommitted)
+}
+
+// rollback ends the span of a transaction whose samples are discarded, e.g. because the
+// scrape failed.
+func (s *scrapeSpan) rollback() {
+	if s == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, "transaction rolled back")
+	s.end(scrapeResultRolledBack)
+}
+
+func (s *scrapeSpan) end(result string) {
+	s.span.SetAttributes(attribute.Int64("samples", s.samples), attribute.String("result", result))
+	s.span.End()
+}
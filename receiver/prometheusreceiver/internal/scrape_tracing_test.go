@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestScrapeSpan(t *testing.T) {
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"foo": {Metric: "foo", Type: textparse.MetricTypeGauge},
+	})}
+	rID := config.NewComponentID("prometheus")
+	newSettings := func(recorder *tracetest.SpanRecorder) component.ReceiverCreateSettings {
+		set := componenttest.NewNopReceiverCreateSettings()
+		set.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		return set
+	}
+
+	tests := []struct {
+		name        string
+		newAppender func(set component.ReceiverCreateSettings, useStartTimeMetric, traceScrapes bool) storage.Appender
+	}{
+		{
+			name: "opencensus",
+			newAppender: func(set component.ReceiverCreateSettings, useStartTimeMetric, traceScrapes bool) storage.Appender {
+				return newTransaction(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), useStartTimeMetric: useStartTimeMetric, receiverID: rID, ms: ms, sink: consumertest.NewNop(), traceScrapes: traceScrapes, resourceAttrKeys: DefaultResourceAttributeKeys, settings: set})
+			},
+		},
+		{
+			name: "pdata",
+			newAppender: func(set component.ReceiverCreateSettings, useStartTimeMetric, traceScrapes bool) storage.Appender {
+				return newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), useStartTimeMetric: useStartTimeMetric, receiverID: rID, ms: ms, sink: consumertest.NewNop(), settings: set, traceScrapes: traceScrapes, resourceAttrKeys: DefaultResourceAttributeKeys})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := time.Now().Unix() * 1000
+			ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080")
+
+			t.Run("commit", func(t *testing.T) {
+				recorder := tracetest.NewSpanRecorder()
+				tr := tt.newAppender(newSettings(recorder), false, true)
+				_, err := tr.Append(0, ls, ts, 1.0)
+				require.NoError(t, err)
+				_, err = tr.Append(0, labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "bar", "baz"), ts, 2.0)
+				require.NoError(t, err)
+				require.NoError(t, tr.Commit())
+
+				spans := scrapeSpans(recorder)
+				require.Len(t, spans, 1)
+				assert.Equal(t, scrapeSpanName, spans[0].Name())
+				assert.Equal(t, codes.Unset, spans[0].Status().Code)
+				assert.ElementsMatch(t, []attribute.KeyValue{
+					attribute.String("receiver", "prometheus"),
+					attribute.String(jobAttr, "test"),
+					attribute.String(instanceAttr, "localhost:8080"),
+					attribute.Int("data_points", 2),
+					attribute.Int64("samples", 2),
+					attribute.String("result", scrapeResultCommitted),
+				}, spans[0].Attributes())
+			})
+
+			t.Run("commit error", func(t *testing.T) {
+				recorder := tracetest.NewSpanRecorder()
+				// The process_start_time_seconds metric is missing.
+				tr := tt.newAppender(newSettings(recorder), true, true)
+				_, err := tr.Append(0, ls, ts, 1.0)
+				require.NoError(t, err)
+				require.ErrorIs(t, tr.Commit(), errNoStartTimeMetrics)
+
+				spans := scrapeSpans(recorder)
+				require.Len(t, spans, 1)
+				assert.Equal(t, codes.Error, spans[0].Status().Code)
+				assert.Contains(t, spans[0].Attributes(), attribute.String("result", scrapeResultFailed))
+			})
+
+			t.Run("rollback", func(t *testing.T) {
+				recorder := tracetest.NewSpanRecorder()
+				tr := tt.newAppender(newSettings(recorder), false, true)
+				_, err := tr.Append(0, ls, ts, 1.0)
+				require.NoError(t, err)
+				require.NoError(t, tr.Rollback())
+
+				spans := scrapeSpans(recorder)
+				require.Len(t, spans, 1)
+				assert.Equal(t, codes.Error, spans[0].Status().Code)
+				assert.Contains(t, spans[0].Attributes(), attribute.Int64("samples", 1))
+				assert.Contains(t, spans[0].Attributes(), attribute.String("result", scrapeResultRolledBack))
+			})
+
+			t.Run("disabled", func(t *testing.T) {
+				recorder := tracetest.NewSpanRecorder()
+				tr := tt.newAppender(newSettings(recorder), false, false)
+				_, err := tr.Append(0, ls, ts, 1.0)
+				require.NoError(t, err)
+				require.NoError(t, tr.Commit())
+				assert.Empty(t, scrapeSpans(recorder))
+			})
+		})
+	}
+}
+
+// scrapeSpans returns the ended spans of the transactions, without the ones of obsreport.
+func scrapeSpans(recorder *tracetest.SpanRecorder) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == scrapeSpanName {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver/internal"
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// targetAttributes returns the values of the target labels listed in names, taken from the
+// labels of the first sample of a scrape, which carry the labels of the target after relabeling.
+// The labels missing from the sample are skipped.
+func targetAttributes(names []string, ls labels.Labels) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	attrs := make(map[string]string, len(names))
+	for _, name := range names {
+		if v := ls.Get(name); v != "" {
+			attrs[name] = v
+		}
+	}
+	return attrs
+}
+
+// addTargetAttributes inserts attrs in the attributes of every data point of metrics,
+// the attributes already set from the labels of the samples are kept.
+func addTargetAttributes(metrics pdata.MetricSlice, attrs map[string]string) {
+	if len(attrs) == 0 {
+		return
+	}
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		switch metric.DataType() {
+		case pdata.MetricDataTypeGauge:
+			dps := metric.Gauge().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				insertAttributes(dps.At(j).Attributes(), attrs)
+			}
+		case pdata.MetricDataTypeSum:
+			dps := metric.Sum().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				insertAttributes(dps.At(j).Attributes(), attrs)
+			}
+		case pdata.MetricDataTypeHistogram:
+			dps := metric.Histogram().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				insertAttributes(dps.At(j).Attributes(), attrs)
+			}
+		case pdata.MetricDataTypeSummary:
+			dps := metric.Summary().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				insertAttributes(dps.At(j).Attributes(), attrs)
+			}
+		}
+	}
+}
+
+func insertAttributes(dest pdata.AttributeMap, attrs map[string]string) {
+	for k, v := range attrs {
+		dest.InsertString(k, v)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestTargetAttributes(t *testing.T) {
+	ms := &mockMetadataProvider{mc: newMockMetadataCache(map[string]scrape.MetricMetadata{
+		"foo": {Metric: "foo", Type: textparse.MetricTypeGauge},
+	})}
+	rID := config.NewComponentID("prometheus")
+	targetLabels := []string{model.JobLabel, model.InstanceLabel, "team", "missing"}
+
+	tests := []struct {
+		name        string
+		newAppender func(sink *consumertest.MetricsSink) storage.Appender
+	}{
+		{
+			name: "opencensus",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransaction(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, targetLabels: targetLabels, resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+			},
+		},
+		{
+			name: "pdata",
+			newAppender: func(sink *consumertest.MetricsSink) storage.Appender {
+				return newTransactionPdata(context.Background(), &txConfig{jobsMap: NewJobsMapPdata(time.Minute, 0, rID), receiverID: rID, ms: ms, sink: sink, settings: componenttest.NewNopReceiverCreateSettings(), targetLabels: targetLabels, resourceAttrKeys: DefaultResourceAttributeKeys})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			tr := tt.newAppender(sink)
+			ls := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "team", "a")
+			overridden := labels.FromStrings(model.MetricNameLabel, "foo", model.JobLabel, "test", model.InstanceLabel, "localhost:8080", "team", "b", "pod", "p")
+			ts := time.Now().Unix() * 1000
+			_, err := tr.Append(0, ls, ts, 1.0)
+			require.NoError(t, err)
+			_, err = tr.Append(0, overridden, ts, 2.0)
+			require.NoError(t, err)
+			require.NoError(t, tr.Commit())
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			metrics := mds[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			require.Equal(t, 1, metrics.Len())
+			dps := metrics.At(0).Gauge().DataPoints()
+			require.Equal(t, 2, dps.Len())
+			got := make(map[float64]map[string]interface{}, dps.Len())
+			for i := 0; i < dps.Len(); i++ {
+				got[dps.At(i).DoubleVal()] = dps.At(i).Attributes().AsRaw()
+			}
+			assert.Equal(t, map[string]interface{}{"job": "test", "instance": "localhost:8080", "team": "a"}, got[1.0])
+			assert.Equal(t, map[string]interface{}{"job": "test", "instance": "localhost:8080", "team": "b", "pod": "p"}, got[2.0])
+		})
+	}
+}
@@ -28,7 +28,6 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
-	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/model/pdata"
@@ -53,6 +52,7 @@ var errMetricNameNotFound = errors.New("metricName not found from labels")
 var errTransactionAborted = errors.New("transaction aborted")
 var errNoJobInstance = errors.New("job or instance cannot be found from labels")
 var errNoStartTimeMetrics = errors.New("process_start_time_seconds metric is missing")
+var errTargetNotResolved = errors.New("target of the scrape not resolved yet")
 
 // A transaction is corresponding to an individual scrape operation or stale report.
 // That said, whenever prometheus receiver scrapped a target metric endpoint a page of raw metrics is returned,
@@ -70,43 +70,87 @@ type transaction struct {
 	jobsMap              *JobsMapPdata
 	useStartTimeMetric   bool
 	startTimeMetricRegex string
-	ms                   *metadataService
+	ms                   metadataProvider
 	node                 *commonpb.Node
 	resource             *resourcepb.Resource
 	metricBuilder        *metricBuilder
-	externalLabels       labels.Labels
+	externalLabels       *externalLabelsAppender
 	logger               *zap.Logger
+	receiverID           config.ComponentID
 	obsrecv              *obsreport.Receiver
 	startTimeMs          int64
+	duplicates           *duplicateSampleDetector
+	missingMetadata      *missingMetadataHandler
+	labelValueLimit      *labelValueLimiter
+	sampleAge            *sampleAgeLimiter
+	targetLabels         []string
+	targetAttributes     map[string]string
+	jobResourceAttrs     map[string]map[string]string
+	resourceAttrKeys     ResourceAttributeKeys
+	honorLabelsJobs      map[string]bool
+	infoMetrics          string
+	span                 *scrapeSpan
+	// honorLabels is whether the scrape config of the target honors labels.
+	honorLabels bool
+	// pending holds the samples appended before the target of the scrape is resolved.
+	pending []pendingSample
+	// honored holds the resources of the series whose job or instance, honored from the
+	// scraped data with honor_labels, differ from the ones of the target.
+	honored      map[string]*honoredResource
+	honoredOrder []*honoredResource
 }
 
-func newTransaction(
-	ctx context.Context,
-	jobsMap *JobsMapPdata,
-	useStartTimeMetric bool,
-	startTimeMetricRegex string,
-	receiverID config.ComponentID,
-	ms *metadataService,
-	sink consumer.Metrics,
-	externalLabels labels.Labels,
-	set component.ReceiverCreateSettings) *transaction {
+// pendingSample is a sample appended before the target of the scrape is resolved.
+type pendingSample struct {
+	ls labels.Labels
+	t  int64
+	v  float64
+}
+
+// honoredResource builds the metrics of the series of a scrape sharing a job and instance
+// honored from the scraped data.
+type honoredResource struct {
+	job, instance string
+	node          *commonpb.Node
+	resource      *resourcepb.Resource
+	metricBuilder *metricBuilder
+}
+
+// honoredResourceKey returns the key of the resource of the series with the given job and instance.
+func honoredResourceKey(job, instance string) string {
+	return job + "\xff" + instance
+}
+
+func newTransaction(ctx context.Context, txc *txConfig) *transaction {
+	ctx, span := startScrapeSpan(ctx, txc.settings, txc.receiverID, txc.traceScrapes)
 	return &transaction{
 		id:                   atomic.AddInt64(&idSeq, 1),
 		ctx:                  ctx,
 		isNew:                true,
-		sink:                 sink,
-		jobsMap:              jobsMap,
-		useStartTimeMetric:   useStartTimeMetric,
-		startTimeMetricRegex: startTimeMetricRegex,
-		ms:                   ms,
-		externalLabels:       externalLabels,
-		logger:               set.Logger,
+		sink:                 txc.sink,
+		jobsMap:              txc.jobsMap,
+		useStartTimeMetric:   txc.useStartTimeMetric,
+		startTimeMetricRegex: txc.startTimeMetricRegex,
+		ms:                   txc.ms,
+		externalLabels:       newExternalLabelsAppender(txc.externalLabels),
+		logger:               txc.settings.Logger,
+		receiverID:           txc.receiverID,
 		obsrecv: obsreport.NewReceiver(obsreport.ReceiverSettings{
-			ReceiverID:             receiverID,
+			ReceiverID:             txc.receiverID,
 			Transport:              transport,
-			ReceiverCreateSettings: set,
+			ReceiverCreateSettings: txc.settings,
 		}),
-		startTimeMs: -1,
+		startTimeMs:      -1,
+		duplicates:       newDuplicateSampleDetector(txc.duplicateSamples, txc.receiverID),
+		missingMetadata:  newMissingMetadataHandler(txc.missingMetadata, txc.receiverID, txc.unknownTargets),
+		labelValueLimit:  newLabelValueLimiter(txc.labelValueMaxLength, txc.labelValueAction, txc.receiverID),
+		sampleAge:        newSampleAgeLimiter(txc.sampleMaxAge, txc.sampleMaxFuture, txc.sampleAgeAction, txc.receiverID, txc.clampedSeries),
+		targetLabels:     txc.targetLabels,
+		jobResourceAttrs: txc.jobResourceAttrs,
+		resourceAttrKeys: txc.resourceAttrKeys,
+		honorLabelsJobs:  txc.honorLabelsJobs,
+		infoMetrics:      txc.infoMetrics,
+		span:             span,
 	}
 }
 
@@ -124,16 +168,79 @@ func (tr *transaction) Append(ref storage.SeriesRef, ls labels.Labels, t int64,
 		return 0, errTransactionAborted
 	default:
 	}
-	if len(tr.externalLabels) > 0 {
-		// TODO(jbd): Improve the allocs.
-		ls = append(ls, tr.externalLabels...)
+	tr.span.appended()
+	ls, keep := tr.labelValueLimit.apply(tr.ctx, ls)
+	if !keep {
+		return 0, nil
+	}
+	t, keep = tr.sampleAge.apply(tr.ctx, ls, t)
+	if !keep {
+		return 0, nil
+	}
+	if err := tr.duplicates.check(tr.ctx, ls); err != nil {
+		return 0, err
 	}
+	ls = tr.externalLabels.append(ls)
 	if tr.isNew {
-		if err := tr.initTransaction(ls); err != nil {
+		if err := tr.initTransaction(ls, mayBeHonored(tr.honorLabelsJobs, ls)); err != nil {
+			if errors.Is(err, errTargetNotResolved) {
+				tr.pending = append(tr.pending, pendingSample{ls: ls, t: t, v: v})
+				return 0, nil
+			}
 			return 0, err
 		}
+		if err := tr.appendPending(); err != nil {
+			return 0, err
+		}
+	}
+	return 0, tr.addDataPoint(ls, t, v)
+}
+
+func (tr *transaction) addDataPoint(ls labels.Labels, t int64, v float64) error {
+	if !tr.missingMetadata.check(tr.ctx, tr.metricBuilder.mc, ls) {
+		return nil
+	}
+	return tr.builderFor(ls).AddDataPoint(ls, t, v)
+}
+
+// appendPending adds the samples appended before the target of the scrape was resolved.
+func (tr *transaction) appendPending() error {
+	pending := tr.pending
+	tr.pending = nil
+	for _, s := range pending {
+		if err := tr.addDataPoint(s.ls, s.t, s.v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builderFor returns the builder of the resource of the series. With honor_labels, the series
+// whose job or instance differ from the ones of the target get a resource of their own.
+func (tr *transaction) builderFor(ls labels.Labels) *metricBuilder {
+	if !tr.honorLabels {
+		return tr.metricBuilder
+	}
+	job, instance := ls.Get(model.JobLabel), ls.Get(model.InstanceLabel)
+	if job == tr.job && instance == tr.instance {
+		return tr.metricBuilder
+	}
+	key := honoredResourceKey(job, instance)
+	hr, ok := tr.honored[key]
+	if !ok {
+		hr = &honoredResource{
+			job:           job,
+			instance:      instance,
+			metricBuilder: newMetricBuilder(tr.metricBuilder.mc, tr.useStartTimeMetric, tr.startTimeMetricRegex, tr.logger, tr.startTimeMs),
+		}
+		hr.node, hr.resource = createNodeAndResource(job, instance, tr.metricBuilder.mc.SharedLabels().Get(model.SchemeLabel), tr.jobResourceAttrs[tr.job], tr.resourceAttrKeys)
+		if tr.honored == nil {
+			tr.honored = make(map[string]*honoredResource)
+		}
+		tr.honored[key] = hr
+		tr.honoredOrder = append(tr.honoredOrder, hr)
 	}
-	return 0, tr.metricBuilder.AddDataPoint(ls, t, v)
+	return hr.metricBuilder
 }
 
 func (tr *transaction) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
@@ -145,21 +252,38 @@ func (tr *transaction) AddFast(_ uint64, _ int64, _ float64) error {
 	return storage.ErrNotFound
 }
 
-func (tr *transaction) initTransaction(ls labels.Labels) error {
+// mayBeHonored returns whether the job and instance of ls may have been honored from the scraped
+// data instead of being the ones of the target, given the honor_labels of the scrape configs: the
+// job of ls is either the one of a scrape config honoring labels, or none of the scrape configs.
+func mayBeHonored(honorLabelsJobs map[string]bool, ls labels.Labels) bool {
+	if len(honorLabelsJobs) == 0 {
+		return false
+	}
+	honorLabels, ok := honorLabelsJobs[ls.Get(model.JobLabel)]
+	return honorLabels || !ok
+}
+
+// initTransaction discovers the target of the scrape from the job and instance of ls. When
+// deferUnknownTarget is set, i.e. with honor_labels, the job and instance of ls may have been
+// honored from the scraped data instead of being the ones of the target: errTargetNotResolved is
+// returned if there is no such target, so that the target is resolved from a later sample, at the
+// latest from the scrape report samples which always carry the labels of the target.
+func (tr *transaction) initTransaction(ls labels.Labels, deferUnknownTarget bool) error {
 	job, instance := ls.Get(model.JobLabel), ls.Get(model.InstanceLabel)
 	if job == "" || instance == "" {
 		return errNoJobInstance
 	}
 	// discover the binding target when this method is called for the first time during a transaction
-	mc, err := tr.ms.Get(job, instance)
+	mc, err := tr.missingMetadata.targetMetadata(tr.ms, job, instance, deferUnknownTarget, tr.logger)
 	if err != nil {
 		return err
 	}
-	if tr.jobsMap != nil {
-		tr.job = job
-		tr.instance = instance
-	}
-	tr.node, tr.resource = createNodeAndResource(job, instance, mc.SharedLabels().Get(model.SchemeLabel))
+	tr.job = job
+	tr.instance = instance
+	tr.honorLabels = tr.honorLabelsJobs[job]
+	tr.span.setTarget(job, instance)
+	tr.node, tr.resource = createNodeAndResource(job, instance, mc.SharedLabels().Get(model.SchemeLabel), tr.jobResourceAttrs[job], tr.resourceAttrKeys)
+	tr.targetAttributes = targetAttributes(tr.targetLabels, ls)
 	tr.metricBuilder = newMetricBuilder(mc, tr.useStartTimeMetric, tr.startTimeMetricRegex, tr.logger, tr.startTimeMs)
 	tr.isNew = false
 	return nil
@@ -167,57 +291,106 @@ func (tr *transaction) initTransaction(ls labels.Labels) error {
 
 // Commit submits metrics data to consumers.
 func (tr *transaction) Commit() error {
+	numPoints, err := tr.commit()
+	tr.span.commit(numPoints, err)
+	return err
+}
+
+// commit returns the number of data points submitted to the consumers.
+func (tr *transaction) commit() (int, error) {
 	if tr.isNew {
 		// In a situation like not able to connect to the remote server, scrapeloop will still commit even if it had
 		// never added any data points, that the transaction has not been initialized.
-		return nil
+		if len(tr.pending) == 0 {
+			return 0, nil
+		}
+		// None of the samples carry the job and instance of a target, the target is looked up
+		// from the first sample as if honor_labels was disabled.
+		if err := tr.initTransaction(tr.pending[0].ls, false); err != nil {
+			tr.pending = nil
+			return 0, err
+		}
+		if err := tr.appendPending(); err != nil {
+			return 0, err
+		}
 	}
 
 	tr.startTimeMs = -1
 
 	ctx := tr.obsrecv.StartMetricsOp(tr.ctx)
-	metrics, _, _, err := tr.metricBuilder.Build()
+	md, err := tr.buildMetrics(tr.metricBuilder, tr.node, tr.resource, tr.job, tr.instance)
 	if err != nil {
-		// Only error by Build() is errNoDataToBuild, with numReceivedPoints set to zero.
 		tr.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
-		return err
+		return 0, err
+	}
+	for _, hr := range tr.honoredOrder {
+		hmd, err := tr.buildMetrics(hr.metricBuilder, hr.node, hr.resource, hr.job, hr.instance)
+		if err != nil {
+			tr.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
+			return 0, err
+		}
+		hmd.ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
 	}
 
+	if tr.infoMetrics == InfoMetricsResource {
+		convertInfoMetrics(md)
+	}
+
+	numPoints := md.DataPointCount()
+	if numPoints > 0 {
+		err = tr.sink.ConsumeMetrics(ctx, md)
+	}
+	tr.obsrecv.EndMetricsOp(ctx, dataformat, numPoints, err)
+	return numPoints, err
+}
+
+// buildMetrics builds the metrics of a resource of the scrape and adjusts their start time.
+func (tr *transaction) buildMetrics(mb *metricBuilder, node *commonpb.Node, resource *resourcepb.Resource, job, instance string) (pdata.Metrics, error) {
+	metrics, numTimeseries, droppedTimeseries, err := mb.Build()
+	if err != nil {
+		// Only error by Build() is errNoDataToBuild, with numReceivedPoints set to zero.
+		return pdata.Metrics{}, err
+	}
+	recordDroppedSeries(tr.ctx, tr.receiverID, job, dropReasonInvalidType, droppedTimeseries)
+
 	if tr.useStartTimeMetric {
 		// startTime is mandatory in this case, but may be zero when the
 		// process_start_time_seconds metric is missing from the target endpoint.
 		if tr.metricBuilder.startTime == 0.0 {
 			// Since we are unable to adjust metrics properly, we will drop them
 			// and return an error.
-			err = errNoStartTimeMetrics
-			tr.obsrecv.EndMetricsOp(ctx, dataformat, 0, err)
-			return err
+			if !mb.fresh {
+				recordDroppedSeries(tr.ctx, tr.receiverID, job, dropReasonStaleOnly, numTimeseries-droppedTimeseries)
+			}
+			return pdata.Metrics{}, errNoStartTimeMetrics
 		}
 
 		adjustStartTimestamp(tr.metricBuilder.startTime, metrics)
 	}
 
-	numPoints := 0
-	var md pdata.Metrics
+	md := pdata.NewMetrics()
 	if len(metrics) > 0 {
-		md = opencensus.OCToMetrics(tr.node, tr.resource, metrics)
+		md = opencensus.OCToMetrics(node, resource, metrics)
 		fixStaleMetrics(&md)
-		numPoints = md.DataPointCount()
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ilms := rms.At(i).InstrumentationLibraryMetrics()
+			for j := 0; j < ilms.Len(); j++ {
+				addTargetAttributes(ilms.At(j).Metrics(), tr.targetAttributes)
+			}
+		}
 	}
 
 	if !tr.useStartTimeMetric {
-		_ = NewMetricsAdjusterPdata(tr.jobsMap.get(tr.job, tr.instance), tr.logger).AdjustMetrics(&md)
-	}
-
-	if numPoints > 0 {
-		err = tr.sink.ConsumeMetrics(ctx, md)
+		_ = NewMetricsAdjusterPdata(tr.jobsMap.get(job, instance), tr.logger).AdjustMetrics(&md)
 	}
-	tr.obsrecv.EndMetricsOp(ctx, dataformat, numPoints, err)
-	return err
+	return md, nil
 }
 
 func (tr *transaction) Rollback() error {
 	tr.startTimeMs = -1
+	tr.pending = nil
+	tr.span.rollback()
 	return nil
 }
 
@@ -244,7 +417,10 @@ func timestampFromFloat64(ts float64) *timestamppb.Timestamp {
 	}
 }
 
-func createNodeAndResource(job, instance, scheme string) (*commonpb.Node, *resourcepb.Resource) {
+// createNodeAndResource returns the node and resource of the target. The port and scheme labels are
+// added with the keys of attrKeys, and the attributes configured for the scrape job are added to the
+// resource, without overriding the ones of the target.
+func createNodeAndResource(job, instance, scheme string, jobAttrs map[string]string, attrKeys ResourceAttributeKeys) (*commonpb.Node, *resourcepb.Resource) {
 	host, port, err := net.SplitHostPort(instance)
 	if err != nil {
 		host = instance
@@ -264,10 +440,19 @@ func createNodeAndResource(job, instance, scheme string) (*commonpb.Node, *resou
 		Labels: map[string]string{
 			jobAttr:      job,
 			instanceAttr: instance,
-			portAttr:     port,
-			schemeAttr:   scheme,
 		},
 	}
+	if attrKeys.Port != "" {
+		resource.Labels[attrKeys.Port] = port
+	}
+	if attrKeys.Scheme != "" {
+		resource.Labels[attrKeys.Scheme] = scheme
+	}
+	for k, v := range jobAttrs {
+		if _, ok := resource.Labels[k]; !ok {
+			resource.Labels[k] = v
+		}
+	}
 	return node, resource
 }
 
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build allocs
+// +build allocs
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+)
+
+// allocationBudgets are the maximum average numbers of allocations per series of a scrape of
+// 1000 series, once the jobs map is initialized. They are the measured numbers with some headroom,
+// to be lowered when a change reduces the allocations.
+var allocationBudgets = map[string]map[string]float64{
+	"transaction": {
+		"counters":   26,
+		"histograms": 8,
+	},
+	"transactionPdata": {
+		"counters":   22,
+		"histograms": 6,
+	},
+}
+
+// TestTransactionAllocationBudget fails when a change increases the allocations of the transactions.
+// The numbers of allocations depend on the build, e.g. they are higher with -race, so it is only
+// built with the allocs tag: go test -tags=allocs -run=TestTransactionAllocationBudget
+func TestTransactionAllocationBudget(t *testing.T) {
+	const numSeries = 1000
+	for _, w := range benchmarkWorkloads {
+		page := w.newPage(numSeries)
+		ms := &mockMetadataProvider{mc: newMockMetadataCache(page.metadata)}
+		for _, tx := range benchmarkTransactions {
+			t.Run(tx.name+"/"+w.name, func(t *testing.T) {
+				jobsMap := NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus"))
+				require.NoError(t, page.scrape(tx.newAppender(jobsMap, ms), 0))
+				n := 0
+				allocs := testing.AllocsPerRun(10, func() {
+					n++
+					if err := page.scrape(tx.newAppender(jobsMap, ms), n); err != nil {
+						t.Fatal(err)
+					}
+				})
+				perSeries := allocs / float64(len(page.series))
+				t.Logf("%.2f allocations per series", perSeries)
+				assert.LessOrEqual(t, perSeries, allocationBudgets[tx.name][w.name])
+			})
+		}
+	}
+}
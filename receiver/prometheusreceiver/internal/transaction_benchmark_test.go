@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+const (
+	// seriesPerFamily is the number of label sets of each metric family of the generated pages.
+	seriesPerFamily = 100
+	// scrapeIntervalMs is the interval between the timestamps of two consecutive scrapes.
+	scrapeIntervalMs = 15000
+)
+
+// histogramBounds are the upper bounds of the buckets of the generated histograms, +Inf excluded.
+var histogramBounds = []string{"0.005", "0.01", "0.025", "0.05", "0.1", "0.25", "0.5", "1", "2.5", "5", "10"}
+
+// scrapePage is a generated scrape page: the series in exposition order, the value of each series
+// on the first scrape, and the metadata of the metric families.
+type scrapePage struct {
+	series   []labels.Labels
+	values   []float64
+	metadata map[string]scrape.MetricMetadata
+}
+
+// newCounterPage generates a page of numSeries counters, grouped in families of seriesPerFamily series.
+func newCounterPage(numSeries int) *scrapePage {
+	page := &scrapePage{metadata: map[string]scrape.MetricMetadata{}}
+	for i := 0; i < numSeries; i++ {
+		name := "counter_" + strconv.Itoa(i/seriesPerFamily) + "_total"
+		page.metadata[name] = scrape.MetricMetadata{Metric: name, Type: textparse.MetricTypeCounter}
+		page.append(labels.FromStrings(
+			model.MetricNameLabel, name,
+			model.InstanceLabel, "localhost:8080",
+			model.JobLabel, "test",
+			"id", strconv.Itoa(i%seriesPerFamily)), 1)
+	}
+	return page
+}
+
+// newHistogramPage generates a page of about numSeries series, all of them part of histograms with
+// len(histogramBounds)+1 buckets, grouped in families of seriesPerFamily series.
+func newHistogramPage(numSeries int) *scrapePage {
+	page := &scrapePage{metadata: map[string]scrape.MetricMetadata{}}
+	seriesPerHistogram := len(histogramBounds) + 3
+	for i := 0; i < numSeries/seriesPerHistogram; i++ {
+		family := "histogram_" + strconv.Itoa(i*seriesPerHistogram/seriesPerFamily)
+		page.metadata[family] = scrape.MetricMetadata{Metric: family, Type: textparse.MetricTypeHistogram}
+		id := strconv.Itoa(i)
+		series := func(name string, pairs ...string) labels.Labels {
+			return labels.FromStrings(append([]string{
+				model.MetricNameLabel, name,
+				model.InstanceLabel, "localhost:8080",
+				model.JobLabel, "test",
+				"id", id}, pairs...)...)
+		}
+		for j, bound := range histogramBounds {
+			page.append(series(family+"_bucket", model.BucketLabel, bound), float64(j+1))
+		}
+		page.append(series(family+"_bucket", model.BucketLabel, "+Inf"), float64(len(histogramBounds)+1))
+		page.append(series(family+"_sum"), 42)
+		page.append(series(family+"_count"), float64(len(histogramBounds)+1))
+	}
+	return page
+}
+
+func (p *scrapePage) append(ls labels.Labels, v float64) {
+	p.series = append(p.series, ls)
+	p.values = append(p.values, v)
+}
+
+// scrape appends the samples of the n-th scrape of the page and commits them. The values grow with
+// n so that the counters and histograms are not reset between scrapes.
+func (p *scrapePage) scrape(app storage.Appender, n int) error {
+	ts := time.Now().UnixNano()/int64(time.Millisecond) + int64(n)*scrapeIntervalMs
+	for i, ls := range p.series {
+		if _, err := app.Append(0, ls, ts, p.values[i]*float64(n+1)); err != nil {
+			return err
+		}
+	}
+	return app.Commit()
+}
+
+var benchmarkWorkloads = []struct {
+	name    string
+	newPage func(numSeries int) *scrapePage
+}{
+	{name: "counters", newPage: newCounterPage},
+	{name: "histograms", newPage: newHistogramPage},
+}
+
+var benchmarkTransactions = []struct {
+	name        string
+	newAppender func(jobsMap *JobsMapPdata, ms metadataProvider) storage.Appender
+}{
+	{
+		name: "transaction",
+		newAppender: func(jobsMap *JobsMapPdata, ms metadataProvider) storage.Appender {
+			return newTransaction(context.Background(), &txConfig{jobsMap: jobsMap, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), resourceAttrKeys: DefaultResourceAttributeKeys, settings: componenttest.NewNopReceiverCreateSettings()})
+		},
+	},
+	{
+		name: "transactionPdata",
+		newAppender: func(jobsMap *JobsMapPdata, ms metadataProvider) storage.Appender {
+			return newTransactionPdata(context.Background(), &txConfig{jobsMap: jobsMap, receiverID: config.NewComponentID("prometheus"), ms: ms, sink: consumertest.NewNop(), settings: componenttest.NewNopReceiverCreateSettings(), resourceAttrKeys: DefaultResourceAttributeKeys})
+		},
+	},
+}
+
+// BenchmarkTransactionAppendCommit appends and commits the samples of scrape pages of 1k, 10k and
+// 100k series. The jobs map is shared by the iterations, as it is by the scrapes of a target.
+func BenchmarkTransactionAppendCommit(b *testing.B) {
+	for _, w := range benchmarkWorkloads {
+		for _, numSeries := range []int{1000, 10000, 100000} {
+			page := w.newPage(numSeries)
+			ms := &mockMetadataProvider{mc: newMockMetadataCache(page.metadata)}
+			for _, tx := range benchmarkTransactions {
+				b.Run(fmt.Sprintf("%s/%s/%d", tx.name, w.name, numSeries), func(b *testing.B) {
+					jobsMap := NewJobsMapPdata(time.Minute, 0, config.NewComponentID("prometheus"))
+					// The first scrape of a target initializes the jobs map, it is not representative.
+					if err := page.scrape(tx.newAppender(jobsMap, ms), 0); err != nil {
+						b.Fatal(err)
+					}
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 1; i <= b.N; i++ {
+						if err := page.scrape(tx.newAppender(jobsMap, ms), i); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
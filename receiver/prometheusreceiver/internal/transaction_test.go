@@ -65,7 +65,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Commit Without Adding", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
@@ -73,7 +73,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Rollback dose nothing", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if got := tr.Rollback(); got != nil {
 			t.Errorf("expecting nil from Rollback() but got err %v", got)
 		}
@@ -82,7 +82,7 @@ func Test_transaction(t *testing.T) {
 	badLabels := labels.Labels([]labels.Label{{Name: "foo", Value: "bar"}})
 	t.Run("Add One No Target", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if _, got := tr.Append(0, badLabels, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -94,7 +94,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "foo", Value: "bar"}})
 	t.Run("Add One Job not found", func(t *testing.T) {
 		nomc := consumertest.NewNop()
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, nomc, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: nomc, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if _, got := tr.Append(0, jobNotFoundLb, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -105,7 +105,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "__name__", Value: "foo"}})
 	t.Run("Add One Good", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, sink, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: sink, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -113,7 +113,7 @@ func Test_transaction(t *testing.T) {
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
-		expectedNode, expectedResource := createNodeAndResource("test", "localhost:8080", "http")
+		expectedNode, expectedResource := createNodeAndResource("test", "localhost:8080", "http", nil, DefaultResourceAttributeKeys)
 		mds := sink.AllMetrics()
 		if len(mds) != 1 {
 			t.Fatalf("wanted one batch, got %v\n", sink.AllMetrics())
@@ -139,7 +139,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Error when start time is zero", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rID, ms, sink, nil, testTelemetry.ToReceiverCreateSettings())
+		tr := newTransaction(context.Background(), &txConfig{useStartTimeMetric: true, receiverID: rID, ms: ms, sink: sink, resourceAttrKeys: DefaultResourceAttributeKeys, settings: testTelemetry.ToReceiverCreateSettings()})
 		if _, got := tr.Append(0, goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
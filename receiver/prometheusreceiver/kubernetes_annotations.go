@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/common/model"
+)
+
+const defaultKubernetesAnnotationsJobName = "kubernetes-pods"
+
+// validate checks the namespaces are not empty and not both discovered and excluded.
+func (ka *KubernetesAnnotationsConfig) validate() error {
+	if !ka.Enabled {
+		return nil
+	}
+	if ka.ScrapeInterval < 0 {
+		return fmt.Errorf("kubernetes_annotations.scrape_interval has to be positive, got %v", ka.ScrapeInterval)
+	}
+	included := make(map[string]bool, len(ka.Namespaces))
+	for _, ns := range ka.Namespaces {
+		if ns == "" {
+			return errors.New("kubernetes_annotations.namespaces cannot contain empty names")
+		}
+		included[ns] = true
+	}
+	for _, ns := range ka.ExcludeNamespaces {
+		if ns == "" {
+			return errors.New("kubernetes_annotations.exclude_namespaces cannot contain empty names")
+		}
+		if included[ns] {
+			return fmt.Errorf("namespace %q cannot be both in kubernetes_annotations.namespaces and exclude_namespaces", ns)
+		}
+	}
+	return nil
+}
+
+// scrapeConfig returns the scrape config of the pods annotated with prometheus.io/scrape: "true", in the
+// form of the scrape_configs entries of the Prometheus configuration so that the defaults and validation
+// of Prometheus apply to it. The prometheus.io/scheme, prometheus.io/path and prometheus.io/port
+// annotations override the scheme, the metrics path and the port scraped. The pods which are not running
+// are left out, as well as their init containers.
+func (ka *KubernetesAnnotationsConfig) scrapeConfig() map[string]interface{} {
+	sdConfig := map[string]interface{}{"role": "pod"}
+	if len(ka.Namespaces) > 0 {
+		sdConfig["namespaces"] = map[string]interface{}{"names": ka.Namespaces}
+	}
+	if ka.NodeName != "" {
+		sdConfig["selectors"] = []interface{}{
+			map[string]interface{}{"role": "pod", "field": "spec.nodeName=" + ka.NodeName},
+		}
+	}
+
+	relabelConfigs := []interface{}{
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_scrape"},
+			"regex":         "true",
+			"action":        "keep",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_phase"},
+			"regex":         "Pending|Succeeded|Failed",
+			"action":        "drop",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_container_init"},
+			"regex":         "true",
+			"action":        "drop",
+		},
+	}
+	if len(ka.ExcludeNamespaces) > 0 {
+		quoted := make([]string, len(ka.ExcludeNamespaces))
+		for i, ns := range ka.ExcludeNamespaces {
+			quoted[i] = regexp.QuoteMeta(ns)
+		}
+		relabelConfigs = append(relabelConfigs, map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_namespace"},
+			"regex":         strings.Join(quoted, "|"),
+			"action":        "drop",
+		})
+	}
+	relabelConfigs = append(relabelConfigs,
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_scheme"},
+			"regex":         "(https?)",
+			"target_label":  "__scheme__",
+			"action":        "replace",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_path"},
+			"regex":         "(.+)",
+			"target_label":  "__metrics_path__",
+			"action":        "replace",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__address__", "__meta_kubernetes_pod_annotation_prometheus_io_port"},
+			"regex":         `([^:]+)(?::\d+)?;(\d+)`,
+			"replacement":   "$1:$2",
+			"target_label":  "__address__",
+			"action":        "replace",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_namespace"},
+			"target_label":  "kubernetes_namespace",
+			"action":        "replace",
+		},
+		map[string]interface{}{
+			"source_labels": []interface{}{"__meta_kubernetes_pod_name"},
+			"target_label":  "kubernetes_pod_name",
+			"action":        "replace",
+		},
+	)
+
+	jobName := ka.JobName
+	if jobName == "" {
+		jobName = defaultKubernetesAnnotationsJobName
+	}
+	scrapeConfig := map[string]interface{}{
+		"job_name":              jobName,
+		"kubernetes_sd_configs": []interface{}{sdConfig},
+		"relabel_configs":       relabelConfigs,
+	}
+	if ka.ScrapeInterval > 0 {
+		scrapeConfig["scrape_interval"] = model.Duration(ka.ScrapeInterval).String()
+	}
+	return scrapeConfig
+}
+
+// addScrapeConfig appends the scrape config of the annotated pods to the scrape_configs of the
+// Prometheus configuration.
+func (ka *KubernetesAnnotationsConfig) addScrapeConfig(promCfg map[string]interface{}) {
+	scrapeConfigs, _ := promCfg["scrape_configs"].([]interface{})
+	promCfg["scrape_configs"] = append(scrapeConfigs, ka.scrapeConfig())
+}
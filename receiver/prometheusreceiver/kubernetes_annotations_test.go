@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/kubernetes"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+	"gopkg.in/yaml.v2"
+)
+
+func TestLoadConfigKubernetesAnnotations(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config-kubernetes-annotations.yaml"), factories)
+	require.NoError(t, err)
+
+	r0 := cfg.Receivers[config.NewComponentID(typeStr)].(*Config)
+	require.Len(t, r0.PrometheusConfig.ScrapeConfigs, 1)
+	sc := r0.PrometheusConfig.ScrapeConfigs[0]
+	assert.Equal(t, "kubernetes-pods", sc.JobName)
+	assert.Equal(t, model.Duration(30*time.Second), sc.ScrapeInterval)
+	assert.Equal(t, "/metrics", sc.MetricsPath)
+	require.Len(t, sc.ServiceDiscoveryConfigs, 1)
+	sd := sc.ServiceDiscoveryConfigs[0].(*kubernetes.SDConfig)
+	assert.Equal(t, kubernetes.RolePod, sd.Role)
+	assert.Equal(t, []string{"apps", "monitoring"}, sd.NamespaceDiscovery.Names)
+	assert.Empty(t, sd.Selectors)
+
+	r1 := cfg.Receivers[config.NewComponentIDWithName(typeStr, "node")].(*Config)
+	require.Len(t, r1.PrometheusConfig.ScrapeConfigs, 2)
+	assert.Equal(t, "demo", r1.PrometheusConfig.ScrapeConfigs[0].JobName)
+	sc = r1.PrometheusConfig.ScrapeConfigs[1]
+	assert.Equal(t, "node-pods", sc.JobName)
+	assert.Equal(t, r1.PrometheusConfig.GlobalConfig.ScrapeInterval, sc.ScrapeInterval)
+	sd = sc.ServiceDiscoveryConfigs[0].(*kubernetes.SDConfig)
+	assert.Empty(t, sd.NamespaceDiscovery.Names)
+	assert.Equal(t, []kubernetes.SelectorConfig{{Role: kubernetes.RolePod, Field: "spec.nodeName=node-1"}}, sd.Selectors)
+}
+
+func TestKubernetesAnnotationsRelabeling(t *testing.T) {
+	ka := KubernetesAnnotationsConfig{Enabled: true, ExcludeNamespaces: []string{"kube-system", "istio.system"}}
+	cfg := &Config{}
+	promCfg := map[string]interface{}{}
+	ka.addScrapeConfig(promCfg)
+	out, err := yaml.Marshal(promCfg)
+	require.NoError(t, err)
+	require.NoError(t, yaml.UnmarshalStrict(out, &cfg.PrometheusConfig))
+	relabelConfigs := cfg.PrometheusConfig.ScrapeConfigs[0].RelabelConfigs
+
+	pod := map[string]string{
+		model.AddressLabel:                                      "10.0.0.1:8080",
+		model.SchemeLabel:                                       "http",
+		model.MetricsPathLabel:                                  "/metrics",
+		"__meta_kubernetes_namespace":                           "apps",
+		"__meta_kubernetes_pod_name":                            "app-0",
+		"__meta_kubernetes_pod_phase":                           "Running",
+		"__meta_kubernetes_pod_container_init":                  "false",
+		"__meta_kubernetes_pod_annotation_prometheus_io_scrape": "true",
+	}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   map[string]string
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{model.AddressLabel: "10.0.0.1:8080", model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"},
+		},
+		{
+			name: "annotations",
+			labels: map[string]string{
+				"__meta_kubernetes_pod_annotation_prometheus_io_scheme": "https",
+				"__meta_kubernetes_pod_annotation_prometheus_io_path":   "/stats/prometheus",
+				"__meta_kubernetes_pod_annotation_prometheus_io_port":   "9102",
+			},
+			want: map[string]string{model.AddressLabel: "10.0.0.1:9102", model.SchemeLabel: "https", model.MetricsPathLabel: "/stats/prometheus"},
+		},
+		{
+			name:   "port without container port",
+			labels: map[string]string{model.AddressLabel: "10.0.0.1", "__meta_kubernetes_pod_annotation_prometheus_io_port": "9102"},
+			want:   map[string]string{model.AddressLabel: "10.0.0.1:9102", model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"},
+		},
+		{
+			name:   "invalid scheme",
+			labels: map[string]string{"__meta_kubernetes_pod_annotation_prometheus_io_scheme": "ftp"},
+			want:   map[string]string{model.AddressLabel: "10.0.0.1:8080", model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"},
+		},
+		{
+			name:   "not annotated",
+			labels: map[string]string{"__meta_kubernetes_pod_annotation_prometheus_io_scrape": "false"},
+		},
+		{
+			name:   "excluded namespace",
+			labels: map[string]string{"__meta_kubernetes_namespace": "kube-system"},
+		},
+		{
+			name:   "excluded namespace with dot",
+			labels: map[string]string{"__meta_kubernetes_namespace": "istio.system"},
+		},
+		{
+			name:   "namespace matching excluded one",
+			labels: map[string]string{"__meta_kubernetes_namespace": "istio-system"},
+			want:   map[string]string{model.AddressLabel: "10.0.0.1:8080", model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"},
+		},
+		{
+			name:   "succeeded",
+			labels: map[string]string{"__meta_kubernetes_pod_phase": "Succeeded"},
+		},
+		{
+			name:   "init container",
+			labels: map[string]string{"__meta_kubernetes_pod_container_init": "true"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			target := map[string]string{}
+			for k, v := range pod {
+				target[k] = v
+			}
+			for k, v := range tt.labels {
+				target[k] = v
+			}
+			got := relabel.Process(labels.FromMap(target), relabelConfigs...)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.want[model.AddressLabel], got.Get(model.AddressLabel))
+			assert.Equal(t, tt.want[model.SchemeLabel], got.Get(model.SchemeLabel))
+			assert.Equal(t, tt.want[model.MetricsPathLabel], got.Get(model.MetricsPathLabel))
+			assert.Equal(t, target["__meta_kubernetes_namespace"], got.Get("kubernetes_namespace"))
+			assert.Equal(t, "app-0", got.Get("kubernetes_pod_name"))
+		})
+	}
+}
+
+func TestValidateKubernetesAnnotations(t *testing.T) {
+	tests := []struct {
+		name    string
+		ka      KubernetesAnnotationsConfig
+		wantErr string
+	}{
+		{name: "disabled", ka: KubernetesAnnotationsConfig{Namespaces: []string{""}}},
+		{name: "valid", ka: KubernetesAnnotationsConfig{Enabled: true, Namespaces: []string{"apps"}, ExcludeNamespaces: []string{"kube-system"}}},
+		{
+			name:    "negative scrape interval",
+			ka:      KubernetesAnnotationsConfig{Enabled: true, ScrapeInterval: -time.Second},
+			wantErr: "kubernetes_annotations.scrape_interval has to be positive, got -1s",
+		},
+		{
+			name:    "empty namespace",
+			ka:      KubernetesAnnotationsConfig{Enabled: true, Namespaces: []string{""}},
+			wantErr: "kubernetes_annotations.namespaces cannot contain empty names",
+		},
+		{
+			name:    "empty excluded namespace",
+			ka:      KubernetesAnnotationsConfig{Enabled: true, ExcludeNamespaces: []string{""}},
+			wantErr: "kubernetes_annotations.exclude_namespaces cannot contain empty names",
+		},
+		{
+			name:    "included and excluded namespace",
+			ka:      KubernetesAnnotationsConfig{Enabled: true, Namespaces: []string{"apps"}, ExcludeNamespaces: []string{"apps"}},
+			wantErr: `namespace "apps" cannot be both in kubernetes_annotations.namespaces and exclude_namespaces`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.KubernetesAnnotations = tt.ka
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -16,6 +16,10 @@ package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/prometheus/prometheus/config"
@@ -31,6 +35,8 @@ import (
 const (
 	defaultGCInterval = 2 * time.Minute
 	gcIntervalDelta   = 1 * time.Minute
+
+	remoteWritePath = "/api/v1/write"
 )
 
 // pReceiver is the type that provides Prometheus scraper/receiver functionality.
@@ -42,6 +48,14 @@ type pReceiver struct {
 	settings      component.ReceiverCreateSettings
 	scrapeManager *scrape.Manager
 	ocaStore      *internal.OcaStore
+
+	remoteWriteServer *http.Server
+	debugServer       *http.Server
+	wg                sync.WaitGroup
+
+	scrapeAuth *scrapeAuthenticators
+
+	unregisterHealthChecks []func()
 }
 
 // New creates a new prometheus.Receiver reference.
@@ -57,14 +71,37 @@ func newPrometheusReceiver(set component.ReceiverCreateSettings, cfg *Config, ne
 // Start is the method that starts Prometheus scraping and it
 // is controlled by having previously defined a Configuration using perhaps New.
 func (r *pReceiver) Start(_ context.Context, host component.Host) error {
+	if err := r.start(host); err != nil {
+		// The credentials files of the scrapes don't outlive a failed start.
+		r.scrapeAuth.stop()
+		r.scrapeAuth = nil
+		return err
+	}
+	return nil
+}
+
+func (r *pReceiver) start(host component.Host) error {
 	discoveryCtx, cancel := context.WithCancel(context.Background())
 	r.cancelFunc = cancel
 
 	logger := internal.NewZapToGokitLogAdapter(r.settings.Logger)
 
+	promConfig := r.cfg.PrometheusConfig
+	if promConfig == nil {
+		// Only remote-write requests are received, without any scrape.
+		defaultConfig := config.DefaultConfig
+		promConfig = &defaultConfig
+	}
+
+	auth, promConfig, err := startScrapeAuthenticators(r.settings.Logger, host, r.cfg.ScrapeAuthenticators, promConfig)
+	if err != nil {
+		return err
+	}
+	r.scrapeAuth = auth
+
 	discoveryManager := discovery.NewManager(discoveryCtx, logger)
 	discoveryCfg := make(map[string]discovery.Configs)
-	for _, scrapeConfig := range r.cfg.PrometheusConfig.ScrapeConfigs {
+	for _, scrapeConfig := range promConfig.ScrapeConfigs {
 		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
 	}
 	if err := discoveryManager.ApplyConfig(discoveryCfg); err != nil {
@@ -77,33 +114,117 @@ func (r *pReceiver) Start(_ context.Context, host component.Host) error {
 		}
 	}()
 
+	sink := r.consumer
+	if r.cfg.Temporality == internal.TemporalityDelta {
+		sink = internal.NewDeltaConsumer(sink, r.gcInterval(promConfig))
+	}
+
 	// Per component.Component Start instructions, for async operations we should not use the
 	// incoming context, it may get cancelled.
 	r.ocaStore = internal.NewOcaStore(
 		context.Background(),
-		r.consumer,
+		sink,
 		r.settings,
-		gcInterval(r.cfg.PrometheusConfig),
+		r.gcInterval(promConfig),
 		r.cfg.UseStartTimeMetric,
 		r.cfg.StartTimeMetricRegex,
 		r.cfg.ID(),
-		r.cfg.PrometheusConfig.GlobalConfig.ExternalLabels,
+		promConfig.GlobalConfig.ExternalLabels,
 		r.cfg.pdataDirect,
+		internal.OcaStoreOptions{
+			JobsMapMaxEntries:   r.cfg.JobsCache.MaxEntries,
+			DuplicateSamples:    r.cfg.DuplicateSamples,
+			MissingMetadata:     r.cfg.MissingMetadata,
+			TargetLabels:        r.cfg.TargetLabelsAsAttributes,
+			HonorLabelsJobs:     honorLabelsJobs(promConfig),
+			InfoMetrics:         r.cfg.InfoMetrics,
+			TraceScrapes:        r.cfg.TraceScrapes,
+			LabelValueMaxLength: r.cfg.LabelValueLimit.MaxLength,
+			LabelValueAction:    r.cfg.LabelValueLimit.Action,
+			JobResourceAttrs:    r.cfg.jobResourceAttributes(),
+			ResourceAttrKeys:    internal.NewResourceAttributeKeys(r.cfg.TargetResourceAttributes.Naming, r.cfg.TargetResourceAttributes.Port, r.cfg.TargetResourceAttributes.Scheme),
+			SampleMaxAge:        r.cfg.SampleAge.MaxAge,
+			SampleMaxFuture:     r.cfg.SampleAge.MaxFuture,
+			SampleAgeAction:     r.cfg.SampleAge.Action,
+		},
 	)
 	r.scrapeManager = scrape.NewManager(&scrape.Options{}, logger, r.ocaStore)
 	r.ocaStore.SetScrapeManager(r.scrapeManager)
-	if err := r.scrapeManager.ApplyConfig(r.cfg.PrometheusConfig); err != nil {
+	if err := r.scrapeManager.ApplyConfig(promConfig); err != nil {
 		return err
 	}
 	go func() {
-		if err := r.scrapeManager.Run(discoveryManager.SyncCh()); err != nil {
+		if err := r.scrapeManager.Run(r.ocaStore.WatchTargetUpdates(discoveryCtx, discoveryManager.SyncCh())); err != nil {
 			r.settings.Logger.Error("Scrape manager failed", zap.Error(err))
 			host.ReportFatalError(err)
 		}
 	}()
+	r.registerHealthChecks(host)
+	if r.cfg.Debug != nil {
+		if err := r.startDebug(host); err != nil {
+			return err
+		}
+	}
+	if r.cfg.RemoteWrite != nil {
+		return r.startRemoteWrite(discoveryCtx, host)
+	}
 	return nil
 }
 
+// startRemoteWrite starts the server receiving remote-write requests.
+func (r *pReceiver) startRemoteWrite(ctx context.Context, host component.Host) error {
+	ln, err := r.cfg.RemoteWrite.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.cfg.RemoteWrite.Endpoint, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(remoteWritePath, internal.NewRemoteWriteHandler(ctx, r.ocaStore, r.settings.Logger))
+	r.remoteWriteServer, err = r.cfg.RemoteWrite.ToServer(host, r.settings.TelemetrySettings, mux)
+	if err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if errHTTP := r.remoteWriteServer.Serve(ln); errHTTP != nil && !errors.Is(errHTTP, http.ErrServerClosed) {
+			r.settings.Logger.Error("Remote write server failed", zap.Error(errHTTP))
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+	return nil
+}
+
+// honorLabelsJobs maps the name of every scrape config to whether it honors the job and instance
+// labels of the scraped data, in which case the series of its targets may belong to other jobs or
+// instances. It returns nil if no scrape config honors labels.
+func honorLabelsJobs(cfg *config.Config) map[string]bool {
+	var jobs map[string]bool
+	for _, scrapeConfig := range cfg.ScrapeConfigs {
+		if scrapeConfig.HonorLabels {
+			jobs = make(map[string]bool, len(cfg.ScrapeConfigs))
+			break
+		}
+	}
+	if jobs == nil {
+		return nil
+	}
+	for _, scrapeConfig := range cfg.ScrapeConfigs {
+		jobs[scrapeConfig.JobName] = scrapeConfig.HonorLabels
+	}
+	return jobs
+}
+
+// gcInterval returns the configured interval at which the jobs cache is garbage collected, or
+// computes one from the scrape configs if it isn't set.
+func (r *pReceiver) gcInterval(cfg *config.Config) time.Duration {
+	if r.cfg.JobsCache.GCInterval > 0 {
+		return r.cfg.JobsCache.GCInterval
+	}
+	return gcInterval(cfg)
+}
+
 // gcInterval returns the longest scrape interval used by a scrape config,
 // plus a delta to prevent race conditions.
 // This ensures jobs are not garbage collected between scrapes.
@@ -121,13 +242,53 @@ func gcInterval(cfg *config.Config) time.Duration {
 }
 
 // Shutdown stops and cancels the underlying Prometheus scrapers.
-func (r *pReceiver) Shutdown(context.Context) error {
-	r.cancelFunc()
+func (r *pReceiver) Shutdown(ctx context.Context) error {
+	for _, unregister := range r.unregisterHealthChecks {
+		unregister()
+	}
+	r.unregisterHealthChecks = nil
+	var err error
+	if r.remoteWriteServer != nil {
+		// Stop receiving remote-write requests before the ocaStore is closed.
+		err = r.remoteWriteServer.Close()
+	}
+	if r.debugServer != nil {
+		if errDebug := r.debugServer.Close(); err == nil {
+			err = errDebug
+		}
+	}
+	r.wg.Wait()
+	r.drain(ctx)
+	// The receiver may have failed to start, or not have been started at all.
+	if r.cancelFunc != nil {
+		r.cancelFunc()
+	}
 	// ocaStore (and internally metadataService) needs to stop first to prevent deadlocks.
 	// When stopping scrapeManager it waits for all scrapes to terminate. However during
 	// scraping metadataService calls scrapeManager.AllTargets() which acquires
 	// the same lock that's acquired when scrapeManager is stopped.
-	r.ocaStore.Close()
-	r.scrapeManager.Stop()
-	return nil
+	if r.ocaStore != nil {
+		r.ocaStore.Close()
+	}
+	if r.scrapeManager != nil {
+		r.scrapeManager.Stop()
+	}
+	// The credentials files are removed once the scrapes have stopped.
+	r.scrapeAuth.stop()
+	r.scrapeAuth = nil
+	return err
+}
+
+// drain waits up to the drain timeout for the scrapes in flight to commit their samples, so
+// that restarting the collector doesn't abort them.
+func (r *pReceiver) drain(ctx context.Context) {
+	if r.cfg.DrainTimeout <= 0 || r.ocaStore == nil {
+		return
+	}
+	drainCtx, cancel := context.WithTimeout(ctx, r.cfg.DrainTimeout)
+	defer cancel()
+	if inFlight := r.ocaStore.Drain(drainCtx); inFlight > 0 {
+		r.settings.Logger.Warn("Scrapes still in flight after the drain timeout are aborted",
+			zap.Int("scrapes", inFlight), zap.Duration("drain_timeout", r.cfg.DrainTimeout))
+	}
 }
@@ -151,7 +151,7 @@ func setupMockPrometheus(tds ...*testData) (*mockPrometheus, *promcfg.Config, er
 	}
 	// update attributes value (will use for validation)
 	for _, t := range tds {
-		t.attributes = internal.CreateNodeAndResourcePdata(t.name, u.Host, "http").Attributes()
+		t.attributes = internal.CreateNodeAndResourcePdata(t.name, u.Host, "http", nil, internal.DefaultResourceAttributeKeys).Attributes()
 	}
 	pCfg, err := promcfg.Load(string(cfg), false, gokitlog.NewNopLogger())
 	return mp, pCfg, err
@@ -563,11 +563,12 @@ func testComponent(t *testing.T, targets []*testData, useStartTimeMetric bool, s
 
 			cms := new(consumertest.MetricsSink)
 			receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), &Config{
-				ReceiverSettings:     config.NewReceiverSettings(config.NewComponentID(typeStr)),
-				PrometheusConfig:     cfg,
-				UseStartTimeMetric:   useStartTimeMetric,
-				StartTimeMetricRegex: startTimeMetricRegex,
-				pdataDirect:          pdataDirect,
+				ReceiverSettings:         config.NewReceiverSettings(config.NewComponentID(typeStr)),
+				PrometheusConfig:         cfg,
+				UseStartTimeMetric:       useStartTimeMetric,
+				StartTimeMetricRegex:     startTimeMetricRegex,
+				TargetResourceAttributes: TargetResourceAttributesConfig{Port: true, Scheme: true},
+				pdataDirect:              pdataDirect,
 			}, cms)
 
 			require.NoError(t, receiver.Start(ctx, componenttest.NewNopHost()))
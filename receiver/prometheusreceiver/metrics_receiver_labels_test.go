@@ -507,36 +507,46 @@ func TestHonorLabelsFalseConfig(t *testing.T) {
 }
 
 func verifyHonorLabelsTrue(t *testing.T, td *testData, rms []*pdata.ResourceMetrics) {
-	//Test for honor_labels: true is skipped. Currently, the Prometheus receiver is unable to support this config
-	//See: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/5757
-	//TODO: Enable this test once issue 5757 is resolved
-	t.Skip("skipping test for honor_labels true configuration")
-
 	require.Greater(t, len(rms), 0, "At least one resource metric should be present")
 
 	//job and instance label values should be honored from honorLabelsTarget
 	expectedAttributes := td.attributes
 	expectedAttributes.Update("job", pdata.NewAttributeValueString("honor_labels_test"))
 	expectedAttributes.Update("instance", pdata.NewAttributeValueString("hostname:8080"))
-	expectedAttributes.Update("host.name", pdata.NewAttributeValueString("hostname"))
+	expectedAttributes.Upsert("host.name", pdata.NewAttributeValueString("hostname"))
 	expectedAttributes.Update("port", pdata.NewAttributeValueString("8080"))
 
-	metrics1 := rms[0].InstrumentationLibraryMetrics().At(0).Metrics()
-	ts1 := metrics1.At(0).Gauge().DataPoints().At(0).Timestamp()
+	// The series with honored labels are reported with their own resource, while the scrape
+	// metrics are reported with the resource of the target.
+	var honored, target *pdata.ResourceMetrics
+	for _, rm := range rms {
+		instance, _ := rm.Resource().Attributes().Get("instance")
+		if instance.StringVal() == "hostname:8080" {
+			if honored == nil {
+				honored = rm
+			}
+		} else if target == nil && countScrapeMetricsRM(rm) == expectedScrapeMetricCount {
+			target = rm
+		}
+	}
+	require.NotNil(t, honored, "Metrics with honored labels should be present")
+	require.NotNil(t, target, "Scrape metrics of the target should be present")
 
-	doCompare(t, "honor_labels_true", expectedAttributes, rms[0], []testExpectation{
-		assertMetricPresent("test_gauge0",
-			compareMetricType(pdata.MetricDataTypeGauge),
-			[]dataPointExpectation{
-				{
-					numberPointComparator: []numberPointComparator{
-						compareTimestamp(ts1),
-						compareDoubleValue(1),
-						compareAttributes(map[string]string{"testLabel": "value1"}),
-					},
+	require.Equal(t, expectedAttributes.AsRaw(), honored.Resource().Attributes().AsRaw())
+
+	metrics1 := honored.InstrumentationLibraryMetrics().At(0).Metrics()
+	ts1 := metrics1.At(0).Gauge().DataPoints().At(0).Timestamp()
+	assertMetricPresent("test_gauge0",
+		compareMetricType(pdata.MetricDataTypeGauge),
+		[]dataPointExpectation{
+			{
+				numberPointComparator: []numberPointComparator{
+					compareTimestamp(ts1),
+					compareDoubleValue(1),
+					compareAttributes(map[string]string{"testLabel": "value1"}),
 				},
-			}),
-	})
+			},
+		})(t, honored)
 }
 
 func TestHonorLabelsTrueConfig(t *testing.T) {
@@ -546,7 +556,8 @@ func TestHonorLabelsTrueConfig(t *testing.T) {
 			pages: []mockPrometheusResponse{
 				{code: 200, data: honorLabelsTarget},
 			},
-			validateFunc: verifyHonorLabelsTrue,
+			validateFunc:    verifyHonorLabelsTrue,
+			validateScrapes: true,
 		},
 	}
 
@@ -15,12 +15,23 @@
 package prometheusreceiver
 
 import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/model/pdata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -1090,3 +1101,47 @@ func TestGCInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteWrite(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	endpoint := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.RemoteWrite = &RemoteWriteConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: endpoint}}
+	require.NoError(t, cfg.Validate())
+
+	sink := new(consumertest.MetricsSink)
+	receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, receiver.Shutdown(context.Background())) })
+
+	data, err := proto.Marshal(&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{{
+			Labels: []prompb.Label{
+				{Name: model.MetricNameLabel, Value: "go_threads"},
+				{Name: model.JobLabel, Value: "prometheus"},
+				{Name: model.InstanceLabel, Value: "localhost:9090"},
+			},
+			Samples: []prompb.Sample{{Timestamp: time.Now().Unix() * 1000, Value: 19}},
+		}},
+	})
+	require.NoError(t, err)
+	resp, err := http.Post("http://"+endpoint+remoteWritePath, "application/x-protobuf", bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metrics := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "go_threads", metrics.At(0).Name())
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DrainTimeout = time.Second
+	receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, receiver.Shutdown(context.Background()))
+}
@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	commonconfig "github.com/prometheus/common/config"
+	promconfig "github.com/prometheus/prometheus/config"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultScrapeAuthRefreshInterval = time.Minute
+
+	credentialsDirMode  = 0700
+	credentialsFileMode = 0600
+)
+
+// ScrapeAuthenticatorConfig sets the Authorization header of the scrapes of a job from a client
+// authenticator extension, instead of the credentials inlined in its scrape config.
+type ScrapeAuthenticatorConfig struct {
+	// JobName is the name of the scrape config whose targets are authenticated.
+	JobName string `mapstructure:"job_name"`
+	// Authentication references the client authenticator extension setting the header.
+	configauth.Authentication `mapstructure:",squash"`
+	// RefreshInterval is the interval at which the credentials are read again from the
+	// authenticator, so that short-lived tokens are renewed. Defaults to one minute.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+}
+
+// errScrapeAuthNoHeader is returned when an authenticator doesn't set the Authorization header.
+var errScrapeAuthNoHeader = errors.New("the authenticator did not set the Authorization header")
+
+// scrapeAuthenticators keep the credentials files of the scrape configs authenticated through
+// extensions up to date. Prometheus reads the credentials file of a scrape config on every
+// request, so replacing its content is enough for the scrapes to use renewed credentials.
+type scrapeAuthenticators struct {
+	logger *zap.Logger
+	dir    string
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// scrapeAuthenticator writes the credentials set by an authenticator to the file of a job.
+type scrapeAuthenticator struct {
+	jobName string
+	rt      http.RoundTripper
+	file    string
+}
+
+// startScrapeAuthenticators returns a copy of promConfig whose scrape configs referenced by cfgs
+// use credentials files written from their authenticator, which are refreshed until stop is
+// called. It returns promConfig and nil scrapeAuthenticators if there are no authenticators.
+func startScrapeAuthenticators(logger *zap.Logger, host component.Host, cfgs []ScrapeAuthenticatorConfig, promConfig *promconfig.Config) (*scrapeAuthenticators, *promconfig.Config, error) {
+	if len(cfgs) == 0 {
+		return nil, promConfig, nil
+	}
+	// Prometheus builds the clients of the scrapes itself, the credentials cannot be set by a round
+	// tripper and are written to files only the collector user can read.
+	dir, err := ioutil.TempDir("", "otelcol-prometheus-auth-")
+	if err == nil {
+		// TempDir creates the directory with 0700 already, the mode is enforced in case of a lax umask policy.
+		if err = os.Chmod(dir, credentialsDirMode); err != nil {
+			os.RemoveAll(dir)
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the scrape credentials directory: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &scrapeAuthenticators{logger: logger, dir: dir, cancel: cancel}
+
+	// The configuration of the receiver is left untouched.
+	authConfig := *promConfig
+	authConfig.ScrapeConfigs = make([]*promconfig.ScrapeConfig, len(promConfig.ScrapeConfigs))
+	copy(authConfig.ScrapeConfigs, promConfig.ScrapeConfigs)
+
+	for i, cfg := range cfgs {
+		j := scrapeConfigIndex(&authConfig, cfg.JobName)
+		if j < 0 {
+			s.stop()
+			return nil, nil, fmt.Errorf("scrape authenticator references unknown job %q", cfg.JobName)
+		}
+		sc := *authConfig.ScrapeConfigs[j]
+		authConfig.ScrapeConfigs[j] = &sc
+		auth, err := cfg.GetClientAuthenticator(host.GetExtensions())
+		if err != nil {
+			s.stop()
+			return nil, nil, fmt.Errorf("failed to get the authenticator of job %q: %w", cfg.JobName, err)
+		}
+		rt, err := auth.RoundTripper(headerCapture{})
+		if err != nil {
+			s.stop()
+			return nil, nil, fmt.Errorf("failed to get the authenticator of job %q: %w", cfg.JobName, err)
+		}
+		a := &scrapeAuthenticator{
+			jobName: cfg.JobName,
+			rt:      rt,
+			file:    filepath.Join(dir, fmt.Sprintf("job-%d", i)),
+		}
+		scheme, err := a.refresh()
+		if err != nil {
+			s.stop()
+			return nil, nil, err
+		}
+		sc.HTTPClientConfig.Authorization = &commonconfig.Authorization{Type: scheme, CredentialsFile: a.file}
+
+		interval := cfg.RefreshInterval
+		if interval == 0 {
+			interval = defaultScrapeAuthRefreshInterval
+		}
+		s.wg.Add(1)
+		go s.refreshLoop(ctx, a, scheme, interval)
+	}
+	return s, &authConfig, nil
+}
+
+// refreshLoop refreshes the credentials of a every interval until ctx is done. The authorization
+// type of the scrape config cannot be changed once the scrapes started, so credentials using
+// another scheme are rejected.
+func (s *scrapeAuthenticators) refreshLoop(ctx context.Context, a *scrapeAuthenticator, scheme string, interval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			newScheme, err := a.refresh()
+			if err == nil && !strings.EqualFold(newScheme, scheme) {
+				err = fmt.Errorf("the authorization scheme changed from %q to %q", scheme, newScheme)
+			}
+			if err != nil {
+				s.logger.Warn("Failed to refresh the scrape credentials, keeping the previous ones",
+					zap.String("job", a.jobName), zap.Error(err))
+			}
+		}
+	}
+}
+
+// stop stops refreshing the credentials and removes their files.
+func (s *scrapeAuthenticators) stop() {
+	if s == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	if err := os.RemoveAll(s.dir); err != nil {
+		s.logger.Warn("Failed to remove the scrape credentials directory", zap.String("dir", s.dir), zap.Error(err))
+	}
+}
+
+// refresh gets the Authorization header from the authenticator and writes its credentials to
+// the file of the job, returning the scheme of the header. The file is replaced atomically so
+// that a scrape never reads partial credentials. The previous credentials are kept on error.
+func (a *scrapeAuthenticator) refresh() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/metrics", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.rt.RoundTrip(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the credentials of job %q: %w", a.jobName, err)
+	}
+	resp.Body.Close()
+	header := resp.Request.Header.Get("Authorization")
+	if header == "" {
+		return "", fmt.Errorf("failed to get the credentials of job %q: %w", a.jobName, errScrapeAuthNoHeader)
+	}
+	i := strings.IndexByte(header, ' ')
+	if i <= 0 {
+		return "", fmt.Errorf("the Authorization header of job %q has no scheme", a.jobName)
+	}
+	scheme, credentials := header[:i], strings.TrimSpace(header[i+1:])
+	if strings.EqualFold(scheme, "Basic") {
+		// Prometheus rejects the Basic type for the authorization of scrape configs.
+		return "", fmt.Errorf("the Basic authorization scheme of job %q is not supported", a.jobName)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(a.file), filepath.Base(a.file)+".tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to write the credentials of job %q: %w", a.jobName, err)
+	}
+	err = tmp.Chmod(credentialsFileMode)
+	if err == nil {
+		_, err = tmp.WriteString(credentials)
+	}
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), a.file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write the credentials of job %q: %w", a.jobName, err)
+	}
+	return scheme, nil
+}
+
+// headerCapture is the base round tripper of the authenticators, it answers every request
+// without sending it so that the headers set by the authenticator can be read.
+type headerCapture struct{}
+
+func (headerCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+// scrapeConfigIndex returns the index of the scrape config of the named job, or -1 if there is none.
+func scrapeConfigIndex(cfg *promconfig.Config, jobName string) int {
+	if cfg == nil {
+		return -1
+	}
+	for i, sc := range cfg.ScrapeConfigs {
+		if sc.JobName == jobName {
+			return i
+		}
+	}
+	return -1
+}
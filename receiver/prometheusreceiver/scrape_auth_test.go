@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	commonconfig "github.com/prometheus/common/config"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+)
+
+// tokenAuthenticator sets the Authorization header to the current value of its header field.
+type tokenAuthenticator struct {
+	component.Extension
+	mu     sync.Mutex
+	header string
+}
+
+var _ configauth.ClientAuthenticator = (*tokenAuthenticator)(nil)
+
+func (a *tokenAuthenticator) setHeader(header string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.header = header
+}
+
+func (a *tokenAuthenticator) RoundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		a.mu.Lock()
+		header := a.header
+		a.mu.Unlock()
+		req = req.Clone(req.Context())
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		return base.RoundTrip(req)
+	}), nil
+}
+
+func (a *tokenAuthenticator) PerRPCCredentials() (credentials.PerRPCCredentials, error) {
+	return nil, nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newScrapeAuthHost(auth *tokenAuthenticator) component.Host {
+	return healthCheckHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{
+			config.NewComponentID("token"): auth,
+		},
+	}
+}
+
+func newScrapeAuthPromConfig() *promconfig.Config {
+	return &promconfig.Config{ScrapeConfigs: []*promconfig.ScrapeConfig{
+		{JobName: "other"},
+		{JobName: "demo"},
+	}}
+}
+
+func TestScrapeAuthenticatorsRefresh(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	auth := &tokenAuthenticator{header: "Bearer first"}
+	promConfig := newScrapeAuthPromConfig()
+	s, authConfig, err := startScrapeAuthenticators(zap.NewNop(), newScrapeAuthHost(auth), []ScrapeAuthenticatorConfig{{
+		JobName:         "demo",
+		Authentication:  configauth.Authentication{AuthenticatorID: config.NewComponentID("token")},
+		RefreshInterval: 10 * time.Millisecond,
+	}}, promConfig)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	// The configuration of the receiver is left untouched.
+	assert.Nil(t, promConfig.ScrapeConfigs[1].HTTPClientConfig.Authorization)
+	assert.Same(t, promConfig.ScrapeConfigs[0], authConfig.ScrapeConfigs[0])
+	authorization := authConfig.ScrapeConfigs[1].HTTPClientConfig.Authorization
+	require.NotNil(t, authorization)
+	assert.Equal(t, "Bearer", authorization.Type)
+
+	// The credentials are only readable by the collector user.
+	info, err := os.Stat(authorization.CredentialsFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(credentialsFileMode), info.Mode().Perm())
+	info, err = os.Stat(filepath.Dir(authorization.CredentialsFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(credentialsDirMode), info.Mode().Perm())
+
+	get := func() string {
+		client, errClient := commonconfig.NewClientFromConfig(authConfig.ScrapeConfigs[1].HTTPClientConfig, "demo")
+		require.NoError(t, errClient)
+		resp, errGet := client.Get(srv.URL)
+		require.NoError(t, errGet)
+		resp.Body.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		return received[len(received)-1]
+	}
+	assert.Equal(t, "Bearer first", get())
+
+	auth.setHeader("Bearer second")
+	assert.Eventually(t, func() bool { return get() == "Bearer second" }, 5*time.Second, 10*time.Millisecond)
+
+	// A failed refresh keeps the previous credentials.
+	auth.setHeader("")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "Bearer second", get())
+
+	s.stop()
+	_, err = os.Stat(authorization.CredentialsFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestScrapeAuthenticatorsNone(t *testing.T) {
+	promConfig := newScrapeAuthPromConfig()
+	s, authConfig, err := startScrapeAuthenticators(zap.NewNop(), componenttest.NewNopHost(), nil, promConfig)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.Same(t, promConfig, authConfig)
+	// Stopping no authenticators is a noop.
+	s.stop()
+}
+
+func TestScrapeAuthenticatorsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobName string
+		authID  config.ComponentID
+		header  string
+		wantErr string
+	}{
+		{
+			name:    "unknown job",
+			jobName: "unknown",
+			authID:  config.NewComponentID("token"),
+			header:  "Bearer token",
+			wantErr: `scrape authenticator references unknown job "unknown"`,
+		},
+		{
+			name:    "unknown authenticator",
+			jobName: "demo",
+			authID:  config.NewComponentID("unknown"),
+			header:  "Bearer token",
+			wantErr: `failed to get the authenticator of job "demo": failed to resolve authenticator "unknown": authenticator not found`,
+		},
+		{
+			name:    "no header",
+			jobName: "demo",
+			authID:  config.NewComponentID("token"),
+			wantErr: `failed to get the credentials of job "demo": the authenticator did not set the Authorization header`,
+		},
+		{
+			name:    "no scheme",
+			jobName: "demo",
+			authID:  config.NewComponentID("token"),
+			header:  "token",
+			wantErr: `the Authorization header of job "demo" has no scheme`,
+		},
+		{
+			name:    "basic",
+			jobName: "demo",
+			authID:  config.NewComponentID("token"),
+			header:  "Basic dXNlcjpwYXNz",
+			wantErr: `the Basic authorization scheme of job "demo" is not supported`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &tokenAuthenticator{header: tt.header}
+			_, _, err := startScrapeAuthenticators(zap.NewNop(), newScrapeAuthHost(auth), []ScrapeAuthenticatorConfig{{
+				JobName:        tt.jobName,
+				Authentication: configauth.Authentication{AuthenticatorID: tt.authID},
+			}}, newScrapeAuthPromConfig())
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestScrapeAuthenticatorsFailedStart(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	cfg := createDefaultConfig().(*Config)
+	cfg.PrometheusConfig = newScrapeAuthPromConfig()
+	cfg.ScrapeAuthenticators = []ScrapeAuthenticatorConfig{{
+		JobName:        "demo",
+		Authentication: configauth.Authentication{AuthenticatorID: config.NewComponentID("token")},
+	}}
+	cfg.RemoteWrite = &RemoteWriteConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "localhost:-1"}}
+	receiver := newPrometheusReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.Error(t, receiver.Start(context.Background(), newScrapeAuthHost(&tokenAuthenticator{header: "Bearer token"})))
+	t.Cleanup(func() { require.NoError(t, receiver.Shutdown(context.Background())) })
+
+	// The credentials files are removed as soon as the start failed.
+	entries, err := ioutil.ReadDir(tmp)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
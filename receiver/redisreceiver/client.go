@@ -15,6 +15,8 @@
 package redisreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver"
 
 import (
+	"fmt"
+
 	"github.com/go-redis/redis/v7"
 )
 
@@ -25,6 +27,16 @@ type client interface {
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
+	// retrieves the output of CLUSTER INFO
+	retrieveClusterInfo() (string, error)
+	// retrieves the output of CLUSTER NODES
+	retrieveClusterNodes() (string, error)
+	// retrieves the fields of the masters monitored by a sentinel
+	retrieveSentinelMasters() ([]map[string]string, error)
+	// retrieves the fields of the replicas of a master monitored by a sentinel
+	retrieveSentinelReplicas(master string) ([]map[string]string, error)
+	// closes the connections to the server
+	close() error
 }
 
 // Wraps a real Redis client, implements `client` interface.
@@ -50,3 +62,52 @@ func (c *redisClient) delimiter() string {
 func (c *redisClient) retrieveInfo() (string, error) {
 	return c.client.Info().Result()
 }
+
+// Retrieve Redis CLUSTER INFO.
+func (c *redisClient) retrieveClusterInfo() (string, error) {
+	return c.client.ClusterInfo().Result()
+}
+
+// Retrieve Redis CLUSTER NODES.
+func (c *redisClient) retrieveClusterNodes() (string, error) {
+	return c.client.ClusterNodes().Result()
+}
+
+// Retrieve Sentinel MASTERS.
+func (c *redisClient) retrieveSentinelMasters() ([]map[string]string, error) {
+	return sentinelReply(c.client.Do("SENTINEL", "MASTERS").Result())
+}
+
+// Retrieve Sentinel REPLICAS of a master, available since Redis 5.0.
+func (c *redisClient) retrieveSentinelReplicas(master string) ([]map[string]string, error) {
+	return sentinelReply(c.client.Do("SENTINEL", "REPLICAS", master).Result())
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
+
+// sentinelReply converts the reply of the Sentinel commands listing masters or replicas,
+// arrays of flattened field-value pairs, to maps.
+func sentinelReply(reply interface{}, err error) ([]map[string]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected sentinel reply %T", reply)
+	}
+	result := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		pairs, ok := item.([]interface{})
+		if !ok || len(pairs)%2 != 0 {
+			return nil, fmt.Errorf("unexpected sentinel reply item %v", item)
+		}
+		fields := make(map[string]string, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			fields[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+		}
+		result = append(result, fields)
+	}
+	return result, nil
+}
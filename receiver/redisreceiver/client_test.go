@@ -44,6 +44,31 @@ func (fakeClient) retrieveInfo() (string, error) {
 	return readFile("info")
 }
 
+func (fakeClient) retrieveClusterInfo() (string, error) {
+	return readFile("cluster_info")
+}
+
+func (fakeClient) retrieveClusterNodes() (string, error) {
+	return readFile("cluster_nodes")
+}
+
+func (fakeClient) retrieveSentinelMasters() ([]map[string]string, error) {
+	return []map[string]string{
+		{"name": "mymaster", "ip": "10.0.0.1", "port": "6379", "flags": "master", "num-slaves": "2", "num-other-sentinels": "2"},
+	}, nil
+}
+
+func (fakeClient) retrieveSentinelReplicas(string) ([]map[string]string, error) {
+	return []map[string]string{
+		{"ip": "10.0.0.2", "port": "6379", "flags": "slave"},
+		{"ip": "10.0.0.3", "port": "6379", "flags": "slave,s_down,disconnected"},
+	}, nil
+}
+
+func (fakeClient) close() error {
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
@@ -52,6 +77,23 @@ func readFile(fname string) (string, error) {
 	return string(file), nil
 }
 
+func TestSentinelReply(t *testing.T) {
+	reply, err := sentinelReply([]interface{}{
+		[]interface{}{"name", "mymaster", "ip", "10.0.0.1", "port", "6379"},
+		[]interface{}{"name", "other"},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]string{
+		{"name": "mymaster", "ip": "10.0.0.1", "port": "6379"},
+		{"name": "other"},
+	}, reply)
+
+	_, err = sentinelReply("OK", nil)
+	require.Error(t, err)
+	_, err = sentinelReply([]interface{}{[]interface{}{"name"}}, nil)
+	require.Error(t, err)
+}
+
 func TestRetrieveInfo(t *testing.T) {
 	g := fakeClient{}
 	res, err := g.retrieveInfo()
@@ -15,6 +15,8 @@
 package redisreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver"
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
@@ -36,5 +38,25 @@ type Config struct {
 
 	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 
+	// Mode is the deployment the endpoint belongs to: standalone (default) to only scrape the
+	// endpoint, sentinel to scrape the masters monitored by the Sentinel at the endpoint and
+	// their replicas, or cluster to scrape all the nodes of the Redis Cluster of the endpoint.
+	// The nodes are discovered every scrape and accessed with the same password and TLS settings.
+	Mode string `mapstructure:"mode"`
+
+	// ClusterName is added as the redis.cluster.name resource attribute of the metrics of the
+	// sentinel and cluster modes, to tell apart the deployments scraped by the collector.
+	ClusterName string `mapstructure:"cluster_name"`
+
 	Metrics metadata.MetricsSettings `mapstructure:"metrics"`
 }
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	switch cfg.Mode {
+	case "", modeStandalone, modeSentinel, modeCluster:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode %q: can be either %q, %q or %q", cfg.Mode, modeStandalone, modeSentinel, modeCluster)
+	}
+}
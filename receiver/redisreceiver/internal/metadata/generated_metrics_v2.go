@@ -19,6 +19,9 @@ type MetricsSettings struct {
 	RedisClientsConnected                  MetricSettings `mapstructure:"redis.clients.connected"`
 	RedisClientsMaxInputBuffer             MetricSettings `mapstructure:"redis.clients.max_input_buffer"`
 	RedisClientsMaxOutputBuffer            MetricSettings `mapstructure:"redis.clients.max_output_buffer"`
+	RedisClusterNodes                      MetricSettings `mapstructure:"redis.cluster.nodes"`
+	RedisClusterSlots                      MetricSettings `mapstructure:"redis.cluster.slots"`
+	RedisClusterState                      MetricSettings `mapstructure:"redis.cluster.state"`
 	RedisCommands                          MetricSettings `mapstructure:"redis.commands"`
 	RedisCommandsProcessed                 MetricSettings `mapstructure:"redis.commands.processed"`
 	RedisConnectionsReceived               MetricSettings `mapstructure:"redis.connections.received"`
@@ -27,6 +30,7 @@ type MetricsSettings struct {
 	RedisDbAvgTTL                          MetricSettings `mapstructure:"redis.db.avg_ttl"`
 	RedisDbExpires                         MetricSettings `mapstructure:"redis.db.expires"`
 	RedisDbKeys                            MetricSettings `mapstructure:"redis.db.keys"`
+	RedisFailovers                         MetricSettings `mapstructure:"redis.failovers"`
 	RedisKeysEvicted                       MetricSettings `mapstructure:"redis.keys.evicted"`
 	RedisKeysExpired                       MetricSettings `mapstructure:"redis.keys.expired"`
 	RedisKeyspaceHits                      MetricSettings `mapstructure:"redis.keyspace.hits"`
@@ -42,6 +46,9 @@ type MetricsSettings struct {
 	RedisRdbChangesSinceLastSave           MetricSettings `mapstructure:"redis.rdb.changes_since_last_save"`
 	RedisReplicationBacklogFirstByteOffset MetricSettings `mapstructure:"redis.replication.backlog_first_byte_offset"`
 	RedisReplicationOffset                 MetricSettings `mapstructure:"redis.replication.offset"`
+	RedisRole                              MetricSettings `mapstructure:"redis.role"`
+	RedisSentinelReplicas                  MetricSettings `mapstructure:"redis.sentinel.replicas"`
+	RedisSentinelSentinels                 MetricSettings `mapstructure:"redis.sentinel.sentinels"`
 	RedisSlavesConnected                   MetricSettings `mapstructure:"redis.slaves.connected"`
 	RedisUptime                            MetricSettings `mapstructure:"redis.uptime"`
 }
@@ -60,6 +67,15 @@ func DefaultMetricsSettings() MetricsSettings {
 		RedisClientsMaxOutputBuffer: MetricSettings{
 			Enabled: true,
 		},
+		RedisClusterNodes: MetricSettings{
+			Enabled: true,
+		},
+		RedisClusterSlots: MetricSettings{
+			Enabled: true,
+		},
+		RedisClusterState: MetricSettings{
+			Enabled: true,
+		},
 		RedisCommands: MetricSettings{
 			Enabled: true,
 		},
@@ -84,6 +100,9 @@ func DefaultMetricsSettings() MetricsSettings {
 		RedisDbKeys: MetricSettings{
 			Enabled: true,
 		},
+		RedisFailovers: MetricSettings{
+			Enabled: true,
+		},
 		RedisKeysEvicted: MetricSettings{
 			Enabled: true,
 		},
@@ -129,6 +148,15 @@ func DefaultMetricsSettings() MetricsSettings {
 		RedisReplicationOffset: MetricSettings{
 			Enabled: true,
 		},
+		RedisRole: MetricSettings{
+			Enabled: true,
+		},
+		RedisSentinelReplicas: MetricSettings{
+			Enabled: true,
+		},
+		RedisSentinelSentinels: MetricSettings{
+			Enabled: true,
+		},
 		RedisSlavesConnected: MetricSettings{
 			Enabled: true,
 		},
@@ -338,6 +366,161 @@ func newMetricRedisClientsMaxOutputBuffer(settings MetricSettings) metricRedisCl
 	return m
 }
 
+type metricRedisClusterNodes struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.cluster.nodes metric with initial data.
+func (m *metricRedisClusterNodes) init() {
+	m.data.SetName("redis.cluster.nodes")
+	m.data.SetDescription("Number of nodes known by the Redis Cluster by role")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricRedisClusterNodes) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, roleAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.Role, pdata.NewAttributeValueString(roleAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisClusterNodes) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisClusterNodes) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisClusterNodes(settings MetricSettings) metricRedisClusterNodes {
+	m := metricRedisClusterNodes{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricRedisClusterSlots struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.cluster.slots metric with initial data.
+func (m *metricRedisClusterSlots) init() {
+	m.data.SetName("redis.cluster.slots")
+	m.data.SetDescription("Number of hash slots of the Redis Cluster by state")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricRedisClusterSlots) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, slotStateAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.SlotState, pdata.NewAttributeValueString(slotStateAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisClusterSlots) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisClusterSlots) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisClusterSlots(settings MetricSettings) metricRedisClusterSlots {
+	m := metricRedisClusterSlots{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricRedisClusterState struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.cluster.state metric with initial data.
+func (m *metricRedisClusterState) init() {
+	m.data.SetName("redis.cluster.state")
+	m.data.SetDescription("Whether the Redis Cluster is able to serve queries, 1 if its state is ok and 0 otherwise")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+}
+
+func (m *metricRedisClusterState) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisClusterState) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisClusterState) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisClusterState(settings MetricSettings) metricRedisClusterState {
+	m := metricRedisClusterState{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricRedisCommands struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -746,6 +929,57 @@ func newMetricRedisDbKeys(settings MetricSettings) metricRedisDbKeys {
 	return m
 }
 
+type metricRedisFailovers struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.failovers metric with initial data.
+func (m *metricRedisFailovers) init() {
+	m.data.SetName("redis.failovers")
+	m.data.SetDescription("Number of failovers observed by the receiver since it started, i.e. changes of the master monitored by Sentinel or replicas of the Redis Cluster promoted to master")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+}
+
+func (m *metricRedisFailovers) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisFailovers) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisFailovers) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisFailovers(settings MetricSettings) metricRedisFailovers {
+	m := metricRedisFailovers{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricRedisKeysEvicted struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1495,6 +1729,159 @@ func newMetricRedisReplicationOffset(settings MetricSettings) metricRedisReplica
 	return m
 }
 
+type metricRedisRole struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.role metric with initial data.
+func (m *metricRedisRole) init() {
+	m.data.SetName("redis.role")
+	m.data.SetDescription("Role of the node, 1 for its current role and 0 for the other one")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricRedisRole) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, roleAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.Role, pdata.NewAttributeValueString(roleAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisRole) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisRole) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisRole(settings MetricSettings) metricRedisRole {
+	m := metricRedisRole{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricRedisSentinelReplicas struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.sentinel.replicas metric with initial data.
+func (m *metricRedisSentinelReplicas) init() {
+	m.data.SetName("redis.sentinel.replicas")
+	m.data.SetDescription("Number of replicas of the master known by Sentinel")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+}
+
+func (m *metricRedisSentinelReplicas) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisSentinelReplicas) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisSentinelReplicas) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisSentinelReplicas(settings MetricSettings) metricRedisSentinelReplicas {
+	m := metricRedisSentinelReplicas{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricRedisSentinelSentinels struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills redis.sentinel.sentinels metric with initial data.
+func (m *metricRedisSentinelSentinels) init() {
+	m.data.SetName("redis.sentinel.sentinels")
+	m.data.SetDescription("Number of Sentinels monitoring the master, including the scraped one")
+	m.data.SetUnit("")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+}
+
+func (m *metricRedisSentinelSentinels) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricRedisSentinelSentinels) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricRedisSentinelSentinels) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricRedisSentinelSentinels(settings MetricSettings) metricRedisSentinelSentinels {
+	m := metricRedisSentinelSentinels{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricRedisSlavesConnected struct {
 	data     pdata.Metric   // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1601,10 +1988,14 @@ func newMetricRedisUptime(settings MetricSettings) metricRedisUptime {
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
 	startTime                                    pdata.Timestamp
+	instrumentationLibraryName                   string
 	metricRedisClientsBlocked                    metricRedisClientsBlocked
 	metricRedisClientsConnected                  metricRedisClientsConnected
 	metricRedisClientsMaxInputBuffer             metricRedisClientsMaxInputBuffer
 	metricRedisClientsMaxOutputBuffer            metricRedisClientsMaxOutputBuffer
+	metricRedisClusterNodes                      metricRedisClusterNodes
+	metricRedisClusterSlots                      metricRedisClusterSlots
+	metricRedisClusterState                      metricRedisClusterState
 	metricRedisCommands                          metricRedisCommands
 	metricRedisCommandsProcessed                 metricRedisCommandsProcessed
 	metricRedisConnectionsReceived               metricRedisConnectionsReceived
@@ -1613,6 +2004,7 @@ type MetricsBuilder struct {
 	metricRedisDbAvgTTL                          metricRedisDbAvgTTL
 	metricRedisDbExpires                         metricRedisDbExpires
 	metricRedisDbKeys                            metricRedisDbKeys
+	metricRedisFailovers                         metricRedisFailovers
 	metricRedisKeysEvicted                       metricRedisKeysEvicted
 	metricRedisKeysExpired                       metricRedisKeysExpired
 	metricRedisKeyspaceHits                      metricRedisKeyspaceHits
@@ -1628,6 +2020,9 @@ type MetricsBuilder struct {
 	metricRedisRdbChangesSinceLastSave           metricRedisRdbChangesSinceLastSave
 	metricRedisReplicationBacklogFirstByteOffset metricRedisReplicationBacklogFirstByteOffset
 	metricRedisReplicationOffset                 metricRedisReplicationOffset
+	metricRedisRole                              metricRedisRole
+	metricRedisSentinelReplicas                  metricRedisSentinelReplicas
+	metricRedisSentinelSentinels                 metricRedisSentinelSentinels
 	metricRedisSlavesConnected                   metricRedisSlavesConnected
 	metricRedisUptime                            metricRedisUptime
 }
@@ -1642,13 +2037,25 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/redisreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
 		startTime:                                    pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:                   "otelcol/redisreceiver",
 		metricRedisClientsBlocked:                    newMetricRedisClientsBlocked(settings.RedisClientsBlocked),
 		metricRedisClientsConnected:                  newMetricRedisClientsConnected(settings.RedisClientsConnected),
 		metricRedisClientsMaxInputBuffer:             newMetricRedisClientsMaxInputBuffer(settings.RedisClientsMaxInputBuffer),
 		metricRedisClientsMaxOutputBuffer:            newMetricRedisClientsMaxOutputBuffer(settings.RedisClientsMaxOutputBuffer),
+		metricRedisClusterNodes:                      newMetricRedisClusterNodes(settings.RedisClusterNodes),
+		metricRedisClusterSlots:                      newMetricRedisClusterSlots(settings.RedisClusterSlots),
+		metricRedisClusterState:                      newMetricRedisClusterState(settings.RedisClusterState),
 		metricRedisCommands:                          newMetricRedisCommands(settings.RedisCommands),
 		metricRedisCommandsProcessed:                 newMetricRedisCommandsProcessed(settings.RedisCommandsProcessed),
 		metricRedisConnectionsReceived:               newMetricRedisConnectionsReceived(settings.RedisConnectionsReceived),
@@ -1657,6 +2064,7 @@ func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption)
 		metricRedisDbAvgTTL:                          newMetricRedisDbAvgTTL(settings.RedisDbAvgTTL),
 		metricRedisDbExpires:                         newMetricRedisDbExpires(settings.RedisDbExpires),
 		metricRedisDbKeys:                            newMetricRedisDbKeys(settings.RedisDbKeys),
+		metricRedisFailovers:                         newMetricRedisFailovers(settings.RedisFailovers),
 		metricRedisKeysEvicted:                       newMetricRedisKeysEvicted(settings.RedisKeysEvicted),
 		metricRedisKeysExpired:                       newMetricRedisKeysExpired(settings.RedisKeysExpired),
 		metricRedisKeyspaceHits:                      newMetricRedisKeyspaceHits(settings.RedisKeyspaceHits),
@@ -1672,6 +2080,9 @@ func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption)
 		metricRedisRdbChangesSinceLastSave:           newMetricRedisRdbChangesSinceLastSave(settings.RedisRdbChangesSinceLastSave),
 		metricRedisReplicationBacklogFirstByteOffset: newMetricRedisReplicationBacklogFirstByteOffset(settings.RedisReplicationBacklogFirstByteOffset),
 		metricRedisReplicationOffset:                 newMetricRedisReplicationOffset(settings.RedisReplicationOffset),
+		metricRedisRole:                              newMetricRedisRole(settings.RedisRole),
+		metricRedisSentinelReplicas:                  newMetricRedisSentinelReplicas(settings.RedisSentinelReplicas),
+		metricRedisSentinelSentinels:                 newMetricRedisSentinelSentinels(settings.RedisSentinelSentinels),
 		metricRedisSlavesConnected:                   newMetricRedisSlavesConnected(settings.RedisSlavesConnected),
 		metricRedisUptime:                            newMetricRedisUptime(settings.RedisUptime),
 	}
@@ -1689,6 +2100,9 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricRedisClientsConnected.emit(metrics)
 	mb.metricRedisClientsMaxInputBuffer.emit(metrics)
 	mb.metricRedisClientsMaxOutputBuffer.emit(metrics)
+	mb.metricRedisClusterNodes.emit(metrics)
+	mb.metricRedisClusterSlots.emit(metrics)
+	mb.metricRedisClusterState.emit(metrics)
 	mb.metricRedisCommands.emit(metrics)
 	mb.metricRedisCommandsProcessed.emit(metrics)
 	mb.metricRedisConnectionsReceived.emit(metrics)
@@ -1697,6 +2111,7 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricRedisDbAvgTTL.emit(metrics)
 	mb.metricRedisDbExpires.emit(metrics)
 	mb.metricRedisDbKeys.emit(metrics)
+	mb.metricRedisFailovers.emit(metrics)
 	mb.metricRedisKeysEvicted.emit(metrics)
 	mb.metricRedisKeysExpired.emit(metrics)
 	mb.metricRedisKeyspaceHits.emit(metrics)
@@ -1712,10 +2127,61 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricRedisRdbChangesSinceLastSave.emit(metrics)
 	mb.metricRedisReplicationBacklogFirstByteOffset.emit(metrics)
 	mb.metricRedisReplicationOffset.emit(metrics)
+	mb.metricRedisRole.emit(metrics)
+	mb.metricRedisSentinelReplicas.emit(metrics)
+	mb.metricRedisSentinelSentinels.emit(metrics)
 	mb.metricRedisSlavesConnected.emit(metrics)
 	mb.metricRedisUptime.emit(metrics)
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// WithRedisClusterName sets provided value as "redis.cluster.name" attribute for current resource.
+func WithRedisClusterName(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("redis.cluster.name", val)
+	}
+}
+
+// WithRedisNodeAddress sets provided value as "redis.node.address" attribute for current resource.
+func WithRedisNodeAddress(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("redis.node.address", val)
+	}
+}
+
+// WithRedisNodeID sets provided value as "redis.node.id" attribute for current resource.
+func WithRedisNodeID(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("redis.node.id", val)
+	}
+}
+
+// WithRedisSentinelMasterName sets provided value as "redis.sentinel.master_name" attribute for current resource.
+func WithRedisSentinelMasterName(val string) ResourceOption {
+	return func(r pdata.Resource) {
+		r.Attributes().UpsertString("redis.sentinel.master_name", val)
+	}
+}
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // RecordRedisClientsBlockedDataPoint adds a data point to redis.clients.blocked metric.
 func (mb *MetricsBuilder) RecordRedisClientsBlockedDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricRedisClientsBlocked.recordDataPoint(mb.startTime, ts, val)
@@ -1736,6 +2202,21 @@ func (mb *MetricsBuilder) RecordRedisClientsMaxOutputBufferDataPoint(ts pdata.Ti
 	mb.metricRedisClientsMaxOutputBuffer.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordRedisClusterNodesDataPoint adds a data point to redis.cluster.nodes metric.
+func (mb *MetricsBuilder) RecordRedisClusterNodesDataPoint(ts pdata.Timestamp, val int64, roleAttributeValue string) {
+	mb.metricRedisClusterNodes.recordDataPoint(mb.startTime, ts, val, roleAttributeValue)
+}
+
+// RecordRedisClusterSlotsDataPoint adds a data point to redis.cluster.slots metric.
+func (mb *MetricsBuilder) RecordRedisClusterSlotsDataPoint(ts pdata.Timestamp, val int64, slotStateAttributeValue string) {
+	mb.metricRedisClusterSlots.recordDataPoint(mb.startTime, ts, val, slotStateAttributeValue)
+}
+
+// RecordRedisClusterStateDataPoint adds a data point to redis.cluster.state metric.
+func (mb *MetricsBuilder) RecordRedisClusterStateDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricRedisClusterState.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordRedisCommandsDataPoint adds a data point to redis.commands metric.
 func (mb *MetricsBuilder) RecordRedisCommandsDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricRedisCommands.recordDataPoint(mb.startTime, ts, val)
@@ -1776,6 +2257,11 @@ func (mb *MetricsBuilder) RecordRedisDbKeysDataPoint(ts pdata.Timestamp, val int
 	mb.metricRedisDbKeys.recordDataPoint(mb.startTime, ts, val, dbAttributeValue)
 }
 
+// RecordRedisFailoversDataPoint adds a data point to redis.failovers metric.
+func (mb *MetricsBuilder) RecordRedisFailoversDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricRedisFailovers.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordRedisKeysEvictedDataPoint adds a data point to redis.keys.evicted metric.
 func (mb *MetricsBuilder) RecordRedisKeysEvictedDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricRedisKeysEvicted.recordDataPoint(mb.startTime, ts, val)
@@ -1851,6 +2337,21 @@ func (mb *MetricsBuilder) RecordRedisReplicationOffsetDataPoint(ts pdata.Timesta
 	mb.metricRedisReplicationOffset.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordRedisRoleDataPoint adds a data point to redis.role metric.
+func (mb *MetricsBuilder) RecordRedisRoleDataPoint(ts pdata.Timestamp, val int64, roleAttributeValue string) {
+	mb.metricRedisRole.recordDataPoint(mb.startTime, ts, val, roleAttributeValue)
+}
+
+// RecordRedisSentinelReplicasDataPoint adds a data point to redis.sentinel.replicas metric.
+func (mb *MetricsBuilder) RecordRedisSentinelReplicasDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricRedisSentinelReplicas.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordRedisSentinelSentinelsDataPoint adds a data point to redis.sentinel.sentinels metric.
+func (mb *MetricsBuilder) RecordRedisSentinelSentinelsDataPoint(ts pdata.Timestamp, val int64) {
+	mb.metricRedisSentinelSentinels.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordRedisSlavesConnectedDataPoint adds a data point to redis.slaves.connected metric.
 func (mb *MetricsBuilder) RecordRedisSlavesConnectedDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricRedisSlavesConnected.recordDataPoint(mb.startTime, ts, val)
@@ -1874,12 +2375,40 @@ func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
 var Attributes = struct {
 	// Db (Redis database identifier)
 	Db string
+	// Role (Redis node role)
+	Role string
+	// SlotState (Redis Cluster hash slot state)
+	SlotState string
 	// State (Redis CPU usage state)
 	State string
 }{
 	"db",
+	"role",
+	"state",
 	"state",
 }
 
 // A is an alias for Attributes.
 var A = Attributes
+
+// AttributeRole are the possible values that the attribute "role" can have.
+var AttributeRole = struct {
+	Master  string
+	Replica string
+}{
+	"master",
+	"replica",
+}
+
+// AttributeSlotState are the possible values that the attribute "slot_state" can have.
+var AttributeSlotState = struct {
+	Ok         string
+	Pfail      string
+	Fail       string
+	Unassigned string
+}{
+	"ok",
+	"pfail",
+	"fail",
+	"unassigned",
+}
@@ -50,16 +50,30 @@ func newRedisScraper(cfg *Config, settings component.ReceiverCreateSettings) (sc
 	if opts.TLSConfig, err = cfg.TLS.LoadTLSConfig(); err != nil {
 		return nil, err
 	}
+	if cfg.Mode == modeSentinel || cfg.Mode == modeCluster {
+		newClient := func(addr string) client {
+			nodeOpts := *opts
+			nodeOpts.Addr = addr
+			return newRedisClient(&nodeOpts)
+		}
+		ts := newTopologyScraper(newRedisClient(opts), newClient, settings, cfg)
+		return scraperhelper.NewScraper(typeStr, ts.Scrape, scraperhelper.WithShutdown(ts.shutdown))
+	}
 	return newRedisScraperWithClient(newRedisClient(opts), settings, cfg)
 }
 
 func newRedisScraperWithClient(client client, settings component.ReceiverCreateSettings, cfg *Config) (scraperhelper.Scraper, error) {
-	rs := &redisScraper{
+	rs := newRedisNodeScraper(client, settings, cfg)
+	return scraperhelper.NewScraper(typeStr, rs.Scrape, scraperhelper.WithShutdown(rs.shutdown))
+}
+
+// newRedisNodeScraper creates the scraper of a single Redis node.
+func newRedisNodeScraper(client client, settings component.ReceiverCreateSettings, cfg *Config) *redisScraper {
+	return &redisScraper{
 		redisSvc: newRedisSvc(client),
 		settings: settings,
 		mb:       metadata.NewMetricsBuilder(cfg.Metrics),
 	}
-	return scraperhelper.NewScraper(typeStr, rs.Scrape)
 }
 
 // Scrape is called periodically, querying Redis and building Metrics to send to
@@ -68,15 +82,25 @@ func newRedisScraperWithClient(client client, settings component.ReceiverCreateS
 // keyspace lines returned by Redis. There should be one keyspace line per
 // active Redis database, of which there can be 16.
 func (rs *redisScraper) Scrape(context.Context) (pdata.Metrics, error) {
+	pdm := pdata.NewMetrics()
+	if err := rs.scrapeNode(pdm.ResourceMetrics()); err != nil {
+		return pdata.Metrics{}, err
+	}
+	return pdm, nil
+}
+
+// scrapeNode appends the metrics of the node to a new resource of rms, whose attributes are
+// set by ro.
+func (rs *redisScraper) scrapeNode(rms pdata.ResourceMetricsSlice, ro ...metadata.ResourceOption) error {
 	inf, err := rs.redisSvc.info()
 	if err != nil {
-		return pdata.Metrics{}, err
+		return err
 	}
 
 	now := pdata.NewTimestampFromTime(time.Now())
 	currentUptime, err := inf.getUptimeInSeconds()
 	if err != nil {
-		return pdata.Metrics{}, err
+		return err
 	}
 
 	if rs.uptime == time.Duration(0) || rs.uptime > currentUptime {
@@ -84,17 +108,27 @@ func (rs *redisScraper) Scrape(context.Context) (pdata.Metrics, error) {
 	}
 	rs.uptime = currentUptime
 
-	pdm := pdata.NewMetrics()
-	rm := pdm.ResourceMetrics().AppendEmpty()
-	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
-	ilm.InstrumentationLibrary().SetName("otelcol/" + typeStr)
-
 	rs.recordCommonMetrics(now, inf)
 	rs.recordKeyspaceMetrics(now, inf)
+	rs.recordRole(now, inf)
 
-	rs.mb.Emit(ilm.Metrics())
+	emit(rms, rs.mb, ro...)
+	return nil
+}
 
-	return pdm, nil
+func (rs *redisScraper) shutdown(context.Context) error {
+	return rs.redisSvc.client.close()
+}
+
+// emit appends the metrics recorded by mb to a new resource of rms, whose attributes are set by ro.
+func emit(rms pdata.ResourceMetricsSlice, mb *metadata.MetricsBuilder, ro ...metadata.ResourceOption) {
+	rm := rms.AppendEmpty()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName("otelcol/" + typeStr)
+	mb.Emit(ilm.Metrics())
 }
 
 // recordCommonMetrics records metrics from Redis info key-value pairs.
@@ -145,3 +179,17 @@ func (rs *redisScraper) recordKeyspaceMetrics(ts pdata.Timestamp, inf info) {
 		rs.mb.RecordRedisDbAvgTTLDataPoint(ts, int64(keyspace.avgTTL), keyspace.db)
 	}
 }
+
+// recordRole records the role of the node, the "role" info value is either master or slave.
+func (rs *redisScraper) recordRole(ts pdata.Timestamp, inf info) {
+	role, ok := inf["role"]
+	if !ok {
+		return
+	}
+	var master int64
+	if role == "master" {
+		master = 1
+	}
+	rs.mb.RecordRedisRoleDataPoint(ts, master, metadata.AttributeRole.Master)
+	rs.mb.RecordRedisRoleDataPoint(ts, 1-master, metadata.AttributeRole.Replica)
+}
@@ -37,8 +37,9 @@ func TestRedisRunnable(t *testing.T) {
 	require.NoError(t, err)
 	md, err := runner.Scrape(context.Background())
 	require.NoError(t, err)
-	// + 6 because there are two keyspace entries each of which has three metrics
-	assert.Equal(t, len(rs.dataPointRecorders())+6, md.DataPointCount())
+	// + 6 because there are two keyspace entries each of which has three metrics,
+	// + 2 for the data points of both roles
+	assert.Equal(t, len(rs.dataPointRecorders())+8, md.DataPointCount())
 	rm := md.ResourceMetrics().At(0)
 	ilm := rm.InstrumentationLibraryMetrics().At(0)
 	il := ilm.InstrumentationLibrary()
@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver"
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
+)
+
+const (
+	modeStandalone = "standalone"
+	modeSentinel   = "sentinel"
+	modeCluster    = "cluster"
+
+	// clusterSlots is the number of hash slots of a Redis Cluster.
+	clusterSlots = 16384
+)
+
+// topologyScraper discovers the nodes of a Sentinel or Redis Cluster deployment through the
+// client of the endpoint every scrape, and scrapes each of them like a standalone node.
+type topologyScraper struct {
+	cfg       *Config
+	settings  component.ReceiverCreateSettings
+	client    client
+	newClient func(addr string) client
+	// mb records the metrics of the masters monitored by Sentinel or of the Redis Cluster.
+	mb *metadata.MetricsBuilder
+	// nodes are the scrapers of the nodes discovered by the last scrape, by address.
+	nodes map[string]*redisScraper
+
+	// masters are the addresses of the masters monitored by Sentinel by name, and masterFailovers
+	// the number of times their address changed.
+	masters         map[string]string
+	masterFailovers map[string]int64
+	// replicas are the IDs of the replicas of the Redis Cluster, and clusterFailovers the number
+	// of replicas promoted to master.
+	replicas         map[string]bool
+	clusterFailovers int64
+}
+
+// discoveredNode is a node of the deployment to scrape, with the resource attributes of its metrics.
+type discoveredNode struct {
+	address    string
+	attributes []metadata.ResourceOption
+}
+
+func newTopologyScraper(client client, newClient func(addr string) client, settings component.ReceiverCreateSettings, cfg *Config) *topologyScraper {
+	return &topologyScraper{
+		cfg:             cfg,
+		settings:        settings,
+		client:          client,
+		newClient:       newClient,
+		mb:              metadata.NewMetricsBuilder(cfg.Metrics),
+		nodes:           map[string]*redisScraper{},
+		masters:         map[string]string{},
+		masterFailovers: map[string]int64{},
+		replicas:        map[string]bool{},
+	}
+}
+
+// Scrape records the metrics of the deployment and of each of its nodes in resources of their own.
+// Failing to scrape a node is reported as a partial scrape error.
+func (ts *topologyScraper) Scrape(context.Context) (pdata.Metrics, error) {
+	pdm := pdata.NewMetrics()
+	rms := pdm.ResourceMetrics()
+	now := pdata.NewTimestampFromTime(time.Now())
+
+	var nodes []discoveredNode
+	var err error
+	if ts.cfg.Mode == modeSentinel {
+		nodes, err = ts.scrapeSentinel(now, rms)
+	} else {
+		nodes, err = ts.scrapeCluster(now, rms)
+	}
+	if err != nil {
+		return pdata.Metrics{}, err
+	}
+
+	errs := &scrapererror.ScrapeErrors{}
+	discovered := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		discovered[node.address] = true
+		rs, ok := ts.nodes[node.address]
+		if !ok {
+			rs = newRedisNodeScraper(ts.newClient(node.address), ts.settings, ts.cfg)
+			ts.nodes[node.address] = rs
+		}
+		if err := rs.scrapeNode(rms, node.attributes...); err != nil {
+			errs.AddPartial(1, fmt.Errorf("failed to scrape node %s: %w", node.address, err))
+		}
+	}
+	for address, rs := range ts.nodes {
+		if discovered[address] {
+			continue
+		}
+		if err := rs.redisSvc.client.close(); err != nil {
+			ts.settings.Logger.Warn("failed to close the client of a removed node", zap.String("address", address), zap.Error(err))
+		}
+		delete(ts.nodes, address)
+	}
+	return pdm, errs.Combine()
+}
+
+func (ts *topologyScraper) shutdown(context.Context) error {
+	err := ts.client.close()
+	for _, rs := range ts.nodes {
+		err = multierr.Append(err, rs.redisSvc.client.close())
+	}
+	return err
+}
+
+// scrapeSentinel records the metrics of the masters monitored by the sentinel, and returns the
+// masters and replicas which are neither down nor disconnected.
+func (ts *topologyScraper) scrapeSentinel(now pdata.Timestamp, rms pdata.ResourceMetricsSlice) ([]discoveredNode, error) {
+	masters, err := ts.client.retrieveSentinelMasters()
+	if err != nil {
+		return nil, err
+	}
+	var nodes []discoveredNode
+	for _, master := range masters {
+		name := master["name"]
+		address := net.JoinHostPort(master["ip"], master["port"])
+		if previous, ok := ts.masters[name]; ok && previous != address {
+			ts.masterFailovers[name]++
+		}
+		ts.masters[name] = address
+
+		ts.mb.RecordRedisFailoversDataPoint(now, ts.masterFailovers[name])
+		if replicas, ok := ts.parseInt(master, "num-slaves"); ok {
+			ts.mb.RecordRedisSentinelReplicasDataPoint(now, replicas)
+		}
+		if sentinels, ok := ts.parseInt(master, "num-other-sentinels"); ok {
+			ts.mb.RecordRedisSentinelSentinelsDataPoint(now, sentinels+1)
+		}
+		emit(rms, ts.mb, ts.resourceAttributes(metadata.WithRedisSentinelMasterName(name))...)
+
+		if sentinelNodeUp(master["flags"]) {
+			nodes = append(nodes, discoveredNode{
+				address:    address,
+				attributes: ts.resourceAttributes(metadata.WithRedisSentinelMasterName(name), metadata.WithRedisNodeAddress(address)),
+			})
+		}
+		replicas, err := ts.client.retrieveSentinelReplicas(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the replicas of master %s: %w", name, err)
+		}
+		for _, replica := range replicas {
+			if !sentinelNodeUp(replica["flags"]) {
+				continue
+			}
+			replicaAddress := net.JoinHostPort(replica["ip"], replica["port"])
+			nodes = append(nodes, discoveredNode{
+				address:    replicaAddress,
+				attributes: ts.resourceAttributes(metadata.WithRedisSentinelMasterName(name), metadata.WithRedisNodeAddress(replicaAddress)),
+			})
+		}
+	}
+	return nodes, nil
+}
+
+// scrapeCluster records the metrics of the Redis Cluster of the endpoint, and returns the nodes
+// which aren't failed, still in handshake or without address.
+func (ts *topologyScraper) scrapeCluster(now pdata.Timestamp, rms pdata.ResourceMetricsSlice) ([]discoveredNode, error) {
+	clusterInfo, err := ts.client.retrieveClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	clusterNodes, err := ts.client.retrieveClusterNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []discoveredNode
+	var masters, replicas int64
+	currentReplicas := map[string]bool{}
+	for _, node := range parseClusterNodes(clusterNodes) {
+		if node.master {
+			masters++
+			if ts.replicas[node.id] {
+				ts.clusterFailovers++
+			}
+		} else if node.replica {
+			replicas++
+			currentReplicas[node.id] = true
+		}
+		if node.up {
+			nodes = append(nodes, discoveredNode{
+				address:    node.address,
+				attributes: ts.resourceAttributes(metadata.WithRedisNodeAddress(node.address), metadata.WithRedisNodeID(node.id)),
+			})
+		}
+	}
+	ts.replicas = currentReplicas
+
+	fields := parseFields(clusterInfo)
+	var state int64
+	if fields["cluster_state"] == "ok" {
+		state = 1
+	}
+	ts.mb.RecordRedisClusterStateDataPoint(now, state)
+	if assigned, ok := ts.parseInt(fields, "cluster_slots_assigned"); ok {
+		ts.mb.RecordRedisClusterSlotsDataPoint(now, clusterSlots-assigned, metadata.AttributeSlotState.Unassigned)
+	}
+	if slots, ok := ts.parseInt(fields, "cluster_slots_ok"); ok {
+		ts.mb.RecordRedisClusterSlotsDataPoint(now, slots, metadata.AttributeSlotState.Ok)
+	}
+	if slots, ok := ts.parseInt(fields, "cluster_slots_pfail"); ok {
+		ts.mb.RecordRedisClusterSlotsDataPoint(now, slots, metadata.AttributeSlotState.Pfail)
+	}
+	if slots, ok := ts.parseInt(fields, "cluster_slots_fail"); ok {
+		ts.mb.RecordRedisClusterSlotsDataPoint(now, slots, metadata.AttributeSlotState.Fail)
+	}
+	ts.mb.RecordRedisClusterNodesDataPoint(now, masters, metadata.AttributeRole.Master)
+	ts.mb.RecordRedisClusterNodesDataPoint(now, replicas, metadata.AttributeRole.Replica)
+	ts.mb.RecordRedisFailoversDataPoint(now, ts.clusterFailovers)
+	emit(rms, ts.mb, ts.resourceAttributes()...)
+	return nodes, nil
+}
+
+// resourceAttributes returns the attributes of a resource of the deployment, including the
+// cluster name if set.
+func (ts *topologyScraper) resourceAttributes(ro ...metadata.ResourceOption) []metadata.ResourceOption {
+	var attributes []metadata.ResourceOption
+	if ts.cfg.ClusterName != "" {
+		attributes = append(attributes, metadata.WithRedisClusterName(ts.cfg.ClusterName))
+	}
+	return append(attributes, ro...)
+}
+
+func (ts *topologyScraper) parseInt(fields map[string]string, key string) (int64, bool) {
+	val, err := strconv.ParseInt(fields[key], 10, 64)
+	if err != nil {
+		ts.settings.Logger.Warn("failed to parse topology int val", zap.String("key", key),
+			zap.String("val", fields[key]), zap.Error(err))
+		return 0, false
+	}
+	return val, true
+}
+
+// sentinelNodeUp returns whether the flags of a master or replica known by Sentinel mark it as
+// neither down nor disconnected.
+func sentinelNodeUp(flags string) bool {
+	for _, flag := range strings.Split(flags, ",") {
+		switch flag {
+		case "s_down", "o_down", "disconnected":
+			return false
+		}
+	}
+	return true
+}
+
+// clusterNode is a node listed by CLUSTER NODES.
+type clusterNode struct {
+	id      string
+	address string
+	master  bool
+	replica bool
+	// up is whether the node can be scraped, i.e. isn't failed, in handshake or without address.
+	up bool
+}
+
+// parseClusterNodes parses the lines of CLUSTER NODES, e.g.
+// "<id> 10.0.0.1:6379@16379 myself,master - 0 0 1 connected 0-5460".
+func parseClusterNodes(str string) []clusterNode {
+	var nodes []clusterNode
+	for _, line := range strings.Split(str, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+		address := fields[1]
+		// The address is followed by the cluster bus port since Redis 4.0, and the hostname since 7.0.
+		if i := strings.IndexAny(address, "@,"); i >= 0 {
+			address = address[:i]
+		}
+		node := clusterNode{id: fields[0], address: address, up: true}
+		for _, flag := range strings.Split(fields[2], ",") {
+			switch flag {
+			case "master":
+				node.master = true
+			case "slave":
+				node.replica = true
+			case "fail", "handshake", "noaddr":
+				node.up = false
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// parseFields parses the "key:value" lines of CLUSTER INFO.
+func parseFields(str string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.IndexByte(line, ':'); i > 0 {
+			fields[line[:i]] = line[i+1:]
+		}
+	}
+	return fields
+}
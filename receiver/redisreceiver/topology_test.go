@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+// fakeTopologyClient is the client of the endpoint of a deployment, whose topology can be changed
+// between scrapes.
+type fakeTopologyClient struct {
+	fakeClient
+	masters      []map[string]string
+	clusterNodes string
+	err          error
+}
+
+func (c *fakeTopologyClient) retrieveClusterNodes() (string, error) {
+	return c.clusterNodes, c.err
+}
+
+func (c *fakeTopologyClient) retrieveSentinelMasters() ([]map[string]string, error) {
+	return c.masters, c.err
+}
+
+// fakeNodeClient is the client of a node of a deployment.
+type fakeNodeClient struct {
+	fakeClient
+	err    error
+	closed bool
+}
+
+func (c *fakeNodeClient) retrieveInfo() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	return c.fakeClient.retrieveInfo()
+}
+
+func (c *fakeNodeClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func newFakeTopologyScraper(t *testing.T, mode string, seed client) (*topologyScraper, map[string]*fakeNodeClient) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Mode = mode
+	cfg.ClusterName = "prod"
+	require.NoError(t, cfg.Validate())
+	nodes := map[string]*fakeNodeClient{}
+	newClient := func(addr string) client {
+		c := &fakeNodeClient{}
+		nodes[addr] = c
+		return c
+	}
+	return newTopologyScraper(seed, newClient, componenttest.NewNopReceiverCreateSettings(), cfg), nodes
+}
+
+func TestTopologyScraperSentinel(t *testing.T) {
+	seed := &fakeTopologyClient{}
+	seed.masters, _ = seed.fakeClient.retrieveSentinelMasters()
+	ts, nodes := newFakeTopologyScraper(t, modeSentinel, seed)
+
+	md, err := ts.Scrape(context.Background())
+	require.NoError(t, err)
+	rms := md.ResourceMetrics()
+	require.Equal(t, 3, rms.Len())
+
+	master := rms.At(0)
+	assert.Equal(t, map[string]interface{}{"redis.cluster.name": "prod", "redis.sentinel.master_name": "mymaster"}, master.Resource().Attributes().AsRaw())
+	assert.Equal(t, int64(0), intValue(t, master, "redis.failovers"))
+	assert.Equal(t, int64(2), intValue(t, master, "redis.sentinel.replicas"))
+	assert.Equal(t, int64(3), intValue(t, master, "redis.sentinel.sentinels"))
+
+	// The replica 10.0.0.3 is down.
+	assert.Equal(t, "10.0.0.1:6379", nodeAddress(rms.At(1)))
+	assert.Equal(t, "mymaster", rms.At(1).Resource().Attributes().AsRaw()["redis.sentinel.master_name"])
+	assert.Equal(t, "10.0.0.2:6379", nodeAddress(rms.At(2)))
+	assert.Len(t, nodes, 2)
+	role := metric(t, rms.At(1), "redis.role").Gauge().DataPoints()
+	require.Equal(t, 2, role.Len())
+	assert.Equal(t, map[string]interface{}{"role": "master"}, role.At(0).Attributes().AsRaw())
+	assert.Equal(t, int64(1), role.At(0).IntVal())
+
+	// The replica 10.0.0.2 is promoted and the former master 10.0.0.1 is removed.
+	seed.masters = []map[string]string{{"name": "mymaster", "ip": "10.0.0.2", "port": "6379", "flags": "master", "num-slaves": "1", "num-other-sentinels": "2"}}
+	md, err = ts.Scrape(context.Background())
+	require.NoError(t, err)
+	rms = md.ResourceMetrics()
+	require.Equal(t, 3, rms.Len())
+	assert.Equal(t, int64(1), intValue(t, rms.At(0), "redis.failovers"))
+	assert.True(t, nodes["10.0.0.1:6379"].closed)
+	assert.False(t, nodes["10.0.0.2:6379"].closed)
+
+	require.NoError(t, ts.shutdown(context.Background()))
+	assert.True(t, nodes["10.0.0.2:6379"].closed)
+}
+
+func TestTopologyScraperCluster(t *testing.T) {
+	seed := &fakeTopologyClient{}
+	seed.clusterNodes, _ = seed.fakeClient.retrieveClusterNodes()
+	ts, nodes := newFakeTopologyScraper(t, modeCluster, seed)
+
+	md, err := ts.Scrape(context.Background())
+	require.NoError(t, err)
+	rms := md.ResourceMetrics()
+	// The failed node and the one without address aren't scraped.
+	require.Equal(t, 4, rms.Len())
+	cluster := rms.At(0)
+	assert.Equal(t, map[string]interface{}{"redis.cluster.name": "prod"}, cluster.Resource().Attributes().AsRaw())
+	assert.Equal(t, int64(1), intValue(t, cluster, "redis.cluster.state"))
+	assert.Equal(t, int64(0), intValue(t, cluster, "redis.failovers"))
+	assert.Equal(t, map[string]int64{"unassigned": 384, "ok": 15000, "pfail": 800, "fail": 200}, sumByAttribute(t, cluster, "redis.cluster.slots", "state"))
+	assert.Equal(t, map[string]int64{"master": 3, "replica": 2}, sumByAttribute(t, cluster, "redis.cluster.nodes", "role"))
+
+	assert.Equal(t, map[string]interface{}{
+		"redis.cluster.name": "prod",
+		"redis.node.address": "10.0.0.1:6379",
+		"redis.node.id":      "07c37dfeb235213a872192d90877d0cd55635b91",
+	}, rms.At(1).Resource().Attributes().AsRaw())
+	assert.Equal(t, "10.0.0.2:6379", nodeAddress(rms.At(2)))
+	assert.Equal(t, "10.0.0.4:6379", nodeAddress(rms.At(3)))
+	assert.Len(t, nodes, 3)
+
+	// The replica 10.0.0.4 is promoted.
+	seed.clusterNodes = strings.Replace(seed.clusterNodes, "10.0.0.4:6379@16379 slave 07c37dfeb235213a872192d90877d0cd55635b91", "10.0.0.4:6379@16379 master -", 1)
+	md, err = ts.Scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), intValue(t, md.ResourceMetrics().At(0), "redis.failovers"))
+	assert.Equal(t, map[string]int64{"master": 4, "replica": 1}, sumByAttribute(t, md.ResourceMetrics().At(0), "redis.cluster.nodes", "role"))
+}
+
+func TestTopologyScraperNodeError(t *testing.T) {
+	seed := &fakeTopologyClient{}
+	seed.clusterNodes, _ = seed.fakeClient.retrieveClusterNodes()
+	ts, _ := newFakeTopologyScraper(t, modeCluster, seed)
+	ts.newClient = func(addr string) client {
+		if addr == "10.0.0.2:6379" {
+			return &fakeNodeClient{err: errors.New("connection refused")}
+		}
+		return &fakeNodeClient{}
+	}
+
+	md, err := ts.Scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "failed to scrape node 10.0.0.2:6379")
+	assert.Equal(t, 3, md.ResourceMetrics().Len())
+}
+
+func TestTopologyScraperSeedError(t *testing.T) {
+	ts, _ := newFakeTopologyScraper(t, modeSentinel, &fakeTopologyClient{err: errors.New("connection refused")})
+	_, err := ts.Scrape(context.Background())
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestInvalidMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Mode = "replication"
+	assert.EqualError(t, cfg.Validate(), `invalid mode "replication": can be either "standalone", "sentinel" or "cluster"`)
+}
+
+func metric(t *testing.T, rm pdata.ResourceMetrics, name string) pdata.Metric {
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %s", name)
+	return pdata.Metric{}
+}
+
+// intValue returns the value of the single data point of a metric.
+func intValue(t *testing.T, rm pdata.ResourceMetrics, name string) int64 {
+	m := metric(t, rm, name)
+	var dps pdata.NumberDataPointSlice
+	if m.DataType() == pdata.MetricDataTypeGauge {
+		dps = m.Gauge().DataPoints()
+	} else {
+		dps = m.Sum().DataPoints()
+	}
+	require.Equal(t, 1, dps.Len())
+	return dps.At(0).IntVal()
+}
+
+func sumByAttribute(t *testing.T, rm pdata.ResourceMetrics, name string, attribute string) map[string]int64 {
+	dps := metric(t, rm, name).Sum().DataPoints()
+	values := map[string]int64{}
+	for i := 0; i < dps.Len(); i++ {
+		val, _ := dps.At(i).Attributes().Get(attribute)
+		values[val.StringVal()] = dps.At(i).IntVal()
+	}
+	return values
+}
+
+func nodeAddress(rm pdata.ResourceMetrics) string {
+	val, _ := rm.Resource().Attributes().Get("redis.node.address")
+	return val.StringVal()
+}
@@ -30,6 +30,7 @@ type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"`
 	NetAddr                 confignet.NetAddr                `mapstructure:",squash"`
 	AggregationInterval     time.Duration                    `mapstructure:"aggregation_interval"`
+	AlignAggregation        bool                             `mapstructure:"align_aggregation"`
 	EnableMetricType        bool                             `mapstructure:"enable_metric_type"`
 	IsMonotonicCounter      bool                             `mapstructure:"is_monotonic_counter"`
 	TimerHistogramMapping   []protocol.TimerHistogramMapping `mapstructure:"timer_histogram_mapping"`
@@ -63,10 +64,20 @@ func (c *Config) validate() error {
 		}
 
 		switch eachMap.ObserverType {
-		case protocol.GaugeObserver, protocol.SummaryObserver:
+		case protocol.GaugeObserver, protocol.SummaryObserver, protocol.HistogramObserver:
 		default:
 			errs = multierr.Append(errs, fmt.Errorf("observer_type is not supported: %s", eachMap.ObserverType))
 		}
+
+		for i := 1; i < len(eachMap.Histogram.ExplicitBuckets); i++ {
+			if eachMap.Histogram.ExplicitBuckets[i] <= eachMap.Histogram.ExplicitBuckets[i-1] {
+				errs = multierr.Append(errs, fmt.Errorf("histogram explicit_buckets must be strictly increasing: %s", eachMap.StatsdType))
+				break
+			}
+		}
+		if eachMap.Histogram.MaxSize < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("histogram max_size must not be negative: %s", eachMap.StatsdType))
+		}
 	}
 
 	if TimerHistogramMappingMissingObjectName {
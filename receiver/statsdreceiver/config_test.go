@@ -53,8 +53,12 @@ func TestLoadConfig(t *testing.T) {
 			Endpoint:  "localhost:12345",
 			Transport: "custom_transport",
 		},
-		AggregationInterval:   70 * time.Second,
-		TimerHistogramMapping: []protocol.TimerHistogramMapping{{StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "timing", ObserverType: "gauge"}},
+		AggregationInterval: 70 * time.Second,
+		AlignAggregation:    true,
+		TimerHistogramMapping: []protocol.TimerHistogramMapping{
+			{StatsdType: "histogram", ObserverType: "gauge"},
+			{StatsdType: "timing", ObserverType: "histogram", Histogram: protocol.HistogramConfig{ExplicitBuckets: []float64{10, 100, 1000}}},
+		},
 	}, r1)
 }
 
@@ -113,6 +117,26 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: fmt.Sprintf(statsdTypeNotSupportErr, "abc"),
 		},
+		{
+			name: "histogramExplicitBucketsNotIncreasing",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timer", ObserverType: "histogram", Histogram: protocol.HistogramConfig{ExplicitBuckets: []float64{10, 10, 100}}},
+				},
+			},
+			expectedErr: "histogram explicit_buckets must be strictly increasing: timer",
+		},
+		{
+			name: "histogramNegativeMaxSize",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "histogram", ObserverType: "histogram", Histogram: protocol.HistogramConfig{MaxSize: -1}},
+				},
+			},
+			expectedErr: "histogram max_size must not be negative: histogram",
+		},
 		{
 			name: "ObserverTypeNotSupport",
 			cfg: &Config{
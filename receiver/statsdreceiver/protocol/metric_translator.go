@@ -15,6 +15,7 @@
 package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -26,6 +27,11 @@ var (
 	statsDDefaultPercentiles = []float64{0, 10, 50, 90, 95, 100}
 )
 
+const (
+	exponentialHistogramMaxScale = 20
+	exponentialHistogramMinScale = -10
+)
+
 func buildCounterMetric(parsedMetric statsDMetric, isMonotonicCounter bool, timeNow, lastIntervalTime time.Time) pdata.InstrumentationLibraryMetrics {
 	ilm := pdata.NewInstrumentationLibraryMetrics()
 	nm := ilm.Metrics().AppendEmpty()
@@ -101,6 +107,146 @@ func buildSummaryMetric(desc statsDMetricDescription, summary summaryMetric, sta
 	}
 }
 
+func buildHistogramMetric(desc statsDMetricDescription, histogram summaryMetric, config HistogramConfig, startTime, timeNow time.Time, ilm pdata.InstrumentationLibraryMetrics) {
+	nm := ilm.Metrics().AppendEmpty()
+	nm.SetName(desc.name)
+
+	sum := float64(0)
+	for i := range histogram.points {
+		sum += histogram.points[i] * histogram.weights[i]
+	}
+
+	var dp interface {
+		Attributes() pdata.AttributeMap
+		SetStartTimestamp(pdata.Timestamp)
+		SetTimestamp(pdata.Timestamp)
+	}
+	if len(config.ExplicitBuckets) > 0 {
+		nm.SetDataType(pdata.MetricDataTypeHistogram)
+		nm.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+		hdp := nm.Histogram().DataPoints().AppendEmpty()
+		fillExplicitHistogram(hdp, histogram, config.ExplicitBuckets)
+		hdp.SetSum(sum)
+		dp = hdp
+	} else {
+		maxSize := config.MaxSize
+		if maxSize <= 0 {
+			maxSize = DefaultExponentialHistogramMaxSize
+		}
+		nm.SetDataType(pdata.MetricDataTypeExponentialHistogram)
+		nm.ExponentialHistogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+		edp := nm.ExponentialHistogram().DataPoints().AppendEmpty()
+		fillExponentialHistogram(edp, histogram, maxSize)
+		edp.SetSum(sum)
+		dp = edp
+	}
+
+	dp.SetStartTimestamp(pdata.NewTimestampFromTime(startTime))
+	dp.SetTimestamp(pdata.NewTimestampFromTime(timeNow))
+	for i := desc.attrs.Iter(); i.Next(); {
+		dp.Attributes().InsertString(string(i.Attribute().Key), i.Attribute().Value.AsString())
+	}
+}
+
+// fillExplicitHistogram counts the weighted points in the buckets delimited by the upper bounds.
+func fillExplicitHistogram(dp pdata.HistogramDataPoint, histogram summaryMetric, bounds []float64) {
+	weights := make([]float64, len(bounds)+1)
+	for i, point := range histogram.points {
+		// Buckets include their upper bound.
+		weights[sort.SearchFloat64s(bounds, point)] += histogram.weights[i]
+	}
+	counts, count := roundCounts(weights)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(counts)
+	dp.SetCount(count)
+}
+
+// fillExponentialHistogram counts the weighted points in the buckets of the highest scale for which
+// neither the positive nor the negative range exceeds maxSize buckets.
+func fillExponentialHistogram(dp pdata.ExponentialHistogramDataPoint, histogram summaryMetric, maxSize int32) {
+	var positive, negative []int
+	var zeroWeight float64
+	for i, point := range histogram.points {
+		switch {
+		case point > 0:
+			positive = append(positive, i)
+		case point < 0:
+			negative = append(negative, i)
+		default:
+			zeroWeight += histogram.weights[i]
+		}
+	}
+
+	scale := int32(exponentialHistogramMaxScale)
+	for scale > exponentialHistogramMinScale &&
+		(exponentialBucketsSize(histogram.points, positive, scale) > maxSize ||
+			exponentialBucketsSize(histogram.points, negative, scale) > maxSize) {
+		scale--
+	}
+	dp.SetScale(scale)
+
+	zeroCount := uint64(math.Round(zeroWeight))
+	dp.SetZeroCount(zeroCount)
+	count := zeroCount
+	count += fillExponentialBuckets(dp.Positive(), histogram, positive, scale)
+	count += fillExponentialBuckets(dp.Negative(), histogram, negative, scale)
+	dp.SetCount(count)
+}
+
+func fillExponentialBuckets(buckets pdata.Buckets, histogram summaryMetric, indices []int, scale int32) uint64 {
+	if len(indices) == 0 {
+		return 0
+	}
+	minIndex, maxIndex := exponentialIndexRange(histogram.points, indices, scale)
+	weights := make([]float64, maxIndex-minIndex+1)
+	for _, i := range indices {
+		weights[exponentialIndex(histogram.points[i], scale)-minIndex] += histogram.weights[i]
+	}
+	counts, count := roundCounts(weights)
+	buckets.SetOffset(minIndex)
+	buckets.SetBucketCounts(counts)
+	return count
+}
+
+func exponentialBucketsSize(points []float64, indices []int, scale int32) int32 {
+	if len(indices) == 0 {
+		return 0
+	}
+	minIndex, maxIndex := exponentialIndexRange(points, indices, scale)
+	return maxIndex - minIndex + 1
+}
+
+func exponentialIndexRange(points []float64, indices []int, scale int32) (int32, int32) {
+	minIndex, maxIndex := int32(math.MaxInt32), int32(math.MinInt32)
+	for _, i := range indices {
+		index := exponentialIndex(points[i], scale)
+		if index < minIndex {
+			minIndex = index
+		}
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+	return minIndex, maxIndex
+}
+
+// exponentialIndex returns the index of the bucket (base^index, base^(index+1)] holding the absolute value
+// of the point, with base = 2^(2^-scale).
+func exponentialIndex(point float64, scale int32) int32 {
+	return int32(math.Ceil(math.Log2(math.Abs(point))*math.Ldexp(1, int(scale)))) - 1
+}
+
+// roundCounts rounds the weighted bucket counts, see note in counterValue(), and returns their sum.
+func roundCounts(weights []float64) ([]uint64, uint64) {
+	counts := make([]uint64, len(weights))
+	var count uint64
+	for i, weight := range weights {
+		counts[i] = uint64(math.Round(weight))
+		count += counts[i]
+	}
+	return counts, count
+}
+
 func (s statsDMetric) counterValue() int64 {
 	x := s.asFloat
 	// Note statds counters are always represented as integers.
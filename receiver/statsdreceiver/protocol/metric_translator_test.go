@@ -207,3 +207,96 @@ func TestBuildSummaryMetricSampled(t *testing.T) {
 		assert.Equal(t, expectedMetric, metric)
 	}
 }
+
+func TestBuildHistogramMetricExplicitBuckets(t *testing.T) {
+	timeNow := time.Now()
+
+	sampledMetric := summaryMetric{
+		points:  []float64{0.5, 1, 3, 10, 20},
+		weights: []float64{1, 1, 2, 1, 1},
+	}
+
+	desc := statsDMetricDescription{
+		name:       "testHistogram",
+		metricType: TimingType,
+		attrs:      attribute.NewSet(attribute.String("mykey", "myvalue")),
+	}
+
+	metric := pdata.NewInstrumentationLibraryMetrics()
+	buildHistogramMetric(desc, sampledMetric, HistogramConfig{ExplicitBuckets: []float64{1, 5, 10}}, timeNow.Add(-time.Minute), timeNow, metric)
+
+	expectedMetric := pdata.NewInstrumentationLibraryMetrics()
+	m := expectedMetric.Metrics().AppendEmpty()
+	m.SetName("testHistogram")
+	m.SetDataType(pdata.MetricDataTypeHistogram)
+	m.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	dp := m.Histogram().DataPoints().AppendEmpty()
+	dp.SetExplicitBounds([]float64{1, 5, 10})
+	dp.SetBucketCounts([]uint64{2, 2, 1, 1})
+	dp.SetCount(6)
+	dp.SetSum(37.5)
+	dp.SetStartTimestamp(pdata.NewTimestampFromTime(timeNow.Add(-time.Minute)))
+	dp.SetTimestamp(pdata.NewTimestampFromTime(timeNow))
+	dp.Attributes().InsertString("mykey", "myvalue")
+
+	assert.Equal(t, expectedMetric, metric)
+}
+
+func TestBuildHistogramMetricExponential(t *testing.T) {
+	timeNow := time.Now()
+
+	sampledMetric := summaryMetric{
+		points:  []float64{1, 2, 4, 0, -2},
+		weights: []float64{1, 1, 1, 1, 10},
+	}
+
+	desc := statsDMetricDescription{
+		name:       "testHistogram",
+		metricType: TimingType,
+		attrs:      attribute.NewSet(attribute.String("mykey", "myvalue")),
+	}
+
+	metric := pdata.NewInstrumentationLibraryMetrics()
+	buildHistogramMetric(desc, sampledMetric, HistogramConfig{}, timeNow.Add(-time.Minute), timeNow, metric)
+
+	expectedMetric := pdata.NewInstrumentationLibraryMetrics()
+	m := expectedMetric.Metrics().AppendEmpty()
+	m.SetName("testHistogram")
+	m.SetDataType(pdata.MetricDataTypeExponentialHistogram)
+	m.ExponentialHistogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityDelta)
+	dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+	// The highest scale with at most 160 buckets between 1 and 4 is 6, 1 is in the bucket -1,
+	// 2 in the bucket 63 and 4 in the bucket 127.
+	dp.SetScale(6)
+	dp.SetZeroCount(1)
+	positive := make([]uint64, 129)
+	positive[0], positive[64], positive[128] = 1, 1, 1
+	dp.Positive().SetOffset(-1)
+	dp.Positive().SetBucketCounts(positive)
+	dp.Negative().SetOffset(63)
+	dp.Negative().SetBucketCounts([]uint64{10})
+	dp.SetCount(14)
+	dp.SetSum(-13)
+	dp.SetStartTimestamp(pdata.NewTimestampFromTime(timeNow.Add(-time.Minute)))
+	dp.SetTimestamp(pdata.NewTimestampFromTime(timeNow))
+	dp.Attributes().InsertString("mykey", "myvalue")
+
+	assert.Equal(t, expectedMetric, metric)
+}
+
+func TestBuildHistogramMetricExponentialMaxSize(t *testing.T) {
+	sampledMetric := summaryMetric{
+		points:  []float64{1, 10, 100, 1000},
+		weights: []float64{1, 1, 1, 1},
+	}
+
+	metric := pdata.NewInstrumentationLibraryMetrics()
+	buildHistogramMetric(statsDMetricDescription{name: "testHistogram"}, sampledMetric, HistogramConfig{MaxSize: 4}, time.Now(), time.Now(), metric)
+
+	dp := metric.Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	// The buckets at scale -2 have a base of 16.
+	assert.EqualValues(t, -2, dp.Scale())
+	assert.EqualValues(t, -1, dp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 1, 1, 1}, dp.Positive().BucketCounts())
+	assert.EqualValues(t, 4, dp.Count())
+}
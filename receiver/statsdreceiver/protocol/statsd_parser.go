@@ -33,7 +33,7 @@ var (
 type (
 	MetricType   string // From the statsd line e.g., "c", "g", "h"
 	TypeName     string // How humans describe the MetricTypes ("counter", "gauge")
-	ObserverType string // How the server will aggregate histogram and timings ("gauge", "summary")
+	ObserverType string // How the server will aggregate histogram and timings ("gauge", "summary", "histogram")
 )
 
 const (
@@ -50,16 +50,32 @@ const (
 	TimingTypeName    TypeName = "timing"
 	TimingAltTypeName TypeName = "timer"
 
-	GaugeObserver   ObserverType = "gauge"
-	SummaryObserver ObserverType = "summary"
-	DisableObserver ObserverType = "disabled"
+	GaugeObserver     ObserverType = "gauge"
+	SummaryObserver   ObserverType = "summary"
+	HistogramObserver ObserverType = "histogram"
+	DisableObserver   ObserverType = "disabled"
 
 	DefaultObserverType = DisableObserver
+
+	// DefaultExponentialHistogramMaxSize is the default maximum number of buckets of each range,
+	// positive and negative, of the exponential histograms.
+	DefaultExponentialHistogramMaxSize = 160
 )
 
 type TimerHistogramMapping struct {
-	StatsdType   TypeName     `mapstructure:"statsd_type"`
-	ObserverType ObserverType `mapstructure:"observer_type"`
+	StatsdType   TypeName        `mapstructure:"statsd_type"`
+	ObserverType ObserverType    `mapstructure:"observer_type"`
+	Histogram    HistogramConfig `mapstructure:"histogram"`
+}
+
+// HistogramConfig configures the histograms built by the histogram observer.
+type HistogramConfig struct {
+	// ExplicitBuckets are the increasing upper bounds of the buckets of an explicit bucket histogram.
+	// An exponential histogram is built instead if no bucket is set.
+	ExplicitBuckets []float64 `mapstructure:"explicit_buckets"`
+	// MaxSize is the maximum number of buckets of each range of an exponential histogram,
+	// the scale is lowered until the values fit (default 160).
+	MaxSize int32 `mapstructure:"max_size"`
 }
 
 // StatsDParser supports the Parse method for parsing StatsD messages with Tags.
@@ -67,11 +83,13 @@ type StatsDParser struct {
 	gauges                 map[statsDMetricDescription]pdata.InstrumentationLibraryMetrics
 	counters               map[statsDMetricDescription]pdata.InstrumentationLibraryMetrics
 	summaries              map[statsDMetricDescription]summaryMetric
+	histograms             map[statsDMetricDescription]summaryMetric
 	timersAndDistributions []pdata.InstrumentationLibraryMetrics
 	enableMetricType       bool
 	isMonotonicCounter     bool
 	observeTimer           ObserverType
 	observeHistogram       ObserverType
+	histogramConfigs       map[MetricType]HistogramConfig
 	lastIntervalTime       time.Time
 }
 
@@ -119,9 +137,11 @@ func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool
 	p.counters = make(map[statsDMetricDescription]pdata.InstrumentationLibraryMetrics)
 	p.timersAndDistributions = make([]pdata.InstrumentationLibraryMetrics, 0)
 	p.summaries = make(map[statsDMetricDescription]summaryMetric)
+	p.histograms = make(map[statsDMetricDescription]summaryMetric)
 
 	p.observeHistogram = DefaultObserverType
 	p.observeTimer = DefaultObserverType
+	p.histogramConfigs = make(map[MetricType]HistogramConfig)
 	p.enableMetricType = enableMetricType
 	p.isMonotonicCounter = isMonotonicCounter
 	// Note: validation occurs in ("../".Config).vaidate()
@@ -129,8 +149,10 @@ func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool
 		switch eachMap.StatsdType {
 		case HistogramTypeName:
 			p.observeHistogram = eachMap.ObserverType
+			p.histogramConfigs[HistogramType] = eachMap.Histogram
 		case TimingTypeName, TimingAltTypeName:
 			p.observeTimer = eachMap.ObserverType
+			p.histogramConfigs[TimingType] = eachMap.Histogram
 		}
 	}
 	return nil
@@ -164,11 +186,23 @@ func (p *StatsDParser) GetMetrics() pdata.Metrics {
 		)
 	}
 
+	for desc, histogramMetric := range p.histograms {
+		buildHistogramMetric(
+			desc,
+			histogramMetric,
+			p.histogramConfigs[desc.metricType],
+			p.lastIntervalTime,
+			timeNowFunc(),
+			rm.InstrumentationLibraryMetrics().AppendEmpty(),
+		)
+	}
+
 	p.lastIntervalTime = timeNowFunc()
 	p.gauges = make(map[statsDMetricDescription]pdata.InstrumentationLibraryMetrics)
 	p.counters = make(map[statsDMetricDescription]pdata.InstrumentationLibraryMetrics)
 	p.timersAndDistributions = make([]pdata.InstrumentationLibraryMetrics, 0)
 	p.summaries = make(map[statsDMetricDescription]summaryMetric)
+	p.histograms = make(map[statsDMetricDescription]summaryMetric)
 	return metrics
 }
 
@@ -232,6 +266,15 @@ func (p *StatsDParser) Aggregate(line string) error {
 					weights: append(existing.weights, raw.count),
 				}
 			}
+		case HistogramObserver:
+			// The points are kept like for summaries so that the scale of exponential
+			// histograms fits all the points of the interval.
+			raw := parsedMetric.summaryValue()
+			existing := p.histograms[parsedMetric.description]
+			p.histograms[parsedMetric.description] = summaryMetric{
+				points:  append(existing.points, raw.value),
+				weights: append(existing.weights, raw.count),
+			}
 		case DisableObserver:
 			// No action.
 		}
@@ -893,6 +893,41 @@ func TestStatsDParser_AggregateTimerWithSummary(t *testing.T) {
 	}
 }
 
+func TestStatsDParser_AggregateTimerWithHistogram(t *testing.T) {
+	timeNowFunc = func() time.Time {
+		return time.Unix(711, 0)
+	}
+
+	p := &StatsDParser{}
+	p.Initialize(false, false, []TimerHistogramMapping{
+		{StatsdType: "timer", ObserverType: "histogram", Histogram: HistogramConfig{ExplicitBuckets: []float64{10, 100}}},
+	})
+	for _, line := range []string{
+		"statsdTestMetric1:1|ms|#mykey:myvalue",
+		"statsdTestMetric1:50|ms|@0.1|#mykey:myvalue",
+		"statsdTestMetric1:500|ms|#mykey:myvalue",
+		"statsdTestMetric1:500|h|#mykey:myvalue",
+	} {
+		assert.NoError(t, p.Aggregate(line))
+	}
+	assert.EqualValues(t, map[statsDMetricDescription]summaryMetric{
+		testDescription("statsdTestMetric1", "ms",
+			[]string{"mykey"}, []string{"myvalue"}): {
+			points:  []float64{1, 50, 500},
+			weights: []float64{1, 10, 1},
+		},
+	}, p.histograms)
+
+	metrics := p.GetMetrics()
+	ilms := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	assert.Equal(t, 1, ilms.Len())
+	dp := ilms.At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 10, 1}, dp.BucketCounts())
+	assert.EqualValues(t, 12, dp.Count())
+	assert.Equal(t, float64(1001), dp.Sum())
+	assert.Empty(t, p.histograms)
+}
+
 func TestStatsDParser_Initialize(t *testing.T) {
 	p := &StatsDParser{}
 	p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}})
@@ -977,6 +1012,26 @@ func TestStatsDParser_Mappings(t *testing.T) {
 				"Gauge": "T",
 			},
 		},
+		{
+			name: "timer-histogram-histo-summary",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "timer", ObserverType: "histogram", Histogram: HistogramConfig{ExplicitBuckets: []float64{1, 10}}},
+				{StatsdType: "histogram", ObserverType: "summary"},
+			},
+			expect: map[string]string{
+				"Histogram": "T",
+				"Summary":   "H",
+			},
+		},
+		{
+			name: "histo-to-exponential-histogram",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "histogram"},
+			},
+			expect: map[string]string{
+				"ExponentialHistogram": "H",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			p := &StatsDParser{}
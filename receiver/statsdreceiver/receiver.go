@@ -89,7 +89,8 @@ func buildTransportServer(config Config) (transport.Server, error) {
 func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 	ctx, r.cancel = context.WithCancel(ctx)
 	var transferChan = make(chan string, 10)
-	ticker := time.NewTicker(r.config.AggregationInterval)
+	nextFlush := firstFlushTime(time.Now(), r.config.AggregationInterval, r.config.AlignAggregation)
+	timer := time.NewTimer(time.Until(nextFlush))
 	r.parser.Initialize(r.config.EnableMetricType, r.config.IsMonotonicCounter, r.config.TimerHistogramMapping)
 	go func() {
 		if err := r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter, transferChan); err != nil {
@@ -101,7 +102,10 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 	go func() {
 		for {
 			select {
-			case <-ticker.C:
+			case <-timer.C:
+				// The next flush is scheduled from the previous one so that the intervals don't drift.
+				nextFlush = nextFlush.Add(r.config.AggregationInterval)
+				timer.Reset(time.Until(nextFlush))
 				metrics := r.parser.GetMetrics()
 				if metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Len() > 0 {
 					r.Flush(ctx, metrics, r.nextConsumer)
@@ -109,7 +113,7 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 			case rawMetric := <-transferChan:
 				r.parser.Aggregate(rawMetric)
 			case <-ctx.Done():
-				ticker.Stop()
+				timer.Stop()
 				return
 			}
 		}
@@ -118,6 +122,15 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// firstFlushTime returns the time of the first flush, an interval after now, or if aligned the next multiple
+// of the interval, e.g. the next whole minute for an interval of 1m, so that receivers flush the same intervals.
+func firstFlushTime(now time.Time, interval time.Duration, align bool) time.Time {
+	if !align {
+		return now.Add(interval)
+	}
+	return now.Truncate(interval).Add(interval)
+}
+
 // Shutdown stops the StatsD receiver.
 func (r *statsdReceiver) Shutdown(context.Context) error {
 	err := r.server.Close()
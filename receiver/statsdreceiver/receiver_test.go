@@ -91,6 +91,13 @@ func TestStatsdReceiver_Flush(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
+func TestFirstFlushTime(t *testing.T) {
+	now := time.Date(2022, 1, 1, 10, 0, 25, 0, time.UTC)
+	assert.Equal(t, now.Add(time.Minute), firstFlushTime(now, time.Minute, false))
+	assert.Equal(t, time.Date(2022, 1, 1, 10, 1, 0, 0, time.UTC), firstFlushTime(now, time.Minute, true))
+	assert.Equal(t, time.Date(2022, 1, 1, 10, 0, 30, 0, time.UTC), firstFlushTime(now, 10*time.Second, true))
+}
+
 func Test_statsdreceiver_EndToEnd(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	host, portStr, err := net.SplitHostPort(addr)
@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/systemdreceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// priorityNames are the names of the syslog priorities, indexed by their level.
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// priorityLevels are the levels of the syslog priorities, by name.
+var priorityLevels = func() map[string]int {
+	levels := make(map[string]int, len(priorityNames))
+	for level, name := range priorityNames {
+		levels[name] = level
+	}
+	return levels
+}()
+
+// unitStatus is the status of a systemd unit.
+type unitStatus struct {
+	name        string
+	activeState string
+	// restarts is the number of automatic restarts of a service, it is nil for the other units.
+	restarts *int64
+}
+
+// systemdClient queries the state of the units and the journal of the host.
+type systemdClient interface {
+	// units returns the status of the units matching the patterns.
+	units(ctx context.Context, patterns []string) ([]unitStatus, error)
+	// journalEntries returns the number of journal entries of the priority or a more important one, by
+	// priority name, written after the entry of the cursor or since the given time if the cursor is empty.
+	// The cursor of the last entry with a valid cursor is returned, it is empty if there was no such entry. An
+	// invalid cursor isn't passed to journalctl, the entries since the given time are returned instead.
+	journalEntries(ctx context.Context, priority string, since time.Time, cursor string) (map[string]int64, string, error)
+}
+
+// commandRunner runs a command and returns its standard output.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// commandClient is a systemdClient running systemctl and journalctl.
+type commandClient struct {
+	run commandRunner
+}
+
+var _ systemdClient = (*commandClient)(nil)
+
+func newSystemdClient() systemdClient {
+	return &commandClient{run: runCommand}
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s failed: %w: %s", name, err, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	return out, nil
+}
+
+func (c *commandClient) units(ctx context.Context, patterns []string) ([]unitStatus, error) {
+	args := append([]string{"list-units", "--all", "--plain", "--no-legend", "--no-pager"}, patterns...)
+	out, err := c.run(ctx, "systemctl", args...)
+	if err != nil {
+		return nil, err
+	}
+	units := parseUnits(out)
+
+	var services []string
+	for _, u := range units {
+		if strings.HasSuffix(u.name, ".service") {
+			services = append(services, u.name)
+		}
+	}
+	if len(services) == 0 {
+		return units, nil
+	}
+
+	args = append([]string{"show", "--property=Id,NRestarts", "--no-pager"}, services...)
+	if out, err = c.run(ctx, "systemctl", args...); err != nil {
+		return nil, err
+	}
+	restarts := parseRestarts(out)
+	for i := range units {
+		if n, ok := restarts[units[i].name]; ok {
+			n := n
+			units[i].restarts = &n
+		}
+	}
+	return units, nil
+}
+
+// parseUnits parses the output of systemctl list-units, made of the unit, load, active, sub and
+// description columns. Failed units may be marked with a bullet before their name.
+func parseUnits(out []byte) []unitStatus {
+	var units []unitStatus
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && (fields[0] == "●" || fields[0] == "*") {
+			fields = fields[1:]
+		}
+		if len(fields) < 4 {
+			continue
+		}
+		units = append(units, unitStatus{name: fields[0], activeState: fields[2]})
+	}
+	return units
+}
+
+// parseRestarts parses the Id and NRestarts properties of the output of systemctl show, where the
+// properties of every unit are separated by an empty line. NRestarts is missing with systemd < 235.
+func parseRestarts(out []byte) map[string]int64 {
+	restarts := map[string]int64{}
+	var id string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		property := strings.SplitN(scanner.Text(), "=", 2)
+		switch {
+		case len(property) != 2:
+			id = ""
+		case property[0] == "Id":
+			id = property[1]
+		case property[0] == "NRestarts" && id != "":
+			if n, err := strconv.ParseInt(property[1], 10, 64); err == nil {
+				restarts[id] = n
+			}
+		}
+	}
+	return restarts
+}
+
+// journalCursorPattern matches the cursors of the journal entries, semicolon separated fields made of a letter and
+// an hexadecimal value, e.g. s=739ad463348b4ceca5a9e69c95a3c93f;i=4ece7;b=6c7c6013a8674e1d9f41de07e6cec2a0.
+var journalCursorPattern = regexp.MustCompile(`^[a-z]=[0-9a-f]+(;[a-z]=[0-9a-f]+)*$`)
+
+// maxJournalCursorLength is the length of the longest cursor kept by the receiver, a cursor of journald is less
+// than 200 characters long.
+const maxJournalCursorLength = 512
+
+// validJournalCursor returns whether cursor is a cursor of a journal entry which can be passed to journalctl.
+func validJournalCursor(cursor string) bool {
+	return len(cursor) <= maxJournalCursorLength && journalCursorPattern.MatchString(cursor)
+}
+
+// journalEntry holds the fields of the JSON output of journalctl used by the receiver.
+type journalEntry struct {
+	Cursor   string `json:"__CURSOR"`
+	Priority string `json:"PRIORITY"`
+}
+
+func (c *commandClient) journalEntries(ctx context.Context, priority string, since time.Time, cursor string) (map[string]int64, string, error) {
+	args := []string{"--priority=" + priority, "--output=json", "--output-fields=PRIORITY", "--no-pager", "--quiet"}
+	if validJournalCursor(cursor) {
+		args = append(args, "--after-cursor="+cursor)
+	} else {
+		args = append(args, "--since=@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	out, err := c.run(ctx, "journalctl", args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	counts := map[string]int64{}
+	var last string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, "", fmt.Errorf("failed to parse journal entry: %w", err)
+		}
+		level, err := strconv.Atoi(entry.Priority)
+		if err == nil && level >= 0 && level < len(priorityNames) {
+			counts[priorityNames[level]]++
+		}
+		if validJournalCursor(entry.Cursor) {
+			last = entry.Cursor
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return counts, last, nil
+}
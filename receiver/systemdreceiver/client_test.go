@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner returns the content of a testdata file for journalctl and for every systemctl subcommand.
+type fakeRunner struct {
+	outputs map[string]string
+	err     error
+	calls   [][]string
+}
+
+func (r *fakeRunner) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	if r.err != nil {
+		return nil, r.err
+	}
+	key := name
+	if name == "systemctl" {
+		key = args[0]
+	}
+	return ioutil.ReadFile(filepath.Join("testdata", r.outputs[key]))
+}
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{outputs: map[string]string{
+		"list-units": "list_units.txt",
+		"show":       "show.txt",
+		"journalctl": "journal.json",
+	}}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestUnits(t *testing.T) {
+	runner := newFakeRunner()
+	client := &commandClient{run: runner.run}
+
+	units, err := client.units(context.Background(), []string{"*.service", "*.timer"})
+	require.NoError(t, err)
+	require.Equal(t, []unitStatus{
+		{name: "cron.service", activeState: "active", restarts: int64Ptr(0)},
+		{name: "nginx.service", activeState: "failed", restarts: int64Ptr(5)},
+		{name: "postgresql.service", activeState: "inactive", restarts: int64Ptr(0)},
+		{name: "systemd-fsck@dev-sda1.service", activeState: "activating", restarts: int64Ptr(1)},
+		{name: "apt-daily.timer", activeState: "active"},
+	}, units)
+	require.Equal(t, [][]string{
+		{"systemctl", "list-units", "--all", "--plain", "--no-legend", "--no-pager", "*.service", "*.timer"},
+		{"systemctl", "show", "--property=Id,NRestarts", "--no-pager",
+			"cron.service", "nginx.service", "postgresql.service", "systemd-fsck@dev-sda1.service"},
+	}, runner.calls)
+}
+
+func TestUnitsNoServices(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputs["list-units"] = "list_units_timers.txt"
+	client := &commandClient{run: runner.run}
+
+	units, err := client.units(context.Background(), []string{"*.timer"})
+	require.NoError(t, err)
+	require.Equal(t, []unitStatus{{name: "apt-daily.timer", activeState: "active"}}, units)
+	require.Len(t, runner.calls, 1)
+}
+
+func TestUnitsError(t *testing.T) {
+	runner := newFakeRunner()
+	runner.err = errors.New("systemctl failed: exit status 1")
+	client := &commandClient{run: runner.run}
+
+	_, err := client.units(context.Background(), []string{"*.service"})
+	require.EqualError(t, err, "systemctl failed: exit status 1")
+}
+
+func TestJournalEntries(t *testing.T) {
+	runner := newFakeRunner()
+	client := &commandClient{run: runner.run}
+
+	since := time.Unix(1643296725, 0)
+	counts, cursor, err := client.journalEntries(context.Background(), "err", since, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"crit": 1, "err": 2}, counts)
+	require.Equal(t, "s=1;i=1c", cursor)
+
+	_, _, err = client.journalEntries(context.Background(), "err", since, cursor)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"journalctl", "--priority=err", "--output=json", "--output-fields=PRIORITY", "--no-pager", "--quiet", "--since=@1643296725"},
+		{"journalctl", "--priority=err", "--output=json", "--output-fields=PRIORITY", "--no-pager", "--quiet", "--after-cursor=s=1;i=1c"},
+	}, runner.calls)
+}
+
+func TestJournalEntriesInvalid(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputs["journalctl"] = "list_units.txt"
+	client := &commandClient{run: runner.run}
+
+	_, _, err := client.journalEntries(context.Background(), "err", time.Now(), "")
+	require.Error(t, err)
+}
+
+func TestJournalEntriesInvalidCursor(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputs["journalctl"] = "journal_invalid_cursor.json"
+	client := &commandClient{run: runner.run}
+
+	// The invalid cursor of the last entry isn't returned.
+	since := time.Unix(1643296725, 0)
+	counts, cursor, err := client.journalEntries(context.Background(), "err", since, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"crit": 1, "err": 1}, counts)
+	require.Equal(t, "s=1;i=1a", cursor)
+
+	// An invalid cursor isn't passed to journalctl.
+	_, _, err = client.journalEntries(context.Background(), "err", since, "s=1;i=1b\n")
+	require.NoError(t, err)
+	require.Equal(t, []string{"journalctl", "--priority=err", "--output=json", "--output-fields=PRIORITY", "--no-pager", "--quiet", "--since=@1643296725"}, runner.calls[1])
+}
+
+func TestValidJournalCursor(t *testing.T) {
+	require.True(t, validJournalCursor("s=739ad463348b4ceca5a9e69c95a3c93f;i=4ece7;b=6c7c6013a8674e1d9f41de07e6cec2a0;m=1f4d5a1d1;t=5d8f1f2a1a0a1;x=d3a1e4a0b58d0e32"))
+	require.False(t, validJournalCursor(""))
+	require.False(t, validJournalCursor("cursor"))
+	require.False(t, validJournalCursor("s=1;i=1;"))
+	require.False(t, validJournalCursor("s=1;i=1 --since=@0"))
+	require.False(t, validJournalCursor("s="+strings.Repeat("a", maxJournalCursorLength)))
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/systemdreceiver"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/systemdreceiver/internal/metadata"
+)
+
+var errNoUnits = errors.New("units must not be empty")
+
+// Config is the configuration for the systemd receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+	// Metrics defines which metrics to enable for the scraper.
+	Metrics metadata.MetricsSettings `mapstructure:"metrics"`
+	// Units are the patterns of the units to report the state of, e.g. "*.service" or "docker.service".
+	Units []string `mapstructure:"units"`
+	// JournalPriority is the lowest priority of the journal entries counted, e.g. "err" counts
+	// the entries with the priorities emerg, alert, crit and err.
+	JournalPriority string `mapstructure:"journal_priority"`
+}
+
+// Validate validates the given config, returning an error specifying any issues with the config.
+func (cfg *Config) Validate() error {
+	if len(cfg.Units) == 0 {
+		return errNoUnits
+	}
+	if _, ok := priorityLevels[cfg.JournalPriority]; !ok {
+		return fmt.Errorf("invalid journal_priority %q", cfg.JournalPriority)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/servicetest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := servicetest.LoadConfigAndValidate(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.Len(t, cfg.Receivers, 2)
+
+	require.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[config.NewComponentID(typeStr)])
+
+	expected := factory.CreateDefaultConfig().(*Config)
+	expected.SetIDName("custom")
+	expected.CollectionInterval = 30 * time.Second
+	expected.Units = []string{"docker.service", "*.timer"}
+	expected.JournalPriority = "warning"
+	expected.Metrics.SystemdUnitRestarts.Enabled = false
+	require.Equal(t, expected, cfg.Receivers[config.NewComponentIDWithName(typeStr, "custom")])
+}
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.Units = nil
+	require.ErrorIs(t, cfg.Validate(), errNoUnits)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.JournalPriority = "error"
+	require.EqualError(t, cfg.Validate(), `invalid journal_priority "error"`)
+}
@@ -0,0 +1,343 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// MetricSettings provides common settings for a particular metric.
+type MetricSettings struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// MetricsSettings provides settings for systemdreceiver metrics.
+type MetricsSettings struct {
+	SystemdJournalEntries MetricSettings `mapstructure:"systemd.journal.entries"`
+	SystemdUnitRestarts   MetricSettings `mapstructure:"systemd.unit.restarts"`
+	SystemdUnitState      MetricSettings `mapstructure:"systemd.unit.state"`
+}
+
+func DefaultMetricsSettings() MetricsSettings {
+	return MetricsSettings{
+		SystemdJournalEntries: MetricSettings{
+			Enabled: true,
+		},
+		SystemdUnitRestarts: MetricSettings{
+			Enabled: true,
+		},
+		SystemdUnitState: MetricSettings{
+			Enabled: true,
+		},
+	}
+}
+
+type metricSystemdJournalEntries struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills systemd.journal.entries metric with initial data.
+func (m *metricSystemdJournalEntries) init() {
+	m.data.SetName("systemd.journal.entries")
+	m.data.SetDescription("The number of journal entries written since the receiver was started.")
+	m.data.SetUnit("{entries}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricSystemdJournalEntries) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, priorityAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.Priority, pdata.NewAttributeValueString(priorityAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricSystemdJournalEntries) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricSystemdJournalEntries) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricSystemdJournalEntries(settings MetricSettings) metricSystemdJournalEntries {
+	m := metricSystemdJournalEntries{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricSystemdUnitRestarts struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills systemd.unit.restarts metric with initial data.
+func (m *metricSystemdUnitRestarts) init() {
+	m.data.SetName("systemd.unit.restarts")
+	m.data.SetDescription("The number of automatic restarts of the service by systemd.")
+	m.data.SetUnit("{restarts}")
+	m.data.SetDataType(pdata.MetricDataTypeSum)
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricSystemdUnitRestarts) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, unitNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.UnitName, pdata.NewAttributeValueString(unitNameAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricSystemdUnitRestarts) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricSystemdUnitRestarts) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricSystemdUnitRestarts(settings MetricSettings) metricSystemdUnitRestarts {
+	m := metricSystemdUnitRestarts{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricSystemdUnitState struct {
+	data     pdata.Metric   // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills systemd.unit.state metric with initial data.
+func (m *metricSystemdUnitState) init() {
+	m.data.SetName("systemd.unit.state")
+	m.data.SetDescription("1 if the unit is in the active state of the state attribute, 0 otherwise.")
+	m.data.SetUnit("1")
+	m.data.SetDataType(pdata.MetricDataTypeGauge)
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricSystemdUnitState) recordDataPoint(start pdata.Timestamp, ts pdata.Timestamp, val int64, unitNameAttributeValue string, unitStateAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntVal(val)
+	dp.Attributes().Insert(A.UnitName, pdata.NewAttributeValueString(unitNameAttributeValue))
+	dp.Attributes().Insert(A.UnitState, pdata.NewAttributeValueString(unitStateAttributeValue))
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricSystemdUnitState) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricSystemdUnitState) emit(metrics pdata.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricSystemdUnitState(settings MetricSettings) metricSystemdUnitState {
+	m := metricSystemdUnitState{settings: settings}
+	if settings.Enabled {
+		m.data = pdata.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+// MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
+// required to produce metric representation defined in metadata and user settings.
+type MetricsBuilder struct {
+	startTime                   pdata.Timestamp
+	instrumentationLibraryName  string
+	metricSystemdJournalEntries metricSystemdJournalEntries
+	metricSystemdUnitRestarts   metricSystemdUnitRestarts
+	metricSystemdUnitState      metricSystemdUnitState
+}
+
+// metricBuilderOption applies changes to default metrics builder.
+type metricBuilderOption func(*MetricsBuilder)
+
+// WithStartTime sets startTime on the metrics builder.
+func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.startTime = startTime
+	}
+}
+
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/systemdreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
+func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
+	mb := &MetricsBuilder{
+		startTime:                   pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:  "otelcol/systemdreceiver",
+		metricSystemdJournalEntries: newMetricSystemdJournalEntries(settings.SystemdJournalEntries),
+		metricSystemdUnitRestarts:   newMetricSystemdUnitRestarts(settings.SystemdUnitRestarts),
+		metricSystemdUnitState:      newMetricSystemdUnitState(settings.SystemdUnitState),
+	}
+	for _, op := range options {
+		op(mb)
+	}
+	return mb
+}
+
+// Emit appends generated metrics to a pdata.MetricsSlice and updates the internal state to be ready for recording
+// another set of data points. This function will be doing all transformations required to produce metric representation
+// defined in metadata and user settings, e.g. delta/cumulative translation.
+func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
+	mb.metricSystemdJournalEntries.emit(metrics)
+	mb.metricSystemdUnitRestarts.emit(metrics)
+	mb.metricSystemdUnitState.emit(metrics)
+}
+
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
+// RecordSystemdJournalEntriesDataPoint adds a data point to systemd.journal.entries metric.
+func (mb *MetricsBuilder) RecordSystemdJournalEntriesDataPoint(ts pdata.Timestamp, val int64, priorityAttributeValue string) {
+	mb.metricSystemdJournalEntries.recordDataPoint(mb.startTime, ts, val, priorityAttributeValue)
+}
+
+// RecordSystemdUnitRestartsDataPoint adds a data point to systemd.unit.restarts metric.
+func (mb *MetricsBuilder) RecordSystemdUnitRestartsDataPoint(ts pdata.Timestamp, val int64, unitNameAttributeValue string) {
+	mb.metricSystemdUnitRestarts.recordDataPoint(mb.startTime, ts, val, unitNameAttributeValue)
+}
+
+// RecordSystemdUnitStateDataPoint adds a data point to systemd.unit.state metric.
+func (mb *MetricsBuilder) RecordSystemdUnitStateDataPoint(ts pdata.Timestamp, val int64, unitNameAttributeValue string, unitStateAttributeValue string) {
+	mb.metricSystemdUnitState.recordDataPoint(mb.startTime, ts, val, unitNameAttributeValue, unitStateAttributeValue)
+}
+
+// Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
+// and metrics builder should update its startTime and reset it's internal state accordingly.
+func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
+	mb.startTime = pdata.NewTimestampFromTime(time.Now())
+	for _, op := range options {
+		op(mb)
+	}
+}
+
+// Attributes contains the possible metric attributes that can be used.
+var Attributes = struct {
+	// Priority (The syslog priority of the journal entries.)
+	Priority string
+	// UnitName (The name of the systemd unit.)
+	UnitName string
+	// UnitState (The active state of the unit.)
+	UnitState string
+}{
+	"priority",
+	"unit",
+	"state",
+}
+
+// A is an alias for Attributes.
+var A = Attributes
+
+// AttributePriority are the possible values that the attribute "priority" can have.
+var AttributePriority = struct {
+	Emerg   string
+	Alert   string
+	Crit    string
+	Err     string
+	Warning string
+	Notice  string
+	Info    string
+	Debug   string
+}{
+	"emerg",
+	"alert",
+	"crit",
+	"err",
+	"warning",
+	"notice",
+	"info",
+	"debug",
+}
+
+// AttributeUnitState are the possible values that the attribute "unit_state" can have.
+var AttributeUnitState = struct {
+	Active       string
+	Reloading    string
+	Inactive     string
+	Failed       string
+	Activating   string
+	Deactivating string
+}{
+	"active",
+	"reloading",
+	"inactive",
+	"failed",
+	"activating",
+	"deactivating",
+}
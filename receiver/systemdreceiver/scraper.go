@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/systemdreceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/systemdreceiver/internal/metadata"
+)
+
+// unitStates are the active states a unit can be in.
+var unitStates = []string{
+	metadata.AttributeUnitState.Active,
+	metadata.AttributeUnitState.Reloading,
+	metadata.AttributeUnitState.Inactive,
+	metadata.AttributeUnitState.Failed,
+	metadata.AttributeUnitState.Activating,
+	metadata.AttributeUnitState.Deactivating,
+}
+
+type systemdScraper struct {
+	client         systemdClient
+	logger         *zap.Logger
+	cfg            *Config
+	metricsBuilder *metadata.MetricsBuilder
+
+	// startTime is the time the journal entries are counted from, when there's no cursor to read the journal from.
+	// It's moved to the time of a failed read of the journal.
+	startTime time.Time
+	// journalCursor is the cursor of the last journal entry counted, reset when the journal can't be read from it.
+	journalCursor string
+	// journalEntries are the number of journal entries counted, by priority.
+	journalEntries map[string]int64
+}
+
+func newSystemdScraper(
+	logger *zap.Logger,
+	cfg *Config,
+) *systemdScraper {
+	return &systemdScraper{
+		logger:         logger,
+		cfg:            cfg,
+		metricsBuilder: metadata.NewMetricsBuilder(cfg.Metrics),
+		journalEntries: map[string]int64{},
+	}
+}
+
+func (s *systemdScraper) start(_ context.Context, _ component.Host) error {
+	s.client = newSystemdClient()
+	s.startTime = time.Now()
+	return nil
+}
+
+func (s *systemdScraper) scrape(ctx context.Context) (pdata.Metrics, error) {
+	metrics := pdata.NewMetrics()
+	errs := &scrapererror.ScrapeErrors{}
+	now := pdata.NewTimestampFromTime(time.Now())
+
+	s.scrapeUnits(ctx, now, errs)
+	s.scrapeJournal(ctx, now, errs)
+
+	s.metricsBuilder.EmitForResource(metrics.ResourceMetrics())
+	return metrics, errs.Combine()
+}
+
+// scrapeUnits records the state and the number of restarts of the units.
+func (s *systemdScraper) scrapeUnits(ctx context.Context, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	if !s.cfg.Metrics.SystemdUnitState.Enabled && !s.cfg.Metrics.SystemdUnitRestarts.Enabled {
+		return
+	}
+
+	units, err := s.client.units(ctx, s.cfg.Units)
+	if err != nil {
+		errs.AddPartial(2, err)
+		return
+	}
+
+	for _, unit := range units {
+		for _, state := range unitStates {
+			var value int64
+			if unit.activeState == state {
+				value = 1
+			}
+			s.metricsBuilder.RecordSystemdUnitStateDataPoint(now, value, unit.name, state)
+		}
+		if unit.restarts != nil {
+			s.metricsBuilder.RecordSystemdUnitRestartsDataPoint(now, *unit.restarts, unit.name)
+		}
+	}
+}
+
+// scrapeJournal records the number of journal entries of the configured priority or a more important one.
+// Entries are counted from the start of the receiver, the journal is read from the last entry counted. If the
+// journal can't be read, e.g. because the entry of the cursor was rotated out or the output of journalctl can't be
+// parsed, the cursor is reset and the next scrape reads the journal from the time of the failed read, so that the
+// same entries don't fail every scrape.
+func (s *systemdScraper) scrapeJournal(ctx context.Context, now pdata.Timestamp, errs *scrapererror.ScrapeErrors) {
+	if !s.cfg.Metrics.SystemdJournalEntries.Enabled {
+		return
+	}
+
+	readTime := time.Now()
+	counts, cursor, err := s.client.journalEntries(ctx, s.cfg.JournalPriority, s.startTime, s.journalCursor)
+	if err != nil {
+		if s.journalCursor != "" {
+			s.logger.Warn("Failed to read the journal after the last entry counted, resetting the journal cursor", zap.Error(err))
+		}
+		s.journalCursor = ""
+		s.startTime = readTime
+		errs.AddPartial(1, err)
+		return
+	}
+	if validJournalCursor(cursor) {
+		s.journalCursor = cursor
+	}
+	for priority, count := range counts {
+		s.journalEntries[priority] += count
+	}
+
+	for _, priority := range priorityNames[:priorityLevels[s.cfg.JournalPriority]+1] {
+		s.metricsBuilder.RecordSystemdJournalEntriesDataPoint(now, s.journalEntries[priority], priority)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemdreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest/golden"
+)
+
+type mockClient struct {
+	unitStatuses []unitStatus
+	unitsErr     error
+	counts       []map[string]int64
+	journalErr   error
+	// journalErrRead is the number of the only read of the journal failing with journalErr, every read fails if
+	// it's 0.
+	journalErrRead int
+	cursors        []string
+	sinces         []time.Time
+}
+
+func (c *mockClient) units(context.Context, []string) ([]unitStatus, error) {
+	return c.unitStatuses, c.unitsErr
+}
+
+func (c *mockClient) journalEntries(_ context.Context, _ string, since time.Time, cursor string) (map[string]int64, string, error) {
+	c.cursors = append(c.cursors, cursor)
+	c.sinces = append(c.sinces, since)
+	if c.journalErr != nil && (c.journalErrRead == 0 || c.journalErrRead == len(c.cursors)) {
+		return nil, "", c.journalErr
+	}
+	if len(c.counts) == 0 {
+		return map[string]int64{}, "", nil
+	}
+	counts := c.counts[0]
+	c.counts = c.counts[1:]
+	return counts, fmt.Sprintf("s=1;i=%d", len(c.cursors)), nil
+}
+
+func newTestScraper(t *testing.T, cfg *Config, client systemdClient) *systemdScraper {
+	s := newSystemdScraper(zap.NewNop(), cfg)
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+	s.client = client
+	return s
+}
+
+func TestScrape(t *testing.T) {
+	runner := newFakeRunner()
+	s := newTestScraper(t, createDefaultConfig().(*Config), &commandClient{run: runner.run})
+
+	actualMetrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	expectedMetrics, err := golden.ReadMetrics(filepath.Join("testdata", "expected_metrics.json"))
+	require.NoError(t, err)
+	requireMetricsEqual(t, expectedMetrics, actualMetrics)
+}
+
+func TestScrapeAccumulatesJournalEntries(t *testing.T) {
+	client := &mockClient{counts: []map[string]int64{
+		{"err": 2},
+		{"err": 1, "crit": 1},
+	}}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.SystemdUnitState.Enabled = false
+	cfg.Metrics.SystemdUnitRestarts.Enabled = false
+	s := newTestScraper(t, cfg, client)
+
+	_, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	_, err = s.scrape(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"", "s=1;i=1", "s=1;i=2"}, client.cursors)
+
+	require.Equal(t, 1, metrics.MetricCount())
+	m := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	require.Equal(t, "systemd.journal.entries", m.Name())
+	counts := map[string]int64{}
+	dps := m.Sum().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		priority, _ := dps.At(i).Attributes().Get("priority")
+		counts[priority.StringVal()] = dps.At(i).IntVal()
+	}
+	require.Equal(t, map[string]int64{"emerg": 0, "alert": 0, "crit": 1, "err": 3}, counts)
+}
+
+func TestScrapeResetsJournalCursor(t *testing.T) {
+	client := &mockClient{
+		counts: []map[string]int64{
+			{"err": 2},
+			{"err": 1},
+		},
+		journalErr:     errors.New("failed to seek to cursor"),
+		journalErrRead: 2,
+	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.SystemdUnitState.Enabled = false
+	cfg.Metrics.SystemdUnitRestarts.Enabled = false
+	s := newTestScraper(t, cfg, client)
+	startTime := s.startTime
+
+	_, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	_, err = s.scrape(context.Background())
+	require.Error(t, err)
+	require.Equal(t, "", s.journalCursor)
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"", "s=1;i=1", ""}, client.cursors)
+	require.Equal(t, startTime, client.sinces[0])
+	require.True(t, client.sinces[2].After(startTime))
+	require.Equal(t, "s=1;i=3", s.journalCursor)
+
+	dps := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		if priority, _ := dps.At(i).Attributes().Get("priority"); priority.StringVal() == "err" {
+			require.EqualValues(t, 3, dps.At(i).IntVal())
+		}
+	}
+}
+
+func TestScrapeIgnoresInvalidJournalCursor(t *testing.T) {
+	client := &invalidCursorClient{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.SystemdUnitState.Enabled = false
+	cfg.Metrics.SystemdUnitRestarts.Enabled = false
+	s := newTestScraper(t, cfg, client)
+
+	_, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "", s.journalCursor)
+}
+
+// invalidCursorClient returns a cursor which isn't a cursor of journald.
+type invalidCursorClient struct {
+	mockClient
+}
+
+func (c *invalidCursorClient) journalEntries(context.Context, string, time.Time, string) (map[string]int64, string, error) {
+	return map[string]int64{"err": 1}, "s=1;i=1 --since=@0", nil
+}
+
+func TestScrapeErrors(t *testing.T) {
+	client := &mockClient{
+		unitsErr:   errors.New("systemctl failed"),
+		journalErr: errors.New("journalctl failed"),
+	}
+	s := newTestScraper(t, createDefaultConfig().(*Config), client)
+
+	metrics, err := s.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Contains(t, err.Error(), "systemctl failed")
+	require.Contains(t, err.Error(), "journalctl failed")
+	require.Equal(t, 0, metrics.MetricCount())
+}
+
+func TestScrapeDisabledMetrics(t *testing.T) {
+	client := &mockClient{
+		unitsErr:   errors.New("systemctl failed"),
+		journalErr: errors.New("journalctl failed"),
+	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.SystemdUnitState.Enabled = false
+	cfg.Metrics.SystemdUnitRestarts.Enabled = false
+	cfg.Metrics.SystemdJournalEntries.Enabled = false
+	s := newTestScraper(t, cfg, client)
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, metrics.MetricCount())
+	require.Empty(t, client.cursors)
+}
+
+func requireMetricsEqual(t *testing.T, expected, actual pdata.Metrics) {
+	require.Equal(t, expected.ResourceMetrics().Len(), actual.ResourceMetrics().Len())
+	for i := 0; i < expected.ResourceMetrics().Len(); i++ {
+		ilms1 := expected.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+		ilms2 := actual.ResourceMetrics().At(i).InstrumentationLibraryMetrics()
+		require.Equal(t, ilms1.Len(), ilms2.Len())
+		for j := 0; j < ilms1.Len(); j++ {
+			require.Equal(t, ilms1.At(j).InstrumentationLibrary().Name(), ilms2.At(j).InstrumentationLibrary().Name())
+			require.NoError(t, scrapertest.CompareMetricSlices(ilms1.At(j).Metrics(), ilms2.At(j).Metrics()))
+		}
+	}
+}
@@ -933,6 +933,7 @@ func newMetricZookeeperZnodes(settings MetricSettings) metricZookeeperZnodes {
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
 	startTime                            pdata.Timestamp
+	instrumentationLibraryName           string
 	metricZookeeperApproximateDateSize   metricZookeeperApproximateDateSize
 	metricZookeeperConnectionsAlive      metricZookeeperConnectionsAlive
 	metricZookeeperEphemeralNodes        metricZookeeperEphemeralNodes
@@ -962,9 +963,18 @@ func WithStartTime(startTime pdata.Timestamp) metricBuilderOption {
 	}
 }
 
+// WithInstrumentationLibraryName sets the name of the instrumentation library of the metrics emitted
+// by EmitForResource, "otelcol/zookeeperreceiver" by default.
+func WithInstrumentationLibraryName(name string) metricBuilderOption {
+	return func(mb *MetricsBuilder) {
+		mb.instrumentationLibraryName = name
+	}
+}
+
 func NewMetricsBuilder(settings MetricsSettings, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
 		startTime:                            pdata.NewTimestampFromTime(time.Now()),
+		instrumentationLibraryName:           "otelcol/zookeeperreceiver",
 		metricZookeeperApproximateDateSize:   newMetricZookeeperApproximateDateSize(settings.ZookeeperApproximateDateSize),
 		metricZookeeperConnectionsAlive:      newMetricZookeeperConnectionsAlive(settings.ZookeeperConnectionsAlive),
 		metricZookeeperEphemeralNodes:        newMetricZookeeperEphemeralNodes(settings.ZookeeperEphemeralNodes),
@@ -1012,6 +1022,26 @@ func (mb *MetricsBuilder) Emit(metrics pdata.MetricSlice) {
 	mb.metricZookeeperZnodes.emit(metrics)
 }
 
+// ResourceOption applies changes to provided resource.
+type ResourceOption func(pdata.Resource)
+
+// EmitForResource appends generated metrics to a new resource of a pdata.ResourceMetricsSlice and updates the internal
+// state to be ready for recording another set of data points. It can be used by scrapers emitting metrics of several
+// resources, resource attributes are provided as ResourceOption arguments. Nothing is appended if no data points
+// were recorded.
+func (mb *MetricsBuilder) EmitForResource(rms pdata.ResourceMetricsSlice, ro ...ResourceOption) {
+	rm := pdata.NewResourceMetrics()
+	for _, op := range ro {
+		op(rm.Resource())
+	}
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(mb.instrumentationLibraryName)
+	mb.Emit(ilm.Metrics())
+	if ilm.Metrics().Len() > 0 {
+		rm.MoveTo(rms.AppendEmpty())
+	}
+}
+
 // RecordZookeeperApproximateDateSizeDataPoint adds a data point to zookeeper.approximate_date_size metric.
 func (mb *MetricsBuilder) RecordZookeeperApproximateDateSizeDataPoint(ts pdata.Timestamp, val int64) {
 	mb.metricZookeeperApproximateDateSize.recordDataPoint(mb.startTime, ts, val)